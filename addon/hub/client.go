@@ -0,0 +1,134 @@
+package hub
+
+import (
+	"sync"
+	"time"
+)
+
+// Conn is a WebSocket connection, e.g. the *websocket.Conn of github.com/gofiber/contrib/websocket
+type Conn interface {
+	ReadMessage() (messageType int, data []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	Close() error
+}
+
+// message is a queued message of a client
+type message struct {
+	data []byte
+	typ  int
+}
+
+// Client is a connection served by the hub
+type Client struct {
+	conn  Conn
+	hub   *Hub
+	rooms map[string]struct{} // guarded by hub.mu
+	queue chan message
+	done  chan struct{}
+	err   error
+	id    string
+	once  sync.Once
+}
+
+// ID returns the unique ID of the client
+func (c *Client) ID() string {
+	return c.id
+}
+
+// Conn returns the connection of the client
+func (c *Client) Conn() Conn {
+	return c.conn
+}
+
+// Join adds the client to the rooms
+func (c *Client) Join(rooms ...string) {
+	c.hub.join(c, rooms)
+}
+
+// Leave removes the client from the rooms
+func (c *Client) Leave(rooms ...string) {
+	c.hub.leave(c, rooms)
+}
+
+// Rooms returns the rooms of the client
+func (c *Client) Rooms() []string {
+	c.hub.mu.RLock()
+	defer c.hub.mu.RUnlock()
+	rooms := make([]string, 0, len(c.rooms))
+	for room := range c.rooms {
+		rooms = append(rooms, room)
+	}
+	return rooms
+}
+
+// Send queues the message for the client. If the queue is full, it waits up to the
+// SendTimeout and returns ErrQueueFull, the connection is closed unless DropOnFull is set.
+func (c *Client) Send(messageType int, data []byte) error {
+	return c.send(message{typ: messageType, data: data}, c.hub.cfg.SendTimeout)
+}
+
+// BroadcastTo sends the message to the clients in the room except this client
+func (c *Client) BroadcastTo(room string, messageType int, data []byte) error {
+	return c.hub.dispatch(&envelope{Kind: kindRoom, Target: room, Except: c.id, Type: messageType, Data: data})
+}
+
+// Close closes the connection of the client
+func (c *Client) Close() error {
+	c.close(nil)
+	return nil
+}
+
+func (c *Client) send(msg message, timeout time.Duration) error {
+	select {
+	case <-c.done:
+		return ErrClosed
+	case c.queue <- msg:
+		return nil
+	default:
+	}
+
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-c.done:
+			return ErrClosed
+		case c.queue <- msg:
+			return nil
+		case <-timer.C:
+		}
+	}
+
+	if !c.hub.cfg.DropOnFull {
+		c.close(ErrQueueFull)
+	}
+	return ErrQueueFull
+}
+
+// close closes the connection once, err is the reason reported to OnDisconnect
+func (c *Client) close(err error) {
+	c.once.Do(func() {
+		c.err = err
+		close(c.done)
+		_ = c.conn.Close() //nolint:errcheck // The connection is not used anymore
+	})
+}
+
+// write writes the queued messages to the connection until the client is closed
+func (c *Client) write() {
+	deadline, hasDeadline := c.conn.(interface{ SetWriteDeadline(t time.Time) error })
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.queue:
+			if hasDeadline {
+				_ = deadline.SetWriteDeadline(time.Now().Add(c.hub.cfg.WriteTimeout)) //nolint:errcheck // A failed write reports the error
+			}
+			if err := c.conn.WriteMessage(msg.typ, msg.data); err != nil {
+				c.close(err)
+				return
+			}
+		}
+	}
+}
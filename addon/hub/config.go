@@ -0,0 +1,95 @@
+package hub
+
+import (
+	"time"
+)
+
+// Config defines the config for addon.
+type Config struct {
+	// PubSub distributes the messages between the hubs of multiple instances,
+	// e.g. the Redis storage of Fiber. Without it, messages are only delivered
+	// to the connections of this instance.
+	//
+	// Optional. Default: nil
+	PubSub PubSub
+
+	// OnConnect is called when a connection is served, before its messages are read.
+	// It can join the client to its rooms.
+	//
+	// Optional. Default: nil
+	OnConnect func(c *Client)
+
+	// OnMessage is called with each message read from a connection.
+	//
+	// Optional. Default: nil
+	OnMessage func(c *Client, messageType int, data []byte)
+
+	// OnDisconnect is called when a connection is closed, with the error which closed it.
+	//
+	// Optional. Default: nil
+	OnDisconnect func(c *Client, err error)
+
+	// Channel is the channel of the PubSub the hubs communicate on.
+	//
+	// Optional. Default: "fiber:hub"
+	Channel string
+
+	// SendQueueSize is the number of messages which are queued per connection
+	// while the connection is written to.
+	//
+	// Optional. Default: 64
+	SendQueueSize int
+
+	// SendTimeout is the maximum time Client.Send waits for space in a full queue.
+	// Broadcasts never wait.
+	//
+	// Optional. Default: 0 (don't wait)
+	SendTimeout time.Duration
+
+	// WriteTimeout is the maximum time of writing a message, if the connection has a
+	// SetWriteDeadline method.
+	//
+	// Optional. Default: 10 * time.Second
+	WriteTimeout time.Duration
+
+	// DropOnFull drops messages for a connection whose queue is full. By default the
+	// connection is closed, as the client can't keep up with its messages.
+	//
+	// Optional. Default: false
+	DropOnFull bool
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Channel:       "fiber:hub",
+	SendQueueSize: 64,
+	SendTimeout:   0,
+	WriteTimeout:  10 * time.Second,
+	DropOnFull:    false,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Channel == "" {
+		cfg.Channel = ConfigDefault.Channel
+	}
+	if cfg.SendQueueSize <= 0 {
+		cfg.SendQueueSize = ConfigDefault.SendQueueSize
+	}
+	if cfg.SendTimeout < 0 {
+		cfg.SendTimeout = ConfigDefault.SendTimeout
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = ConfigDefault.WriteTimeout
+	}
+	return cfg
+}
@@ -0,0 +1,310 @@
+// Package hub manages WebSocket connections for chat and notification workloads. It
+// groups the connections in named rooms, broadcasts and unicasts messages through
+// per-connection send queues, and scales out to multiple instances with a PubSub.
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/utils/v2"
+)
+
+var (
+	// ErrQueueFull is returned when the send queue of a client is full
+	ErrQueueFull = errors.New("hub: send queue is full")
+	// ErrClosed is returned when a message is sent to a closed client or hub
+	ErrClosed = errors.New("hub: closed")
+)
+
+// PubSub distributes messages between the hubs of multiple instances, it is implemented
+// by the Redis storage of Fiber
+type PubSub interface {
+	// Publish publishes the message to the channel
+	Publish(ctx context.Context, channel string, msg []byte) error
+	// Subscribe calls the handler with the messages of the channel until the context is
+	// canceled, it returns after the subscription is established
+	Subscribe(ctx context.Context, channel string, handler func(msg []byte)) error
+}
+
+// Kinds of envelopes
+const (
+	kindAll = iota
+	kindRoom
+	kindClient
+)
+
+// envelope is a message which is delivered by all hubs
+type envelope struct {
+	Origin string `json:"o"`
+	Target string `json:"t,omitempty"`
+	Except string `json:"x,omitempty"`
+	Data   []byte `json:"d"`
+	Kind   int    `json:"k"`
+	Type   int    `json:"m"`
+}
+
+// Hub keeps the connections and their rooms
+type Hub struct {
+	clients map[string]*Client
+	rooms   map[string]map[*Client]struct{}
+	cancel  context.CancelFunc
+	id      string
+	cfg     Config
+	mu      sync.RWMutex
+	closed  bool
+}
+
+// New creates a new hub, it panics if the PubSub can't be subscribed to
+func New(config ...Config) *Hub {
+	// Set default config
+	cfg := configDefault(config...)
+
+	h := &Hub{
+		clients: make(map[string]*Client),
+		rooms:   make(map[string]map[*Client]struct{}),
+		id:      utils.UUIDv4(),
+		cfg:     cfg,
+	}
+
+	if cfg.PubSub != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		h.cancel = cancel
+		if err := cfg.PubSub.Subscribe(ctx, cfg.Channel, h.receive); err != nil {
+			cancel()
+			panic(fmt.Errorf("hub: failed to subscribe: %w", err))
+		}
+	}
+	return h
+}
+
+// Serve serves the connection until it is closed, it blocks like the handler of a
+// WebSocket connection must
+func (h *Hub) Serve(conn Conn) error {
+	c := &Client{
+		conn:  conn,
+		hub:   h,
+		rooms: make(map[string]struct{}),
+		queue: make(chan message, h.cfg.SendQueueSize),
+		done:  make(chan struct{}),
+		id:    utils.UUIDv4(),
+	}
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return ErrClosed
+	}
+	h.clients[c.id] = c
+	h.mu.Unlock()
+
+	go c.write()
+	if h.cfg.OnConnect != nil {
+		h.cfg.OnConnect(c)
+	}
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			c.close(err)
+			break
+		}
+		if h.cfg.OnMessage != nil {
+			h.cfg.OnMessage(c, messageType, data)
+		}
+	}
+
+	h.mu.Lock()
+	delete(h.clients, c.id)
+	for room := range c.rooms {
+		h.removeFromRoom(c, room)
+	}
+	h.mu.Unlock()
+
+	if h.cfg.OnDisconnect != nil {
+		h.cfg.OnDisconnect(c, c.err)
+	}
+	return nil
+}
+
+// Broadcast sends the message to all clients
+func (h *Hub) Broadcast(messageType int, data []byte) error {
+	return h.dispatch(&envelope{Kind: kindAll, Type: messageType, Data: data})
+}
+
+// BroadcastTo sends the message to the clients in the room
+func (h *Hub) BroadcastTo(room string, messageType int, data []byte) error {
+	return h.dispatch(&envelope{Kind: kindRoom, Target: room, Type: messageType, Data: data})
+}
+
+// SendTo sends the message to the client with the ID, which may be connected to another instance
+func (h *Hub) SendTo(id string, messageType int, data []byte) error {
+	if c := h.Client(id); c != nil {
+		return c.Send(messageType, data)
+	}
+	if h.cfg.PubSub == nil {
+		return nil
+	}
+	return h.publish(&envelope{Kind: kindClient, Target: id, Type: messageType, Data: data})
+}
+
+// Client returns the client of this instance with the ID, or nil
+func (h *Hub) Client(id string) *Client {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.clients[id]
+}
+
+// Clients returns the number of clients of this instance
+func (h *Hub) Clients() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+// Members returns the clients of this instance in the room
+func (h *Hub) Members(room string) []*Client {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	members := make([]*Client, 0, len(h.rooms[room]))
+	for c := range h.rooms[room] {
+		members = append(members, c)
+	}
+	return members
+}
+
+// Close closes all connections and stops receiving messages of other instances
+func (h *Hub) Close() error {
+	h.mu.Lock()
+	h.closed = true
+	clients := make([]*Client, 0, len(h.clients))
+	for _, c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+
+	if h.cancel != nil {
+		h.cancel()
+	}
+	for _, c := range clients {
+		c.close(ErrClosed)
+	}
+	return nil
+}
+
+func (h *Hub) join(c *Client, rooms []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	select {
+	case <-c.done:
+		// A closed client is removed from its rooms by Serve
+		return
+	default:
+	}
+	for _, room := range rooms {
+		members, ok := h.rooms[room]
+		if !ok {
+			members = make(map[*Client]struct{})
+			h.rooms[room] = members
+		}
+		members[c] = struct{}{}
+		c.rooms[room] = struct{}{}
+	}
+}
+
+func (h *Hub) leave(c *Client, rooms []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, room := range rooms {
+		h.removeFromRoom(c, room)
+	}
+}
+
+// removeFromRoom removes the client from the room, h.mu must be locked
+func (h *Hub) removeFromRoom(c *Client, room string) {
+	delete(c.rooms, room)
+	if members, ok := h.rooms[room]; ok {
+		delete(members, c)
+		if len(members) == 0 {
+			delete(h.rooms, room)
+		}
+	}
+}
+
+// dispatch delivers the envelope to the clients of this instance and publishes it to the others
+func (h *Hub) dispatch(e *envelope) error {
+	h.mu.RLock()
+	closed := h.closed
+	h.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+
+	h.deliver(e)
+	if h.cfg.PubSub == nil {
+		return nil
+	}
+	return h.publish(e)
+}
+
+func (h *Hub) publish(e *envelope) error {
+	e.Origin = h.id
+	msg, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("hub: failed to encode message: %w", err)
+	}
+	if err := h.cfg.PubSub.Publish(context.Background(), h.cfg.Channel, msg); err != nil {
+		return fmt.Errorf("hub: failed to publish message: %w", err)
+	}
+	return nil
+}
+
+// receive delivers the envelopes published by other instances
+func (h *Hub) receive(msg []byte) {
+	var e envelope
+	if err := json.Unmarshal(msg, &e); err != nil {
+		log.Errorf("[HUB] failed to decode message: %v", err)
+		return
+	}
+	if e.Origin == h.id {
+		return
+	}
+	h.deliver(&e)
+}
+
+// deliver sends the envelope to the matching clients of this instance, without waiting
+// for full queues
+func (h *Hub) deliver(e *envelope) {
+	msg := message{typ: e.Type, data: e.Data}
+
+	h.mu.RLock()
+	var targets []*Client
+	switch e.Kind {
+	case kindAll:
+		targets = make([]*Client, 0, len(h.clients))
+		for _, c := range h.clients {
+			targets = append(targets, c)
+		}
+	case kindRoom:
+		targets = make([]*Client, 0, len(h.rooms[e.Target]))
+		for c := range h.rooms[e.Target] {
+			if c.id != e.Except {
+				targets = append(targets, c)
+			}
+		}
+	case kindClient:
+		if c, ok := h.clients[e.Target]; ok {
+			targets = append(targets, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	// The hub isn't locked while sending, as slow clients are closed
+	for _, c := range targets {
+		_ = c.send(msg, 0) //nolint:errcheck // Slow and closed clients are handled by send
+	}
+}
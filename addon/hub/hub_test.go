@@ -0,0 +1,335 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3/storage/redis"
+	"github.com/stretchr/testify/require"
+)
+
+var _ PubSub = (*redis.Storage)(nil)
+
+const textMessage = 1
+
+var errConnClosed = errors.New("connection closed")
+
+// fakeConn is an in-memory connection, whose writes block if block is set
+type fakeConn struct {
+	in     chan []byte
+	out    chan []byte
+	closed chan struct{}
+	once   sync.Once
+	block  bool
+}
+
+func newFakeConn(block bool) *fakeConn {
+	return &fakeConn{
+		in:     make(chan []byte),
+		out:    make(chan []byte, 16),
+		closed: make(chan struct{}),
+		block:  block,
+	}
+}
+
+func (c *fakeConn) ReadMessage() (int, []byte, error) {
+	select {
+	case data := <-c.in:
+		return textMessage, data, nil
+	case <-c.closed:
+		return 0, nil, errConnClosed
+	}
+}
+
+func (c *fakeConn) WriteMessage(_ int, data []byte) error {
+	if c.block {
+		<-c.closed
+		return errConnClosed
+	}
+	select {
+	case c.out <- data:
+		return nil
+	case <-c.closed:
+		return errConnClosed
+	}
+}
+
+func (c *fakeConn) Close() error {
+	c.once.Do(func() {
+		close(c.closed)
+	})
+	return nil
+}
+
+// receive returns the next message written to the connection
+func (c *fakeConn) receive(t *testing.T) string {
+	t.Helper()
+	select {
+	case data := <-c.out:
+		return string(data)
+	case <-time.After(time.Second):
+		t.Fatal("no message received")
+		return ""
+	}
+}
+
+// requireNoMessage fails if a message is written to the connection
+func (c *fakeConn) requireNoMessage(t *testing.T) {
+	t.Helper()
+	select {
+	case data := <-c.out:
+		t.Fatalf("unexpected message %q", data)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+// connect serves a new connection and returns it with its client
+func connect(t *testing.T, h *Hub, clients <-chan *Client, block bool) (*fakeConn, *Client, <-chan error) {
+	t.Helper()
+	conn := newFakeConn(block)
+	served := make(chan error, 1)
+	go func() {
+		served <- h.Serve(conn)
+	}()
+	select {
+	case c := <-clients:
+		return conn, c, served
+	case <-time.After(time.Second):
+		t.Fatal("connection not served")
+		return nil, nil, nil
+	}
+}
+
+func newTestHub(config Config) (*Hub, <-chan *Client) {
+	clients := make(chan *Client, 16)
+	config.OnConnect = func(c *Client) {
+		clients <- c
+	}
+	return New(config), clients
+}
+
+// go test -run Test_Hub_Rooms
+func Test_Hub_Rooms(t *testing.T) {
+	t.Parallel()
+	h, clients := newTestHub(Config{})
+
+	conn1, client1, _ := connect(t, h, clients, false)
+	conn2, client2, _ := connect(t, h, clients, false)
+	conn3, client3, _ := connect(t, h, clients, false)
+	require.Equal(t, 3, h.Clients())
+
+	client1.Join("news", "chat")
+	client2.Join("chat")
+	require.ElementsMatch(t, []string{"news", "chat"}, client1.Rooms())
+	require.ElementsMatch(t, []*Client{client1, client2}, h.Members("chat"))
+
+	require.NoError(t, h.BroadcastTo("chat", textMessage, []byte("hello chat")))
+	require.Equal(t, "hello chat", conn1.receive(t))
+	require.Equal(t, "hello chat", conn2.receive(t))
+	conn3.requireNoMessage(t)
+
+	// The sender doesn't receive its own message
+	require.NoError(t, client1.BroadcastTo("chat", textMessage, []byte("from 1")))
+	require.Equal(t, "from 1", conn2.receive(t))
+	conn1.requireNoMessage(t)
+
+	client1.Leave("chat")
+	require.NoError(t, h.BroadcastTo("chat", textMessage, []byte("without 1")))
+	require.Equal(t, "without 1", conn2.receive(t))
+	conn1.requireNoMessage(t)
+
+	require.NoError(t, h.Broadcast(textMessage, []byte("everyone")))
+	require.Equal(t, "everyone", conn1.receive(t))
+	require.Equal(t, "everyone", conn2.receive(t))
+	require.Equal(t, "everyone", conn3.receive(t))
+
+	require.NoError(t, h.SendTo(client3.ID(), textMessage, []byte("only 3")))
+	require.Equal(t, "only 3", conn3.receive(t))
+	conn1.requireNoMessage(t)
+	conn2.requireNoMessage(t)
+}
+
+// go test -run Test_Hub_Serve
+func Test_Hub_Serve(t *testing.T) {
+	t.Parallel()
+	disconnected := make(chan error, 1)
+	clients := make(chan *Client, 2)
+	h := New(Config{
+		OnConnect: func(c *Client) {
+			c.Join("chat")
+			clients <- c
+		},
+		OnMessage: func(c *Client, messageType int, data []byte) {
+			_ = c.BroadcastTo("chat", messageType, data) //nolint:errcheck // It is a test
+		},
+		OnDisconnect: func(_ *Client, err error) {
+			disconnected <- err
+		},
+	})
+
+	conn1, client1, served1 := connect(t, h, clients, false)
+	conn2, _, served2 := connect(t, h, clients, false)
+
+	conn1.in <- []byte("hi")
+	require.Equal(t, "hi", conn2.receive(t))
+	conn1.requireNoMessage(t)
+
+	// The client is removed when the connection is closed
+	require.NoError(t, conn1.Close())
+	require.NoError(t, <-served1)
+	require.ErrorIs(t, <-disconnected, errConnClosed)
+	require.Equal(t, 1, h.Clients())
+	require.Nil(t, h.Client(client1.ID()))
+	require.Len(t, h.Members("chat"), 1)
+
+	require.NoError(t, h.Close())
+	require.NoError(t, <-served2)
+	require.ErrorIs(t, <-disconnected, ErrClosed)
+	require.ErrorIs(t, h.Broadcast(textMessage, []byte("late")), ErrClosed)
+	require.ErrorIs(t, h.Serve(newFakeConn(false)), ErrClosed)
+}
+
+// go test -run Test_Hub_SlowClient
+func Test_Hub_SlowClient(t *testing.T) {
+	t.Parallel()
+	disconnected := make(chan error, 1)
+	h, clients := newTestHub(Config{
+		SendQueueSize: 1,
+		SendTimeout:   10 * time.Millisecond,
+		OnDisconnect: func(_ *Client, err error) {
+			disconnected <- err
+		},
+	})
+
+	_, client, served := connect(t, h, clients, true)
+	// The first message blocks the writer, the second fills the queue
+	require.NoError(t, client.Send(textMessage, []byte("1")))
+	require.Eventually(t, func() bool {
+		return len(client.queue) == 0
+	}, time.Second, time.Millisecond)
+	require.NoError(t, client.Send(textMessage, []byte("2")))
+
+	require.ErrorIs(t, client.Send(textMessage, []byte("3")), ErrQueueFull)
+	require.NoError(t, <-served)
+	require.ErrorIs(t, <-disconnected, ErrQueueFull)
+	require.ErrorIs(t, client.Send(textMessage, []byte("4")), ErrClosed)
+}
+
+// go test -run Test_Hub_DropOnFull
+func Test_Hub_DropOnFull(t *testing.T) {
+	t.Parallel()
+	h, clients := newTestHub(Config{SendQueueSize: 1, DropOnFull: true})
+
+	_, client, served := connect(t, h, clients, true)
+	require.NoError(t, client.Send(textMessage, []byte("1")))
+	require.Eventually(t, func() bool {
+		return len(client.queue) == 0
+	}, time.Second, time.Millisecond)
+	require.NoError(t, h.Broadcast(textMessage, []byte("2")))
+
+	// Further messages are dropped, the client stays connected
+	require.ErrorIs(t, client.Send(textMessage, []byte("3")), ErrQueueFull)
+	require.NoError(t, h.Broadcast(textMessage, []byte("4")))
+	require.Equal(t, 1, h.Clients())
+
+	require.NoError(t, client.Close())
+	require.NoError(t, <-served)
+}
+
+// memoryPubSub delivers the published messages to the subscribers of the same process
+type memoryPubSub struct {
+	handlers []func(msg []byte)
+	mu       sync.Mutex
+}
+
+func (ps *memoryPubSub) Publish(_ context.Context, _ string, msg []byte) error {
+	ps.mu.Lock()
+	handlers := append([]func(msg []byte){}, ps.handlers...)
+	ps.mu.Unlock()
+	for _, handler := range handlers {
+		handler(msg)
+	}
+	return nil
+}
+
+func (ps *memoryPubSub) Subscribe(_ context.Context, _ string, handler func(msg []byte)) error {
+	ps.mu.Lock()
+	ps.handlers = append(ps.handlers, handler)
+	ps.mu.Unlock()
+	return nil
+}
+
+// go test -run Test_Hub_PubSub
+func Test_Hub_PubSub(t *testing.T) {
+	t.Parallel()
+	ps := &memoryPubSub{}
+	h1, clients1 := newTestHub(Config{PubSub: ps})
+	h2, clients2 := newTestHub(Config{PubSub: ps})
+
+	conn1, client1, _ := connect(t, h1, clients1, false)
+	conn2, client2, _ := connect(t, h2, clients2, false)
+	client1.Join("chat")
+	client2.Join("chat")
+
+	// The messages are delivered once to the clients of both instances
+	require.NoError(t, h1.BroadcastTo("chat", textMessage, []byte("hello")))
+	require.Equal(t, "hello", conn1.receive(t))
+	require.Equal(t, "hello", conn2.receive(t))
+	conn1.requireNoMessage(t)
+
+	require.NoError(t, client2.BroadcastTo("chat", textMessage, []byte("from 2")))
+	require.Equal(t, "from 2", conn1.receive(t))
+	conn2.requireNoMessage(t)
+
+	// A client of another instance is found by its ID
+	require.NoError(t, h1.SendTo(client2.ID(), textMessage, []byte("direct")))
+	require.Equal(t, "direct", conn2.receive(t))
+	conn1.requireNoMessage(t)
+}
+
+// go test -v -run=^$ -bench=Benchmark_Hub_BroadcastTo -benchmem -count=4
+func Benchmark_Hub_BroadcastTo(b *testing.B) {
+	h := New()
+	conns := make([]*fakeConn, 100)
+	for i := range conns {
+		conns[i] = newFakeConn(false)
+		conn := conns[i]
+		go func() {
+			_ = h.Serve(conn) //nolint:errcheck // It is a benchmark
+		}()
+		// Drain the messages
+		go func() {
+			for {
+				select {
+				case <-conn.out:
+				case <-conn.closed:
+					return
+				}
+			}
+		}()
+	}
+	for h.Clients() < len(conns) {
+		time.Sleep(time.Millisecond)
+	}
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.clients))
+	for _, c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.RUnlock()
+	for _, c := range clients {
+		c.Join("room")
+	}
+
+	data := []byte("hello")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = h.BroadcastTo("room", textMessage, data) //nolint:errcheck // It is a benchmark
+	}
+	b.StopTimer()
+	_ = h.Close() //nolint:errcheck // It is a benchmark
+}
@@ -0,0 +1,81 @@
+package sse
+
+import (
+	"time"
+)
+
+// Config defines the config for addon.
+type Config struct {
+	// Backplane distributes the events between the brokers of multiple instances,
+	// e.g. the Redis storage of Fiber. Without it, events are only sent to the
+	// subscribers of this instance.
+	//
+	// Optional. Default: nil
+	Backplane PubSub
+
+	// Channel is the channel of the Backplane the brokers communicate on.
+	//
+	// Optional. Default: "fiber:sse"
+	Channel string
+
+	// BufferSize is the number of recent events per topic which are kept to be
+	// replayed to reconnecting clients with a Last-Event-ID header.
+	//
+	// Optional. Default: 100
+	BufferSize int
+
+	// SendQueueSize is the number of events which are queued per subscriber. A subscriber
+	// whose queue is full is disconnected, it replays the missed events when it reconnects.
+	//
+	// Optional. Default: 64
+	SendQueueSize int
+
+	// HeartbeatInterval is the interval of the comments which are sent to keep idle
+	// connections open.
+	//
+	// Optional. Default: 15 * time.Second
+	HeartbeatInterval time.Duration
+
+	// Retry is the reconnection time which is sent to the clients.
+	//
+	// Optional. Default: 0 (the default of the client)
+	Retry time.Duration
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Channel:           "fiber:sse",
+	BufferSize:        100,
+	SendQueueSize:     64,
+	HeartbeatInterval: 15 * time.Second,
+	Retry:             0,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Channel == "" {
+		cfg.Channel = ConfigDefault.Channel
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = ConfigDefault.BufferSize
+	}
+	if cfg.SendQueueSize <= 0 {
+		cfg.SendQueueSize = ConfigDefault.SendQueueSize
+	}
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = ConfigDefault.HeartbeatInterval
+	}
+	if cfg.Retry < 0 {
+		cfg.Retry = ConfigDefault.Retry
+	}
+	return cfg
+}
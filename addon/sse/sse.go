@@ -0,0 +1,354 @@
+// Package sse provides a broker which pushes server-sent events to the subscribers
+// of topics. Reconnecting clients replay the events they missed from a bounded buffer
+// by their Last-Event-ID, and the brokers of multiple instances share their events
+// through a backplane.
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/utils/v2"
+)
+
+// lineBreaks removes line breaks, which would end the field of an event
+var lineBreaks = strings.NewReplacer("\r", "", "\n", "")
+
+// ErrClosed is returned when an event is published to a closed broker
+var ErrClosed = errors.New("sse: broker is closed")
+
+// PubSub distributes events between the brokers of multiple instances, it is implemented
+// by the Redis storage of Fiber
+type PubSub interface {
+	// Publish publishes the message to the channel
+	Publish(ctx context.Context, channel string, msg []byte) error
+	// Subscribe calls the handler with the messages of the channel until the context is
+	// canceled, it returns after the subscription is established
+	Subscribe(ctx context.Context, channel string, handler func(msg []byte)) error
+}
+
+// Event is a server-sent event
+type Event struct {
+	// ID identifies the event for the replay, a unique ID is generated if it is empty
+	ID string `json:"id"`
+	// Event is the type of the event, the client dispatches "message" events if it is empty
+	Event string `json:"event,omitempty"`
+	// Data is the payload of the event, it is sent as multiple data lines if it contains new lines
+	Data []byte `json:"data"`
+}
+
+// envelope is an event which is delivered by all brokers
+type envelope struct {
+	Origin string `json:"o"`
+	Topic  string `json:"t"`
+	Event  Event  `json:"e"`
+}
+
+// bufferedEvent is an event in the buffer of a topic, seq is the order the events arrived
+type bufferedEvent struct {
+	event *Event
+	seq   uint64
+}
+
+// topic keeps the subscribers and the recent events of a topic
+type topic struct {
+	subscribers map[*subscriber]struct{}
+	buffer      []bufferedEvent // ring of the recent events
+	next        int             // index of the next event in buffer
+}
+
+// subscriber is the connection of a client
+type subscriber struct {
+	events chan *Event
+	done   chan struct{}
+	once   sync.Once
+}
+
+func (s *subscriber) close() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+// Broker keeps the topics and their subscribers
+type Broker struct {
+	topics map[string]*topic
+	cancel context.CancelFunc
+	id     string
+	cfg    Config
+	seq    uint64
+	mu     sync.Mutex
+	closed bool
+}
+
+// New creates a new broker, it panics if the Backplane can't be subscribed to
+func New(config ...Config) *Broker {
+	// Set default config
+	cfg := configDefault(config...)
+
+	b := &Broker{
+		topics: make(map[string]*topic),
+		id:     utils.UUIDv4(),
+		cfg:    cfg,
+	}
+
+	if cfg.Backplane != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		b.cancel = cancel
+		if err := cfg.Backplane.Subscribe(ctx, cfg.Channel, b.receive); err != nil {
+			cancel()
+			panic(fmt.Errorf("sse: failed to subscribe: %w", err))
+		}
+	}
+	return b
+}
+
+// Topics returns a function which returns the topics, for Handler
+func Topics(topics ...string) func(c fiber.Ctx) []string {
+	return func(_ fiber.Ctx) []string {
+		return topics
+	}
+}
+
+// Handler returns a handler which subscribes the client to the topics and streams
+// their events. The events after the Last-Event-ID header or the lastEventId query
+// parameter are replayed first.
+func (b *Broker) Handler(topics func(c fiber.Ctx) []string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		lastID := c.Get("Last-Event-ID")
+		if lastID == "" {
+			lastID = c.Query("lastEventId")
+		}
+
+		names := topics(c)
+		for i := range names {
+			names[i] = utils.CopyString(names[i])
+		}
+		s := &subscriber{
+			events: make(chan *Event, b.cfg.SendQueueSize),
+			done:   make(chan struct{}),
+		}
+		replay, err := b.subscribe(s, names, utils.CopyString(lastID))
+		if err != nil {
+			return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+		}
+
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set(fiber.HeaderConnection, "keep-alive")
+		c.Set("X-Accel-Buffering", "no")
+
+		return c.SendStreamWriter(func(w *bufio.Writer) {
+			defer b.unsubscribe(s, names)
+
+			if b.cfg.Retry > 0 {
+				_, _ = w.WriteString("retry: " + strconv.FormatInt(b.cfg.Retry.Milliseconds(), 10) + "\n\n") //nolint:errcheck // Write errors are reported by Flush
+			}
+			for _, event := range replay {
+				writeEvent(w, event)
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+
+			heartbeat := time.NewTicker(b.cfg.HeartbeatInterval)
+			defer heartbeat.Stop()
+			for {
+				select {
+				case <-s.done:
+					return
+				case event := <-s.events:
+					writeEvent(w, event)
+				case <-heartbeat.C:
+					_, _ = w.WriteString(": ping\n\n") //nolint:errcheck // Write errors are reported by Flush
+				}
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		})
+	}
+}
+
+// Publish sends the event to the subscribers of the topic on all instances
+func (b *Broker) Publish(name string, event Event) error {
+	if event.ID == "" {
+		event.ID = utils.UUIDv4()
+	}
+	if !b.deliver(name, &event) {
+		return ErrClosed
+	}
+	if b.cfg.Backplane == nil {
+		return nil
+	}
+
+	msg, err := json.Marshal(envelope{Origin: b.id, Topic: name, Event: event})
+	if err != nil {
+		return fmt.Errorf("sse: failed to encode event: %w", err)
+	}
+	if err := b.cfg.Backplane.Publish(context.Background(), b.cfg.Channel, msg); err != nil {
+		return fmt.Errorf("sse: failed to publish event: %w", err)
+	}
+	return nil
+}
+
+// Subscribers returns the number of subscribers of the topic on this instance
+func (b *Broker) Subscribers(name string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if t, ok := b.topics[name]; ok {
+		return len(t.subscribers)
+	}
+	return 0
+}
+
+// Close ends the streams of all subscribers and stops receiving events of other instances
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for _, t := range b.topics {
+		for s := range t.subscribers {
+			s.close()
+		}
+	}
+	if b.cancel != nil {
+		b.cancel()
+	}
+	return nil
+}
+
+// subscribe adds the subscriber to the topics and returns the buffered events after
+// the event with lastID in the order they arrived. All buffered events are replayed
+// if lastID isn't buffered anymore.
+func (b *Broker) subscribe(s *subscriber, names []string, lastID string) ([]*Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+
+	var replay []bufferedEvent
+	for _, name := range names {
+		t := b.topic(name)
+		t.subscribers[s] = struct{}{}
+		if lastID != "" {
+			replay = append(replay, t.buffer...)
+		}
+	}
+
+	sort.Slice(replay, func(i, j int) bool {
+		return replay[i].seq < replay[j].seq
+	})
+	for i := len(replay) - 1; i >= 0; i-- {
+		if replay[i].event.ID == lastID {
+			replay = replay[i+1:]
+			break
+		}
+	}
+	events := make([]*Event, len(replay))
+	for i := range replay {
+		events[i] = replay[i].event
+	}
+	return events, nil
+}
+
+func (b *Broker) unsubscribe(s *subscriber, names []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s.close()
+	for _, name := range names {
+		if t, ok := b.topics[name]; ok {
+			delete(t.subscribers, s)
+			if len(t.subscribers) == 0 && len(t.buffer) == 0 {
+				delete(b.topics, name)
+			}
+		}
+	}
+}
+
+// topic returns the topic with the name, b.mu must be locked
+func (b *Broker) topic(name string) *topic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &topic{
+			subscribers: make(map[*subscriber]struct{}),
+			buffer:      make([]bufferedEvent, 0, b.cfg.BufferSize),
+		}
+		b.topics[name] = t
+	}
+	return t
+}
+
+// deliver buffers the event and queues it for the subscribers of the topic on this
+// instance, it reports false if the broker is closed
+func (b *Broker) deliver(name string, event *Event) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return false
+	}
+
+	b.seq++
+	t := b.topic(name)
+	if len(t.buffer) < cap(t.buffer) {
+		t.buffer = append(t.buffer, bufferedEvent{event: event, seq: b.seq})
+	} else {
+		t.buffer[t.next] = bufferedEvent{event: event, seq: b.seq}
+	}
+	t.next = (t.next + 1) % cap(t.buffer)
+
+	for s := range t.subscribers {
+		select {
+		case s.events <- event:
+		default:
+			// The subscriber can't keep up, it replays the missed events when it reconnects
+			s.close()
+		}
+	}
+	return true
+}
+
+// receive delivers the events published by other instances
+func (b *Broker) receive(msg []byte) {
+	var e envelope
+	if err := json.Unmarshal(msg, &e); err != nil {
+		log.Errorf("[SSE] failed to decode event: %v", err)
+		return
+	}
+	if e.Origin == b.id {
+		return
+	}
+	b.deliver(e.Topic, &e.Event)
+}
+
+// writeEvent writes the event in the event stream format
+func writeEvent(w *bufio.Writer, event *Event) {
+	_, _ = w.WriteString("id: " + lineBreaks.Replace(event.ID) + "\n") //nolint:errcheck // Write errors are reported by Flush
+	if event.Event != "" {
+		_, _ = w.WriteString("event: " + lineBreaks.Replace(event.Event) + "\n") //nolint:errcheck // Write errors are reported by Flush
+	}
+	data := event.Data
+	for {
+		line, rest, found := bytes.Cut(data, []byte("\n"))
+		_, _ = w.WriteString("data: ") //nolint:errcheck // Write errors are reported by Flush
+		_, _ = w.Write(line)           //nolint:errcheck // Write errors are reported by Flush
+		_ = w.WriteByte('\n')          //nolint:errcheck // Write errors are reported by Flush
+		if !found {
+			break
+		}
+		data = rest
+	}
+	_ = w.WriteByte('\n') //nolint:errcheck // Write errors are reported by Flush
+}
@@ -0,0 +1,290 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/storage/redis"
+	"github.com/stretchr/testify/require"
+)
+
+var _ PubSub = (*redis.Storage)(nil)
+
+// stream requests the path and returns the response once the broker closed it
+func stream(t *testing.T, app *fiber.App, req *http.Request) <-chan string {
+	t.Helper()
+	body := make(chan string, 1)
+	go func() {
+		resp, err := app.Test(req, fiber.TestConfig{Timeout: 0})
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		body <- string(data)
+	}()
+	return body
+}
+
+// waitSubscribers waits until the topic has n subscribers
+func waitSubscribers(t *testing.T, b *Broker, name string, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		return b.Subscribers(name) == n
+	}, time.Second, time.Millisecond)
+}
+
+func receive(t *testing.T, body <-chan string) string {
+	t.Helper()
+	select {
+	case data := <-body:
+		return data
+	case <-time.After(time.Second):
+		t.Fatal("stream not closed")
+		return ""
+	}
+}
+
+// go test -run Test_SSE_Publish
+func Test_SSE_Publish(t *testing.T) {
+	t.Parallel()
+	b := New(Config{Retry: 3 * time.Second})
+	app := fiber.New()
+	app.Get("/events/:topic", b.Handler(func(c fiber.Ctx) []string {
+		return []string{c.Params("topic")}
+	}))
+
+	body := stream(t, app, httptest.NewRequest(fiber.MethodGet, "/events/news", nil))
+	waitSubscribers(t, b, "news", 1)
+
+	require.NoError(t, b.Publish("news", Event{ID: "1", Event: "update", Data: []byte("first")}))
+	require.NoError(t, b.Publish("sports", Event{ID: "2", Data: []byte("other topic")}))
+	require.NoError(t, b.Publish("news", Event{ID: "3", Data: []byte("line 1\nline 2")}))
+
+	// The events must be written before the stream is closed
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, b.Close())
+	require.Equal(t, "retry: 3000\n\n"+
+		"id: 1\nevent: update\ndata: first\n\n"+
+		"id: 3\ndata: line 1\ndata: line 2\n\n", receive(t, body))
+	require.Equal(t, 0, b.Subscribers("news"))
+	require.ErrorIs(t, b.Publish("news", Event{}), ErrClosed)
+}
+
+// go test -run Test_SSE_Headers
+func Test_SSE_Headers(t *testing.T) {
+	t.Parallel()
+	b := New()
+	app := fiber.New()
+	app.Get("/", b.Handler(Topics("news")))
+
+	require.NoError(t, b.Close())
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+
+	b = New()
+	app = fiber.New()
+	app.Get("/", b.Handler(Topics("news")))
+	go func() {
+		waitSubscribers(t, b, "news", 1)
+		_ = b.Close() //nolint:errcheck // It is a test
+	}()
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil), fiber.TestConfig{Timeout: 0})
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get(fiber.HeaderContentType))
+	require.Equal(t, "no-cache", resp.Header.Get(fiber.HeaderCacheControl))
+	require.Equal(t, "no", resp.Header.Get("X-Accel-Buffering"))
+}
+
+// go test -run Test_SSE_Replay
+func Test_SSE_Replay(t *testing.T) {
+	t.Parallel()
+	b := New(Config{BufferSize: 3})
+	app := fiber.New()
+	app.Get("/", b.Handler(Topics("news", "sports")))
+
+	require.NoError(t, b.Publish("news", Event{ID: "1", Data: []byte("a")}))
+	require.NoError(t, b.Publish("sports", Event{ID: "2", Data: []byte("b")}))
+	require.NoError(t, b.Publish("news", Event{ID: "3", Data: []byte("c")}))
+	require.NoError(t, b.Publish("news", Event{ID: "4", Data: []byte("d")}))
+	require.NoError(t, b.Publish("news", Event{ID: "5", Data: []byte("e")}))
+
+	// The events of all topics after the Last-Event-ID are replayed in order
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set("Last-Event-ID", "3")
+	body := stream(t, app, req)
+	waitSubscribers(t, b, "news", 1)
+
+	// The buffer of news dropped event 1, so all buffered events are replayed
+	body2 := stream(t, app, httptest.NewRequest(fiber.MethodGet, "/?lastEventId=1", nil))
+	waitSubscribers(t, b, "news", 2)
+
+	// Without an ID, nothing is replayed
+	body3 := stream(t, app, httptest.NewRequest(fiber.MethodGet, "/", nil))
+	waitSubscribers(t, b, "news", 3)
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, b.Close())
+	require.Equal(t, "id: 4\ndata: d\n\nid: 5\ndata: e\n\n", receive(t, body))
+	require.Equal(t, "id: 2\ndata: b\n\nid: 3\ndata: c\n\nid: 4\ndata: d\n\nid: 5\ndata: e\n\n", receive(t, body2))
+	require.Equal(t, "", receive(t, body3))
+}
+
+// go test -run Test_SSE_Heartbeat
+func Test_SSE_Heartbeat(t *testing.T) {
+	t.Parallel()
+	b := New(Config{HeartbeatInterval: 5 * time.Millisecond})
+	app := fiber.New()
+	app.Get("/", b.Handler(Topics("news")))
+
+	body := stream(t, app, httptest.NewRequest(fiber.MethodGet, "/", nil))
+	waitSubscribers(t, b, "news", 1)
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, b.Close())
+	require.Contains(t, receive(t, body), ": ping\n\n")
+}
+
+// go test -run Test_SSE_SlowSubscriber
+func Test_SSE_SlowSubscriber(t *testing.T) {
+	t.Parallel()
+	b := New(Config{SendQueueSize: 1})
+	s := &subscriber{
+		events: make(chan *Event, 1),
+		done:   make(chan struct{}),
+	}
+	_, err := b.subscribe(s, []string{"news"}, "")
+	require.NoError(t, err)
+
+	require.NoError(t, b.Publish("news", Event{Data: []byte("1")}))
+	require.NoError(t, b.Publish("news", Event{Data: []byte("2")}))
+	select {
+	case <-s.done:
+	default:
+		t.Fatal("slow subscriber not closed")
+	}
+
+	// The generated ID of the queued event can be used for the replay
+	event := <-s.events
+	require.NotEmpty(t, event.ID)
+	replay, err := b.subscribe(&subscriber{done: make(chan struct{})}, []string{"news"}, event.ID)
+	require.NoError(t, err)
+	require.Len(t, replay, 1)
+	require.Equal(t, []byte("2"), replay[0].Data)
+}
+
+// go test -run Test_SSE_WriteEvent
+func Test_SSE_WriteEvent(t *testing.T) {
+	t.Parallel()
+	var sb strings.Builder
+	w := bufio.NewWriter(&sb)
+	writeEvent(w, &Event{ID: "1\n2", Event: "up\rdate", Data: []byte("a\n\nb")})
+	writeEvent(w, &Event{ID: "3"})
+	require.NoError(t, w.Flush())
+	require.Equal(t, "id: 12\nevent: update\ndata: a\ndata: \ndata: b\n\nid: 3\ndata: \n\n", sb.String())
+}
+
+// memoryPubSub delivers the published messages to the subscribers of the same process
+type memoryPubSub struct {
+	handlers []func(msg []byte)
+	mu       sync.Mutex
+}
+
+func (ps *memoryPubSub) Publish(_ context.Context, _ string, msg []byte) error {
+	ps.mu.Lock()
+	handlers := append([]func(msg []byte){}, ps.handlers...)
+	ps.mu.Unlock()
+	for _, handler := range handlers {
+		handler(msg)
+	}
+	return nil
+}
+
+func (ps *memoryPubSub) Subscribe(_ context.Context, _ string, handler func(msg []byte)) error {
+	ps.mu.Lock()
+	ps.handlers = append(ps.handlers, handler)
+	ps.mu.Unlock()
+	return nil
+}
+
+// go test -run Test_SSE_Backplane
+func Test_SSE_Backplane(t *testing.T) {
+	t.Parallel()
+	ps := &memoryPubSub{}
+	b1 := New(Config{Backplane: ps})
+	b2 := New(Config{Backplane: ps})
+	app1 := fiber.New()
+	app1.Get("/", b1.Handler(Topics("news")))
+	app2 := fiber.New()
+	app2.Get("/", b2.Handler(Topics("news")))
+
+	body1 := stream(t, app1, httptest.NewRequest(fiber.MethodGet, "/", nil))
+	body2 := stream(t, app2, httptest.NewRequest(fiber.MethodGet, "/", nil))
+	waitSubscribers(t, b1, "news", 1)
+	waitSubscribers(t, b2, "news", 1)
+
+	// The events are delivered once to the subscribers of both instances
+	require.NoError(t, b1.Publish("news", Event{ID: "1", Data: []byte("from 1")}))
+	require.NoError(t, b2.Publish("news", Event{ID: "2", Data: []byte("from 2")}))
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, b1.Close())
+	require.NoError(t, b2.Close())
+	expected := "id: 1\ndata: from 1\n\nid: 2\ndata: from 2\n\n"
+	require.Equal(t, expected, receive(t, body1))
+	require.Equal(t, expected, receive(t, body2))
+
+	// The events of other instances are replayed too
+	b3 := New(Config{Backplane: ps})
+	b4 := New(Config{Backplane: ps})
+	require.NoError(t, b3.Publish("news", Event{ID: "3", Data: []byte("c")}))
+	require.NoError(t, b4.Publish("news", Event{ID: "4", Data: []byte("d")}))
+	replay, err := b3.subscribe(&subscriber{done: make(chan struct{})}, []string{"news"}, "3")
+	require.NoError(t, err)
+	require.Len(t, replay, 1)
+	require.Equal(t, "4", replay[0].ID)
+}
+
+// go test -v -run=^$ -bench=Benchmark_SSE_Publish -benchmem -count=4
+func Benchmark_SSE_Publish(b *testing.B) {
+	broker := New()
+	for i := 0; i < 100; i++ {
+		s := &subscriber{
+			events: make(chan *Event, broker.cfg.SendQueueSize),
+			done:   make(chan struct{}),
+		}
+		_, _ = broker.subscribe(s, []string{"news"}, "") //nolint:errcheck // It is a benchmark
+		// Drain the events
+		go func() {
+			for {
+				select {
+				case <-s.events:
+				case <-s.done:
+					return
+				}
+			}
+		}()
+	}
+
+	event := Event{ID: "1", Data: []byte("hello")}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = broker.Publish("news", event) //nolint:errcheck // It is a benchmark
+	}
+	b.StopTimer()
+	_ = broker.Close() //nolint:errcheck // It is a benchmark
+}
@@ -10,7 +10,6 @@ package fiber
 import (
 	"bufio"
 	"context"
-	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -23,6 +22,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fxamacker/cbor/v2"
@@ -64,6 +64,59 @@ type Storage interface {
 	Close() error
 }
 
+// StorageWithContext is an optional interface for storages which support a context.Context,
+// e.g. to cancel requests to a remote storage when the client disconnects.
+// Use a type assertion to detect if a Storage implements it.
+type StorageWithContext interface {
+	// GetWithContext gets the value for the given key like Get.
+	GetWithContext(ctx context.Context, key string) ([]byte, error)
+
+	// SetWithContext stores the given value for the given key like Set.
+	SetWithContext(ctx context.Context, key string, val []byte, exp time.Duration) error
+
+	// DeleteWithContext deletes the value for the given key like Delete.
+	DeleteWithContext(ctx context.Context, key string) error
+}
+
+// AtomicStorage is an optional interface for storages which support atomic operations,
+// so middlewares don't have to use racy read-modify-write cycles.
+// Use a type assertion to detect if a Storage implements it.
+type AtomicStorage interface {
+	// SetNX stores the given value for the given key only if the key does not exist yet.
+	// It returns true if the value was stored.
+	SetNX(key string, val []byte, exp time.Duration) (bool, error)
+
+	// IncrBy increments the integer value of the given key by delta and returns the new value.
+	// A key that does not exist is set to delta with the given expiration, 0 means no expiration.
+	// The expiration of an existing key is not changed.
+	IncrBy(key string, delta int64, exp time.Duration) (int64, error)
+}
+
+// TTLStorage is an optional interface for storages which can report the expiration of keys.
+// Use a type assertion to detect if a Storage implements it.
+type TTLStorage interface {
+	// TTL returns the remaining time to live of the given key.
+	// 0 is returned when the key does not expire and
+	// a negative duration is returned when the key does not exist.
+	TTL(key string) (time.Duration, error)
+}
+
+// ScanStorage is an optional interface for storages which can list their keys.
+// Use a type assertion to detect if a Storage implements it.
+type ScanStorage interface {
+	// Scan returns all keys starting with the given prefix.
+	Scan(prefix string) ([]string, error)
+}
+
+// StorageV2 is a Storage which implements all optional storage interfaces.
+type StorageV2 interface {
+	Storage
+	StorageWithContext
+	AtomicStorage
+	TTLStorage
+	ScanStorage
+}
+
 // ErrorHandler defines a function that will process all errors
 // returned from any handlers in the stack
 //
@@ -98,24 +151,43 @@ type App struct {
 	getString func(b []byte) string
 	// Hooks
 	hooks *Hooks
+	// Background tasks
+	tasks *tasks
+	// Parent of the contexts of the requests of the running server, canceled on shutdown
+	requests atomic.Pointer[requestsContext]
+	// Registered plugins in the order of their registration
+	plugins []Plugin
 	// Latest route & group
 	latestRoute *Route
+	// Stack entries of the latest route, one per method for middleware routes
+	latestRoutes []*Route
 	// newCtxFunc
 	newCtxFunc func(app *App) CustomCtx
 	// TLS handler
 	tlsHandler *TLSHandler
 	// Mount fields
 	mountFields *mountFields
+	// Error handlers of groups, the one with the longest prefix handles the error
+	groupErrorHandlers []groupErrorHandler
+	// Status codes of errors, registered with MapError
+	errorStatuses []errorStatus
 	// Route stack divided by HTTP methods
 	stack [][]*Route
-	// Route stack divided by HTTP methods and route prefixes
-	treeStack []map[string][]*Route
+	// Route stack divided by HTTP methods and route prefixes, which is swapped
+	// atomically when the tree is rebuilt
+	treeStack *atomic.Pointer[[]map[string][]*Route]
 	// custom binders
 	customBinders []CustomBinder
+	// bodyEncoders are the encoders of response bodies by MIME type, registered with RegisterBodyEncoder
+	bodyEncoders map[string]func(data any) ([]byte, error)
 	// customConstraints is a list of external constraints
 	customConstraints []CustomConstraint
 	// sendfiles stores configurations for handling ctx.SendFile operations
 	sendfiles []*sendFileStore
+	// viewGlobals stores the data which is passed to every template render
+	viewGlobals sync.Map
+	// Settings which can be changed at runtime with ReloadConfig
+	runtime runtimeConfig
 	// App config
 	config Config
 	// Indicates if the value was explicitly configured
@@ -127,6 +199,8 @@ type App struct {
 	routesCount uint32
 	// Amount of registered handlers
 	handlersCount uint32
+	// Is set to 1 when a route overrides the timeouts or the body limit of the app
+	routeConfigs uint32
 	// contains the information if the route stack has been changed to build the optimized tree
 	routesRefreshed bool
 }
@@ -274,6 +348,36 @@ type Config struct { //nolint:govet // Aligning the struct fields is not necessa
 	// Default: false
 	DisableHeaderNormalizing bool `json:"disable_header_normalizing"`
 
+	// When set to true, GET routes answer HEAD requests with the same headers and
+	// without a body, unless a HEAD route is registered for the same path.
+	// The GET route is returned by c.Route() for these requests.
+	//
+	// Default: false
+	EnableAutoHead bool `json:"enable_auto_head"`
+
+	// When set to true, c.Context() returns a context which is canceled when the request
+	// is done or the app shuts down, instead of context.Background(). Database queries and
+	// outgoing requests which use it stop with the request. It isn't canceled when the
+	// client disconnects, as fasthttp doesn't notice a disconnect while the handler runs.
+	//
+	// Default: false
+	EnableContextCancellation bool `json:"enable_context_cancellation"`
+
+	// When set to true, OPTIONS requests for paths which are only registered with
+	// other methods are answered with 204 No Content and an Allow header listing
+	// the registered methods. Preflight requests handled by the CORS middleware
+	// and explicit OPTIONS routes are not affected.
+	//
+	// Default: false
+	EnableAutoOptions bool `json:"enable_auto_options"`
+
+	// When set to true, requests whose path is only registered with other methods
+	// are answered with 404 Not Found. By default they are answered with
+	// 405 Method Not Allowed and an Allow header listing the registered methods.
+	//
+	// Default: false
+	DisableMethodNotAllowed bool `json:"disable_method_not_allowed"`
+
 	// This function allows to setup app name for the app
 	//
 	// Default: nil
@@ -306,20 +410,34 @@ type Config struct { //nolint:govet // Aligning the struct fields is not necessa
 	// Default: false
 	ReduceMemoryUsage bool `json:"reduce_memory_usage"`
 
+	// JSONCodec is the JSON library of the app, e.g. sonic, go-json or jsoniter, which is
+	// used for encoding, decoding, streaming and describing decoding errors. The defaults of
+	// JSONEncoder, JSONDecoder and JSONStreamEncoder use it.
+	//
+	// Default: StdJSONCodec{}, which uses encoding/json
+	JSONCodec JSONCodec `json:"-"`
+
 	// When set by an external client of Fiber it will use the provided implementation of a
 	// JSONMarshal
 	//
 	// Allowing for flexibility in using another json library for encoding
-	// Default: json.Marshal
+	// Default: the Marshal method of JSONCodec
 	JSONEncoder utils.JSONMarshal `json:"-"`
 
 	// When set by an external client of Fiber it will use the provided implementation of a
 	// JSONUnmarshal
 	//
 	// Allowing for flexibility in using another json library for decoding
-	// Default: json.Unmarshal
+	// Default: the Unmarshal method of JSONCodec
 	JSONDecoder utils.JSONUnmarshal `json:"-"`
 
+	// When set by an external client of Fiber it will use the provided implementation of a
+	// JSON encoder which writes to the response stream, which is used by c.JSONStream
+	//
+	// Allowing for flexibility in using another json library for encoding large responses
+	// Default: JSONCodec.NewEncoder(w).Encode
+	JSONStreamEncoder func(w io.Writer, v any) error `json:"-"`
+
 	// When set by an external client of Fiber it will use the provided implementation of a
 	// CBORMarshal
 	//
@@ -334,6 +452,34 @@ type Config struct { //nolint:govet // Aligning the struct fields is not necessa
 	// Default: cbor.Unmarshal
 	CBORDecoder utils.CBORUnmarshal `json:"-"`
 
+	// When set by an external client of Fiber it will use the provided implementation of a
+	// Protocol Buffers marshaler, e.g. proto.Marshal of google.golang.org/protobuf
+	//
+	// Allowing for flexibility in using another protobuf library for encoding
+	// Default: the Marshal method of the message, which gogo/protobuf generates
+	ProtobufEncoder func(v any) ([]byte, error) `json:"-"`
+
+	// When set by an external client of Fiber it will use the provided implementation of a
+	// Protocol Buffers unmarshaler, e.g. proto.Unmarshal of google.golang.org/protobuf
+	//
+	// Allowing for flexibility in using another protobuf library for decoding
+	// Default: the Unmarshal method of the message, which gogo/protobuf generates
+	ProtobufDecoder func(data []byte, v any) error `json:"-"`
+
+	// When set by an external client of Fiber it will use the provided implementation of a
+	// MessagePack marshaler, e.g. msgpack.Marshal of github.com/vmihailenco/msgpack
+	//
+	// Allowing for flexibility in using another msgpack library for encoding
+	// Default: msgp.AppendIntf, which uses the MarshalMsg method that tinylib/msgp generates
+	MsgPackEncoder func(v any) ([]byte, error) `json:"-"`
+
+	// When set by an external client of Fiber it will use the provided implementation of a
+	// MessagePack unmarshaler, e.g. msgpack.Unmarshal of github.com/vmihailenco/msgpack
+	//
+	// Allowing for flexibility in using another msgpack library for decoding
+	// Default: the UnmarshalMsg method that tinylib/msgp generates
+	MsgPackDecoder func(data []byte, v any) error `json:"-"`
+
 	// XMLEncoder set by an external client of Fiber it will use the provided implementation of a
 	// XMLMarshal
 	//
@@ -341,6 +487,13 @@ type Config struct { //nolint:govet // Aligning the struct fields is not necessa
 	// Default: xml.Marshal
 	XMLEncoder utils.XMLMarshal `json:"-"`
 
+	// XMLDecoder set by an external client of Fiber it will use the provided implementation of a
+	// XMLUnmarshal
+	//
+	// Allowing for flexibility in using another XML library for decoding
+	// Default: xml.Unmarshal, which also decodes the charsets declared by the XML declaration
+	XMLDecoder utils.XMLUnmarshal `json:"-"`
+
 	// If you find yourself behind some sort of proxy, like a load balancer,
 	// then certain header information may be sent to you using special X-Forwarded-* headers or the Forwarded header.
 	// For example, the Host HTTP header is usually used to return the requested host.
@@ -401,6 +554,26 @@ type Config struct { //nolint:govet // Aligning the struct fields is not necessa
 	//
 	// Optional. Default: false
 	EnableSplittingOnParsers bool `json:"enable_splitting_on_parsers"`
+
+	// When set to true, all requests are answered with 503 Service Unavailable by the
+	// ErrorHandler, except the requests for which MaintenanceNext returns true.
+	// It can be changed at runtime with App.ReloadConfig.
+	//
+	// Default: false
+	Maintenance bool `json:"maintenance"`
+
+	// MaintenanceNext defines a function to skip the maintenance mode when returned true,
+	// e.g. for health checks or admin routes.
+	//
+	// Optional. Default: nil
+	MaintenanceNext func(c Ctx) bool `json:"-"`
+
+	// Plugins contains the config sections of plugins by their name. The section of a
+	// plugin which implements PluginWithConfig is passed to its Configure method before
+	// it is registered with RegisterPlugin.
+	//
+	// Optional. Default: nil
+	Plugins map[string]any `json:"plugins"`
 }
 
 // Default TrustProxyConfig
@@ -462,13 +635,10 @@ var DefaultMethods = []string{
 	MethodPatch,
 }
 
-// DefaultErrorHandler that process return errors from handlers
+// DefaultErrorHandler that process return errors from handlers.
+// The status code of the response is resolved with App.ErrorStatus.
 func DefaultErrorHandler(c Ctx, err error) error {
-	code := StatusInternalServerError
-	var e *Error
-	if errors.As(err, &e) {
-		code = e.Code
-	}
+	code := c.App().ErrorStatus(err)
 	c.Set(HeaderContentType, MIMETextPlainCharsetUTF8)
 	return c.Status(code).SendString(err.Error())
 }
@@ -493,6 +663,7 @@ func New(config ...Config) *App {
 		latestRoute:   &Route{},
 		customBinders: []CustomBinder{},
 		sendfiles:     []*sendFileStore{},
+		treeStack:     &atomic.Pointer[[]map[string][]*Route]{},
 	}
 
 	// Create Ctx pool
@@ -505,6 +676,10 @@ func New(config ...Config) *App {
 	// Define hooks
 	app.hooks = newHooks(app)
 
+	// Define background tasks
+	app.tasks = newTasks()
+	app.requests.Store(newRequestsContext())
+
 	// Define mountFields
 	app.mountFields = newMountFields(app)
 
@@ -545,11 +720,20 @@ func New(config ...Config) *App {
 		app.config.ErrorHandler = DefaultErrorHandler
 	}
 
+	if app.config.JSONCodec == nil {
+		app.config.JSONCodec = StdJSONCodec{}
+	}
 	if app.config.JSONEncoder == nil {
-		app.config.JSONEncoder = json.Marshal
+		app.config.JSONEncoder = app.config.JSONCodec.Marshal
 	}
 	if app.config.JSONDecoder == nil {
-		app.config.JSONDecoder = json.Unmarshal
+		app.config.JSONDecoder = app.config.JSONCodec.Unmarshal
+	}
+	if app.config.JSONStreamEncoder == nil {
+		codec := app.config.JSONCodec
+		app.config.JSONStreamEncoder = func(w io.Writer, v any) error {
+			return codec.NewEncoder(w).Encode(v)
+		}
 	}
 	if app.config.CBOREncoder == nil {
 		app.config.CBOREncoder = cbor.Marshal
@@ -557,21 +741,35 @@ func New(config ...Config) *App {
 	if app.config.CBORDecoder == nil {
 		app.config.CBORDecoder = cbor.Unmarshal
 	}
+	if app.config.ProtobufEncoder == nil {
+		app.config.ProtobufEncoder = marshalProtobuf
+	}
+	if app.config.ProtobufDecoder == nil {
+		app.config.ProtobufDecoder = unmarshalProtobuf
+	}
+	if app.config.MsgPackEncoder == nil {
+		app.config.MsgPackEncoder = marshalMsgPack
+	}
+	if app.config.MsgPackDecoder == nil {
+		app.config.MsgPackDecoder = unmarshalMsgPack
+	}
 	if app.config.XMLEncoder == nil {
 		app.config.XMLEncoder = xml.Marshal
 	}
+	if app.config.XMLDecoder == nil {
+		app.config.XMLDecoder = unmarshalXML
+	}
 	if len(app.config.RequestMethods) == 0 {
 		app.config.RequestMethods = DefaultMethods
 	}
 
-	app.config.TrustProxyConfig.ips = make(map[string]struct{}, len(app.config.TrustProxyConfig.Proxies))
-	for _, ipAddress := range app.config.TrustProxyConfig.Proxies {
-		app.handleTrustedProxy(ipAddress)
-	}
+	// Init the settings which can be changed with ReloadConfig
+	app.initRuntimeConfig()
 
 	// Create router stack
 	app.stack = make([][]*Route, len(app.config.RequestMethods))
-	app.treeStack = make([]map[string][]*Route, len(app.config.RequestMethods))
+	treeStack := make([]map[string][]*Route, len(app.config.RequestMethods))
+	app.treeStack.Store(&treeStack)
 
 	// Override colors
 	app.config.ColorScheme = defaultColors(app.config.ColorScheme)
@@ -583,25 +781,6 @@ func New(config ...Config) *App {
 	return app
 }
 
-// Adds an ip address to TrustProxyConfig.ranges or TrustProxyConfig.ips based on whether it is an IP range or not
-func (app *App) handleTrustedProxy(ipAddress string) {
-	if strings.Contains(ipAddress, "/") {
-		_, ipNet, err := net.ParseCIDR(ipAddress)
-		if err != nil {
-			log.Warnf("IP range %q could not be parsed: %v", ipAddress, err)
-		} else {
-			app.config.TrustProxyConfig.ranges = append(app.config.TrustProxyConfig.ranges, ipNet)
-		}
-	} else {
-		ip := net.ParseIP(ipAddress)
-		if ip == nil {
-			log.Warnf("IP address %q could not be parsed", ipAddress)
-		} else {
-			app.config.TrustProxyConfig.ips[ipAddress] = struct{}{}
-		}
-	}
-}
-
 // NewCtxFunc allows to customize ctx methods as we want.
 // Note: It doesn't allow adding new methods, only customizing exist methods.
 func (app *App) NewCtxFunc(function func(app *App) CustomCtx) {
@@ -619,6 +798,45 @@ func (app *App) RegisterCustomBinder(binder CustomBinder) {
 	app.customBinders = append(app.customBinders, binder)
 }
 
+// RegisterBodyDecoder registers a decoder of request bodies with the Content-Type, e.g. for
+// YAML or TOML, which Bind().Body uses for this content type. The decoder is also
+// available as Bind().Custom(mimeType).
+//
+//	app.RegisterBodyDecoder("application/yaml", yaml.Unmarshal)
+func (app *App) RegisterBodyDecoder(mimeType string, decoder func(body []byte, out any) error) {
+	app.RegisterCustomBinder(&bodyDecoder{mimeType: utils.ToLower(mimeType), decode: decoder})
+}
+
+// RegisterBodyEncoder registers an encoder of response bodies with the Content-Type, e.g. for
+// YAML or TOML, which c.Negotiate offers if the MIME type is in NegotiateOffer.Encoders.
+//
+//	app.RegisterBodyEncoder("application/yaml", yaml.Marshal)
+func (app *App) RegisterBodyEncoder(mimeType string, encoder func(data any) ([]byte, error)) {
+	if app.bodyEncoders == nil {
+		app.bodyEncoders = make(map[string]func(data any) ([]byte, error))
+	}
+	app.bodyEncoders[utils.ToLower(mimeType)] = encoder
+}
+
+// SetViewGlobal adds a value to the data which is passed to every template render,
+// e.g. the current user, a CSP nonce or an asset manifest.
+// If the value is a func(Ctx) any, it is called on every render and its result is used instead.
+// Values passed to Render, set with ViewBind or passed as locals take precedence over globals.
+func (app *App) SetViewGlobal(key string, value any) {
+	app.viewGlobals.Store(key, value)
+}
+
+// SetErrorHandler sets the ErrorHandler of the app, like the ErrorHandler of the config.
+// Use app.Group(prefix).SetErrorHandler to handle the errors of a group differently.
+func (app *App) SetErrorHandler(handler ErrorHandler) Router {
+	app.mutex.Lock()
+	app.config.ErrorHandler = handler
+	app.configured.ErrorHandler = handler
+	app.mutex.Unlock()
+
+	return app
+}
+
 // SetTLSHandler Can be used to set ClientHelloInfo when using TLS with Listener.
 func (app *App) SetTLSHandler(tlsHandler *TLSHandler) {
 	// Attach the tlsHandler to the config
@@ -653,6 +871,29 @@ func (app *App) Name(name string) Router {
 	return app
 }
 
+// Meta Assign metadata to specific route, which is available in the handlers with c.Route().Meta.
+func (app *App) Meta(key string, value any) Router {
+	app.updateLatestRoute(func(route *Route) {
+		if route.Meta == nil {
+			route.Meta = make(Map)
+		}
+		route.Meta[key] = value
+	})
+
+	return app
+}
+
+// updateLatestRoute calls update for the stack entries of the latest created route.
+// GET routes also answer HEAD requests with EnableAutoHead, so they need no HEAD copy.
+func (app *App) updateLatestRoute(update func(route *Route)) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	for _, route := range app.latestRoutes {
+		update(route)
+	}
+}
+
 // GetRoute Get route by name
 func (app *App) GetRoute(name string) Route {
 	for _, routes := range app.stack {
@@ -851,6 +1092,8 @@ func NewError(code int, message ...string) *Error {
 
 // Config returns the app config as value ( read-only ).
 func (app *App) Config() Config {
+	app.runtime.mu.RLock()
+	defer app.runtime.mu.RUnlock()
 	return app.config
 }
 
@@ -873,6 +1116,7 @@ func (app *App) HandlersCount() uint32 {
 
 // Shutdown gracefully shuts down the server without interrupting any active connections.
 // Shutdown works by first closing all open listeners and then waiting indefinitely for all connections to return to idle before shutting down.
+// Afterwards, the context of the background tasks started by Go, Schedule and Ctx.Defer is canceled and Shutdown waits for them to return.
 //
 // Make sure the program doesn't exit and waits instead for Shutdown to return.
 //
@@ -895,22 +1139,51 @@ func (app *App) ShutdownWithTimeout(timeout time.Duration) error {
 }
 
 // ShutdownWithContext shuts down the server including by force if the context's deadline is exceeded.
+// The hooks are executed in the order OnPreShutdown, OnShutdown and, after the in-flight requests
+// and background tasks are done, OnPostShutdown.
 //
 // Make sure the program doesn't exit and waits instead for ShutdownWithTimeout to return.
 //
 // ShutdownWithContext does not close keepalive connections so its recommended to set ReadTimeout to something else than 0.
 func (app *App) ShutdownWithContext(ctx context.Context) error {
-	if app.hooks != nil {
-		// TODO: check should be defered?
-		app.hooks.executeOnShutdownHooks()
+	if app.hooks == nil {
+		return app.shutdownServer(ctx)
 	}
 
+	app.hooks.executeOnPreShutdownHooks()
+	app.hooks.executeOnShutdownHooks()
+	err := app.shutdownServer(ctx)
+	app.hooks.executeOnPostShutdownHooks(err)
+	return err
+}
+
+// requestsContext is the parent of the contexts of the requests of a run of the server
+type requestsContext struct {
+	ctx    context.Context //nolint:containedctx // The context is canceled on shutdown
+	cancel context.CancelFunc
+}
+
+func newRequestsContext() *requestsContext {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &requestsContext{ctx: ctx, cancel: cancel}
+}
+
+// shutdownServer drains the server and stops the background tasks
+func (app *App) shutdownServer(ctx context.Context) error {
 	app.mutex.Lock()
 	defer app.mutex.Unlock()
 	if app.server == nil {
 		return ErrNotRunning
 	}
-	return app.server.ShutdownWithContext(ctx)
+	// Signal the handlers which use the context of their request to stop
+	if app.config.EnableContextCancellation {
+		app.requests.Load().cancel()
+	}
+	if err := app.server.ShutdownWithContext(ctx); err != nil {
+		return err
+	}
+	// The tasks are stopped after the requests, which may start tasks, are done
+	return app.tasks.stop(ctx)
 }
 
 // Server returns the underlying fasthttp server
@@ -1059,6 +1332,7 @@ func (app *App) init() *App {
 	app.server.ReadTimeout = app.config.ReadTimeout
 	app.server.WriteTimeout = app.config.WriteTimeout
 	app.server.IdleTimeout = app.config.IdleTimeout
+	app.server.HeaderReceived = app.headerReceived
 	app.server.ReadBufferSize = app.config.ReadBufferSize
 	app.server.WriteBufferSize = app.config.WriteBufferSize
 	app.server.GetOnly = app.config.GETOnly
@@ -1077,6 +1351,10 @@ func (app *App) init() *App {
 // error handler. Otherwise it uses the configured error handler for
 // the app, which if not set is the DefaultErrorHandler.
 func (app *App) ErrorHandler(ctx Ctx, err error) error {
+	if groupErrHandler := app.groupErrorHandler(ctx); groupErrHandler != nil {
+		return groupErrHandler(ctx, err)
+	}
+
 	var (
 		mountedErrHandler  ErrorHandler
 		mountedPrefixParts int
@@ -1102,6 +1380,42 @@ func (app *App) ErrorHandler(ctx Ctx, err error) error {
 	return app.config.ErrorHandler(ctx, err)
 }
 
+// errorStatus is the status code of an error, registered with MapError
+type errorStatus struct {
+	target error
+	status int
+}
+
+// MapError registers the status code of the responses to errors which match the target
+// with errors.Is, e.g. app.MapError(sql.ErrNoRows, fiber.StatusNotFound). The status code is
+// used by the DefaultErrorHandler and returned by ErrorStatus, the first matching error wins.
+func (app *App) MapError(target error, status int) {
+	app.mutex.Lock()
+	app.errorStatuses = append(app.errorStatuses, errorStatus{target: target, status: status})
+	app.mutex.Unlock()
+}
+
+// ErrorStatus returns the status code of the response to the error. It is the status code of the
+// first error registered with MapError which matches the error, the code of a wrapped *fiber.Error,
+// the status code of a wrapped StatusCoder, or 500 Internal Server Error.
+// Custom ErrorHandlers use it to translate errors like the DefaultErrorHandler.
+func (app *App) ErrorStatus(err error) int {
+	for _, es := range app.errorStatuses {
+		if errors.Is(err, es.target) {
+			return es.status
+		}
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	var sc StatusCoder
+	if errors.As(err, &sc) {
+		return sc.StatusCode()
+	}
+	return StatusInternalServerError
+}
+
 // serverErrorHandler is a wrapper around the application's error handler method
 // user for the fasthttp server configuration. It maps a set of fasthttp errors to fiber
 // errors before calling the application's error handler method.
@@ -1149,6 +1463,11 @@ func (app *App) startupProcess() *App {
 	// build route tree stack
 	app.buildTree()
 
+	// The contexts of the requests of a restarted server derive from a new context
+	if app.requests.Load().ctx.Err() != nil {
+		app.requests.Store(newRequestsContext())
+	}
+
 	return app
 }
 
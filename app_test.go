@@ -109,6 +109,112 @@ func Test_App_MethodNotAllowed(t *testing.T) {
 	require.Equal(t, "GET, HEAD, POST, OPTIONS", resp.Header.Get(HeaderAllow))
 }
 
+func Test_App_DisableMethodNotAllowed(t *testing.T) {
+	t.Parallel()
+	app := New(Config{DisableMethodNotAllowed: true})
+
+	app.Post("/", testEmptyHandler)
+
+	resp, err := app.Test(httptest.NewRequest(MethodPost, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusNotFound, resp.StatusCode)
+	require.Equal(t, "", resp.Header.Get(HeaderAllow))
+}
+
+func Test_App_EnableAutoHead(t *testing.T) {
+	t.Parallel()
+	app := New(Config{EnableAutoHead: true})
+
+	app.Use(func(c Ctx) error {
+		c.Set("X-Middleware", c.Method())
+		return c.Next()
+	})
+	app.Get("/", func(c Ctx) error {
+		c.Set("X-Route", c.Route().Method)
+		return c.SendString("Hello, World!")
+	})
+	app.Get("/explicit", testEmptyHandler)
+	app.Head("/explicit", func(c Ctx) error {
+		return c.SendStatus(StatusNoContent)
+	})
+	app.Post("/post", testEmptyHandler)
+
+	resp, err := app.Test(httptest.NewRequest(MethodHead, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, "13", resp.Header.Get(HeaderContentLength))
+	require.Equal(t, MethodHead, resp.Header.Get("X-Middleware"))
+	require.Equal(t, MethodGet, resp.Header.Get("X-Route"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Empty(t, body)
+
+	// An explicit HEAD route takes precedence
+	resp, err = app.Test(httptest.NewRequest(MethodHead, "/explicit", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusNoContent, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(MethodHead, "/post", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusMethodNotAllowed, resp.StatusCode)
+	require.Equal(t, MethodPost, resp.Header.Get(HeaderAllow))
+
+	resp, err = app.Test(httptest.NewRequest(MethodPost, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusMethodNotAllowed, resp.StatusCode)
+	require.Equal(t, "GET, HEAD", resp.Header.Get(HeaderAllow))
+
+	// Without the config, HEAD requests for GET routes aren't matched
+	app = New()
+	app.Get("/", testEmptyHandler)
+	resp, err = app.Test(httptest.NewRequest(MethodHead, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func Test_App_EnableAutoOptions(t *testing.T) {
+	t.Parallel()
+	app := New(Config{EnableAutoOptions: true})
+
+	app.Get("/users", testEmptyHandler)
+	app.Post("/users", testEmptyHandler)
+	app.Get("/explicit", testEmptyHandler)
+	app.Options("/explicit", func(c Ctx) error {
+		return c.SendString("explicit")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodOptions, "/users", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusNoContent, resp.StatusCode)
+	require.Equal(t, "GET, POST, OPTIONS", resp.Header.Get(HeaderAllow))
+
+	// An explicit OPTIONS route takes precedence
+	resp, err = app.Test(httptest.NewRequest(MethodOptions, "/explicit", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, "", resp.Header.Get(HeaderAllow))
+
+	resp, err = app.Test(httptest.NewRequest(MethodOptions, "/unknown", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusNotFound, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(MethodPut, "/users", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusMethodNotAllowed, resp.StatusCode)
+	require.Equal(t, "GET, POST", resp.Header.Get(HeaderAllow))
+
+	// Without the config, OPTIONS requests aren't answered
+	app = New()
+	app.Get("/users", testEmptyHandler)
+	resp, err = app.Test(httptest.NewRequest(MethodOptions, "/users", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusMethodNotAllowed, resp.StatusCode)
+}
+
 func Test_App_Custom_Middleware_404_Should_Not_SetMethodNotAllowed(t *testing.T) {
 	t.Parallel()
 	app := New()
@@ -236,6 +342,38 @@ func Test_App_CustomConstraint(t *testing.T) {
 	require.Equal(t, 404, resp.StatusCode, "Status code")
 }
 
+func Test_App_Constraint_FallThrough(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/users/:id<int>", func(c Ctx) error {
+		return c.SendString("id " + c.Params("id"))
+	})
+	app.Get("/users/:name", func(c Ctx) error {
+		return c.SendString("name " + c.Params("name"))
+	})
+	app.Get(`/files/:name<regex(\w+\.png)>`, func(c Ctx) error {
+		return c.SendString("png " + c.Params("name"))
+	})
+	app.Get("/orders/:uuid<guid>", func(c Ctx) error {
+		return c.SendString("order " + c.Params("uuid"))
+	})
+
+	for path, expected := range map[string]string{
+		"/users/42":    "id 42",
+		"/users/john":  "name john",
+		"/files/a.png": "png a.png",
+		"/files/a.jpg": "Cannot GET /files/a.jpg",
+		"/orders/CD2C1638-1638-72D5-1638-DEADBEEF1638": "order CD2C1638-1638-72D5-1638-DEADBEEF1638",
+		"/orders/42": "Cannot GET /orders/42",
+	} {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, path, nil))
+		require.NoError(t, err, "app.Test(req)")
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, expected, string(body), path)
+	}
+}
+
 func Test_App_ErrorHandler_Custom(t *testing.T) {
 	t.Parallel()
 	app := New(Config{
@@ -313,6 +451,105 @@ func Test_App_ErrorHandler_RouteStack(t *testing.T) {
 	require.Equal(t, "1: USE error", string(body))
 }
 
+func Test_App_ErrorHandler_Group(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.SetErrorHandler(func(c Ctx, err error) error {
+		return c.Status(StatusInternalServerError).SendString("app: " + err.Error())
+	})
+	failing := func(_ Ctx) error {
+		return errors.New("failed")
+	}
+
+	api := app.Group("/api").SetErrorHandler(func(c Ctx, err error) error {
+		return c.Status(StatusInternalServerError).JSON(Map{"error": err.Error()})
+	})
+	api.Get("/users", failing)
+	api.Group("/v2").SetErrorHandler(func(c Ctx, err error) error {
+		return c.Status(StatusInternalServerError).SendString("v2: " + err.Error())
+	}).Get("/users", failing)
+	app.Get("/apix", failing)
+	app.Get("/web", failing)
+
+	tenants := app.Host(":tenant.example.com").SetErrorHandler(func(c Ctx, err error) error {
+		return c.Status(StatusInternalServerError).SendString(c.Hostname() + ": " + err.Error())
+	})
+	tenants.Get("/dashboard", failing)
+
+	testCases := []struct {
+		path string
+		host string
+		body string
+	}{
+		{path: "/api/users", body: `{"error":"failed"}`},
+		{path: "/API/users", body: `{"error":"failed"}`},
+		{path: "/api/unknown", body: `{"error":"Cannot GET /api/unknown"}`},
+		{path: "/api/v2/users", body: "v2: failed"},
+		{path: "/apix", body: "app: failed"},
+		{path: "/web", body: "app: failed"},
+		{path: "/dashboard", host: "acme.example.com", body: "acme.example.com: failed"},
+		{path: "/web", host: "acme.example.com", body: "acme.example.com: failed"},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest(MethodGet, tc.path, nil)
+		if tc.host != "" {
+			req.Host = tc.host
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err, "app.Test(req)")
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, tc.body, string(body), tc.host+tc.path)
+	}
+}
+
+type statusError struct {
+	status int
+}
+
+func (e statusError) Error() string {
+	return "status error"
+}
+
+func (e statusError) StatusCode() int {
+	return e.status
+}
+
+func Test_App_MapError(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	errNoRows := errors.New("no rows")
+	errConflict := errors.New("conflict")
+	app.MapError(errNoRows, StatusNotFound)
+	app.MapError(errConflict, StatusConflict)
+	app.MapError(ErrBadRequest, StatusUnprocessableEntity)
+
+	testCases := []struct {
+		err    error
+		status int
+	}{
+		{err: errNoRows, status: StatusNotFound},
+		{err: fmt.Errorf("find user: %w", errNoRows), status: StatusNotFound},
+		{err: fmt.Errorf("save user: %w", errConflict), status: StatusConflict},
+		{err: ErrBadRequest, status: StatusUnprocessableEntity},
+		{err: ErrForbidden, status: StatusForbidden},
+		{err: fmt.Errorf("wrapped: %w", statusError{status: StatusTeapot}), status: StatusTeapot},
+		{err: errors.New("unknown"), status: StatusInternalServerError},
+	}
+	for i, tc := range testCases {
+		require.Equal(t, tc.status, app.ErrorStatus(tc.err), tc.err.Error())
+
+		path := fmt.Sprintf("/%d", i)
+		app.Get(path, func(_ Ctx) error {
+			return tc.err
+		})
+		resp, err := app.Test(httptest.NewRequest(MethodGet, path, nil))
+		require.NoError(t, err, "app.Test(req)")
+		require.Equal(t, tc.status, resp.StatusCode, tc.err.Error())
+	}
+}
+
 func Test_App_serverErrorHandler_Internal_Error(t *testing.T) {
 	t.Parallel()
 	app := New()
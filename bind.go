@@ -12,6 +12,24 @@ type CustomBinder interface {
 	Parse(c Ctx, out any) error
 }
 
+// bodyDecoder is the custom binder of a decoder registered with App.RegisterBodyDecoder
+type bodyDecoder struct {
+	decode   func(body []byte, out any) error
+	mimeType string
+}
+
+func (d *bodyDecoder) Name() string {
+	return d.mimeType
+}
+
+func (d *bodyDecoder) MIMETypes() []string {
+	return []string{d.mimeType}
+}
+
+func (d *bodyDecoder) Parse(c Ctx, out any) error {
+	return d.decode(c.Body(), out)
+}
+
 // StructValidator is an interface to register custom struct validator for binding.
 type StructValidator interface {
 	Validate(out any) error
@@ -21,6 +39,7 @@ type StructValidator interface {
 type Bind struct {
 	ctx            Ctx
 	dontHandleErrs bool
+	skipValidation bool
 }
 
 // WithoutAutoHandling If you want to handle binder errors manually, you can use `WithoutAutoHandling`.
@@ -52,9 +71,15 @@ func (b *Bind) returnErr(err error) error {
 
 // Struct validation.
 func (b *Bind) validateStruct(out any) error {
+	if b.skipValidation {
+		return nil
+	}
 	validator := b.ctx.App().config.StructValidator
-	if validator != nil {
-		return validator.Validate(out)
+	if validator == nil {
+		return nil
+	}
+	if err := validator.Validate(out); err != nil {
+		return &ValidationError{Err: err}
 	}
 
 	return nil
@@ -112,9 +137,15 @@ func (b *Bind) Query(out any) error {
 	return b.validateStruct(out)
 }
 
-// JSON binds the body string into the struct.
+// JSON binds the body string into the struct. Fields with values of the wrong type are
+// described with the ErrorDetail of the JSONCodec of the app.
 func (b *Bind) JSON(out any) error {
-	if err := b.returnErr(binder.JSONBinder.Bind(b.ctx.Body(), b.ctx.App().Config().JSONDecoder, out)); err != nil {
+	cfg := b.ctx.App().config
+	err := binder.JSONBinder.Bind(b.ctx.Body(), cfg.JSONDecoder, out)
+	if err != nil {
+		err = describeJSONError(cfg.JSONCodec, err)
+	}
+	if err := b.returnErr(err); err != nil {
 		return err
 	}
 
@@ -129,9 +160,27 @@ func (b *Bind) CBOR(out any) error {
 	return b.validateStruct(out)
 }
 
-// XML binds the body string into the struct.
+// Protobuf binds the body into the protobuf message with the ProtobufDecoder of the app.
+func (b *Bind) Protobuf(out any) error {
+	if err := b.returnErr(binder.ProtobufBinder.Bind(b.ctx.Body(), b.ctx.App().Config().ProtobufDecoder, out)); err != nil {
+		return err
+	}
+	return b.validateStruct(out)
+}
+
+// MsgPack binds the MessagePack body into the struct with the MsgPackDecoder of the app.
+func (b *Bind) MsgPack(out any) error {
+	if err := b.returnErr(binder.MsgPackBinder.Bind(b.ctx.Body(), b.ctx.App().Config().MsgPackDecoder, out)); err != nil {
+		return err
+	}
+	return b.validateStruct(out)
+}
+
+// XML binds the body string into the struct with the XMLDecoder of the app.
+// Bodies in another charset than UTF-8, e.g. "text/xml; charset=ISO-8859-1", are converted to UTF-8 first.
 func (b *Bind) XML(out any) error {
-	if err := b.returnErr(binder.XMLBinder.Bind(b.ctx.Body(), out)); err != nil {
+	ctype := utils.UnsafeString(b.ctx.RequestCtx().Request.Header.ContentType())
+	if err := b.returnErr(binder.XMLBinder.Bind(b.ctx.Body(), ctype, b.ctx.App().Config().XMLDecoder, out)); err != nil {
 		return err
 	}
 
@@ -167,8 +216,9 @@ func (b *Bind) MultipartForm(out any) error {
 
 // Body binds the request body into the struct, map[string]string and map[string][]string.
 // It supports decoding the following content types based on the Content-Type header:
-// application/json, application/xml, application/x-www-form-urlencoded, multipart/form-data
+// application/json, application/xml, application/cbor, application/x-protobuf, application/msgpack, application/x-www-form-urlencoded, multipart/form-data
 // If none of the content types above are matched, it'll take a look custom binders by checking the MIMETypes() method of custom binder.
+// Custom binders and decoders registered with App.RegisterBodyDecoder take precedence over the content types above.
 // If there're no custom binder for mime type of body, it will return a ErrUnprocessableEntity error.
 func (b *Bind) Body(out any) error {
 	// Get content-type
@@ -180,7 +230,11 @@ func (b *Bind) Body(out any) error {
 	for _, customBinder := range binders {
 		for _, mime := range customBinder.MIMETypes() {
 			if mime == ctype {
-				return b.returnErr(customBinder.Parse(b.ctx, out))
+				if err := b.returnErr(customBinder.Parse(b.ctx, out)); err != nil {
+					return err
+				}
+
+				return b.validateStruct(out)
 			}
 		}
 	}
@@ -193,6 +247,10 @@ func (b *Bind) Body(out any) error {
 		return b.XML(out)
 	case MIMEApplicationCBOR:
 		return b.CBOR(out)
+	case MIMEApplicationProtobuf:
+		return b.Protobuf(out)
+	case MIMEApplicationMsgPack:
+		return b.MsgPack(out)
 	case MIMEApplicationForm:
 		return b.Form(out)
 	case MIMEMultipartForm:
@@ -202,3 +260,53 @@ func (b *Bind) Body(out any) error {
 	// No suitable content type found
 	return ErrUnprocessableEntity
 }
+
+// All binds the request headers, the cookies, the query string, the body, if there is one,
+// and the route parameters into the struct, in this order. A source overrides the fields
+// which earlier sources bound, so the route parameters take precedence over the body and
+// the body over the query string. The struct is validated once all sources were bound.
+func (b *Bind) All(out any) error {
+	b.skipValidation = true
+	err := b.Header(out)
+	if err == nil {
+		err = b.Cookie(out)
+	}
+	if err == nil {
+		err = b.Query(out)
+	}
+	if err == nil && len(b.ctx.Body()) > 0 {
+		err = b.Body(out)
+	}
+	if err == nil {
+		err = b.URI(out)
+	}
+	b.skipValidation = false
+	if err != nil {
+		return err
+	}
+
+	return b.validateStruct(out)
+}
+
+// Wrap returns a handler which binds the request into a Req, like Bind.All, validates it
+// with the StructValidator of the app and calls the function. The returned Resp is sent as JSON, XML or CBOR, depending on the
+// Accept header, and JSON is used if the header is missing.
+// Binding errors are returned as 400 Bad Request errors and validation errors as ValidationErrors.
+//
+//	app.Post("/users/:id", fiber.Wrap(func(c fiber.Ctx, req UpdateUser) (User, error) {
+//		return users.Update(req.ID, req.Name)
+//	}))
+func Wrap[Req, Resp any](handler func(c Ctx, req Req) (Resp, error)) Handler {
+	return func(c Ctx) error {
+		var req Req
+		if err := (&Bind{ctx: c}).All(&req); err != nil {
+			return err
+		}
+		resp, err := handler(c, req)
+		if err != nil {
+			return err
+		}
+
+		return c.Negotiate(resp, NegotiateOffer{JSON: true, XML: true, CBOR: true})
+	}
+}
@@ -5,16 +5,20 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/fxamacker/cbor/v2"
 	"github.com/gofiber/fiber/v3/binder"
 	"github.com/stretchr/testify/require"
+	"github.com/tinylib/msgp/msgp"
 	"github.com/valyala/fasthttp"
 )
 
@@ -146,6 +150,57 @@ func Test_Bind_Query_Map(t *testing.T) {
 	require.ErrorIs(t, c.Bind().Query(&em), binder.ErrMapNotConvertable)
 }
 
+// go test -run Test_Bind_Query_MapFields -v
+func Test_Bind_Query_MapFields(t *testing.T) {
+	t.Parallel()
+
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	type Page struct {
+		Sort  map[string]string `query:"sort"`
+		Depth int               `query:"depth"`
+	}
+	type Query struct {
+		Filter  map[string]string   `query:"filter"`
+		Tags    map[string][]string `query:"tags"`
+		Ignored map[string]int      `query:"ignored"`
+		Params  Page                `query:"params"`
+	}
+	c.Request().URI().SetQueryString("filter[name]=john&filter[age]=30&Filter.city=berlin&tags[a]=1&tags[a]=2&ignored[a]=1&params.depth=2&params[sort][name]=asc")
+	q := new(Query)
+	require.NoError(t, c.Bind().Query(q))
+	require.Equal(t, map[string]string{"name": "john", "age": "30", "city": "berlin"}, q.Filter)
+	require.Equal(t, map[string][]string{"a": {"1", "2"}}, q.Tags)
+	require.Nil(t, q.Ignored)
+	require.Equal(t, Page{Sort: map[string]string{"name": "asc"}, Depth: 2}, q.Params)
+
+	c.Request().URI().SetQueryString("name=john")
+	q = new(Query)
+	require.NoError(t, c.Bind().Query(q))
+	require.Nil(t, q.Filter)
+}
+
+// go test -run Test_Bind_Query_SliceDelimiter -v
+func Test_Bind_Query_SliceDelimiter(t *testing.T) {
+	t.Parallel()
+
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	type Query struct {
+		IDs   []int    `query:"ids,split:|"`
+		Tags  []string `query:"tags,split:;"`
+		Names []string `query:"names"`
+	}
+	c.Request().URI().SetQueryString("ids=1|2|3&tags=a,b;c&names=john,doe")
+	q := new(Query)
+	require.NoError(t, c.Bind().Query(q))
+	require.Equal(t, []int{1, 2, 3}, q.IDs)
+	require.Equal(t, []string{"a,b", "c"}, q.Tags)
+	require.Equal(t, []string{"john", "doe"}, q.Names)
+}
+
 // go test -run Test_Bind_Query_WithSetParserDecoder -v
 func Test_Bind_Query_WithSetParserDecoder(t *testing.T) {
 	type NonRFCTime time.Time
@@ -400,6 +455,27 @@ func Test_Bind_Header_Map(t *testing.T) {
 	require.Empty(t, empty["Hobby"])
 }
 
+// go test -run Test_Bind_Header_MultiValue -v
+func Test_Bind_Header_MultiValue(t *testing.T) {
+	t.Parallel()
+
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	type Header struct {
+		Authorization string   `header:"authorization"`
+		Flags         []string `header:"X-Feature-Flag"`
+		Page          int      `header:"x-page"`
+	}
+	c.Request().Header.Set("x-page", "2")
+	c.Request().Header.Set(HeaderAuthorization, "Bearer token")
+	c.Request().Header.Add("x-feature-flag", "dark-mode")
+	c.Request().Header.Add("X-Feature-Flag", "beta,search")
+	h := new(Header)
+	require.NoError(t, c.Bind().Header(h))
+	require.Equal(t, Header{Authorization: "Bearer token", Flags: []string{"dark-mode", "beta", "search"}, Page: 2}, *h)
+}
+
 // go test -run Test_Bind_Header_WithSetParserDecoder -v
 func Test_Bind_Header_WithSetParserDecoder(t *testing.T) {
 	type NonRFCTime time.Time
@@ -1268,6 +1344,31 @@ func Test_Bind_URI_Map(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// go test -run Test_Bind_URI_ConversionError
+func Test_Bind_URI_ConversionError(t *testing.T) {
+	t.Parallel()
+
+	app := New()
+	app.Get("/orgs/:org/repos/:repo/issues/:issue", func(c Ctx) error {
+		params := new(struct {
+			Org   string `uri:"org"`
+			Repo  int    `uri:"repo"`
+			Issue uint   `uri:"issue"`
+		})
+		err := c.Bind().URI(params)
+		require.EqualError(t, err, `bind: issue: "-1" is not a valid uint; repo: "abc" is not a valid int`)
+
+		var multiErr MultiError
+		require.ErrorAs(t, err, &multiErr)
+		var convErr ConversionError
+		require.ErrorAs(t, multiErr["repo"], &convErr)
+		require.Equal(t, reflect.TypeOf(0), convErr.Type)
+		return nil
+	})
+	_, err := app.Test(httptest.NewRequest(MethodGet, "/orgs/gofiber/repos/abc/issues/-1", nil))
+	require.NoError(t, err)
+}
+
 // go test -v -run=^$ -bench=Benchmark_Bind_URI -benchmem -count=4
 func Benchmark_Bind_URI(b *testing.B) {
 	var err error
@@ -1672,6 +1773,209 @@ func Test_Bind_CustomBinder(t *testing.T) {
 	require.Equal(t, "john", d.Name)
 }
 
+// go test -run Test_Bind_BodyDecoder
+func Test_Bind_BodyDecoder(t *testing.T) {
+	t.Parallel()
+	app := New(Config{StructValidator: &wrapValidator{}})
+	app.RegisterBodyDecoder("Application/X-Lines", func(body []byte, out any) error {
+		name, _, _ := strings.Cut(string(body), "\n")
+		reflect.ValueOf(out).Elem().FieldByName("Name").SetString(name)
+		return nil
+	})
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	type Demo struct {
+		Name string
+	}
+	c.Request().SetBody([]byte("john\ndoe"))
+	c.Request().Header.SetContentType("application/x-lines; charset=utf-8")
+	d := new(Demo)
+	require.NoError(t, c.Bind().Body(d))
+	require.Equal(t, "john", d.Name)
+
+	d = new(Demo)
+	require.NoError(t, c.Bind().Custom("application/x-lines", d))
+	require.Equal(t, "john", d.Name)
+
+	// The decoded struct is validated
+	c.Request().SetBody([]byte("\ndoe"))
+	var validationErr *ValidationError
+	require.ErrorAs(t, c.Bind().Body(new(Demo)), &validationErr)
+}
+
+// protoUser is a protobuf message with the string field 1, like the messages of gogo/protobuf
+type protoUser struct {
+	Name string
+}
+
+func (u *protoUser) Marshal() ([]byte, error) {
+	if len(u.Name) > 127 {
+		return nil, errors.New("name too long")
+	}
+	return append([]byte{0x0a, byte(len(u.Name))}, u.Name...), nil
+}
+
+func (u *protoUser) Unmarshal(data []byte) error {
+	if len(data) < 2 || data[0] != 0x0a || int(data[1]) != len(data)-2 {
+		return errors.New("invalid message")
+	}
+	u.Name = string(data[2:])
+	return nil
+}
+
+// go test -run Test_Bind_Protobuf
+func Test_Bind_Protobuf(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	c.Request().SetBody([]byte("\x0a\x04john"))
+	c.Request().Header.SetContentType(MIMEApplicationProtobuf)
+	u := new(protoUser)
+	require.NoError(t, c.Bind().Body(u))
+	require.Equal(t, "john", u.Name)
+
+	c.Request().SetBody([]byte("\x0a\x05john"))
+	require.EqualError(t, c.Bind().Protobuf(u), "invalid message")
+
+	type Demo struct {
+		Name string
+	}
+	require.ErrorIs(t, c.Bind().Protobuf(new(Demo)), ErrProtobufUnmarshaler)
+
+	// Pluggable decoder, e.g. proto.Unmarshal of google.golang.org/protobuf
+	app = New(Config{ProtobufDecoder: func(data []byte, v any) error {
+		d, ok := v.(*Demo)
+		if !ok {
+			return errors.New("unexpected message")
+		}
+		d.Name = string(data)
+		return nil
+	}})
+	c = app.AcquireCtx(&fasthttp.RequestCtx{})
+	c.Request().SetBody([]byte("doe"))
+	c.Request().Header.SetContentType(MIMEApplicationProtobuf)
+	d := new(Demo)
+	require.NoError(t, c.Bind().Body(d))
+	require.Equal(t, "doe", d.Name)
+}
+
+// go test -run Test_Bind_XML
+func Test_Bind_XML(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	type Envelope struct {
+		XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Envelope"`
+		Body    struct {
+			User struct {
+				ID   string `xml:"id,attr"`
+				Name string `xml:"urn:users name"`
+			} `xml:"urn:users User"`
+		} `xml:"http://schemas.xmlsoap.org/soap/envelope/ Body"`
+	}
+
+	// Attributes and namespaces
+	c.Request().Header.SetContentType(MIMETextXML)
+	c.Request().SetBody([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:u="urn:users">` +
+		`<soap:Body><u:User id="1"><u:name>john</u:name></u:User></soap:Body></soap:Envelope>`))
+	e := new(Envelope)
+	require.NoError(t, c.Bind().Body(e))
+	require.Equal(t, "1", e.Body.User.ID)
+	require.Equal(t, "john", e.Body.User.Name)
+
+	type Demo struct {
+		Name string `xml:"name"`
+	}
+
+	// Charset of the XML declaration
+	c.Request().SetBody([]byte("<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><Demo><name>Jos\xe9</name></Demo>"))
+	d := new(Demo)
+	require.NoError(t, c.Bind().XML(d))
+	require.Equal(t, "José", d.Name)
+
+	// Charset of the content type takes precedence over the XML declaration
+	c.Request().Header.SetContentType(MIMEApplicationXML + "; charset=windows-1252")
+	c.Request().SetBody([]byte("<?xml version='1.0' encoding='UTF-8'?><Demo><name>\x80 Jos\xe9</name></Demo>"))
+	d = new(Demo)
+	require.NoError(t, c.Bind().Body(d))
+	require.Equal(t, "€ José", d.Name)
+
+	c.Request().Header.SetContentType(MIMEApplicationXML + "; charset=utf-8")
+	c.Request().SetBody([]byte("<Demo><name>José</name></Demo>"))
+	d = new(Demo)
+	require.NoError(t, c.Bind().Body(d))
+	require.Equal(t, "José", d.Name)
+
+	c.Request().Header.SetContentType(MIMEApplicationXML + "; charset=unknown")
+	require.ErrorContains(t, c.Bind().XML(d), `failed to convert xml from charset "unknown"`)
+
+	// Pluggable decoder
+	app = New(Config{XMLDecoder: func(data []byte, v any) error {
+		d, ok := v.(*Demo)
+		if !ok {
+			return errors.New("unexpected value")
+		}
+		d.Name = string(data)
+		return nil
+	}})
+	c = app.AcquireCtx(&fasthttp.RequestCtx{})
+	c.Request().Header.SetContentType(MIMEApplicationXML)
+	c.Request().SetBody([]byte("doe"))
+	d = new(Demo)
+	require.NoError(t, c.Bind().Body(d))
+	require.Equal(t, "doe", d.Name)
+	require.ErrorContains(t, c.Bind().XML(new(Envelope)), "failed to unmarshal xml: unexpected value")
+}
+
+// go test -run Test_Bind_MsgPack
+func Test_Bind_MsgPack(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	msg := &redirectionMsg{key: "name", value: "john", level: 1}
+	raw, err := msg.MarshalMsg(nil)
+	require.NoError(t, err)
+	c.Request().SetBody(raw)
+	c.Request().Header.SetContentType(MIMEApplicationMsgPack)
+	out := new(redirectionMsg)
+	require.NoError(t, c.Bind().Body(out))
+	require.Equal(t, msg, out)
+
+	raw, err = msgp.AppendIntf(nil, Map{"name": "doe"})
+	require.NoError(t, err)
+	c.Request().SetBody(raw)
+	m := map[string]any{}
+	require.NoError(t, c.Bind().MsgPack(&m))
+	require.Equal(t, map[string]any{"name": "doe"}, m)
+
+	c.Request().SetBody([]byte{0xc1})
+	require.Error(t, c.Bind().MsgPack(&m))
+
+	type Demo struct {
+		Name string
+	}
+	require.ErrorIs(t, c.Bind().MsgPack(new(Demo)), ErrMsgPackUnmarshaler)
+
+	// Pluggable decoder, e.g. msgpack.Unmarshal of github.com/vmihailenco/msgpack
+	app = New(Config{MsgPackDecoder: func(data []byte, v any) error {
+		d, ok := v.(*Demo)
+		if !ok {
+			return errors.New("unexpected value")
+		}
+		d.Name = string(data)
+		return nil
+	}})
+	c = app.AcquireCtx(&fasthttp.RequestCtx{})
+	c.Request().SetBody([]byte("doe"))
+	c.Request().Header.SetContentType(MIMEApplicationMsgPack)
+	d := new(Demo)
+	require.NoError(t, c.Bind().Body(d))
+	require.Equal(t, "doe", d.Name)
+}
+
 // go test -run Test_Bind_WithAutoHandling
 func Test_Bind_WithAutoHandling(t *testing.T) {
 	app := New()
@@ -1715,13 +2019,160 @@ func Test_Bind_StructValidator(t *testing.T) {
 
 	rq := new(simpleQuery)
 	c.Request().URI().SetQueryString("name=efe")
-	require.Equal(t, "you should have entered right name", c.Bind().Query(rq).Error())
+	err := c.Bind().Query(rq)
+	require.Equal(t, "you should have entered right name", err.Error())
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.EqualError(t, validationErr.Err, "you should have entered right name")
+	require.Equal(t, StatusUnprocessableEntity, app.ErrorStatus(err))
 
 	rq = new(simpleQuery)
 	c.Request().URI().SetQueryString("name=john")
 	require.NoError(t, c.Bind().Query(rq))
 }
 
+// go test -run Test_Bind_All
+func Test_Bind_All(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		Token   string `header:"X-Token"`
+		Session string `cookie:"session"`
+		Name    string `query:"name" json:"name"`
+		Page    int    `query:"page"`
+		ID      int    `query:"id" json:"id" uri:"id"`
+	}
+
+	app := New(Config{StructValidator: &wrapValidator{}})
+	app.Post("/users/:id", func(c Ctx) error {
+		req := new(request)
+		if err := c.Bind().All(req); err != nil {
+			return err
+		}
+		return c.JSON(req)
+	})
+
+	// The body overrides the query string and the route parameters override the body
+	req := httptest.NewRequest(MethodPost, "/users/3?name=query&page=2&id=1", strings.NewReader(`{"name":"body","id":2}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	req.Header.Set("X-Token", "token")
+	req.Header.Set(HeaderCookie, "session=abc")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"Token":"token","Session":"abc","name":"body","Page":2,"id":3}`, string(body))
+
+	// Without a body, the query string is bound
+	resp, err = app.Test(httptest.NewRequest(MethodPost, "/users/3?name=query", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"Token":"","Session":"","name":"query","Page":0,"id":3}`, string(body))
+
+	// The struct is validated once all sources were bound
+	resp, err = app.Test(httptest.NewRequest(MethodPost, "/users/3", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusUnprocessableEntity, resp.StatusCode)
+
+	// Binding errors stop the binding
+	resp, err = app.Test(httptest.NewRequest(MethodPost, "/users/abc?name=query", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusInternalServerError, resp.StatusCode)
+}
+
+// go test -run Test_Bind_Wrap
+func Test_Bind_Wrap(t *testing.T) {
+	t.Parallel()
+
+	type updateUser struct {
+		Name   string `json:"name" xml:"name"`
+		ID     int    `uri:"id" json:"-" xml:"-"`
+		Notify bool   `query:"notify" json:"-" xml:"-"`
+	}
+	type user struct {
+		XMLName xml.Name `json:"-" xml:"user"`
+		Name    string   `json:"name" xml:"name"`
+		ID      int      `json:"id" xml:"id"`
+		Notify  bool     `json:"notify" xml:"notify"`
+	}
+
+	app := New(Config{StructValidator: &wrapValidator{}})
+	app.Put("/users/:id", Wrap(func(_ Ctx, req updateUser) (user, error) {
+		if req.ID == 0 {
+			return user{}, ErrNotFound
+		}
+		return user{Name: req.Name, ID: req.ID, Notify: req.Notify}, nil
+	}))
+
+	testCases := []struct {
+		name        string
+		uri         string
+		contentType string
+		body        string
+		accept      string
+		respBody    string
+		status      int
+	}{
+		{
+			name: "json", uri: "/users/1?notify=true", contentType: MIMEApplicationJSON, body: `{"name":"john"}`,
+			status: StatusOK, respBody: `{"name":"john","id":1,"notify":true}`,
+		},
+		{
+			name: "xml", uri: "/users/2", contentType: MIMEApplicationXML, body: `<user><name>doe</name></user>`, accept: MIMEApplicationXML,
+			status: StatusOK, respBody: `<user><name>doe</name><id>2</id><notify>false</notify></user>`,
+		},
+		{
+			name: "handler error", uri: "/users/0", contentType: MIMEApplicationJSON, body: `{"name":"john"}`,
+			status: StatusNotFound, respBody: "Not Found",
+		},
+		{
+			name: "binding error", uri: "/users/abc", contentType: MIMEApplicationJSON, body: `{"name":"john"}`,
+			status: StatusBadRequest,
+		},
+		{
+			name: "invalid body", uri: "/users/1", contentType: MIMEApplicationJSON, body: `{"name":`,
+			status: StatusBadRequest,
+		},
+		{
+			name: "unknown content type", uri: "/users/1", contentType: MIMETextPlain, body: "john",
+			status: StatusUnprocessableEntity, respBody: "Unprocessable Entity",
+		},
+		{
+			name: "validation error", uri: "/users/1", status: StatusUnprocessableEntity, respBody: "name is required",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(MethodPut, tc.uri, strings.NewReader(tc.body))
+			req.Header.Set(HeaderContentType, tc.contentType)
+			req.Header.Set(HeaderAccept, tc.accept)
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			require.Equal(t, tc.status, resp.StatusCode)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			if tc.respBody != "" {
+				require.Equal(t, tc.respBody, string(body))
+			}
+		})
+	}
+}
+
+type wrapValidator struct{}
+
+func (*wrapValidator) Validate(out any) error {
+	// The name is bound from the body, so the struct must be validated once all sources were bound
+	if reflect.ValueOf(out).Elem().FieldByName("Name").String() == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
 // go test -run Test_Bind_RepeatParserWithSameStruct -v
 func Test_Bind_RepeatParserWithSameStruct(t *testing.T) {
 	t.Parallel()
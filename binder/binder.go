@@ -21,4 +21,6 @@ var (
 	XMLBinder        = &xmlBinding{}
 	JSONBinder       = &jsonBinding{}
 	CBORBinder       = &cborBinding{}
+	ProtobufBinder   = &protobufBinding{}
+	MsgPackBinder    = &msgPackBinding{}
 )
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 
@@ -28,7 +29,16 @@ type ParserType struct {
 	Converter  func(string) reflect.Value
 }
 
+// delimitersKey is the key of the slice delimiters of a struct type for a tag
+type delimitersKey struct {
+	typ reflect.Type
+	tag string
+}
+
 var (
+	// delimitersCache holds the slice delimiters of the struct types
+	delimitersCache sync.Map
+
 	// decoderPoolMap helps to improve binders
 	decoderPoolMap = map[string]*sync.Pool{}
 	// tags is used to classify parser's pool
@@ -96,9 +106,197 @@ func parseToStruct(aliasTag string, out any, data map[string][]string) error {
 	schemaDecoder.SetAliasTag(aliasTag)
 
 	if err := schemaDecoder.Decode(out, data); err != nil {
+		var multiErr schema.MultiError
+		if errors.As(err, &multiErr) {
+			return &decodeError{err: err, msg: describeErrors(multiErr, data)}
+		}
 		return fmt.Errorf("bind: %w", err)
 	}
 
+	if val := reflect.ValueOf(out); val.Kind() == reflect.Ptr && val.Elem().Kind() == reflect.Struct {
+		parseMapFields(aliasTag, val.Elem(), "", data)
+	}
+
+	return nil
+}
+
+// parseMapFields sets the map[string]string and map[string][]string fields of the struct,
+// which gorilla/schema doesn't support, from the keys which start with the name of the
+// field and a dot, e.g. "filter.name" for a field tagged with `query:"filter"`
+func parseMapFields(aliasTag string, val reflect.Value, prefix string, data map[string][]string) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get(aliasTag), ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldVal := val.Field(i)
+		switch fieldVal.Kind() { //nolint:exhaustive // only structs and maps have to be handled
+		case reflect.Struct:
+			// The fields of embedded structs without a name are promoted
+			if field.Anonymous && name == "" {
+				parseMapFields(aliasTag, fieldVal, prefix, data)
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			parseMapFields(aliasTag, fieldVal, prefix+name+".", data)
+		case reflect.Map:
+			if name == "" {
+				name = field.Name
+			}
+			setMapField(fieldVal, prefix+name+".", data)
+		}
+	}
+}
+
+// setMapField sets the entries of the map field from the keys with the prefix. Keys of other
+// types than strings and values of other types than strings or string slices are skipped.
+func setMapField(field reflect.Value, prefix string, data map[string][]string) {
+	typ := field.Type()
+	elem := typ.Elem()
+	isSlice := elem.Kind() == reflect.Slice && elem.Elem().Kind() == reflect.String
+	if typ.Key().Kind() != reflect.String || (elem.Kind() != reflect.String && !isSlice) {
+		return
+	}
+
+	for k, values := range data {
+		if len(k) <= len(prefix) || !utils.EqualFold(k[:len(prefix)], prefix) {
+			continue
+		}
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(typ))
+		}
+
+		key := reflect.ValueOf(k[len(prefix):]).Convert(typ.Key())
+		if !isSlice {
+			field.SetMapIndex(key, reflect.ValueOf(values[len(values)-1]).Convert(elem))
+			continue
+		}
+		slice := reflect.MakeSlice(elem, len(values), len(values))
+		for i, v := range values {
+			slice.Index(i).Set(reflect.ValueOf(v).Convert(elem.Elem()))
+		}
+		field.SetMapIndex(key, slice)
+	}
+}
+
+// sliceDelimiters returns the delimiters of the values of the slice fields of the struct by
+// their lowercase name, which are set with the "split" option of their tag, e.g. `query:"ids,split:|"`
+func sliceDelimiters(aliasTag string, out any) map[string]string {
+	typ := reflect.TypeOf(out)
+	if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	typ = typ.Elem()
+
+	cacheKey := delimitersKey{typ: typ, tag: aliasTag}
+	if delimiters, ok := delimitersCache.Load(cacheKey); ok {
+		return delimiters.(map[string]string) //nolint:forcetypeassert,errcheck // We store nothing else in the cache
+	}
+
+	var delimiters map[string]string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Type.Kind() != reflect.Slice {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get(aliasTag), ",")
+		for _, option := range strings.Split(options, ",") {
+			if delimiter, found := strings.CutPrefix(option, "split:"); found && delimiter != "" {
+				if name == "" {
+					name = field.Name
+				}
+				if delimiters == nil {
+					delimiters = make(map[string]string)
+				}
+				delimiters[utils.ToLower(name)] = delimiter
+			}
+		}
+	}
+	delimitersCache.Store(cacheKey, delimiters)
+
+	return delimiters
+}
+
+// sliceDelimiter returns the delimiter of the values of the slice field with the key,
+// which defaults to ","
+func sliceDelimiter(delimiters map[string]string, key string) string {
+	if len(delimiters) > 0 {
+		if delimiter, ok := delimiters[utils.ToLower(key)]; ok {
+			return delimiter
+		}
+	}
+	return ","
+}
+
+// decodeError is returned when values can't be decoded into the fields of the struct.
+// It unwraps to the schema.MultiError of the decoder.
+type decodeError struct {
+	err error
+	msg string
+}
+
+func (e *decodeError) Error() string {
+	return "bind: " + e.msg
+}
+
+func (e *decodeError) Unwrap() error {
+	return e.err
+}
+
+// describeErrors describes the invalid fields sorted by their keys. Values which can't be
+// converted are described with the type of their field, e.g. `id: "abc" is not a valid int`.
+func describeErrors(multiErr schema.MultiError, data map[string][]string) string {
+	keys := make([]string, 0, len(multiErr))
+	for key := range multiErr {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	msgs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		err := multiErr[key]
+		var convErr schema.ConversionError
+		if !errors.As(err, &convErr) || convErr.Type == nil {
+			msgs = append(msgs, err.Error())
+			continue
+		}
+
+		field := convErr.Key
+		values := lookupValues(data, convErr.Key)
+		value := ""
+		switch {
+		case convErr.Index >= 0:
+			field = fmt.Sprintf("%s[%d]", convErr.Key, convErr.Index)
+			if convErr.Index < len(values) {
+				value = values[convErr.Index]
+			}
+		case len(values) > 0:
+			value = values[len(values)-1]
+		}
+		msgs = append(msgs, fmt.Sprintf("%s: %q is not a valid %s", field, value, convErr.Type))
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// lookupValues returns the values of the key, which the decoder matches case-insensitively
+func lookupValues(data map[string][]string, key string) []string {
+	if values, ok := data[key]; ok {
+		return values
+	}
+	for k, values := range data {
+		if utils.EqualFold(k, key) {
+			return values
+		}
+	}
 	return nil
 }
 
@@ -172,7 +370,9 @@ func parseParamSquareBrackets(k string) (string, error) {
 	return bb.String(), nil
 }
 
-func equalFieldType(out any, kind reflect.Kind, key string) bool {
+// equalFieldType reports whether the struct has a field of the kind whose name, or the name
+// in its tag of the binder, is the key
+func equalFieldType(out any, kind reflect.Kind, key, aliasTag string) bool {
 	// Get type of interface
 	outTyp := reflect.TypeOf(out).Elem()
 	key = utils.ToLower(key)
@@ -223,7 +423,7 @@ func equalFieldType(out any, kind reflect.Kind, key string) bool {
 			continue
 		}
 		// Get tag from field if exist
-		inputFieldName := typeField.Tag.Get(QueryBinder.Name())
+		inputFieldName := typeField.Tag.Get(aliasTag)
 		if inputFieldName == "" {
 			inputFieldName = typeField.Name
 		} else {
@@ -10,25 +10,31 @@ import (
 
 func Test_EqualFieldType(t *testing.T) {
 	var out int
-	require.False(t, equalFieldType(&out, reflect.Int, "key"))
+	require.False(t, equalFieldType(&out, reflect.Int, "key", QueryBinder.Name()))
 
 	var dummy struct{ f string }
-	require.False(t, equalFieldType(&dummy, reflect.String, "key"))
+	require.False(t, equalFieldType(&dummy, reflect.String, "key", QueryBinder.Name()))
 
 	var dummy2 struct{ f string }
-	require.False(t, equalFieldType(&dummy2, reflect.String, "f"))
+	require.False(t, equalFieldType(&dummy2, reflect.String, "f", QueryBinder.Name()))
 
 	var user struct {
 		Name    string
 		Address string `query:"address"`
 		Age     int    `query:"AGE"`
 	}
-	require.True(t, equalFieldType(&user, reflect.String, "name"))
-	require.True(t, equalFieldType(&user, reflect.String, "Name"))
-	require.True(t, equalFieldType(&user, reflect.String, "address"))
-	require.True(t, equalFieldType(&user, reflect.String, "Address"))
-	require.True(t, equalFieldType(&user, reflect.Int, "AGE"))
-	require.True(t, equalFieldType(&user, reflect.Int, "age"))
+	require.True(t, equalFieldType(&user, reflect.String, "name", QueryBinder.Name()))
+	require.True(t, equalFieldType(&user, reflect.String, "Name", QueryBinder.Name()))
+	require.True(t, equalFieldType(&user, reflect.String, "address", QueryBinder.Name()))
+	require.True(t, equalFieldType(&user, reflect.String, "Address", QueryBinder.Name()))
+	require.True(t, equalFieldType(&user, reflect.Int, "AGE", QueryBinder.Name()))
+	require.True(t, equalFieldType(&user, reflect.Int, "age", QueryBinder.Name()))
+
+	var headers struct {
+		Flags []string `header:"X-Feature-Flag"`
+	}
+	require.True(t, equalFieldType(&headers, reflect.Slice, "x-feature-flag", HeaderBinder.Name()))
+	require.False(t, equalFieldType(&headers, reflect.Slice, "x-feature-flag", QueryBinder.Name()))
 }
 
 func Test_ParseParamSquareBrackets(t *testing.T) {
@@ -0,0 +1,14 @@
+package binder
+
+// msgPackBinding is the MessagePack binder for msgpack request body.
+type msgPackBinding struct{}
+
+// Name returns the binding name.
+func (*msgPackBinding) Name() string {
+	return "msgpack"
+}
+
+// Bind parses the request body as MessagePack and returns the result.
+func (*msgPackBinding) Bind(body []byte, msgPackDecoder func(data []byte, v any) error, out any) error {
+	return msgPackDecoder(body, out)
+}
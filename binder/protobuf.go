@@ -0,0 +1,14 @@
+package binder
+
+// protobufBinding is the Protocol Buffers binder for protobuf request body.
+type protobufBinding struct{}
+
+// Name returns the binding name.
+func (*protobufBinding) Name() string {
+	return "protobuf"
+}
+
+// Bind parses the request body as protobuf message and returns the result.
+func (*protobufBinding) Bind(body []byte, protobufDecoder func(data []byte, v any) error, out any) error {
+	return protobufDecoder(body, out)
+}
@@ -19,6 +19,7 @@ func (*queryBinding) Name() string {
 // Bind parses the request query and returns the result.
 func (b *queryBinding) Bind(reqCtx *fasthttp.RequestCtx, out any) error {
 	data := make(map[string][]string)
+	delimiters := sliceDelimiters(b.Name(), out)
 	var err error
 
 	reqCtx.QueryArgs().VisitAll(func(key, val []byte) {
@@ -33,8 +34,9 @@ func (b *queryBinding) Bind(reqCtx *fasthttp.RequestCtx, out any) error {
 			k, err = parseParamSquareBrackets(k)
 		}
 
-		if strings.Contains(v, ",") && equalFieldType(out, reflect.Slice, k) {
-			values := strings.Split(v, ",")
+		delimiter := sliceDelimiter(delimiters, k)
+		if strings.Contains(v, delimiter) && equalFieldType(out, reflect.Slice, k, b.Name()) {
+			values := strings.Split(v, delimiter)
 			for i := 0; i < len(values); i++ {
 				data[k] = append(data[k], values[i])
 			}
@@ -1,8 +1,13 @@
 package binder
 
 import (
-	"encoding/xml"
+	"bytes"
 	"fmt"
+	"io"
+	"mime"
+
+	"github.com/gofiber/utils/v2"
+	"golang.org/x/net/html/charset"
 )
 
 // xmlBinding is the XML binder for XML request body.
@@ -13,11 +18,73 @@ func (*xmlBinding) Name() string {
 	return "xml"
 }
 
-// Bind parses the request body as XML and returns the result.
-func (*xmlBinding) Bind(body []byte, out any) error {
-	if err := xml.Unmarshal(body, out); err != nil {
+// Bind parses the request body as XML and returns the result. If the charset of the
+// content type isn't UTF-8, the body is converted to UTF-8 before it is decoded.
+func (*xmlBinding) Bind(body []byte, contentType string, xmlDecoder utils.XMLUnmarshal, out any) error {
+	body, err := xmlToUTF8(body, contentType)
+	if err != nil {
+		return err
+	}
+
+	if err := xmlDecoder(body, out); err != nil {
 		return fmt.Errorf("failed to unmarshal xml: %w", err)
 	}
 
 	return nil
 }
+
+// xmlToUTF8 converts the body to UTF-8 if the content type has another charset.
+// The charset of the content type takes precedence over the XML declaration (RFC 7303),
+// so the encoding of the declaration is removed from the converted body.
+func xmlToUTF8(body []byte, contentType string) ([]byte, error) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return body, nil //nolint:nilerr // Without a valid charset parameter, the XML declaration applies
+	}
+	label := params["charset"]
+	if label == "" || utils.EqualFold(label, "utf-8") || utils.EqualFold(label, "utf8") {
+		return body, nil
+	}
+
+	r, err := charset.NewReaderLabel(label, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert xml from charset %q: %w", label, err)
+	}
+	converted, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert xml from charset %q: %w", label, err)
+	}
+
+	return removeXMLEncoding(converted), nil
+}
+
+// removeXMLEncoding removes the encoding attribute of the XML declaration, e.g.
+// <?xml version="1.0" encoding="ISO-8859-1"?> becomes <?xml version="1.0"?>
+func removeXMLEncoding(body []byte) []byte {
+	if !bytes.HasPrefix(body, []byte("<?xml")) {
+		return body
+	}
+	end := bytes.Index(body, []byte("?>"))
+	if end == -1 {
+		return body
+	}
+	start := bytes.Index(body[:end], []byte(" encoding"))
+	if start == -1 {
+		return body
+	}
+	eq := bytes.IndexByte(body[start:end], '=')
+	if eq == -1 {
+		return body
+	}
+	value := bytes.TrimLeft(body[start+eq+1:end], " \t\r\n")
+	if len(value) == 0 || (value[0] != '"' && value[0] != '\'') {
+		return body
+	}
+	closing := bytes.IndexByte(value[1:], value[0])
+	if closing == -1 {
+		return body
+	}
+	valueEnd := end - len(value) + closing + 2
+
+	return append(body[:start], body[valueEnd:]...)
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/fxamacker/cbor/v2"
+	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/log"
 
 	"github.com/gofiber/utils/v2"
@@ -115,6 +116,14 @@ func (c *Client) SetJSONUnmarshal(f utils.JSONUnmarshal) *Client {
 	return c
 }
 
+// SetJSONCodec sets the JSON marshal and unmarshal functions to the methods of the codec,
+// e.g. to use the JSON library of an app with app.Config().JSONCodec.
+func (c *Client) SetJSONCodec(codec fiber.JSONCodec) *Client {
+	c.jsonMarshal = codec.Marshal
+	c.jsonUnmarshal = codec.Unmarshal
+	return c
+}
+
 // XMLMarshal returns the XML marshal function used by the client.
 func (c *Client) XMLMarshal() utils.XMLMarshal {
 	return c.xmlMarshal
@@ -202,6 +202,19 @@ func Test_Client_Marshal(t *testing.T) {
 		require.Equal(t, errors.New("empty json"), err)
 	})
 
+	t.Run("set json codec", func(t *testing.T) {
+		t.Parallel()
+		client := New().SetJSONCodec(fiber.StdJSONCodec{})
+
+		val, err := client.JSONMarshal()(fiber.Map{"name": "john"})
+		require.NoError(t, err)
+		require.Equal(t, `{"name":"john"}`, string(val))
+
+		var out fiber.Map
+		require.NoError(t, client.JSONUnmarshal()(val, &out))
+		require.Equal(t, fiber.Map{"name": "john"}, out)
+	})
+
 	t.Run("set xml marshal", func(t *testing.T) {
 		t.Parallel()
 		client := New().
@@ -21,9 +21,16 @@ const (
 	MIMETextPlain       = "text/plain"
 	MIMETextJavaScript  = "text/javascript"
 	MIMETextCSS         = "text/css"
+	MIMETextCSV         = "text/csv"
 	MIMEApplicationXML  = "application/xml"
 	MIMEApplicationJSON = "application/json"
 	MIMEApplicationCBOR = "application/cbor"
+	// MIMEApplicationProtobuf is the MIME type of Protocol Buffers messages
+	MIMEApplicationProtobuf = "application/x-protobuf"
+	// MIMEApplicationMsgPack is the MIME type of MessagePack
+	MIMEApplicationMsgPack = "application/msgpack"
+	// MIMEApplicationNDJSON is the MIME type of newline-delimited JSON, also known as JSON Lines
+	MIMEApplicationNDJSON = "application/x-ndjson"
 	// Deprecated: use MIMETextJavaScript instead
 	MIMEApplicationJavaScript = "application/javascript"
 	MIMEApplicationForm       = "application/x-www-form-urlencoded"
@@ -35,6 +42,7 @@ const (
 	MIMETextPlainCharsetUTF8       = "text/plain; charset=utf-8"
 	MIMETextJavaScriptCharsetUTF8  = "text/javascript; charset=utf-8"
 	MIMETextCSSCharsetUTF8         = "text/css; charset=utf-8"
+	MIMETextCSVCharsetUTF8         = "text/csv; charset=utf-8"
 	MIMEApplicationXMLCharsetUTF8  = "application/xml; charset=utf-8"
 	MIMEApplicationJSONCharsetUTF8 = "application/json; charset=utf-8"
 	// Deprecated: use MIMETextJavaScriptCharsetUTF8 instead
@@ -9,13 +9,16 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/csv"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"iter"
 	"mime/multipart"
 	"net"
 	"net/http"
+	"net/url"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -23,6 +26,8 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/gofiber/fiber/v3/httpcache"
+	"github.com/gofiber/fiber/v3/log"
 	"github.com/gofiber/utils/v2"
 	"github.com/valyala/bytebufferpool"
 	"github.com/valyala/fasthttp"
@@ -49,26 +54,30 @@ const userContextKey contextKey = 0 // __local_user_context__
 //
 //go:generate ifacemaker --file ctx.go --struct DefaultCtx --iface Ctx --pkg fiber --output ctx_interface_gen.go --not-exported true --iface-comment "Ctx represents the Context which hold the HTTP request and response.\nIt has methods for the request query string, parameters, body, HTTP headers and so on."
 type DefaultCtx struct {
-	app                 *App                 // Reference to *App
-	route               *Route               // Reference to *Route
-	fasthttp            *fasthttp.RequestCtx // Reference to *fasthttp.RequestCtx
-	bind                *Bind                // Default bind reference
-	redirect            *Redirect            // Default redirect reference
-	values              [maxParams]string    // Route parameter values
-	viewBindMap         sync.Map             // Default view map to bind template engine
-	method              string               // HTTP method
-	baseURI             string               // HTTP base uri
-	path                string               // HTTP path with the modifications by the configuration -> string copy from pathBuffer
-	detectionPath       string               // Route detection path                                  -> string copy from detectionPathBuffer
-	treePath            string               // Path for the search in the tree
-	pathOriginal        string               // Original HTTP path
-	pathBuffer          []byte               // HTTP path buffer
-	detectionPathBuffer []byte               // HTTP detectionPath buffer
-	flashMessages       redirectionMsgs      // Flash messages
-	indexRoute          int                  // Index of the current route
-	indexHandler        int                  // Index of the current handler
-	methodINT           int                  // HTTP method INT equivalent
-	matched             bool                 // Non use route matched
+	app                 *App                   // Reference to *App
+	route               *Route                 // Reference to *Route
+	fasthttp            *fasthttp.RequestCtx   // Reference to *fasthttp.RequestCtx
+	bind                *Bind                  // Default bind reference
+	redirect            *Redirect              // Default redirect reference
+	cancel              context.CancelFunc     // Cancels the context of the request
+	values              [maxParams]string      // Route parameter values
+	viewBindMap         sync.Map               // Default view map to bind template engine
+	method              string                 // HTTP method
+	baseURI             string                 // HTTP base uri
+	path                string                 // HTTP path with the modifications by the configuration -> string copy from pathBuffer
+	detectionPath       string                 // Route detection path                                  -> string copy from detectionPathBuffer
+	treePath            string                 // Path for the search in the tree
+	treeStack           *[]map[string][]*Route // Route tree of the request
+	pathOriginal        string                 // Original HTTP path
+	pathBuffer          []byte                 // HTTP path buffer
+	detectionPathBuffer []byte                 // HTTP detectionPath buffer
+	flashMessages       redirectionMsgs        // Flash messages
+	deferred            []func()               // Functions which run after the response
+	trailerFuncs        []trailerFunc          // Trailers of SetTrailerFunc, until the body is streamed
+	indexRoute          int                    // Index of the current route
+	indexHandler        int                    // Index of the current handler
+	methodINT           int                    // HTTP method INT equivalent
+	matched             bool                   // Non use route matched
 }
 
 // SendFile defines configuration options when to transfer file with SendFile.
@@ -87,11 +96,21 @@ type SendFile struct {
 	// Optional. Default: false
 	Compress bool `json:"compress"`
 
-	// When set to true, enables byte range requests.
+	// When set to true, enables byte range requests. Requests for multiple ranges are
+	// answered with multipart/byteranges responses and the ranges of requests with an
+	// If-Range header are only sent while the file wasn't modified.
 	//
 	// Optional. Default: false
 	ByteRange bool `json:"byte_range"`
 
+	// When set to true, sets the ETag header, which is derived from the modification
+	// time and size of the file, and answers requests whose If-None-Match header matches
+	// it with 304 Not Modified. The Last-Modified and If-Modified-Since headers are
+	// always handled.
+	//
+	// Optional. Default: false
+	ETag bool `json:"etag"`
+
 	// When set to true, enables direct download.
 	//
 	// Optional. Default: false
@@ -138,6 +157,10 @@ func (sf *sendFileStore) compareConfig(cfg SendFile) bool {
 		return false
 	}
 
+	if sf.config.ETag != cfg.ETag {
+		return false
+	}
+
 	if sf.config.CacheDuration != cfg.CacheDuration {
 		return false
 	}
@@ -190,11 +213,68 @@ type Cookie struct {
 }
 
 // Views is the interface that wraps the Render function.
+// When used with RenderStream, the out writer is a *bufio.Writer that
+// writes directly to the response body and can be flushed by the engine.
 type Views interface {
 	Load() error
 	Render(out io.Writer, name string, binding any, layout ...string) error
 }
 
+// NegotiateOffer defines the representations offered by c.Negotiate.
+// Representations which are not set are not offered to the client.
+type NegotiateOffer struct {
+	// CSV encodes the data for text/csv responses.
+	CSV func(data any) ([]byte, error)
+
+	// HTML is the name of the template which is rendered for text/html responses.
+	// If the data is not a Map, it is passed to the template as "Data".
+	HTML string
+
+	// Layouts are the layouts which are used to render the HTML template.
+	Layouts []string
+
+	// JSON enables application/json responses.
+	JSON bool
+
+	// XML enables application/xml responses.
+	XML bool
+
+	// CBOR enables application/cbor responses.
+	CBOR bool
+
+	// MsgPack enables application/msgpack responses.
+	MsgPack bool
+
+	// Encoders are the MIME types of encoders registered with App.RegisterBodyEncoder,
+	// which are offered after the other representations.
+	Encoders []string
+}
+
+// CSVConfig defines the options of the CSV responses of c.CSV.
+type CSVConfig struct {
+	// Filename sends the CSV as attachment with the filename, which browsers download.
+	//
+	// Optional. Default: ""
+	Filename string
+
+	// Comma is the field delimiter, e.g. ';' for spreadsheet applications with a
+	// decimal comma.
+	//
+	// Optional. Default: ','
+	Comma rune
+
+	// BOM writes the UTF-8 byte order mark before the header, so that spreadsheet
+	// applications detect the encoding of non-ASCII values.
+	//
+	// Optional. Default: false
+	BOM bool
+
+	// UseCRLF terminates the records with \r\n instead of \n, as specified by RFC 4180.
+	//
+	// Optional. Default: false
+	UseCRLF bool
+}
+
 // ResFmt associates a Content Type to a fiber.Handler for c.Format
 type ResFmt struct {
 	Handler   func(Ctx) error
@@ -217,8 +297,29 @@ func (c *DefaultCtx) AcceptsEncodings(offers ...string) string {
 }
 
 // AcceptsLanguages checks if the specified language is acceptable.
+// Languages are compared case-insensitively, and a language matches more and less
+// specific languages of the Accept-Language header, e.g. "en" matches "en-GB".
 func (c *DefaultCtx) AcceptsLanguages(offers ...string) string {
-	return getOffer(c.fasthttp.Request.Header.Peek(HeaderAcceptLanguage), acceptsOffer, offers...)
+	return getOffer(c.fasthttp.Request.Header.Peek(HeaderAcceptLanguage), acceptsLanguageOffer, offers...)
+}
+
+// Language returns the supported language which the client prefers according to the
+// quality values of the Accept-Language header. Languages which aren't supported fall
+// back to less specific ones with the same quality, e.g. "en-GB" to "en", unless these
+// are listed in the header themselves. Exact matches take precedence over fallbacks.
+// If the header is missing or no supported language is acceptable, the first supported
+// language is returned, so that it can be used as default.
+//
+//	lang := c.Language("en", "de", "fr-CH")
+func (c *DefaultCtx) Language(supported ...string) string {
+	if len(supported) == 0 {
+		return ""
+	}
+	header := languageFallbacks(c.fasthttp.Request.Header.Peek(HeaderAcceptLanguage))
+	if lang := getOffer(header, acceptsLanguageTag, supported...); lang != "" {
+		return lang
+	}
+	return supported[0]
 }
 
 // App returns the *App reference to the instance of the Fiber application
@@ -253,7 +354,7 @@ func (c *DefaultCtx) Attachment(filename ...string) {
 		fname := filepath.Base(filename[0])
 		c.Type(filepath.Ext(fname))
 
-		c.setCanonical(HeaderContentDisposition, `attachment; filename="`+c.app.quoteString(fname)+`"`)
+		c.setCanonical(HeaderContentDisposition, c.app.attachmentDisposition(fname))
 		return
 	}
 	c.setCanonical(HeaderContentDisposition, "attachment")
@@ -391,10 +492,16 @@ func (c *DefaultCtx) RequestCtx() *fasthttp.RequestCtx {
 
 // Context returns a context implementation that was set by
 // user earlier or returns a non-nil, empty context,if it was not set earlier.
+// With Config.EnableContextCancellation, the returned context is canceled
+// when the request is done or the app shuts down, but not when the client disconnects.
 func (c *DefaultCtx) Context() context.Context {
 	ctx, ok := c.fasthttp.UserValue(userContextKey).(context.Context)
 	if !ok {
-		ctx = context.Background()
+		if c.app.config.EnableContextCancellation {
+			ctx, c.cancel = context.WithCancel(c.app.requests.Load().ctx)
+		} else {
+			ctx = context.Background()
+		}
 		c.SetContext(ctx)
 	}
 
@@ -462,7 +569,7 @@ func (c *DefaultCtx) Download(file string, filename ...string) error {
 	} else {
 		fname = filepath.Base(file)
 	}
-	c.setCanonical(HeaderContentDisposition, `attachment; filename="`+c.app.quoteString(fname)+`"`)
+	c.setCanonical(HeaderContentDisposition, c.app.attachmentDisposition(fname))
 	return c.SendFile(file)
 }
 
@@ -565,6 +672,74 @@ func (c *DefaultCtx) AutoFormat(body any) error {
 	return c.SendString(b)
 }
 
+// Negotiate performs content-negotiation on the Accept HTTP header and sends
+// the data in the representation preferred by the client.
+// The HTML representation renders the configured template, the other
+// representations serialize the data with the encoders of the app.
+// The offers are tried in the order HTML, JSON, XML, CBOR, MsgPack, CSV and the registered
+// encoders, so the first offered representation is used if the Accept header is missing.
+// If no offered representation is acceptable, StatusNotAcceptable is sent.
+func (c *DefaultCtx) Negotiate(data any, offer NegotiateOffer) error {
+	// Using an int literal as the slice capacity allows for the slice to be
+	// allocated on the stack.
+	handlers := make([]ResFmt, 0, 8)
+
+	if offer.HTML != "" {
+		handlers = append(handlers, ResFmt{MediaType: MIMETextHTML, Handler: func(c Ctx) error {
+			bind, ok := data.(Map)
+			if !ok {
+				bind = Map{"Data": data}
+			}
+			return c.Render(offer.HTML, bind, offer.Layouts...)
+		}})
+	}
+	if offer.JSON {
+		handlers = append(handlers, ResFmt{MediaType: MIMEApplicationJSON, Handler: func(c Ctx) error {
+			return c.JSON(data)
+		}})
+	}
+	if offer.XML {
+		handlers = append(handlers, ResFmt{MediaType: MIMEApplicationXML, Handler: func(c Ctx) error {
+			return c.XML(data)
+		}})
+	}
+	if offer.CBOR {
+		handlers = append(handlers, ResFmt{MediaType: MIMEApplicationCBOR, Handler: func(c Ctx) error {
+			return c.CBOR(data)
+		}})
+	}
+	if offer.MsgPack {
+		handlers = append(handlers, ResFmt{MediaType: MIMEApplicationMsgPack, Handler: func(c Ctx) error {
+			return c.MsgPack(data)
+		}})
+	}
+	if offer.CSV != nil {
+		handlers = append(handlers, ResFmt{MediaType: MIMETextCSV, Handler: func(c Ctx) error {
+			raw, err := offer.CSV(data)
+			if err != nil {
+				return fmt.Errorf("failed to encode csv: %w", err)
+			}
+			c.Response().Header.SetContentType(MIMETextCSVCharsetUTF8)
+			return c.Send(raw)
+		}})
+	}
+	for _, mimeType := range offer.Encoders {
+		encoder, ok := c.app.bodyEncoders[utils.ToLower(mimeType)]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrBodyEncoderNotFound, mimeType)
+		}
+		handlers = append(handlers, ResFmt{MediaType: mimeType, Handler: func(c Ctx) error {
+			raw, err := encoder(data)
+			if err != nil {
+				return fmt.Errorf("failed to encode %s: %w", mimeType, err)
+			}
+			return c.Send(raw)
+		}})
+	}
+
+	return c.Format(handlers...)
+}
+
 // FormFile returns the first file by key from a MultipartForm.
 func (c *DefaultCtx) FormFile(key string) (*multipart.FileHeader, error) {
 	return c.fasthttp.FormFile(key)
@@ -610,7 +785,7 @@ func (c *DefaultCtx) Fresh() bool {
 		if etag == "" {
 			return false
 		}
-		if c.app.isEtagStale(etag, c.app.getBytes(noneMatch)) {
+		if !httpcache.NoneMatch(noneMatch, etag) {
 			return false
 		}
 
@@ -679,6 +854,21 @@ func (c *DefaultCtx) GetReqHeaders() map[string][]string {
 	return headers
 }
 
+// GetReqTrailers returns the HTTP request trailers, which are declared in the Trailer header
+// and sent after the chunked body of the request.
+// Returned value is only valid within the handler. Do not store any references.
+// Make copies or use the Immutable setting instead.
+func (c *DefaultCtx) GetReqTrailers() map[string][]string {
+	trailers := make(map[string][]string)
+	c.Request().Header.VisitAllTrailer(func(k []byte) {
+		key := c.app.getString(k)
+		for _, v := range c.Request().Header.PeekAll(key) {
+			trailers[key] = append(trailers[key], c.app.getString(v))
+		}
+	})
+	return trailers
+}
+
 // Host contains the host derived from the X-Forwarded-Host or Host HTTP header.
 // Returned value is only valid within the handler. Do not store any references.
 // In a network context, `Host` refers to the combination of a hostname and potentially a port number used for connecting,
@@ -884,6 +1074,42 @@ func (c *DefaultCtx) JSON(data any, ctype ...string) error {
 	return nil
 }
 
+// Protobuf encodes the protobuf message with the ProtobufEncoder of the app.
+// If the ctype parameter is given, this method will set the
+// Content-Type header equal to ctype. If ctype is not given,
+// The Content-Type header will be set to application/x-protobuf.
+func (c *DefaultCtx) Protobuf(msg any, ctype ...string) error {
+	raw, err := c.app.config.ProtobufEncoder(msg)
+	if err != nil {
+		return err
+	}
+	c.fasthttp.Response.SetBodyRaw(raw)
+	if len(ctype) > 0 {
+		c.fasthttp.Response.Header.SetContentType(ctype[0])
+	} else {
+		c.fasthttp.Response.Header.SetContentType(MIMEApplicationProtobuf)
+	}
+	return nil
+}
+
+// MsgPack encodes the data as MessagePack with the MsgPackEncoder of the app.
+// If the ctype parameter is given, this method will set the
+// Content-Type header equal to ctype. If ctype is not given,
+// The Content-Type header will be set to application/msgpack.
+func (c *DefaultCtx) MsgPack(data any, ctype ...string) error {
+	raw, err := c.app.config.MsgPackEncoder(data)
+	if err != nil {
+		return err
+	}
+	c.fasthttp.Response.SetBodyRaw(raw)
+	if len(ctype) > 0 {
+		c.fasthttp.Response.Header.SetContentType(ctype[0])
+	} else {
+		c.fasthttp.Response.Header.SetContentType(MIMEApplicationMsgPack)
+	}
+	return nil
+}
+
 // CBOR converts any interface or string to CBOR encoded bytes.
 // If the ctype parameter is given, this method will set the
 // Content-Type header equal to ctype. If ctype is not given,
@@ -902,6 +1128,80 @@ func (c *DefaultCtx) CBOR(data any, ctype ...string) error {
 	return nil
 }
 
+// JSONStream converts any interface to JSON like JSON, but encodes it directly into the
+// response body stream with chunked transfer instead of an in-memory buffer, for large
+// responses. The data is encoded after the handler returned, so it must not reference
+// values which are only valid within the handler. Since the headers are sent before the
+// encoding starts, encoding errors can no longer change the response and are only logged.
+// If the ctype parameter is given, this method will set the Content-Type header equal
+// to ctype. If ctype is not given, the Content-Type header will be set to application/json.
+func (c *DefaultCtx) JSONStream(data any, ctype ...string) error {
+	if len(ctype) > 0 {
+		c.fasthttp.Response.Header.SetContentType(ctype[0])
+	} else {
+		c.fasthttp.Response.Header.SetContentType(MIMEApplicationJSON)
+	}
+
+	encode := c.app.config.JSONStreamEncoder
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		if err := encode(w, data); err != nil {
+			log.Errorf("failed to encode json stream: %v", err)
+		}
+	})
+}
+
+// CSV streams the header and the rows as CSV into the response body stream, so the rows are
+// never buffered in memory. Fields with delimiters, quotes or line breaks are quoted.
+// The rows are iterated after the handler returned, so the iterator must not use the Ctx.
+// Since the headers are sent before the rows are written, errors can no longer change the
+// response and are only logged. A nil header writes no header record.
+//
+//	return c.CSV([]string{"id", "name"}, func(yield func([]string) bool) {
+//		for _, user := range users {
+//			if !yield([]string{user.ID, user.Name}) {
+//				return
+//			}
+//		}
+//	}, fiber.CSVConfig{Filename: "users.csv"})
+func (c *DefaultCtx) CSV(header []string, rows iter.Seq[[]string], config ...CSVConfig) error {
+	var cfg CSVConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	c.fasthttp.Response.Header.SetContentType(MIMETextCSVCharsetUTF8)
+	if cfg.Filename != "" {
+		c.setCanonical(HeaderContentDisposition, c.app.attachmentDisposition(filepath.Base(cfg.Filename)))
+	}
+
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		if cfg.BOM {
+			_, _ = w.WriteString("\xEF\xBB\xBF") //nolint:errcheck // Write errors are reported by the csv writer
+		}
+		cw := csv.NewWriter(w)
+		if cfg.Comma != 0 {
+			cw.Comma = cfg.Comma
+		}
+		cw.UseCRLF = cfg.UseCRLF
+		if header != nil {
+			if err := cw.Write(header); err != nil {
+				log.Errorf("failed to write csv header: %v", err)
+				return
+			}
+		}
+		for row := range rows {
+			if err := cw.Write(row); err != nil {
+				log.Errorf("failed to write csv row: %v", err)
+				return
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			log.Errorf("failed to write csv: %v", err)
+		}
+	})
+}
+
 // JSONP sends a JSON response with JSONP support.
 // This method is identical to JSON, except that it opts-in to JSONP callback support.
 // By default, the callback name is simply callback.
@@ -992,6 +1292,45 @@ func Locals[V any](c Ctx, key any, value ...V) V {
 	return v
 }
 
+// LocalKey is a typed key of a local value. As every key is compared by its address,
+// keys of different packages never collide, even if they have the same name, and
+// the type of the value is checked by the compiler.
+//
+//	var userKey = fiber.NewLocalKey[*User]("user")
+//
+//	userKey.Set(c, user)
+//	user, ok := userKey.Get(c)
+type LocalKey[V any] struct {
+	name string
+}
+
+// NewLocalKey returns a new key of local values of type V. The name is only used for debugging.
+func NewLocalKey[V any](name string) *LocalKey[V] {
+	return &LocalKey[V]{name: name}
+}
+
+// Set stores the value under the key in the locals of the request.
+func (k *LocalKey[V]) Set(c Ctx, value V) {
+	c.Locals(k, value)
+}
+
+// Get returns the value stored under the key, and whether it was set.
+func (k *LocalKey[V]) Get(c Ctx) (V, bool) {
+	v, ok := c.Locals(k).(V)
+	return v, ok
+}
+
+// Value returns the value stored under the key, or the zero value of V if it wasn't set.
+func (k *LocalKey[V]) Value(c Ctx) V {
+	v, _ := k.Get(c)
+	return v
+}
+
+// String returns the name of the key.
+func (k *LocalKey[V]) String() string {
+	return k.name
+}
+
 // Location sets the response Location HTTP header to the specified path parameter.
 func (c *DefaultCtx) Location(path string) {
 	c.setCanonical(HeaderLocation, path)
@@ -1363,8 +1702,35 @@ func (c *DefaultCtx) getLocationFromRoute(route Route, params Map) (string, erro
 }
 
 // GetRouteURL generates URLs to named routes, with parameters. URLs are relative, for example: "/user/1831"
-func (c *DefaultCtx) GetRouteURL(routeName string, params Map) (string, error) {
-	return c.getLocationFromRoute(c.App().GetRoute(routeName), params)
+// The optional queries are appended as query string, sorted by key: "/user/1831?page=2"
+func (c *DefaultCtx) GetRouteURL(routeName string, params Map, queries ...Map) (string, error) {
+	route := c.App().GetRoute(routeName)
+	if route.Path == "" {
+		return "", fmt.Errorf("%w: %q", ErrRouteNotFound, routeName)
+	}
+	location, err := c.getLocationFromRoute(route, params)
+	if err != nil {
+		return "", err
+	}
+	if len(queries) == 0 {
+		return location, nil
+	}
+
+	values := url.Values{}
+	for _, query := range queries {
+		for key, val := range query {
+			switch v := val.(type) {
+			case []string:
+				values[key] = append(values[key], v...)
+			default:
+				values.Add(key, utils.ToString(val))
+			}
+		}
+	}
+	if len(values) == 0 {
+		return location, nil
+	}
+	return location + "?" + values.Encode(), nil
 }
 
 // Render a template with data and sends a text/html response.
@@ -1382,7 +1748,89 @@ func (c *DefaultCtx) Render(name string, bind Map, layouts ...string) error {
 	// Pass-locals-to-views, bind, appListKeys
 	c.renderExtensions(bind)
 
-	var rendered bool
+	if err := c.app.hooks.executeOnPreRenderHooks(c, name, bind); err != nil {
+		return err
+	}
+
+	views, layouts := c.viewsEngine(layouts)
+	if views != nil {
+		// Render template from Views
+		if err := views.Render(buf, name, bind, layouts...); err != nil {
+			return fmt.Errorf("failed to render: %w", err)
+		}
+	} else {
+		// Render raw template using 'name' as filepath if no engine is set
+		tmpl, err := parseTemplateFile(name)
+		if err != nil {
+			return err
+		}
+		// Render template
+		if err := tmpl.Execute(buf, bind); err != nil {
+			return fmt.Errorf("failed to execute: %w", err)
+		}
+	}
+
+	// Set Content-Type to text/html
+	c.fasthttp.Response.Header.SetContentType(MIMETextHTMLCharsetUTF8)
+	// Set rendered template to body
+	c.fasthttp.Response.SetBody(buf.Bytes())
+
+	return c.app.hooks.executeOnPostRenderHooks(c, name)
+}
+
+// RenderStream renders a template with data like Render, but writes the output
+// directly into the response body stream instead of an in-memory buffer.
+// The writer passed to the Views engine is a *bufio.Writer, so engines can call
+// Flush to send already rendered parts (e.g. the page head) to the client early.
+// Since the headers are sent before the rendering starts, errors returned by the
+// Views engine can no longer change the response and are only logged.
+func (c *DefaultCtx) RenderStream(name string, bind Map, layouts ...string) error {
+	// Initialize empty bind map if bind is nil
+	if bind == nil {
+		bind = make(Map)
+	}
+
+	// Pass-locals-to-views, bind, appListKeys
+	c.renderExtensions(bind)
+
+	if err := c.app.hooks.executeOnPreRenderHooks(c, name, bind); err != nil {
+		return err
+	}
+
+	var render func(w io.Writer) error
+	views, layouts := c.viewsEngine(layouts)
+	if views != nil {
+		render = func(w io.Writer) error {
+			return views.Render(w, name, bind, layouts...)
+		}
+	} else {
+		// Parse the raw template upfront, so a missing or invalid file is still reported to the handler
+		tmpl, err := parseTemplateFile(name)
+		if err != nil {
+			return err
+		}
+		render = func(w io.Writer) error {
+			return tmpl.Execute(w, bind)
+		}
+	}
+
+	// Set Content-Type to text/html
+	c.fasthttp.Response.Header.SetContentType(MIMETextHTMLCharsetUTF8)
+
+	if err := c.SendStreamWriter(func(w *bufio.Writer) {
+		if err := render(w); err != nil {
+			log.Errorf("failed to render stream: %v", err)
+		}
+	}); err != nil {
+		return err
+	}
+
+	return c.app.hooks.executeOnPostRenderHooks(c, name)
+}
+
+// viewsEngine returns the Views engine of the (mounted) app which is responsible
+// for the current request, together with the layouts that should be applied.
+func (c *DefaultCtx) viewsEngine(layouts []string) (Views, []string) {
 	for i := len(c.app.mountFields.appListKeys) - 1; i >= 0; i-- {
 		prefix := c.app.mountFields.appListKeys[i]
 		app := c.app.mountFields.appList[prefix]
@@ -1393,42 +1841,31 @@ func (c *DefaultCtx) Render(name string, bind Map, layouts ...string) error {
 				}
 			}
 
-			// Render template from Views
 			if app.config.Views != nil {
-				if err := app.config.Views.Render(buf, name, bind, layouts...); err != nil {
-					return fmt.Errorf("failed to render: %w", err)
-				}
-
-				rendered = true
-				break
+				return app.config.Views, layouts
 			}
 		}
 	}
 
-	if !rendered {
-		// Render raw template using 'name' as filepath if no engine is set
-		var tmpl *template.Template
-		if _, err := readContent(buf, name); err != nil {
-			return err
-		}
-		// Parse template
-		tmpl, err := template.New("").Parse(c.app.getString(buf.Bytes()))
-		if err != nil {
-			return fmt.Errorf("failed to parse: %w", err)
-		}
-		buf.Reset()
-		// Render template
-		if err := tmpl.Execute(buf, bind); err != nil {
-			return fmt.Errorf("failed to execute: %w", err)
-		}
-	}
+	return nil, layouts
+}
 
-	// Set Content-Type to text/html
-	c.fasthttp.Response.Header.SetContentType(MIMETextHTMLCharsetUTF8)
-	// Set rendered template to body
-	c.fasthttp.Response.SetBody(buf.Bytes())
+// parseTemplateFile reads and parses the template file at the given path,
+// which is used by the render methods when no Views engine is set.
+func parseTemplateFile(name string) (*template.Template, error) {
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
 
-	return nil
+	if _, err := readContent(buf, name); err != nil {
+		return nil, err
+	}
+	// Parse template
+	tmpl, err := template.New("").Parse(buf.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse: %w", err)
+	}
+
+	return tmpl, nil
 }
 
 func (c *DefaultCtx) renderExtensions(bind any) {
@@ -1456,6 +1893,21 @@ func (c *DefaultCtx) renderExtensions(bind any) {
 				}
 			})
 		}
+
+		// Bind view globals of the app
+		c.app.viewGlobals.Range(func(key, value any) bool {
+			keyValue, ok := key.(string)
+			if !ok {
+				return true
+			}
+			if _, ok := bindMap[keyValue]; !ok {
+				if fn, ok := value.(func(Ctx) any); ok {
+					value = fn(c)
+				}
+				bindMap[keyValue] = value
+			}
+			return true
+		})
 	}
 
 	if len(c.app.mountFields.appListKeys) == 0 {
@@ -1575,7 +2027,10 @@ func (c *DefaultCtx) SendFile(file string, config ...SendFile) error {
 
 		maxAge := cfg.MaxAge
 		if maxAge > 0 {
-			sf.cacheControlValue = "public, max-age=" + strconv.Itoa(maxAge)
+			cc := httpcache.NewCacheControl()
+			cc.Public = true
+			cc.MaxAge = maxAge
+			sf.cacheControlValue = cc.String()
 		}
 
 		// set vars
@@ -1625,8 +2080,21 @@ func (c *DefaultCtx) SendFile(file string, config ...SendFile) error {
 	// Save status code
 	status := c.fasthttp.Response.StatusCode()
 
+	// If-None-Match takes precedence over If-Modified-Since
+	if cfg.ETag && len(c.fasthttp.Request.Header.Peek(HeaderIfNoneMatch)) > 0 {
+		c.fasthttp.Request.Header.Del(HeaderIfModifiedSince)
+	}
+
 	// Serve file
-	fsHandler(c.fasthttp)
+	c.serveFile(fsHandler, cfg)
+
+	if cfg.ETag && (c.fasthttp.IsGet() || c.fasthttp.IsHead()) &&
+		httpcache.NoneMatch(c.Get(HeaderIfNoneMatch), c.app.getString(c.fasthttp.Response.Header.Peek(HeaderETag))) {
+		// Keep the headers, unlike fasthttp's NotModified, as 304 responses must contain the validators
+		c.fasthttp.Response.ResetBody()
+		c.fasthttp.Response.Header.Del(HeaderContentRange)
+		c.fasthttp.Response.SetStatusCode(StatusNotModified)
+	}
 
 	// Sets the response Content-Disposition header to attachment if the Download option is true
 	if cfg.Download {
@@ -1658,6 +2126,261 @@ func (c *DefaultCtx) SendFile(file string, config ...SendFile) error {
 	return nil
 }
 
+// byteRangeUpdater is implemented by the file readers of fasthttp, which read the byte range afterwards
+type byteRangeUpdater interface {
+	UpdateByteRange(startPos, endPos int) error
+}
+
+// byteRange is a range of bytes of a file, both positions are inclusive
+type byteRange struct {
+	start int
+	end   int
+}
+
+// serveFile serves the file with the handler of fasthttp, which only supports a single byte
+// range. GET requests for multiple byte ranges or with an If-Range header are served from the
+// reader of the full file, which is set as the body of the response.
+func (c *DefaultCtx) serveFile(handler fasthttp.RequestHandler, cfg SendFile) {
+	rangeHeader := c.fasthttp.Request.Header.Peek(HeaderRange)
+	ifRange := c.fasthttp.Request.Header.Peek(HeaderIfRange)
+	if !cfg.ByteRange || len(rangeHeader) == 0 || !c.fasthttp.IsGet() || (len(ifRange) == 0 && bytes.IndexByte(rangeHeader, ',') == -1) {
+		handler(c.fasthttp)
+		if cfg.ETag {
+			setFileETag(&c.fasthttp.Response.Header)
+		}
+		return
+	}
+
+	// The full file is served with a separate context, as replacing the body of the
+	// response would close the reader
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Init(&c.fasthttp.Request, c.fasthttp.RemoteAddr(), nil)
+	fctx.Request.Header.Del(HeaderRange)
+	fctx.Request.Header.Del(HeaderIfRange)
+	fctx.Request.Header.Del(HeaderAcceptEncoding)
+	c.fasthttp.Response.Header.CopyTo(&fctx.Response.Header)
+	handler(fctx)
+	if cfg.ETag {
+		setFileETag(&fctx.Response.Header)
+	}
+
+	resp := &c.fasthttp.Response
+	fctx.Response.Header.CopyTo(&resp.Header)
+	file := fctx.Response.BodyStream()
+	if file == nil {
+		// The file wasn't found or wasn't modified
+		resp.SetBody(fctx.Response.Body())
+		return
+	}
+	size := fctx.Response.Header.ContentLength()
+	closeFile := func() {
+		if closer, ok := file.(io.Closer); ok {
+			_ = closer.Close() //nolint:errcheck // It is fine to ignore the error here
+		}
+	}
+
+	updater, ok := file.(byteRangeUpdater)
+	if !ok || fctx.Response.StatusCode() != StatusOK || !ifRangeMatches(ifRange, &resp.Header) {
+		resp.SetBodyStream(file, size)
+		return
+	}
+
+	ranges, err := parseByteRanges(c.app.getString(rangeHeader), size)
+	switch {
+	case err != nil:
+		closeFile()
+		resp.Header.Set(HeaderContentRange, "bytes */"+strconv.Itoa(size))
+		resp.SetStatusCode(StatusRequestedRangeNotSatisfiable)
+		resp.SetBodyString(utils.StatusMessage(StatusRequestedRangeNotSatisfiable))
+	case ranges == nil:
+		// The ranges are larger than the file, which is sent instead
+		resp.SetBodyStream(file, size)
+	case len(ranges) == 1:
+		resp.SetBodyStream(file, ranges[0].end-ranges[0].start+1)
+		if err := updater.UpdateByteRange(ranges[0].start, ranges[0].end); err != nil {
+			resp.ResetBody()
+			resp.SetStatusCode(StatusInternalServerError)
+			return
+		}
+		resp.Header.SetContentRange(ranges[0].start, ranges[0].end, size)
+		resp.SetStatusCode(StatusPartialContent)
+	default:
+		contentType := utils.CopyString(c.app.getString(resp.Header.ContentType()))
+		boundary := multipart.NewWriter(nil).Boundary()
+
+		// Count the length of the body, without reading the file
+		var length countWriter
+		if err := writeByteRanges(&length, boundary, contentType, size, ranges, func(_ io.Writer, r byteRange) error {
+			length += countWriter(r.end - r.start + 1)
+			return nil
+		}); err != nil {
+			resp.SetBodyStream(file, size)
+			return
+		}
+
+		resp.SetBodyStreamWriter(func(w *bufio.Writer) {
+			defer closeFile()
+			err := writeByteRanges(w, boundary, contentType, size, ranges, func(w io.Writer, r byteRange) error {
+				if err := updater.UpdateByteRange(r.start, r.end); err != nil {
+					return fmt.Errorf("failed to seek byte range: %w", err)
+				}
+				_, err := io.Copy(w, file)
+				return err //nolint:wrapcheck // It is fine to return the error of the writer here
+			})
+			if err != nil {
+				log.Errorf("sendfile: failed to write byte ranges: %v", err)
+			}
+		})
+		resp.Header.SetContentLength(int(length))
+		resp.Header.SetContentType("multipart/byteranges; boundary=" + boundary)
+		resp.SetStatusCode(StatusPartialContent)
+	}
+}
+
+// writeByteRanges writes the ranges as parts of a multipart/byteranges body,
+// the content of each range is written by the function
+func writeByteRanges(w io.Writer, boundary, contentType string, size int, ranges []byteRange, writeRange func(w io.Writer, r byteRange) error) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return fmt.Errorf("failed to set boundary: %w", err)
+	}
+	for _, r := range ranges {
+		part, err := mw.CreatePart(map[string][]string{
+			HeaderContentType:  {contentType},
+			HeaderContentRange: {fmt.Sprintf("bytes %d-%d/%d", r.start, r.end, size)},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create part: %w", err)
+		}
+		if err := writeRange(part, r); err != nil {
+			return err
+		}
+	}
+	return mw.Close() //nolint:wrapcheck // It is fine to return the error of the writer here
+}
+
+// parseByteRanges parses the ranges of the Range header, e.g. "bytes=0-99,200-". Unsatisfiable
+// ranges are skipped and an error is returned if no range is satisfiable. The ranges are nil if
+// they're larger than the file together, so the file is sent instead.
+func parseByteRanges(header string, size int) ([]byteRange, error) {
+	specs, found := strings.CutPrefix(header, "bytes=")
+	if !found {
+		return nil, ErrRangeMalformed
+	}
+
+	var ranges []byteRange
+	total := 0
+	for _, spec := range strings.Split(specs, ",") {
+		spec = utils.Trim(spec, ' ')
+		if spec == "" {
+			continue
+		}
+		start, end, err := fasthttp.ParseByteRange([]byte("bytes="+spec), size)
+		if err != nil {
+			continue
+		}
+		ranges = append(ranges, byteRange{start: start, end: end})
+		total += end - start + 1
+	}
+	if len(ranges) == 0 {
+		return nil, ErrRangeUnsatisfiable
+	}
+	if total > size {
+		return nil, nil
+	}
+	return ranges, nil
+}
+
+// ifRangeMatches reports whether the If-Range header is missing or matches the
+// Last-Modified date or the strong ETag of the response
+func ifRangeMatches(ifRange []byte, header *fasthttp.ResponseHeader) bool {
+	if len(ifRange) == 0 {
+		return true
+	}
+	if ifRange[0] == '"' || bytes.HasPrefix(ifRange, []byte("W/")) {
+		return httpcache.StrongCompare(string(ifRange), string(header.Peek(HeaderETag)))
+	}
+	return bytes.Equal(ifRange, header.Peek(HeaderLastModified))
+}
+
+// setFileETag sets the ETag of the file which is served by the response
+// from its Last-Modified date and size
+func setFileETag(header *fasthttp.ResponseHeader) {
+	if header.StatusCode() != StatusOK && header.StatusCode() != StatusPartialContent {
+		return
+	}
+	lastModified, err := httpcache.ParseTime(string(header.Peek(HeaderLastModified)))
+	if err != nil {
+		return
+	}
+	size := header.ContentLength()
+	if contentRange := header.Peek(HeaderContentRange); len(contentRange) > 0 {
+		i := bytes.LastIndexByte(contentRange, '/')
+		if i == -1 {
+			return
+		}
+		if size, err = strconv.Atoi(string(contentRange[i+1:])); err != nil {
+			return
+		}
+	}
+	if size < 0 {
+		return
+	}
+	header.Set(HeaderETag, httpcache.FileETag(lastModified, int64(size)).String())
+}
+
+// countWriter counts the bytes which are written
+type countWriter int
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	*w += countWriter(len(p))
+	return len(p), nil
+}
+
+// SendEarlyHints sends an informational 103 Early Hints response with the links as Link headers,
+// so that clients can preload resources while the final response is prepared. The links are
+// also appended to the Link header of the final response. Clients of HTTP/1.0 don't support
+// informational responses and only get the final response.
+//
+// fasthttp buffers the responses of pipelined requests, so the 103 response is only sent for the
+// first request of a connection, unless Config.ReduceMemoryUsage is set, which flushes every
+// response. For the other requests, only the Link header of the final response is set.
+//
+//	err := c.SendEarlyHints([]string{"</style.css>; rel=preload; as=style"})
+func (c *DefaultCtx) SendEarlyHints(hints []string) error {
+	if len(hints) == 0 {
+		return nil
+	}
+	for _, hint := range hints {
+		if strings.ContainsAny(hint, "\r\n") {
+			return ErrInvalidEarlyHint
+		}
+	}
+	c.Append(HeaderLink, hints...)
+
+	conn := c.fasthttp.Conn()
+	if conn == nil || !c.fasthttp.Request.Header.IsHTTP11() {
+		return nil
+	}
+	// Earlier responses of the connection may still be in the buffer of fasthttp,
+	// which must be written before the 103 response
+	if c.fasthttp.ConnRequestNum() > 1 && !c.app.config.ReduceMemoryUsage {
+		return nil
+	}
+	bb := bytebufferpool.Get()
+	defer bytebufferpool.Put(bb)
+	bb.WriteString("HTTP/1.1 103 Early Hints\r\n")
+	for _, hint := range hints {
+		bb.WriteString(HeaderLink + ": " + hint + "\r\n")
+	}
+	bb.WriteString("\r\n")
+	if _, err := conn.Write(bb.B); err != nil {
+		return fmt.Errorf("failed to write early hints: %w", err)
+	}
+
+	return nil
+}
+
 // SendStatus sets the HTTP status code and if the response body is empty,
 // it sets the correct status message in the body.
 func (c *DefaultCtx) SendStatus(status int) error {
@@ -1692,16 +2415,153 @@ func (c *DefaultCtx) SendStream(stream io.Reader, size ...int) error {
 
 // SendStreamWriter sets response body stream writer
 func (c *DefaultCtx) SendStreamWriter(streamWriter func(*bufio.Writer)) error {
-	c.fasthttp.Response.SetBodyStreamWriter(fasthttp.StreamWriter(streamWriter))
+	stream := &trailerStream{
+		ReadCloser: fasthttp.NewStreamReader(fasthttp.StreamWriter(streamWriter)),
+		header:     &c.fasthttp.Response.Header,
+		trailers:   c.trailerFuncs,
+	}
+	c.trailerFuncs = nil
+	c.fasthttp.Response.SetBodyStream(stream, -1)
 
 	return nil
 }
 
+// SSE streams server-sent events to the client. It sets the headers of an event stream
+// and calls fn with the stream, which writes and flushes the events. fn is called after
+// the handler returned, so it must not use the Ctx, and the stream ends when fn returns.
+// Keep-alive comments are written every SSEConfig.KeepAlive until then.
+//
+//	return c.SSE(func(stream *fiber.EventStream) {
+//		for msg := range messages {
+//			if err := stream.Send(fiber.SSEEvent{Event: "message", Data: msg}); err != nil {
+//				return // The client disconnected
+//			}
+//		}
+//	})
+func (c *DefaultCtx) SSE(fn func(stream *EventStream), config ...SSEConfig) error {
+	cfg := sseConfigDefault(config...)
+	stream := &EventStream{
+		app:         c.app,
+		done:        make(chan struct{}),
+		lastEventID: utils.CopyString(c.Get(HeaderLastEventID)),
+	}
+
+	c.Set(HeaderContentType, "text/event-stream")
+	c.Set(HeaderCacheControl, "no-cache")
+	c.Set(HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		stream.w = w
+		stream.run(cfg, fn)
+	})
+}
+
+// NDJSON streams newline-delimited JSON records, also known as JSON Lines, to the client.
+// It calls fn with the stream, which writes the records of e.g. a channel or an iterator.
+// fn is called after the handler returned, so it must not use the Ctx, and the stream ends
+// when fn returns. The records are flushed every NDJSONConfig.FlushEvery records.
+//
+//	return c.NDJSON(func(stream *fiber.NDJSONStream) {
+//		for entry := range entries {
+//			if err := stream.Write(entry); err != nil {
+//				return // The client disconnected
+//			}
+//		}
+//	})
+func (c *DefaultCtx) NDJSON(fn func(stream *NDJSONStream), config ...NDJSONConfig) error {
+	cfg := ndjsonConfigDefault(config...)
+	stream := &NDJSONStream{
+		app:        c.app,
+		flushEvery: cfg.FlushEvery,
+	}
+
+	c.Set(HeaderContentType, MIMEApplicationNDJSON)
+	c.Set("X-Accel-Buffering", "no")
+
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		stream.w = w
+		stream.run(fn)
+	})
+}
+
 // Set sets the response's HTTP header field to the specified key, value.
 func (c *DefaultCtx) Set(key, val string) {
 	c.fasthttp.Response.Header.Set(key, val)
 }
 
+// SetTrailer sets the response's HTTP trailer field to the specified key, value and declares
+// it in the Trailer header. Trailers are written after the body of streamed responses without
+// a known size, e.g. SendStreamWriter, and are omitted from other responses. Use SetTrailerFunc
+// for values which are computed while the body is streamed.
+// Fields which are forbidden as trailers, e.g. Content-Length, return fasthttp.ErrBadTrailer.
+func (c *DefaultCtx) SetTrailer(key, val string) error {
+	if err := c.declareTrailer(key); err != nil {
+		return err
+	}
+	c.fasthttp.Response.Header.Set(key, val)
+
+	return nil
+}
+
+// SetTrailerFunc declares the response's HTTP trailer field like SetTrailer, and sets its value
+// to the result of fn once the body of SendStreamWriter is written completely. fn is called by
+// the goroutine which writes the response after the stream writer returned, so it can return
+// values which the stream writer computed, e.g. a checksum of the body.
+func (c *DefaultCtx) SetTrailerFunc(key string, fn func() string) error {
+	if err := c.declareTrailer(key); err != nil {
+		return err
+	}
+	trailer := trailerFunc{key: utils.CopyString(key), fn: fn}
+	if stream, ok := c.fasthttp.Response.BodyStream().(*trailerStream); ok {
+		stream.trailers = append(stream.trailers, trailer)
+	} else {
+		c.trailerFuncs = append(c.trailerFuncs, trailer)
+	}
+
+	return nil
+}
+
+// declareTrailer declares the key in the Trailer header, unless it's declared already
+func (c *DefaultCtx) declareTrailer(key string) error {
+	declared := false
+	c.fasthttp.Response.Header.VisitAllTrailer(func(trailer []byte) {
+		declared = declared || utils.EqualFold(c.app.getString(trailer), key)
+	})
+	if !declared {
+		if err := c.fasthttp.Response.Header.AddTrailer(key); err != nil {
+			return fmt.Errorf("failed to add trailer %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// trailerFunc is a trailer of SetTrailerFunc
+type trailerFunc struct {
+	fn  func() string
+	key string
+}
+
+// trailerStream is the body stream of SendStreamWriter, which sets the trailers of SetTrailerFunc
+// when the body is read completely. fasthttp reads the body and writes the trailers afterwards
+// in the same goroutine, and the stream writer returned before the end of the body is read.
+type trailerStream struct {
+	io.ReadCloser
+	header   *fasthttp.ResponseHeader
+	trailers []trailerFunc
+}
+
+func (s *trailerStream) Read(p []byte) (int, error) {
+	n, err := s.ReadCloser.Read(p)
+	if errors.Is(err, io.EOF) {
+		for _, trailer := range s.trailers {
+			s.header.Set(trailer.key, trailer.fn())
+		}
+		s.trailers = nil
+	}
+	return n, err //nolint:wrapcheck // io.EOF must not be wrapped
+}
+
 func (c *DefaultCtx) setCanonical(key, val string) {
 	c.fasthttp.Response.Header.SetCanonical(utils.UnsafeBytes(key), utils.UnsafeBytes(val))
 }
@@ -1850,23 +2710,24 @@ func (c *DefaultCtx) configDependentPaths() {
 // If Config.TrustProxy false, it returns true
 // IsProxyTrusted can check remote ip by proxy ranges and ip map.
 func (c *DefaultCtx) IsProxyTrusted() bool {
-	if !c.app.config.TrustProxy {
+	proxy := c.app.runtime.trustProxy.Load()
+	if !proxy.enabled {
 		return true
 	}
 
 	ip := c.fasthttp.RemoteIP()
 
-	if (c.app.config.TrustProxyConfig.Loopback && ip.IsLoopback()) ||
-		(c.app.config.TrustProxyConfig.Private && ip.IsPrivate()) ||
-		(c.app.config.TrustProxyConfig.LinkLocal && ip.IsLinkLocalUnicast()) {
+	if (proxy.config.Loopback && ip.IsLoopback()) ||
+		(proxy.config.Private && ip.IsPrivate()) ||
+		(proxy.config.LinkLocal && ip.IsLinkLocalUnicast()) {
 		return true
 	}
 
-	if _, trusted := c.app.config.TrustProxyConfig.ips[ip.String()]; trusted {
+	if _, trusted := proxy.config.ips[ip.String()]; trusted {
 		return true
 	}
 
-	for _, ipNet := range c.app.config.TrustProxyConfig.ranges {
+	for _, ipNet := range proxy.config.ranges {
 		if ipNet.Contains(ip) {
 			return true
 		}
@@ -1875,8 +2736,20 @@ func (c *DefaultCtx) IsProxyTrusted() bool {
 	return false
 }
 
-// IsFromLocal will return true if request came from local.
+// IsFromLocal will return true if request came from local, from an IPv4 or IPv6 loopback address.
+// If TrustProxy is enabled and the request came from a trusted proxy, the last address in the
+// ProxyHeader is checked instead, which the proxy added, as the previous ones can be spoofed.
 func (c *DefaultCtx) IsFromLocal() bool {
+	if c.app.config.ProxyHeader != "" && c.app.runtime.trustProxy.Load().enabled && c.IsProxyTrusted() {
+		if header := c.Get(c.app.config.ProxyHeader); header != "" {
+			if i := strings.LastIndexByte(header, ','); i != -1 {
+				header = header[i+1:]
+			}
+			ip := net.ParseIP(utils.Trim(header, ' '))
+			return ip != nil && ip.IsLoopback()
+		}
+	}
+
 	return c.fasthttp.RemoteIP().IsLoopback()
 }
 
@@ -1893,6 +2766,14 @@ func (c *DefaultCtx) Bind() *Bind {
 	return c.bind
 }
 
+// Defer registers a function which runs in a background task after the handlers
+// returned, so post-response work doesn't delay the response. The functions run in
+// the order they were registered and Shutdown waits for them. The Ctx must not be
+// used in the functions, copy the values they need.
+func (c *DefaultCtx) Defer(fn func()) {
+	c.deferred = append(c.deferred, fn)
+}
+
 // Reset is a method to reset context fields by given request when to use server handlers.
 func (c *DefaultCtx) Reset(fctx *fasthttp.RequestCtx) {
 	// Reset route and handler index
@@ -1915,11 +2796,20 @@ func (c *DefaultCtx) Reset(fctx *fasthttp.RequestCtx) {
 
 // Release is a method to reset context fields when to use ReleaseCtx()
 func (c *DefaultCtx) release() {
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
 	c.route = nil
 	c.fasthttp = nil
 	c.bind = nil
 	c.flashMessages = c.flashMessages[:0]
 	c.viewBindMap = sync.Map{}
+	if len(c.deferred) > 0 {
+		c.app.runDeferred(c.deferred)
+		c.deferred = nil
+	}
+	c.trailerFuncs = nil
 	if c.redirect != nil {
 		ReleaseRedirect(c.redirect)
 		c.redirect = nil
@@ -1947,6 +2837,14 @@ func (c *DefaultCtx) getTreePath() string {
 	return c.treePath
 }
 
+func (c *DefaultCtx) getTreeStack() *[]map[string][]*Route {
+	return c.treeStack
+}
+
+func (c *DefaultCtx) setTreeStack(treeStack *[]map[string][]*Route) {
+	c.treeStack = treeStack
+}
+
 func (c *DefaultCtx) getDetectionPath() string {
 	return c.detectionPath
 }
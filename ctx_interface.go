@@ -20,6 +20,7 @@ type CustomCtx interface {
 	getMethodINT() int
 	getIndexRoute() int
 	getTreePath() string
+	getTreeStack() *[]map[string][]*Route
 	getDetectionPath() string
 	getPathOriginal() string
 	getValues() *[maxParams]string
@@ -28,6 +29,7 @@ type CustomCtx interface {
 	setIndexRoute(route int)
 	setMatched(matched bool)
 	setRoute(route *Route)
+	setTreeStack(treeStack *[]map[string][]*Route)
 }
 
 func NewDefaultCtx(app *App) *DefaultCtx {
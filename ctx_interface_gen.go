@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto/tls"
 	"io"
+	"iter"
 	"mime/multipart"
 
 	"github.com/valyala/fasthttp"
@@ -22,7 +23,18 @@ type Ctx interface {
 	// AcceptsEncodings checks if the specified encoding is acceptable.
 	AcceptsEncodings(offers ...string) string
 	// AcceptsLanguages checks if the specified language is acceptable.
+	// Languages are compared case-insensitively, and a language matches more and less
+	// specific languages of the Accept-Language header, e.g. "en" matches "en-GB".
 	AcceptsLanguages(offers ...string) string
+	// Language returns the supported language which the client prefers according to the
+	// quality values of the Accept-Language header. Languages which aren't supported fall
+	// back to less specific ones with the same quality, e.g. "en-GB" to "en", unless these
+	// are listed in the header themselves. Exact matches take precedence over fallbacks.
+	// If the header is missing or no supported language is acceptable, the first supported
+	// language is returned, so that it can be used as default.
+	//
+	//	lang := c.Language("en", "de", "fr-CH")
+	Language(supported ...string) string
 	// App returns the *App reference to the instance of the Fiber application
 	App() *App
 	// Append the specified value to the HTTP response header field.
@@ -51,6 +63,8 @@ type Ctx interface {
 	RequestCtx() *fasthttp.RequestCtx
 	// Context returns a context implementation that was set by
 	// user earlier or returns a non-nil, empty context,if it was not set earlier.
+	// With Config.EnableContextCancellation, the returned context is canceled
+	// when the request is done or the app shuts down, but not when the client disconnects.
 	Context() context.Context
 	// SetContext sets a context implementation by user.
 	SetContext(ctx context.Context)
@@ -88,6 +102,14 @@ type Ctx interface {
 	// For more flexible content negotiation, use Format.
 	// If the header is not specified or there is no proper format, text/plain is used.
 	AutoFormat(body any) error
+	// Negotiate performs content-negotiation on the Accept HTTP header and sends
+	// the data in the representation preferred by the client.
+	// The HTML representation renders the configured template, the other
+	// representations serialize the data with the encoders of the app.
+	// The offers are tried in the order HTML, JSON, XML, CBOR, MsgPack, CSV and the registered
+	// encoders, so the first offered representation is used if the Accept header is missing.
+	// If no offered representation is acceptable, StatusNotAcceptable is sent.
+	Negotiate(data any, offer NegotiateOffer) error
 	// FormFile returns the first file by key from a MultipartForm.
 	FormFile(key string) (*multipart.FileHeader, error)
 	// FormValue returns the first value by key from a MultipartForm.
@@ -122,6 +144,11 @@ type Ctx interface {
 	// Returned value is only valid within the handler. Do not store any references.
 	// Make copies or use the Immutable setting instead.
 	GetReqHeaders() map[string][]string
+	// GetReqTrailers returns the HTTP request trailers, which are declared in the Trailer header
+	// and sent after the chunked body of the request.
+	// Returned value is only valid within the handler. Do not store any references.
+	// Make copies or use the Immutable setting instead.
+	GetReqTrailers() map[string][]string
 	// Host contains the host derived from the X-Forwarded-Host or Host HTTP header.
 	// Returned value is only valid within the handler. Do not store any references.
 	// In a network context, `Host` refers to the combination of a hostname and potentially a port number used for connecting,
@@ -164,11 +191,43 @@ type Ctx interface {
 	// Content-Type header equal to ctype. If ctype is not given,
 	// The Content-Type header will be set to application/json.
 	JSON(data any, ctype ...string) error
+	// Protobuf encodes the protobuf message with the ProtobufEncoder of the app.
+	// If the ctype parameter is given, this method will set the
+	// Content-Type header equal to ctype. If ctype is not given,
+	// The Content-Type header will be set to application/x-protobuf.
+	Protobuf(msg any, ctype ...string) error
+	// MsgPack encodes the data as MessagePack with the MsgPackEncoder of the app.
+	// If the ctype parameter is given, this method will set the
+	// Content-Type header equal to ctype. If ctype is not given,
+	// The Content-Type header will be set to application/msgpack.
+	MsgPack(data any, ctype ...string) error
 	// CBOR converts any interface or string to CBOR encoded bytes.
 	// If the ctype parameter is given, this method will set the
 	// Content-Type header equal to ctype. If ctype is not given,
 	// The Content-Type header will be set to application/cbor.
 	CBOR(data any, ctype ...string) error
+	// JSONStream converts any interface to JSON like JSON, but encodes it directly into the
+	// response body stream with chunked transfer instead of an in-memory buffer, for large
+	// responses. The data is encoded after the handler returned, so it must not reference
+	// values which are only valid within the handler. Since the headers are sent before the
+	// encoding starts, encoding errors can no longer change the response and are only logged.
+	// If the ctype parameter is given, this method will set the Content-Type header equal
+	// to ctype. If ctype is not given, the Content-Type header will be set to application/json.
+	JSONStream(data any, ctype ...string) error
+	// CSV streams the header and the rows as CSV into the response body stream, so the rows are
+	// never buffered in memory. Fields with delimiters, quotes or line breaks are quoted.
+	// The rows are iterated after the handler returned, so the iterator must not use the Ctx.
+	// Since the headers are sent before the rows are written, errors can no longer change the
+	// response and are only logged. A nil header writes no header record.
+	//
+	//	return c.CSV([]string{"id", "name"}, func(yield func([]string) bool) {
+	//		for _, user := range users {
+	//			if !yield([]string{user.ID, user.Name}) {
+	//				return
+	//			}
+	//		}
+	//	}, fiber.CSVConfig{Filename: "users.csv"})
+	CSV(header []string, rows iter.Seq[[]string], config ...CSVConfig) error
 	// JSONP sends a JSON response with JSONP support.
 	// This method is identical to JSON, except that it opts-in to JSONP callback support.
 	// By default, the callback name is simply callback.
@@ -260,10 +319,21 @@ type Ctx interface {
 	// getLocationFromRoute get URL location from route using parameters
 	getLocationFromRoute(route Route, params Map) (string, error)
 	// GetRouteURL generates URLs to named routes, with parameters. URLs are relative, for example: "/user/1831"
-	GetRouteURL(routeName string, params Map) (string, error)
+	// The optional queries are appended as query string, sorted by key: "/user/1831?page=2"
+	GetRouteURL(routeName string, params Map, queries ...Map) (string, error)
 	// Render a template with data and sends a text/html response.
 	// We support the following engines: https://github.com/gofiber/template
 	Render(name string, bind Map, layouts ...string) error
+	// RenderStream renders a template with data like Render, but writes the output
+	// directly into the response body stream instead of an in-memory buffer.
+	// The writer passed to the Views engine is a *bufio.Writer, so engines can call
+	// Flush to send already rendered parts (e.g. the page head) to the client early.
+	// Since the headers are sent before the rendering starts, errors returned by the
+	// Views engine can no longer change the response and are only logged.
+	RenderStream(name string, bind Map, layouts ...string) error
+	// viewsEngine returns the Views engine of the (mounted) app which is responsible
+	// for the current request, together with the layouts that should be applied.
+	viewsEngine(layouts []string) (Views, []string)
 	renderExtensions(bind any)
 	// Route returns the matched Route struct.
 	Route() *Route
@@ -281,6 +351,21 @@ type Ctx interface {
 	// The Content-Type response HTTP header field is set based on the file's extension.
 	// If the file extension is missing or invalid, the Content-Type is detected from the file's format.
 	SendFile(file string, config ...SendFile) error
+	// serveFile serves the file with the handler of fasthttp, which only supports a single byte
+	// range. GET requests for multiple byte ranges or with an If-Range header are served from the
+	// reader of the full file, which is set as the body of the response.
+	serveFile(handler fasthttp.RequestHandler, cfg SendFile)
+	// SendEarlyHints sends an informational 103 Early Hints response with the links as Link headers,
+	// so that clients can preload resources while the final response is prepared. The links are
+	// also appended to the Link header of the final response. Clients of HTTP/1.0 don't support
+	// informational responses and only get the final response.
+	//
+	// fasthttp buffers the responses of pipelined requests, so the 103 response is only sent for the
+	// first request of a connection, unless Config.ReduceMemoryUsage is set, which flushes every
+	// response. For the other requests, only the Link header of the final response is set.
+	//
+	//	err := c.SendEarlyHints([]string{"</style.css>; rel=preload; as=style"})
+	SendEarlyHints(hints []string) error
 	// SendStatus sets the HTTP status code and if the response body is empty,
 	// it sets the correct status message in the body.
 	SendStatus(status int) error
@@ -291,8 +376,47 @@ type Ctx interface {
 	SendStream(stream io.Reader, size ...int) error
 	// SendStreamWriter sets response body stream writer
 	SendStreamWriter(streamWriter func(*bufio.Writer)) error
+	// SSE streams server-sent events to the client. It sets the headers of an event stream
+	// and calls fn with the stream, which writes and flushes the events. fn is called after
+	// the handler returned, so it must not use the Ctx, and the stream ends when fn returns.
+	// Keep-alive comments are written every SSEConfig.KeepAlive until then.
+	//
+	//	return c.SSE(func(stream *fiber.EventStream) {
+	//		for msg := range messages {
+	//			if err := stream.Send(fiber.SSEEvent{Event: "message", Data: msg}); err != nil {
+	//				return // The client disconnected
+	//			}
+	//		}
+	//	})
+	SSE(fn func(stream *EventStream), config ...SSEConfig) error
+	// NDJSON streams newline-delimited JSON records, also known as JSON Lines, to the client.
+	// It calls fn with the stream, which writes the records of e.g. a channel or an iterator.
+	// fn is called after the handler returned, so it must not use the Ctx, and the stream ends
+	// when fn returns. The records are flushed every NDJSONConfig.FlushEvery records.
+	//
+	//	return c.NDJSON(func(stream *fiber.NDJSONStream) {
+	//		for entry := range entries {
+	//			if err := stream.Write(entry); err != nil {
+	//				return // The client disconnected
+	//			}
+	//		}
+	//	})
+	NDJSON(fn func(stream *NDJSONStream), config ...NDJSONConfig) error
 	// Set sets the response's HTTP header field to the specified key, value.
 	Set(key, val string)
+	// SetTrailer sets the response's HTTP trailer field to the specified key, value and declares
+	// it in the Trailer header. Trailers are written after the body of streamed responses without
+	// a known size, e.g. SendStreamWriter, and are omitted from other responses. Use SetTrailerFunc
+	// for values which are computed while the body is streamed.
+	// Fields which are forbidden as trailers, e.g. Content-Length, return fasthttp.ErrBadTrailer.
+	SetTrailer(key, val string) error
+	// SetTrailerFunc declares the response's HTTP trailer field like SetTrailer, and sets its value
+	// to the result of fn once the body of SendStreamWriter is written completely. fn is called by
+	// the goroutine which writes the response after the stream writer returned, so it can return
+	// values which the stream writer computed, e.g. a checksum of the body.
+	SetTrailerFunc(key string, fn func() string) error
+	// declareTrailer declares the key in the Trailer header, unless it's declared already
+	declareTrailer(key string) error
 	setCanonical(key, val string)
 	// Subdomains returns a string slice of subdomains in the domain name of the request.
 	// The subdomain offset, which defaults to 2, is used for determining the beginning of the subdomain segments.
@@ -327,12 +451,19 @@ type Ctx interface {
 	// If Config.TrustProxy false, it returns true
 	// IsProxyTrusted can check remote ip by proxy ranges and ip map.
 	IsProxyTrusted() bool
-	// IsFromLocal will return true if request came from local.
+	// IsFromLocal will return true if request came from local, from an IPv4 or IPv6 loopback address.
+	// If TrustProxy is enabled and the request came from a trusted proxy, the last address in the
+	// ProxyHeader is checked instead, which the proxy added, as the previous ones can be spoofed.
 	IsFromLocal() bool
 	// Bind You can bind body, cookie, headers etc. into the map, map slice, struct easily by using Binding method.
 	// It gives custom binding support, detailed binding options and more.
 	// Replacement of: BodyParser, ParamsParser, GetReqHeaders, GetRespHeaders, AllParams, QueryParser, ReqHeaderParser
 	Bind() *Bind
+	// Defer registers a function which runs in a background task after the handlers
+	// returned, so post-response work doesn't delay the response. The functions run in
+	// the order they were registered and Shutdown waits for them. The Ctx must not be
+	// used in the functions, copy the values they need.
+	Defer(fn func())
 	// Reset is a method to reset context fields by given request when to use server handlers.
 	Reset(fctx *fasthttp.RequestCtx)
 	// Release is a method to reset context fields when to use ReleaseCtx()
@@ -342,6 +473,8 @@ type Ctx interface {
 	getMethodINT() int
 	getIndexRoute() int
 	getTreePath() string
+	getTreeStack() *[]map[string][]*Route
+	setTreeStack(treeStack *[]map[string][]*Route)
 	getDetectionPath() string
 	getPathOriginal() string
 	getValues() *[maxParams]string
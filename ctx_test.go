@@ -13,27 +13,35 @@ import (
 	"crypto/tls"
 	"embed"
 	"encoding/hex"
+	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
 	"math"
+	"mime"
 	"mime/multipart"
 	"net"
+	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
 	"text/template"
 	"time"
 
+	"github.com/gofiber/fiber/v3/httpcache"
 	"github.com/gofiber/fiber/v3/internal/storage/memory"
 	"github.com/gofiber/utils/v2"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tinylib/msgp/msgp"
 	"github.com/valyala/bytebufferpool"
 	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
 )
 
 const epsilon = 0.001
@@ -67,6 +75,13 @@ func Test_Ctx_Accepts(t *testing.T) {
 
 	c.Request().Header.Set(HeaderAccept, "*/*")
 	require.Equal(t, "html", c.Accepts("html"))
+
+	c.Request().Header.Set(HeaderAccept, "text/*;q=0.8, text/html;q=0.2, */*;q=0.1")
+	require.Equal(t, "txt", c.Accepts("html", "txt", "json"), "must use the quality of the most specific type")
+	require.Equal(t, "html", c.Accepts("html", "json"))
+
+	c.Request().Header.Set(HeaderAccept, "*/*, image/webp;q=0")
+	require.Equal(t, "png", c.Accepts("webp", "png"), "must treat image/webp;q=0 as not acceptable")
 }
 
 // go test -v -run=^$ -bench=Benchmark_Ctx_Accepts -benchmem -count=4
@@ -209,6 +224,40 @@ func Test_Ctx_AcceptsLanguages(t *testing.T) {
 
 	c.Request().Header.Set(HeaderAcceptLanguage, "fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5")
 	require.Equal(t, "fr", c.AcceptsLanguages("fr"))
+
+	c.Request().Header.Set(HeaderAcceptLanguage, "en-GB, de;q=0.5")
+	require.Equal(t, "EN", c.AcceptsLanguages("de", "EN"))
+	require.Equal(t, "de", c.AcceptsLanguages("e", "de"), "must only match whole subtags")
+
+	c.Request().Header.Set(HeaderAcceptLanguage, "en;q=0.9, de")
+	require.Equal(t, "de-AT", c.AcceptsLanguages("en-US", "de-AT"))
+	require.Equal(t, "", c.AcceptsLanguages("fr"))
+}
+
+// go test -run Test_Ctx_Language
+func Test_Ctx_Language(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	require.Equal(t, "", c.Language())
+	// Without Accept-Language header the first language is used
+	require.Equal(t, "en", c.Language("en", "de"))
+
+	c.Request().Header.Set(HeaderAcceptLanguage, "de-CH, fr;q=0.9, en-GB;q=0.8")
+	require.Equal(t, "de", c.Language("en", "fr", "de"), "must fall back to the language of the region")
+	require.Equal(t, "de-CH", c.Language("de", "de-CH"), "must prefer exact matches")
+	require.Equal(t, "fr", c.Language("en", "fr"))
+	require.Equal(t, "en", c.Language("en", "it"))
+	require.Equal(t, "it", c.Language("it", "es"), "must use the first language as default")
+
+	c.Request().Header.Set(HeaderAcceptLanguage, "zh-Hant-TW, zh-Hant;q=0.5, en;q=0.7")
+	require.Equal(t, "zh", c.Language("en", "zh"))
+	require.Equal(t, "en", c.Language("zh-hant", "en"), "must keep the quality of listed languages")
+
+	c.Request().Header.Set(HeaderAcceptLanguage, "en-US, en;q=0, *;q=0.1")
+	require.Equal(t, "de", c.Language("en", "de"))
+	require.Equal(t, "EN-us", c.Language("de", "EN-us"))
 }
 
 // go test -v -run=^$ -bench=Benchmark_Ctx_AcceptsLanguages -benchmem -count=4
@@ -303,6 +352,11 @@ func Test_Ctx_Attachment(t *testing.T) {
 	// check quoting
 	c.Attachment("another document.pdf\"\r\nBla: \"fasel")
 	require.Equal(t, `attachment; filename="another+document.pdf%22%0D%0ABla%3A+%22fasel"`, string(c.Response().Header.Peek(HeaderContentDisposition)))
+	// non-ASCII filename
+	c.Attachment("./static/résumé.pdf")
+	require.Equal(t, `attachment; filename="r%C3%A9sum%C3%A9.pdf"; filename*=UTF-8''r%C3%A9sum%C3%A9.pdf`, string(c.Response().Header.Peek(HeaderContentDisposition)))
+	c.Attachment("文件 \"1\"\r\n.txt")
+	require.Equal(t, `attachment; filename="%E6%96%87%E4%BB%B6+%221%22%0D%0A.txt"; filename*=UTF-8''%E6%96%87%E4%BB%B6%20%221%22%0D%0A.txt`, string(c.Response().Header.Peek(HeaderContentDisposition)))
 }
 
 // go test -v -run=^$ -bench=Benchmark_Ctx_Attachment -benchmem -count=4
@@ -873,6 +927,46 @@ func Test_Ctx_Context(t *testing.T) {
 	})
 }
 
+// go test -run Test_Ctx_Context_Cancellation
+func Test_Ctx_Context_Cancellation(t *testing.T) {
+	t.Parallel()
+	app := New(Config{EnableContextCancellation: true})
+
+	done := make(chan context.Context, 1)
+	app.Get("/", func(c Ctx) error {
+		ctx := c.Context()
+		require.NoError(t, ctx.Err())
+		done <- ctx
+		return c.SendStatus(StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	// The context is canceled when the request is done
+	require.ErrorIs(t, (<-done).Err(), context.Canceled)
+
+	// The contexts of running requests are canceled on shutdown
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	ctx := c.Context()
+	require.NoError(t, ctx.Err())
+	require.NoError(t, app.Shutdown())
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	// The requests of a restarted app get contexts which aren't canceled
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.ErrorIs(t, (<-done).Err(), context.Canceled)
+
+	// Without the config, the context is never canceled
+	app = New()
+	c = app.AcquireCtx(&fasthttp.RequestCtx{})
+	require.Nil(t, c.Context().Done())
+	app.ReleaseCtx(c)
+}
+
 // go test -run Test_Ctx_SetContext
 func Test_Ctx_SetContext(t *testing.T) {
 	t.Parallel()
@@ -1124,6 +1218,102 @@ func Benchmark_Ctx_Format(b *testing.B) {
 	})
 }
 
+// go test -run Test_Ctx_Negotiate
+func Test_Ctx_Negotiate(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	type user struct {
+		Name string `json:"name" xml:"name"`
+	}
+	offer := NegotiateOffer{
+		HTML: "./.github/testdata/index.tmpl",
+		JSON: true,
+		XML:  true,
+		CSV: func(data any) ([]byte, error) {
+			u, ok := data.(user)
+			if !ok {
+				return nil, errors.New("unexpected data")
+			}
+			return []byte("name\n" + u.Name + "\n"), nil
+		},
+	}
+	data := user{Name: "john"}
+
+	// Without Accept header the first offer is used
+	err := c.Negotiate(Map{"Title": "Hello"}, offer)
+	require.NoError(t, err)
+	require.Equal(t, MIMETextHTMLCharsetUTF8, c.GetRespHeader(HeaderContentType))
+	require.Equal(t, "<h1>Hello</h1>", string(c.Response().Body()))
+	require.Equal(t, HeaderAccept, c.GetRespHeader(HeaderVary))
+
+	c.Request().Header.Set(HeaderAccept, "application/json")
+	err = c.Negotiate(data, offer)
+	require.NoError(t, err)
+	require.Equal(t, MIMEApplicationJSON, c.GetRespHeader(HeaderContentType))
+	require.Equal(t, `{"name":"john"}`, string(c.Response().Body()))
+
+	c.Request().Header.Set(HeaderAccept, "text/html;q=0.5,application/xml")
+	err = c.Negotiate(data, offer)
+	require.NoError(t, err)
+	require.Equal(t, MIMEApplicationXML, c.GetRespHeader(HeaderContentType))
+	require.Equal(t, `<user><name>john</name></user>`, string(c.Response().Body()))
+
+	c.Request().Header.Set(HeaderAccept, "text/csv")
+	err = c.Negotiate(data, offer)
+	require.NoError(t, err)
+	require.Equal(t, MIMETextCSVCharsetUTF8, c.GetRespHeader(HeaderContentType))
+	require.Equal(t, "name\njohn\n", string(c.Response().Body()))
+
+	err = c.Negotiate("invalid", offer)
+	require.ErrorContains(t, err, "unexpected data")
+
+	c.Request().Header.Set(HeaderAccept, "application/cbor")
+	err = c.Negotiate(data, offer)
+	require.NoError(t, err)
+	require.Equal(t, StatusNotAcceptable, c.Response().StatusCode())
+
+	c.Request().Header.Set(HeaderAccept, "application/msgpack")
+	offer.MsgPack = true
+	err = c.Negotiate(Map{"name": "john"}, offer)
+	require.NoError(t, err)
+	require.Equal(t, MIMEApplicationMsgPack, c.GetRespHeader(HeaderContentType))
+	out, _, err := msgp.ReadMapStrIntfBytes(c.Response().Body(), nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "john"}, out)
+
+	err = c.Negotiate(data, NegotiateOffer{})
+	require.ErrorIs(t, err, ErrNoHandlers)
+}
+
+// go test -run Test_Ctx_Negotiate_Encoders
+func Test_Ctx_Negotiate_Encoders(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.RegisterBodyEncoder("Application/MsgPack", func(data any) ([]byte, error) {
+		return []byte(fmt.Sprintf("msgpack:%v", data)), nil
+	})
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	offer := NegotiateOffer{JSON: true, Encoders: []string{"application/msgpack"}}
+
+	// The most specific type determines the quality of an offer
+	c.Request().Header.Set(HeaderAccept, "application/*;q=0.9, application/json;q=0.1")
+	err := c.Negotiate("john", offer)
+	require.NoError(t, err)
+	require.Equal(t, "application/msgpack", c.GetRespHeader(HeaderContentType))
+	require.Equal(t, "msgpack:john", string(c.Response().Body()))
+
+	c.Request().Header.Set(HeaderAccept, "application/msgpack;q=0.5, application/json")
+	err = c.Negotiate("john", offer)
+	require.NoError(t, err)
+	require.Equal(t, MIMEApplicationJSON, c.GetRespHeader(HeaderContentType))
+	require.Equal(t, `"john"`, string(c.Response().Body()))
+
+	err = c.Negotiate("john", NegotiateOffer{Encoders: []string{"application/protobuf"}})
+	require.ErrorIs(t, err, ErrBodyEncoderNotFound)
+}
+
 // go test -run Test_Ctx_AutoFormat
 func Test_Ctx_AutoFormat(t *testing.T) {
 	t.Parallel()
@@ -2272,6 +2462,49 @@ func Test_Ctx_Locals_GenericCustomStruct(t *testing.T) {
 	require.Equal(t, StatusOK, resp.StatusCode, "Status code")
 }
 
+// go test -run Test_Ctx_LocalKey
+func Test_Ctx_LocalKey(t *testing.T) {
+	t.Parallel()
+
+	type User struct {
+		name string
+	}
+	userKey := NewLocalKey[*User]("user")
+	otherUserKey := NewLocalKey[*User]("user")
+	countKey := NewLocalKey[int]("count")
+	require.Equal(t, "user", userKey.String())
+
+	app := New()
+	app.Use(func(c Ctx) error {
+		user, ok := userKey.Get(c)
+		require.False(t, ok)
+		require.Nil(t, user)
+
+		userKey.Set(c, &User{name: "john"})
+		countKey.Set(c, 0)
+		return c.Next()
+	})
+	app.Get("/test", func(c Ctx) error {
+		user, ok := userKey.Get(c)
+		require.True(t, ok)
+		require.Equal(t, "john", user.name)
+		require.Equal(t, &User{name: "john"}, userKey.Value(c))
+
+		// Keys with the same name don't collide
+		_, ok = otherUserKey.Get(c)
+		require.False(t, ok)
+		require.Nil(t, c.Locals("user"))
+
+		count, ok := countKey.Get(c)
+		require.True(t, ok)
+		require.Equal(t, 0, count)
+		return nil
+	})
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/test", nil))
+	require.NoError(t, err, "app.Test(req)")
+	require.Equal(t, StatusOK, resp.StatusCode, "Status code")
+}
+
 // go test -run Test_Ctx_Method
 func Test_Ctx_Method(t *testing.T) {
 	t.Parallel()
@@ -3075,6 +3308,9 @@ func Test_Ctx_Download(t *testing.T) {
 
 	require.NoError(t, c.Download("ctx.go"))
 	require.Equal(t, `attachment; filename="ctx.go"`, string(c.Response().Header.Peek(HeaderContentDisposition)))
+
+	require.NoError(t, c.Download("ctx.go", "Ünïcödé.go"))
+	require.Equal(t, `attachment; filename="%C3%9Cn%C3%AFc%C3%B6d%C3%A9.go"; filename*=UTF-8''%C3%9Cn%C3%AFc%C3%B6d%C3%A9.go`, string(c.Response().Header.Peek(HeaderContentDisposition)))
 }
 
 // go test -race -run Test_Ctx_SendFile
@@ -3350,6 +3586,175 @@ func Test_Ctx_SendFile_EmbedFS(t *testing.T) {
 	require.Equal(t, expectFileContent, body)
 }
 
+// go test -run Test_Ctx_SendFile_ByteRanges
+func Test_Ctx_SendFile_ByteRanges(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/:file", func(c Ctx) error {
+		file := "./.github/testdata/index.html"
+		if c.Params("file") == "big" {
+			file = "./ctx.go"
+		}
+		return c.SendFile(file, SendFile{ByteRange: true})
+	})
+	app.Get("/embed/ctx.go", func(c Ctx) error {
+		return c.SendFile("ctx.go", SendFile{FS: embedFile, ByteRange: true})
+	})
+
+	request := func(t *testing.T, uri, byteRange, ifRange string) (*http.Response, []byte) {
+		t.Helper()
+		req := httptest.NewRequest(MethodGet, uri, nil)
+		req.Header.Set(HeaderRange, byteRange)
+		if ifRange != "" {
+			req.Header.Set(HeaderIfRange, ifRange)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, body
+	}
+
+	for _, tc := range []struct {
+		uri  string
+		path string
+	}{
+		{uri: "/small", path: "./.github/testdata/index.html"},
+		{uri: "/big", path: "./ctx.go"},
+		{uri: "/embed/ctx.go", path: "./ctx.go"},
+	} {
+		t.Run(tc.uri, func(t *testing.T) {
+			t.Parallel()
+			content, err := os.ReadFile(tc.path)
+			require.NoError(t, err)
+			size := strconv.Itoa(len(content))
+
+			// A single range
+			resp, body := request(t, tc.uri, "bytes=0-9", "")
+			require.Equal(t, StatusPartialContent, resp.StatusCode)
+			require.Equal(t, "bytes 0-9/"+size, resp.Header.Get(HeaderContentRange))
+			require.Equal(t, content[:10], body)
+
+			// Multiple ranges
+			resp, body = request(t, tc.uri, "bytes=0-4, 10-14,-5", "")
+			require.Equal(t, StatusPartialContent, resp.StatusCode)
+			require.Equal(t, strconv.Itoa(len(body)), resp.Header.Get(HeaderContentLength))
+			mediaType, params, err := mime.ParseMediaType(resp.Header.Get(HeaderContentType))
+			require.NoError(t, err)
+			require.Equal(t, "multipart/byteranges", mediaType)
+			reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+			for _, expected := range []struct {
+				contentRange string
+				body         []byte
+			}{
+				{contentRange: "bytes 0-4/" + size, body: content[:5]},
+				{contentRange: "bytes 10-14/" + size, body: content[10:15]},
+				{contentRange: fmt.Sprintf("bytes %d-%d/%s", len(content)-5, len(content)-1, size), body: content[len(content)-5:]},
+			} {
+				part, err := reader.NextPart()
+				require.NoError(t, err)
+				require.Equal(t, expected.contentRange, part.Header.Get(HeaderContentRange))
+				require.NotEmpty(t, part.Header.Get(HeaderContentType))
+				partBody, err := io.ReadAll(part)
+				require.NoError(t, err)
+				require.Equal(t, expected.body, partBody)
+			}
+			_, err = reader.NextPart()
+			require.ErrorIs(t, err, io.EOF)
+
+			// Ranges which are larger than the file together
+			resp, body = request(t, tc.uri, "bytes=0-,0-", "")
+			require.Equal(t, StatusOK, resp.StatusCode)
+			require.Equal(t, content, body)
+
+			// Unsatisfiable ranges
+			resp, _ = request(t, tc.uri, "bytes=99999998-99999999,99999999-", "")
+			require.Equal(t, StatusRequestedRangeNotSatisfiable, resp.StatusCode)
+			require.Equal(t, "bytes */"+size, resp.Header.Get(HeaderContentRange))
+
+			// If-Range
+			resp, _ = request(t, tc.uri, "bytes=0-9", "")
+			lastModified := resp.Header.Get(HeaderLastModified)
+			require.NotEmpty(t, lastModified)
+
+			resp, body = request(t, tc.uri, "bytes=0-9", lastModified)
+			require.Equal(t, StatusPartialContent, resp.StatusCode)
+			require.Equal(t, content[:10], body)
+
+			resp, body = request(t, tc.uri, "bytes=0-9", "Mon, 02 Jan 2006 15:04:05 GMT")
+			require.Equal(t, StatusOK, resp.StatusCode)
+			require.Equal(t, content, body)
+
+			resp, body = request(t, tc.uri, "bytes=0-9", `W/"etag"`)
+			require.Equal(t, StatusOK, resp.StatusCode)
+			require.Equal(t, content, body)
+		})
+	}
+}
+
+// go test -run Test_Ctx_SendFile_ETag
+func Test_Ctx_SendFile_ETag(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		return c.SendFile("./.github/testdata/index.html", SendFile{ETag: true, ByteRange: true})
+	})
+	app.Get("/disabled", func(c Ctx) error {
+		return c.SendFile("./.github/testdata/index.html")
+	})
+
+	info, err := os.Stat("./.github/testdata/index.html")
+	require.NoError(t, err)
+	etag := httpcache.FileETag(info.ModTime(), info.Size()).String()
+
+	request := func(t *testing.T, uri string, headers map[string]string) *http.Response {
+		t.Helper()
+		req := httptest.NewRequest(MethodGet, uri, nil)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := request(t, "/", nil)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, etag, resp.Header.Get(HeaderETag))
+	lastModified := resp.Header.Get(HeaderLastModified)
+	require.NotEmpty(t, lastModified)
+
+	// The ETag of ranges is the ETag of the file
+	resp = request(t, "/", map[string]string{HeaderRange: "bytes=0-9"})
+	require.Equal(t, StatusPartialContent, resp.StatusCode)
+	require.Equal(t, etag, resp.Header.Get(HeaderETag))
+	resp = request(t, "/", map[string]string{HeaderRange: "bytes=0-9", HeaderIfRange: etag})
+	require.Equal(t, StatusPartialContent, resp.StatusCode)
+	resp = request(t, "/", map[string]string{HeaderRange: "bytes=0-9", HeaderIfRange: `"other"`})
+	require.Equal(t, StatusOK, resp.StatusCode)
+
+	resp = request(t, "/", map[string]string{HeaderIfNoneMatch: `"other", W/` + etag})
+	require.Equal(t, StatusNotModified, resp.StatusCode)
+	require.Equal(t, etag, resp.Header.Get(HeaderETag))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Empty(t, body)
+
+	resp = request(t, "/", map[string]string{HeaderIfNoneMatch: etag, HeaderRange: "bytes=0-9"})
+	require.Equal(t, StatusNotModified, resp.StatusCode)
+	require.Empty(t, resp.Header.Get(HeaderContentRange))
+
+	// If-None-Match takes precedence over If-Modified-Since
+	resp = request(t, "/", map[string]string{HeaderIfNoneMatch: `"other"`, HeaderIfModifiedSince: lastModified})
+	require.Equal(t, StatusOK, resp.StatusCode)
+	resp = request(t, "/", map[string]string{HeaderIfModifiedSince: lastModified})
+	require.Equal(t, StatusNotModified, resp.StatusCode)
+
+	resp = request(t, "/disabled", map[string]string{HeaderIfNoneMatch: etag})
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Empty(t, resp.Header.Get(HeaderETag))
+}
+
 // go test -race -run Test_Ctx_SendFile_404
 func Test_Ctx_SendFile_404(t *testing.T) {
 	t.Parallel()
@@ -3619,6 +4024,62 @@ func Benchmark_Ctx_JSON(b *testing.B) {
 	require.JSONEq(b, `{"Name":"Grame","Age":20}`, string(c.Response().Body()))
 }
 
+// go test -run Test_Ctx_Protobuf
+func Test_Ctx_Protobuf(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	require.NoError(t, c.Protobuf(&protoUser{Name: "john"}))
+	require.Equal(t, "\x0a\x04john", string(c.Response().Body()))
+	require.Equal(t, MIMEApplicationProtobuf, string(c.Response().Header.Peek(HeaderContentType)))
+
+	require.NoError(t, c.Protobuf(&protoUser{Name: "doe"}, "application/vnd.users+protobuf"))
+	require.Equal(t, "\x0a\x03doe", string(c.Response().Body()))
+	require.Equal(t, "application/vnd.users+protobuf", string(c.Response().Header.Peek(HeaderContentType)))
+
+	require.Error(t, c.Protobuf(&protoUser{Name: strings.Repeat("a", 128)}))
+	require.ErrorIs(t, c.Protobuf(Map{"name": "john"}), ErrProtobufMarshaler)
+
+	// Pluggable encoder, e.g. proto.Marshal of google.golang.org/protobuf
+	app = New(Config{ProtobufEncoder: func(v any) ([]byte, error) {
+		return []byte(fmt.Sprint(v)), nil
+	}})
+	c = app.AcquireCtx(&fasthttp.RequestCtx{})
+	require.NoError(t, c.Protobuf("message"))
+	require.Equal(t, "message", string(c.Response().Body()))
+}
+
+// go test -run Test_Ctx_MsgPack
+func Test_Ctx_MsgPack(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	msg := &redirectionMsg{key: "name", value: "john"}
+	raw, err := msg.MarshalMsg(nil)
+	require.NoError(t, err)
+	require.NoError(t, c.MsgPack(msg))
+	require.Equal(t, raw, c.Response().Body())
+	require.Equal(t, MIMEApplicationMsgPack, string(c.Response().Header.Peek(HeaderContentType)))
+
+	require.NoError(t, c.MsgPack(Map{"name": "doe"}, "application/vnd.users+msgpack"))
+	out, _, err := msgp.ReadMapStrIntfBytes(c.Response().Body(), nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "doe"}, out)
+	require.Equal(t, "application/vnd.users+msgpack", string(c.Response().Header.Peek(HeaderContentType)))
+
+	require.Error(t, c.MsgPack(make(chan int)))
+
+	// Pluggable encoder, e.g. msgpack.Marshal of github.com/vmihailenco/msgpack
+	app = New(Config{MsgPackEncoder: func(v any) ([]byte, error) {
+		return []byte(fmt.Sprint(v)), nil
+	}})
+	c = app.AcquireCtx(&fasthttp.RequestCtx{})
+	require.NoError(t, c.MsgPack("message"))
+	require.Equal(t, "message", string(c.Response().Body()))
+}
+
 // go test -run Test_Ctx_CBOR
 func Test_Ctx_CBOR(t *testing.T) {
 	t.Parallel()
@@ -3735,6 +4196,80 @@ func Benchmark_Ctx_JSON_Ctype(b *testing.B) {
 	require.Equal(b, "application/problem+json", string(c.Response().Header.Peek("content-type")))
 }
 
+// go test -run Test_Ctx_JSONStream
+func Test_Ctx_JSONStream(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	type item struct {
+		Name string `json:"name"`
+		ID   int    `json:"id"`
+	}
+	items := make([]item, 1000)
+	for i := range items {
+		items[i] = item{ID: i, Name: "item"}
+	}
+	expected, err := json.Marshal(items)
+	require.NoError(t, err)
+
+	require.NoError(t, c.JSONStream(items))
+	require.True(t, c.Response().IsBodyStream())
+	require.Equal(t, MIMEApplicationJSON, string(c.Response().Header.Peek(HeaderContentType)))
+	require.JSONEq(t, string(expected), string(c.Response().Body()))
+
+	require.NoError(t, c.JSONStream(Map{"a": 1}, "application/problem+json"))
+	require.Equal(t, "application/problem+json", string(c.Response().Header.Peek(HeaderContentType)))
+	require.Equal(t, "{\"a\":1}\n", string(c.Response().Body()))
+
+	// Custom encoder, whose errors are only logged
+	app = New(Config{
+		JSONStreamEncoder: func(w io.Writer, v any) error {
+			if _, err := io.WriteString(w, "custom"); err != nil {
+				return err
+			}
+			return errors.New("stream broken")
+		},
+	})
+	app.Get("/", func(c Ctx) error {
+		return c.JSONStream(items)
+	})
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, []string{"chunked"}, resp.TransferEncoding)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "custom", string(body))
+}
+
+// go test -run Test_Ctx_CSV
+func Test_Ctx_CSV(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	rows := [][]string{{"1", "john"}, {"2", "doe, \"jr\""}, {"3", "line\nbreak"}}
+	require.NoError(t, c.CSV([]string{"id", "name"}, slices.Values(rows)))
+	require.True(t, c.Response().IsBodyStream())
+	require.Equal(t, MIMETextCSVCharsetUTF8, string(c.Response().Header.Peek(HeaderContentType)))
+	require.Empty(t, c.Response().Header.Peek(HeaderContentDisposition))
+	require.Equal(t, "id,name\n1,john\n2,\"doe, \"\"jr\"\"\"\n3,\"line\nbreak\"\n", string(c.Response().Body()))
+
+	require.NoError(t, c.CSV(nil, slices.Values(rows[:1]), CSVConfig{
+		Filename: "./reports/Bericht März.csv",
+		Comma:    ';',
+		BOM:      true,
+		UseCRLF:  true,
+	}))
+	require.Equal(t, `attachment; filename="Bericht+M%C3%A4rz.csv"; filename*=UTF-8''Bericht%20M%C3%A4rz.csv`, string(c.Response().Header.Peek(HeaderContentDisposition)))
+	require.Equal(t, "\xEF\xBB\xBF1;john\r\n", string(c.Response().Body()))
+
+	// The iteration stops at the first error, which is only logged
+	require.NoError(t, c.CSV(nil, slices.Values(rows), CSVConfig{Comma: '"'}))
+	require.Empty(t, c.Response().Body())
+}
+
 // go test -run Test_Ctx_JSONP
 func Test_Ctx_JSONP(t *testing.T) {
 	t.Parallel()
@@ -4388,6 +4923,49 @@ func Test_Ctx_Get_Location_From_Route_name(t *testing.T) {
 	})
 }
 
+// go test -run Test_Ctx_GetRouteURL_Queries
+func Test_Ctx_GetRouteURL_Queries(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	app.Get("/user/:id", func(c Ctx) error {
+		return c.SendString(c.Params("id"))
+	}).Name("user.show")
+
+	location, err := c.GetRouteURL("user.show", Map{"id": 42}, Map{"tab": "posts", "page": 2, "tag": []string{"go", "web"}})
+	require.NoError(t, err)
+	require.Equal(t, "/user/42?page=2&tab=posts&tag=go&tag=web", location)
+
+	location, err = c.GetRouteURL("user.show", Map{"id": 42}, Map{"q": "a b&c"})
+	require.NoError(t, err)
+	require.Equal(t, "/user/42?q=a+b%26c", location)
+
+	location, err = c.GetRouteURL("user.show", Map{"id": 42}, Map{})
+	require.NoError(t, err)
+	require.Equal(t, "/user/42", location)
+}
+
+// go test -run Test_Ctx_GetRouteURL_NotFound
+func Test_Ctx_GetRouteURL_NotFound(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	app.Get("/user/:id", func(c Ctx) error {
+		return c.SendString(c.Params("id"))
+	}).Name("user.show")
+
+	location, err := c.GetRouteURL("user.edit", Map{"id": 42})
+	require.ErrorIs(t, err, ErrRouteNotFound)
+	require.Empty(t, location)
+
+	require.ErrorIs(t, c.Redirect().Route("user.edit"), ErrRouteNotFound)
+	require.Empty(t, c.GetRespHeader(HeaderLocation))
+}
+
 // go test -run Test_Ctx_Get_Location_From_Route_name_Optional_greedy
 func Test_Ctx_Get_Location_From_Route_name_Optional_greedy(t *testing.T) {
 	t.Parallel()
@@ -4469,6 +5047,125 @@ func Test_Ctx_Render_Go_Template(t *testing.T) {
 	require.Equal(t, "template", string(c.Response().Body()))
 }
 
+type flushTemplateEngine struct {
+	testTemplateEngine
+	flushes int
+}
+
+func (t *flushTemplateEngine) Render(w io.Writer, name string, bind any, layout ...string) error {
+	if err := t.templates.ExecuteTemplate(w, name, bind); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	flusher, ok := w.(interface{ Flush() error })
+	if !ok {
+		return errors.New("writer does not support flushing")
+	}
+	if err := flusher.Flush(); err != nil {
+		return fmt.Errorf("failed to flush: %w", err)
+	}
+	t.flushes++
+	if len(layout) > 0 {
+		if err := t.templates.ExecuteTemplate(w, layout[0], bind); err != nil {
+			return fmt.Errorf("failed to execute template with layout: %w", err)
+		}
+	}
+	return nil
+}
+
+// go test -run Test_Ctx_RenderStream
+func Test_Ctx_RenderStream(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	err := c.RenderStream("./.github/testdata/index.tmpl", Map{
+		"Title": "Hello, World!",
+	})
+	require.NoError(t, err)
+	require.Equal(t, MIMETextHTMLCharsetUTF8, string(c.Response().Header.ContentType()))
+	require.True(t, c.Response().IsBodyStream())
+	require.Equal(t, "<h1>Hello, World!</h1>", string(c.Response().Body()))
+
+	err = c.RenderStream("./.github/testdata/template-non-exists.html", nil)
+	require.Error(t, err)
+
+	err = c.RenderStream("./.github/testdata/template-invalid.html", nil)
+	require.Error(t, err)
+}
+
+// go test -run Test_Ctx_RenderStream_Engine
+func Test_Ctx_RenderStream_Engine(t *testing.T) {
+	t.Parallel()
+	engine := &flushTemplateEngine{}
+	require.NoError(t, engine.Load())
+	app := New(Config{
+		Views:             engine,
+		ViewsLayout:       "main.tmpl",
+		PassLocalsToViews: true,
+	})
+	app.Get("/", func(c Ctx) error {
+		c.Locals("Title", "Hello, World!")
+		return c.RenderStream("index.tmpl", nil)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, MIMETextHTMLCharsetUTF8, resp.Header.Get(HeaderContentType))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "<h1>Hello, World!</h1><h1>I'm main</h1>", string(body))
+	require.Equal(t, 1, engine.flushes)
+}
+
+// go test -run Test_Ctx_RenderStream_Engine_Error
+func Test_Ctx_RenderStream_Engine_Error(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.config.Views = errorTemplateEngine{}
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	// Errors of the engine happen while streaming and can not be returned anymore
+	err := c.RenderStream("index.tmpl", nil)
+	require.NoError(t, err)
+	require.Empty(t, c.Response().Body())
+}
+
+// go test -run Test_Ctx_Render_ViewGlobals
+func Test_Ctx_Render_ViewGlobals(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		PassLocalsToViews: true,
+	})
+	app.SetViewGlobal("Title", "Global")
+	app.SetViewGlobal("Dynamic", func(c Ctx) any {
+		return c.Locals("user")
+	})
+
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	bind := Map{}
+	err := c.Render("./.github/testdata/index.tmpl", bind)
+	require.NoError(t, err)
+	require.Equal(t, "<h1>Global</h1>", string(c.Response().Body()))
+
+	// Locals take precedence over globals
+	c.Locals("Title", "Local")
+	c.Locals("user", "john")
+	bind = Map{}
+	err = c.Render("./.github/testdata/index.tmpl", bind)
+	require.NoError(t, err)
+	require.Equal(t, "<h1>Local</h1>", string(c.Response().Body()))
+	require.Equal(t, "john", bind["Dynamic"])
+
+	// Bind values take precedence over everything
+	err = c.Render("./.github/testdata/index.tmpl", Map{"Title": "Bind"})
+	require.NoError(t, err)
+	require.Equal(t, "<h1>Bind</h1>", string(c.Response().Body()))
+}
+
 // go test -run Test_Ctx_Send
 func Test_Ctx_Send(t *testing.T) {
 	t.Parallel()
@@ -4498,6 +5195,131 @@ func Benchmark_Ctx_Send(b *testing.B) {
 	require.Equal(b, "Hello, World!", string(c.Response().Body()))
 }
 
+// go test -run Test_Ctx_SendEarlyHints
+func Test_Ctx_SendEarlyHints(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		if err := c.SendEarlyHints([]string{"</style.css>; rel=preload; as=style", "</app.js>; rel=preload; as=script"}); err != nil {
+			return err
+		}
+		return c.SendString("page")
+	})
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		assert.NoError(t, app.Listener(ln, ListenConfig{DisableStartupMessage: true}))
+	}()
+	defer func() {
+		require.NoError(t, app.Shutdown())
+	}()
+
+	conn, err := ln.Dial()
+	require.NoError(t, err)
+	defer conn.Close() //nolint:errcheck // It is fine to ignore the error here
+
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+	require.NoError(t, err)
+	br := bufio.NewReader(conn)
+
+	hints, err := http.ReadResponse(br, nil)
+	require.NoError(t, err)
+	require.Equal(t, StatusEarlyHints, hints.StatusCode)
+	require.Equal(t, []string{"</style.css>; rel=preload; as=style", "</app.js>; rel=preload; as=script"}, hints.Header.Values(HeaderLink))
+
+	resp, err := http.ReadResponse(br, nil)
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck // It is fine to ignore the error here
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, "</style.css>; rel=preload; as=style, </app.js>; rel=preload; as=script", resp.Header.Get(HeaderLink))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "page", string(body))
+
+	// HTTP/1.0 clients only get the final response
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	c.Request().Header.SetProtocol("HTTP/1.0")
+	require.NoError(t, c.SendEarlyHints([]string{"</style.css>; rel=preload"}))
+	require.Equal(t, "</style.css>; rel=preload", string(c.Response().Header.Peek(HeaderLink)))
+	require.NoError(t, c.SendEarlyHints(nil))
+	require.ErrorIs(t, c.SendEarlyHints([]string{"</a.css>\r\nSet-Cookie: a=b"}), ErrInvalidEarlyHint)
+}
+
+func Test_Ctx_SendEarlyHints_Pipelined(t *testing.T) {
+	t.Parallel()
+
+	readResponses := func(t *testing.T, app *App) []*http.Response {
+		t.Helper()
+		app.Get("/plain", func(c Ctx) error {
+			return c.SendString("plain")
+		})
+		app.Get("/", func(c Ctx) error {
+			if err := c.SendEarlyHints([]string{"</style.css>; rel=preload; as=style"}); err != nil {
+				return err
+			}
+			return c.SendString("page")
+		})
+
+		ln := fasthttputil.NewInmemoryListener()
+		go func() {
+			assert.NoError(t, app.Listener(ln, ListenConfig{DisableStartupMessage: true}))
+		}()
+		t.Cleanup(func() {
+			require.NoError(t, app.Shutdown())
+		})
+
+		conn, err := ln.Dial()
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, conn.Close())
+		})
+
+		// Both requests are read at once, so the first response is still buffered
+		// while the handler of the second request runs
+		_, err = conn.Write([]byte("GET /plain HTTP/1.1\r\nHost: example.com\r\n\r\n" +
+			"GET / HTTP/1.1\r\nHost: example.com\r\nConnection: close\r\n\r\n"))
+		require.NoError(t, err)
+
+		var responses []*http.Response
+		br := bufio.NewReader(conn)
+		for {
+			resp, err := http.ReadResponse(br, nil)
+			require.NoError(t, err)
+			responses = append(responses, resp)
+			if resp.StatusCode == StatusEarlyHints {
+				continue
+			}
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			resp.Header.Set("X-Body", string(body))
+			if resp.Close {
+				// The connection is closed after the final response of the second request
+				_, err = br.ReadByte()
+				require.ErrorIs(t, err, io.EOF)
+				return responses
+			}
+		}
+	}
+
+	// The 103 response isn't written before the buffered response
+	responses := readResponses(t, New())
+	require.Len(t, responses, 2)
+	require.Equal(t, StatusOK, responses[0].StatusCode)
+	require.Equal(t, "plain", responses[0].Header.Get("X-Body"))
+	require.Equal(t, StatusOK, responses[1].StatusCode)
+	require.Equal(t, "page", responses[1].Header.Get("X-Body"))
+	require.Equal(t, "</style.css>; rel=preload; as=style", responses[1].Header.Get(HeaderLink))
+
+	// With ReduceMemoryUsage, every response is flushed, so the 103 response follows the first one
+	responses = readResponses(t, New(Config{ReduceMemoryUsage: true}))
+	require.Len(t, responses, 3)
+	require.Equal(t, "plain", responses[0].Header.Get("X-Body"))
+	require.Equal(t, StatusEarlyHints, responses[1].StatusCode)
+	require.Equal(t, "</style.css>; rel=preload; as=style", responses[1].Header.Get(HeaderLink))
+	require.Equal(t, "page", responses[2].Header.Get("X-Body"))
+}
+
 // go test -run Test_Ctx_SendStatus
 func Test_Ctx_SendStatus(t *testing.T) {
 	t.Parallel()
@@ -4620,6 +5442,43 @@ func Test_Ctx_Set(t *testing.T) {
 	require.Equal(t, "1337", string(c.Response().Header.Peek("x-3")))
 }
 
+// go test -run Test_Ctx_SetTrailer
+func Test_Ctx_SetTrailer(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		require.NoError(t, c.SetTrailer("X-Checksum", ""))
+		require.NoError(t, c.SetTrailer("X-Lines", "5"))
+		require.NoError(t, c.SetTrailer("x-lines", "3"))
+		require.ErrorIs(t, c.SetTrailer(HeaderContentLength, "1"), fasthttp.ErrBadTrailer)
+		require.ErrorIs(t, c.SetTrailerFunc(HeaderContentLength, func() string { return "1" }), fasthttp.ErrBadTrailer)
+
+		// The values of the stream writer are set once the body is written
+		var lines int
+		require.NoError(t, c.SetTrailerFunc("X-Checksum", func() string {
+			return "lines-" + strconv.Itoa(lines)
+		}))
+		err := c.SendStreamWriter(func(w *bufio.Writer) {
+			for lineNum := 1; lineNum <= 3; lineNum++ {
+				fmt.Fprintf(w, "Line %d\n", lineNum) //nolint:errcheck // It is fine to ignore the error
+				lines++
+			}
+		})
+		require.NoError(t, c.SetTrailerFunc("X-Done", func() string { return "true" }))
+		return err
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, []string{"chunked"}, resp.TransferEncoding)
+	require.Empty(t, resp.Header.Get("X-Lines"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "Line 1\nLine 2\nLine 3\n", string(body))
+	require.Equal(t, http.Header{"X-Checksum": {"lines-3"}, "X-Done": {"true"}, "X-Lines": {"3"}}, resp.Trailer)
+}
+
 // go test -run Test_Ctx_Set_Splitter
 func Test_Ctx_Set_Splitter(t *testing.T) {
 	t.Parallel()
@@ -5100,6 +5959,46 @@ func Test_Ctx_IsFromLocal_RemoteAddr(t *testing.T) {
 	}
 }
 
+// go test -run Test_Ctx_IsFromLocal_TrustedProxy
+func Test_Ctx_IsFromLocal_TrustedProxy(t *testing.T) {
+	t.Parallel()
+
+	app := New(Config{
+		TrustProxy:       true,
+		TrustProxyConfig: TrustProxyConfig{Loopback: true},
+		ProxyHeader:      HeaderXForwardedFor,
+	})
+	proxyAddr := net.Addr(&net.TCPAddr{IP: net.ParseIP("127.0.0.1")})
+	remoteAddr := net.Addr(&net.TCPAddr{IP: net.ParseIP("93.46.8.90")})
+
+	testCases := []struct {
+		remoteAddr net.Addr
+		header     string
+		local      bool
+	}{
+		// The proxy forwards the requests of other hosts
+		{remoteAddr: proxyAddr, header: "93.46.8.90", local: false},
+		{remoteAddr: proxyAddr, header: "127.0.0.1, 93.46.8.90", local: false},
+		{remoteAddr: proxyAddr, header: "127.0.0.1", local: true},
+		{remoteAddr: proxyAddr, header: "93.46.8.90, ::1", local: true},
+		{remoteAddr: proxyAddr, header: "invalid", local: false},
+		// Requests to the proxy itself
+		{remoteAddr: proxyAddr, local: true},
+		// Headers of untrusted hosts are ignored
+		{remoteAddr: remoteAddr, header: "127.0.0.1", local: false},
+	}
+	for _, tc := range testCases {
+		fastCtx := &fasthttp.RequestCtx{}
+		fastCtx.SetRemoteAddr(tc.remoteAddr)
+		c := app.AcquireCtx(fastCtx)
+		if tc.header != "" {
+			c.Request().Header.Set(HeaderXForwardedFor, tc.header)
+		}
+		require.Equal(t, tc.local, c.IsFromLocal(), tc.remoteAddr.String()+" "+tc.header)
+		app.ReleaseCtx(c)
+	}
+}
+
 // go test -run Test_Ctx_extractIPsFromHeader -v
 func Test_Ctx_extractIPsFromHeader(t *testing.T) {
 	app := New()
@@ -5164,6 +6063,27 @@ func Benchmark_Ctx_GetRespHeaders(b *testing.B) {
 	}, headers)
 }
 
+// go test -run Test_Ctx_GetReqTrailers
+func Test_Ctx_GetReqTrailers(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Post("/", func(c Ctx) error {
+		require.Equal(t, "hello", string(c.Body()))
+		require.Equal(t, map[string][]string{"X-Checksum": {"abc"}}, c.GetReqTrailers())
+		return nil
+	})
+
+	fctx := &fasthttp.RequestCtx{}
+	raw := "POST / HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: chunked\r\nTrailer: X-Checksum\r\n\r\n" +
+		"3\r\nhel\r\n2\r\nlo\r\n0\r\nX-Checksum: abc\r\n\r\n"
+	require.NoError(t, fctx.Request.Read(bufio.NewReader(strings.NewReader(raw))))
+	app.Handler()(fctx)
+	require.Equal(t, StatusOK, fctx.Response.StatusCode())
+
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	require.Empty(t, c.GetReqTrailers())
+}
+
 // go test -run Test_Ctx_GetReqHeaders
 func Test_Ctx_GetReqHeaders(t *testing.T) {
 	t.Parallel()
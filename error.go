@@ -25,19 +25,88 @@ var (
 	ErrRedirectBackNoFallback = NewError(StatusInternalServerError, "Referer not found, you have to enter fallback URL for redirection.")
 )
 
+// StatusCoder is implemented by errors which know the status code of their response.
+// The DefaultErrorHandler responds with the status code of these errors.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// Route errors
+var (
+	// ErrRouteNotFound is returned when no route with the name is registered.
+	ErrRouteNotFound = errors.New("route: no route with the name was found")
+)
+
+// Plugin errors
+var (
+	// ErrPluginRegistered is returned when a plugin with the same name is already registered.
+	ErrPluginRegistered = errors.New("plugin: already registered")
+	// ErrPluginDependency is returned when a dependency of a plugin isn't registered.
+	ErrPluginDependency = errors.New("plugin: missing dependency")
+	// ErrPluginCycle is returned when plugins depend on each other.
+	ErrPluginCycle = errors.New("plugin: dependency cycle")
+)
+
+// ErrInvalidConfigUpdate is returned by App.ReloadConfig when the update is invalid.
+var ErrInvalidConfigUpdate = errors.New("reload: invalid config update")
+
 // Range errors
 var (
 	ErrRangeMalformed     = errors.New("range: malformed range header string")
 	ErrRangeUnsatisfiable = errors.New("range: unsatisfiable range")
 )
 
+// Early hints errors
+var (
+	// ErrInvalidEarlyHint is returned by c.SendEarlyHints for links with line breaks.
+	ErrInvalidEarlyHint = errors.New("early hints: link must not contain line breaks")
+)
+
+// Protobuf errors
+var (
+	// ErrProtobufMarshaler is returned by the default ProtobufEncoder for values without a Marshal method.
+	ErrProtobufMarshaler = errors.New("protobuf: value doesn't implement Marshal() ([]byte, error), please set Config.ProtobufEncoder")
+	// ErrProtobufUnmarshaler is returned by the default ProtobufDecoder for values without an Unmarshal method.
+	ErrProtobufUnmarshaler = errors.New("protobuf: value doesn't implement Unmarshal([]byte) error, please set Config.ProtobufDecoder")
+)
+
+// MsgPack errors
+var (
+	// ErrMsgPackUnmarshaler is returned by the default MsgPackDecoder for values without an UnmarshalMsg method.
+	ErrMsgPackUnmarshaler = errors.New("msgpack: value doesn't implement msgp.Unmarshaler, please set Config.MsgPackDecoder")
+)
+
 // Binder errors
 var ErrCustomBinderNotFound = errors.New("binder: custom binder not found, please be sure to enter the right name")
 
+// ValidationError is returned by the Bind methods when the StructValidator rejects a struct.
+// The DefaultErrorHandler responds with 422 Unprocessable Entity and the message of the
+// validator, whose error, e.g. validator.ValidationErrors, is found by errors.As.
+type ValidationError struct {
+	Err error
+}
+
+// Error returns the message of the validator.
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error of the validator.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns 422 Unprocessable Entity.
+func (*ValidationError) StatusCode() int {
+	return StatusUnprocessableEntity
+}
+
 // Format errors
 var (
 	// ErrNoHandlers is returned when c.Format is called with no arguments.
 	ErrNoHandlers = errors.New("format: at least one handler is required, but none were set")
+	// ErrBodyEncoderNotFound is returned by c.Negotiate for offered encoders which aren't registered.
+	ErrBodyEncoderNotFound = errors.New("negotiate: body encoder not found, please register it with app.RegisterBodyEncoder")
 )
 
 // gorilla/schema errors
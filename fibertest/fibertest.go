@@ -0,0 +1,369 @@
+// Package fibertest provides a fluent request builder and response assertions for
+// tests of Fiber apps. Requests are sent with app.Test, and the cookies of the
+// responses are stored in a cookie jar and sent with the following requests of the
+// same client.
+//
+//	client := fibertest.New(t, app)
+//	client.Post("/login").JSON(fiber.Map{"user": "john"}).Do().Status(fiber.StatusOK)
+//	client.Get("/profile").Do().Status(fiber.StatusOK).JSONEq(`{"user":"john"}`)
+package fibertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// jarURL is the URL of the cookies in the jar, it is secure so cookies with the
+// Secure attribute are sent as well
+var jarURL = &url.URL{Scheme: "https", Host: "example.com", Path: "/"}
+
+// Client sends requests to an app and stores the cookies of the responses
+type Client struct {
+	t   TestingT
+	app *fiber.App
+	jar *cookiejar.Jar
+}
+
+// TestingT is the subset of testing.TB used by the package
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
+}
+
+// New creates a new client, which reports failures to t
+func New(t TestingT, app *fiber.App) *Client {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		// cookiejar.New never fails without options
+		panic(fmt.Errorf("fibertest: failed to create cookie jar: %w", err))
+	}
+	return &Client{t: t, app: app, jar: jar}
+}
+
+// Cookies returns the cookies of the jar
+func (c *Client) Cookies() []*http.Cookie {
+	return c.jar.Cookies(jarURL)
+}
+
+// Get creates a GET request
+func (c *Client) Get(path string) *Request {
+	return c.Request(fiber.MethodGet, path)
+}
+
+// Head creates a HEAD request
+func (c *Client) Head(path string) *Request {
+	return c.Request(fiber.MethodHead, path)
+}
+
+// Post creates a POST request
+func (c *Client) Post(path string) *Request {
+	return c.Request(fiber.MethodPost, path)
+}
+
+// Put creates a PUT request
+func (c *Client) Put(path string) *Request {
+	return c.Request(fiber.MethodPut, path)
+}
+
+// Patch creates a PATCH request
+func (c *Client) Patch(path string) *Request {
+	return c.Request(fiber.MethodPatch, path)
+}
+
+// Delete creates a DELETE request
+func (c *Client) Delete(path string) *Request {
+	return c.Request(fiber.MethodDelete, path)
+}
+
+// Options creates an OPTIONS request
+func (c *Client) Options(path string) *Request {
+	return c.Request(fiber.MethodOptions, path)
+}
+
+// Request creates a request with the method and path, which may contain a query
+func (c *Client) Request(method, path string) *Request {
+	return &Request{
+		client: c,
+		method: method,
+		path:   path,
+		header: make(http.Header),
+		query:  make(url.Values),
+		config: fiber.TestConfig{Timeout: time.Second, FailOnTimeout: true},
+	}
+}
+
+// Request is a request which is built with its methods and sent with Do
+type Request struct {
+	client  *Client
+	header  http.Header
+	query   url.Values
+	body    io.Reader
+	err     error
+	method  string
+	path    string
+	cookies []*http.Cookie
+	config  fiber.TestConfig
+}
+
+// Header sets a header of the request
+func (r *Request) Header(key, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+// Query adds a query parameter to the request
+func (r *Request) Query(key, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+// Cookie adds a cookie to the request, in addition to the cookies of the jar
+func (r *Request) Cookie(name, value string) *Request {
+	r.cookies = append(r.cookies, &http.Cookie{Name: name, Value: value})
+	return r
+}
+
+// BasicAuth sets the Authorization header with the credentials of basic authentication
+func (r *Request) BasicAuth(username, password string) *Request {
+	req := http.Request{Header: make(http.Header)}
+	req.SetBasicAuth(username, password)
+	return r.Header(fiber.HeaderAuthorization, req.Header.Get(fiber.HeaderAuthorization))
+}
+
+// BearerToken sets the Authorization header with the bearer token
+func (r *Request) BearerToken(token string) *Request {
+	return r.Header(fiber.HeaderAuthorization, "Bearer "+token)
+}
+
+// Timeout sets the timeout of app.Test, 0 disables it
+func (r *Request) Timeout(timeout time.Duration) *Request {
+	r.config.Timeout = timeout
+	return r
+}
+
+// Body sets the body and the content type of the request
+func (r *Request) Body(contentType string, body []byte) *Request {
+	r.body = bytes.NewReader(body)
+	return r.Header(fiber.HeaderContentType, contentType)
+}
+
+// JSON sets the JSON encoding of v with the JSONEncoder of the app as body of the request
+func (r *Request) JSON(v any) *Request {
+	data, err := r.client.app.Config().JSONEncoder(v)
+	if err != nil {
+		r.err = fmt.Errorf("failed to encode JSON body: %w", err)
+		return r
+	}
+	return r.Body(fiber.MIMEApplicationJSON, data)
+}
+
+// Form sets the URL encoded form values as body of the request
+func (r *Request) Form(values map[string]string) *Request {
+	form := make(url.Values, len(values))
+	for key, value := range values {
+		form.Set(key, value)
+	}
+	return r.Body(fiber.MIMEApplicationForm, []byte(form.Encode()))
+}
+
+// File is a file of a multipart request
+type File struct {
+	Field    string // Name of the form field
+	Name     string // Filename
+	Content  []byte
+	MimeType string // Optional, application/octet-stream if it is empty
+}
+
+// Multipart sets the multipart form of the fields and files as body of the request
+func (r *Request) Multipart(fields map[string]string, files ...File) *Request {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for key, value := range fields {
+		if err := w.WriteField(key, value); err != nil {
+			r.err = fmt.Errorf("failed to write multipart field: %w", err)
+			return r
+		}
+	}
+	for _, file := range files {
+		header := make(map[string][]string)
+		header[fiber.HeaderContentDisposition] = []string{fmt.Sprintf(`form-data; name=%q; filename=%q`, file.Field, file.Name)}
+		mimeType := file.MimeType
+		if mimeType == "" {
+			mimeType = fiber.MIMEOctetStream
+		}
+		header[fiber.HeaderContentType] = []string{mimeType}
+		part, err := w.CreatePart(header)
+		if err == nil {
+			_, err = part.Write(file.Content)
+		}
+		if err != nil {
+			r.err = fmt.Errorf("failed to write multipart file: %w", err)
+			return r
+		}
+	}
+	if err := w.Close(); err != nil {
+		r.err = fmt.Errorf("failed to write multipart form: %w", err)
+		return r
+	}
+	return r.Body(w.FormDataContentType(), buf.Bytes())
+}
+
+// Do sends the request with app.Test and returns the response. The test fails
+// immediately if the request can't be sent.
+func (r *Request) Do() *Response {
+	t := r.client.t
+	t.Helper()
+	if r.err != nil {
+		t.Fatalf("fibertest: %s %s: %v", r.method, r.path, r.err)
+	}
+
+	target := r.path
+	if len(r.query) > 0 {
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		target += sep + r.query.Encode()
+	}
+	req := httptest.NewRequest(r.method, target, r.body)
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	for _, cookie := range r.client.jar.Cookies(jarURL.ResolveReference(req.URL)) {
+		req.AddCookie(cookie)
+	}
+	for _, cookie := range r.cookies {
+		req.AddCookie(cookie)
+	}
+
+	resp, err := r.client.app.Test(req, r.config)
+	if err != nil {
+		t.Fatalf("fibertest: %s %s: %v", r.method, target, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // The body is read completely
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("fibertest: %s %s: failed to read body: %v", r.method, target, err)
+	}
+	r.client.jar.SetCookies(jarURL.ResolveReference(req.URL), resp.Cookies())
+
+	return &Response{
+		Response: resp,
+		Body:     body,
+		t:        t,
+		app:      r.client.app,
+		request:  r.method + " " + target,
+	}
+}
+
+// Response is a response with assertions, which report failures without stopping the test
+type Response struct {
+	*http.Response
+	t       TestingT
+	app     *fiber.App
+	request string
+	Body    []byte // Body is the read body of the response
+}
+
+func (r *Response) errorf(format string, args ...any) {
+	r.t.Helper()
+	r.t.Errorf("fibertest: %s: "+format, append([]any{r.request}, args...)...)
+}
+
+// Status asserts the status code of the response
+func (r *Response) Status(code int) *Response {
+	r.t.Helper()
+	if r.StatusCode != code {
+		r.errorf("expected status %d, got %d", code, r.StatusCode)
+	}
+	return r
+}
+
+// HeaderEq asserts the value of a header of the response
+func (r *Response) HeaderEq(key, value string) *Response {
+	r.t.Helper()
+	if actual := r.Header.Get(key); actual != value {
+		r.errorf("expected header %s %q, got %q", key, value, actual)
+	}
+	return r
+}
+
+// HeaderContains asserts that a header of the response contains the value
+func (r *Response) HeaderContains(key, value string) *Response {
+	r.t.Helper()
+	if actual := r.Header.Get(key); !strings.Contains(actual, value) {
+		r.errorf("expected header %s %q to contain %q", key, actual, value)
+	}
+	return r
+}
+
+// BodyEq asserts the body of the response
+func (r *Response) BodyEq(body string) *Response {
+	r.t.Helper()
+	if string(r.Body) != body {
+		r.errorf("expected body %q, got %q", body, r.Body)
+	}
+	return r
+}
+
+// BodyContains asserts that the body of the response contains the value
+func (r *Response) BodyContains(value string) *Response {
+	r.t.Helper()
+	if !bytes.Contains(r.Body, []byte(value)) {
+		r.errorf("expected body %q to contain %q", r.Body, value)
+	}
+	return r
+}
+
+// JSONEq asserts that the body of the response is semantically equal to the JSON
+func (r *Response) JSONEq(expected string) *Response {
+	r.t.Helper()
+	var want, got any
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		r.errorf("invalid expected JSON %q: %v", expected, err)
+		return r
+	}
+	if err := json.Unmarshal(r.Body, &got); err != nil {
+		r.errorf("expected JSON body, got %q: %v", r.Body, err)
+		return r
+	}
+	if !reflect.DeepEqual(want, got) {
+		r.errorf("expected JSON body %s, got %s", expected, r.Body)
+	}
+	return r
+}
+
+// DecodeJSON decodes the JSON body of the response into v with the JSONDecoder of the app
+func (r *Response) DecodeJSON(v any) *Response {
+	r.t.Helper()
+	if err := r.app.Config().JSONDecoder(r.Body, v); err != nil {
+		r.errorf("failed to decode JSON body %q: %v", r.Body, err)
+	}
+	return r
+}
+
+// Cookie returns the cookie of the response with the name, or nil and a failure if it
+// isn't set
+func (r *Response) Cookie(name string) *http.Cookie {
+	r.t.Helper()
+	for _, cookie := range r.Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	r.errorf("expected cookie %q", name)
+	return nil
+}
@@ -0,0 +1,132 @@
+package fibertest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// mockT records the failures of the assertions
+type mockT struct {
+	errors []string
+}
+
+func (*mockT) Helper() {}
+
+func (m *mockT) Errorf(format string, args ...any) {
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
+
+func (m *mockT) Fatalf(format string, args ...any) {
+	panic(fmt.Sprintf(format, args...))
+}
+
+func newApp() *fiber.App {
+	app := fiber.New()
+	app.Post("/login", func(c fiber.Ctx) error {
+		var body struct {
+			User string `json:"user"`
+		}
+		if err := c.Bind().JSON(&body); err != nil {
+			return err
+		}
+		c.Cookie(&fiber.Cookie{Name: "session", Value: body.User, Secure: true})
+		return c.JSON(fiber.Map{"user": body.User})
+	})
+	app.Get("/profile", func(c fiber.Ctx) error {
+		if c.Cookies("session") == "" {
+			return fiber.ErrUnauthorized
+		}
+		return c.JSON(fiber.Map{"user": c.Cookies("session"), "theme": c.Cookies("theme"), "page": c.Query("page")})
+	})
+	app.Get("/auth", func(c fiber.Ctx) error {
+		return c.SendString(c.Get(fiber.HeaderAuthorization))
+	})
+	app.Post("/form", func(c fiber.Ctx) error {
+		c.Set("X-Name", c.FormValue("name"))
+		return c.SendString(c.FormValue("name"))
+	})
+	app.Post("/upload", func(c fiber.Ctx) error {
+		file, err := c.FormFile("file")
+		if err != nil {
+			return err
+		}
+		return c.SendString(c.FormValue("title") + ":" + file.Filename + ":" + file.Header.Get(fiber.HeaderContentType))
+	})
+	app.Get("/slow", func(c fiber.Ctx) error {
+		time.Sleep(50 * time.Millisecond)
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	return app
+}
+
+// go test -run Test_Client_CookieJar
+func Test_Client_CookieJar(t *testing.T) {
+	t.Parallel()
+	client := New(t, newApp())
+
+	client.Get("/profile").Do().Status(fiber.StatusUnauthorized)
+
+	resp := client.Post("/login").JSON(fiber.Map{"user": "john"}).Do().
+		Status(fiber.StatusOK).
+		HeaderContains(fiber.HeaderContentType, fiber.MIMEApplicationJSON).
+		JSONEq(`{"user":"john"}`)
+	require.Equal(t, "john", resp.Cookie("session").Value)
+	require.Len(t, client.Cookies(), 1)
+
+	var profile map[string]string
+	client.Get("/profile?page=1").Query("page", "2").Cookie("theme", "dark").Do().
+		Status(fiber.StatusOK).
+		DecodeJSON(&profile)
+	require.Equal(t, map[string]string{"user": "john", "theme": "dark", "page": "1"}, profile)
+}
+
+// go test -run Test_Request_Bodies
+func Test_Request_Bodies(t *testing.T) {
+	t.Parallel()
+	client := New(t, newApp())
+
+	client.Get("/auth").BasicAuth("john", "doe").Do().BodyEq("Basic am9objpkb2U=")
+	client.Get("/auth").BearerToken("token").Do().BodyEq("Bearer token")
+
+	client.Post("/form").Form(map[string]string{"name": "john"}).Do().
+		HeaderEq("X-Name", "john").
+		BodyEq("john")
+
+	client.Post("/upload").
+		Multipart(map[string]string{"title": "avatar"}, File{Field: "file", Name: "a.png", Content: []byte{1, 2}, MimeType: "image/png"}).
+		Do().
+		BodyEq("avatar:a.png:image/png").
+		BodyContains("a.png")
+
+	client.Get("/slow").Timeout(0).Do().Status(fiber.StatusNoContent)
+}
+
+// go test -run Test_Response_Failures
+func Test_Response_Failures(t *testing.T) {
+	t.Parallel()
+	m := &mockT{}
+	client := New(m, newApp())
+
+	resp := client.Get("/auth").Header(fiber.HeaderAuthorization, "x").Do()
+	resp.Status(fiber.StatusCreated).
+		HeaderEq(fiber.HeaderContentType, "x").
+		HeaderContains(fiber.HeaderContentType, "json").
+		BodyEq("y").
+		BodyContains("y").
+		JSONEq(`{}`)
+	require.Nil(t, resp.Cookie("session"))
+	require.Len(t, m.errors, 7)
+	require.Equal(t, "fibertest: GET /auth: expected status 201, got 200", m.errors[0])
+	require.Equal(t, `fibertest: GET /auth: expected body "x" to contain "y"`, m.errors[4])
+
+	require.PanicsWithValue(t, "fibertest: GET /slow: i/o timeout", func() {
+		client.Get("/slow").Timeout(10 * time.Millisecond).Do()
+	})
+	require.Panics(t, func() {
+		client.Post("/login").JSON(make(chan int)).Do()
+	})
+}
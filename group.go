@@ -6,15 +6,25 @@ package fiber
 
 import (
 	"fmt"
+	"maps"
 	"reflect"
+	"time"
+
+	"github.com/gofiber/utils/v2"
 )
 
 // Group struct
 type Group struct {
 	app         *App
 	parentGroup *Group
+	host        *hostMatcher
+	meta        Map
 	name        string
 
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+	bodyLimit    int
+
 	Prefix          string
 	anyRouteDefined bool
 }
@@ -45,6 +55,87 @@ func (grp *Group) Name(name string) Router {
 	return grp
 }
 
+// Meta Assign metadata to specific route or group itself.
+//
+// If this method is used before any route added to group, the metadata is assigned to all routes
+// which are added to the group afterwards. Otherwise, it'll be assigned to the latest route.
+func (grp *Group) Meta(key string, value any) Router {
+	if grp.anyRouteDefined {
+		grp.app.Meta(key, value)
+
+		return grp
+	}
+
+	grp.app.mutex.Lock()
+	if grp.meta == nil {
+		grp.meta = make(Map)
+	}
+	grp.meta[key] = value
+	grp.app.mutex.Unlock()
+
+	return grp
+}
+
+// SetErrorHandler sets the ErrorHandler for errors of requests whose path starts with
+// the prefix of the group, and which match the host of the group. It takes precedence
+// over the ErrorHandler of the app and of mounted apps, the group with the longest
+// prefix handles an error.
+//
+//	api := app.Group("/api").SetErrorHandler(func(c fiber.Ctx, err error) error {
+//		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+//	})
+func (grp *Group) SetErrorHandler(handler ErrorHandler) Router {
+	prefix := utils.TrimRight(grp.Prefix, '/')
+	if prefix != "" && prefix[0] != '/' {
+		prefix = "/" + prefix
+	}
+
+	grp.app.mutex.Lock()
+	grp.app.groupErrorHandlers = append(grp.app.groupErrorHandlers, groupErrorHandler{
+		prefix:  prefix,
+		host:    grp.host,
+		handler: handler,
+	})
+	grp.app.mutex.Unlock()
+
+	return grp
+}
+
+// groupErrorHandler is the ErrorHandler of a group
+type groupErrorHandler struct {
+	host    *hostMatcher
+	handler ErrorHandler
+	prefix  string
+}
+
+// groupErrorHandler returns the ErrorHandler of the group with the longest prefix
+// which matches the request, or nil if there is none
+func (app *App) groupErrorHandler(c Ctx) ErrorHandler {
+	var (
+		handler ErrorHandler
+		length  = -1
+	)
+	path := c.Path()
+	for _, geh := range app.groupErrorHandlers {
+		if len(geh.prefix) <= length || len(path) < len(geh.prefix) {
+			continue
+		}
+		// The prefix must end at a segment boundary of the path
+		if len(path) > len(geh.prefix) && path[len(geh.prefix)] != '/' {
+			continue
+		}
+		if pathPrefix := path[:len(geh.prefix)]; pathPrefix != geh.prefix &&
+			(app.config.CaseSensitive || !utils.EqualFold(pathPrefix, geh.prefix)) {
+			continue
+		}
+		if geh.host != nil && !geh.host.match(c.Hostname(), make([]string, len(geh.host.params))) {
+			continue
+		}
+		handler, length = geh.handler, len(geh.prefix)
+	}
+	return handler
+}
+
 // Use registers a middleware route that will match requests
 // with the provided prefix (which is optional and defaults to "/").
 // Also, you can pass another app instance as a sub-router along a routing path.
@@ -187,7 +278,10 @@ func (grp *Group) Group(prefix string, handlers ...Handler) Router {
 	}
 
 	// Create new group
-	newGrp := &Group{Prefix: prefix, app: grp.app, parentGroup: grp}
+	newGrp := &Group{
+		Prefix: prefix, app: grp.app, parentGroup: grp, host: grp.host, meta: maps.Clone(grp.meta),
+		readTimeout: grp.readTimeout, writeTimeout: grp.writeTimeout, bodyLimit: grp.bodyLimit,
+	}
 	if err := grp.app.hooks.executeOnGroupHooks(*newGrp); err != nil {
 		panic(err)
 	}
@@ -199,7 +293,7 @@ func (grp *Group) Group(prefix string, handlers ...Handler) Router {
 // Uses Group method to define new sub-router.
 func (grp *Group) Route(path string) Register {
 	// Create new group
-	register := &Registering{app: grp.app, path: getGroupPath(grp.Prefix, path)}
+	register := &Registering{app: grp.app, group: grp, path: getGroupPath(grp.Prefix, path)}
 
 	return register
 }
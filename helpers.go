@@ -7,6 +7,7 @@ package fiber
 import (
 	"bytes"
 	"crypto/tls"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
@@ -14,17 +15,22 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 	"unsafe"
 
+	"github.com/gofiber/fiber/v3/httpcache"
 	"github.com/gofiber/fiber/v3/log"
 	"github.com/gofiber/utils/v2"
+	"github.com/tinylib/msgp/msgp"
 
 	"github.com/valyala/bytebufferpool"
 	"github.com/valyala/fasthttp"
+	"golang.org/x/net/html/charset"
 )
 
 // acceptedType is a struct that holds the parsed value of an Accept header
@@ -100,6 +106,91 @@ func (app *App) quoteString(raw string) string {
 	return quoted
 }
 
+// attachmentDisposition returns the attachment Content-Disposition header value for the filename.
+// Non-ASCII filenames additionally get the filename* parameter with RFC 5987 encoding.
+func (app *App) attachmentDisposition(fname string) string {
+	disposition := `attachment; filename="` + app.quoteString(fname) + `"`
+	for i := 0; i < len(fname); i++ {
+		if fname[i] >= utf8.RuneSelf {
+			return disposition + "; filename*=UTF-8''" + encodeExtValue(fname)
+		}
+	}
+	return disposition
+}
+
+// encodeExtValue percent-encodes all bytes of the value which aren't attr-chars of RFC 5987
+func encodeExtValue(value string) string {
+	const hex = "0123456789ABCDEF"
+	encoded := make([]byte, 0, len(value)*3)
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		switch {
+		case 'a' <= ch && ch <= 'z', 'A' <= ch && ch <= 'Z', '0' <= ch && ch <= '9',
+			strings.IndexByte("!#$&+-.^_`|~", ch) != -1:
+			encoded = append(encoded, ch)
+		default:
+			encoded = append(encoded, '%', hex[ch>>4], hex[ch&0x0F])
+		}
+	}
+	return string(encoded)
+}
+
+// marshalProtobuf is the default ProtobufEncoder, which uses the Marshal method of
+// messages generated by gogo/protobuf
+func marshalProtobuf(v any) ([]byte, error) {
+	if m, ok := v.(interface{ Marshal() ([]byte, error) }); ok {
+		return m.Marshal()
+	}
+	return nil, ErrProtobufMarshaler
+}
+
+// unmarshalProtobuf is the default ProtobufDecoder, which uses the Unmarshal method of
+// messages generated by gogo/protobuf
+func unmarshalProtobuf(data []byte, v any) error {
+	if m, ok := v.(interface{ Unmarshal(data []byte) error }); ok {
+		return m.Unmarshal(data)
+	}
+	return ErrProtobufUnmarshaler
+}
+
+// unmarshalXML is the default XMLDecoder. Unlike xml.Unmarshal, it decodes documents whose
+// XML declaration names another charset than UTF-8, e.g. encoding="ISO-8859-1".
+func unmarshalXML(data []byte, v any) error {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.CharsetReader = charset.NewReaderLabel
+	return dec.Decode(v) //nolint:wrapcheck // unnecessary to wrap it
+}
+
+// marshalMsgPack is the default MsgPackEncoder. It uses the MarshalMsg method of types
+// generated by tinylib/msgp and also encodes maps, slices and basic types.
+func marshalMsgPack(v any) ([]byte, error) {
+	raw, err := msgp.AppendIntf(nil, v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal msgpack: %w", err)
+	}
+	return raw, nil
+}
+
+// unmarshalMsgPack is the default MsgPackDecoder. It uses the UnmarshalMsg method of types
+// generated by tinylib/msgp and also decodes into *map[string]any and *any.
+func unmarshalMsgPack(data []byte, v any) error {
+	var err error
+	switch out := v.(type) {
+	case msgp.Unmarshaler:
+		_, err = out.UnmarshalMsg(data)
+	case *map[string]any:
+		*out, _, err = msgp.ReadMapStrIntfBytes(data, *out)
+	case *any:
+		*out, _, err = msgp.ReadIntfBytes(data)
+	default:
+		return ErrMsgPackUnmarshaler
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal msgpack: %w", err)
+	}
+	return nil
+}
+
 // Scan stack if other methods match the request
 func (app *App) methodExist(c *DefaultCtx) bool {
 	var exists bool
@@ -113,10 +204,7 @@ func (app *App) methodExist(c *DefaultCtx) bool {
 		// Reset stack index
 		c.setIndexRoute(-1)
 
-		tree, ok := c.App().treeStack[i][c.getTreePath()]
-		if !ok {
-			tree = c.App().treeStack[i][""]
-		}
+		tree := routeTree(c.App().treeStack.Load(), i, c.getTreePath())
 		// Get stack length
 		lenr := len(tree) - 1
 		// Loop over the route stack starting from previous index
@@ -129,8 +217,8 @@ func (app *App) methodExist(c *DefaultCtx) bool {
 			if route.use {
 				continue
 			}
-			// Check if it matches the request path
-			match := route.match(c.getDetectionPath(), c.Path(), c.getValues())
+			// Check if it matches the request path and host
+			match := route.match(c.getDetectionPath(), c.Path(), c.getValues()) && route.matchHost(c, c.getValues())
 			// No match, next route
 			if match {
 				// We matched
@@ -157,10 +245,7 @@ func (app *App) methodExistCustom(c CustomCtx) bool {
 		// Reset stack index
 		c.setIndexRoute(-1)
 
-		tree, ok := c.App().treeStack[i][c.getTreePath()]
-		if !ok {
-			tree = c.App().treeStack[i][""]
-		}
+		tree := routeTree(c.App().treeStack.Load(), i, c.getTreePath())
 		// Get stack length
 		lenr := len(tree) - 1
 		// Loop over the route stack starting from previous index
@@ -173,8 +258,8 @@ func (app *App) methodExistCustom(c CustomCtx) bool {
 			if route.use {
 				continue
 			}
-			// Check if it matches the request path
-			match := route.match(c.getDetectionPath(), c.Path(), c.getValues())
+			// Check if it matches the request path and host
+			match := route.match(c.getDetectionPath(), c.Path(), c.getValues()) && route.matchHost(c, c.getValues())
 			// No match, next route
 			if match {
 				// We matched
@@ -237,6 +322,72 @@ func acceptsOffer(spec, offer string, _ headerParams) bool {
 	return false
 }
 
+// acceptsLanguageOffer determines if a language offer matches a language range of Accept-Language.
+// The match is case-insensitive and the range "*" matches every offer. A range also matches
+// more specific offers, e.g. "en" matches "en-GB", and less specific offers, e.g. "en-GB" matches "en".
+func acceptsLanguageOffer(spec, offer string, _ headerParams) bool {
+	switch {
+	case spec == "*":
+		return true
+	case len(spec) == len(offer):
+		return utils.EqualFold(spec, offer)
+	case len(spec) < len(offer):
+		return offer[len(spec)] == '-' && utils.EqualFold(spec, offer[:len(spec)])
+	default:
+		return spec[len(offer)] == '-' && utils.EqualFold(spec[:len(offer)], offer)
+	}
+}
+
+// acceptsLanguageTag determines if a language offer equals a language range of Accept-Language,
+// ignoring the case. The range "*" matches every offer.
+func acceptsLanguageTag(spec, offer string, _ headerParams) bool {
+	return spec == "*" || utils.EqualFold(spec, offer)
+}
+
+// languageFallbacks adds the less specific ranges of the ranges in an Accept-Language header
+// after them with the same quality, e.g. "de-CH;q=0.8" becomes "de-CH;q=0.8,de;q=0.8".
+// Ranges which are listed in the header themselves keep their own quality.
+// See: https://www.rfc-editor.org/rfc/rfc4647#section-3.4
+func languageFallbacks(header []byte) []byte {
+	if bytes.IndexByte(header, '-') == -1 {
+		return header
+	}
+
+	splitRange := func(languageRange []byte) ([]byte, []byte) {
+		spec, params := languageRange, []byte(nil)
+		if i := bytes.IndexByte(languageRange, ';'); i != -1 {
+			spec, params = languageRange[:i], languageRange[i:]
+		}
+		return utils.Trim(spec, ' '), params
+	}
+
+	listed := make([][]byte, 0, 8)
+	forEachMediaRange(header, func(languageRange []byte) {
+		spec, _ := splitRange(languageRange)
+		listed = append(listed, spec)
+	})
+
+	expanded := make([]byte, 0, 2*len(header))
+	forEachMediaRange(header, func(languageRange []byte) {
+		if len(expanded) > 0 {
+			expanded = append(expanded, ',')
+		}
+		expanded = append(expanded, languageRange...)
+
+		spec, params := splitRange(languageRange)
+		for i := bytes.LastIndexByte(spec, '-'); i > 0; i = bytes.LastIndexByte(spec, '-') {
+			spec = spec[:i]
+			if slices.ContainsFunc(listed, func(other []byte) bool { return bytes.EqualFold(other, spec) }) {
+				continue
+			}
+			expanded = append(expanded, ',')
+			expanded = append(expanded, spec...)
+			expanded = append(expanded, params...)
+		}
+	})
+	return expanded
+}
+
 // acceptsOfferType This function determines if an offer type matches a given specification.
 // It checks if the specification is equal to */* (i.e., all types are accepted).
 // It gets the MIME type of the offer (either from the offer itself or by its file extension).
@@ -452,11 +603,6 @@ func getOffer(header []byte, isAccepted func(spec, offer string, specParams head
 				})
 			}
 
-			// Skip this accept type if quality is 0.0
-			// See: https://www.rfc-editor.org/rfc/rfc9110#quality.values
-			if quality == 0.0 {
-				return
-			}
 		}
 
 		spec = utils.Trim(spec, ' ')
@@ -493,27 +639,56 @@ func getOffer(header []byte, isAccepted func(spec, offer string, specParams head
 		sortAcceptedTypes(&acceptedTypes)
 	}
 
-	// Find the first offer that matches the accepted types
-	for _, acceptedType := range acceptedTypes {
+	defer func() {
+		for _, acceptedType := range acceptedTypes {
+			if acceptedType.params != nil {
+				headerParamPool.Put(acceptedType.params)
+			}
+		}
+	}()
+
+	// Find the first offer that matches the accepted types. The quality of an offer is
+	// the quality of the most specific accepted type matching it, so a more specific type
+	// with a lower quality, e.g. "text/html;q=0" besides "text/*", takes precedence.
+	// Types with quality 0.0 are sorted last and never accept an offer.
+	// See: https://www.rfc-editor.org/rfc/rfc9110#quality.values
+	for i, acceptedType := range acceptedTypes {
+		if acceptedType.quality == 0.0 {
+			break
+		}
 		for _, offer := range offers {
 			if offer == "" {
 				continue
 			}
-			if isAccepted(acceptedType.spec, offer, acceptedType.params) {
-				if acceptedType.params != nil {
-					headerParamPool.Put(acceptedType.params)
-				}
+			if isAccepted(acceptedType.spec, offer, acceptedType.params) &&
+				!isOverridden(acceptedTypes, i, offer, isAccepted) {
 				return offer
 			}
 		}
-		if acceptedType.params != nil {
-			headerParamPool.Put(acceptedType.params)
-		}
 	}
 
 	return ""
 }
 
+// isOverridden reports whether an accepted type with a lower quality than acceptedTypes[i]
+// is more specific and matches the offer, which then determines the quality of the offer.
+func isOverridden(
+	acceptedTypes []acceptedType, i int, offer string, isAccepted func(spec, offer string, specParams headerParams) bool,
+) bool {
+	current := acceptedTypes[i]
+	for _, other := range acceptedTypes[i+1:] {
+		if other.quality == current.quality {
+			continue
+		}
+		if (other.specificity > current.specificity ||
+			(other.specificity == current.specificity && len(other.params) > len(current.params))) &&
+			isAccepted(other.spec, offer, other.params) {
+			return true
+		}
+	}
+	return false
+}
+
 // sortAcceptedTypes sorts accepted types by quality and specificity, preserving order of equal elements
 // A type with parameters has higher priority than an equivalent one without parameters.
 // e.g., text/html;a=1;b=2 comes before text/html;a=1
@@ -543,40 +718,6 @@ func sortAcceptedTypes(acceptedTypes *[]acceptedType) {
 	}
 }
 
-func matchEtag(s, etag string) bool {
-	if s == etag || s == "W/"+etag || "W/"+s == etag {
-		return true
-	}
-
-	return false
-}
-
-func (app *App) isEtagStale(etag string, noneMatchBytes []byte) bool {
-	var start, end int
-
-	// Adapted from:
-	// https://github.com/jshttp/fresh/blob/10e0471669dbbfbfd8de65bc6efac2ddd0bfa057/index.js#L110
-	for i := range noneMatchBytes {
-		switch noneMatchBytes[i] {
-		case 0x20:
-			if start == end {
-				start = i + 1
-				end = i + 1
-			}
-		case 0x2c:
-			if matchEtag(app.getString(noneMatchBytes[start:end]), etag) {
-				return false
-			}
-			start = i + 1
-			end = i + 1
-		default:
-			end = i + 1
-		}
-	}
-
-	return !matchEtag(app.getString(noneMatchBytes[start:end]), etag)
-}
-
 func parseAddr(raw string) (string, string) { //nolint:revive // Returns (host, port)
 	if i := strings.LastIndex(raw, ":"); i != -1 {
 		return raw[:i], raw[i+1:]
@@ -584,32 +725,9 @@ func parseAddr(raw string) (string, string) { //nolint:revive // Returns (host,
 	return raw, ""
 }
 
-const noCacheValue = "no-cache"
-
 // isNoCache checks if the cacheControl header value is a `no-cache`.
 func isNoCache(cacheControl string) bool {
-	i := strings.Index(cacheControl, noCacheValue)
-	if i == -1 {
-		return false
-	}
-
-	// Xno-cache
-	if i > 0 && !(cacheControl[i-1] == ' ' || cacheControl[i-1] == ',') {
-		return false
-	}
-
-	// bla bla, no-cache
-	if i+len(noCacheValue) == len(cacheControl) {
-		return true
-	}
-
-	// bla bla, no-cacheX
-	if cacheControl[i+len(noCacheValue)] != ',' {
-		return false
-	}
-
-	// OK
-	return true
+	return httpcache.HasDirective(cacheControl, httpcache.DirectiveNoCache)
 }
 
 type testConn struct {
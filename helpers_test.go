@@ -59,6 +59,15 @@ func Test_Utils_GetOffer(t *testing.T) {
 
 	require.Equal(t, "deflate", getOffer([]byte("gzip, deflate"), acceptsOffer, "deflate"))
 	require.Equal(t, "", getOffer([]byte("gzip, deflate;q=0"), acceptsOffer, "deflate"))
+
+	// The most specific accepted type determines the quality of an offer
+	require.Equal(t, "application/json", getOffer([]byte("application/*;q=0.9, application/xml;q=0.1"), acceptsOfferType, "application/xml", "application/json"))
+	require.Equal(t, "application/xml", getOffer([]byte("application/*;q=0.9, application/xml;q=0.1"), acceptsOfferType, "application/xml"))
+	require.Equal(t, "text/plain", getOffer([]byte("text/*, text/html;q=0"), acceptsOfferType, "text/html", "text/plain"))
+	require.Equal(t, "", getOffer([]byte("text/*, text/html;q=0"), acceptsOfferType, "text/html"))
+	require.Equal(t, "text/html", getOffer([]byte("*/*;q=0.5, text/html;level=1;q=0, text/html;q=0.8"), acceptsOfferType, "application/json", "text/html"))
+	require.Equal(t, "application/json", getOffer([]byte("*/*;q=0.5, text/html;level=1;q=0, text/html;q=0.8"), acceptsOfferType, "text/html;level=1", "application/json"))
+	require.Equal(t, "deflate", getOffer([]byte("*, gzip;q=0"), acceptsOffer, "gzip", "deflate"))
 }
 
 // go test -v -run=^$ -bench=Benchmark_Utils_GetOffer -benchmem -count=4
@@ -149,6 +158,31 @@ func Benchmark_Utils_GetOffer(b *testing.B) {
 	}
 }
 
+func Test_Utils_LanguageFallbacks(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		header   string
+		expected string
+	}{
+		{header: "", expected: ""},
+		{header: "en, de;q=0.5", expected: "en, de;q=0.5"},
+		{header: "de-CH;q=0.8", expected: "de-CH;q=0.8,de;q=0.8"},
+		{header: "zh-Hant-TW, en;q=0.5", expected: "zh-Hant-TW,zh-Hant,zh,en;q=0.5"},
+		{header: "en-GB, EN;q=0.1", expected: "en-GB,EN;q=0.1"},
+		{header: "*, fr-CA ;q=0.3", expected: "*,fr-CA ;q=0.3,fr;q=0.3"},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, string(languageFallbacks([]byte(tc.header))), tc.header)
+	}
+
+	require.True(t, acceptsLanguageOffer("en", "EN-gb", nil))
+	require.True(t, acceptsLanguageOffer("en-GB", "en", nil))
+	require.False(t, acceptsLanguageOffer("en-GB", "e", nil))
+	require.False(t, acceptsLanguageOffer("eng", "en", nil))
+	require.True(t, acceptsLanguageTag("*", "en", nil))
+	require.False(t, acceptsLanguageTag("en-GB", "en", nil))
+}
+
 func Test_Utils_ParamsMatch(t *testing.T) {
 	testCases := []struct {
 		description string
@@ -6,14 +6,19 @@ import (
 
 // OnRouteHandler Handlers define a function to create hooks for Fiber.
 type (
-	OnRouteHandler     = func(Route) error
-	OnNameHandler      = OnRouteHandler
-	OnGroupHandler     = func(Group) error
-	OnGroupNameHandler = OnGroupHandler
-	OnListenHandler    = func(ListenData) error
-	OnShutdownHandler  = func() error
-	OnForkHandler      = func(int) error
-	OnMountHandler     = func(*App) error
+	OnRouteHandler        = func(Route) error
+	OnNameHandler         = OnRouteHandler
+	OnGroupHandler        = func(Group) error
+	OnGroupNameHandler    = OnGroupHandler
+	OnListenHandler       = func(ListenData) error
+	OnShutdownHandler     = func() error
+	OnPreShutdownHandler  = func() error
+	OnPostShutdownHandler = func(error) error
+	OnForkHandler         = func(int) error
+	OnMountHandler        = func(*App) error
+	OnPreRenderHandler    = func(Ctx, string, Map) error
+	OnPostRenderHandler   = func(Ctx, string) error
+	OnReloadHandler       = func(ConfigUpdate) error
 )
 
 // Hooks is a struct to use it with App.
@@ -22,14 +27,19 @@ type Hooks struct {
 	app *App
 
 	// Hooks
-	onRoute     []OnRouteHandler
-	onName      []OnNameHandler
-	onGroup     []OnGroupHandler
-	onGroupName []OnGroupNameHandler
-	onListen    []OnListenHandler
-	onShutdown  []OnShutdownHandler
-	onFork      []OnForkHandler
-	onMount     []OnMountHandler
+	onRoute        []OnRouteHandler
+	onName         []OnNameHandler
+	onGroup        []OnGroupHandler
+	onGroupName    []OnGroupNameHandler
+	onListen       []OnListenHandler
+	onShutdown     []OnShutdownHandler
+	onPreShutdown  []OnPreShutdownHandler
+	onPostShutdown []OnPostShutdownHandler
+	onFork         []OnForkHandler
+	onMount        []OnMountHandler
+	onPreRender    []OnPreRenderHandler
+	onPostRender   []OnPostRenderHandler
+	onReload       []OnReloadHandler
 }
 
 // ListenData is a struct to use it with OnListenHandler
@@ -41,15 +51,20 @@ type ListenData struct {
 
 func newHooks(app *App) *Hooks {
 	return &Hooks{
-		app:         app,
-		onRoute:     make([]OnRouteHandler, 0),
-		onGroup:     make([]OnGroupHandler, 0),
-		onGroupName: make([]OnGroupNameHandler, 0),
-		onName:      make([]OnNameHandler, 0),
-		onListen:    make([]OnListenHandler, 0),
-		onShutdown:  make([]OnShutdownHandler, 0),
-		onFork:      make([]OnForkHandler, 0),
-		onMount:     make([]OnMountHandler, 0),
+		app:            app,
+		onRoute:        make([]OnRouteHandler, 0),
+		onGroup:        make([]OnGroupHandler, 0),
+		onGroupName:    make([]OnGroupNameHandler, 0),
+		onName:         make([]OnNameHandler, 0),
+		onListen:       make([]OnListenHandler, 0),
+		onShutdown:     make([]OnShutdownHandler, 0),
+		onPreShutdown:  make([]OnPreShutdownHandler, 0),
+		onPostShutdown: make([]OnPostShutdownHandler, 0),
+		onFork:         make([]OnForkHandler, 0),
+		onMount:        make([]OnMountHandler, 0),
+		onPreRender:    make([]OnPreRenderHandler, 0),
+		onPostRender:   make([]OnPostRenderHandler, 0),
+		onReload:       make([]OnReloadHandler, 0),
 	}
 }
 
@@ -96,13 +111,33 @@ func (h *Hooks) OnListen(handler ...OnListenHandler) {
 	h.app.mutex.Unlock()
 }
 
-// OnShutdown is a hook to execute user functions after Shutdown.
+// OnShutdown is a hook to execute user functions on Shutdown, after the OnPreShutdown hooks
+// and before the server is shut down. It is meant to close long-lived connections, like
+// WebSockets or event streams, which would keep the server from draining.
 func (h *Hooks) OnShutdown(handler ...OnShutdownHandler) {
 	h.app.mutex.Lock()
 	h.onShutdown = append(h.onShutdown, handler...)
 	h.app.mutex.Unlock()
 }
 
+// OnPreShutdown is a hook to execute user functions on Shutdown, before the server stops
+// accepting connections. It can be used to let health checks fail, so load balancers
+// stop sending new requests.
+func (h *Hooks) OnPreShutdown(handler ...OnPreShutdownHandler) {
+	h.app.mutex.Lock()
+	h.onPreShutdown = append(h.onPreShutdown, handler...)
+	h.app.mutex.Unlock()
+}
+
+// OnPostShutdown is a hook to execute user functions after Shutdown, when the in-flight
+// requests and background tasks are done or the context of ShutdownWithContext expired.
+// The error of the shutdown is passed as a parameter.
+func (h *Hooks) OnPostShutdown(handler ...OnPostShutdownHandler) {
+	h.app.mutex.Lock()
+	h.onPostShutdown = append(h.onPostShutdown, handler...)
+	h.app.mutex.Unlock()
+}
+
 // OnFork is a hook to execute user function after fork process.
 func (h *Hooks) OnFork(handler ...OnForkHandler) {
 	h.app.mutex.Lock()
@@ -119,6 +154,33 @@ func (h *Hooks) OnMount(handler ...OnMountHandler) {
 	h.app.mutex.Unlock()
 }
 
+// OnPreRender is a hook to execute user functions before a template is rendered by Render or RenderStream.
+// The bind map already contains the view globals, view binds and locals and can be modified by the handler.
+// Returning an error aborts the rendering and the error is returned by the render method.
+func (h *Hooks) OnPreRender(handler ...OnPreRenderHandler) {
+	h.app.mutex.Lock()
+	h.onPreRender = append(h.onPreRender, handler...)
+	h.app.mutex.Unlock()
+}
+
+// OnPostRender is a hook to execute user functions after a template is rendered by Render.
+// For RenderStream the hook is executed once the response stream is prepared,
+// because the template itself is rendered after the handler has returned.
+func (h *Hooks) OnPostRender(handler ...OnPostRenderHandler) {
+	h.app.mutex.Lock()
+	h.onPostRender = append(h.onPostRender, handler...)
+	h.app.mutex.Unlock()
+}
+
+// OnReload is a hook to execute user functions after the config was changed with App.ReloadConfig.
+// The update is passed as a parameter, it allows middlewares to react to the changes.
+// The error of the first failing hook is returned by ReloadConfig.
+func (h *Hooks) OnReload(handler ...OnReloadHandler) {
+	h.app.mutex.Lock()
+	h.onReload = append(h.onReload, handler...)
+	h.app.mutex.Unlock()
+}
+
 func (h *Hooks) executeOnRouteHooks(route Route) error {
 	// Check mounting
 	if h.app.mountFields.mountPath != "" {
@@ -199,6 +261,22 @@ func (h *Hooks) executeOnShutdownHooks() {
 	}
 }
 
+func (h *Hooks) executeOnPreShutdownHooks() {
+	for _, v := range h.onPreShutdown {
+		if err := v(); err != nil {
+			log.Errorf("failed to call pre shutdown hook: %v", err)
+		}
+	}
+}
+
+func (h *Hooks) executeOnPostShutdownHooks(err error) {
+	for _, v := range h.onPostShutdown {
+		if hookErr := v(err); hookErr != nil {
+			log.Errorf("failed to call post shutdown hook: %v", hookErr)
+		}
+	}
+}
+
 func (h *Hooks) executeOnForkHooks(pid int) {
 	for _, v := range h.onFork {
 		if err := v(pid); err != nil {
@@ -216,3 +294,33 @@ func (h *Hooks) executeOnMountHooks(app *App) error {
 
 	return nil
 }
+
+func (h *Hooks) executeOnPreRenderHooks(c Ctx, name string, bind Map) error {
+	for _, v := range h.onPreRender {
+		if err := v(c, name, bind); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *Hooks) executeOnPostRenderHooks(c Ctx, name string) error {
+	for _, v := range h.onPostRender {
+		if err := v(c, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *Hooks) executeOnReloadHooks(update ConfigUpdate) error {
+	for _, v := range h.onReload {
+		if err := v(update); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
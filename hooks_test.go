@@ -1,6 +1,7 @@
 package fiber
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -9,6 +10,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/bytebufferpool"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
 )
 
 func testSimpleHandler(c Ctx) error {
@@ -199,6 +202,63 @@ func Test_Hook_OnShutdown(t *testing.T) {
 	require.Equal(t, "shutdowning", buf.String())
 }
 
+func Test_Hook_OnPreShutdown_OnPostShutdown(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var order []string
+	app.Hooks().OnPostShutdown(func(err error) error {
+		require.NoError(t, err)
+		order = append(order, "post")
+		return errors.New("logged")
+	})
+	app.Hooks().OnShutdown(func() error {
+		order = append(order, "shutdown")
+		return nil
+	})
+	app.Hooks().OnPreShutdown(func() error {
+		order = append(order, "pre")
+		return nil
+	})
+
+	require.NoError(t, app.Shutdown())
+	require.Equal(t, []string{"pre", "shutdown", "post"}, order)
+}
+
+func Test_Hook_OnPostShutdown_Deadline(t *testing.T) {
+	t.Parallel()
+	app := New()
+	started := make(chan struct{})
+	app.Get("/", func(c Ctx) error {
+		close(started)
+		time.Sleep(500 * time.Millisecond)
+		return c.SendString("drained")
+	})
+
+	shutdownErr := make(chan error, 1)
+	app.Hooks().OnPostShutdown(func(err error) error {
+		shutdownErr <- err
+		return nil
+	})
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		assert.NoError(t, app.Listener(ln))
+	}()
+	go func() {
+		conn, err := ln.Dial()
+		assert.NoError(t, err)
+		_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+		assert.NoError(t, err)
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, app.ShutdownWithContext(ctx), context.DeadlineExceeded)
+	require.ErrorIs(t, <-shutdownErr, context.DeadlineExceeded)
+}
+
 func Test_Hook_OnListen(t *testing.T) {
 	t.Parallel()
 
@@ -282,3 +342,39 @@ func Test_Hook_OnMount(t *testing.T) {
 
 	app.Use("/sub", subApp)
 }
+
+func Test_Hook_OnPreRender_OnPostRender(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var rendered []string
+	app.Hooks().OnPreRender(func(_ Ctx, name string, bind Map) error {
+		if name == "./.github/testdata/template-invalid.html" {
+			return errors.New("pre render failed")
+		}
+		bind["Title"] = "From Hook"
+		return nil
+	})
+	app.Hooks().OnPostRender(func(c Ctx, name string) error {
+		rendered = append(rendered, name)
+		c.Set("X-Rendered", name)
+		return nil
+	})
+
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	err := c.Render("./.github/testdata/index.tmpl", nil)
+	require.NoError(t, err)
+	require.Equal(t, "<h1>From Hook</h1>", string(c.Response().Body()))
+	require.Equal(t, "./.github/testdata/index.tmpl", c.GetRespHeader("X-Rendered"))
+
+	err = c.RenderStream("./.github/testdata/index.tmpl", nil)
+	require.NoError(t, err)
+	require.Equal(t, "<h1>From Hook</h1>", string(c.Response().Body()))
+
+	err = c.Render("./.github/testdata/template-invalid.html", nil)
+	require.EqualError(t, err, "pre render failed")
+
+	require.Equal(t, []string{"./.github/testdata/index.tmpl", "./.github/testdata/index.tmpl"}, rendered)
+}
@@ -0,0 +1,142 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/utils/v2"
+)
+
+// hostMatcher matches the hostname of a request against the host of a route
+type hostMatcher struct {
+	host     string   // Host with lowercase labels, without the wildcard
+	labels   []string // Labels of the host, parameters start with ':'
+	params   []string // Names of the parameters in the order of the labels
+	wildcard bool     // The host starts with "*.", which matches all subdomains
+}
+
+// newHostMatcher parses the host, e.g. "api.example.com", "*.example.com" or ":tenant.example.com"
+func newHostMatcher(host string) *hostMatcher {
+	host = utils.TrimRight(utils.Trim(host, ' '), '.')
+	matcher := &hostMatcher{}
+	if domain, found := strings.CutPrefix(host, "*."); found {
+		host = domain
+		matcher.wildcard = true
+	}
+	matcher.labels = strings.Split(host, ".")
+	for i, label := range matcher.labels {
+		if label == "" || label == ":" || strings.ContainsAny(label, "*/") || strings.LastIndexByte(label, ':') > 0 {
+			panic(fmt.Sprintf("host: invalid host %q\n", host))
+		}
+		if label[0] == ':' {
+			matcher.params = append(matcher.params, label[1:])
+			continue
+		}
+		matcher.labels[i] = utils.ToLower(label)
+	}
+	matcher.host = strings.Join(matcher.labels, ".")
+	return matcher
+}
+
+// String returns the host as it is matched
+func (h *hostMatcher) String() string {
+	if h.wildcard {
+		return "*." + h.host
+	}
+	return h.host
+}
+
+// match reports whether the hostname matches the host and sets the values of the parameters
+func (h *hostMatcher) match(hostname string, values []string) bool {
+	rest, hasRest := utils.TrimRight(hostname, '.'), true
+	param := len(h.params)
+	// Compare the labels from the end, as the wildcard matches the first labels
+	for i := len(h.labels) - 1; i >= 0; i-- {
+		if !hasRest {
+			return false
+		}
+		label := rest
+		rest, hasRest = "", false
+		if dot := strings.LastIndexByte(label, '.'); dot != -1 {
+			label, rest, hasRest = label[dot+1:], label[:dot], true
+		}
+		if label == "" {
+			return false
+		}
+		if h.labels[i][0] == ':' {
+			param--
+			values[param] = label
+		} else if !utils.EqualFold(label, h.labels[i]) {
+			return false
+		}
+	}
+	if h.wildcard {
+		return hasRest && rest != ""
+	}
+	return !hasRest
+}
+
+// matchHost reports whether the route matches the hostname of the request and sets the
+// values of the host parameters, which follow the values of the path parameters
+func (r *Route) matchHost(c Ctx, values *[maxParams]string) bool {
+	if r.host == nil {
+		return true
+	}
+	return r.host.match(c.Hostname(), values[len(r.Params)-len(r.host.params):])
+}
+
+// setHost scopes the route to the host, the parameters of the host follow the path parameters
+func (r *Route) setHost(host *hostMatcher) {
+	if len(r.Params)+len(host.params) > maxParams {
+		panic(fmt.Sprintf("host: too many parameters in route %q of host %q\n", r.Path, host))
+	}
+	r.host = host
+	r.Host = host.String()
+	r.Params = append(slices.Clip(r.Params), host.params...)
+}
+
+// Host is used for routes which only match requests for the host, with optional middleware.
+// The host is case-insensitive and "*." matches all subdomains of a domain. Labels which
+// start with ':' are parameters, whose values are returned by c.Params.
+//
+//	api := app.Host("api.example.com")
+//	api.Get("/users", handler)
+//
+//	tenants := app.Host(":tenant.example.com")
+//	tenants.Get("/", func(c fiber.Ctx) error {
+//		return c.SendString(c.Params("tenant"))
+//	})
+func (app *App) Host(host string, handlers ...Handler) Router {
+	grp := &Group{app: app, host: newHostMatcher(host)}
+	if len(handlers) > 0 {
+		app.register([]string{methodUse}, "", grp, nil, handlers...)
+	}
+	if err := app.hooks.executeOnGroupHooks(*grp); err != nil {
+		panic(err)
+	}
+
+	return grp
+}
+
+// Host is used for routes of the group which only match requests for the host,
+// with optional middleware. See App.Host for the format of the host.
+func (grp *Group) Host(host string, handlers ...Handler) Router {
+	newGrp := &Group{
+		Prefix: grp.Prefix, app: grp.app, parentGroup: grp, host: newHostMatcher(host), meta: maps.Clone(grp.meta),
+		readTimeout: grp.readTimeout, writeTimeout: grp.writeTimeout, bodyLimit: grp.bodyLimit,
+	}
+	if len(handlers) > 0 {
+		grp.app.register([]string{methodUse}, grp.Prefix, newGrp, nil, handlers...)
+	}
+	if err := grp.app.hooks.executeOnGroupHooks(*newGrp); err != nil {
+		panic(err)
+	}
+
+	return newGrp
+}
@@ -0,0 +1,214 @@
+package fiber
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// testHostRequest sends a GET request for the path to the host and returns the status and body
+func testHostRequest(t *testing.T, app *App, host, path string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest(MethodGet, path, nil)
+	req.Host = host
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+// go test -run Test_App_Host
+func Test_App_Host(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	api := app.Host("api.example.com", func(c Ctx) error {
+		c.Set("X-Stack", "api")
+		return c.Next()
+	})
+	api.Get("/", func(c Ctx) error {
+		return c.SendString("api " + c.Get("X-Stack"))
+	})
+	api.Group("/v1").Route("/users").Get(func(c Ctx) error {
+		return c.SendString("api users")
+	})
+
+	admin := app.Host("admin.example.com", func(c Ctx) error {
+		c.Set("X-Stack", "admin")
+		return c.Next()
+	})
+	admin.Get("/", func(c Ctx) error {
+		return c.SendString("admin")
+	})
+
+	app.Get("/", func(c Ctx) error {
+		return c.SendString("default")
+	})
+
+	status, body := testHostRequest(t, app, "api.example.com", "/")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "api ", body)
+
+	// The host is case-insensitive and the port is ignored
+	status, body = testHostRequest(t, app, "API.Example.com:8080", "/v1/users")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "api users", body)
+
+	status, body = testHostRequest(t, app, "admin.example.com", "/")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "admin", body)
+
+	// Other hosts fall through to the routes without a host
+	status, body = testHostRequest(t, app, "example.com", "/")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "default", body)
+
+	status, _ = testHostRequest(t, app, "admin.example.com", "/v1/users")
+	require.Equal(t, StatusNotFound, status)
+
+	var hosts []string
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == MethodGet {
+			hosts = append(hosts, route.Host)
+		}
+	}
+	require.Equal(t, []string{"api.example.com", "api.example.com", "admin.example.com", ""}, hosts)
+}
+
+// go test -run Test_App_Host_Wildcard
+func Test_App_Host_Wildcard(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Host("*.example.com").Get("/", func(c Ctx) error {
+		return c.SendString("subdomain " + c.Hostname())
+	})
+	app.Host("example.com").Post("/", func(c Ctx) error {
+		return c.SendStatus(StatusCreated)
+	})
+
+	status, body := testHostRequest(t, app, "a.b.example.com", "/")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "subdomain a.b.example.com", body)
+
+	status, _ = testHostRequest(t, app, "example.com", "/")
+	require.Equal(t, StatusMethodNotAllowed, status)
+
+	status, _ = testHostRequest(t, app, "badexample.com", "/")
+	require.Equal(t, StatusNotFound, status)
+}
+
+// go test -run Test_App_Host_Mount
+func Test_App_Host_Mount(t *testing.T) {
+	t.Parallel()
+	subApp := New()
+	subApp.Get("/status", func(c Ctx) error {
+		return c.SendString("ok")
+	})
+
+	app := New()
+	app.Host("internal.example.com").Use("/admin", subApp)
+
+	status, body := testHostRequest(t, app, "internal.example.com", "/admin/status")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "ok", body)
+
+	status, _ = testHostRequest(t, app, "example.com", "/admin/status")
+	require.Equal(t, StatusNotFound, status)
+}
+
+// go test -run Test_App_Host_Params
+func Test_App_Host_Params(t *testing.T) {
+	t.Parallel()
+	app := New()
+	tenants := app.Host(":tenant.example.com")
+	tenants.Get("/users/:id", func(c Ctx) error {
+		return c.SendString(c.Params("tenant") + " " + c.Params("id"))
+	})
+	app.Host(":region.:env.example.org").Get("/", func(c Ctx) error {
+		return c.SendString(c.Params("region") + " " + Params[string](c, "env"))
+	})
+
+	status, body := testHostRequest(t, app, "ACME.example.com", "/users/42")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "acme 42", body)
+
+	status, body = testHostRequest(t, app, "eu.prod.example.org:443", "/")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "eu prod", body)
+
+	// A parameter matches exactly one label
+	status, _ = testHostRequest(t, app, "a.b.example.com", "/users/42")
+	require.Equal(t, StatusNotFound, status)
+	status, _ = testHostRequest(t, app, "example.com", "/users/42")
+	require.Equal(t, StatusNotFound, status)
+
+	require.Equal(t, []string{"id", "tenant"}, app.GetRoutes(true)[0].Params)
+}
+
+// go test -run Test_HostMatcher
+func Test_HostMatcher(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		host     string
+		hostname string
+		params   []string
+		match    bool
+	}{
+		{host: "example.com", hostname: "example.com", match: true},
+		{host: "Example.COM.", hostname: "example.com.", match: true},
+		{host: "example.com", hostname: "www.example.com", match: false},
+		{host: "example.com", hostname: "com", match: false},
+		{host: "*.example.com", hostname: "www.example.com", match: true},
+		{host: "*.example.com", hostname: "a.b.example.com", match: true},
+		{host: "*.example.com", hostname: "example.com", match: false},
+		{host: "*.example.com", hostname: ".example.com", match: false},
+		{host: "*.example.com", hostname: "wwwexample.com", match: false},
+		{host: ":tenant.example.com", hostname: "acme.example.com", params: []string{"acme"}, match: true},
+		{host: ":tenant.example.com", hostname: ".example.com", match: false},
+		{host: "*.:tenant.example.com", hostname: "www.acme.example.com", params: []string{"acme"}, match: true},
+		{host: ":a.:b.example.com", hostname: "x.y.example.com", params: []string{"x", "y"}, match: true},
+		{host: "api.:region.example.com", hostname: "web.eu.example.com", match: false},
+	}
+	for _, tc := range testCases {
+		matcher := newHostMatcher(tc.host)
+		values := make([]string, len(matcher.params))
+		require.Equal(t, tc.match, matcher.match(tc.hostname, values), tc.host+" "+tc.hostname)
+		if tc.match && len(tc.params) > 0 {
+			require.Equal(t, tc.params, values, tc.host+" "+tc.hostname)
+		}
+	}
+
+	require.Equal(t, "*.example.com", newHostMatcher("*.Example.com").String())
+	require.Equal(t, ":Tenant.example.com", newHostMatcher(":Tenant.EXAMPLE.com").String())
+	require.Panics(t, func() { newHostMatcher("") })
+	require.Panics(t, func() { newHostMatcher("*.") })
+	require.Panics(t, func() { newHostMatcher("a.*.example.com") })
+	require.Panics(t, func() { newHostMatcher(":.example.com") })
+	require.Panics(t, func() { newHostMatcher("a:b.example.com") })
+}
+
+// go test -v -run=^$ -bench=Benchmark_App_Host -benchmem -count=4
+func Benchmark_App_Host(b *testing.B) {
+	app := New()
+	app.Host("*.example.com").Get("/", func(c Ctx) error {
+		return c.SendStatus(StatusOK)
+	})
+	handler := app.Handler()
+	app.RebuildTree()
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(MethodGet)
+	ctx.Request.SetRequestURI("/")
+	ctx.Request.Header.SetHost("www.example.com")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		handler(ctx)
+	}
+	require.Equal(b, StatusOK, ctx.Response.StatusCode())
+}
@@ -0,0 +1,332 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/utils/v2"
+	"github.com/valyala/fasthttp"
+	"golang.org/x/net/http2"
+)
+
+// http2Preface is the connection preface of HTTP/2 clients, which starts cleartext
+// connections with prior knowledge (h2c)
+const http2Preface = http2.ClientPreface
+
+// errHTTP2Conn is returned by the connection of HTTP/2 requests, which can't be read
+// or written by handlers
+var errHTTP2Conn = errors.New("http2: the connection of HTTP/2 requests can't be used directly")
+
+// http2CtxPool is the pool of the fasthttp contexts of HTTP/2 requests
+var http2CtxPool = sync.Pool{
+	New: func() any {
+		return new(fasthttp.RequestCtx)
+	},
+}
+
+// enableHTTP2 enables the negotiation of HTTP/2 with ALPN
+func enableHTTP2(tlsConfig *tls.Config) {
+	if len(tlsConfig.NextProtos) == 0 {
+		tlsConfig.NextProtos = []string{"http/1.1"}
+	}
+	if !slices.Contains(tlsConfig.NextProtos, http2.NextProtoTLS) {
+		tlsConfig.NextProtos = append([]string{http2.NextProtoTLS}, tlsConfig.NextProtos...)
+	}
+}
+
+// http2Listener serves the connections which use HTTP/2 with the HTTP/2 server and
+// passes the other connections to the fasthttp server
+type http2Listener struct {
+	net.Listener
+	app    *App
+	server *http.Server
+	h2     *http2.Server
+	conns  chan acceptResult
+	done   chan struct{}
+	close  sync.Once
+	tls    bool
+	h2c    bool
+}
+
+// acceptResult is a connection or an error of the accepting listener
+type acceptResult struct {
+	conn net.Conn
+	err  error
+}
+
+// newHTTP2Listener returns the listener which serves HTTP/2 connections, if HTTP/2 or
+// h2c is enabled, otherwise ln is returned
+func (app *App) newHTTP2Listener(ln net.Listener, cfg ListenConfig) net.Listener {
+	if !cfg.EnableHTTP2 && !cfg.EnableH2C {
+		return ln
+	}
+
+	server := &http.Server{
+		ReadTimeout:  app.config.ReadTimeout,
+		WriteTimeout: app.config.WriteTimeout,
+		IdleTimeout:  app.config.IdleTimeout,
+	}
+	h2 := &http2.Server{IdleTimeout: app.config.IdleTimeout}
+	if err := http2.ConfigureServer(server, h2); err != nil {
+		log.Warnf("http2: failed to configure server: %v", err)
+		return ln
+	}
+
+	hln := &http2Listener{
+		Listener: ln,
+		app:      app,
+		server:   server,
+		h2:       h2,
+		conns:    make(chan acceptResult),
+		done:     make(chan struct{}),
+		tls:      cfg.EnableHTTP2,
+		h2c:      cfg.EnableH2C,
+	}
+	go hln.accept()
+	return hln
+}
+
+// Accept returns the next connection, which doesn't use HTTP/2
+func (ln *http2Listener) Accept() (net.Conn, error) {
+	select {
+	case result := <-ln.conns:
+		return result.conn, result.err
+	case <-ln.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close closes the listener and shuts down the HTTP/2 connections gracefully
+func (ln *http2Listener) Close() error {
+	err := ln.Listener.Close()
+	ln.close.Do(func() {
+		close(ln.done)
+		// Sends GOAWAY to the HTTP/2 connections, which are closed after their streams
+		_ = ln.server.Shutdown(context.Background()) //nolint:errcheck // The server has no listeners to close
+	})
+	return err
+}
+
+// accept accepts the connections of the listener until it is closed
+func (ln *http2Listener) accept() {
+	for {
+		conn, err := ln.Listener.Accept()
+		if err != nil {
+			select {
+			case ln.conns <- acceptResult{err: err}:
+			case <-ln.done:
+				return
+			}
+			// Keep accepting after temporary errors, like the fasthttp server
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return
+		}
+		go ln.handle(conn)
+	}
+}
+
+// handle serves the connection with the HTTP/2 server if it uses HTTP/2,
+// otherwise it is returned by Accept
+func (ln *http2Listener) handle(conn net.Conn) {
+	if ln.app.config.ReadTimeout > 0 {
+		_ = conn.SetReadDeadline(time.Now().Add(ln.app.config.ReadTimeout)) //nolint:errcheck // It is fine to ignore the error here
+	}
+
+	var isHTTP2 bool
+	var state *tls.ConnectionState
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		if ln.tls {
+			if err := tlsConn.Handshake(); err != nil {
+				_ = conn.Close() //nolint:errcheck // It is fine to ignore the error here
+				return
+			}
+			connState := tlsConn.ConnectionState()
+			state = &connState
+			isHTTP2 = connState.NegotiatedProtocol == http2.NextProtoTLS
+		}
+	} else if ln.h2c {
+		isHTTP2, conn = hasHTTP2Preface(conn)
+	}
+	_ = conn.SetReadDeadline(time.Time{}) //nolint:errcheck // It is fine to ignore the error here
+
+	if !isHTTP2 {
+		select {
+		case ln.conns <- acceptResult{conn: conn}:
+		case <-ln.done:
+			_ = conn.Close() //nolint:errcheck // It is fine to ignore the error here
+		}
+		return
+	}
+
+	var requestConn net.Conn = &http2Conn{Conn: conn}
+	if state != nil {
+		requestConn = &http2TLSConn{http2Conn: http2Conn{Conn: conn}, state: *state}
+	}
+	ln.h2.ServeConn(conn, &http2.ServeConnOpts{
+		BaseConfig: ln.server,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ln.app.serveHTTP2(w, r, requestConn)
+		}),
+	})
+}
+
+// hasHTTP2Preface reports whether the connection starts with the HTTP/2 connection preface.
+// The returned connection still contains the read bytes.
+func hasHTTP2Preface(conn net.Conn) (bool, net.Conn) {
+	bc := &bufferedConn{Conn: conn, r: bufio.NewReaderSize(conn, len(http2Preface))}
+	// Peek byte by byte, so short HTTP/1.1 requests aren't blocked
+	for i := 1; i <= len(http2Preface); i++ {
+		b, err := bc.r.Peek(i)
+		if err != nil || b[i-1] != http2Preface[i-1] {
+			return false, bc
+		}
+	}
+	return true, bc
+}
+
+// bufferedConn is a connection whose first bytes were already read into r
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b) //nolint:wrapcheck // This must not be wrapped
+}
+
+// http2Conn is the connection of the requests of an HTTP/2 connection, it only provides
+// the addresses of the connection, as its streams are read and written by the HTTP/2 server
+type http2Conn struct {
+	net.Conn
+}
+
+func (*http2Conn) Read([]byte) (int, error)         { return 0, errHTTP2Conn }
+func (*http2Conn) Write([]byte) (int, error)        { return 0, errHTTP2Conn }
+func (*http2Conn) Close() error                     { return errHTTP2Conn }
+func (*http2Conn) SetDeadline(time.Time) error      { return errHTTP2Conn }
+func (*http2Conn) SetReadDeadline(time.Time) error  { return errHTTP2Conn }
+func (*http2Conn) SetWriteDeadline(time.Time) error { return errHTTP2Conn }
+
+// http2TLSConn is the connection of the requests of an HTTP/2 connection over TLS,
+// so fasthttp.RequestCtx.IsTLS and TLSConnectionState work
+type http2TLSConn struct {
+	http2Conn
+	state tls.ConnectionState
+}
+
+func (*http2TLSConn) Handshake() error                       { return nil }
+func (c *http2TLSConn) ConnectionState() tls.ConnectionState { return c.state }
+
+// fasthttpLogger writes the logs of fasthttp.RequestCtx.Logger with the log package
+type fasthttpLogger struct{}
+
+func (fasthttpLogger) Printf(format string, args ...any) {
+	log.Infof(format, args...)
+}
+
+// flushWriter flushes every write, so streamed responses reach the client immediately
+type flushWriter struct {
+	w http.ResponseWriter
+	f http.Flusher
+}
+
+func (fw flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	fw.f.Flush()
+	return n, err //nolint:wrapcheck // This must not be wrapped
+}
+
+// serveHTTP2 serves a request of an HTTP/2 connection with the request handler of the app
+func (app *App) serveHTTP2(w http.ResponseWriter, r *http.Request, conn net.Conn) {
+	fctx, ok := http2CtxPool.Get().(*fasthttp.RequestCtx)
+	if !ok {
+		panic(errors.New("failed to type-assert to *fasthttp.RequestCtx"))
+	}
+	defer func() {
+		fctx.Request.Reset()
+		fctx.Response.Reset()
+		http2CtxPool.Put(fctx)
+	}()
+	fctx.Init2(conn, fasthttpLogger{}, app.config.ReduceMemoryUsage)
+
+	// Convert net/http -> fasthttp request
+	req := &fctx.Request
+	if app.config.DisableHeaderNormalizing {
+		req.Header.DisableNormalizing()
+	}
+	req.Header.SetMethod(r.Method)
+	req.Header.SetProtocol(r.Proto)
+	req.SetRequestURI(r.RequestURI)
+	req.Header.SetHost(r.Host)
+	for key, values := range r.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if r.Body != nil && r.Body != http.NoBody {
+		bodyLimit := app.config.BodyLimit
+		if limit := app.headerReceived(&req.Header).MaxRequestBodySize; limit > 0 {
+			bodyLimit = limit
+		}
+		if r.ContentLength > int64(bodyLimit) {
+			http.Error(w, utils.StatusMessage(StatusRequestEntityTooLarge), StatusRequestEntityTooLarge)
+			return
+		}
+		n, err := io.Copy(req.BodyWriter(), io.LimitReader(r.Body, int64(bodyLimit)+1))
+		if err != nil {
+			http.Error(w, utils.StatusMessage(StatusBadRequest), StatusBadRequest)
+			return
+		}
+		if n > int64(bodyLimit) {
+			http.Error(w, utils.StatusMessage(StatusRequestEntityTooLarge), StatusRequestEntityTooLarge)
+			return
+		}
+		req.Header.SetContentLength(int(n))
+	}
+
+	// Serve the request
+	fctx.Response.Header.SetNoDefaultContentType(app.config.DisableDefaultContentType)
+	app.requestHandler(fctx)
+
+	// Convert fasthttp -> net/http response
+	header := w.Header()
+	fctx.Response.Header.VisitAll(func(k, v []byte) {
+		switch key := string(k); key {
+		// Connection-specific headers are not allowed in HTTP/2
+		case HeaderConnection, HeaderTransferEncoding, HeaderKeepAlive, HeaderUpgrade, "Proxy-Connection":
+		default:
+			header.Add(key, string(v))
+		}
+	})
+	if app.config.ServerHeader != "" && header.Get(HeaderServer) == "" {
+		header.Set(HeaderServer, app.config.ServerHeader)
+	}
+	if app.config.DisableDefaultDate {
+		header[HeaderDate] = nil
+	}
+	w.WriteHeader(fctx.Response.StatusCode())
+
+	var body io.Writer = w
+	if flusher, ok := w.(http.Flusher); ok && fctx.Response.IsBodyStream() {
+		body = flushWriter{w: w, f: flusher}
+	}
+	if err := fctx.Response.BodyWriteTo(body); err != nil {
+		log.Debugf("http2: failed to write response body: %v", err)
+	}
+}
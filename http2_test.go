@@ -0,0 +1,158 @@
+package fiber
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+)
+
+// listenHTTP2 starts the app with the config and returns its address
+func listenHTTP2(t *testing.T, app *App, cfg ListenConfig) string {
+	t.Helper()
+	addr := make(chan string, 1)
+	cfg.DisableStartupMessage = true
+	cfg.ListenerAddrFunc = func(a net.Addr) {
+		addr <- a.String()
+	}
+	go func() {
+		assert.NoError(t, app.Listen("127.0.0.1:0", cfg))
+	}()
+	t.Cleanup(func() {
+		assert.NoError(t, app.Shutdown())
+	})
+	return <-addr
+}
+
+func http2TestApp(config ...Config) *App {
+	app := New(config...)
+	app.Get("/", func(c Ctx) error {
+		return c.SendString(c.Protocol() + " " + c.Scheme() + " " + c.Get("X-Test"))
+	})
+	app.Post("/", func(c Ctx) error {
+		return c.Send(c.Body())
+	})
+	app.Get("/stream", func(c Ctx) error {
+		return c.SendStreamWriter(func(w *bufio.Writer) {
+			for i := 0; i < 3; i++ {
+				_, _ = w.WriteString("chunk" + strconv.Itoa(i) + "\n") //nolint:errcheck // It is fine to ignore the error here
+				_ = w.Flush()                                          //nolint:errcheck // It is fine to ignore the error here
+			}
+		})
+	})
+	return app
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close() //nolint:errcheck // It is fine to ignore the error here
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+// go test -run Test_Listen_HTTP2
+func Test_Listen_HTTP2(t *testing.T) {
+	t.Parallel()
+	app := http2TestApp(Config{ServerHeader: "Fiber"})
+	addr := listenHTTP2(t, app, ListenConfig{
+		CertFile:    "./.github/testdata/ssl.pem",
+		CertKeyFile: "./.github/testdata/ssl.key",
+		EnableHTTP2: true,
+	})
+	tlsConfig := &tls.Config{InsecureSkipVerify: true} //nolint:gosec // We're in a test
+
+	client := &http.Client{Transport: &http2.Transport{TLSClientConfig: tlsConfig}}
+	req, err := http.NewRequestWithContext(context.Background(), MethodGet, "https://"+addr+"/", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Test", "h2")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, 2, resp.ProtoMajor)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, "Fiber", resp.Header.Get(HeaderServer))
+	require.Equal(t, MIMETextPlainCharsetUTF8, resp.Header.Get(HeaderContentType))
+	require.Equal(t, "HTTP/2.0 https h2", readBody(t, resp))
+
+	// Streamed responses
+	resp, err = client.Get("https://" + addr + "/stream") //nolint:noctx // We're in a test
+	require.NoError(t, err)
+	require.Equal(t, "chunk0\nchunk1\nchunk2\n", readBody(t, resp))
+
+	// HTTP/1.1 clients are still served by fasthttp
+	client = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	resp, err = client.Get("https://" + addr + "/") //nolint:noctx // We're in a test
+	require.NoError(t, err)
+	require.Equal(t, 1, resp.ProtoMajor)
+	require.Equal(t, "HTTP/1.1 https ", readBody(t, resp))
+}
+
+// go test -run Test_Listen_H2C
+func Test_Listen_H2C(t *testing.T) {
+	t.Parallel()
+	app := http2TestApp(Config{BodyLimit: 8})
+	addr := listenHTTP2(t, app, ListenConfig{EnableH2C: true})
+
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+	resp, err := client.Get("http://" + addr + "/") //nolint:noctx // We're in a test
+	require.NoError(t, err)
+	require.Equal(t, 2, resp.ProtoMajor)
+	require.Equal(t, "HTTP/2.0 http ", readBody(t, resp))
+
+	resp, err = client.Post("http://"+addr+"/", MIMETextPlain, strings.NewReader("body")) //nolint:noctx // We're in a test
+	require.NoError(t, err)
+	require.Equal(t, "body", readBody(t, resp))
+
+	resp, err = client.Post("http://"+addr+"/", MIMETextPlain, strings.NewReader("too large body")) //nolint:noctx // We're in a test
+	require.NoError(t, err)
+	require.Equal(t, StatusRequestEntityTooLarge, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	// HTTP/1.1 requests are still served by fasthttp
+	resp, err = (&http.Client{Timeout: 5 * time.Second}).Get("http://" + addr + "/") //nolint:noctx // We're in a test
+	require.NoError(t, err)
+	require.Equal(t, 1, resp.ProtoMajor)
+	require.Equal(t, "HTTP/1.1 http ", readBody(t, resp))
+}
+
+// go test -run Test_HasHTTP2Preface
+func Test_HasHTTP2Preface(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		data  string
+		http2 bool
+	}{
+		{data: http2Preface, http2: true},
+		{data: "GET / HTTP/1.1\r\n\r\n"},
+		{data: "PUT / HTTP/1.1\r\n\r\n"},
+		{data: "PRI"},
+	} {
+		server, client := net.Pipe()
+		go func() {
+			_, _ = client.Write([]byte(tt.data)) //nolint:errcheck // It is fine to ignore the error here
+			_ = client.Close()                   //nolint:errcheck // It is fine to ignore the error here
+		}()
+		isHTTP2, conn := hasHTTP2Preface(server)
+		require.Equal(t, tt.http2, isHTTP2, tt.data)
+
+		// The peeked bytes are still read from the connection
+		data, err := io.ReadAll(conn)
+		require.NoError(t, err)
+		require.Equal(t, tt.data, string(data))
+	}
+}
@@ -0,0 +1,57 @@
+package httpcache
+
+import (
+	"strconv"
+	"time"
+)
+
+// ParseAge parses the Age header, which is a non-negative number of seconds
+func ParseAge(header string) (time.Duration, bool) {
+	seconds, ok := parseSeconds(header)
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// FormatAge returns the age in the format of the Age header, in whole seconds
+func FormatAge(age time.Duration) string {
+	if age < 0 {
+		age = 0
+	}
+	return strconv.FormatInt(int64(age/time.Second), 10)
+}
+
+// CurrentAge returns the current age of a stored response, as specified in RFC 9111
+// section 4.2.3. The age is the value of the Age header, date the value of the Date
+// header and responseTime the time the response was received.
+func CurrentAge(age time.Duration, date, responseTime, now time.Time) time.Duration {
+	apparentAge := responseTime.Sub(date)
+	if apparentAge < 0 || date.IsZero() {
+		apparentAge = 0
+	}
+	correctedAge := max(apparentAge, age)
+	if resident := now.Sub(responseTime); resident > 0 {
+		correctedAge += resident
+	}
+	return correctedAge
+}
+
+// FreshnessLifetime returns the freshness lifetime of a response, as specified in
+// RFC 9111 section 4.2.1, from the s-maxage directive for shared caches, the max-age
+// directive, or the difference of the Expires and Date headers. It returns false if
+// the response has no explicit lifetime.
+func FreshnessLifetime(cc CacheControl, expires, date time.Time, shared bool) (time.Duration, bool) {
+	switch {
+	case shared && cc.SMaxAge >= 0:
+		return time.Duration(cc.SMaxAge) * time.Second, true
+	case cc.MaxAge >= 0:
+		return time.Duration(cc.MaxAge) * time.Second, true
+	case !expires.IsZero():
+		if date.IsZero() {
+			return 0, false
+		}
+		return max(expires.Sub(date), 0), true
+	}
+	return 0, false
+}
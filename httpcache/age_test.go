@@ -0,0 +1,66 @@
+package httpcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_Age
+func Test_Age(t *testing.T) {
+	t.Parallel()
+	age, ok := ParseAge("60")
+	require.True(t, ok)
+	require.Equal(t, time.Minute, age)
+	_, ok = ParseAge("-1")
+	require.False(t, ok)
+	_, ok = ParseAge("")
+	require.False(t, ok)
+
+	require.Equal(t, "90", FormatAge(90*time.Second+500*time.Millisecond))
+	require.Equal(t, "0", FormatAge(-time.Second))
+}
+
+// go test -run Test_CurrentAge
+func Test_CurrentAge(t *testing.T) {
+	t.Parallel()
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	received := date.Add(10 * time.Second)
+	now := received.Add(time.Minute)
+
+	// The apparent age is larger than the age header
+	require.Equal(t, 70*time.Second, CurrentAge(5*time.Second, date, received, now))
+	// The age header is larger than the apparent age
+	require.Equal(t, 90*time.Second, CurrentAge(30*time.Second, date, received, now))
+	// A date in the future and a missing date are ignored
+	require.Equal(t, time.Minute, CurrentAge(0, now, received, now))
+	require.Equal(t, time.Minute, CurrentAge(0, time.Time{}, received, now))
+}
+
+// go test -run Test_FreshnessLifetime
+func Test_FreshnessLifetime(t *testing.T) {
+	t.Parallel()
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expires := date.Add(time.Hour)
+
+	cc := ParseCacheControl("max-age=60, s-maxage=120")
+	lifetime, ok := FreshnessLifetime(cc, expires, date, false)
+	require.True(t, ok)
+	require.Equal(t, time.Minute, lifetime)
+	lifetime, ok = FreshnessLifetime(cc, expires, date, true)
+	require.True(t, ok)
+	require.Equal(t, 2*time.Minute, lifetime)
+
+	lifetime, ok = FreshnessLifetime(NewCacheControl(), expires, date, true)
+	require.True(t, ok)
+	require.Equal(t, time.Hour, lifetime)
+	lifetime, ok = FreshnessLifetime(NewCacheControl(), date, expires, true)
+	require.True(t, ok)
+	require.Equal(t, time.Duration(0), lifetime)
+
+	_, ok = FreshnessLifetime(NewCacheControl(), expires, time.Time{}, true)
+	require.False(t, ok)
+	_, ok = FreshnessLifetime(NewCacheControl(), time.Time{}, date, true)
+	require.False(t, ok)
+}
@@ -0,0 +1,312 @@
+package httpcache
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/utils/v2"
+)
+
+// Absent is the value of the durations of a CacheControl whose directive is absent
+const Absent = -1
+
+// MaxStaleAny is the value of CacheControl.MaxStale for the max-stale directive without
+// a value, which accepts responses of any staleness
+const MaxStaleAny = math.MaxInt32
+
+// Cache-Control directives
+const (
+	DirectiveMaxAge               = "max-age"
+	DirectiveSMaxAge              = "s-maxage"
+	DirectiveMaxStale             = "max-stale"
+	DirectiveMinFresh             = "min-fresh"
+	DirectiveStaleWhileRevalidate = "stale-while-revalidate"
+	DirectiveStaleIfError         = "stale-if-error"
+	DirectiveNoCache              = "no-cache"
+	DirectiveNoStore              = "no-store"
+	DirectiveNoTransform          = "no-transform"
+	DirectiveOnlyIfCached         = "only-if-cached"
+	DirectivePublic               = "public"
+	DirectivePrivate              = "private"
+	DirectiveMustRevalidate       = "must-revalidate"
+	DirectiveProxyRevalidate      = "proxy-revalidate"
+	DirectiveMustUnderstand       = "must-understand"
+	DirectiveImmutable            = "immutable"
+)
+
+// CacheControl contains the directives of a Cache-Control request or response header.
+// The durations are in seconds and Absent if the directive is absent, NewCacheControl
+// returns a CacheControl without directives.
+type CacheControl struct {
+	// Extensions are the unknown directives with their values, which are empty for
+	// directives without a value
+	Extensions map[string]string
+
+	MaxAge               int
+	SMaxAge              int
+	MaxStale             int
+	MinFresh             int
+	StaleWhileRevalidate int
+	StaleIfError         int
+
+	NoCache         bool
+	NoStore         bool
+	NoTransform     bool
+	OnlyIfCached    bool
+	Public          bool
+	Private         bool
+	MustRevalidate  bool
+	ProxyRevalidate bool
+	MustUnderstand  bool
+	Immutable       bool
+}
+
+// NewCacheControl returns a CacheControl without directives
+func NewCacheControl() CacheControl {
+	return CacheControl{
+		MaxAge:               Absent,
+		SMaxAge:              Absent,
+		MaxStale:             Absent,
+		MinFresh:             Absent,
+		StaleWhileRevalidate: Absent,
+		StaleIfError:         Absent,
+	}
+}
+
+// ParseCacheControl parses the directives of a Cache-Control header. Directive names
+// are case-insensitive, duplicated directives use the first occurrence and
+// durations which aren't valid are ignored.
+func ParseCacheControl(header string) CacheControl {
+	cc := NewCacheControl()
+	forEachDirective(header, func(name, value string) {
+		if hasUpper(name) {
+			name = utils.ToLower(name)
+		}
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = unquoteValue(value[1 : len(value)-1])
+		}
+		switch name {
+		case DirectiveMaxAge:
+			setSeconds(&cc.MaxAge, value)
+		case DirectiveSMaxAge:
+			setSeconds(&cc.SMaxAge, value)
+		case DirectiveMaxStale:
+			if value == "" {
+				if cc.MaxStale == Absent {
+					cc.MaxStale = MaxStaleAny
+				}
+			} else {
+				setSeconds(&cc.MaxStale, value)
+			}
+		case DirectiveMinFresh:
+			setSeconds(&cc.MinFresh, value)
+		case DirectiveStaleWhileRevalidate:
+			setSeconds(&cc.StaleWhileRevalidate, value)
+		case DirectiveStaleIfError:
+			setSeconds(&cc.StaleIfError, value)
+		case DirectiveNoCache:
+			cc.NoCache = true
+		case DirectiveNoStore:
+			cc.NoStore = true
+		case DirectiveNoTransform:
+			cc.NoTransform = true
+		case DirectiveOnlyIfCached:
+			cc.OnlyIfCached = true
+		case DirectivePublic:
+			cc.Public = true
+		case DirectivePrivate:
+			cc.Private = true
+		case DirectiveMustRevalidate:
+			cc.MustRevalidate = true
+		case DirectiveProxyRevalidate:
+			cc.ProxyRevalidate = true
+		case DirectiveMustUnderstand:
+			cc.MustUnderstand = true
+		case DirectiveImmutable:
+			cc.Immutable = true
+		default:
+			if cc.Extensions == nil {
+				cc.Extensions = make(map[string]string)
+			}
+			if _, ok := cc.Extensions[name]; !ok {
+				cc.Extensions[name] = value
+			}
+		}
+	})
+	return cc
+}
+
+// String returns the directives in the format of the Cache-Control header, the
+// extensions are sorted by name
+func (cc CacheControl) String() string {
+	var b strings.Builder
+	flag := func(set bool, name string) {
+		if !set {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(name)
+	}
+	seconds := func(v int, name string) {
+		if v < 0 {
+			return
+		}
+		flag(true, name)
+		if name != DirectiveMaxStale || v != MaxStaleAny {
+			b.WriteByte('=')
+			b.WriteString(strconv.Itoa(v))
+		}
+	}
+
+	flag(cc.Public, DirectivePublic)
+	flag(cc.Private, DirectivePrivate)
+	flag(cc.NoCache, DirectiveNoCache)
+	flag(cc.NoStore, DirectiveNoStore)
+	seconds(cc.MaxAge, DirectiveMaxAge)
+	seconds(cc.SMaxAge, DirectiveSMaxAge)
+	seconds(cc.MaxStale, DirectiveMaxStale)
+	seconds(cc.MinFresh, DirectiveMinFresh)
+	seconds(cc.StaleWhileRevalidate, DirectiveStaleWhileRevalidate)
+	seconds(cc.StaleIfError, DirectiveStaleIfError)
+	flag(cc.NoTransform, DirectiveNoTransform)
+	flag(cc.OnlyIfCached, DirectiveOnlyIfCached)
+	flag(cc.MustRevalidate, DirectiveMustRevalidate)
+	flag(cc.ProxyRevalidate, DirectiveProxyRevalidate)
+	flag(cc.MustUnderstand, DirectiveMustUnderstand)
+	flag(cc.Immutable, DirectiveImmutable)
+
+	names := make([]string, 0, len(cc.Extensions))
+	for name := range cc.Extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		flag(true, name)
+		if value := cc.Extensions[name]; value != "" {
+			b.WriteByte('=')
+			b.WriteString(quoteValue(value))
+		}
+	}
+	return b.String()
+}
+
+// HasDirective reports whether the Cache-Control header contains the directive, the
+// comparison is case-insensitive. It doesn't allocate.
+func HasDirective(header, directive string) bool {
+	found := false
+	forEachDirective(header, func(name, _ string) {
+		if !found && utils.EqualFold(name, directive) {
+			found = true
+		}
+	})
+	return found
+}
+
+// forEachDirective calls fn with the names and the values of the directives of the header
+func forEachDirective(header string, fn func(name, value string)) {
+	for len(header) > 0 {
+		var directive string
+		// Commas in quoted values don't separate directives
+		if i := indexComma(header); i != -1 {
+			directive, header = header[:i], header[i+1:]
+		} else {
+			directive, header = header, ""
+		}
+		name, value, _ := strings.Cut(directive, "=")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		fn(name, strings.TrimSpace(value))
+	}
+}
+
+// indexComma returns the index of the first comma outside of quotes, or -1
+func indexComma(s string) int {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func hasUpper(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 'A' && s[i] <= 'Z' {
+			return true
+		}
+	}
+	return false
+}
+
+// unquoteValue removes the backslashes of quoted pairs
+func unquoteValue(s string) string {
+	if strings.IndexByte(s, '\\') == -1 {
+		return s
+	}
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b = append(b, s[i])
+	}
+	return string(b)
+}
+
+// quoteValue returns the value as quoted string if it isn't a token
+func quoteValue(s string) string {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c <= ' ' || c >= 0x7f || strings.IndexByte(`"(),/:;<=>?@[\]{}`, c) != -1 {
+			return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+		}
+	}
+	return s
+}
+
+// setSeconds sets the duration of a directive, if it is the first occurrence and valid
+func setSeconds(dst *int, value string) {
+	if *dst != Absent {
+		return
+	}
+	if v, ok := parseSeconds(value); ok {
+		*dst = v
+	}
+}
+
+// parseSeconds parses a non-negative number of seconds, larger values than the maximum
+// int32 are limited to it, as recommended by RFC 9111 section 1.2.2
+func parseSeconds(value string) (int, bool) {
+	if value == "" {
+		return 0, false
+	}
+	n := 0
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		if n < math.MaxInt32 {
+			n = n*10 + int(c-'0')
+		}
+	}
+	if n > math.MaxInt32 {
+		n = math.MaxInt32
+	}
+	return n, true
+}
@@ -0,0 +1,74 @@
+package httpcache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_ParseCacheControl
+func Test_ParseCacheControl(t *testing.T) {
+	t.Parallel()
+	cc := ParseCacheControl(`Public, max-age=60, max-age=10, s-maxage="120", no-cache="Set-Cookie, X-Id", immutable, community="UCI", x-ext`)
+	require.True(t, cc.Public)
+	require.True(t, cc.NoCache)
+	require.True(t, cc.Immutable)
+	require.False(t, cc.NoStore)
+	require.Equal(t, 60, cc.MaxAge)
+	require.Equal(t, 120, cc.SMaxAge)
+	require.Equal(t, Absent, cc.MaxStale)
+	require.Equal(t, map[string]string{"community": "UCI", "x-ext": ""}, cc.Extensions)
+
+	cc = ParseCacheControl("max-stale, min-fresh=abc, stale-if-error=99999999999, only-if-cached")
+	require.Equal(t, MaxStaleAny, cc.MaxStale)
+	require.Equal(t, Absent, cc.MinFresh)
+	require.Equal(t, 2147483647, cc.StaleIfError)
+	require.True(t, cc.OnlyIfCached)
+
+	require.Equal(t, NewCacheControl(), ParseCacheControl(""))
+	require.Equal(t, NewCacheControl(), ParseCacheControl(" , ,"))
+}
+
+// go test -run Test_CacheControl_String
+func Test_CacheControl_String(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "", NewCacheControl().String())
+
+	cc := NewCacheControl()
+	cc.Public = true
+	cc.MaxAge = 0
+	cc.StaleWhileRevalidate = 30
+	cc.MustRevalidate = true
+	cc.Extensions = map[string]string{"b": "x y", "a": "", "c": `q"`}
+	require.Equal(t, `public, max-age=0, stale-while-revalidate=30, must-revalidate, a, b="x y", c="q\""`, cc.String())
+
+	cc = NewCacheControl()
+	cc.MaxStale = MaxStaleAny
+	require.Equal(t, "max-stale", cc.String())
+
+	// The header is parsed to the same directives
+	header := `private, no-store, s-maxage=5, max-stale=10, min-fresh=1, stale-if-error=2, no-transform, proxy-revalidate, must-understand, x="a,b"`
+	require.Equal(t, header, ParseCacheControl(header).String())
+}
+
+// go test -run Test_HasDirective
+func Test_HasDirective(t *testing.T) {
+	t.Parallel()
+	require.True(t, HasDirective("public, no-cache", DirectiveNoCache))
+	require.True(t, HasDirective("No-Cache", DirectiveNoCache))
+	require.True(t, HasDirective(`no-cache="Set-Cookie", public`, DirectiveNoCache))
+	require.False(t, HasDirective("no-cache-x, xno-cache", DirectiveNoCache))
+	require.False(t, HasDirective(`x="no-cache, no-store"`, DirectiveNoStore))
+	require.False(t, HasDirective("", DirectiveNoCache))
+}
+
+// go test -v -run=^$ -bench=Benchmark_HasDirective -benchmem -count=4
+func Benchmark_HasDirective(b *testing.B) {
+	var ok bool
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ok = HasDirective("public, max-age=30, No-Cache", DirectiveNoCache)
+	}
+	require.True(b, ok)
+}
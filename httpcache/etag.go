@@ -0,0 +1,130 @@
+// Package httpcache provides functions for computing, parsing and comparing the
+// validators and freshness headers of HTTP caching, ETag, Last-Modified,
+// Cache-Control and Age, as specified in RFC 9110 and RFC 9111. It is used by the
+// cache, etag and static middleware and can be used by handlers which implement
+// custom caching.
+package httpcache
+
+import (
+	"errors"
+	"hash/crc32"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidETag is returned by ParseETag for malformed entity tags
+var ErrInvalidETag = errors.New("httpcache: invalid entity tag")
+
+// crc32q is the table of the default ETags
+var crc32q = crc32.MakeTable(0xD5828281)
+
+// ETag is an entity tag
+type ETag struct {
+	Tag  string // Opaque tag without quotes
+	Weak bool   // Weak validator, the tag has the W/ prefix
+}
+
+// ParseETag parses an entity tag like `"abc"` or `W/"abc"`
+func ParseETag(s string) (ETag, error) {
+	s = strings.TrimSpace(s)
+	var e ETag
+	if strings.HasPrefix(s, "W/") {
+		e.Weak = true
+		s = s[2:]
+	}
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' || strings.IndexByte(s[1:len(s)-1], '"') != -1 {
+		return ETag{}, ErrInvalidETag
+	}
+	e.Tag = s[1 : len(s)-1]
+	return e, nil
+}
+
+// String returns the entity tag in the format of the ETag header
+func (e ETag) String() string {
+	if e.Weak {
+		return `W/"` + e.Tag + `"`
+	}
+	return `"` + e.Tag + `"`
+}
+
+// AppendETag appends the default ETag of the body, its length and CRC-32 checksum,
+// to dst and returns the extended dst
+func AppendETag(dst, body []byte, weak bool) []byte {
+	if weak {
+		dst = append(dst, "W/"...)
+	}
+	dst = append(dst, '"')
+	dst = strconv.AppendUint(dst, uint64(len(body)), 10)
+	dst = append(dst, '-')
+	dst = strconv.AppendUint(dst, uint64(crc32.Checksum(body, crc32q)), 10)
+	return append(dst, '"')
+}
+
+// FileETag returns the strong entity tag of a file, which is derived from its modification
+// time and size like the entity tags of nginx, so it changes when the file is modified
+func FileETag(modTime time.Time, size int64) ETag {
+	return ETag{Tag: strconv.FormatInt(modTime.Unix(), 16) + "-" + strconv.FormatInt(size, 16)}
+}
+
+// StrongCompare reports whether the entity tags are equal by the strong comparison,
+// both tags must be strong
+func StrongCompare(a, b string) bool {
+	return !strings.HasPrefix(a, "W/") && !strings.HasPrefix(b, "W/") && a == b
+}
+
+// WeakCompare reports whether the entity tags are equal by the weak comparison, the
+// weak prefixes are ignored
+func WeakCompare(a, b string) bool {
+	return strings.TrimPrefix(a, "W/") == strings.TrimPrefix(b, "W/")
+}
+
+// NoneMatch reports whether the If-None-Match header matches the entity tag with the
+// weak comparison, which means a GET or HEAD request can be answered with
+// 304 Not Modified. The wildcard "*" matches any entity tag.
+func NoneMatch(ifNoneMatch, etag string) bool {
+	return matchList(ifNoneMatch, etag, WeakCompare)
+}
+
+// Match reports whether the If-Match header matches the entity tag with the strong
+// comparison, a request which doesn't match should be answered with
+// 412 Precondition Failed. The wildcard "*" matches any entity tag.
+func Match(ifMatch, etag string) bool {
+	return matchList(ifMatch, etag, StrongCompare)
+}
+
+// matchList reports whether an entity tag of the comma separated list matches the
+// entity tag, commas inside of quoted tags are part of the tag
+func matchList(list, etag string, compare func(a, b string) bool) bool {
+	if etag == "" {
+		return false
+	}
+	for i := 0; i < len(list); {
+		// Skip whitespace and empty elements
+		if c := list[i]; c == ' ' || c == '\t' || c == ',' {
+			i++
+			continue
+		}
+		start := i
+		if strings.HasPrefix(list[i:], "W/") {
+			i += 2
+		}
+		if i < len(list) && list[i] == '"' {
+			if end := strings.IndexByte(list[i+1:], '"'); end != -1 {
+				i += end + 2
+			} else {
+				i = len(list)
+			}
+		} else if end := strings.IndexByte(list[i:], ','); end != -1 {
+			// Unquoted tags are compared as they are
+			i += end
+		} else {
+			i = len(list)
+		}
+		tag := strings.TrimRight(list[start:i], " \t")
+		if tag == "*" || compare(tag, etag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package httpcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_ParseETag
+func Test_ParseETag(t *testing.T) {
+	t.Parallel()
+	e, err := ParseETag(`"abc"`)
+	require.NoError(t, err)
+	require.Equal(t, ETag{Tag: "abc"}, e)
+	require.Equal(t, `"abc"`, e.String())
+
+	e, err = ParseETag(` W/"a,b" `)
+	require.NoError(t, err)
+	require.Equal(t, ETag{Tag: "a,b", Weak: true}, e)
+	require.Equal(t, `W/"a,b"`, e.String())
+
+	for _, invalid := range []string{"", `"`, "abc", `W/abc`, `"a"b"`, `w/"a"`} {
+		_, err = ParseETag(invalid)
+		require.ErrorIs(t, err, ErrInvalidETag, invalid)
+	}
+}
+
+// go test -run Test_AppendETag
+func Test_AppendETag(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, `"13-1831710635"`, string(AppendETag(nil, []byte("Hello, World!"), false)))
+	require.Equal(t, `W/"13-1831710635"`, string(AppendETag(nil, []byte("Hello, World!"), true)))
+}
+
+// go test -run Test_FileETag
+func Test_FileETag(t *testing.T) {
+	t.Parallel()
+	modTime := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+	require.Equal(t, `"65937d25-400"`, FileETag(modTime, 1024).String())
+	require.NotEqual(t, FileETag(modTime, 1024), FileETag(modTime.Add(time.Second), 1024))
+	require.NotEqual(t, FileETag(modTime, 1024), FileETag(modTime, 1025))
+}
+
+// go test -run Test_Compare
+func Test_Compare(t *testing.T) {
+	t.Parallel()
+	// RFC 9110 section 8.8.3.2
+	cases := []struct {
+		a, b   string
+		strong bool
+		weak   bool
+	}{
+		{a: `W/"1"`, b: `W/"1"`, strong: false, weak: true},
+		{a: `W/"1"`, b: `W/"2"`, strong: false, weak: false},
+		{a: `W/"1"`, b: `"1"`, strong: false, weak: true},
+		{a: `"1"`, b: `"1"`, strong: true, weak: true},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.strong, StrongCompare(c.a, c.b), "%s %s", c.a, c.b)
+		require.Equal(t, c.weak, WeakCompare(c.a, c.b), "%s %s", c.a, c.b)
+	}
+}
+
+// go test -run Test_NoneMatch
+func Test_NoneMatch(t *testing.T) {
+	t.Parallel()
+	require.True(t, NoneMatch(`"1"`, `"1"`))
+	require.True(t, NoneMatch(`W/"1"`, `"1"`))
+	require.True(t, NoneMatch(`"2", W/"1"`, `W/"1"`))
+	require.True(t, NoneMatch(`"2",W/"1" `, `"1"`))
+	require.True(t, NoneMatch(`"a,b", "1"`, `"1"`))
+	require.True(t, NoneMatch(`"a,b"`, `"a,b"`))
+	require.True(t, NoneMatch(`*`, `"1"`))
+	require.True(t, NoneMatch(`abc, def`, `def`))
+	require.False(t, NoneMatch(``, `"1"`))
+	require.False(t, NoneMatch(`"1"`, ``))
+	require.False(t, NoneMatch(`"a,b"`, `"a"`))
+	require.False(t, NoneMatch(`"12"`, `"1"`))
+	require.False(t, NoneMatch(`"1`, `"1"`))
+}
+
+// go test -run Test_Match
+func Test_Match(t *testing.T) {
+	t.Parallel()
+	require.True(t, Match(`"1"`, `"1"`))
+	require.True(t, Match(`"2", "1"`, `"1"`))
+	require.True(t, Match(`*`, `"1"`))
+	require.False(t, Match(`W/"1"`, `"1"`))
+	require.False(t, Match(`"1"`, `W/"1"`))
+	require.False(t, Match(`"2"`, `"1"`))
+}
+
+// go test -v -run=^$ -bench=Benchmark_NoneMatch -benchmem -count=4
+func Benchmark_NoneMatch(b *testing.B) {
+	var ok bool
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ok = NoneMatch(`"2", W/"3", "13-1831710635"`, `W/"13-1831710635"`)
+	}
+	require.True(b, ok)
+}
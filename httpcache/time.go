@@ -0,0 +1,50 @@
+package httpcache
+
+import (
+	"net/http"
+	"time"
+)
+
+// FormatTime returns the time in the HTTP date format of the Last-Modified, Date and
+// Expires headers, e.g. "Mon, 02 Jan 2006 15:04:05 GMT"
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(http.TimeFormat)
+}
+
+// AppendTime appends the time in the HTTP date format to dst and returns the extended dst
+func AppendTime(dst []byte, t time.Time) []byte {
+	return t.UTC().AppendFormat(dst, http.TimeFormat)
+}
+
+// ParseTime parses an HTTP date in the formats which are allowed by RFC 9110
+func ParseTime(s string) (time.Time, error) {
+	return http.ParseTime(s) //nolint:wrapcheck // The error of net/http is descriptive
+}
+
+// ModifiedSince reports whether the resource was modified after the date of the
+// If-Modified-Since header, in the resolution of HTTP dates. It is true if the header
+// is empty or invalid, since the header must be ignored then.
+func ModifiedSince(ifModifiedSince string, lastModified time.Time) bool {
+	if ifModifiedSince == "" {
+		return true
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return true
+	}
+	return lastModified.Truncate(time.Second).After(since)
+}
+
+// UnmodifiedSince reports whether the resource wasn't modified after the date of the
+// If-Unmodified-Since header, in the resolution of HTTP dates. It is true if the header
+// is empty or invalid, since the header must be ignored then.
+func UnmodifiedSince(ifUnmodifiedSince string, lastModified time.Time) bool {
+	if ifUnmodifiedSince == "" {
+		return true
+	}
+	since, err := http.ParseTime(ifUnmodifiedSince)
+	if err != nil {
+		return true
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
@@ -0,0 +1,40 @@
+package httpcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_Time
+func Test_Time(t *testing.T) {
+	t.Parallel()
+	tm := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+	require.Equal(t, "Tue, 02 Jan 2024 02:04:05 GMT", FormatTime(tm))
+	require.Equal(t, "x Tue, 02 Jan 2024 02:04:05 GMT", string(AppendTime([]byte("x "), tm)))
+
+	parsed, err := ParseTime("Tuesday, 02-Jan-24 02:04:05 GMT")
+	require.NoError(t, err)
+	require.True(t, parsed.Equal(tm))
+	_, err = ParseTime("yesterday")
+	require.Error(t, err)
+}
+
+// go test -run Test_ModifiedSince
+func Test_ModifiedSince(t *testing.T) {
+	t.Parallel()
+	lastModified := time.Date(2024, 1, 2, 3, 4, 5, 500, time.UTC)
+	header := FormatTime(lastModified)
+
+	// The fraction of the second isn't compared
+	require.False(t, ModifiedSince(header, lastModified))
+	require.True(t, ModifiedSince(header, lastModified.Add(time.Second)))
+	require.True(t, ModifiedSince("", lastModified))
+	require.True(t, ModifiedSince("invalid", lastModified))
+
+	require.True(t, UnmodifiedSince(header, lastModified))
+	require.False(t, UnmodifiedSince(header, lastModified.Add(time.Second)))
+	require.True(t, UnmodifiedSince("", lastModified))
+	require.True(t, UnmodifiedSince("invalid", lastModified))
+}
@@ -9,7 +9,16 @@ import (
 	"github.com/gofiber/utils/v2"
 )
 
+// shardCount is the number of shards the data is split into, so concurrent
+// requests for different keys don't compete for the same lock.
+// It must be a power of two.
+const shardCount = 64
+
 type Storage struct {
+	shards [shardCount]*shard
+}
+
+type shard struct {
 	data map[string]item // data
 	sync.RWMutex
 }
@@ -21,19 +30,37 @@ type item struct {
 }
 
 func New() *Storage {
-	store := &Storage{
-		data: make(map[string]item),
+	store := &Storage{}
+	for i := range store.shards {
+		store.shards[i] = &shard{
+			data: make(map[string]item),
+		}
 	}
 	utils.StartTimeStampUpdater()
 	go store.gc(1 * time.Second)
 	return store
 }
 
+// getShard returns the shard responsible for the key, using the FNV-1a hash of the key
+func (s *Storage) getShard(key string) *shard {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	hash := uint32(offset32)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= prime32
+	}
+	return s.shards[hash&(shardCount-1)]
+}
+
 // Get value by key
 func (s *Storage) Get(key string) any {
-	s.RLock()
-	v, ok := s.data[key]
-	s.RUnlock()
+	sh := s.getShard(key)
+	sh.RLock()
+	v, ok := sh.data[key]
+	sh.RUnlock()
 	if !ok || v.e != 0 && v.e <= utils.Timestamp() {
 		return nil
 	}
@@ -47,24 +74,28 @@ func (s *Storage) Set(key string, val any, ttl time.Duration) {
 		exp = uint32(ttl.Seconds()) + utils.Timestamp()
 	}
 	i := item{e: exp, v: val}
-	s.Lock()
-	s.data[key] = i
-	s.Unlock()
+	sh := s.getShard(key)
+	sh.Lock()
+	sh.data[key] = i
+	sh.Unlock()
 }
 
 // Delete key by key
 func (s *Storage) Delete(key string) {
-	s.Lock()
-	delete(s.data, key)
-	s.Unlock()
+	sh := s.getShard(key)
+	sh.Lock()
+	delete(sh.data, key)
+	sh.Unlock()
 }
 
 // Reset all keys
 func (s *Storage) Reset() {
-	nd := make(map[string]item)
-	s.Lock()
-	s.data = nd
-	s.Unlock()
+	for _, sh := range s.shards {
+		nd := make(map[string]item)
+		sh.Lock()
+		sh.data = nd
+		sh.Unlock()
+	}
 }
 
 func (s *Storage) gc(sleep time.Duration) {
@@ -74,23 +105,28 @@ func (s *Storage) gc(sleep time.Duration) {
 
 	for range ticker.C {
 		ts := utils.Timestamp()
-		expired = expired[:0]
-		s.RLock()
-		for key, v := range s.data {
-			if v.e != 0 && v.e <= ts {
-				expired = append(expired, key)
+		for _, sh := range s.shards {
+			expired = expired[:0]
+			sh.RLock()
+			for key, v := range sh.data {
+				if v.e != 0 && v.e <= ts {
+					expired = append(expired, key)
+				}
 			}
-		}
-		s.RUnlock()
-		s.Lock()
-		// Double-checked locking.
-		// We might have replaced the item in the meantime.
-		for i := range expired {
-			v := s.data[expired[i]]
-			if v.e != 0 && v.e <= ts {
-				delete(s.data, expired[i])
+			sh.RUnlock()
+			if len(expired) == 0 {
+				continue
+			}
+			sh.Lock()
+			// Double-checked locking.
+			// We might have replaced the item in the meantime.
+			for i := range expired {
+				v := sh.data[expired[i]]
+				if v.e != 0 && v.e <= ts {
+					delete(sh.data, expired[i])
+				}
 			}
+			sh.Unlock()
 		}
-		s.Unlock()
 	}
 }
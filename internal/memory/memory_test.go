@@ -1,6 +1,8 @@
 package memory
 
 import (
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -55,6 +57,42 @@ func Test_Memory(t *testing.T) {
 	require.Nil(t, result)
 }
 
+// go test -run Test_Memory_Shards -v -race
+func Test_Memory_Shards(t *testing.T) {
+	t.Parallel()
+	store := New()
+
+	// Write concurrently to many keys, which are spread over the shards
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := strconv.Itoa(worker) + "-" + strconv.Itoa(j)
+				store.Set(key, j, 0)
+				require.Equal(t, j, store.Get(key))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var used int
+	for _, sh := range store.shards {
+		if len(sh.data) > 0 {
+			used++
+		}
+	}
+	require.Greater(t, used, 1)
+
+	// Reset clears all shards
+	store.Reset()
+	for _, sh := range store.shards {
+		require.Empty(t, sh.data)
+	}
+	require.Nil(t, store.Get("0-0"))
+}
+
 // go test -v -run=^$ -bench=Benchmark_Memory -benchmem -count=4
 func Benchmark_Memory(b *testing.B) {
 	keyLength := 1000
@@ -82,3 +120,59 @@ func Benchmark_Memory(b *testing.B) {
 		}
 	})
 }
+
+// go test -v -run=^$ -bench=Benchmark_Memory_Parallel -benchmem -count=4
+func Benchmark_Memory_Parallel(b *testing.B) {
+	keyLength := 1000
+	keys := make([]string, keyLength)
+	for i := 0; i < keyLength; i++ {
+		keys[i] = utils.UUID()
+	}
+	value := []byte("joe")
+	ttl := 2 * time.Second
+
+	b.Run("set", func(b *testing.B) {
+		d := New()
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			var i int
+			for pb.Next() {
+				d.Set(keys[i%keyLength], value, ttl)
+				i++
+			}
+		})
+	})
+
+	b.Run("get", func(b *testing.B) {
+		d := New()
+		for _, key := range keys {
+			d.Set(key, value, ttl)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			var i int
+			for pb.Next() {
+				_ = d.Get(keys[i%keyLength])
+				i++
+			}
+		})
+	})
+
+	b.Run("set_get_delete", func(b *testing.B) {
+		d := New()
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			var i int
+			for pb.Next() {
+				key := keys[i%keyLength]
+				d.Set(key, value, ttl)
+				_ = d.Get(key)
+				d.Delete(key)
+				i++
+			}
+		})
+	})
+}
@@ -3,6 +3,10 @@
 package memory
 
 import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -64,18 +68,100 @@ func (s *Storage) Set(key string, val []byte, exp time.Duration) error {
 		return nil
 	}
 
-	var expire uint32
-	if exp != 0 {
-		expire = uint32(exp.Seconds()) + utils.Timestamp()
-	}
-
-	e := entry{data: val, expiry: expire}
+	e := entry{data: val, expiry: expiryOf(exp)}
 	s.mux.Lock()
 	s.db[key] = e
 	s.mux.Unlock()
 	return nil
 }
 
+// GetWithContext gets value by key, the context is not used by the memory storage
+func (s *Storage) GetWithContext(_ context.Context, key string) ([]byte, error) {
+	return s.Get(key)
+}
+
+// SetWithContext sets key with value, the context is not used by the memory storage
+func (s *Storage) SetWithContext(_ context.Context, key string, val []byte, exp time.Duration) error {
+	return s.Set(key, val, exp)
+}
+
+// DeleteWithContext deletes key by key, the context is not used by the memory storage
+func (s *Storage) DeleteWithContext(_ context.Context, key string) error {
+	return s.Delete(key)
+}
+
+// SetNX sets key with value only if the key does not exist
+func (s *Storage) SetNX(key string, val []byte, exp time.Duration) (bool, error) {
+	// Ain't Nobody Got Time For That
+	if len(key) == 0 || len(val) == 0 {
+		return false, nil
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if v, ok := s.db[key]; ok && (v.expiry == 0 || v.expiry > utils.Timestamp()) {
+		return false, nil
+	}
+	s.db[key] = entry{data: val, expiry: expiryOf(exp)}
+	return true, nil
+}
+
+// IncrBy increments the integer value of key by delta
+func (s *Storage) IncrBy(key string, delta int64, exp time.Duration) (int64, error) {
+	if len(key) == 0 {
+		return 0, errors.New("memory: empty key")
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	v, ok := s.db[key]
+	if !ok || v.expiry != 0 && v.expiry <= utils.Timestamp() {
+		v = entry{expiry: expiryOf(exp)}
+	}
+
+	var current int64
+	if len(v.data) > 0 {
+		var err error
+		if current, err = strconv.ParseInt(utils.UnsafeString(v.data), 10, 64); err != nil {
+			return 0, errors.New("memory: value is not an integer")
+		}
+	}
+	current += delta
+	v.data = strconv.AppendInt(nil, current, 10)
+	s.db[key] = v
+	return current, nil
+}
+
+// TTL returns the remaining time to live of key
+func (s *Storage) TTL(key string) (time.Duration, error) {
+	s.mux.RLock()
+	v, ok := s.db[key]
+	s.mux.RUnlock()
+	ts := utils.Timestamp()
+	if !ok || v.expiry != 0 && v.expiry <= ts {
+		return -1, nil
+	}
+	if v.expiry == 0 {
+		return 0, nil
+	}
+	return time.Duration(v.expiry-ts) * time.Second, nil
+}
+
+// Scan returns all keys with the prefix
+func (s *Storage) Scan(prefix string) ([]string, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	ts := utils.Timestamp()
+	var keys []string
+	for key, v := range s.db {
+		if strings.HasPrefix(key, prefix) && (v.expiry == 0 || v.expiry > ts) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
 // Delete key by key
 func (s *Storage) Delete(key string) error {
 	// Ain't Nobody Got Time For That
@@ -103,6 +189,14 @@ func (s *Storage) Close() error {
 	return nil
 }
 
+// expiryOf converts the expiration duration to a timestamp, 0 means no expiration
+func expiryOf(exp time.Duration) uint32 {
+	if exp == 0 {
+		return 0
+	}
+	return uint32(exp.Seconds()) + utils.Timestamp()
+}
+
 func (s *Storage) gc() {
 	ticker := time.NewTicker(s.gcInterval)
 	defer ticker.Stop()
@@ -1,12 +1,16 @@
 package memory
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/gofiber/fiber/v3"
 	"github.com/stretchr/testify/require"
 )
 
+var _ fiber.StorageV2 = (*Storage)(nil)
+
 func Test_Storage_Memory_Set(t *testing.T) {
 	t.Parallel()
 	var (
@@ -202,6 +206,103 @@ func Test_Storage_Memory_Conn(t *testing.T) {
 	require.NotNil(t, testStore.Conn())
 }
 
+func Test_Storage_Memory_WithContext(t *testing.T) {
+	t.Parallel()
+	var (
+		testStore = New()
+		ctx       = context.Background()
+		key       = "john"
+		val       = []byte("doe")
+	)
+
+	require.NoError(t, testStore.SetWithContext(ctx, key, val, 0))
+
+	result, err := testStore.GetWithContext(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, val, result)
+
+	require.NoError(t, testStore.DeleteWithContext(ctx, key))
+
+	result, err = testStore.GetWithContext(ctx, key)
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
+
+func Test_Storage_Memory_SetNX(t *testing.T) {
+	t.Parallel()
+	testStore := New()
+
+	stored, err := testStore.SetNX("john", []byte("doe"), 0)
+	require.NoError(t, err)
+	require.True(t, stored)
+
+	stored, err = testStore.SetNX("john", []byte("smith"), 0)
+	require.NoError(t, err)
+	require.False(t, stored)
+
+	result, err := testStore.Get("john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("doe"), result)
+}
+
+func Test_Storage_Memory_IncrBy(t *testing.T) {
+	t.Parallel()
+	testStore := New()
+
+	value, err := testStore.IncrBy("counter", 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), value)
+
+	value, err = testStore.IncrBy("counter", 5, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), value)
+
+	value, err = testStore.IncrBy("counter", -2, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), value)
+
+	require.NoError(t, testStore.Set("john", []byte("doe"), 0))
+	_, err = testStore.IncrBy("john", 1, 0)
+	require.Error(t, err)
+}
+
+func Test_Storage_Memory_TTL(t *testing.T) {
+	t.Parallel()
+	testStore := New()
+
+	ttl, err := testStore.TTL("missing")
+	require.NoError(t, err)
+	require.Negative(t, ttl)
+
+	require.NoError(t, testStore.Set("forever", []byte("doe"), 0))
+	ttl, err = testStore.TTL("forever")
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(0), ttl)
+
+	require.NoError(t, testStore.Set("john", []byte("doe"), time.Minute))
+	ttl, err = testStore.TTL("john")
+	require.NoError(t, err)
+	require.Greater(t, ttl, 58*time.Second)
+	require.LessOrEqual(t, ttl, time.Minute)
+}
+
+func Test_Storage_Memory_Scan(t *testing.T) {
+	t.Parallel()
+	testStore := New()
+
+	require.NoError(t, testStore.Set("user:1", []byte("john"), 0))
+	require.NoError(t, testStore.Set("user:2", []byte("jane"), 0))
+	require.NoError(t, testStore.Set("session:1", []byte("abc"), 0))
+
+	keys, err := testStore.Scan("user:")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"user:1", "user:2"}, keys)
+
+	keys, err = testStore.Scan("post:")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
+
 // Benchmarks for Set operation
 func Benchmark_Memory_Set(b *testing.B) {
 	testStore := New()
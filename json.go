@@ -0,0 +1,115 @@
+package fiber
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// JSONCodec is a JSON library, e.g. sonic, go-json or jsoniter. The app uses the codec
+// of its config for all JSON encoding and decoding, unless JSONEncoder, JSONDecoder or
+// JSONStreamEncoder of the config are set, which take precedence.
+type JSONCodec interface {
+	// Marshal returns the JSON encoding of v.
+	Marshal(v any) ([]byte, error)
+
+	// Unmarshal parses the JSON-encoded data and stores the result in the value pointed to by v.
+	Unmarshal(data []byte, v any) error
+
+	// NewEncoder returns an encoder which writes the JSON encoding of values to w.
+	NewEncoder(w io.Writer) JSONValueEncoder
+
+	// NewDecoder returns a decoder which reads JSON values from r.
+	NewDecoder(r io.Reader) JSONValueDecoder
+
+	// ErrorDetail returns the details of an error of Unmarshal or a decoder, e.g. the
+	// field with a value of the wrong type. It returns false for other errors.
+	ErrorDetail(err error) (JSONErrorDetail, bool)
+}
+
+// JSONValueEncoder writes the JSON encoding of values to a stream.
+type JSONValueEncoder interface {
+	Encode(v any) error
+}
+
+// JSONValueDecoder reads JSON values from a stream.
+type JSONValueDecoder interface {
+	Decode(v any) error
+}
+
+// JSONErrorDetail describes why JSON couldn't be decoded.
+type JSONErrorDetail struct {
+	// Field is the path of the field whose value has the wrong type, e.g. "user.age".
+	// It's empty for syntax errors.
+	Field string
+	// Type is the Go type of the field, e.g. "int".
+	Type string
+	// Value is the type of the JSON value, e.g. "string".
+	Value string
+	// Offset is the offset in the input after which the error occurred.
+	Offset int64
+}
+
+// StdJSONCodec is the JSONCodec of encoding/json, which is the default JSONCodec of the app.
+type StdJSONCodec struct{}
+
+// Marshal returns the JSON encoding of v with json.Marshal.
+func (StdJSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v) //nolint:wrapcheck // unnecessary to wrap it
+}
+
+// Unmarshal parses the JSON-encoded data with json.Unmarshal.
+func (StdJSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v) //nolint:wrapcheck // unnecessary to wrap it
+}
+
+// NewEncoder returns a json.Encoder which writes to w.
+func (StdJSONCodec) NewEncoder(w io.Writer) JSONValueEncoder {
+	return json.NewEncoder(w)
+}
+
+// NewDecoder returns a json.Decoder which reads from r.
+func (StdJSONCodec) NewDecoder(r io.Reader) JSONValueDecoder {
+	return json.NewDecoder(r)
+}
+
+// ErrorDetail returns the details of a json.SyntaxError or a json.UnmarshalTypeError.
+func (StdJSONCodec) ErrorDetail(err error) (JSONErrorDetail, bool) {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		detail := JSONErrorDetail{Field: typeErr.Field, Value: typeErr.Value, Offset: typeErr.Offset}
+		if typeErr.Type != nil {
+			detail.Type = typeErr.Type.String()
+		}
+		return detail, true
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return JSONErrorDetail{Offset: syntaxErr.Offset}, true
+	}
+	return JSONErrorDetail{}, false
+}
+
+// describeJSONError describes decoding errors with the details of the codec, e.g.
+// `bind: age: string is not a valid int`. Other errors are returned unchanged.
+func describeJSONError(codec JSONCodec, err error) error {
+	detail, ok := codec.ErrorDetail(err)
+	if !ok || detail.Field == "" {
+		return err
+	}
+	return &jsonDecodeError{msg: detail.Field + ": " + detail.Value + " is not a valid " + detail.Type, err: err}
+}
+
+// jsonDecodeError is a decoding error of JSON which describes the invalid field
+type jsonDecodeError struct {
+	err error
+	msg string
+}
+
+func (e *jsonDecodeError) Error() string {
+	return "bind: " + e.msg
+}
+
+func (e *jsonDecodeError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,118 @@
+package fiber
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// upperJSONCodec is a JSONCodec which marks its output, to check that it is used
+type upperJSONCodec struct {
+	StdJSONCodec
+}
+
+func (c upperJSONCodec) Marshal(v any) ([]byte, error) {
+	raw, err := c.StdJSONCodec.Marshal(v)
+	return bytes.ToUpper(raw), err
+}
+
+func (upperJSONCodec) NewEncoder(w io.Writer) JSONValueEncoder {
+	return upperJSONEncoder{w: w}
+}
+
+type upperJSONEncoder struct {
+	w io.Writer
+}
+
+func (e upperJSONEncoder) Encode(v any) error {
+	var buf bytes.Buffer
+	if err := (StdJSONCodec{}).NewEncoder(&buf).Encode(v); err != nil {
+		return err
+	}
+	_, err := e.w.Write(bytes.ToUpper(buf.Bytes()))
+	return err
+}
+
+// go test -run Test_JSONCodec
+func Test_JSONCodec(t *testing.T) {
+	t.Parallel()
+	app := New(Config{JSONCodec: upperJSONCodec{}})
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	require.NoError(t, c.JSON(Map{"name": "john"}))
+	require.Equal(t, `{"NAME":"JOHN"}`, string(c.Response().Body()))
+
+	require.NoError(t, c.JSONStream(Map{"name": "doe"}))
+	require.Equal(t, "{\"NAME\":\"DOE\"}\n", string(c.Response().Body()))
+	app.ReleaseCtx(c)
+
+	c = app.AcquireCtx(&fasthttp.RequestCtx{})
+	c.Request().Header.SetContentType(MIMEApplicationJSON)
+	c.Request().SetBody([]byte(`{"name":"john"}`))
+	var out struct {
+		Name string `json:"name"`
+	}
+	require.NoError(t, c.Bind().Body(&out))
+	require.Equal(t, "john", out.Name)
+
+	// The encoders of the config take precedence
+	app = New(Config{JSONCodec: upperJSONCodec{}, JSONEncoder: StdJSONCodec{}.Marshal})
+	c = app.AcquireCtx(&fasthttp.RequestCtx{})
+	require.NoError(t, c.JSON(Map{"name": "john"}))
+	require.Equal(t, `{"name":"john"}`, string(c.Response().Body()))
+}
+
+// go test -run Test_StdJSONCodec
+func Test_StdJSONCodec(t *testing.T) {
+	t.Parallel()
+	codec := StdJSONCodec{}
+
+	var buf bytes.Buffer
+	require.NoError(t, codec.NewEncoder(&buf).Encode(Map{"name": "john"}))
+	require.Equal(t, "{\"name\":\"john\"}\n", buf.String())
+
+	var out struct {
+		User struct {
+			Age int `json:"age"`
+		} `json:"user"`
+	}
+	dec := codec.NewDecoder(strings.NewReader(`{"user":{"age":"20"}}`))
+	err := dec.Decode(&out)
+	require.Error(t, err)
+	detail, ok := codec.ErrorDetail(err)
+	require.True(t, ok)
+	require.Equal(t, JSONErrorDetail{Field: "user.age", Type: "int", Value: "string", Offset: 19}, detail)
+
+	err = codec.Unmarshal([]byte(`{"user":}`), &out)
+	detail, ok = codec.ErrorDetail(err)
+	require.True(t, ok)
+	require.Equal(t, JSONErrorDetail{Offset: 9}, detail)
+
+	_, ok = codec.ErrorDetail(errors.New("other"))
+	require.False(t, ok)
+}
+
+// go test -run Test_Bind_JSON_ErrorDetail
+func Test_Bind_JSON_ErrorDetail(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	c.Request().Header.SetContentType(MIMEApplicationJSON)
+	c.Request().SetBody([]byte(`{"age":"20"}`))
+
+	var out struct {
+		Age int `json:"age"`
+	}
+	err := c.Bind().WithoutAutoHandling().JSON(&out)
+	require.EqualError(t, err, "bind: age: string is not a valid int")
+	var typeErr *UnmarshalTypeError
+	require.ErrorAs(t, err, &typeErr)
+
+	err = c.Bind().WithAutoHandling().JSON(&out)
+	require.EqualError(t, err, "Bad request: bind: age: string is not a valid int")
+}
@@ -128,6 +128,19 @@ type ListenConfig struct {
 	//
 	// Default: false
 	EnablePrintRoutes bool `json:"enable_print_routes"`
+
+	// When set to true, TLS listeners negotiate HTTP/2 with ALPN, so handlers and middlewares
+	// serve HTTP/2 requests as well. Custom TLS listeners of Listener must add "h2" to
+	// tls.Config.NextProtos. Hijacking connections isn't supported for HTTP/2 requests.
+	//
+	// Default: false
+	EnableHTTP2 bool `json:"enable_http2"`
+
+	// When set to true, cleartext listeners accept HTTP/2 connections with prior knowledge (h2c),
+	// e.g. from gRPC clients or reverse proxies. Upgrading HTTP/1.1 connections to h2c isn't supported.
+	//
+	// Default: false
+	EnableH2C bool `json:"enable_h2c"`
 }
 
 // listenConfigDefault is a function to set default values of ListenConfig.
@@ -214,6 +227,10 @@ func (app *App) Listen(addr string, config ...ListenConfig) error {
 		}
 	}
 
+	if tlsConfig != nil && cfg.EnableHTTP2 {
+		enableHTTP2(tlsConfig)
+	}
+
 	if tlsConfig != nil && cfg.TLSConfigFunc != nil {
 		cfg.TLSConfigFunc(tlsConfig)
 	}
@@ -253,7 +270,7 @@ func (app *App) Listen(addr string, config ...ListenConfig) error {
 		}
 	}
 
-	return app.server.Serve(ln)
+	return app.server.Serve(app.newHTTP2Listener(ln, cfg))
 }
 
 // Listener serves HTTP requests from the given listener.
@@ -290,7 +307,7 @@ func (app *App) Listener(ln net.Listener, config ...ListenConfig) error {
 		log.Warn("Prefork isn't supported for custom listeners.")
 	}
 
-	return app.server.Serve(ln)
+	return app.server.Serve(app.newHTTP2Listener(ln, cfg))
 }
 
 // Create listener function.
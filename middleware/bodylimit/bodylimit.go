@@ -0,0 +1,66 @@
+// Package bodylimit provides a middleware which limits the size of request bodies
+// per route, while the BodyLimit of the app applies to all routes.
+package bodylimit
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/bytebufferpool"
+)
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		req := c.Request()
+
+		// Reject the request before the body is read if its length is known
+		if req.Header.ContentLength() > cfg.Limit {
+			return limitExceeded(c, &cfg)
+		}
+
+		if !req.IsBodyStream() {
+			// The whole body was already read, e.g. because it was sent in chunks
+			if len(req.Body()) > cfg.Limit {
+				return cfg.LimitExceeded(c)
+			}
+			return c.Next()
+		}
+
+		// A streamed body with a known length is passed through untouched, as it can't
+		// be longer than its Content-Length. Otherwise, read at most one byte more than
+		// the limit to find out whether the body exceeds it.
+		if req.Header.ContentLength() < 0 {
+			bb := bytebufferpool.Get()
+			defer bytebufferpool.Put(bb)
+
+			if _, err := bb.ReadFrom(io.LimitReader(req.BodyStream(), int64(cfg.Limit)+1)); err != nil {
+				return fmt.Errorf("bodylimit: failed to read body: %w", err)
+			}
+			if bb.Len() > cfg.Limit {
+				return limitExceeded(c, &cfg)
+			}
+			req.SetBody(bb.Bytes())
+		}
+
+		return c.Next()
+	}
+}
+
+// limitExceeded closes the connection, as the rest of the body is not read anymore
+func limitExceeded(c fiber.Ctx, cfg *Config) error {
+	if c.Request().IsBodyStream() {
+		c.Response().SetConnectionClose()
+	}
+	return cfg.LimitExceeded(c)
+}
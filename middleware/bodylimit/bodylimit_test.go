@@ -0,0 +1,169 @@
+package bodylimit
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// chunkedRequest returns a request whose body is sent in chunks, as its length is unknown
+func chunkedRequest(body string) *http.Request {
+	req := httptest.NewRequest(fiber.MethodPost, "/", io.MultiReader(strings.NewReader(body)))
+	req.ContentLength = -1
+	req.TransferEncoding = []string{"chunked"}
+	return req
+}
+
+// go test -run Test_BodyLimit
+func Test_BodyLimit(t *testing.T) {
+	t.Parallel()
+
+	for _, stream := range []bool{false, true} {
+		app := fiber.New(fiber.Config{
+			StreamRequestBody: stream,
+		})
+		app.Use(New(Config{Limit: 5}))
+		app.Post("/", func(c fiber.Ctx) error {
+			return c.Send(c.Body())
+		})
+
+		testCases := []struct {
+			req    *http.Request
+			body   string
+			status int
+		}{
+			{req: httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader("hello")), status: fiber.StatusOK, body: "hello"},
+			{req: httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader("hello world")), status: fiber.StatusRequestEntityTooLarge, body: "Request Entity Too Large"},
+			{req: chunkedRequest("hello"), status: fiber.StatusOK, body: "hello"},
+			{req: chunkedRequest("hello world"), status: fiber.StatusRequestEntityTooLarge, body: "Request Entity Too Large"},
+		}
+		for _, tc := range testCases {
+			resp, err := app.Test(tc.req)
+			require.NoError(t, err)
+			require.Equal(t, tc.status, resp.StatusCode)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, tc.body, string(body))
+		}
+	}
+}
+
+// go test -run Test_BodyLimit_Stream
+func Test_BodyLimit_Stream(t *testing.T) {
+	t.Parallel()
+	var called bool
+	app := fiber.New(fiber.Config{
+		StreamRequestBody: true,
+	})
+	app.Use(New(Config{Limit: 1024}))
+	app.Post("/", func(c fiber.Ctx) error {
+		called = true
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	// The body is rejected before it was read by the handler
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(strings.Repeat("a", 64*1024))))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusRequestEntityTooLarge, resp.StatusCode)
+	require.True(t, resp.Close)
+	require.False(t, called)
+
+	// A body without a length is read up to the limit only
+	resp, err = app.Test(chunkedRequest(strings.Repeat("a", 64*1024)))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusRequestEntityTooLarge, resp.StatusCode)
+	require.True(t, resp.Close)
+	require.False(t, called)
+}
+
+// go test -run Test_BodyLimit_LimitExceeded
+func Test_BodyLimit_LimitExceeded(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Limit: 2,
+		LimitExceeded: func(c fiber.Ctx) error {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+				"error": "body too large",
+				"limit": 2,
+			})
+		},
+	}))
+	app.Post("/", func(c fiber.Ctx) error {
+		return c.Send(c.Body())
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader("hello")))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusRequestEntityTooLarge, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"error":"body too large","limit":2}`, string(body))
+}
+
+// go test -run Test_BodyLimit_Next
+func Test_BodyLimit_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Limit: 2,
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	}))
+	app.Post("/", func(c fiber.Ctx) error {
+		return c.Send(c.Body())
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader("hello")))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(body))
+}
+
+// go test -run Test_BodyLimit_Default
+func Test_BodyLimit_Default(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New())
+	app.Post("/", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(strings.Repeat("a", ConfigDefault.Limit))))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(strings.Repeat("a", ConfigDefault.Limit+1))))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+// go test -v -run=^$ -bench=Benchmark_BodyLimit -benchmem -count=4
+func Benchmark_BodyLimit(b *testing.B) {
+	app := fiber.New()
+	app.Use(New())
+	app.Post("/", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	h := app.Handler()
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fiber.MethodPost)
+	ctx.Request.SetRequestURI("/")
+	ctx.Request.SetBodyString("hello")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		h(ctx)
+	}
+}
@@ -5,12 +5,12 @@ package cache
 import (
 	"slices"
 	"strconv"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/httpcache"
 	"github.com/gofiber/utils/v2"
 )
 
@@ -87,6 +87,10 @@ func New(config ...Config) fiber.Handler {
 		if cfg.Storage != nil {
 			manager.del(dkey + "_body")
 		}
+		// Release the claim of an atomic storage, so the next miss stores the entry again
+		if manager.atomic != nil {
+			manager.del(dkey + "_lock")
+		}
 	}
 
 	// Return new handler
@@ -226,6 +230,13 @@ func New(config ...Config) fiber.Handler {
 		}
 		e.exp = ts + uint64(expiration.Seconds())
 
+		// Another instance sharing the atomic storage already stores the entry
+		if !manager.claim(key, expiration) {
+			manager.release(e)
+			c.Set(cfg.CacheHeader, cacheMiss)
+			return nil
+		}
+
 		// Store entry in heap
 		if cfg.MaxBytes > 0 {
 			e.heapidx = heap.put(key, e.exp, bodySize)
@@ -253,5 +264,5 @@ func New(config ...Config) fiber.Handler {
 
 // Check if request has directive
 func hasRequestDirective(c fiber.Ctx, directive string) bool {
-	return strings.Contains(c.Get(fiber.HeaderCacheControl), directive)
+	return httpcache.HasDirective(c.Get(fiber.HeaderCacheControl), directive)
 }
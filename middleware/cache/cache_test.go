@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net/http"
 	"net/http/httptest"
 	"os"
 	"strconv"
@@ -17,6 +18,7 @@ import (
 	"github.com/gofiber/fiber/v3/internal/storage/memory"
 	"github.com/gofiber/fiber/v3/middleware/etag"
 	"github.com/gofiber/utils/v2"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
 )
@@ -837,6 +839,75 @@ func Test_CacheStorage_CustomHeaders(t *testing.T) {
 	require.NotEmpty(t, respCached.Header.Get(fiber.HeaderCacheControl))
 }
 
+// go test -run Test_Cache_AtomicStorage -race -v
+func Test_Cache_AtomicStorage(t *testing.T) {
+	t.Parallel()
+
+	// Two instances sharing the same storage miss the cache concurrently
+	storage := memory.New()
+	entered, proceed := make(chan struct{}), make(chan struct{})
+	apps := make([]*fiber.App, 2)
+	for i := range apps {
+		apps[i] = fiber.New()
+		apps[i].Use(New(Config{
+			Storage: storage,
+			CacheInvalidator: func(c fiber.Ctx) bool {
+				return fiber.Query[bool](c, "invalidate")
+			},
+		}))
+	}
+	apps[0].Get("/", func(c fiber.Ctx) error {
+		if fiber.Query[bool](c, "invalidate") {
+			return c.SendString("first again")
+		}
+		close(entered)
+		<-proceed
+		return c.SendString("first")
+	})
+	apps[1].Get("/", func(c fiber.Ctx) error {
+		return c.SendString("second")
+	})
+
+	done := make(chan *http.Response)
+	go func() {
+		resp, err := apps[0].Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		assert.NoError(t, err)
+		done <- resp
+	}()
+	<-entered
+
+	resp, err := apps[1].Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, cacheMiss, resp.Header.Get("X-Cache"))
+
+	// The later miss doesn't overwrite the entry stored by the other instance
+	close(proceed)
+	resp = <-done
+	require.Equal(t, cacheMiss, resp.Header.Get("X-Cache"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "first", string(body))
+
+	resp, err = apps[0].Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, cacheHit, resp.Header.Get("X-Cache"))
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "second", string(body))
+
+	// An invalidated entry is stored again
+	resp, err = apps[0].Test(httptest.NewRequest(fiber.MethodGet, "/?invalidate=true", nil))
+	require.NoError(t, err)
+	require.Equal(t, cacheMiss, resp.Header.Get("X-Cache"))
+
+	resp, err = apps[1].Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, cacheHit, resp.Header.Get("X-Cache"))
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "first again", string(body))
+}
+
 // Because time points are updated once every X milliseconds, entries in tests can often have
 // equal expiration times and thus be in an random order. This closure hands out increasing
 // time intervals to maintain strong ascending order of expiration
@@ -27,6 +27,8 @@ type manager struct {
 	pool    sync.Pool
 	memory  *memory.Storage
 	storage fiber.Storage
+	// atomic is set if the storage implements fiber.AtomicStorage
+	atomic fiber.AtomicStorage
 }
 
 func newManager(storage fiber.Storage) *manager {
@@ -41,6 +43,7 @@ func newManager(storage fiber.Storage) *manager {
 	if storage != nil {
 		// Use provided storage if provided
 		manager.storage = storage
+		manager.atomic, _ = storage.(fiber.AtomicStorage) //nolint:errcheck // The storage is not atomic if the assertion fails
 	} else {
 		// Fallback to memory storage
 		manager.memory = memory.New()
@@ -122,6 +125,17 @@ func (m *manager) setRaw(key string, raw []byte, exp time.Duration) {
 	}
 }
 
+// claim reports whether the entry of key may be stored. With an atomic storage,
+// only the first of the instances sharing the storage which claims the key within
+// exp stores the entry, so the body and the entry of concurrent misses are never mixed.
+func (m *manager) claim(key string, exp time.Duration) bool {
+	if m.atomic == nil {
+		return true
+	}
+	claimed, err := m.atomic.SetNX(key+"_lock", []byte{1}, exp)
+	return err == nil && claimed
+}
+
 // delete data from storage or memory
 func (m *manager) del(key string) {
 	if m.storage != nil {
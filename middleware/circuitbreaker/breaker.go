@@ -0,0 +1,149 @@
+package circuitbreaker
+
+import (
+	"sync"
+	"time"
+)
+
+// State is the state of a circuit
+type State int
+
+const (
+	// StateClosed lets all requests through and tracks their failures
+	StateClosed State = iota
+	// StateOpen rejects all requests until the OpenTimeout passed
+	StateOpen
+	// StateHalfOpen lets a limited number of probe requests through
+	StateHalfOpen
+)
+
+// String returns the name of the state
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// numBuckets is the number of buckets the rolling window is divided into
+const numBuckets = 10
+
+type bucket struct {
+	index    int64 // index of the time slot the counts belong to
+	total    int
+	failures int
+}
+
+// breaker is the circuit of a single key
+type breaker struct {
+	openUntil time.Time
+	cfg       *Config
+	buckets   [numBuckets]bucket
+	probes    int // probe requests which were let through in the half-open state
+	successes int // successful probe requests in the half-open state
+	state     State
+	mu        sync.Mutex
+}
+
+// transition is a state change which is reported after the lock was released
+type transition struct {
+	from, to State
+}
+
+// allow reports whether a request may pass and whether it is a probe request.
+// If it may not pass, it returns the time until the next request may pass.
+func (b *breaker) allow(now time.Time) (allowed, probe bool, retryAfter time.Duration, t *transition) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen {
+		if now.Before(b.openUntil) {
+			return false, false, b.openUntil.Sub(now), nil
+		}
+		t = b.setState(StateHalfOpen)
+	}
+
+	if b.state == StateHalfOpen {
+		if b.probes >= b.cfg.HalfOpenRequests {
+			// Wait for the result of the probe requests
+			return false, false, time.Second, t
+		}
+		b.probes++
+		return true, true, 0, t
+	}
+
+	return true, false, 0, t
+}
+
+// record tracks the result of a request which was let through
+func (b *breaker) record(now time.Time, probe, failed bool) *transition {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		if b.state != StateHalfOpen {
+			return nil
+		}
+		if failed {
+			return b.open(now)
+		}
+		b.successes++
+		if b.successes >= b.cfg.HalfOpenRequests {
+			b.buckets = [numBuckets]bucket{}
+			return b.setState(StateClosed)
+		}
+		return nil
+	}
+
+	// The request was let through before the circuit opened
+	if b.state != StateClosed {
+		return nil
+	}
+
+	size := int64(b.cfg.Window) / numBuckets
+	if size <= 0 {
+		size = 1
+	}
+	index := now.UnixNano() / size
+	current := &b.buckets[index%numBuckets]
+	if current.index != index {
+		*current = bucket{index: index}
+	}
+	current.total++
+	if failed {
+		current.failures++
+	}
+
+	var total, failures int
+	for i := range b.buckets {
+		if b.buckets[i].index > index-numBuckets {
+			total += b.buckets[i].total
+			failures += b.buckets[i].failures
+		}
+	}
+	if total >= b.cfg.MinRequests && float64(failures)/float64(total) >= b.cfg.FailureThreshold {
+		return b.open(now)
+	}
+	return nil
+}
+
+// open opens the circuit, the caller has to hold the lock
+func (b *breaker) open(now time.Time) *transition {
+	b.openUntil = now.Add(b.cfg.OpenTimeout)
+	return b.setState(StateOpen)
+}
+
+// setState changes the state, the caller has to hold the lock
+func (b *breaker) setState(state State) *transition {
+	t := &transition{from: b.state, to: state}
+	b.state = state
+	b.probes = 0
+	b.successes = 0
+	return t
+}
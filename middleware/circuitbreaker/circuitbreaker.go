@@ -0,0 +1,80 @@
+// Package circuitbreaker provides a middleware which rejects requests with
+// 503 Service Unavailable after too many of them failed, so an unhealthy
+// dependency isn't overloaded by further requests.
+package circuitbreaker
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+)
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	var mu sync.Mutex
+	breakers := make(map[string]*breaker)
+
+	getBreaker := func(key string) *breaker {
+		mu.Lock()
+		defer mu.Unlock()
+		b, ok := breakers[key]
+		if !ok {
+			b = &breaker{cfg: &cfg}
+			breakers[key] = b
+		}
+		return b
+	}
+
+	notify := func(key string, t *transition) {
+		if t != nil && cfg.OnStateChange != nil && t.from != t.to {
+			cfg.OnStateChange(key, t.from, t.to)
+		}
+	}
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		key := utils.CopyString(cfg.KeyGenerator(c))
+		b := getBreaker(key)
+
+		start := time.Now()
+		allowed, probe, retryAfter, t := b.allow(start)
+		notify(key, t)
+		if !allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(int64(math.Ceil(retryAfter.Seconds())), 10))
+			return cfg.OpenHandler(c)
+		}
+
+		// A panic counts as failure, so a probe request can't keep the circuit half-open
+		completed := false
+		defer func() {
+			if !completed {
+				notify(key, b.record(time.Now(), probe, true))
+			}
+		}()
+
+		// Continue stack
+		err := c.Next()
+		completed = true
+
+		failed := cfg.IsFailure(c, err)
+		end := time.Now()
+		if cfg.SlowThreshold > 0 && end.Sub(start) > cfg.SlowThreshold {
+			failed = true
+		}
+		notify(key, b.record(end, probe, failed))
+
+		return err
+	}
+}
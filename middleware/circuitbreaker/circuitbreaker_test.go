@@ -0,0 +1,248 @@
+package circuitbreaker
+
+import (
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type stateChange struct {
+	key      string
+	from, to State
+}
+
+type stateRecorder struct {
+	changes []stateChange
+	mu      sync.Mutex
+}
+
+func (r *stateRecorder) record(key string, from, to State) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes = append(r.changes, stateChange{key: key, from: from, to: to})
+}
+
+func (r *stateRecorder) get() []stateChange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]stateChange(nil), r.changes...)
+}
+
+func doRequest(t *testing.T, app *fiber.App, path string) int {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+	require.NoError(t, err)
+	return resp.StatusCode
+}
+
+// go test -run Test_CircuitBreaker
+func Test_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+	recorder := &stateRecorder{}
+	var calls, failing atomic.Int32
+	failing.Store(1)
+
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		calls.Add(1)
+		if failing.Load() == 1 {
+			return fiber.ErrInternalServerError
+		}
+		return c.SendStatus(fiber.StatusOK)
+	}, New(Config{
+		MinRequests:   4,
+		OpenTimeout:   100 * time.Millisecond,
+		OnStateChange: recorder.record,
+	}))
+
+	for i := 0; i < 4; i++ {
+		require.Equal(t, fiber.StatusInternalServerError, doRequest(t, app, "/"))
+	}
+
+	// The circuit is open now
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, "1", resp.Header.Get(fiber.HeaderRetryAfter))
+	require.Equal(t, int32(4), calls.Load())
+	require.Equal(t, []stateChange{{key: "GET /", from: StateClosed, to: StateOpen}}, recorder.get())
+
+	// A failed probe opens the circuit again
+	time.Sleep(150 * time.Millisecond)
+	require.Equal(t, fiber.StatusInternalServerError, doRequest(t, app, "/"))
+	require.Equal(t, fiber.StatusServiceUnavailable, doRequest(t, app, "/"))
+	require.Equal(t, int32(5), calls.Load())
+
+	// A successful probe closes the circuit
+	failing.Store(0)
+	time.Sleep(150 * time.Millisecond)
+	require.Equal(t, fiber.StatusOK, doRequest(t, app, "/"))
+	require.Equal(t, fiber.StatusOK, doRequest(t, app, "/"))
+	require.Equal(t, int32(7), calls.Load())
+
+	require.Equal(t, []stateChange{
+		{key: "GET /", from: StateClosed, to: StateOpen},
+		{key: "GET /", from: StateOpen, to: StateHalfOpen},
+		{key: "GET /", from: StateHalfOpen, to: StateOpen},
+		{key: "GET /", from: StateOpen, to: StateHalfOpen},
+		{key: "GET /", from: StateHalfOpen, to: StateClosed},
+	}, recorder.get())
+}
+
+// go test -run Test_CircuitBreaker_FailureThreshold
+func Test_CircuitBreaker_FailureThreshold(t *testing.T) {
+	t.Parallel()
+	var n atomic.Int32
+
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		// Every second request fails
+		if n.Add(1)%2 == 0 {
+			return c.SendStatus(fiber.StatusBadGateway)
+		}
+		return c.SendStatus(fiber.StatusOK)
+	}, New(Config{
+		MinRequests:      4,
+		FailureThreshold: 0.75,
+	}))
+
+	for i := 0; i < 20; i++ {
+		require.NotEqual(t, fiber.StatusServiceUnavailable, doRequest(t, app, "/"))
+	}
+}
+
+// go test -run Test_CircuitBreaker_ClientErrors
+func Test_CircuitBreaker_ClientErrors(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Get("/", func(_ fiber.Ctx) error {
+		return fiber.ErrNotFound
+	}, New(Config{
+		MinRequests: 2,
+	}))
+
+	for i := 0; i < 5; i++ {
+		require.Equal(t, fiber.StatusNotFound, doRequest(t, app, "/"))
+	}
+}
+
+// go test -run Test_CircuitBreaker_SlowThreshold
+func Test_CircuitBreaker_SlowThreshold(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		time.Sleep(20 * time.Millisecond)
+		return c.SendStatus(fiber.StatusOK)
+	}, New(Config{
+		MinRequests:   2,
+		SlowThreshold: 10 * time.Millisecond,
+	}))
+
+	require.Equal(t, fiber.StatusOK, doRequest(t, app, "/"))
+	require.Equal(t, fiber.StatusOK, doRequest(t, app, "/"))
+	require.Equal(t, fiber.StatusServiceUnavailable, doRequest(t, app, "/"))
+}
+
+// go test -run Test_CircuitBreaker_PerRoute
+func Test_CircuitBreaker_PerRoute(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	breaker := New(Config{
+		MinRequests: 2,
+		OpenHandler: func(c fiber.Ctx) error {
+			return c.Status(fiber.StatusServiceUnavailable).SendString("unavailable")
+		},
+	})
+	app.Get("/broken", func(_ fiber.Ctx) error {
+		return fiber.ErrInternalServerError
+	}, breaker)
+	app.Get("/healthy", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	}, breaker)
+
+	require.Equal(t, fiber.StatusInternalServerError, doRequest(t, app, "/broken"))
+	require.Equal(t, fiber.StatusInternalServerError, doRequest(t, app, "/broken"))
+	require.Equal(t, fiber.StatusServiceUnavailable, doRequest(t, app, "/broken"))
+	require.Equal(t, fiber.StatusOK, doRequest(t, app, "/healthy"))
+}
+
+// go test -run Test_CircuitBreaker_Panic
+func Test_CircuitBreaker_Panic(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fiber.ErrInternalServerError
+			}
+		}()
+		return c.Next()
+	})
+	app.Get("/", func(_ fiber.Ctx) error {
+		panic("boom")
+	}, New(Config{
+		MinRequests: 1,
+		OpenTimeout: 50 * time.Millisecond,
+	}))
+
+	require.Equal(t, fiber.StatusInternalServerError, doRequest(t, app, "/"))
+	require.Equal(t, fiber.StatusServiceUnavailable, doRequest(t, app, "/"))
+
+	// The panicking probe opens the circuit again instead of keeping it half-open
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, fiber.StatusInternalServerError, doRequest(t, app, "/"))
+	require.Equal(t, fiber.StatusServiceUnavailable, doRequest(t, app, "/"))
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, fiber.StatusInternalServerError, doRequest(t, app, "/"))
+}
+
+// go test -run Test_CircuitBreaker_Next
+func Test_CircuitBreaker_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		MinRequests: 1,
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	}))
+	app.Get("/", func(_ fiber.Ctx) error {
+		return fiber.ErrInternalServerError
+	})
+
+	require.Equal(t, fiber.StatusInternalServerError, doRequest(t, app, "/"))
+	require.Equal(t, fiber.StatusInternalServerError, doRequest(t, app, "/"))
+}
+
+// go test -run Test_State_String
+func Test_State_String(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "closed", StateClosed.String())
+	require.Equal(t, "open", StateOpen.String())
+	require.Equal(t, "half-open", StateHalfOpen.String())
+	require.Equal(t, "unknown", State(42).String())
+}
+
+// go test -v -run=^$ -bench=Benchmark_CircuitBreaker -benchmem -count=4
+func Benchmark_CircuitBreaker(b *testing.B) {
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	}, New())
+
+	h := app.Handler()
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI("/")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		h(ctx)
+	}
+}
@@ -0,0 +1,134 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// KeyGenerator returns the key of the circuit which tracks the request.
+	//
+	// Optional. Default: the method and path of the route the middleware is registered for
+	KeyGenerator func(c fiber.Ctx) string
+
+	// IsFailure reports whether a request failed.
+	//
+	// Optional. Default: a function which reports errors and 5xx status codes as failures,
+	// a *fiber.Error only counts as failure if its code is 5xx
+	IsFailure func(c fiber.Ctx, err error) bool
+
+	// OpenHandler is called instead of the next handler while the circuit is open.
+	// The Retry-After header is already set when it is called.
+	//
+	// Optional. Default: func(c fiber.Ctx) error {
+	//   return c.SendStatus(fiber.StatusServiceUnavailable)
+	// }
+	OpenHandler fiber.Handler
+
+	// OnStateChange is called when a circuit changes its state, e.g. to log or alert.
+	//
+	// Optional. Default: nil
+	OnStateChange func(key string, from, to State)
+
+	// FailureThreshold is the ratio of failed requests in the Window which opens the circuit.
+	//
+	// Optional. Default: 0.5
+	FailureThreshold float64
+
+	// MinRequests is the minimum number of requests in the Window before the circuit can open.
+	//
+	// Optional. Default: 10
+	MinRequests int
+
+	// Window is the duration of the rolling window the failure ratio is calculated for.
+	//
+	// Optional. Default: 10 * time.Second
+	Window time.Duration
+
+	// SlowThreshold is the duration after which a request counts as failed even if it succeeded.
+	//
+	// Optional. Default: 0 (the latency is not tracked)
+	SlowThreshold time.Duration
+
+	// OpenTimeout is how long the circuit stays open before it lets probe requests through.
+	//
+	// Optional. Default: 30 * time.Second
+	OpenTimeout time.Duration
+
+	// HalfOpenRequests is the number of probe requests which must succeed to close the
+	// circuit again, a single failed probe opens it again.
+	//
+	// Optional. Default: 1
+	HalfOpenRequests int
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next: nil,
+	KeyGenerator: func(c fiber.Ctx) string {
+		return c.Route().Method + " " + c.Route().Path
+	},
+	IsFailure: func(c fiber.Ctx, err error) bool {
+		var e *fiber.Error
+		if errors.As(err, &e) {
+			return e.Code >= fiber.StatusInternalServerError
+		}
+		return err != nil || c.Response().StatusCode() >= fiber.StatusInternalServerError
+	},
+	OpenHandler: func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusServiceUnavailable)
+	},
+	OnStateChange:    nil,
+	FailureThreshold: 0.5,
+	MinRequests:      10,
+	Window:           10 * time.Second,
+	SlowThreshold:    0,
+	OpenTimeout:      30 * time.Second,
+	HalfOpenRequests: 1,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.KeyGenerator == nil {
+		cfg.KeyGenerator = ConfigDefault.KeyGenerator
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = ConfigDefault.IsFailure
+	}
+	if cfg.OpenHandler == nil {
+		cfg.OpenHandler = ConfigDefault.OpenHandler
+	}
+	if cfg.FailureThreshold <= 0 || cfg.FailureThreshold > 1 {
+		cfg.FailureThreshold = ConfigDefault.FailureThreshold
+	}
+	if cfg.MinRequests <= 0 {
+		cfg.MinRequests = ConfigDefault.MinRequests
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = ConfigDefault.Window
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = ConfigDefault.OpenTimeout
+	}
+	if cfg.HalfOpenRequests <= 0 {
+		cfg.HalfOpenRequests = ConfigDefault.HalfOpenRequests
+	}
+	return cfg
+}
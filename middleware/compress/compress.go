@@ -1,47 +1,37 @@
 package compress
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
 	"github.com/valyala/fasthttp"
 )
 
+// Supported encodings, in the order of preference for equal qualities
+const (
+	encodingZstd    = "zstd"
+	encodingBrotli  = "br"
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+var encodings = []string{encodingZstd, encodingBrotli, encodingGzip, encodingDeflate}
+
 // New creates a new middleware handler
 func New(config ...Config) fiber.Handler {
 	// Set default config
 	cfg := configDefault(config...)
 
-	// Setup request handlers
-	var (
-		fctx       = func(_ *fasthttp.RequestCtx) {}
-		compressor fasthttp.RequestHandler
-	)
-
-	// Setup compression algorithm
-	switch cfg.Level {
-	case LevelDefault:
-		// LevelDefault
-		compressor = fasthttp.CompressHandlerBrotliLevel(fctx,
-			fasthttp.CompressBrotliDefaultCompression,
-			fasthttp.CompressDefaultCompression,
-		)
-	case LevelBestSpeed:
-		// LevelBestSpeed
-		compressor = fasthttp.CompressHandlerBrotliLevel(fctx,
-			fasthttp.CompressBrotliBestSpeed,
-			fasthttp.CompressBestSpeed,
-		)
-	case LevelBestCompression:
-		// LevelBestCompression
-		compressor = fasthttp.CompressHandlerBrotliLevel(fctx,
-			fasthttp.CompressBrotliBestCompression,
-			fasthttp.CompressBestCompression,
-		)
-	default:
-		// LevelDisabled
-		return func(c fiber.Ctx) error {
-			return c.Next()
-		}
+	// Setup compression algorithms of the levels, which routes can override
+	compressors := [...]fasthttp.RequestHandler{
+		LevelDefault:         newCompressor(LevelDefault),
+		LevelBestSpeed:       newCompressor(LevelBestSpeed),
+		LevelBestCompression: newCompressor(LevelBestCompression),
 	}
+	include := lowerContentTypes(cfg.IncludeContentTypes)
+	exclude := lowerContentTypes(cfg.ExcludeContentTypes)
 
 	// Return new handler
 	return func(c fiber.Ctx) error {
@@ -55,10 +45,153 @@ func New(config ...Config) fiber.Handler {
 			return err
 		}
 
+		level := cfg.Level
+		if routeLevel, ok := c.Route().Meta[MetaKey].(Level); ok && routeLevel >= LevelDisabled && routeLevel <= LevelBestCompression {
+			level = routeLevel
+		}
+		if level == LevelDisabled {
+			return nil
+		}
+
+		// Skip small bodies and excluded content types
+		resp := c.Response()
+		if !resp.IsBodyStream() && len(resp.Body()) < cfg.MinLength {
+			return nil
+		}
+		if !compressibleContentType(utils.UnsafeString(resp.Header.ContentType()), include, exclude) {
+			return nil
+		}
+
 		// Compress response
-		compressor(c.RequestCtx())
+		compressors[level](c.RequestCtx())
 
 		// Return from handler
 		return nil
 	}
 }
+
+// newCompressor returns a handler which compresses the response with the levels of the Level
+// and the encoding which is negotiated with the Accept-Encoding header of the request
+func newCompressor(level Level) fasthttp.RequestHandler {
+	var (
+		fctx                               = func(_ *fasthttp.RequestCtx) {}
+		brotliLevel, flateLevel, zstdLevel int
+	)
+	switch level {
+	case LevelBestSpeed:
+		brotliLevel, flateLevel, zstdLevel = fasthttp.CompressBrotliBestSpeed, fasthttp.CompressBestSpeed, fasthttp.CompressZstdBestSpeed
+	case LevelBestCompression:
+		brotliLevel, flateLevel, zstdLevel = fasthttp.CompressBrotliBestCompression, fasthttp.CompressBestCompression, fasthttp.CompressZstdBestCompression
+	default:
+		brotliLevel, flateLevel, zstdLevel = fasthttp.CompressBrotliDefaultCompression, fasthttp.CompressDefaultCompression, fasthttp.CompressZstdDefault
+	}
+
+	// fasthttp uses the pooled encoders of the levels, and the level of gzip and deflate for zstd
+	flateCompressor := fasthttp.CompressHandlerBrotliLevel(fctx, brotliLevel, flateLevel)
+	zstdCompressor := fasthttp.CompressHandlerBrotliLevel(fctx, brotliLevel, zstdLevel)
+
+	return func(ctx *fasthttp.RequestCtx) {
+		accept := ctx.Request.Header.Peek(fiber.HeaderAcceptEncoding)
+		encoding := negotiateEncoding(utils.UnsafeString(accept))
+		if encoding == "" {
+			return
+		}
+
+		// fasthttp compresses with the first accepted encoding in its own order,
+		// so only the negotiated encoding is accepted while compressing
+		original := utils.CopyBytes(accept)
+		ctx.Request.Header.Set(fiber.HeaderAcceptEncoding, encoding)
+		if encoding == encodingZstd {
+			zstdCompressor(ctx)
+		} else {
+			flateCompressor(ctx)
+		}
+		ctx.Request.Header.SetBytesV(fiber.HeaderAcceptEncoding, original)
+	}
+}
+
+// negotiateEncoding returns the supported encoding with the highest quality in the
+// Accept-Encoding header, or an empty string if none is acceptable. The wildcard "*"
+// applies to all encodings which aren't listed.
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+	var (
+		qualities [4]float64
+		listed    [4]bool
+		wildcard  = -1.0
+	)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = utils.Trim(name, ' ')
+		quality := 1.0
+		if q, ok := strings.CutPrefix(utils.Trim(params, ' '), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if name == "*" {
+			wildcard = quality
+			continue
+		}
+		for i, encoding := range encodings {
+			if utils.EqualFold(name, encoding) {
+				qualities[i], listed[i] = quality, true
+			}
+		}
+	}
+
+	best, bestQuality := "", 0.0
+	for i, encoding := range encodings {
+		quality := qualities[i]
+		if !listed[i] {
+			quality = wildcard
+		}
+		if quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+// lowerContentTypes returns the content types in lowercase
+func lowerContentTypes(contentTypes []string) []string {
+	lower := make([]string, len(contentTypes))
+	for i, contentType := range contentTypes {
+		lower[i] = utils.ToLower(utils.Trim(contentType, ' '))
+	}
+	return lower
+}
+
+// compressibleContentType reports whether the content type matches none of the excluded
+// content types and, if there are any, one of the included content types
+func compressibleContentType(contentType string, include, exclude []string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = utils.ToLower(utils.Trim(mediaType, ' '))
+	for _, pattern := range exclude {
+		if matchContentType(pattern, mediaType) {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, pattern := range include {
+		if matchContentType(pattern, mediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchContentType reports whether the media type matches the pattern, which is a media
+// type or a type with a wildcard subtype, e.g. "text/*"
+func matchContentType(pattern, mediaType string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(mediaType, prefix)
+	}
+	return pattern == mediaType
+}
@@ -1,10 +1,12 @@
 package compress
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
 	"time"
@@ -163,6 +165,149 @@ func Test_Compress_Zstd(t *testing.T) {
 	require.Less(t, len(body), len(filedata))
 }
 
+// go test -run Test_Compress_Negotiation
+func Test_Compress_Negotiation(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{Level: LevelBestCompression}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.Send(filedata)
+	})
+
+	tests := []struct {
+		acceptEncoding string
+		encoding       string
+	}{
+		{acceptEncoding: "gzip, deflate, br, zstd", encoding: "zstd"},
+		{acceptEncoding: "gzip, deflate, br", encoding: "br"},
+		{acceptEncoding: "zstd;q=0.5, gzip", encoding: "gzip"},
+		{acceptEncoding: "br;q=0.8, GZIP;q=0.9, zstd;q=0.1", encoding: "gzip"},
+		{acceptEncoding: "*", encoding: "zstd"},
+		{acceptEncoding: "zstd;q=0, *;q=0.5", encoding: "br"},
+		{acceptEncoding: "zstd;q=0, br;q=0", encoding: ""},
+		{acceptEncoding: "identity", encoding: ""},
+		{acceptEncoding: "", encoding: ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+
+		resp, err := app.Test(req, testConfig)
+		require.NoError(t, err)
+		require.Equal(t, tt.encoding, resp.Header.Get(fiber.HeaderContentEncoding), tt.acceptEncoding)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		if tt.encoding == "" {
+			require.Equal(t, filedata, body)
+			continue
+		}
+		require.Less(t, len(body), len(filedata))
+		if tt.encoding == "zstd" {
+			decoded, err := fasthttp.AppendUnzstdBytes(nil, body)
+			require.NoError(t, err)
+			require.Equal(t, filedata, decoded)
+		}
+	}
+}
+
+// go test -run Test_Compress_MinLength
+func Test_Compress_MinLength(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{MinLength: 1024}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.Send(filedata[:fiber.Query[int](c, "size")])
+	})
+	app.Get("/stream", func(c fiber.Ctx) error {
+		return c.SendStream(bytes.NewReader(filedata[:64]))
+	})
+
+	for _, tt := range []struct {
+		path     string
+		encoding string
+	}{
+		{path: "/?size=512", encoding: ""},
+		{path: "/?size=1024", encoding: "gzip"},
+		{path: "/stream", encoding: "gzip"},
+	} {
+		req := httptest.NewRequest(fiber.MethodGet, tt.path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		resp, err := app.Test(req, testConfig)
+		require.NoError(t, err)
+		require.Equal(t, tt.encoding, resp.Header.Get(fiber.HeaderContentEncoding), tt.path)
+	}
+}
+
+// go test -run Test_Compress_ContentTypes
+func Test_Compress_ContentTypes(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		IncludeContentTypes: []string{"text/*", "application/json"},
+		ExcludeContentTypes: []string{"text/csv"},
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, c.Query("type"))
+		return c.Send(filedata)
+	})
+
+	for contentType, encoding := range map[string]string{
+		"text/plain; charset=utf-8": "gzip",
+		"application/json":          "gzip",
+		"text/csv":                  "",
+		"application/xml":           "",
+	} {
+		req := httptest.NewRequest(fiber.MethodGet, "/?type="+url.QueryEscape(contentType), nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		resp, err := app.Test(req, testConfig)
+		require.NoError(t, err)
+		require.Equal(t, encoding, resp.Header.Get(fiber.HeaderContentEncoding), contentType)
+	}
+
+	// Archives are excluded by default
+	app = fiber.New()
+	app.Use(New())
+	app.Get("/", func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, "application/zip")
+		return c.Send(filedata)
+	})
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := app.Test(req, testConfig)
+	require.NoError(t, err)
+	require.Empty(t, resp.Header.Get(fiber.HeaderContentEncoding))
+}
+
+// go test -run Test_Compress_RouteLevel
+func Test_Compress_RouteLevel(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{Level: LevelDisabled}))
+	handler := func(c fiber.Ctx) error {
+		return c.Send(filedata)
+	}
+	app.Get("/", handler)
+	app.Get("/export", handler).Meta(MetaKey, LevelBestCompression)
+
+	api := app.Group("/api").Meta(MetaKey, LevelBestSpeed)
+	api.Get("/users", handler)
+	api.Get("/health", handler).Meta(MetaKey, LevelDisabled)
+
+	for path, encoding := range map[string]string{
+		"/":           "",
+		"/export":     "br",
+		"/api/users":  "br",
+		"/api/health": "",
+	} {
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", "br")
+		resp, err := app.Test(req, testConfig)
+		require.NoError(t, err)
+		require.Equal(t, encoding, resp.Header.Get(fiber.HeaderContentEncoding), path)
+	}
+}
+
 func Test_Compress_Disabled(t *testing.T) {
 	t.Parallel()
 	app := fiber.New()
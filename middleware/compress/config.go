@@ -19,8 +19,37 @@ type Config struct {
 	// LevelBestSpeed:        1
 	// LevelBestCompression:  2
 	Level Level
+
+	// MinLength is the minimum length of a response body in bytes which is compressed.
+	// Streamed bodies, whose length is unknown, are always compressed. Bodies smaller
+	// than 200 bytes are never compressed.
+	//
+	// Optional. Default: 200
+	MinLength int
+
+	// IncludeContentTypes are the content types of the responses which are compressed,
+	// e.g. "application/json" or "text/*". If it's empty, all content types which
+	// fasthttp considers compressible are compressed, e.g. text/*, application/* and
+	// image/svg+xml, but no other images.
+	//
+	// Optional. Default: nil
+	IncludeContentTypes []string
+
+	// ExcludeContentTypes are the content types of the responses which aren't compressed,
+	// because they are already compressed, e.g. "application/zip" or "video/*".
+	// It takes precedence over IncludeContentTypes.
+	//
+	// Optional. Default: archive types like application/zip and application/gzip
+	ExcludeContentTypes []string
 }
 
+// MetaKey is the key of the route metadata with the Level of the route, which overrides
+// the Level of the config.
+//
+//	app.Get("/export", handler).Meta(compress.MetaKey, compress.LevelBestCompression)
+//	app.Get("/health", handler).Meta(compress.MetaKey, compress.LevelDisabled)
+const MetaKey = "compress"
+
 // Level is numeric representation of compression level
 type Level int
 
@@ -34,8 +63,19 @@ const (
 
 // ConfigDefault is the default config
 var ConfigDefault = Config{
-	Next:  nil,
-	Level: LevelDefault,
+	Next:      nil,
+	Level:     LevelDefault,
+	MinLength: 200,
+	ExcludeContentTypes: []string{
+		"application/zip",
+		"application/gzip",
+		"application/x-gzip",
+		"application/zstd",
+		"application/x-bzip2",
+		"application/x-xz",
+		"application/x-7z-compressed",
+		"application/x-rar-compressed",
+	},
 }
 
 // Helper function to set default values
@@ -52,5 +92,11 @@ func configDefault(config ...Config) Config {
 	if cfg.Level < LevelDisabled || cfg.Level > LevelBestCompression {
 		cfg.Level = ConfigDefault.Level
 	}
+	if cfg.MinLength <= 0 {
+		cfg.MinLength = ConfigDefault.MinLength
+	}
+	if cfg.ExcludeContentTypes == nil {
+		cfg.ExcludeContentTypes = ConfigDefault.ExcludeContentTypes
+	}
 	return cfg
 }
@@ -15,15 +15,34 @@ type Config struct {
 	// response header to the 'origin' request header when returned true. This allows for
 	// dynamic evaluation of allowed origins. Note if AllowCredentials is true, wildcard origins
 	// will be not have the 'Access-Control-Allow-Credentials' header set to 'true'.
+	// Well-formed origins are normalized to a lowercase scheme and host like
+	// "https://example.com" before they are passed to the function.
 	//
 	// Optional. Default: nil
 	AllowOriginsFunc func(origin string) bool
 
+	// OriginRejected is called with the origin of every CORS request from a
+	// disallowed origin, e.g. to log rejected origins. Note that browsers also send
+	// the Origin header with some same-origin requests, like POST requests.
+	//
+	// Optional. Default: nil
+	OriginRejected func(c fiber.Ctx, origin string)
+
+	// Overrides defines policies for path prefixes like "/admin", which replace this config
+	// for requests to the prefix and the paths below it. The longest matching prefix wins,
+	// so one middleware registration can serve routes and groups with different policies.
+	// Overrides of an override are ignored.
+	//
+	// Optional. Default: nil
+	Overrides map[string]Config
+
 	// AllowOrigin defines a list of origins that may access the resource.
 	//
 	// This supports subdomains wildcarding by prefixing the domain with a `*.`
 	// e.g. "http://.domain.com". This will allow all level of subdomains of domain.com to access the resource.
 	//
+	// A trailing ":*" allows any port, e.g. "http://localhost:*" or "https://*.domain.com:*".
+	//
 	// If the special wildcard `"*"` is present in the list, all origins will be allowed.
 	//
 	// Optional. Default value []string{}
@@ -65,6 +84,12 @@ type Config struct {
 	// Optional. Default value false.
 	AllowCredentials bool
 
+	// RejectDisallowedPreflight responds to preflight requests from disallowed origins
+	// with 403 Forbidden instead of 204 No Content without CORS headers.
+	//
+	// Optional. Default value false.
+	RejectDisallowedPreflight bool
+
 	// AllowPrivateNetwork indicates whether the Access-Control-Allow-Private-Network
 	// response header should be set to true, allowing requests from private networks.
 	//
@@ -85,9 +110,10 @@ var ConfigDefault = Config{
 		fiber.MethodDelete,
 		fiber.MethodPatch,
 	},
-	AllowHeaders:        []string{},
-	AllowCredentials:    false,
-	ExposeHeaders:       []string{},
-	MaxAge:              0,
-	AllowPrivateNetwork: false,
+	AllowHeaders:              []string{},
+	AllowCredentials:          false,
+	ExposeHeaders:             []string{},
+	MaxAge:                    0,
+	AllowPrivateNetwork:       false,
+	RejectDisallowedPreflight: false,
 }
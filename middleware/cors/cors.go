@@ -1,6 +1,7 @@
 package cors
 
 import (
+	"sort"
 	"strconv"
 	"strings"
 
@@ -17,11 +18,62 @@ func New(config ...Config) fiber.Handler {
 	// Override config if provided
 	if len(config) > 0 {
 		cfg = config[0]
+	}
+
+	root := newPolicy(cfg)
+
+	// Compile the overrides, the longest prefix is matched first
+	overrides := make([]override, 0, len(cfg.Overrides))
+	for prefix, overrideCfg := range cfg.Overrides {
+		if !strings.HasPrefix(prefix, "/") {
+			panic("[CORS] Override prefix must start with '/': " + prefix)
+		}
+		overrides = append(overrides, override{prefix: strings.TrimSuffix(prefix, "/"), policy: newPolicy(overrideCfg)})
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		return len(overrides[i].prefix) > len(overrides[j].prefix)
+	})
 
-		// Set default values
-		if len(cfg.AllowMethods) == 0 {
-			cfg.AllowMethods = ConfigDefault.AllowMethods
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		if len(overrides) > 0 {
+			path := c.Path()
+			for _, o := range overrides {
+				if o.match(path) {
+					return o.policy.handle(c)
+				}
+			}
 		}
+		return root.handle(c)
+	}
+}
+
+// policy is a compiled CORS configuration
+type policy struct {
+	maxAge          string
+	allowOrigins    []string
+	allowSOrigins   []subdomain
+	allowPOrigins   []string // origins which are allowed with any port
+	cfg             Config
+	allowAllOrigins bool
+}
+
+// override is a policy which replaces the config for a path prefix
+type override struct {
+	policy *policy
+	prefix string
+}
+
+// match reports if the path is the prefix or below it
+func (o override) match(path string) bool {
+	return strings.HasPrefix(path, o.prefix) && (len(path) == len(o.prefix) || path[len(o.prefix)] == '/')
+}
+
+// newPolicy validates and normalizes the config
+func newPolicy(cfg Config) *policy {
+	// Set default values
+	if len(cfg.AllowMethods) == 0 {
+		cfg.AllowMethods = ConfigDefault.AllowMethods
 	}
 
 	// Warning logs if both AllowOrigins and AllowOriginsFunc are set
@@ -33,6 +85,7 @@ func New(config ...Config) fiber.Handler {
 	// defined in the 'AllowOrigins' configuration.
 	allowOrigins := []string{}
 	allowSOrigins := []subdomain{}
+	allowPOrigins := []string{}
 	allowAllOrigins := false
 
 	// Validate and normalize static AllowOrigins
@@ -44,21 +97,32 @@ func New(config ...Config) fiber.Handler {
 			allowAllOrigins = true
 			break
 		}
+		origin = utils.Trim(origin, ' ')
+
+		// A trailing ":*" allows any port, e.g. "http://localhost:*"
+		anyPort := strings.HasSuffix(origin, ":*")
+		if anyPort {
+			origin = strings.TrimSuffix(origin, ":*")
+		}
+
 		if i := strings.Index(origin, "://*."); i != -1 {
-			trimmedOrigin := utils.Trim(origin[:i+3]+origin[i+4:], ' ')
+			trimmedOrigin := origin[:i+3] + origin[i+4:]
 			isValid, normalizedOrigin := normalizeOrigin(trimmedOrigin)
-			if !isValid {
+			if !isValid || anyPort && hasPort(normalizedOrigin) {
 				panic("[CORS] Invalid origin format in configuration: " + trimmedOrigin)
 			}
-			sd := subdomain{prefix: normalizedOrigin[:i+3], suffix: normalizedOrigin[i+3:]}
+			sd := subdomain{prefix: normalizedOrigin[:i+3], suffix: normalizedOrigin[i+3:], anyPort: anyPort}
 			allowSOrigins = append(allowSOrigins, sd)
 		} else {
-			trimmedOrigin := utils.Trim(origin, ' ')
-			isValid, normalizedOrigin := normalizeOrigin(trimmedOrigin)
-			if !isValid {
-				panic("[CORS] Invalid origin format in configuration: " + trimmedOrigin)
+			isValid, normalizedOrigin := normalizeOrigin(origin)
+			if !isValid || anyPort && hasPort(normalizedOrigin) {
+				panic("[CORS] Invalid origin format in configuration: " + origin)
+			}
+			if anyPort {
+				allowPOrigins = append(allowPOrigins, normalizedOrigin)
+			} else {
+				allowOrigins = append(allowOrigins, normalizedOrigin)
 			}
-			allowOrigins = append(allowOrigins, normalizedOrigin)
 		}
 	}
 
@@ -72,116 +136,138 @@ func New(config ...Config) fiber.Handler {
 		log.Warn("[CORS] 'AllowOrigins' is set to allow all origins, 'AllowOriginsFunc' will not be used.")
 	}
 
-	// Convert int to string
-	maxAge := strconv.Itoa(cfg.MaxAge)
+	return &policy{
+		cfg:             cfg,
+		allowOrigins:    allowOrigins,
+		allowSOrigins:   allowSOrigins,
+		allowPOrigins:   allowPOrigins,
+		allowAllOrigins: allowAllOrigins,
+		// Convert int to string
+		maxAge: strconv.Itoa(cfg.MaxAge),
+	}
+}
 
-	// Return new handler
-	return func(c fiber.Ctx) error {
-		// Don't execute middleware if Next returns true
-		if cfg.Next != nil && cfg.Next(c) {
-			return c.Next()
-		}
+// handle applies the policy to the request
+func (p *policy) handle(c fiber.Ctx) error {
+	cfg := p.cfg
 
-		// Get originHeader header
-		originHeader := strings.ToLower(c.Get(fiber.HeaderOrigin))
+	// Don't execute middleware if Next returns true
+	if cfg.Next != nil && cfg.Next(c) {
+		return c.Next()
+	}
 
-		// If the request does not have Origin header, the request is outside the scope of CORS
-		if originHeader == "" {
-			// See https://fetch.spec.whatwg.org/#cors-protocol-and-http-caches
-			// Unless all origins are allowed, we include the Vary header to cache the response correctly
-			if !allowAllOrigins {
-				c.Vary(fiber.HeaderOrigin)
-			}
+	// See https://fetch.spec.whatwg.org/#cors-protocol-and-http-caches
+	// The response depends on the request headers of CORS whether the request is a
+	// CORS request or not, so every response varies by all of them, even if all
+	// origins are allowed. Otherwise, caches could serve a response without CORS
+	// headers to a CORS request, or a simple response to a preflight request.
+	c.Vary(fiber.HeaderOrigin, fiber.HeaderAccessControlRequestMethod, fiber.HeaderAccessControlRequestHeaders)
 
-			return c.Next()
-		}
+	// Get originHeader header
+	originHeader := strings.ToLower(c.Get(fiber.HeaderOrigin))
 
-		// If it's a preflight request and doesn't have Access-Control-Request-Method header, it's outside the scope of CORS
-		if c.Method() == fiber.MethodOptions && c.Get(fiber.HeaderAccessControlRequestMethod) == "" {
-			// Response to OPTIONS request should not be cached but,
-			// some caching can be configured to cache such responses.
-			// To Avoid poisoning the cache, we include the Vary header
-			// for non-CORS OPTIONS requests:
-			c.Vary(fiber.HeaderOrigin)
-			return c.Next()
-		}
+	// If the request does not have Origin header, the request is outside the scope of CORS
+	if originHeader == "" {
+		return c.Next()
+	}
+
+	// If it's a preflight request and doesn't have Access-Control-Request-Method header, it's outside the scope of CORS
+	if c.Method() == fiber.MethodOptions && c.Get(fiber.HeaderAccessControlRequestMethod) == "" {
+		return c.Next()
+	}
 
-		// Set default allowOrigin to empty string
-		allowOrigin := ""
+	// Set default allowOrigin to empty string
+	allowOrigin := ""
 
-		// Check allowed origins
-		if allowAllOrigins {
-			allowOrigin = "*"
-		} else {
-			// Check if the origin is in the list of allowed origins
-			for _, origin := range allowOrigins {
-				if origin == originHeader {
+	// Check allowed origins
+	if p.allowAllOrigins {
+		allowOrigin = "*"
+	} else {
+		// Check if the origin is in the list of allowed origins
+		for _, origin := range p.allowOrigins {
+			if origin == originHeader {
+				allowOrigin = originHeader
+				break
+			}
+		}
+
+		// Check if the origin is in the list of origins allowed with any port
+		if allowOrigin == "" && len(p.allowPOrigins) > 0 {
+			withoutPort := trimPort(originHeader)
+			for _, origin := range p.allowPOrigins {
+				if origin == withoutPort {
 					allowOrigin = originHeader
 					break
 				}
 			}
+		}
 
-			// Check if the origin is in the list of allowed subdomains
-			if allowOrigin == "" {
-				for _, sOrigin := range allowSOrigins {
-					if sOrigin.match(originHeader) {
-						allowOrigin = originHeader
-						break
-					}
+		// Check if the origin is in the list of allowed subdomains
+		if allowOrigin == "" {
+			for _, sOrigin := range p.allowSOrigins {
+				if sOrigin.match(originHeader) {
+					allowOrigin = originHeader
+					break
 				}
 			}
 		}
+	}
 
-		// Run AllowOriginsFunc if the logic for
-		// handling the value in 'AllowOrigins' does
-		// not result in allowOrigin being set.
-		if allowOrigin == "" && cfg.AllowOriginsFunc != nil && cfg.AllowOriginsFunc(originHeader) {
+	// Run AllowOriginsFunc if the logic for
+	// handling the value in 'AllowOrigins' does
+	// not result in allowOrigin being set.
+	// Well-formed origins are passed to the function normalized like
+	// the static origins, so they can be compared with stored origins.
+	if allowOrigin == "" && cfg.AllowOriginsFunc != nil {
+		origin := originHeader
+		if isValid, normalizedOrigin := normalizeOrigin(originHeader); isValid {
+			origin = normalizedOrigin
+		}
+		if cfg.AllowOriginsFunc(origin) {
 			allowOrigin = originHeader
 		}
+	}
 
-		// Simple request
-		// Ommit allowMethods and allowHeaders, only used for pre-flight requests
-		if c.Method() != fiber.MethodOptions {
-			if !allowAllOrigins {
-				// See https://fetch.spec.whatwg.org/#cors-protocol-and-http-caches
-				c.Vary(fiber.HeaderOrigin)
-			}
-			setSimpleHeaders(c, allowOrigin, maxAge, cfg)
-			return c.Next()
-		}
+	if allowOrigin == "" && cfg.OriginRejected != nil {
+		cfg.OriginRejected(c, originHeader)
+	}
 
-		// Pre-flight request
-
-		// Response to OPTIONS request should not be cached but,
-		// some caching can be configured to cache such responses.
-		// To Avoid poisoning the cache, we include the Vary header
-		// of preflight responses:
-		c.Vary(fiber.HeaderAccessControlRequestMethod)
-		c.Vary(fiber.HeaderAccessControlRequestHeaders)
-		if cfg.AllowPrivateNetwork && c.Get(fiber.HeaderAccessControlRequestPrivateNetwork) == "true" {
-			c.Vary(fiber.HeaderAccessControlRequestPrivateNetwork)
-			c.Set(fiber.HeaderAccessControlAllowPrivateNetwork, "true")
-		}
-		c.Vary(fiber.HeaderOrigin)
+	// Simple request
+	// Ommit allowMethods and allowHeaders, only used for pre-flight requests
+	if c.Method() != fiber.MethodOptions {
+		setSimpleHeaders(c, allowOrigin, p.maxAge, cfg)
+		return c.Next()
+	}
 
-		setSimpleHeaders(c, allowOrigin, maxAge, cfg)
+	// Pre-flight request
 
-		// Set Preflight headers
-		if len(cfg.AllowMethods) > 0 {
-			c.Set(fiber.HeaderAccessControlAllowMethods, strings.Join(cfg.AllowMethods, ", "))
-		}
-		if len(cfg.AllowHeaders) > 0 {
-			c.Set(fiber.HeaderAccessControlAllowHeaders, strings.Join(cfg.AllowHeaders, ", "))
-		} else {
-			h := c.Get(fiber.HeaderAccessControlRequestHeaders)
-			if h != "" {
-				c.Set(fiber.HeaderAccessControlAllowHeaders, h)
-			}
-		}
+	// Don't answer a preflight request of a disallowed origin like an allowed one
+	if allowOrigin == "" && cfg.RejectDisallowedPreflight {
+		return c.SendStatus(fiber.StatusForbidden)
+	}
+
+	if cfg.AllowPrivateNetwork && c.Get(fiber.HeaderAccessControlRequestPrivateNetwork) == "true" {
+		c.Vary(fiber.HeaderAccessControlRequestPrivateNetwork)
+		c.Set(fiber.HeaderAccessControlAllowPrivateNetwork, "true")
+	}
+
+	setSimpleHeaders(c, allowOrigin, p.maxAge, cfg)
 
-		// Send 204 No Content
-		return c.SendStatus(fiber.StatusNoContent)
+	// Set Preflight headers
+	if len(cfg.AllowMethods) > 0 {
+		c.Set(fiber.HeaderAccessControlAllowMethods, strings.Join(cfg.AllowMethods, ", "))
 	}
+	if len(cfg.AllowHeaders) > 0 {
+		c.Set(fiber.HeaderAccessControlAllowHeaders, strings.Join(cfg.AllowHeaders, ", "))
+	} else {
+		h := c.Get(fiber.HeaderAccessControlRequestHeaders)
+		if h != "" {
+			c.Set(fiber.HeaderAccessControlAllowHeaders, h)
+		}
+	}
+
+	// Send 204 No Content
+	return c.SendStatus(fiber.StatusNoContent)
 }
 
 // Function to set Simple CORS headers
@@ -157,7 +157,7 @@ func Test_CORS_Wildcard(t *testing.T) {
 	ctx.Request.Header.Set(fiber.HeaderOrigin, "http://localhost")
 	handler(ctx)
 
-	require.NotContains(t, string(ctx.Response.Header.Peek(fiber.HeaderVary)), fiber.HeaderOrigin, "Vary header should not be set")
+	require.Contains(t, string(ctx.Response.Header.Peek(fiber.HeaderVary)), fiber.HeaderOrigin, "Vary header should be set")
 	require.Equal(t, "", string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowCredentials)))
 	require.Equal(t, "X-Request-ID", string(ctx.Response.Header.Peek(fiber.HeaderAccessControlExposeHeaders)))
 }
@@ -679,6 +679,44 @@ func Test_CORS_AllowOriginsFunc(t *testing.T) {
 	require.Equal(t, "http://example-2.com", string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowOrigin)))
 }
 
+func Test_CORS_AllowOriginsFunc_Normalized(t *testing.T) {
+	t.Parallel()
+	var received []string
+	allowed := map[string]bool{"https://tenant.example.com": true}
+
+	app := fiber.New()
+	app.Use("/", New(Config{
+		AllowOriginsFunc: func(origin string) bool {
+			received = append(received, origin)
+			return allowed[origin]
+		},
+	}))
+
+	handler := app.Handler()
+
+	testCases := []struct {
+		origin      string
+		allowOrigin string
+	}{
+		{origin: "https://Tenant.Example.com", allowOrigin: "https://tenant.example.com"},
+		{origin: "https://evil.com", allowOrigin: ""},
+		{origin: "null", allowOrigin: ""},
+	}
+
+	for _, tc := range testCases {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.SetRequestURI("/")
+		ctx.Request.Header.SetMethod(fiber.MethodGet)
+		ctx.Request.Header.Set(fiber.HeaderOrigin, tc.origin)
+
+		handler(ctx)
+
+		require.Equal(t, tc.allowOrigin, string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowOrigin)), tc.origin)
+	}
+
+	require.Equal(t, []string{"https://tenant.example.com", "https://evil.com", "null"}, received)
+}
+
 func Test_CORS_AllowOriginsAndAllowOriginsFunc_AllUseCases(t *testing.T) {
 	testCases := []struct {
 		Name           string
@@ -1465,3 +1503,266 @@ func Benchmark_CORS_NewHandlerPreflightWildcard_Parallel(b *testing.B) {
 		}
 	})
 }
+
+// go test -run Test_CORS_Overrides
+func Test_CORS_Overrides(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		AllowOrigins: []string{"*"},
+		Overrides: map[string]Config{
+			"/admin": {
+				AllowOrigins:     []string{"https://admin.example.com"},
+				AllowCredentials: true,
+			},
+			"/admin/public/": {
+				AllowOrigins: []string{"https://docs.example.com"},
+			},
+		},
+	}))
+
+	handler := app.Handler()
+
+	testCases := []struct {
+		path        string
+		origin      string
+		allowOrigin string
+		credentials string
+	}{
+		{path: "/api/users", origin: "https://any.com", allowOrigin: "*"},
+		{path: "/admin", origin: "https://admin.example.com", allowOrigin: "https://admin.example.com", credentials: "true"},
+		{path: "/admin/users", origin: "https://admin.example.com", allowOrigin: "https://admin.example.com", credentials: "true"},
+		{path: "/admin/users", origin: "https://any.com", allowOrigin: ""},
+		// The longest prefix wins
+		{path: "/admin/public/info", origin: "https://docs.example.com", allowOrigin: "https://docs.example.com"},
+		// Prefixes only match whole path segments
+		{path: "/administrator", origin: "https://any.com", allowOrigin: "*"},
+	}
+
+	for _, tc := range testCases {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.SetRequestURI(tc.path)
+		ctx.Request.Header.SetMethod(fiber.MethodOptions)
+		ctx.Request.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodGet)
+		ctx.Request.Header.Set(fiber.HeaderOrigin, tc.origin)
+
+		handler(ctx)
+
+		require.Equal(t, tc.allowOrigin, string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowOrigin)), tc.path)
+		require.Equal(t, tc.credentials, string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowCredentials)), tc.path)
+	}
+}
+
+// go test -run Test_CORS_Overrides_Invalid
+func Test_CORS_Overrides_Invalid(t *testing.T) {
+	t.Parallel()
+	require.Panics(t, func() {
+		New(Config{Overrides: map[string]Config{"admin": {}}})
+	})
+	require.Panics(t, func() {
+		New(Config{Overrides: map[string]Config{"/admin": {AllowOrigins: []string{"*"}, AllowCredentials: true}}})
+	})
+}
+
+// go test -run Test_CORS_AllowOrigins_PortPatterns
+func Test_CORS_AllowOrigins_PortPatterns(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		AllowOrigins:     []string{"http://localhost:*", "https://*.preview.example.com:*", "https://example.com"},
+		AllowCredentials: true,
+	}))
+
+	handler := app.Handler()
+
+	testCases := []struct {
+		origin  string
+		allowed bool
+	}{
+		{origin: "http://localhost:3000", allowed: true},
+		{origin: "http://localhost", allowed: true},
+		{origin: "https://localhost:3000", allowed: false},
+		{origin: "http://localhost.evil.com:3000", allowed: false},
+		{origin: "https://pr-42.preview.example.com:8443", allowed: true},
+		{origin: "https://pr-42.preview.example.com", allowed: true},
+		{origin: "http://pr-42.preview.example.com", allowed: false},
+		{origin: "https://example.com", allowed: true},
+		// Exact origins don't allow other ports
+		{origin: "https://example.com:8443", allowed: false},
+	}
+
+	for _, tc := range testCases {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.SetRequestURI("/")
+		ctx.Request.Header.SetMethod(fiber.MethodGet)
+		ctx.Request.Header.Set(fiber.HeaderOrigin, tc.origin)
+
+		handler(ctx)
+
+		if tc.allowed {
+			// The specific origin is reflected, so credentials are safe to allow
+			require.Equal(t, tc.origin, string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowOrigin)), tc.origin)
+			require.Equal(t, "true", string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowCredentials)), tc.origin)
+		} else {
+			require.Empty(t, ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowOrigin), tc.origin)
+		}
+	}
+}
+
+// go test -run Test_CORS_AllowOrigins_InvalidPortPatterns
+func Test_CORS_AllowOrigins_InvalidPortPatterns(t *testing.T) {
+	t.Parallel()
+	for _, origin := range []string{"http://localhost:3000:*", "http://*:*", "https://*.example.com:8080:*"} {
+		require.Panics(t, func() {
+			New(Config{AllowOrigins: []string{origin}})
+		}, origin)
+	}
+}
+
+// go test -run Test_CORS_RejectDisallowedPreflight
+func Test_CORS_RejectDisallowedPreflight(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		origin string
+		reject bool
+		status int
+	}{
+		{name: "disallowed origin rejected", origin: "https://evil.com", reject: true, status: fiber.StatusForbidden},
+		{name: "disallowed origin not rejected", origin: "https://evil.com", reject: false, status: fiber.StatusNoContent},
+		{name: "allowed origin", origin: "https://example.com", reject: true, status: fiber.StatusNoContent},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var rejected []string
+			app := fiber.New()
+			app.Use(New(Config{
+				AllowOrigins:              []string{"https://example.com"},
+				RejectDisallowedPreflight: tc.reject,
+				OriginRejected: func(_ fiber.Ctx, origin string) {
+					rejected = append(rejected, origin)
+				},
+			}))
+
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.SetMethod(fiber.MethodOptions)
+			ctx.Request.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodGet)
+			ctx.Request.Header.Set(fiber.HeaderOrigin, tc.origin)
+			app.Handler()(ctx)
+
+			require.Equal(t, tc.status, ctx.Response.StatusCode())
+			require.Equal(t, "Origin, Access-Control-Request-Method, Access-Control-Request-Headers", string(ctx.Response.Header.Peek(fiber.HeaderVary)))
+			if tc.origin == "https://example.com" {
+				require.Equal(t, tc.origin, string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowOrigin)))
+				require.Empty(t, rejected)
+			} else {
+				require.Empty(t, ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowOrigin))
+				require.Equal(t, []string{tc.origin}, rejected)
+			}
+		})
+	}
+}
+
+// go test -run Test_CORS_OriginRejected_SimpleRequest
+func Test_CORS_OriginRejected_SimpleRequest(t *testing.T) {
+	t.Parallel()
+	var rejected []string
+	app := fiber.New()
+	app.Use(New(Config{
+		AllowOrigins: []string{"https://example.com"},
+		OriginRejected: func(c fiber.Ctx, origin string) {
+			rejected = append(rejected, c.Method()+" "+origin)
+		},
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	handler := app.Handler()
+	for _, origin := range []string{"https://example.com", "https://evil.com", ""} {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(fiber.MethodGet)
+		if origin != "" {
+			ctx.Request.Header.Set(fiber.HeaderOrigin, origin)
+		}
+		handler(ctx)
+		require.Equal(t, fiber.StatusOK, ctx.Response.StatusCode())
+	}
+
+	require.Equal(t, []string{"GET https://evil.com"}, rejected)
+}
+
+// go test -run Test_CORS_Vary
+func Test_CORS_Vary(t *testing.T) {
+	t.Parallel()
+
+	configs := map[string]Config{
+		"allowed origins": {AllowOrigins: []string{"https://example.com"}},
+		"all origins":     {},
+	}
+	for name, config := range configs {
+		handler := func() fasthttp.RequestHandler {
+			app := fiber.New()
+			app.Use(New(config))
+			app.Get("/", func(c fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+			return app.Handler()
+		}()
+
+		// Simple, non-CORS and preflight requests vary by the same headers
+		for _, method := range []string{fiber.MethodGet, fiber.MethodOptions} {
+			for _, origin := range []string{"", "https://example.com", "https://evil.com"} {
+				ctx := &fasthttp.RequestCtx{}
+				ctx.Request.Header.SetMethod(method)
+				if origin != "" {
+					ctx.Request.Header.Set(fiber.HeaderOrigin, origin)
+				}
+				handler(ctx)
+				require.Equal(t, "Origin, Access-Control-Request-Method, Access-Control-Request-Headers", string(ctx.Response.Header.Peek(fiber.HeaderVary)), name+" "+method+" "+origin)
+
+				ctx.Request.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodGet)
+				ctx.Response.Reset()
+				handler(ctx)
+				require.Equal(t, "Origin, Access-Control-Request-Method, Access-Control-Request-Headers", string(ctx.Response.Header.Peek(fiber.HeaderVary)), name+" "+method+" "+origin)
+			}
+		}
+	}
+}
+
+func Test_CORS_EnableAutoOptions(t *testing.T) {
+	t.Parallel()
+	app := fiber.New(fiber.Config{EnableAutoOptions: true})
+	app.Use(New(Config{
+		AllowOrigins: []string{"https://example.com"},
+	}))
+	app.Get("/users", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	handler := app.Handler()
+
+	// Preflight requests are answered by the middleware
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fiber.MethodOptions)
+	ctx.Request.SetRequestURI("/users")
+	ctx.Request.Header.Set(fiber.HeaderOrigin, "https://example.com")
+	ctx.Request.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodGet)
+	handler(ctx)
+	require.Equal(t, fiber.StatusNoContent, ctx.Response.StatusCode())
+	require.Equal(t, "https://example.com", string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowOrigin)))
+	require.NotEmpty(t, string(ctx.Response.Header.Peek(fiber.HeaderAccessControlAllowMethods)))
+	require.Empty(t, string(ctx.Response.Header.Peek(fiber.HeaderAllow)))
+
+	// Other OPTIONS requests are answered by the router with the allowed methods
+	ctx = &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fiber.MethodOptions)
+	ctx.Request.SetRequestURI("/users")
+	handler(ctx)
+	require.Equal(t, fiber.StatusNoContent, ctx.Response.StatusCode())
+	require.Equal(t, "GET, OPTIONS", string(ctx.Response.Header.Peek(fiber.HeaderAllow)))
+	require.Equal(t, "Origin, Access-Control-Request-Method, Access-Control-Request-Headers", string(ctx.Response.Header.Peek(fiber.HeaderVary)))
+}
@@ -59,8 +59,41 @@ type subdomain struct {
 	// The wildcard pattern
 	prefix string
 	suffix string
+	// anyPort allows the subdomains with any port
+	anyPort bool
 }
 
 func (s subdomain) match(o string) bool {
+	if s.anyPort {
+		o = trimPort(o)
+	}
 	return len(o) >= len(s.prefix)+len(s.suffix) && strings.HasPrefix(o, s.prefix) && strings.HasSuffix(o, s.suffix)
 }
+
+// portIndex returns the index of the colon in front of the port of a normalized origin, or -1
+func portIndex(origin string) int {
+	i := strings.LastIndexByte(origin, ':')
+	// The colon of the scheme and colons inside an IPv6 address are not followed by digits only
+	if i == -1 || i == len(origin)-1 {
+		return -1
+	}
+	for _, r := range origin[i+1:] {
+		if r < '0' || r > '9' {
+			return -1
+		}
+	}
+	return i
+}
+
+// hasPort reports if the normalized origin contains a port
+func hasPort(origin string) bool {
+	return portIndex(origin) != -1
+}
+
+// trimPort removes the port from the normalized origin
+func trimPort(origin string) string {
+	if i := portIndex(origin); i != -1 {
+		return origin[:i]
+	}
+	return origin
+}
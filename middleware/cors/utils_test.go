@@ -194,3 +194,35 @@ func Test_CORS_SubdomainMatch(t *testing.T) {
 		})
 	}
 }
+
+func Test_CORS_TrimPort(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		origin   string
+		expected string
+	}{
+		{origin: "http://localhost:3000", expected: "http://localhost"},
+		{origin: "http://localhost", expected: "http://localhost"},
+		{origin: "http://[::1]:8080", expected: "http://[::1]"},
+		{origin: "http://[::1]", expected: "http://[::1]"},
+		{origin: "https://example.com:", expected: "https://example.com:"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, trimPort(tc.origin), tc.origin)
+		assert.Equal(t, tc.expected != tc.origin, hasPort(tc.origin), tc.origin)
+	}
+}
+
+func Test_CORS_SubdomainMatch_AnyPort(t *testing.T) {
+	t.Parallel()
+	s := subdomain{prefix: "https://", suffix: ".example.com", anyPort: true}
+
+	assert.True(t, s.match("https://preview-1.example.com:8443"))
+	assert.True(t, s.match("https://preview-1.example.com"))
+	assert.False(t, s.match("http://preview-1.example.com:8443"))
+	assert.False(t, s.match("https://preview-1.example.com.evil.com:8443"))
+
+	s.anyPort = false
+	assert.False(t, s.match("https://preview-1.example.com:8443"))
+}
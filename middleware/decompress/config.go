@@ -0,0 +1,57 @@
+package decompress
+
+import (
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// LimitExceeded is called instead of the next handler if the decompressed
+	// request body exceeds the Limit, e.g. to respond with a structured error.
+	//
+	// Optional. Default: func(c fiber.Ctx) error {
+	//   return fiber.ErrRequestEntityTooLarge
+	// }
+	LimitExceeded fiber.Handler
+
+	// Limit is the maximum size of the decompressed request body in bytes, which
+	// protects the app from small bodies which are decompressed to gigabytes (zip bombs).
+	// The BodyLimit of the app limits the size of the compressed body.
+	//
+	// Optional. Default: 10 * 1024 * 1024
+	Limit int
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next: nil,
+	LimitExceeded: func(_ fiber.Ctx) error {
+		return fiber.ErrRequestEntityTooLarge
+	},
+	Limit: 10 * 1024 * 1024,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.LimitExceeded == nil {
+		cfg.LimitExceeded = ConfigDefault.LimitExceeded
+	}
+	if cfg.Limit <= 0 {
+		cfg.Limit = ConfigDefault.Limit
+	}
+	return cfg
+}
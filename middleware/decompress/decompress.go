@@ -0,0 +1,109 @@
+// Package decompress provides a middleware which decompresses request bodies by their
+// Content-Encoding header before they are read by the handlers, with a limit of the
+// decompressed size.
+package decompress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+	"github.com/valyala/bytebufferpool"
+)
+
+var (
+	errLimitExceeded       = errors.New("decompress: body exceeds the limit")
+	errUnsupportedEncoding = errors.New("decompress: unsupported content encoding")
+)
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		req := c.Request()
+		header := utils.Trim(utils.UnsafeString(req.Header.ContentEncoding()), ' ')
+		if header == "" {
+			return c.Next()
+		}
+
+		// Each decoding reads from the result of the previous one, so two buffers are swapped
+		bb, prev := bytebufferpool.Get(), bytebufferpool.Get()
+		defer bytebufferpool.Put(bb)
+		defer bytebufferpool.Put(prev)
+
+		// The encodings are listed in the order in which they were applied (RFC 9110)
+		body := req.Body()
+		encodings := strings.Split(header, ",")
+		for i := len(encodings) - 1; i >= 0; i-- {
+			encoding := utils.ToLower(utils.Trim(encodings[i], ' '))
+			if encoding == "identity" {
+				continue
+			}
+			bb, prev = prev, bb
+			bb.Reset()
+			if err := decode(bb, encoding, body, cfg.Limit); err != nil {
+				switch {
+				case errors.Is(err, errLimitExceeded):
+					return cfg.LimitExceeded(c)
+				case errors.Is(err, errUnsupportedEncoding):
+					return fiber.NewError(fiber.StatusUnsupportedMediaType, "Unsupported Content-Encoding: "+encoding)
+				default:
+					return fiber.NewError(fiber.StatusBadRequest, "Invalid "+encoding+" body")
+				}
+			}
+			body = bb.Bytes()
+		}
+
+		// The handlers read the decompressed body as if it was sent without an encoding
+		req.SetBody(body)
+		req.Header.Del(fiber.HeaderContentEncoding)
+		req.Header.SetContentLength(len(body))
+
+		return c.Next()
+	}
+}
+
+// decode writes the decoded body to the buffer, reading at most one byte more than the
+// limit to find out whether the decoded body exceeds it
+func decode(bb *bytebufferpool.ByteBuffer, encoding string, body []byte, limit int) error {
+	var (
+		r   io.Reader
+		err error
+	)
+	switch encoding {
+	case "gzip", "x-gzip":
+		r, err = gzip.NewReader(bytes.NewReader(body))
+	case "deflate":
+		r, err = zlib.NewReader(bytes.NewReader(body))
+	case "br":
+		r = brotli.NewReader(bytes.NewReader(body))
+	default:
+		return errUnsupportedEncoding
+	}
+	if err != nil {
+		return fmt.Errorf("decompress: failed to read %s header: %w", encoding, err)
+	}
+
+	if _, err := bb.ReadFrom(io.LimitReader(r, int64(limit)+1)); err != nil {
+		return fmt.Errorf("decompress: failed to decode %s body: %w", encoding, err)
+	}
+	if bb.Len() > limit {
+		return errLimitExceeded
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package decompress
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Decompress
+func Test_Decompress(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New())
+	app.Post("/", func(c fiber.Ctx) error {
+		var body struct {
+			Name string `json:"name"`
+		}
+		if err := c.Bind().Body(&body); err != nil {
+			return err
+		}
+		return c.SendString(body.Name + " " + c.Get(fiber.HeaderContentEncoding))
+	})
+	body := []byte(`{"name":"john"}`)
+
+	for encoding, compressed := range map[string][]byte{
+		"":         body,
+		"identity": body,
+		"gzip":     fasthttp.AppendGzipBytes(nil, body),
+		"X-Gzip":   fasthttp.AppendGzipBytes(nil, body),
+		"deflate":  fasthttp.AppendDeflateBytes(nil, body),
+		"br":       fasthttp.AppendBrotliBytes(nil, body),
+		// The encodings are decoded in the reverse order
+		"gzip, br": fasthttp.AppendBrotliBytes(nil, fasthttp.AppendGzipBytes(nil, body)),
+	} {
+		req := httptest.NewRequest(fiber.MethodPost, "/", bytes.NewReader(compressed))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		if encoding != "" {
+			req.Header.Set(fiber.HeaderContentEncoding, encoding)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, encoding)
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, "john ", string(respBody), encoding)
+	}
+}
+
+// go test -run Test_Decompress_Errors
+func Test_Decompress_Errors(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New())
+	app.Post("/", func(c fiber.Ctx) error {
+		return c.Send(c.Body())
+	})
+
+	testCases := []struct {
+		encoding string
+		reqBody  string
+		body     string
+		status   int
+	}{
+		{encoding: "compress", reqBody: "data", status: fiber.StatusUnsupportedMediaType, body: "Unsupported Content-Encoding: compress"},
+		{encoding: "gzip", reqBody: "not gzip", status: fiber.StatusBadRequest, body: "Invalid gzip body"},
+		{encoding: "br", reqBody: "\xff\xff\xff\xff", status: fiber.StatusBadRequest, body: "Invalid br body"},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(tc.reqBody))
+		req.Header.Set(fiber.HeaderContentEncoding, tc.encoding)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, tc.status, resp.StatusCode, tc.encoding)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, tc.body, string(body), tc.encoding)
+	}
+}
+
+// go test -run Test_Decompress_Limit
+func Test_Decompress_Limit(t *testing.T) {
+	t.Parallel()
+
+	// 1 MB of zeros are compressed to about 2 KB
+	bomb := fasthttp.AppendGzipBytes(nil, make([]byte, 1024*1024))
+	require.Less(t, len(bomb), 4096)
+
+	app := fiber.New()
+	app.Use(New(Config{Limit: 1024}))
+	app.Post("/", func(c fiber.Ctx) error {
+		return c.Send(c.Body())
+	})
+
+	req := httptest.NewRequest(fiber.MethodPost, "/", bytes.NewReader(bomb))
+	req.Header.Set(fiber.HeaderContentEncoding, "gzip")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusRequestEntityTooLarge, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "Request Entity Too Large", string(body))
+
+	req = httptest.NewRequest(fiber.MethodPost, "/", bytes.NewReader(fasthttp.AppendGzipBytes(nil, []byte("john"))))
+	req.Header.Set(fiber.HeaderContentEncoding, "gzip")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "john", string(body))
+
+	app = fiber.New()
+	app.Use(New(Config{
+		Limit: 1024,
+		LimitExceeded: func(c fiber.Ctx) error {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": "body too large"})
+		},
+	}))
+	app.Post("/", func(c fiber.Ctx) error {
+		return c.Send(c.Body())
+	})
+
+	req = httptest.NewRequest(fiber.MethodPost, "/", bytes.NewReader(bomb))
+	req.Header.Set(fiber.HeaderContentEncoding, "gzip")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusRequestEntityTooLarge, resp.StatusCode)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"error":"body too large"}`, string(body))
+}
+
+// go test -run Test_Decompress_Next
+func Test_Decompress_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	}))
+	app.Post("/", func(c fiber.Ctx) error {
+		var body struct {
+			Name string `json:"name"`
+		}
+		if err := c.Bind().Body(&body); err != nil {
+			return err
+		}
+		return c.SendString(body.Name + " " + c.Get(fiber.HeaderContentEncoding))
+	})
+
+	// The body is decoded by c.Body() instead, which keeps the header
+	req := httptest.NewRequest(fiber.MethodPost, "/", bytes.NewReader(fasthttp.AppendGzipBytes(nil, []byte(`{"name":"john"}`))))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(fiber.HeaderContentEncoding, "gzip")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "john gzip", string(body))
+}
+
+// go test -v -run=^$ -bench=Benchmark_Decompress -benchmem -count=4
+func Benchmark_Decompress(b *testing.B) {
+	app := fiber.New()
+	app.Use(New())
+	app.Post("/", func(c fiber.Ctx) error {
+		return c.Send(c.Body())
+	})
+	h := app.Handler()
+	body := fasthttp.AppendGzipBytes(nil, []byte(`{"name":"john"}`))
+
+	fctx := &fasthttp.RequestCtx{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		fctx.Request.Reset()
+		fctx.Request.Header.SetMethod(fiber.MethodPost)
+		fctx.Request.SetRequestURI("/")
+		fctx.Request.Header.SetContentType(fiber.MIMEApplicationJSON)
+		fctx.Request.Header.Set(fiber.HeaderContentEncoding, "gzip")
+		fctx.Request.SetBody(body)
+		h(fctx)
+	}
+}
@@ -1,6 +1,8 @@
 package etag
 
 import (
+	"hash"
+
 	"github.com/gofiber/fiber/v3"
 )
 
@@ -19,12 +21,35 @@ type Config struct {
 	// when byte range requests are used, but strong etags mean range
 	// requests can still be cached.
 	Weak bool
+
+	// WeakFunc decides per request whether a weak validator is used, e.g. to
+	// use weak etags only for some routes. It overrides Weak.
+	//
+	// Optional. Default: nil
+	WeakFunc func(c fiber.Ctx) bool
+
+	// Hash returns a new hash which is used to generate the etag from the body,
+	// e.g. sha256.New for etags which are strong against collisions.
+	// The hashes are reused, so it must return a new hash on every call.
+	//
+	// Optional. Default: nil (a fast CRC-32 checksum)
+	Hash func() hash.Hash
+
+	// MaxBodySize is the maximum size of a body an etag is generated for, to not spend
+	// time on hashing large bodies. Streamed bodies are read into memory to be hashed,
+	// unless their Content-Length is known to exceed it.
+	//
+	// Optional. Default: 0 (no limit)
+	MaxBodySize int
 }
 
 // ConfigDefault is the default config
 var ConfigDefault = Config{
-	Weak: false,
-	Next: nil,
+	Weak:        false,
+	Next:        nil,
+	WeakFunc:    nil,
+	Hash:        nil,
+	MaxBodySize: 0,
 }
 
 // Helper function to set default values
@@ -1,12 +1,18 @@
 package etag
 
 import (
-	"bytes"
-	"hash/crc32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
 	"math"
+	"sync"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/httpcache"
+	"github.com/gofiber/utils/v2"
 	"github.com/valyala/bytebufferpool"
+	"github.com/valyala/fasthttp"
 )
 
 // New creates a new middleware handler
@@ -19,8 +25,14 @@ func New(config ...Config) fiber.Handler {
 		weakPrefix           = []byte("W/")
 	)
 
-	const crcPol = 0xD5828281
-	crc32q := crc32.MakeTable(crcPol)
+	var hashPool *sync.Pool
+	if cfg.Hash != nil {
+		hashPool = &sync.Pool{
+			New: func() any {
+				return cfg.Hash()
+			},
+		}
+	}
 
 	// Return new handler
 	return func(c fiber.Ctx) error {
@@ -38,71 +50,108 @@ func New(config ...Config) fiber.Handler {
 		if c.Response().StatusCode() != fiber.StatusOK {
 			return nil
 		}
+		// Read streamed bodies into memory to hash them, unless their length is known to
+		// exceed MaxBodySize
+		if c.Response().IsBodyStream() {
+			if cfg.MaxBodySize > 0 && c.Response().Header.ContentLength() > cfg.MaxBodySize {
+				return nil
+			}
+			if err := readBodyStream(c.Response()); err != nil {
+				return err
+			}
+		}
 		body := c.Response().Body()
 		// Skips ETag if no response body is present
 		if len(body) == 0 {
 			return nil
 		}
+		// Skip ETag if the body is too large to be hashed
+		if cfg.MaxBodySize > 0 && len(body) > cfg.MaxBodySize {
+			return nil
+		}
 		// Skip ETag if header is already present
 		if c.Response().Header.PeekBytes(normalizedHeaderETag) != nil {
 			return nil
 		}
 
-		// Generate ETag for response
-		bb := bytebufferpool.Get()
-		defer bytebufferpool.Put(bb)
-
-		// Enable weak tag
-		if cfg.Weak {
-			bb.Write(weakPrefix)
-		}
-
-		// Write ETag
-		bb.WriteByte('"')
-
 		bodyLength := len(body)
 		if bodyLength > math.MaxUint32 {
 			return c.SendStatus(fiber.StatusRequestEntityTooLarge)
 		}
 
-		bb.B = appendUint(bb.Bytes(), uint32(bodyLength))
-		bb.WriteByte('-')
-		bb.B = appendUint(bb.Bytes(), crc32.Checksum(body, crc32q))
-		bb.WriteByte('"')
-
-		etag := bb.Bytes()
-
-		// Get ETag header from request
-		clientEtag := c.Request().Header.Peek(fiber.HeaderIfNoneMatch)
+		// Enable weak tag
+		weak := cfg.Weak
+		if cfg.WeakFunc != nil {
+			weak = cfg.WeakFunc(c)
+		}
 
-		// Check if client's ETag is weak
-		if bytes.HasPrefix(clientEtag, weakPrefix) {
-			// Check if server's ETag is weak
-			if bytes.Equal(clientEtag[2:], etag) || bytes.Equal(clientEtag[2:], etag[2:]) {
-				// W/1 == 1 || W/1 == W/1
-				c.RequestCtx().ResetBody()
+		// Generate ETag for response
+		bb := bytebufferpool.Get()
+		defer bytebufferpool.Put(bb)
 
-				return c.SendStatus(fiber.StatusNotModified)
+		if hashPool == nil {
+			bb.B = httpcache.AppendETag(bb.B, body, weak)
+		} else {
+			if weak {
+				bb.Write(weakPrefix)
 			}
-			// W/1 != W/2 || W/1 != 2
-			c.Response().Header.SetCanonical(normalizedHeaderETag, etag)
-
-			return nil
+			bb.WriteByte('"')
+			bb.B = appendUint(bb.Bytes(), uint32(bodyLength))
+			bb.WriteByte('-')
+			bb.B = appendHash(bb.Bytes(), hashPool, body)
+			bb.WriteByte('"')
 		}
 
-		if bytes.Contains(clientEtag, etag) {
-			// 1 == 1
+		etag := bb.Bytes()
+
+		// Check if the client's ETags match with the weak comparison
+		if httpcache.NoneMatch(c.Get(fiber.HeaderIfNoneMatch), utils.UnsafeString(etag)) {
 			c.RequestCtx().ResetBody()
 
 			return c.SendStatus(fiber.StatusNotModified)
 		}
-		// 1 != 2
 		c.Response().Header.SetCanonical(normalizedHeaderETag, etag)
 
 		return nil
 	}
 }
 
+// readBodyStream reads the body stream of the response into a pooled buffer, which
+// becomes the body of the response without copying it.
+func readBodyStream(resp *fasthttp.Response) error {
+	bb := bytebufferpool.Get()
+	defer bytebufferpool.Put(bb)
+
+	_, err := bb.ReadFrom(resp.BodyStream())
+	if closeErr := resp.CloseBodyStream(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("etag: failed to read body stream: %w", err)
+	}
+	// The buffer of the previous body is put back into the pool
+	bb.B = resp.SwapBody(bb.B)
+	return nil
+}
+
+// appendHash appends the hex encoded hash of the body to dst and returns the extended dst.
+// The body is written to the hash directly, so it isn't copied.
+func appendHash(dst []byte, pool *sync.Pool, body []byte) []byte {
+	h, ok := pool.Get().(hash.Hash)
+	if !ok {
+		panic(errors.New("failed to type-assert to hash.Hash"))
+	}
+	defer func() {
+		h.Reset()
+		pool.Put(h)
+	}()
+
+	_, _ = h.Write(body) //nolint:errcheck // A hash never returns an error
+
+	var buf [64]byte
+	return hex.AppendEncode(dst, h.Sum(buf[:0]))
+}
+
 // appendUint appends n to dst and returns the extended dst.
 func appendUint(dst []byte, n uint32) []byte {
 	var b [20]byte
@@ -2,8 +2,11 @@ package etag
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gofiber/fiber/v3"
@@ -262,6 +265,125 @@ func Test_ETag_CustomEtagPut(t *testing.T) {
 	require.Equal(t, fiber.StatusPreconditionFailed, resp.StatusCode)
 }
 
+// go test -run Test_ETag_Hash
+func Test_ETag_Hash(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+
+	app.Use(New(Config{
+		Hash: sha256.New,
+	}))
+
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString("Hello, World!")
+	})
+
+	sum := sha256.Sum256([]byte("Hello, World!"))
+	etag := `"13-` + hex.EncodeToString(sum[:]) + `"`
+
+	// The hashes are reused, so the etag must be the same for every request
+	for i := 0; i < 3; i++ {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		require.Equal(t, etag, resp.Header.Get(fiber.HeaderETag))
+	}
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderIfNoneMatch, etag)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+}
+
+// go test -run Test_ETag_WeakFunc
+func Test_ETag_WeakFunc(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+
+	app.Use(New(Config{
+		WeakFunc: func(c fiber.Ctx) bool {
+			return strings.HasPrefix(c.Path(), "/weak")
+		},
+	}))
+
+	app.Get("/*", func(c fiber.Ctx) error {
+		return c.SendString("Hello, World!")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/weak", nil))
+	require.NoError(t, err)
+	require.Equal(t, `W/"13-1831710635"`, resp.Header.Get(fiber.HeaderETag))
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/strong", nil))
+	require.NoError(t, err)
+	require.Equal(t, `"13-1831710635"`, resp.Header.Get(fiber.HeaderETag))
+}
+
+// go test -run Test_ETag_MaxBodySize
+func Test_ETag_MaxBodySize(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+
+	app.Use(New(Config{
+		MaxBodySize: 5,
+	}))
+
+	app.Get("/small", func(c fiber.Ctx) error {
+		return c.SendString("Hello")
+	})
+	app.Get("/large", func(c fiber.Ctx) error {
+		return c.SendString("Hello, World!")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/small", nil))
+	require.NoError(t, err)
+	require.Equal(t, `"5-1615849884"`, resp.Header.Get(fiber.HeaderETag))
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/large", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Empty(t, resp.Header.Get(fiber.HeaderETag))
+}
+
+// go test -run Test_ETag_BodyStream
+func Test_ETag_BodyStream(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		etag        string
+		size        int
+		maxBodySize int
+	}{
+		{name: "unknown size", size: -1, etag: `"13-1831710635"`},
+		{name: "known size", size: 13, etag: `"13-1831710635"`},
+		{name: "below threshold", size: -1, maxBodySize: 13, etag: `"13-1831710635"`},
+		{name: "known size above threshold", size: 13, maxBodySize: 12},
+		{name: "unknown size above threshold", size: -1, maxBodySize: 12},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			app := fiber.New()
+
+			app.Use(New(Config{MaxBodySize: tc.maxBodySize}))
+
+			app.Get("/", func(c fiber.Ctx) error {
+				return c.SendStream(io.NopCloser(strings.NewReader("Hello, World!")), tc.size)
+			})
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			require.NoError(t, err)
+			require.Equal(t, fiber.StatusOK, resp.StatusCode)
+			require.Equal(t, tc.etag, resp.Header.Get(fiber.HeaderETag))
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, "Hello, World!", string(body))
+		})
+	}
+}
+
 // go test -v -run=^$ -bench=Benchmark_Etag -benchmem -count=4
 func Benchmark_Etag(b *testing.B) {
 	app := fiber.New()
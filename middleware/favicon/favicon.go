@@ -1,12 +1,13 @@
 package favicon
 
 import (
-	"io"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"strconv"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
 )
 
 // Config defines the config for middleware.
@@ -41,6 +42,40 @@ type Config struct {
 	//
 	// Optional. Default: nil
 	Data []byte `json:"-"`
+
+	// Icons defines further icons which are served in addition to the favicon,
+	// e.g. the apple-touch-icon or the icons referenced by a web app manifest.
+	//
+	// Optional. Default: nil
+	Icons []Icon `json:"icons"`
+}
+
+// Icon defines an icon which is served from memory
+type Icon struct {
+	// URL of the icon, e.g. "/apple-touch-icon.png"
+	//
+	// Required.
+	URL string `json:"url"`
+
+	// File holds the path to the icon, which is read from the FileSystem if it is set
+	//
+	// Optional. Default: ""
+	File string `json:"file"`
+
+	// ContentType of the icon
+	//
+	// Optional. Default: the MIME type of the file extension of File or URL
+	ContentType string `json:"content_type"`
+
+	// CacheControl defines how the Cache-Control header in the response should be set
+	//
+	// Optional. Default: the CacheControl of the config
+	CacheControl string `json:"cache_control"`
+
+	// Raw data of the icon, which can be used instead of File
+	//
+	// Optional. Default: nil
+	Data []byte `json:"-"`
 }
 
 // ConfigDefault is the default config
@@ -82,34 +117,29 @@ func New(config ...Config) fiber.Handler {
 		}
 	}
 
-	// Load iconData if provided
-	var (
-		err           error
-		iconData      []byte
-		iconLenHeader string
-		iconLen       int
-	)
-	if cfg.Data != nil {
-		// use the provided favicon data
-		iconData = cfg.Data
-		iconLenHeader = strconv.Itoa(len(cfg.Data))
-		iconLen = len(cfg.Data)
-	} else if cfg.File != "" {
-		// read from configured filesystem if present
-		if cfg.FileSystem != nil {
-			f, err := cfg.FileSystem.Open(cfg.File)
-			if err != nil {
-				panic(err)
-			}
-			if iconData, err = io.ReadAll(f); err != nil {
-				panic(err)
+	// Load the favicon and the icons
+	icons := make(map[string]*cachedIcon, len(cfg.Icons)+1)
+	icons[cfg.URL] = newIcon(cfg.FileSystem, cfg.File, cfg.Data, hType, cfg.CacheControl)
+	for _, i := range cfg.Icons {
+		if i.URL == "" {
+			panic("favicon: the URL of an icon is required")
+		}
+		if i.Data == nil && i.File == "" {
+			panic("favicon: the icon " + i.URL + " has no File or Data")
+		}
+		contentType := i.ContentType
+		if contentType == "" {
+			ext := filepath.Ext(i.File)
+			if ext == "" {
+				ext = filepath.Ext(i.URL)
 			}
-		} else if iconData, err = os.ReadFile(cfg.File); err != nil {
-			panic(err)
+			contentType = utils.GetMIME(ext)
 		}
-
-		iconLenHeader = strconv.Itoa(len(iconData))
-		iconLen = len(iconData)
+		cacheControl := i.CacheControl
+		if cacheControl == "" {
+			cacheControl = cfg.CacheControl
+		}
+		icons[i.URL] = newIcon(cfg.FileSystem, i.File, i.Data, contentType, cacheControl)
 	}
 
 	// Return new handler
@@ -120,7 +150,8 @@ func New(config ...Config) fiber.Handler {
 		}
 
 		// Only respond to favicon requests
-		if c.Path() != cfg.URL {
+		icon, ok := icons[c.Path()]
+		if !ok {
 			return c.Next()
 		}
 
@@ -137,13 +168,43 @@ func New(config ...Config) fiber.Handler {
 		}
 
 		// Serve cached favicon
-		if iconLen > 0 {
-			c.Set(fiber.HeaderContentLength, iconLenHeader)
-			c.Set(fiber.HeaderContentType, hType)
-			c.Set(fiber.HeaderCacheControl, cfg.CacheControl)
-			return c.Status(fiber.StatusOK).Send(iconData)
+		if len(icon.data) > 0 {
+			c.Set(fiber.HeaderContentLength, icon.length)
+			c.Set(fiber.HeaderContentType, icon.contentType)
+			c.Set(fiber.HeaderCacheControl, icon.cacheControl)
+			return c.Status(fiber.StatusOK).Send(icon.data)
 		}
 
 		return c.SendStatus(fiber.StatusNoContent)
 	}
 }
+
+// cachedIcon is an icon with its headers
+type cachedIcon struct {
+	length       string
+	contentType  string
+	cacheControl string
+	data         []byte
+}
+
+// newIcon returns the cached icon of the data, or of the file if data is nil
+func newIcon(fileSystem fs.FS, file string, data []byte, contentType, cacheControl string) *cachedIcon {
+	if data == nil && file != "" {
+		var err error
+		// read from configured filesystem if present
+		if fileSystem != nil {
+			data, err = fs.ReadFile(fileSystem, file)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+		if err != nil {
+			panic(err)
+		}
+	}
+	return &cachedIcon{
+		length:       strconv.Itoa(len(data)),
+		contentType:  contentType,
+		cacheControl: cacheControl,
+		data:         data,
+	}
+}
@@ -1,10 +1,12 @@
 package favicon
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
@@ -186,3 +188,66 @@ func Test_Favicon_Next(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
 }
+
+// go test -run Test_Favicon_Icons
+func Test_Favicon_Icons(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+
+	app.Use(New(Config{
+		File: "favicon.ico",
+		FileSystem: fstest.MapFS{
+			"favicon.ico":                {Data: []byte("ico")},
+			"icons/apple-touch-icon.png": {Data: []byte("png")},
+		},
+		Icons: []Icon{
+			{URL: "/apple-touch-icon.png", File: "icons/apple-touch-icon.png"},
+			{URL: "/icon-192.png", Data: []byte("192"), CacheControl: "public, max-age=100"},
+			{URL: "/icon.svg", Data: []byte("<svg/>"), ContentType: "image/svg+xml; charset=utf-8"},
+		},
+	}))
+
+	tests := []struct {
+		url          string
+		body         string
+		contentType  string
+		cacheControl string
+	}{
+		{url: "/favicon.ico", body: "ico", contentType: "image/x-icon", cacheControl: "public, max-age=31536000"},
+		{url: "/apple-touch-icon.png", body: "png", contentType: "image/png", cacheControl: "public, max-age=31536000"},
+		{url: "/icon-192.png", body: "192", contentType: "image/png", cacheControl: "public, max-age=100"},
+		{url: "/icon.svg", body: "<svg/>", contentType: "image/svg+xml; charset=utf-8", cacheControl: "public, max-age=31536000"},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.url, nil))
+		require.NoError(t, err, "app.Test(req)")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, tt.url)
+		require.Equal(t, tt.contentType, resp.Header.Get(fiber.HeaderContentType), tt.url)
+		require.Equal(t, tt.cacheControl, resp.Header.Get(fiber.HeaderCacheControl), tt.url)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, tt.body, string(body), tt.url)
+	}
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/icon-192.png", nil))
+	require.NoError(t, err, "app.Test(req)")
+	require.Equal(t, fiber.StatusMethodNotAllowed, resp.StatusCode, "Status code")
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/icon-512.png", nil))
+	require.NoError(t, err, "app.Test(req)")
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode, "Status code")
+}
+
+// go test -run Test_Favicon_Icons_Invalid
+func Test_Favicon_Icons_Invalid(t *testing.T) {
+	t.Parallel()
+	require.Panics(t, func() {
+		New(Config{Icons: []Icon{{File: "icon.png"}}})
+	})
+	require.Panics(t, func() {
+		New(Config{Icons: []Icon{{URL: "/icon.png"}}})
+	})
+	require.Panics(t, func() {
+		New(Config{Icons: []Icon{{URL: "/icon.png", File: "non-exist.png"}}})
+	})
+}
@@ -0,0 +1,63 @@
+package geoip
+
+import (
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Provider resolves the client IP of the request, e.g. an MMDB of a MaxMind database.
+	// The client IP is c.IP(), which respects the TrustProxy and ProxyHeader config of the app.
+	//
+	// Required. Default: nil
+	Provider Provider
+
+	// Forbidden is called if the country of the request is not allowed.
+	//
+	// Optional. Default: func(c fiber.Ctx) error {
+	//   return c.SendStatus(fiber.StatusForbidden)
+	// }
+	Forbidden fiber.Handler
+
+	// AllowCountries is a list of ISO 3166-1 country codes, e.g. "DE". If it is set,
+	// requests from other countries and from unknown locations are forbidden.
+	//
+	// Optional. Default: nil
+	AllowCountries []string
+
+	// DenyCountries is a list of ISO 3166-1 country codes, e.g. "DE". Requests from
+	// these countries are forbidden.
+	//
+	// Optional. Default: nil
+	DenyCountries []string
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next: nil,
+	Forbidden: func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusForbidden)
+	},
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Forbidden == nil {
+		cfg.Forbidden = ConfigDefault.Forbidden
+	}
+	return cfg
+}
@@ -0,0 +1,148 @@
+// Package geoip provides a middleware which resolves the country, region and
+// autonomous system of the client IP and allows or denies requests by country.
+package geoip
+
+import (
+	"net/netip"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// The contextKey type is unexported to prevent collisions with context keys defined in
+// other packages.
+type contextKey int
+
+// The keys for the values in context
+const (
+	recordKey contextKey = iota
+)
+
+// Record is the location of an IP address. Fields which are unknown are empty.
+type Record struct {
+	// Country is the ISO 3166-1 country code, e.g. "DE"
+	Country string
+	// Region is the ISO 3166-2 code of the subdivision in the country, e.g. "BE"
+	Region string
+	// City is the English name of the city, e.g. "Berlin"
+	City string
+	// Organization is the organization of the autonomous system, e.g. "Google LLC"
+	Organization string
+	// ASN is the number of the autonomous system, e.g. 15169
+	ASN uint
+}
+
+// Provider resolves the location of IP addresses.
+type Provider interface {
+	// Lookup returns the record of the IP address, or nil if it is unknown.
+	Lookup(ip netip.Addr) (*Record, error)
+}
+
+// providers combines the records of several providers
+type providers []Provider
+
+// Providers returns a Provider which combines the records of the providers, e.g. of
+// a country and an ASN database. Fields of a record are taken from the first
+// provider which knows them.
+func Providers(p ...Provider) Provider {
+	return providers(p)
+}
+
+// Lookup implements Provider
+func (p providers) Lookup(ip netip.Addr) (*Record, error) {
+	var record *Record
+	for _, provider := range p {
+		r, err := provider.Lookup(ip)
+		if err != nil {
+			return nil, err
+		}
+		if r == nil {
+			continue
+		}
+		if record == nil {
+			record = &Record{}
+		}
+		if record.Country == "" {
+			record.Country = r.Country
+		}
+		if record.Region == "" {
+			record.Region = r.Region
+		}
+		if record.City == "" {
+			record.City = r.City
+		}
+		if record.Organization == "" {
+			record.Organization = r.Organization
+		}
+		if record.ASN == 0 {
+			record.ASN = r.ASN
+		}
+	}
+	return record, nil
+}
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	if cfg.Provider == nil {
+		panic("geoip: Provider is required")
+	}
+	allow := countrySet(cfg.AllowCountries)
+	deny := countrySet(cfg.DenyCountries)
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		var record *Record
+		if ip, err := netip.ParseAddr(c.IP()); err == nil {
+			if record, err = cfg.Provider.Lookup(ip); err != nil {
+				return err
+			}
+		}
+
+		var country string
+		if record != nil {
+			country = record.Country
+		}
+		if allow != nil {
+			if _, ok := allow[country]; !ok {
+				return cfg.Forbidden(c)
+			}
+		}
+		if _, ok := deny[country]; ok && country != "" {
+			return cfg.Forbidden(c)
+		}
+
+		c.Locals(recordKey, record)
+
+		// Continue stack
+		return c.Next()
+	}
+}
+
+// countrySet returns the set of the upper case country codes, or nil if there are none
+func countrySet(countries []string) map[string]struct{} {
+	if len(countries) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(countries))
+	for _, country := range countries {
+		set[strings.ToUpper(country)] = struct{}{}
+	}
+	return set
+}
+
+// FromContext returns the record of the client IP of the request.
+// If the location is unknown or the middleware wasn't executed, nil is returned.
+func FromContext(c fiber.Ctx) *Record {
+	if record, ok := c.Locals(recordKey).(*Record); ok {
+		return record
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package geoip
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// mapProvider resolves the IP addresses of the map
+type mapProvider map[string]*Record
+
+func (p mapProvider) Lookup(ip netip.Addr) (*Record, error) {
+	if ip.String() == "192.0.2.255" {
+		return nil, errors.New("lookup failed")
+	}
+	return p[ip.String()], nil
+}
+
+var testProvider = mapProvider{
+	"192.0.2.1": {Country: "DE", Region: "BE", City: "Berlin"},
+	"192.0.2.2": {Country: "FR"},
+	"192.0.2.3": {ASN: 15169, Organization: "Google LLC"},
+}
+
+// go test -run Test_GeoIP
+func Test_GeoIP(t *testing.T) {
+	t.Parallel()
+	app := fiber.New(fiber.Config{
+		ProxyHeader:      fiber.HeaderXForwardedFor,
+		TrustProxy:       true,
+		TrustProxyConfig: fiber.TrustProxyConfig{Proxies: []string{"0.0.0.0"}},
+	})
+	app.Use(New(Config{Provider: testProvider}))
+	app.Get("/", func(c fiber.Ctx) error {
+		record := FromContext(c)
+		if record == nil {
+			return c.SendString("unknown")
+		}
+		return c.SendString(record.Country + " " + record.Region + " " + record.City)
+	})
+
+	testCases := []struct {
+		ip     string
+		body   string
+		status int
+	}{
+		{ip: "192.0.2.1", status: fiber.StatusOK, body: "DE BE Berlin"},
+		{ip: "198.51.100.1", status: fiber.StatusOK, body: "unknown"},
+		{ip: "192.0.2.255", status: fiber.StatusInternalServerError, body: "lookup failed"},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(fiber.HeaderXForwardedFor, tc.ip)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, tc.status, resp.StatusCode, tc.ip)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, tc.body, string(body), tc.ip)
+	}
+}
+
+// go test -run Test_GeoIP_UntrustedProxy
+func Test_GeoIP_UntrustedProxy(t *testing.T) {
+	t.Parallel()
+	app := fiber.New(fiber.Config{
+		ProxyHeader: fiber.HeaderXForwardedFor,
+		TrustProxy:  true,
+	})
+	app.Use(New(Config{Provider: testProvider, DenyCountries: []string{"fr"}}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(c.IP())
+	})
+
+	// The header of an untrusted proxy is ignored
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderXForwardedFor, "192.0.2.2")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "0.0.0.0", string(body))
+}
+
+// go test -run Test_GeoIP_AllowCountries
+func Test_GeoIP_AllowCountries(t *testing.T) {
+	t.Parallel()
+	app := fiber.New(fiber.Config{
+		ProxyHeader:      fiber.HeaderXForwardedFor,
+		TrustProxy:       true,
+		TrustProxyConfig: fiber.TrustProxyConfig{Proxies: []string{"0.0.0.0"}},
+	})
+	app.Use(New(Config{Provider: testProvider, AllowCountries: []string{"de"}}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	testCases := []struct {
+		ip     string
+		status int
+	}{
+		{ip: "192.0.2.1", status: fiber.StatusOK},
+		{ip: "192.0.2.2", status: fiber.StatusForbidden},
+		// Unknown locations are not allowed
+		{ip: "198.51.100.1", status: fiber.StatusForbidden},
+		{ip: "192.0.2.3", status: fiber.StatusForbidden},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(fiber.HeaderXForwardedFor, tc.ip)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, tc.status, resp.StatusCode, tc.ip)
+	}
+}
+
+// go test -run Test_GeoIP_DenyCountries
+func Test_GeoIP_DenyCountries(t *testing.T) {
+	t.Parallel()
+	app := fiber.New(fiber.Config{
+		ProxyHeader:      fiber.HeaderXForwardedFor,
+		TrustProxy:       true,
+		TrustProxyConfig: fiber.TrustProxyConfig{Proxies: []string{"0.0.0.0"}},
+	})
+	app.Use(New(Config{
+		Provider:      testProvider,
+		DenyCountries: []string{"FR"},
+		Forbidden: func(c fiber.Ctx) error {
+			return c.Status(fiber.StatusUnavailableForLegalReasons).SendString("not available in your country")
+		},
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString("available")
+	})
+
+	testCases := []struct {
+		ip     string
+		body   string
+		status int
+	}{
+		{ip: "192.0.2.2", status: fiber.StatusUnavailableForLegalReasons, body: "not available in your country"},
+		{ip: "192.0.2.1", status: fiber.StatusOK, body: "available"},
+		// Unknown locations are allowed
+		{ip: "198.51.100.1", status: fiber.StatusOK, body: "available"},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(fiber.HeaderXForwardedFor, tc.ip)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, tc.status, resp.StatusCode, tc.ip)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, tc.body, string(body), tc.ip)
+	}
+}
+
+// go test -run Test_GeoIP_Providers
+func Test_GeoIP_Providers(t *testing.T) {
+	t.Parallel()
+	asn := mapProvider{
+		"192.0.2.1": {ASN: 3320, Organization: "Deutsche Telekom AG", Country: "XX"},
+		"192.0.2.4": {ASN: 0, Organization: "Unknown"},
+	}
+	provider := Providers(testProvider, asn)
+
+	record, err := provider.Lookup(netip.MustParseAddr("192.0.2.1"))
+	require.NoError(t, err)
+	require.Equal(t, &Record{Country: "DE", Region: "BE", City: "Berlin", ASN: 3320, Organization: "Deutsche Telekom AG"}, record)
+
+	record, err = provider.Lookup(netip.MustParseAddr("192.0.2.4"))
+	require.NoError(t, err)
+	require.Equal(t, &Record{Organization: "Unknown"}, record)
+
+	record, err = provider.Lookup(netip.MustParseAddr("198.51.100.1"))
+	require.NoError(t, err)
+	require.Nil(t, record)
+
+	_, err = provider.Lookup(netip.MustParseAddr("192.0.2.255"))
+	require.Error(t, err)
+}
+
+// go test -run Test_GeoIP_MMDB
+func Test_GeoIP_MMDB(t *testing.T) {
+	t.Parallel()
+	db, err := NewMMDB(testMMDB(t, 6, 28))
+	require.NoError(t, err)
+	app := fiber.New(fiber.Config{
+		ProxyHeader:      fiber.HeaderXForwardedFor,
+		TrustProxy:       true,
+		TrustProxyConfig: fiber.TrustProxyConfig{Proxies: []string{"0.0.0.0"}},
+	})
+	app.Use(New(Config{Provider: db, AllowCountries: []string{"GB"}}))
+	app.Get("/", func(c fiber.Ctx) error {
+		record := FromContext(c)
+		return c.SendString(record.Country + " " + record.Region + " " + record.City)
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderXForwardedFor, "81.2.69.160")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "GB ENG London", string(body))
+
+	req = httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderXForwardedFor, "89.160.20.200")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+// go test -run Test_GeoIP_Next
+func Test_GeoIP_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New(fiber.Config{
+		ProxyHeader:      fiber.HeaderXForwardedFor,
+		TrustProxy:       true,
+		TrustProxyConfig: fiber.TrustProxyConfig{Proxies: []string{"0.0.0.0"}},
+	})
+	app.Use(New(Config{
+		Provider:      testProvider,
+		DenyCountries: []string{"FR"},
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		if FromContext(c) == nil {
+			return c.SendString("unknown")
+		}
+		return c.SendString("known")
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderXForwardedFor, "192.0.2.2")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "unknown", string(body))
+
+	require.Panics(t, func() {
+		New()
+	})
+}
+
+// go test -v -run=^$ -bench=Benchmark_GeoIP -benchmem -count=4
+func Benchmark_GeoIP(b *testing.B) {
+	app := fiber.New()
+	app.Use(New(Config{Provider: testProvider}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	h := app.Handler()
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI("/")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		h(ctx)
+	}
+}
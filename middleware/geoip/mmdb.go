@@ -0,0 +1,369 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+	"os"
+)
+
+// mmdbMetadataMarker starts the metadata section at the end of a MaxMind DB file
+var mmdbMetadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// ErrInvalidDatabase is returned if a database is not in the MaxMind DB format or corrupt.
+var ErrInvalidDatabase = errors.New("geoip: invalid MaxMind DB database")
+
+// maxDecodeDepth limits the nesting of data structures while decoding
+const maxDecodeDepth = 32
+
+// The types of the MaxMind DB data section
+const (
+	mmdbExtended = iota
+	mmdbPointer
+	mmdbString
+	mmdbDouble
+	mmdbBytes
+	mmdbUint16
+	mmdbUint32
+	mmdbMap
+	mmdbInt32
+	mmdbUint64
+	mmdbUint128
+	mmdbArray
+	mmdbContainer
+	mmdbEndMarker
+	mmdbBool
+	mmdbFloat
+)
+
+// MMDB is a Provider which reads a database in the MaxMind DB format, e.g. of the
+// GeoLite2-Country, GeoLite2-City or GeoLite2-ASN databases. The database is held in memory.
+type MMDB struct {
+	// DatabaseType is the type of the database from its metadata, e.g. "GeoLite2-Country"
+	DatabaseType string
+
+	tree       []byte
+	data       []byte
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	ipv4Start  uint
+}
+
+// OpenMMDB reads the MaxMind DB database of the file.
+func OpenMMDB(path string) (*MMDB, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("geoip: failed to read database: %w", err)
+	}
+	return NewMMDB(data)
+}
+
+// NewMMDB reads a MaxMind DB database from the bytes of the file.
+func NewMMDB(data []byte) (*MMDB, error) {
+	start := bytes.LastIndex(data, mmdbMetadataMarker)
+	if start == -1 {
+		return nil, fmt.Errorf("%w: metadata not found", ErrInvalidDatabase)
+	}
+	metadata, err := (&mmdbDecoder{buf: data[start+len(mmdbMetadataMarker):]}).decodeMap(0)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &MMDB{}
+	nodeCount, ok1 := metadata["node_count"].(uint64)
+	recordSize, ok2 := metadata["record_size"].(uint64)
+	ipVersion, ok3 := metadata["ip_version"].(uint64)
+	if !ok1 || !ok2 || !ok3 {
+		return nil, fmt.Errorf("%w: node_count, record_size or ip_version missing", ErrInvalidDatabase)
+	}
+	if recordSize != 24 && recordSize != 28 && recordSize != 32 {
+		return nil, fmt.Errorf("%w: unsupported record size %d", ErrInvalidDatabase, recordSize)
+	}
+	if ipVersion != 4 && ipVersion != 6 {
+		return nil, fmt.Errorf("%w: unsupported ip version %d", ErrInvalidDatabase, ipVersion)
+	}
+	db.DatabaseType, _ = metadata["database_type"].(string) //nolint:errcheck // The type is optional
+	db.nodeCount = uint(nodeCount)
+	db.recordSize = uint(recordSize)
+	db.ipVersion = uint(ipVersion)
+
+	treeSize := db.nodeCount * db.recordSize / 4
+	if treeSize+16 > uint(start) {
+		return nil, fmt.Errorf("%w: search tree exceeds the file", ErrInvalidDatabase)
+	}
+	db.tree = data[:treeSize]
+	db.data = data[treeSize+16 : start]
+
+	// IPv4 addresses are stored in the ::/96 subtree of IPv6 databases
+	if db.ipVersion == 6 {
+		for i := 0; i < 96 && db.ipv4Start < db.nodeCount; i++ {
+			db.ipv4Start = db.record(db.ipv4Start, 0)
+		}
+	}
+	return db, nil
+}
+
+// Lookup returns the record of the IP address, or nil if the database has no record for it.
+func (db *MMDB) Lookup(ip netip.Addr) (*Record, error) {
+	value, err := db.lookup(ip)
+	if value == nil || err != nil {
+		return nil, err
+	}
+
+	record := &Record{}
+	record.Country = nestedString(value, "country", "iso_code")
+	if record.Country == "" {
+		record.Country = nestedString(value, "registered_country", "iso_code")
+	}
+	if subdivisions, ok := value["subdivisions"].([]any); ok && len(subdivisions) > 0 {
+		if subdivision, ok := subdivisions[0].(map[string]any); ok {
+			record.Region = nestedString(subdivision, "iso_code")
+		}
+	}
+	record.City = nestedString(value, "city", "names", "en")
+	if asn, ok := value["autonomous_system_number"].(uint64); ok {
+		record.ASN = uint(asn)
+	}
+	record.Organization = nestedString(value, "autonomous_system_organization")
+	return record, nil
+}
+
+// lookup returns the decoded data of the IP address
+func (db *MMDB) lookup(ip netip.Addr) (map[string]any, error) {
+	ip = ip.Unmap()
+	node := uint(0)
+	if ip.Is4() && db.ipVersion == 6 {
+		node = db.ipv4Start
+	} else if ip.Is6() && db.ipVersion == 4 {
+		return nil, nil
+	}
+
+	addr := ip.AsSlice()
+	for i := 0; i < len(addr)*8 && node < db.nodeCount; i++ {
+		node = db.record(node, uint(addr[i/8]>>(7-i%8))&1)
+	}
+	switch {
+	case node == db.nodeCount:
+		return nil, nil
+	case node < db.nodeCount:
+		return nil, fmt.Errorf("%w: search tree too deep", ErrInvalidDatabase)
+	}
+	value, _, err := (&mmdbDecoder{buf: db.data}).decode(node-db.nodeCount-16, 0)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: record is not a map", ErrInvalidDatabase)
+	}
+	return m, nil
+}
+
+// record returns the left (bit 0) or right (bit 1) record of the node
+func (db *MMDB) record(node, bit uint) uint {
+	b := db.tree[node*db.recordSize/4:]
+	switch db.recordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		return uint(binary.BigEndian.Uint32(b[bit*4:]))
+	}
+}
+
+// nestedString returns the string at the path of nested maps
+func nestedString(m map[string]any, path ...string) string {
+	for _, key := range path[:len(path)-1] {
+		var ok bool
+		if m, ok = m[key].(map[string]any); !ok {
+			return ""
+		}
+	}
+	s, _ := m[path[len(path)-1]].(string) //nolint:errcheck // Missing values are empty
+	return s
+}
+
+// mmdbDecoder decodes values of the data section, all offsets are relative to buf
+type mmdbDecoder struct {
+	buf []byte
+}
+
+// decode decodes the value at the offset and returns the offset after it
+func (d *mmdbDecoder) decode(offset uint, depth int) (any, uint, error) {
+	if depth > maxDecodeDepth {
+		return nil, 0, fmt.Errorf("%w: data nested too deep", ErrInvalidDatabase)
+	}
+	typ, size, offset, err := d.control(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if typ == mmdbPointer {
+		pointer, next, err := d.pointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+
+	switch typ {
+	case mmdbMap:
+		m := make(map[string]any, size)
+		for i := uint(0); i < size; i++ {
+			var key, value any
+			if key, offset, err = d.decode(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+			k, ok := key.(string)
+			if !ok {
+				return nil, 0, fmt.Errorf("%w: map key is not a string", ErrInvalidDatabase)
+			}
+			if value, offset, err = d.decode(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+			m[k] = value
+		}
+		return m, offset, nil
+	case mmdbArray:
+		a := make([]any, 0, min(size, 1024))
+		for i := uint(0); i < size; i++ {
+			var value any
+			if value, offset, err = d.decode(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+			a = append(a, value)
+		}
+		return a, offset, nil
+	case mmdbBool:
+		return size != 0, offset, nil
+	}
+
+	if offset+size > uint(len(d.buf)) {
+		return nil, 0, fmt.Errorf("%w: value exceeds the data section", ErrInvalidDatabase)
+	}
+	b := d.buf[offset : offset+size]
+	next := offset + size
+	switch typ {
+	case mmdbString:
+		return string(b), next, nil
+	case mmdbBytes, mmdbUint128:
+		return append([]byte(nil), b...), next, nil
+	case mmdbDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("%w: invalid double size %d", ErrInvalidDatabase, size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), next, nil
+	case mmdbFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("%w: invalid float size %d", ErrInvalidDatabase, size)
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(b)), next, nil
+	case mmdbUint16, mmdbUint32, mmdbUint64:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("%w: invalid integer size %d", ErrInvalidDatabase, size)
+		}
+		var n uint64
+		for _, c := range b {
+			n = n<<8 | uint64(c)
+		}
+		return n, next, nil
+	case mmdbInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("%w: invalid integer size %d", ErrInvalidDatabase, size)
+		}
+		var n uint32
+		for _, c := range b {
+			n = n<<8 | uint32(c)
+		}
+		return int64(int32(n)), next, nil //nolint:gosec // The value is a signed 32-bit integer
+	default:
+		return nil, 0, fmt.Errorf("%w: unsupported data type %d", ErrInvalidDatabase, typ)
+	}
+}
+
+// control decodes the control byte at the offset and returns the type, size and offset of the value
+func (d *mmdbDecoder) control(offset uint) (typ, size, next uint, err error) {
+	if offset >= uint(len(d.buf)) {
+		return 0, 0, 0, fmt.Errorf("%w: offset exceeds the data section", ErrInvalidDatabase)
+	}
+	ctrl := d.buf[offset]
+	offset++
+	typ = uint(ctrl >> 5)
+	if typ == mmdbPointer {
+		// The size bits of pointers are decoded by pointer
+		return typ, uint(ctrl & 0x1F), offset, nil
+	}
+	if typ == mmdbExtended {
+		if offset >= uint(len(d.buf)) {
+			return 0, 0, 0, fmt.Errorf("%w: offset exceeds the data section", ErrInvalidDatabase)
+		}
+		typ = 7 + uint(d.buf[offset])
+		offset++
+	}
+
+	size = uint(ctrl & 0x1F)
+	if size >= 29 {
+		n := size - 28
+		if offset+n > uint(len(d.buf)) {
+			return 0, 0, 0, fmt.Errorf("%w: offset exceeds the data section", ErrInvalidDatabase)
+		}
+		var extra uint
+		for _, c := range d.buf[offset : offset+n] {
+			extra = extra<<8 | uint(c)
+		}
+		offset += n
+		switch size {
+		case 29:
+			size = 29 + extra
+		case 30:
+			size = 285 + extra
+		default:
+			size = 65821 + extra
+		}
+	}
+	return typ, size, offset, nil
+}
+
+// pointer decodes a pointer with the size bits of its control byte and returns its target
+func (d *mmdbDecoder) pointer(bits, offset uint) (pointer, next uint, err error) {
+	n := bits>>3 + 1
+	if offset+n > uint(len(d.buf)) {
+		return 0, 0, fmt.Errorf("%w: offset exceeds the data section", ErrInvalidDatabase)
+	}
+	if n < 4 {
+		pointer = bits & 0x7
+	}
+	for _, c := range d.buf[offset : offset+n] {
+		pointer = pointer<<8 | uint(c)
+	}
+	switch n {
+	case 2:
+		pointer += 2048
+	case 3:
+		pointer += 526336
+	}
+	return pointer, offset + n, nil
+}
+
+// decodeMap decodes the map at the offset
+func (d *mmdbDecoder) decodeMap(offset uint) (map[string]any, error) {
+	value, _, err := d.decode(offset, 0)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: metadata is not a map", ErrInvalidDatabase)
+	}
+	return m, nil
+}
@@ -0,0 +1,340 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mmdbWriter writes databases in the MaxMind DB format for tests
+type mmdbWriter struct {
+	keys       map[string]int // offsets of the map keys for pointers
+	nodes      [][2]int       // children: > 0 node, 0 empty, < 0 data offset - 1
+	data       []byte
+	ipVersion  int
+	recordSize int
+}
+
+func newMMDBWriter(ipVersion, recordSize int) *mmdbWriter {
+	return &mmdbWriter{keys: make(map[string]int), nodes: make([][2]int, 1), ipVersion: ipVersion, recordSize: recordSize}
+}
+
+// insert adds the value for the network
+func (w *mmdbWriter) insert(t testing.TB, network string, value map[string]any) {
+	t.Helper()
+	prefix := netip.MustParsePrefix(network)
+	addr := prefix.Addr().AsSlice()
+	bits := prefix.Bits()
+	if prefix.Addr().Is4() && w.ipVersion == 6 {
+		addr = append(make([]byte, 12), addr...)
+		bits += 96
+	}
+	offset := len(w.data)
+	w.data = w.encode(w.data, value)
+
+	node := 0
+	for i := 0; i < bits; i++ {
+		bit := int(addr[i/8]>>(7-i%8)) & 1
+		if i == bits-1 {
+			w.nodes[node][bit] = -offset - 1
+			break
+		}
+		if w.nodes[node][bit] <= 0 {
+			w.nodes = append(w.nodes, [2]int{})
+			w.nodes[node][bit] = len(w.nodes) - 1
+		}
+		node = w.nodes[node][bit]
+	}
+}
+
+// bytes returns the database file
+func (w *mmdbWriter) bytes() []byte {
+	nodeCount := len(w.nodes)
+	record := func(child int) uint32 {
+		switch {
+		case child > 0:
+			return uint32(child)
+		case child == 0:
+			return uint32(nodeCount)
+		default:
+			return uint32(nodeCount + 16 - child - 1)
+		}
+	}
+
+	var file []byte
+	for _, node := range w.nodes {
+		left, right := record(node[0]), record(node[1])
+		switch w.recordSize {
+		case 24:
+			file = append(file, byte(left>>16), byte(left>>8), byte(left), byte(right>>16), byte(right>>8), byte(right))
+		case 28:
+			file = append(file, byte(left>>16), byte(left>>8), byte(left),
+				byte(left>>20&0xF0)|byte(right>>24&0x0F), byte(right>>16), byte(right>>8), byte(right))
+		default:
+			file = binary.BigEndian.AppendUint32(file, left)
+			file = binary.BigEndian.AppendUint32(file, right)
+		}
+	}
+	file = append(file, make([]byte, 16)...)
+	file = append(file, w.data...)
+	file = append(file, mmdbMetadataMarker...)
+	metadata := (&mmdbWriter{keys: make(map[string]int)}).encode(nil, map[string]any{
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 uint16(w.recordSize),
+		"ip_version":                  uint16(w.ipVersion),
+		"database_type":               "Test",
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"languages":                   []any{"en"},
+	})
+	return append(file, metadata...)
+}
+
+// encode appends the encoded value to b, map keys are written once and referenced by pointers
+func (w *mmdbWriter) encode(b []byte, value any) []byte {
+	switch v := value.(type) {
+	case string:
+		b = mmdbControl(b, mmdbString, len(v))
+		return append(b, v...)
+	case uint16:
+		return mmdbUint(b, mmdbUint16, uint64(v))
+	case uint32:
+		return mmdbUint(b, mmdbUint32, uint64(v))
+	case uint64:
+		return mmdbUint(b, mmdbUint64, v)
+	case int32:
+		b = mmdbControl(b, mmdbInt32, 4)
+		return binary.BigEndian.AppendUint32(b, uint32(v))
+	case float64:
+		b = mmdbControl(b, mmdbDouble, 8)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(v))
+	case float32:
+		b = mmdbControl(b, mmdbFloat, 4)
+		return binary.BigEndian.AppendUint32(b, math.Float32bits(v))
+	case bool:
+		size := 0
+		if v {
+			size = 1
+		}
+		return mmdbControl(b, mmdbBool, size)
+	case []byte:
+		b = mmdbControl(b, mmdbBytes, len(v))
+		return append(b, v...)
+	case []any:
+		b = mmdbControl(b, mmdbArray, len(v))
+		for _, e := range v {
+			b = w.encode(b, e)
+		}
+		return b
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b = mmdbControl(b, mmdbMap, len(v))
+		for _, k := range keys {
+			if offset, ok := w.keys[k]; ok && offset < 2048 {
+				b = append(b, mmdbPointer<<5|byte(offset>>8), byte(offset))
+			} else {
+				w.keys[k] = len(b)
+				b = w.encode(b, k)
+			}
+			b = w.encode(b, v[k])
+		}
+		return b
+	default:
+		panic("unsupported type")
+	}
+}
+
+func mmdbControl(b []byte, typ, size int) []byte {
+	ctrl := byte(typ << 5)
+	if typ > 7 {
+		ctrl = 0
+	}
+	var extra []byte
+	switch {
+	case size < 29:
+		ctrl |= byte(size)
+	case size < 285:
+		ctrl |= 29
+		extra = []byte{byte(size - 29)}
+	case size < 65821:
+		ctrl |= 30
+		extra = []byte{byte((size - 285) >> 8), byte(size - 285)}
+	default:
+		ctrl |= 31
+		extra = []byte{byte((size - 65821) >> 16), byte((size - 65821) >> 8), byte(size - 65821)}
+	}
+	b = append(b, ctrl)
+	if typ > 7 {
+		b = append(b, byte(typ-7))
+	}
+	return append(b, extra...)
+}
+
+func mmdbUint(b []byte, typ int, v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	n := 0
+	for n < 8 && buf[n] == 0 {
+		n++
+	}
+	b = mmdbControl(b, typ, 8-n)
+	return append(b, buf[n:]...)
+}
+
+// testMMDB returns a database with the GeoLite2 structure of the test networks
+func testMMDB(t testing.TB, ipVersion, recordSize int) []byte {
+	t.Helper()
+	w := newMMDBWriter(ipVersion, recordSize)
+	w.insert(t, "81.2.69.0/24", map[string]any{
+		"city":         map[string]any{"geoname_id": uint32(2643743), "names": map[string]any{"en": "London", "de": "London"}},
+		"country":      map[string]any{"iso_code": "GB", "names": map[string]any{"en": "United Kingdom"}},
+		"location":     map[string]any{"latitude": 51.5142, "longitude": -0.0931, "accuracy_radius": uint16(10)},
+		"subdivisions": []any{map[string]any{"iso_code": "ENG"}},
+	})
+	w.insert(t, "89.160.20.128/25", map[string]any{
+		"country":                        map[string]any{"iso_code": "SE", "is_in_european_union": true},
+		"autonomous_system_number":       uint32(29518),
+		"autonomous_system_organization": "Bredband2 AB",
+	})
+	w.insert(t, "1.0.0.0/24", map[string]any{
+		"registered_country": map[string]any{"iso_code": "AU"},
+		"metro_code":         int32(-1),
+		"bytes":              []byte{1, 2},
+		"population":         uint64(1 << 40),
+		"ratio":              float32(0.5),
+		"note":               strings.Repeat("a", 300),
+	})
+	if ipVersion == 6 {
+		w.insert(t, "2001:218::/32", map[string]any{
+			"country": map[string]any{"iso_code": "JP"},
+		})
+	}
+	return w.bytes()
+}
+
+// go test -run Test_MMDB
+func Test_MMDB(t *testing.T) {
+	t.Parallel()
+	for _, ipVersion := range []int{4, 6} {
+		for _, recordSize := range []int{24, 28, 32} {
+			db, err := NewMMDB(testMMDB(t, ipVersion, recordSize))
+			require.NoError(t, err)
+			require.Equal(t, "Test", db.DatabaseType)
+
+			tests := []struct {
+				expected *Record
+				ip       string
+			}{
+				{ip: "81.2.69.160", expected: &Record{Country: "GB", Region: "ENG", City: "London"}},
+				{ip: "::ffff:81.2.69.1", expected: &Record{Country: "GB", Region: "ENG", City: "London"}},
+				{ip: "89.160.20.200", expected: &Record{Country: "SE", ASN: 29518, Organization: "Bredband2 AB"}},
+				{ip: "89.160.20.100", expected: nil},
+				{ip: "1.0.0.1", expected: &Record{Country: "AU"}},
+				{ip: "127.0.0.1", expected: nil},
+				{ip: "2001:218::1", expected: nil},
+			}
+			if ipVersion == 6 {
+				tests[len(tests)-1].expected = &Record{Country: "JP"}
+				tests = append(tests, struct {
+					expected *Record
+					ip       string
+				}{ip: "2001:db8::1", expected: nil})
+			}
+			for _, tt := range tests {
+				record, err := db.Lookup(netip.MustParseAddr(tt.ip))
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, record, "%s in IPv%d database with %d bit records", tt.ip, ipVersion, recordSize)
+			}
+		}
+	}
+}
+
+// go test -run Test_MMDB_Values
+func Test_MMDB_Values(t *testing.T) {
+	t.Parallel()
+	db, err := NewMMDB(testMMDB(t, 6, 28))
+	require.NoError(t, err)
+
+	value, err := db.lookup(netip.MustParseAddr("1.0.0.1"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"registered_country": map[string]any{"iso_code": "AU"},
+		"metro_code":         int64(-1),
+		"bytes":              []byte{1, 2},
+		"population":         uint64(1 << 40),
+		"ratio":              float32(0.5),
+		"note":               strings.Repeat("a", 300),
+	}, value)
+
+	value, err = db.lookup(netip.MustParseAddr("81.2.69.160"))
+	require.NoError(t, err)
+	require.InDelta(t, 51.5142, value["location"].(map[string]any)["latitude"], 0) //nolint:forcetypeassert // Fails the test otherwise
+	require.NotNil(t, value["country"])
+}
+
+// go test -run Test_MMDB_Invalid
+func Test_MMDB_Invalid(t *testing.T) {
+	t.Parallel()
+	_, err := NewMMDB([]byte("not a database"))
+	require.ErrorIs(t, err, ErrInvalidDatabase)
+
+	// The metadata is missing the search tree
+	metadata := (&mmdbWriter{keys: make(map[string]int)}).encode(nil, map[string]any{"database_type": "Test"})
+	_, err = NewMMDB(append(append([]byte(nil), mmdbMetadataMarker...), metadata...))
+	require.ErrorIs(t, err, ErrInvalidDatabase)
+
+	// The search tree exceeds the file
+	data := testMMDB(t, 4, 24)
+	_, err = NewMMDB(data[bytes.LastIndex(data, mmdbMetadataMarker)-100:])
+	require.ErrorIs(t, err, ErrInvalidDatabase)
+
+	// The data section is truncated
+	data = testMMDB(t, 4, 24)
+	db, err := NewMMDB(data)
+	require.NoError(t, err)
+	db.data = db.data[:10]
+	_, err = db.Lookup(netip.MustParseAddr("89.160.20.200"))
+	require.ErrorIs(t, err, ErrInvalidDatabase)
+}
+
+// go test -run Test_OpenMMDB
+func Test_OpenMMDB(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "test.mmdb")
+	require.NoError(t, os.WriteFile(path, testMMDB(t, 6, 24), 0o600))
+
+	db, err := OpenMMDB(path)
+	require.NoError(t, err)
+	record, err := db.Lookup(netip.MustParseAddr("81.2.69.160"))
+	require.NoError(t, err)
+	require.Equal(t, "GB", record.Country)
+
+	_, err = OpenMMDB(filepath.Join(t.TempDir(), "missing.mmdb"))
+	require.Error(t, err)
+}
+
+// go test -v -run=^$ -bench=Benchmark_MMDB_Lookup -benchmem -count=4
+func Benchmark_MMDB_Lookup(b *testing.B) {
+	db, err := NewMMDB(testMMDB(b, 6, 24))
+	require.NoError(b, err)
+	ip := netip.MustParseAddr("81.2.69.160")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_, err = db.Lookup(ip)
+	}
+	require.NoError(b, err)
+}
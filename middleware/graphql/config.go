@@ -0,0 +1,70 @@
+package graphql
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/internal/storage/memory"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Storage stores the persisted queries by their hash.
+	//
+	// Optional. Default: memory.New()
+	Storage fiber.Storage
+
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Execute executes the GraphQL request, e.g. with graphql-go or gqlgen. The result
+	// is sent as JSON, so it should have the fields "data" and "errors".
+	//
+	// Required.
+	Execute func(c fiber.Ctx, req *Request) any
+
+	// MaxBatchSize is the maximum number of operations of a batch request, which is
+	// a JSON array of requests whose results are sent as an array.
+	//
+	// Optional. Default: 0 (batch requests are rejected)
+	MaxBatchSize int
+
+	// GraphiQL serves the GraphiQL IDE for browser requests without a query.
+	//
+	// Optional. Default: false
+	GraphiQL bool
+
+	// PersistedQueries enables automatic persisted queries, the client sends the
+	// SHA-256 hash of a query instead of the query once the query was persisted.
+	//
+	// Optional. Default: false
+	PersistedQueries bool
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:             nil,
+	GraphiQL:         false,
+	PersistedQueries: false,
+	MaxBatchSize:     0,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.PersistedQueries && cfg.Storage == nil {
+		cfg.Storage = memory.New()
+	}
+	if cfg.MaxBatchSize < 0 {
+		cfg.MaxBatchSize = ConfigDefault.MaxBatchSize
+	}
+	return cfg
+}
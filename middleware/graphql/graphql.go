@@ -0,0 +1,295 @@
+// Package graphql provides a handler which serves a GraphQL server over HTTP. It
+// parses GET and POST requests, multipart file uploads, batch requests and automatic
+// persisted queries, and passes them to the executor of a GraphQL library.
+package graphql
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Request is a GraphQL request
+type Request struct {
+	// Variables are the values of the variables of the operation. Uploaded files are
+	// set as *multipart.FileHeader values.
+	Variables map[string]any `json:"variables"`
+
+	// Extensions are the extensions of the request, e.g. "persistedQuery"
+	Extensions map[string]any `json:"extensions"`
+
+	// Query is the GraphQL document
+	Query string `json:"query"`
+
+	// OperationName selects the operation of the document which is executed
+	OperationName string `json:"operationName"`
+}
+
+// Error is a GraphQL error
+type Error struct {
+	Extensions map[string]any `json:"extensions,omitempty"`
+	Message    string         `json:"message"`
+}
+
+// errorResponse is the response of a request which can't be executed
+type errorResponse struct {
+	Errors []Error `json:"errors"`
+}
+
+// requestError is an error of a request, which is sent with its HTTP status code
+type requestError struct {
+	err    Error
+	status int
+}
+
+func (e *requestError) Error() string {
+	return e.err.Message
+}
+
+// badRequest returns a requestError with the status code 400
+func badRequest(format string, args ...any) *requestError {
+	return &requestError{status: fiber.StatusBadRequest, err: Error{Message: fmt.Sprintf(format, args...)}}
+}
+
+// New creates a new handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	if cfg.Execute == nil {
+		panic("graphql: Execute is required")
+	}
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		var (
+			reqs  []*Request
+			batch bool
+			err   error
+		)
+		switch c.Method() {
+		case fiber.MethodGet:
+			if cfg.GraphiQL && c.Query("query") == "" && c.Query("extensions") == "" &&
+				c.Accepts(fiber.MIMEApplicationJSON, fiber.MIMETextHTML) == fiber.MIMETextHTML {
+				c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+				return c.SendString(graphiQLPage)
+			}
+			reqs, err = parseGet(c)
+		case fiber.MethodPost:
+			reqs, batch, err = cfg.parsePost(c)
+		default:
+			c.Set(fiber.HeaderAllow, fiber.MethodGet+", "+fiber.MethodPost)
+			err = &requestError{status: fiber.StatusMethodNotAllowed, err: Error{Message: "GraphQL only supports GET and POST requests"}}
+		}
+		if err != nil {
+			return sendError(c, err)
+		}
+
+		results := make([]any, len(reqs))
+		for i, req := range reqs {
+			if err := cfg.prepare(c, req); err != nil {
+				if !batch {
+					return sendError(c, err)
+				}
+				results[i] = errorResult(err)
+				continue
+			}
+			results[i] = cfg.Execute(c, req)
+		}
+
+		if batch {
+			return c.JSON(results)
+		}
+		return c.JSON(results[0])
+	}
+}
+
+// prepare resolves the persisted query of the request and validates it
+func (cfg *Config) prepare(c fiber.Ctx, req *Request) error {
+	if err := cfg.persistedQuery(req); err != nil {
+		return err
+	}
+	if req.Query == "" {
+		return badRequest("must provide a query")
+	}
+	if c.Method() == fiber.MethodGet {
+		// GET requests must not have side effects
+		if typ := operationType(req.Query, req.OperationName); typ != "" && typ != "query" {
+			c.Set(fiber.HeaderAllow, fiber.MethodPost)
+			return &requestError{
+				status: fiber.StatusMethodNotAllowed,
+				err:    Error{Message: "can only perform a " + typ + " operation from a POST request"},
+			}
+		}
+	}
+	return nil
+}
+
+// parseGet parses the request from the query parameters
+func parseGet(c fiber.Ctx) ([]*Request, error) {
+	req := &Request{
+		Query:         c.Query("query"),
+		OperationName: c.Query("operationName"),
+	}
+	decode := c.App().Config().JSONDecoder
+	if variables := c.Query("variables"); variables != "" {
+		if err := decode([]byte(variables), &req.Variables); err != nil {
+			return nil, badRequest("invalid variables: %v", err)
+		}
+	}
+	if extensions := c.Query("extensions"); extensions != "" {
+		if err := decode([]byte(extensions), &req.Extensions); err != nil {
+			return nil, badRequest("invalid extensions: %v", err)
+		}
+	}
+	return []*Request{req}, nil
+}
+
+// parsePost parses the request from the body, it reports whether the body is a batch request
+func (cfg *Config) parsePost(c fiber.Ctx) ([]*Request, bool, error) {
+	switch {
+	case strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON):
+		return cfg.decodeOperations(c, c.Body())
+	case strings.HasPrefix(c.Get(fiber.HeaderContentType), "application/graphql"):
+		return []*Request{{Query: string(c.Body()), OperationName: c.Query("operationName")}}, false, nil
+	case strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm):
+		return cfg.parseMultipart(c)
+	default:
+		return nil, false, &requestError{
+			status: fiber.StatusUnsupportedMediaType,
+			err:    Error{Message: "unsupported content type " + strconv.Quote(c.Get(fiber.HeaderContentType))},
+		}
+	}
+}
+
+// decodeOperations decodes a request or a batch of requests
+func (cfg *Config) decodeOperations(c fiber.Ctx, data []byte) ([]*Request, bool, error) {
+	decode := c.App().Config().JSONDecoder
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '[' {
+		var reqs []*Request
+		if err := decode(data, &reqs); err != nil {
+			return nil, true, badRequest("invalid request body: %v", err)
+		}
+		if len(reqs) == 0 || len(reqs) > cfg.MaxBatchSize {
+			return nil, true, badRequest("batch requests must have 1 to %d operations", cfg.MaxBatchSize)
+		}
+		for i, req := range reqs {
+			if req == nil {
+				return nil, true, badRequest("operation %d of the batch is null", i)
+			}
+		}
+		return reqs, true, nil
+	}
+
+	req := new(Request)
+	if err := decode(data, req); err != nil {
+		return nil, false, badRequest("invalid request body: %v", err)
+	}
+	return []*Request{req}, false, nil
+}
+
+// parseMultipart parses a request with file uploads, see
+// https://github.com/jaydenseric/graphql-multipart-request-spec
+func (cfg *Config) parseMultipart(c fiber.Ctx) ([]*Request, bool, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, false, badRequest("invalid multipart form: %v", err)
+	}
+	if len(form.Value["operations"]) == 0 {
+		return nil, false, badRequest("missing multipart field operations")
+	}
+	reqs, batch, err := cfg.decodeOperations(c, []byte(form.Value["operations"][0]))
+	if err != nil {
+		return nil, batch, err
+	}
+
+	if len(form.Value["map"]) == 0 {
+		return reqs, batch, nil
+	}
+	var files map[string][]string
+	if err := c.App().Config().JSONDecoder([]byte(form.Value["map"][0]), &files); err != nil {
+		return nil, batch, badRequest("invalid multipart field map: %v", err)
+	}
+	for key, paths := range files {
+		if len(form.File[key]) == 0 {
+			return nil, batch, badRequest("missing file %q of the map", key)
+		}
+		for _, path := range paths {
+			if err := setUpload(reqs, batch, path, form.File[key][0]); err != nil {
+				return nil, batch, badRequest("invalid path %q of file %q: %v", path, key, err)
+			}
+		}
+	}
+	return reqs, batch, nil
+}
+
+// setUpload sets the variable with the object path, e.g. "variables.files.0", to the file
+func setUpload(reqs []*Request, batch bool, path string, file *multipart.FileHeader) error {
+	segments := strings.Split(path, ".")
+	req := reqs[0]
+	if batch {
+		i, err := strconv.Atoi(segments[0])
+		if err != nil || i < 0 || i >= len(reqs) {
+			return errors.New("no such operation")
+		}
+		req, segments = reqs[i], segments[1:]
+	}
+	if len(segments) < 2 || segments[0] != "variables" {
+		return errors.New("files must be variables")
+	}
+
+	var parent any = req.Variables
+	for i, segment := range segments[1:] {
+		last := i == len(segments)-2
+		switch p := parent.(type) {
+		case map[string]any:
+			if last {
+				p[segment] = file
+				return nil
+			}
+			parent = p[segment]
+		case []any:
+			j, err := strconv.Atoi(segment)
+			if err != nil || j < 0 || j >= len(p) {
+				return errors.New("no such element")
+			}
+			if last {
+				p[j] = file
+				return nil
+			}
+			parent = p[j]
+		default:
+			return errors.New("no such variable")
+		}
+	}
+	return nil
+}
+
+// errorResult returns the result of a request which can't be executed
+func errorResult(err error) errorResponse {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return errorResponse{Errors: []Error{reqErr.err}}
+	}
+	return errorResponse{Errors: []Error{{Message: err.Error()}}}
+}
+
+// sendError sends the error of a request which can't be executed
+func sendError(c fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		status = reqErr.status
+	}
+	return c.Status(status).JSON(errorResult(err))
+}
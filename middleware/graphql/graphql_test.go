@@ -0,0 +1,309 @@
+package graphql
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// echo is an executor which returns the request as data
+func echo(_ fiber.Ctx, req *Request) any {
+	variables := req.Variables
+	if variables == nil {
+		variables = map[string]any{}
+	}
+	return fiber.Map{"data": fiber.Map{
+		"query":         req.Query,
+		"operationName": req.OperationName,
+		"variables":     variables,
+	}}
+}
+
+func jsonRequest(body string) *http.Request {
+	req := httptest.NewRequest(fiber.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return req
+}
+
+// go test -run Test_GraphQL
+func Test_GraphQL(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.All("/graphql", New(Config{Execute: echo}))
+
+	target := "/graphql?query=" + url.QueryEscape("{ user { name } }") + "&variables=" + url.QueryEscape(`{"id":"2"}`)
+	graphqlReq := httptest.NewRequest(fiber.MethodPost, "/graphql?operationName=Q", strings.NewReader("query Q { a }"))
+	graphqlReq.Header.Set(fiber.HeaderContentType, "application/graphql")
+
+	testCases := []struct {
+		req  *http.Request
+		body string
+	}{
+		{
+			req:  jsonRequest(`{"query":"query User($id: ID!) { user(id: $id) { name } }","operationName":"User","variables":{"id":"1"}}`),
+			body: `{"data":{"query":"query User($id: ID!) { user(id: $id) { name } }","operationName":"User","variables":{"id":"1"}}}`,
+		},
+		{
+			req:  httptest.NewRequest(fiber.MethodGet, target, nil),
+			body: `{"data":{"query":"{ user { name } }","operationName":"","variables":{"id":"2"}}}`,
+		},
+		{
+			req:  graphqlReq,
+			body: `{"data":{"query":"query Q { a }","operationName":"Q","variables":{}}}`,
+		},
+	}
+	for _, tc := range testCases {
+		resp, err := app.Test(tc.req)
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, tc.body, string(body))
+	}
+}
+
+// go test -run Test_GraphQL_Errors
+func Test_GraphQL_Errors(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.All("/graphql", New(Config{Execute: echo, GraphiQL: true}))
+
+	formReq := httptest.NewRequest(fiber.MethodPost, "/graphql", strings.NewReader("query=x"))
+	formReq.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+
+	testCases := []struct {
+		req    *http.Request
+		body   string
+		status int
+	}{
+		// Mutations must not be sent with GET requests
+		{
+			req:    httptest.NewRequest(fiber.MethodGet, "/graphql?query="+url.QueryEscape("mutation { deleteUser(id: 1) }"), nil),
+			status: fiber.StatusMethodNotAllowed,
+			body:   `{"errors":[{"message":"can only perform a mutation operation from a POST request"}]}`,
+		},
+		{
+			req:    jsonRequest(`{"variables":{}}`),
+			status: fiber.StatusBadRequest,
+			body:   `{"errors":[{"message":"must provide a query"}]}`,
+		},
+		{req: jsonRequest(`{"query":`), status: fiber.StatusBadRequest},
+		// Batch requests are disabled by default
+		{req: jsonRequest(`[{"query":"{ a }"}]`), status: fiber.StatusBadRequest},
+		{req: formReq, status: fiber.StatusUnsupportedMediaType},
+		// Persisted queries are disabled by default
+		{
+			req:    jsonRequest(`{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"abc"}}}`),
+			status: fiber.StatusOK,
+			body:   `{"errors":[{"message":"PersistedQueryNotSupported","extensions":{"code":"PERSISTED_QUERY_NOT_SUPPORTED"}}]}`,
+		},
+	}
+	for _, tc := range testCases {
+		resp, err := app.Test(tc.req)
+		require.NoError(t, err)
+		require.Equal(t, tc.status, resp.StatusCode)
+		if tc.body != "" {
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.body, string(body))
+		}
+	}
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPut, "/graphql", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusMethodNotAllowed, resp.StatusCode)
+	require.Equal(t, "GET, POST", resp.Header.Get(fiber.HeaderAllow))
+}
+
+// go test -run Test_GraphQL_Batch
+func Test_GraphQL_Batch(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.All("/graphql", New(Config{Execute: echo, MaxBatchSize: 2}))
+
+	resp, err := app.Test(jsonRequest(`[{"query":"{ a }"},{"variables":{}}]`))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"data":{"query":"{ a }","operationName":"","variables":{}}},{"errors":[{"message":"must provide a query"}]}]`, string(body))
+
+	resp, err = app.Test(jsonRequest(`[{"query":"{ a }"},{"query":"{ b }"},{"query":"{ c }"}]`))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// go test -run Test_GraphQL_Upload
+func Test_GraphQL_Upload(t *testing.T) {
+	t.Parallel()
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	require.NoError(t, w.WriteField("operations", `{"query":"mutation($file: Upload!, $files: [Upload!]!) { upload(file: $file, files: $files) }","variables":{"file":null,"files":[null]}}`))
+	require.NoError(t, w.WriteField("map", `{"0":["variables.file"],"1":["variables.files.0"]}`))
+	for key, name := range map[string]string{"0": "a.txt", "1": "b.txt"} {
+		part, err := w.CreateFormFile(key, name)
+		require.NoError(t, err)
+		_, err = part.Write([]byte("content of " + name))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	var files []any
+	app := fiber.New()
+	app.All("/graphql", New(Config{Execute: func(_ fiber.Ctx, req *Request) any {
+		files = append(files, req.Variables["file"], req.Variables["files"].([]any)[0]) //nolint:forcetypeassert // The variable is a list
+		return fiber.Map{"data": nil}
+	}}))
+	req := httptest.NewRequest(fiber.MethodPost, "/graphql", body)
+	req.Header.Set(fiber.HeaderContentType, w.FormDataContentType())
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Len(t, files, 2)
+	require.Equal(t, "a.txt", files[0].(*multipart.FileHeader).Filename) //nolint:forcetypeassert // The files are set by the handler
+	require.Equal(t, "b.txt", files[1].(*multipart.FileHeader).Filename) //nolint:forcetypeassert // The files are set by the handler
+
+	// The map refers to a variable which doesn't exist
+	body.Reset()
+	w = multipart.NewWriter(body)
+	require.NoError(t, w.WriteField("operations", `{"query":"mutation { a }","variables":{}}`))
+	require.NoError(t, w.WriteField("map", `{"0":["variables.files.0"]}`))
+	part, err := w.CreateFormFile("0", "a.txt")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("a"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	req = httptest.NewRequest(fiber.MethodPost, "/graphql", body)
+	req.Header.Set(fiber.HeaderContentType, w.FormDataContentType())
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// go test -run Test_GraphQL_PersistedQueries
+func Test_GraphQL_PersistedQueries(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.All("/graphql", New(Config{Execute: echo, PersistedQueries: true}))
+
+	query := "{ user { name } }"
+	sum := sha256.Sum256([]byte(query))
+	hash := hex.EncodeToString(sum[:])
+	target := "/graphql?extensions=" + url.QueryEscape(`{"persistedQuery":{"version":1,"sha256Hash":"`+hash+`"}}`)
+
+	testCases := []struct {
+		target string
+		body   string
+		status int
+	}{
+		// The query isn't persisted yet
+		{target: target, status: fiber.StatusOK, body: `{"errors":[{"message":"PersistedQueryNotFound","extensions":{"code":"PERSISTED_QUERY_NOT_FOUND"}}]}`},
+		// The hash must match the query
+		{target: target + "&query=" + url.QueryEscape("{ other }"), status: fiber.StatusBadRequest},
+		{target: target + "&query=" + url.QueryEscape(query), status: fiber.StatusOK},
+		{target: target, status: fiber.StatusOK, body: `{"data":{"query":"{ user { name } }","operationName":"","variables":{}}}`},
+	}
+	for _, tc := range testCases {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tc.target, nil))
+		require.NoError(t, err)
+		require.Equal(t, tc.status, resp.StatusCode, tc.target)
+		if tc.body != "" {
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.body, string(body), tc.target)
+		}
+	}
+}
+
+// go test -run Test_GraphQL_GraphiQL
+func Test_GraphQL_GraphiQL(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.All("/graphql", New(Config{Execute: echo, GraphiQL: true}))
+
+	req := httptest.NewRequest(fiber.MethodGet, "/graphql", nil)
+	req.Header.Set(fiber.HeaderAccept, "text/html,application/xhtml+xml,*/*;q=0.8")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, fiber.MIMETextHTMLCharsetUTF8, resp.Header.Get(fiber.HeaderContentType))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "GraphiQL.createFetcher")
+
+	// Clients which accept JSON are sent an error
+	req = httptest.NewRequest(fiber.MethodGet, "/graphql", nil)
+	req.Header.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// go test -run Test_GraphQL_Next
+func Test_GraphQL_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.All("/graphql", New(Config{
+		Execute: echo,
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	}))
+
+	resp, err := app.Test(jsonRequest(`{"query":"{ a }"}`))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+// go test -run Test_OperationType
+func Test_OperationType(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		document, operationName, typ string
+	}{
+		{"{ user }", "", "query"},
+		{"query { user }", "", "query"},
+		{"mutation Delete($id: ID = \"query\") @auth(role: mutation) { delete(id: $id) }", "", "mutation"},
+		{"# mutation\nsubscription OnEvent { event }", "", "subscription"},
+		{"query A { a } mutation B { b(s: \"\"\"}\"\"\") }", "B", "mutation"},
+		{"query A { a } mutation B { b }", "A", "query"},
+		{"query A { a } mutation B { b }", "", ""},
+		{"fragment F on User { name } mutation M { ...F }", "", "mutation"},
+		{"query A { a }", "B", ""},
+	}
+	for _, tc := range cases {
+		require.Equal(t, tc.typ, operationType(tc.document, tc.operationName), tc.document)
+	}
+}
+
+// go test -v -run=^$ -bench=Benchmark_GraphQL -benchmem -count=4
+func Benchmark_GraphQL(b *testing.B) {
+	app := fiber.New()
+	app.All("/graphql", New(Config{Execute: echo}))
+
+	h := app.Handler()
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fiber.MethodPost)
+	ctx.Request.Header.SetContentType(fiber.MIMEApplicationJSON)
+	ctx.Request.SetRequestURI("/graphql")
+	ctx.Request.SetBodyString(`{"query":"query User($id: ID!) { user(id: $id) { name } }","variables":{"id":"1"}}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		h(ctx)
+	}
+}
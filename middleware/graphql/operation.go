@@ -0,0 +1,110 @@
+package graphql
+
+import (
+	"strings"
+)
+
+// operation is an operation definition of a GraphQL document
+type operation struct {
+	typ  string
+	name string
+}
+
+// operationType returns the type of the operation of the document which is selected by
+// the operation name, or the only operation. It returns "" if there is no such operation,
+// the executor reports the error then. Only the top level of the document is scanned.
+func operationType(document, operationName string) string {
+	var (
+		operations []operation
+		pending    *operation
+		depth      int // depth of {}
+		parens     int // depth of () at the top level
+	)
+	for i := 0; i < len(document); {
+		ch := document[i]
+		switch {
+		case ch == '#':
+			// Comments end at the end of the line
+			for i < len(document) && document[i] != '\n' && document[i] != '\r' {
+				i++
+			}
+			continue
+		case ch == '"':
+			i = skipString(document, i)
+			continue
+		case ch == '{':
+			if depth == 0 && parens == 0 {
+				if pending == nil {
+					// The query shorthand
+					operations = append(operations, operation{typ: "query"})
+				} else if pending.typ != "fragment" {
+					operations = append(operations, *pending)
+				}
+				pending = nil
+			}
+			depth++
+		case ch == '}':
+			depth--
+		case depth == 0 && ch == '(':
+			parens++
+		case depth == 0 && ch == ')':
+			parens--
+		case depth == 0 && parens == 0 && isNameStart(ch):
+			start := i
+			for i < len(document) && isName(document[i]) {
+				i++
+			}
+			name := document[start:i]
+			directive := start > 0 && document[start-1] == '@'
+			switch {
+			case directive:
+			case pending == nil:
+				if name == "query" || name == "mutation" || name == "subscription" || name == "fragment" {
+					pending = &operation{typ: name}
+				}
+			case pending.name == "":
+				pending.name = name
+			}
+			continue
+		}
+		i++
+	}
+
+	for _, op := range operations {
+		if operationName != "" && op.name == operationName || operationName == "" && len(operations) == 1 {
+			return op.typ
+		}
+	}
+	return ""
+}
+
+// skipString returns the index after the string or block string which starts at i
+func skipString(document string, i int) int {
+	if strings.HasPrefix(document[i:], `"""`) {
+		for j := i + 3; j < len(document); j++ {
+			if document[j] == '\\' && strings.HasPrefix(document[j:], `\"""`) {
+				j += 3
+			} else if strings.HasPrefix(document[j:], `"""`) {
+				return j + 3
+			}
+		}
+		return len(document)
+	}
+	for j := i + 1; j < len(document); j++ {
+		switch document[j] {
+		case '\\':
+			j++
+		case '"', '\n':
+			return j + 1
+		}
+	}
+	return len(document)
+}
+
+func isNameStart(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
+
+func isName(ch byte) bool {
+	return isNameStart(ch) || ch >= '0' && ch <= '9'
+}
@@ -0,0 +1,60 @@
+package graphql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// persistedQuery resolves the query of an automatic persisted query, which is identified
+// by the extension {"persistedQuery": {"version": 1, "sha256Hash": "..."}}. A query with
+// a hash is persisted, a request with only a hash is sent the error PersistedQueryNotFound
+// if its query wasn't persisted, so the client sends it again with the query.
+func (cfg *Config) persistedQuery(req *Request) error {
+	extension, ok := req.Extensions["persistedQuery"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	if !cfg.PersistedQueries {
+		if req.Query != "" {
+			return nil
+		}
+		return &requestError{status: fiber.StatusOK, err: Error{
+			Message:    "PersistedQueryNotSupported",
+			Extensions: map[string]any{"code": "PERSISTED_QUERY_NOT_SUPPORTED"},
+		}}
+	}
+
+	hash, ok := extension["sha256Hash"].(string)
+	if !ok || hash == "" {
+		return badRequest("persisted query has no sha256Hash")
+	}
+	hash = strings.ToLower(hash)
+
+	if req.Query == "" {
+		query, err := cfg.Storage.Get(hash)
+		if err != nil {
+			return fmt.Errorf("graphql: failed to get persisted query: %w", err)
+		}
+		if query == nil {
+			return &requestError{status: fiber.StatusOK, err: Error{
+				Message:    "PersistedQueryNotFound",
+				Extensions: map[string]any{"code": "PERSISTED_QUERY_NOT_FOUND"},
+			}}
+		}
+		req.Query = string(query)
+		return nil
+	}
+
+	sum := sha256.Sum256([]byte(req.Query))
+	if hex.EncodeToString(sum[:]) != hash {
+		return badRequest("provided sha256Hash does not match the query")
+	}
+	if err := cfg.Storage.Set(hash, []byte(req.Query), 0); err != nil {
+		return fmt.Errorf("graphql: failed to persist query: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package grpcgateway
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+)
+
+// fieldCache caches the field indexes by name of the message types
+var fieldCache sync.Map // map[reflect.Type]map[string]int
+
+// fields returns the indexes of the fields of the struct type by their names. A field is
+// found by its name, its JSON name and the name and JSON name of its protobuf tag.
+func fields(t reflect.Type) map[string]int {
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.(map[string]int) //nolint:forcetypeassert // Only maps are stored
+	}
+	names := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		names[strings.ToLower(field.Name)] = i
+		if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+			names[name] = i
+		}
+		for _, option := range strings.Split(field.Tag.Get("protobuf"), ",") {
+			if name, ok := strings.CutPrefix(option, "name="); ok {
+				names[name] = i
+			} else if name, ok := strings.CutPrefix(option, "json="); ok {
+				names[name] = i
+			}
+		}
+	}
+	fieldCache.Store(t, names)
+	return names
+}
+
+// lookupField returns the field of the message with the dotted path, it allocates nil pointers
+// to nested messages. It returns false if the message has no such field.
+func lookupField(msg reflect.Value, path string) (reflect.Value, bool) {
+	for {
+		for msg.Kind() == reflect.Pointer {
+			if msg.IsNil() {
+				msg.Set(reflect.New(msg.Type().Elem()))
+			}
+			msg = msg.Elem()
+		}
+		if msg.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		name, rest, nested := strings.Cut(path, ".")
+		names := fields(msg.Type())
+		i, ok := names[name]
+		if !ok {
+			if i, ok = names[strings.ToLower(name)]; !ok {
+				return reflect.Value{}, false
+			}
+		}
+		msg = msg.Field(i)
+		if !nested {
+			return msg, true
+		}
+		path = rest
+	}
+}
+
+// setValues sets the field to the values, which are parsed according to its type
+func setValues(field reflect.Value, values []string) error {
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setValue(field, values[len(values)-1])
+}
+
+// setValue sets the field to the value, which is parsed according to its type
+func setValue(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Pointer {
+		elem := reflect.New(field.Type().Elem())
+		if err := setValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	switch field.Kind() { //nolint:exhaustive // Other kinds are not supported
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		// bytes fields are base64 encoded like in the JSON mapping of protobuf
+		b, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			if b, err = base64.RawURLEncoding.DecodeString(value); err != nil {
+				return err
+			}
+		}
+		field.SetBytes(b)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// bind decodes the request body into the message and sets the fields of the route
+// parameters and query parameters, route parameters take precedence.
+func (cfg *Config) bind(c fiber.Ctx, msg any) error {
+	v := reflect.ValueOf(msg)
+
+	if body := c.Body(); len(body) > 0 {
+		unmarshal := cfg.Unmarshal
+		if unmarshal == nil {
+			unmarshal = c.App().Config().JSONDecoder
+		}
+		target := msg
+		if cfg.Body != "*" {
+			f, ok := lookupField(v, cfg.Body)
+			if !ok {
+				return fmt.Errorf("grpcgateway: request message has no body field %q", cfg.Body)
+			}
+			target = f.Addr().Interface()
+		}
+		if err := unmarshal(body, target); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body: "+err.Error())
+		}
+	}
+
+	var err error
+	query := make(map[string][]string)
+	c.RequestCtx().QueryArgs().VisitAll(func(key, value []byte) {
+		query[string(key)] = append(query[string(key)], string(value))
+	})
+	for key, values := range query {
+		f, ok := lookupField(v, key)
+		if !ok {
+			continue
+		}
+		if err = setValues(f, values); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid query parameter %q: %v", key, err))
+		}
+	}
+
+	for _, param := range c.Route().Params {
+		f, ok := lookupField(v, param)
+		if !ok {
+			continue
+		}
+		if err = setValue(f, utils.CopyString(c.Params(param))); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid route parameter %q: %v", param, err))
+		}
+	}
+	return nil
+}
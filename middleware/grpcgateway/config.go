@@ -0,0 +1,87 @@
+package grpcgateway
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+)
+
+// Config defines the config for the gateway handlers.
+type Config struct {
+	// Context returns the context the gRPC method is called with, e.g. to forward
+	// headers of the request as outgoing metadata.
+	//
+	// Optional. Default: func(c fiber.Ctx) context.Context {
+	//   return c.Context()
+	// }
+	Context func(c fiber.Ctx) context.Context
+
+	// ErrorHandler is called if the request message can't be bound or the gRPC method
+	// returns an error.
+	//
+	// Optional. Default: responds with the HTTP status of the gRPC status code of the
+	// error and a JSON body {"code": 5, "message": "..."}
+	ErrorHandler func(c fiber.Ctx, err error) error
+
+	// Marshal encodes the response messages, e.g. with protojson.Marshal.
+	//
+	// Optional. Default: the JSONEncoder of the app
+	Marshal utils.JSONMarshal
+
+	// Unmarshal decodes the request body into the request message, e.g. with protojson.Unmarshal.
+	//
+	// Optional. Default: the JSONDecoder of the app
+	Unmarshal utils.JSONUnmarshal
+
+	// ContentType is the content type of the response messages.
+	//
+	// Optional. Default: "application/json"
+	ContentType string
+
+	// Body is the field of the request message the request body is decoded into,
+	// "*" decodes the body into the whole message. Route parameters and query
+	// parameters are set on the fields with the same name.
+	//
+	// Optional. Default: "*"
+	Body string
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Context: func(c fiber.Ctx) context.Context {
+		return c.Context()
+	},
+	ErrorHandler: func(c fiber.Ctx, err error) error {
+		code, message := Status(err)
+		return c.Status(HTTPStatus(code)).JSON(errorBody{Code: code, Message: message})
+	},
+	ContentType: fiber.MIMEApplicationJSON,
+	Body:        "*",
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Context == nil {
+		cfg.Context = ConfigDefault.Context
+	}
+	if cfg.ErrorHandler == nil {
+		cfg.ErrorHandler = ConfigDefault.ErrorHandler
+	}
+	if cfg.ContentType == "" {
+		cfg.ContentType = ConfigDefault.ContentType
+	}
+	if cfg.Body == "" {
+		cfg.Body = ConfigDefault.Body
+	}
+	return cfg
+}
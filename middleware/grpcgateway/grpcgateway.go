@@ -0,0 +1,111 @@
+// Package grpcgateway mounts gRPC methods onto Fiber routes. It transcodes JSON
+// requests to request messages and response messages to JSON, like grpc-gateway,
+// and streams the messages of server streaming methods as server-sent events.
+//
+// The package doesn't depend on the gRPC module: a method is any function with the
+// signature of a gRPC server method or a wrapped client method, and the errors of
+// the gRPC status package are recognized by their GRPCStatus method.
+package grpcgateway
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Stream is the client stream of a server streaming gRPC method
+type Stream[T any] interface {
+	Recv() (*T, error)
+}
+
+// Unary returns a handler which calls the unary gRPC method with the request message
+// bound from the request and responds with the response message.
+func Unary[Req, Resp any](method func(ctx context.Context, req *Req) (*Resp, error), config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	return func(c fiber.Ctx) error {
+		req := new(Req)
+		if err := cfg.bind(c, req); err != nil {
+			return cfg.ErrorHandler(c, err)
+		}
+
+		resp, err := method(cfg.Context(c), req)
+		if err != nil {
+			return cfg.ErrorHandler(c, err)
+		}
+
+		marshal := cfg.Marshal
+		if marshal == nil {
+			marshal = c.App().Config().JSONEncoder
+		}
+		body, err := marshal(resp)
+		if err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentType, cfg.ContentType)
+		return c.Send(body)
+	}
+}
+
+// ServerStream returns a handler which calls the server streaming gRPC method with the
+// request message bound from the request and sends the response messages as server-sent
+// events. An error of the stream is sent as an event of type "error" and ends the stream.
+func ServerStream[Req, Resp any](method func(ctx context.Context, req *Req) (Stream[Resp], error), config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	return func(c fiber.Ctx) error {
+		req := new(Req)
+		if err := cfg.bind(c, req); err != nil {
+			return cfg.ErrorHandler(c, err)
+		}
+
+		// The stream outlives the handler, so it is canceled when writing to the client fails
+		ctx, cancel := context.WithCancel(cfg.Context(c))
+		stream, err := method(ctx, req)
+		if err != nil {
+			cancel()
+			return cfg.ErrorHandler(c, err)
+		}
+
+		marshal := cfg.Marshal
+		if marshal == nil {
+			marshal = c.App().Config().JSONEncoder
+		}
+
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set(fiber.HeaderConnection, "keep-alive")
+
+		return c.SendStreamWriter(func(w *bufio.Writer) {
+			defer cancel()
+			for {
+				msg, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					return
+				}
+
+				var data []byte
+				if err == nil {
+					data, err = marshal(msg)
+				}
+				failed := err != nil
+				if failed {
+					code, message := Status(err)
+					data, _ = marshal(errorBody{Code: code, Message: message}) //nolint:errcheck // The error body can always be encoded
+					_, _ = w.WriteString("event: error\n")                     //nolint:errcheck // Write errors are reported by Flush
+				}
+				_, _ = w.WriteString("data: ") //nolint:errcheck // Write errors are reported by Flush
+				_, _ = w.Write(data)           //nolint:errcheck // Write errors are reported by Flush
+				_, _ = w.WriteString("\n\n")   //nolint:errcheck // Write errors are reported by Flush
+				if err := w.Flush(); err != nil || failed {
+					return
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package grpcgateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// The messages and errors mimic the code generated by protoc-gen-go and the gRPC status package
+
+type getUserRequest struct {
+	Filter *userFilter `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	Name   string      `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Tags   []string    `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	ID     int64       `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+type userFilter struct {
+	Active *bool `protobuf:"varint,1,opt,name=active,proto3,oneof" json:"active,omitempty"`
+}
+
+type user struct {
+	Name string   `json:"name,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+	ID   int64    `json:"id,omitempty"`
+}
+
+type grpcStatusValue struct {
+	message string
+	code    uint32
+}
+
+func (s *grpcStatusValue) Code() uint32    { return s.code }
+func (s *grpcStatusValue) Message() string { return s.message }
+
+type grpcError struct {
+	status *grpcStatusValue
+}
+
+func (e *grpcError) Error() string {
+	if e.status == nil {
+		return "unknown"
+	}
+	return e.status.message
+}
+
+func (e *grpcError) GRPCStatus() *grpcStatusValue { return e.status }
+
+func errorf(code Code, format string, args ...any) error {
+	return &grpcError{status: &grpcStatusValue{code: uint32(code), message: fmt.Sprintf(format, args...)}}
+}
+
+func getUser(_ context.Context, req *getUserRequest) (*user, error) {
+	if req.ID == 0 {
+		return nil, errorf(NotFound, "user %d not found", req.ID)
+	}
+	if req.Filter != nil && req.Filter.Active != nil && !*req.Filter.Active {
+		return nil, errorf(FailedPrecondition, "user %d is active", req.ID)
+	}
+	return &user{ID: req.ID, Name: req.Name, Tags: req.Tags}, nil
+}
+
+// go test -run Test_Unary
+func Test_Unary(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Get("/users/:id", Unary(getUser))
+	app.Post("/users/:id", Unary(getUser))
+
+	testCases := []struct {
+		method string
+		target string
+		body   string
+		resp   string
+		status int
+	}{
+		{method: fiber.MethodGet, target: "/users/42?displayName=john&tags=a&tags=b", status: fiber.StatusOK, resp: `{"id":42,"name":"john","tags":["a","b"]}`},
+		// The route parameter takes precedence over the body
+		{method: fiber.MethodPost, target: "/users/42", body: `{"id":1,"display_name":"jane"}`, status: fiber.StatusOK, resp: `{"id":42,"name":"jane"}`},
+		// Nested fields are set with dotted query parameters
+		{method: fiber.MethodGet, target: "/users/42?filter.active=false", status: fiber.StatusBadRequest, resp: `{"code":9,"message":"user 42 is active"}`},
+		{method: fiber.MethodGet, target: "/users/0", status: fiber.StatusNotFound, resp: `{"code":5,"message":"user 0 not found"}`},
+		{method: fiber.MethodGet, target: "/users/abc", status: fiber.StatusBadRequest, resp: `{"code":3,"message":"invalid route parameter \"id\": strconv.ParseInt: parsing \"abc\": invalid syntax"}`},
+		{method: fiber.MethodPost, target: "/users/1", body: `{"id":`, status: fiber.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, tc.status, resp.StatusCode, tc.target)
+		if tc.resp != "" {
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.resp, string(body), tc.target)
+		}
+	}
+}
+
+// go test -run Test_Unary_Config
+func Test_Unary_Config(t *testing.T) {
+	t.Parallel()
+	type contextKey struct{}
+
+	app := fiber.New()
+	app.Post("/users/:id", Unary(func(ctx context.Context, req *struct {
+		User *user `json:"user"`
+		ID   int64 `json:"id"`
+	},
+	) (*user, error) {
+		req.User.ID = req.ID
+		req.User.Name += ctx.Value(contextKey{}).(string) //nolint:forcetypeassert // The value is set by Context
+		return req.User, nil
+	}, Config{
+		Body: "user",
+		Context: func(c fiber.Ctx) context.Context {
+			return context.WithValue(c.Context(), contextKey{}, c.Get("X-Suffix"))
+		},
+		ContentType: "application/vnd.user+json",
+		ErrorHandler: func(c fiber.Ctx, err error) error {
+			code, message := Status(err)
+			return c.Status(HTTPStatus(code)).SendString(message)
+		},
+	}))
+
+	req := httptest.NewRequest(fiber.MethodPost, "/users/7", strings.NewReader(`{"name":"john"}`))
+	req.Header.Set("X-Suffix", "-doe")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/vnd.user+json", resp.Header.Get(fiber.HeaderContentType))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":7,"name":"john-doe"}`, string(body))
+
+	req = httptest.NewRequest(fiber.MethodPost, "/users/x", strings.NewReader(`{}`))
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "invalid route parameter")
+}
+
+type userStream struct {
+	err   error
+	users []*user
+}
+
+func (s *userStream) Recv() (*user, error) {
+	if len(s.users) == 0 {
+		return nil, s.err
+	}
+	u := s.users[0]
+	s.users = s.users[1:]
+	return u, nil
+}
+
+// go test -run Test_ServerStream
+func Test_ServerStream(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Get("/users", ServerStream(func(_ context.Context, req *getUserRequest) (Stream[user], error) {
+		if req.Name == "" {
+			return nil, errorf(InvalidArgument, "display name is required")
+		}
+		stream := &userStream{err: io.EOF, users: []*user{{ID: 1, Name: req.Name}, {ID: 2, Name: req.Name}}}
+		if req.ID != 0 {
+			stream.err = errorf(Unavailable, "backend unavailable")
+		}
+		return stream, nil
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/users?display_name=john", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get(fiber.HeaderContentType))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "data: {\"name\":\"john\",\"id\":1}\n\ndata: {\"name\":\"john\",\"id\":2}\n\n", string(body))
+
+	// An error of the stream is sent as an event
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/users?display_name=john&id=1", nil))
+	require.NoError(t, err)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(string(body), "event: error\ndata: {\"message\":\"backend unavailable\",\"code\":14}\n\n"))
+
+	// An error of the method is sent as a response
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/users", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"code":3,"message":"display name is required"}`, string(body))
+}
+
+// go test -run Test_Status
+func Test_Status(t *testing.T) {
+	t.Parallel()
+
+	code, message := Status(fmt.Errorf("wrapped: %w", errorf(PermissionDenied, "denied")))
+	require.Equal(t, PermissionDenied, code)
+	require.Equal(t, "denied", message)
+	require.Equal(t, fiber.StatusForbidden, HTTPStatus(code))
+
+	code, message = Status(fiber.NewError(fiber.StatusTooManyRequests, "slow down"))
+	require.Equal(t, ResourceExhausted, code)
+	require.Equal(t, "slow down", message)
+
+	code, _ = Status(context.DeadlineExceeded)
+	require.Equal(t, DeadlineExceeded, code)
+	require.Equal(t, fiber.StatusGatewayTimeout, HTTPStatus(code))
+
+	code, message = Status(errors.New("boom"))
+	require.Equal(t, Unknown, code)
+	require.Equal(t, "boom", message)
+	require.Equal(t, fiber.StatusInternalServerError, HTTPStatus(code))
+
+	// A nil status is not a gRPC status
+	code, _ = Status(&grpcError{})
+	require.Equal(t, Unknown, code)
+}
+
+// go test -v -run=^$ -bench=Benchmark_Unary -benchmem -count=4
+func Benchmark_Unary(b *testing.B) {
+	app := fiber.New()
+	app.Get("/users/:id", Unary(getUser))
+
+	h := app.Handler()
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI("/users/42?displayName=john")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		h(ctx)
+	}
+}
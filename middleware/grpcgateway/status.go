@@ -0,0 +1,143 @@
+package grpcgateway
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Code is a gRPC status code
+type Code uint32
+
+// gRPC status codes, see https://grpc.io/docs/guides/status-codes/
+const (
+	OK Code = iota
+	Canceled
+	Unknown
+	InvalidArgument
+	DeadlineExceeded
+	NotFound
+	AlreadyExists
+	PermissionDenied
+	ResourceExhausted
+	FailedPrecondition
+	Aborted
+	OutOfRange
+	Unimplemented
+	Internal
+	Unavailable
+	DataLoss
+	Unauthenticated
+)
+
+// errorBody is the response body of an error
+type errorBody struct {
+	Message string `json:"message"`
+	Code    Code   `json:"code"`
+}
+
+// HTTPStatus returns the HTTP status code which corresponds to the gRPC status code
+func HTTPStatus(code Code) int {
+	switch code {
+	case OK:
+		return fiber.StatusOK
+	case Canceled:
+		return 499 // Client Closed Request
+	case InvalidArgument, FailedPrecondition, OutOfRange:
+		return fiber.StatusBadRequest
+	case DeadlineExceeded:
+		return fiber.StatusGatewayTimeout
+	case NotFound:
+		return fiber.StatusNotFound
+	case AlreadyExists, Aborted:
+		return fiber.StatusConflict
+	case PermissionDenied:
+		return fiber.StatusForbidden
+	case Unauthenticated:
+		return fiber.StatusUnauthorized
+	case ResourceExhausted:
+		return fiber.StatusTooManyRequests
+	case Unimplemented:
+		return fiber.StatusNotImplemented
+	case Unavailable:
+		return fiber.StatusServiceUnavailable
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// Status returns the gRPC status code and message of the error. Errors of the
+// gRPC status package are recognized by their GRPCStatus method, a *fiber.Error
+// is mapped by its HTTP status code.
+func Status(err error) (Code, string) {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if code, message, ok := grpcStatus(e); ok {
+			return code, message
+		}
+	}
+
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &fiberErr):
+		return codeFromHTTPStatus(fiberErr.Code), fiberErr.Message
+	case errors.Is(err, context.Canceled):
+		return Canceled, err.Error()
+	case errors.Is(err, context.DeadlineExceeded):
+		return DeadlineExceeded, err.Error()
+	default:
+		return Unknown, err.Error()
+	}
+}
+
+// grpcStatus returns the code and message of the *status.Status returned by the
+// GRPCStatus method of the error, without depending on the gRPC module
+func grpcStatus(err error) (Code, string, bool) {
+	method := reflect.ValueOf(err).MethodByName("GRPCStatus")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return 0, "", false
+	}
+	status := method.Call(nil)[0]
+	if status.Kind() == reflect.Pointer && status.IsNil() {
+		return 0, "", false
+	}
+	code, message := status.MethodByName("Code"), status.MethodByName("Message")
+	if !code.IsValid() || !message.IsValid() ||
+		code.Type().NumIn() != 0 || code.Type().NumOut() != 1 || code.Type().Out(0).Kind() != reflect.Uint32 ||
+		message.Type().NumIn() != 0 || message.Type().NumOut() != 1 || message.Type().Out(0).Kind() != reflect.String {
+		return 0, "", false
+	}
+	return Code(code.Call(nil)[0].Uint()), message.Call(nil)[0].String(), true
+}
+
+// codeFromHTTPStatus returns the gRPC status code which corresponds to the HTTP status code
+func codeFromHTTPStatus(status int) Code {
+	switch status {
+	case fiber.StatusOK:
+		return OK
+	case fiber.StatusBadRequest:
+		return InvalidArgument
+	case fiber.StatusUnauthorized:
+		return Unauthenticated
+	case fiber.StatusForbidden:
+		return PermissionDenied
+	case fiber.StatusNotFound:
+		return NotFound
+	case fiber.StatusConflict:
+		return Aborted
+	case fiber.StatusTooManyRequests:
+		return ResourceExhausted
+	case fiber.StatusNotImplemented:
+		return Unimplemented
+	case fiber.StatusServiceUnavailable:
+		return Unavailable
+	case fiber.StatusGatewayTimeout:
+		return DeadlineExceeded
+	default:
+		if status >= 500 {
+			return Internal
+		}
+		return Unknown
+	}
+}
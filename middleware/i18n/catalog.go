@@ -0,0 +1,163 @@
+package i18n
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// pluralForms are the names of the plural forms in the message catalogs
+var pluralForms = map[string]plural.Form{
+	"zero":  plural.Zero,
+	"one":   plural.One,
+	"two":   plural.Two,
+	"few":   plural.Few,
+	"many":  plural.Many,
+	"other": plural.Other,
+}
+
+// message is a message of a catalog with its plural forms
+type message struct {
+	forms map[plural.Form]string
+	other string
+}
+
+// catalog contains the messages of a language
+type catalog struct {
+	messages map[string]*message
+	tag      language.Tag
+}
+
+// bundle contains the catalogs of all supported languages
+type bundle struct {
+	matcher  language.Matcher
+	catalogs []*catalog // in the order of the tags of the matcher, the default language first
+}
+
+// newBundle loads the message catalogs of the config
+func newBundle(cfg *Config) (*bundle, error) {
+	defaultTag, err := language.Parse(cfg.DefaultLanguage)
+	if err != nil {
+		return nil, fmt.Errorf("i18n: invalid default language %q: %w", cfg.DefaultLanguage, err)
+	}
+	b := &bundle{}
+	b.catalog(defaultTag)
+
+	if cfg.FS != nil {
+		entries, err := fs.ReadDir(cfg.FS, cfg.Root)
+		if err != nil {
+			return nil, fmt.Errorf("i18n: failed to read catalogs: %w", err)
+		}
+		for _, entry := range entries {
+			ext := path.Ext(entry.Name())
+			unmarshal, ok := cfg.Unmarshalers[ext]
+			if entry.IsDir() || !ok {
+				continue
+			}
+			tag, err := language.Parse(strings.TrimSuffix(entry.Name(), ext))
+			if err != nil {
+				return nil, fmt.Errorf("i18n: invalid language of catalog %q: %w", entry.Name(), err)
+			}
+			data, err := fs.ReadFile(cfg.FS, path.Join(cfg.Root, entry.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("i18n: failed to read catalog %q: %w", entry.Name(), err)
+			}
+			var messages map[string]any
+			if err := unmarshal(data, &messages); err != nil {
+				return nil, fmt.Errorf("i18n: failed to parse catalog %q: %w", entry.Name(), err)
+			}
+			if err := b.catalog(tag).add(messages); err != nil {
+				return nil, fmt.Errorf("i18n: invalid catalog %q: %w", entry.Name(), err)
+			}
+		}
+	}
+
+	for lang, messages := range cfg.Messages {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return nil, fmt.Errorf("i18n: invalid language %q: %w", lang, err)
+		}
+		if err := b.catalog(tag).add(messages); err != nil {
+			return nil, fmt.Errorf("i18n: invalid messages of %q: %w", lang, err)
+		}
+	}
+
+	tags := make([]language.Tag, len(b.catalogs))
+	for i, c := range b.catalogs {
+		tags[i] = c.tag
+	}
+	b.matcher = language.NewMatcher(tags)
+	return b, nil
+}
+
+// catalog returns the catalog of the language, it is created if it doesn't exist
+func (b *bundle) catalog(tag language.Tag) *catalog {
+	for _, c := range b.catalogs {
+		if c.tag == tag {
+			return c
+		}
+	}
+	c := &catalog{tag: tag, messages: make(map[string]*message)}
+	b.catalogs = append(b.catalogs, c)
+	return c
+}
+
+// match returns the catalog of the best supported language, or false if no language is supported
+func (b *bundle) match(tags []language.Tag) (*catalog, bool) {
+	if len(tags) == 0 {
+		return nil, false
+	}
+	_, index, confidence := b.matcher.Match(tags...)
+	if confidence == language.No {
+		return nil, false
+	}
+	return b.catalogs[index], true
+}
+
+// add adds the messages to the catalog
+func (c *catalog) add(messages map[string]any) error {
+	for key, value := range messages {
+		switch value := value.(type) {
+		case string:
+			c.messages[key] = &message{other: value}
+		case map[string]string:
+			forms := make(map[string]any, len(value))
+			for name, form := range value {
+				forms[name] = form
+			}
+			if err := c.addPlural(key, forms); err != nil {
+				return err
+			}
+		case map[string]any:
+			if err := c.addPlural(key, value); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %q must be a string or a map of plural forms, got %T", key, value)
+		}
+	}
+	return nil
+}
+
+// addPlural adds a message with plural forms to the catalog
+func (c *catalog) addPlural(key string, forms map[string]any) error {
+	msg := &message{forms: make(map[plural.Form]string, len(forms))}
+	for name, value := range forms {
+		form, ok := pluralForms[name]
+		if !ok {
+			return fmt.Errorf("message %q has unknown plural form %q", key, name)
+		}
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("plural form %q of message %q must be a string, got %T", name, key, value)
+		}
+		msg.forms[form] = s
+	}
+	msg.other = msg.forms[plural.Other]
+	c.messages[key] = msg
+	return nil
+}
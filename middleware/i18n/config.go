@@ -0,0 +1,100 @@
+package i18n
+
+import (
+	"encoding/json"
+	"io/fs"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// FS is the file system with the message catalogs, e.g. an embed.FS.
+	// Every file in Root named after a language tag, e.g. "en.json" or "pt-BR.json",
+	// is loaded as the catalog of that language if its extension has an Unmarshaler.
+	//
+	// Optional. Default: nil
+	FS fs.FS
+
+	// Root is the directory of the message catalogs in FS.
+	//
+	// Optional. Default: "."
+	Root string
+
+	// Messages defines message catalogs per language in addition to the files in FS.
+	// A message is either a string or a map of the plural forms "zero", "one", "two",
+	// "few", "many" and "other" to strings.
+	//
+	// Optional. Default: nil
+	Messages map[string]map[string]any
+
+	// Unmarshalers decode the message catalogs in FS by their file extension,
+	// e.g. ".toml": toml.Unmarshal.
+	//
+	// Optional. Default: map[string]func(data []byte, v any) error{".json": json.Unmarshal}
+	Unmarshalers map[string]func(data []byte, v any) error
+
+	// DefaultLanguage is the language if no language of the request is supported,
+	// and the fallback for messages missing in the catalog of the language.
+	//
+	// Optional. Default: "en"
+	DefaultLanguage string
+
+	// LanguageLookup is a list of "<source>:<name>" strings used in order to find the
+	// language of the request. The first supported language is used.
+	// Possible values:
+	// - "header:<name>"
+	// - "query:<name>"
+	// - "cookie:<name>"
+	//
+	// Optional. Default: []string{"query:lang", "cookie:lang", "header:Accept-Language"}
+	LanguageLookup []string
+
+	// ViewBindKey is the key the Localizer of the request is bound to for templates,
+	// e.g. {{.i18n.T "hello" .Name}}. The Localizer is not bound if it is empty.
+	//
+	// Optional. Default: ""
+	ViewBindKey string
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next: nil,
+	Root: ".",
+	Unmarshalers: map[string]func(data []byte, v any) error{
+		".json": json.Unmarshal,
+	},
+	DefaultLanguage: "en",
+	LanguageLookup:  []string{"query:lang", "cookie:lang", "header:" + fiber.HeaderAcceptLanguage},
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Root == "" {
+		cfg.Root = ConfigDefault.Root
+	}
+	if cfg.Unmarshalers == nil {
+		cfg.Unmarshalers = ConfigDefault.Unmarshalers
+	}
+	if cfg.DefaultLanguage == "" {
+		cfg.DefaultLanguage = ConfigDefault.DefaultLanguage
+	}
+	if len(cfg.LanguageLookup) == 0 {
+		cfg.LanguageLookup = ConfigDefault.LanguageLookup
+	}
+	return cfg
+}
@@ -0,0 +1,118 @@
+// Package i18n provides a localization middleware, which negotiates the language
+// of the request and translates messages from catalogs with plural rules.
+package i18n
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"golang.org/x/text/language"
+)
+
+// The contextKey type is unexported to prevent collisions with context keys defined in
+// other packages.
+type contextKey int
+
+// The keys for the values in context
+const (
+	localizerKey contextKey = iota
+)
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	b, err := newBundle(&cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	// Create the immutable localizers of all languages once
+	localizers := make(map[*catalog]*Localizer, len(b.catalogs))
+	for _, c := range b.catalogs {
+		localizers[c] = &Localizer{catalog: c, fallback: b.catalogs[0]}
+	}
+
+	lookups := make([]func(c fiber.Ctx) string, len(cfg.LanguageLookup))
+	var vary []string
+	for i, lookup := range cfg.LanguageLookup {
+		source, name, ok := strings.Cut(lookup, ":")
+		if !ok || name == "" {
+			panic("i18n: invalid language lookup " + lookup)
+		}
+		switch source {
+		case "header":
+			lookups[i] = func(c fiber.Ctx) string { return c.Get(name) }
+			vary = append(vary, name)
+		case "query":
+			lookups[i] = func(c fiber.Ctx) string { return c.Query(name) }
+		case "cookie":
+			lookups[i] = func(c fiber.Ctx) string { return c.Cookies(name) }
+		default:
+			panic("i18n: unsupported language lookup source " + source)
+		}
+	}
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		if len(vary) > 0 {
+			c.Vary(vary...)
+		}
+
+		localizer := localizers[b.catalogs[0]]
+		for _, lookup := range lookups {
+			value := lookup(c)
+			if value == "" {
+				continue
+			}
+			// Invalid tags are ignored, the valid tags are still matched
+			tags, _, _ := language.ParseAcceptLanguage(value) //nolint:errcheck // see above
+			if catalog, ok := b.match(tags); ok {
+				localizer = localizers[catalog]
+				break
+			}
+		}
+
+		c.Locals(localizerKey, localizer)
+		if cfg.ViewBindKey != "" {
+			if err := c.ViewBind(fiber.Map{cfg.ViewBindKey: localizer}); err != nil {
+				return err
+			}
+		}
+
+		// Continue stack
+		return c.Next()
+	}
+}
+
+// FromContext returns the Localizer of the request.
+// If the middleware wasn't executed, nil is returned, which translates messages to their keys.
+func FromContext(c fiber.Ctx) *Localizer {
+	if localizer, ok := c.Locals(localizerKey).(*Localizer); ok {
+		return localizer
+	}
+	return nil
+}
+
+// T translates the message with the key to the language of the request,
+// see Localizer.T.
+func T(c fiber.Ctx, key string, args ...any) string {
+	return FromContext(c).T(key, args...)
+}
+
+// Plural translates the message with the key to the language of the request in the
+// plural form for the count, see Localizer.Plural.
+func Plural(c fiber.Ctx, key string, count int, args ...any) string {
+	return FromContext(c).Plural(key, count, args...)
+}
+
+// Language returns the language of the request, e.g. "en" or "pt-BR".
+func Language(c fiber.Ctx) string {
+	return FromContext(c).Language()
+}
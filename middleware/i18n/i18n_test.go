@@ -0,0 +1,233 @@
+package i18n
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+var testFS = fstest.MapFS{
+	"locales/en.json": {Data: []byte(`{
+		"hello": "Hello, %s!",
+		"bye": "Goodbye",
+		"apples": {"one": "%d apple", "other": "%d apples"}
+	}`)},
+	"locales/de.json": {Data: []byte(`{
+		"hello": "Hallo, %s!",
+		"apples": {"one": "ein Apfel", "other": "%d Äpfel"}
+	}`)},
+	"locales/pl.json": {Data: []byte(`{
+		"apples": {"one": "%d jabłko", "few": "%d jabłka", "many": "%d jabłek", "other": "%d jabłka"}
+	}`)},
+	"locales/README.md": {Data: []byte("not a catalog")},
+}
+
+// go test -run Test_I18n
+func Test_I18n(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{FS: testFS, Root: "locales"}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(Language(c) + ": " + T(c, "hello", "Fiber"))
+	})
+
+	tests := []struct {
+		name     string
+		target   string
+		header   string
+		cookie   string
+		expected string
+	}{
+		{name: "default", target: "/", expected: "en: Hello, Fiber!"},
+		{name: "header", target: "/", header: "fr;q=0.9, de-AT;q=0.8, en;q=0.1", expected: "de: Hallo, Fiber!"},
+		{name: "unsupported header", target: "/", header: "fr", expected: "en: Hello, Fiber!"},
+		{name: "cookie", target: "/", header: "en", cookie: "de", expected: "de: Hallo, Fiber!"},
+		{name: "query", target: "/?lang=de", header: "en", cookie: "en", expected: "de: Hallo, Fiber!"},
+		{name: "invalid query", target: "/?lang=%21%21", header: "de", expected: "de: Hallo, Fiber!"},
+		{name: "fallback", target: "/?lang=pl", expected: "pl: Hello, Fiber!"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(fiber.MethodGet, tt.target, nil)
+		if tt.header != "" {
+			req.Header.Set(fiber.HeaderAcceptLanguage, tt.header)
+		}
+		if tt.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "lang", Value: tt.cookie})
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, tt.name)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, string(body), tt.name)
+	}
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.HeaderAcceptLanguage, resp.Header.Get(fiber.HeaderVary))
+}
+
+// go test -run Test_I18n_Plural
+func Test_I18n_Plural(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{FS: testFS, Root: "locales"}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(Plural(c, "apples", fiber.Query[int](c, "n")))
+	})
+
+	tests := []struct {
+		lang     string
+		count    string
+		expected string
+	}{
+		{lang: "en", count: "1", expected: "1 apple"},
+		{lang: "en", count: "2", expected: "2 apples"},
+		{lang: "de", count: "1", expected: "ein Apfel"},
+		{lang: "de", count: "5", expected: "5 Äpfel"},
+		{lang: "pl", count: "1", expected: "1 jabłko"},
+		{lang: "pl", count: "3", expected: "3 jabłka"},
+		{lang: "pl", count: "5", expected: "5 jabłek"},
+		{lang: "pl", count: "22", expected: "22 jabłka"},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/?lang="+tt.lang+"&n="+tt.count, nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, string(body), tt.lang+" "+tt.count)
+	}
+}
+
+// go test -run Test_I18n_Messages
+func Test_I18n_Messages(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		DefaultLanguage: "de",
+		Messages: map[string]map[string]any{
+			"de":    {"hello": "Hallo, %s!"},
+			"pt-BR": {"hello": "Olá, %s!", "apples": map[string]string{"one": "uma maçã", "other": "%d maçãs"}},
+		},
+		LanguageLookup: []string{"header:X-Language"},
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(Language(c) + ": " + T(c, "hello", "Fiber"))
+	})
+
+	tests := []struct {
+		name     string
+		header   string
+		value    string
+		expected string
+	}{
+		{name: "default", expected: "de: Hallo, Fiber!"},
+		{name: "lookup", header: "X-Language", value: "pt-BR", expected: "pt-BR: Olá, Fiber!"},
+		// The Accept-Language header is not looked up
+		{name: "accept language", header: fiber.HeaderAcceptLanguage, value: "pt-BR", expected: "de: Hallo, Fiber!"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		if tt.header != "" {
+			req.Header.Set(tt.header, tt.value)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, tt.name)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, string(body), tt.name)
+	}
+}
+
+// go test -run Test_I18n_Localizer
+func Test_I18n_Localizer(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{FS: testFS, Root: "locales", ViewBindKey: "i18n"}))
+	app.Get("/", func(c fiber.Ctx) error {
+		localizer := FromContext(c)
+		require.NotNil(t, localizer)
+		require.Equal(t, "de", localizer.Language())
+		// Missing messages are taken from the default language
+		require.Equal(t, "Goodbye", localizer.T("bye"))
+		require.Equal(t, "missing", localizer.T("missing"))
+		require.Equal(t, "missing", localizer.Plural("missing", 1))
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/?lang=de", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+// go test -run Test_I18n_Invalid
+func Test_I18n_Invalid(t *testing.T) {
+	t.Parallel()
+	invalid := []Config{
+		{DefaultLanguage: "!!"},
+		{Messages: map[string]map[string]any{"!!": {}}},
+		{Messages: map[string]map[string]any{"en": {"hello": 1}}},
+		{Messages: map[string]map[string]any{"en": {"apples": map[string]any{"several": "apples"}}}},
+		{FS: fstest.MapFS{"xx-!!.json": {Data: []byte(`{}`)}}},
+		{FS: fstest.MapFS{"en.json": {Data: []byte(`{`)}}},
+		{FS: testFS, Root: "missing"},
+		{LanguageLookup: []string{"query"}},
+		{LanguageLookup: []string{"form:lang"}},
+	}
+	for _, config := range invalid {
+		require.Panics(t, func() {
+			New(config)
+		})
+	}
+}
+
+// go test -run Test_I18n_Next
+func Test_I18n_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		FS:   testFS,
+		Root: "locales",
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(Language(c) + ": " + T(c, "hello", "Fiber"))
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/?lang=de", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, ": hello", string(body))
+}
+
+// go test -v -run=^$ -bench=Benchmark_I18n -benchmem -count=4
+func Benchmark_I18n(b *testing.B) {
+	app := fiber.New()
+	app.Use(New(Config{FS: testFS, Root: "locales"}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(T(c, "hello", "Fiber"))
+	})
+
+	h := app.Handler()
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI("/")
+	ctx.Request.Header.Set(fiber.HeaderAcceptLanguage, "fr;q=0.9, de;q=0.8")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		h(ctx)
+	}
+}
@@ -0,0 +1,73 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+)
+
+// Localizer translates messages to a language. It can be bound to templates with
+// Config.ViewBindKey, its methods are safe to call on a nil Localizer.
+type Localizer struct {
+	catalog  *catalog
+	fallback *catalog
+}
+
+// Language returns the language of the localizer, e.g. "en" or "pt-BR".
+func (l *Localizer) Language() string {
+	if l == nil {
+		return ""
+	}
+	return l.catalog.tag.String()
+}
+
+// T translates the message with the key. If args are given, the message is formatted
+// with them like fmt.Sprintf. Messages missing in the language are taken from the
+// default language, or the key is returned.
+func (l *Localizer) T(key string, args ...any) string {
+	msg, _ := l.message(key)
+	if msg == nil {
+		return key
+	}
+	return format(msg.other, args)
+}
+
+// Plural translates the message with the key in the plural form of the language for
+// the count, e.g. "one" or "other". The message is formatted with the args like
+// fmt.Sprintf, or with the count if no args are given.
+func (l *Localizer) Plural(key string, count int, args ...any) string {
+	msg, c := l.message(key)
+	if msg == nil {
+		return key
+	}
+	s, ok := msg.forms[plural.Cardinal.MatchPlural(c.tag, count, 0, 0, 0, 0)]
+	if !ok {
+		s = msg.other
+	}
+	if len(args) == 0 && strings.Contains(s, "%") {
+		args = []any{count}
+	}
+	return format(s, args)
+}
+
+// message returns the message with the key and the catalog it was found in
+func (l *Localizer) message(key string) (*message, *catalog) {
+	if l == nil {
+		return nil, nil
+	}
+	if msg, ok := l.catalog.messages[key]; ok {
+		return msg, l.catalog
+	}
+	if msg, ok := l.fallback.messages[key]; ok {
+		return msg, l.fallback
+	}
+	return nil, nil
+}
+
+func format(s string, args []any) string {
+	if len(args) == 0 {
+		return s
+	}
+	return fmt.Sprintf(s, args...)
+}
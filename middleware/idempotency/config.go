@@ -11,6 +11,10 @@ import (
 
 var ErrInvalidIdempotencyKey = errors.New("invalid idempotency key")
 
+// ErrFingerprintMismatch is returned if an idempotency key is reused for a request
+// with a different fingerprint, see Config.Fingerprint.
+var ErrFingerprintMismatch = fiber.NewError(fiber.StatusUnprocessableEntity, "idempotency key was already used for a different request")
+
 // Config defines the config for middleware.
 type Config struct {
 	// Lock locks an idempotency key.
@@ -22,6 +26,25 @@ type Config struct {
 	//
 	// Optional. Default: an in-memory storage for this process only.
 	Storage fiber.Storage
+
+	// OnConflict is called instead of waiting for the lock if a request with the same
+	// idempotency key is still in progress, e.g. to respond with 409 Conflict:
+	//
+	//	OnConflict: func(c fiber.Ctx) error {
+	//		return fiber.ErrConflict
+	//	}
+	//
+	// The Lock has to implement TryLocker to use it.
+	//
+	// Optional. Default: nil (wait for the first request and replay its response)
+	OnConflict fiber.Handler
+
+	// Fingerprint returns a fingerprint of the request, e.g. a hash of its body, which is
+	// stored with the response. Retries with the same idempotency key but a different
+	// fingerprint are rejected with ErrFingerprintMismatch instead of replaying the response.
+	//
+	// Optional. Default: nil (requests are not compared)
+	Fingerprint func(c fiber.Ctx) string
 	// Next defines a function to skip this middleware when returned true.
 	//
 	// Optional. Default: a function which skips the middleware on safe HTTP request method.
@@ -71,6 +94,10 @@ var ConfigDefault = Config{
 	Lock: nil, // Set in configDefault so we don't allocate data here.
 
 	Storage: nil, // Set in configDefault so we don't allocate data here.
+
+	OnConflict: nil,
+
+	Fingerprint: nil,
 }
 
 // Helper function to set default values
@@ -1,6 +1,7 @@
 package idempotency
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -38,7 +39,15 @@ func New(config ...Config) fiber.Handler {
 		keepResponseHeadersMap[strings.ToLower(h)] = struct{}{}
 	}
 
-	maybeWriteCachedResponse := func(c fiber.Ctx, key string) (bool, error) {
+	var tryLocker TryLocker
+	if cfg.OnConflict != nil {
+		var ok bool
+		if tryLocker, ok = cfg.Lock.(TryLocker); !ok {
+			panic("[IDEMPOTENCY] OnConflict requires a Lock which implements TryLocker")
+		}
+	}
+
+	maybeWriteCachedResponse := func(c fiber.Ctx, key, fingerprint string) (bool, error) {
 		if val, err := cfg.Storage.Get(key); err != nil {
 			return false, fmt.Errorf("failed to read response: %w", err)
 		} else if val != nil {
@@ -47,6 +56,10 @@ func New(config ...Config) fiber.Handler {
 				return false, fmt.Errorf("failed to unmarshal response: %w", err)
 			}
 
+			if cfg.Fingerprint != nil && res.Fingerprint != fingerprint {
+				return true, ErrFingerprintMismatch
+			}
+
 			_ = c.Status(res.StatusCode)
 
 			for header, vals := range res.Headers {
@@ -86,14 +99,30 @@ func New(config ...Config) fiber.Handler {
 			return err
 		}
 
+		var fingerprint string
+		if cfg.Fingerprint != nil {
+			fingerprint = cfg.Fingerprint(c)
+		}
+
 		// First-pass: if the idempotency key is in the storage, get and return the response
-		if ok, err := maybeWriteCachedResponse(c, key); err != nil {
+		if ok, err := maybeWriteCachedResponse(c, key, fingerprint); errors.Is(err, ErrFingerprintMismatch) {
+			return err
+		} else if err != nil {
 			return fmt.Errorf("failed to write cached response at fastpath: %w", err)
 		} else if ok {
 			return nil
 		}
 
-		if err := cfg.Lock.Lock(key); err != nil {
+		if tryLocker != nil {
+			locked, err := tryLocker.TryLock(key)
+			if err != nil {
+				return fmt.Errorf("failed to lock: %w", err)
+			}
+			if !locked {
+				// Another request with the same key is still in progress
+				return cfg.OnConflict(c)
+			}
+		} else if err := cfg.Lock.Lock(key); err != nil {
 			return fmt.Errorf("failed to lock: %w", err)
 		}
 		defer func() {
@@ -103,7 +132,9 @@ func New(config ...Config) fiber.Handler {
 		}()
 
 		// Lock acquired. If the idempotency key now is in the storage, get and return the response
-		if ok, err := maybeWriteCachedResponse(c, key); err != nil {
+		if ok, err := maybeWriteCachedResponse(c, key, fingerprint); errors.Is(err, ErrFingerprintMismatch) {
+			return err
+		} else if err != nil {
 			return fmt.Errorf("failed to write cached response while locked: %w", err)
 		} else if ok {
 			return nil
@@ -120,6 +151,8 @@ func New(config ...Config) fiber.Handler {
 			StatusCode: c.Response().StatusCode(),
 
 			Body: utils.CopyBytes(c.Response().Body()),
+
+			Fingerprint: fingerprint,
 		}
 		{
 			headers := make(map[string][]string)
@@ -1,10 +1,13 @@
 package idempotency_test
 
 import (
+	"crypto/sha256"
 	"errors"
 	"io"
+	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -129,6 +132,115 @@ func Test_Idempotency(t *testing.T) {
 	require.Equal(t, "12", doReq(fiber.MethodPost, "/slow", "22222222-2222-2222-2222-222222222222"))
 }
 
+// go test -run Test_Idempotency_OnConflict
+func Test_Idempotency_OnConflict(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+
+	app.Use(idempotency.New(idempotency.Config{
+		OnConflict: func(_ fiber.Ctx) error {
+			return fiber.ErrConflict
+		},
+	}))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	app.Post("/", func(c fiber.Ctx) error {
+		close(started)
+		<-release
+		return c.SendString("created")
+	})
+
+	const key = "00000000-0000-0000-0000-000000000000"
+	doReq := func() *http.Response {
+		req := httptest.NewRequest(fiber.MethodPost, "/", nil)
+		req.Header.Set("X-Idempotency-Key", key)
+		resp, err := app.Test(req, fiber.TestConfig{Timeout: 0})
+		require.NoError(t, err)
+		return resp
+	}
+
+	first := make(chan *http.Response)
+	go func() {
+		first <- doReq()
+	}()
+	<-started
+
+	// The first request is still in progress
+	require.Equal(t, fiber.StatusConflict, doReq().StatusCode)
+
+	close(release)
+	resp := <-first
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// The response of the first request is replayed now
+	resp = doReq()
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "created", string(body))
+}
+
+// go test -run Test_Idempotency_OnConflict_RequiresTryLocker
+func Test_Idempotency_OnConflict_RequiresTryLocker(t *testing.T) {
+	t.Parallel()
+
+	require.Panics(t, func() {
+		idempotency.New(idempotency.Config{
+			Lock: struct{ idempotency.Locker }{idempotency.NewMemoryLock()},
+			OnConflict: func(_ fiber.Ctx) error {
+				return fiber.ErrConflict
+			},
+		})
+	})
+}
+
+// go test -run Test_Idempotency_Fingerprint
+func Test_Idempotency_Fingerprint(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+
+	app.Use(idempotency.New(idempotency.Config{
+		Fingerprint: func(c fiber.Ctx) string {
+			sum := sha256.Sum256(c.Body())
+			return string(sum[:])
+		},
+	}))
+
+	var count int32
+	app.Post("/", func(c fiber.Ctx) error {
+		return c.SendString(strconv.Itoa(int(atomic.AddInt32(&count, 1))))
+	})
+
+	doReq := func(key, body string) (int, string) {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("X-Idempotency-Key", key)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(respBody)
+	}
+
+	const key = "00000000-0000-0000-0000-000000000000"
+
+	status, body := doReq(key, `{"amount":10}`)
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, "1", body)
+
+	status, body = doReq(key, `{"amount":10}`)
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, "1", body)
+
+	status, body = doReq(key, `{"amount":20}`)
+	require.Equal(t, fiber.StatusUnprocessableEntity, status)
+	require.Equal(t, idempotency.ErrFingerprintMismatch.Error(), body)
+
+	status, body = doReq("11111111-1111-1111-1111-111111111111", `{"amount":20}`)
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, "2", body)
+}
+
 // go test -v -run=^$ -bench=Benchmark_Idempotency -benchmem -count=4
 func Benchmark_Idempotency(b *testing.B) {
 	app := fiber.New()
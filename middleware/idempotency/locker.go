@@ -10,6 +10,14 @@ type Locker interface {
 	Unlock(key string) error
 }
 
+// TryLocker is a Locker which can lock a key without waiting for it.
+// It is required by Config.OnConflict.
+type TryLocker interface {
+	Locker
+	// TryLock locks the key and reports whether it succeeded
+	TryLock(key string) (bool, error)
+}
+
 type MemoryLock struct {
 	keys map[string]*sync.Mutex
 	mu   sync.Mutex
@@ -29,6 +37,18 @@ func (l *MemoryLock) Lock(key string) error {
 	return nil
 }
 
+func (l *MemoryLock) TryLock(key string) (bool, error) {
+	l.mu.Lock()
+	mu, ok := l.keys[key]
+	if !ok {
+		mu = new(sync.Mutex)
+		l.keys[key] = mu
+	}
+	l.mu.Unlock()
+
+	return mu.TryLock(), nil
+}
+
 func (l *MemoryLock) Unlock(key string) error {
 	l.mu.Lock()
 	mu, ok := l.keys[key]
@@ -49,4 +69,4 @@ func NewMemoryLock() *MemoryLock {
 	}
 }
 
-var _ Locker = (*MemoryLock)(nil)
+var _ TryLocker = (*MemoryLock)(nil)
@@ -59,3 +59,24 @@ func Test_MemoryLock(t *testing.T) {
 		require.NoError(t, err)
 	}
 }
+
+// go test -run Test_MemoryLock_TryLock
+func Test_MemoryLock_TryLock(t *testing.T) {
+	t.Parallel()
+
+	l := idempotency.NewMemoryLock()
+
+	locked, err := l.TryLock("a")
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	locked, err = l.TryLock("a")
+	require.NoError(t, err)
+	require.False(t, locked)
+
+	require.NoError(t, l.Unlock("a"))
+
+	locked, err = l.TryLock("a")
+	require.NoError(t, err)
+	require.True(t, locked)
+}
@@ -7,6 +7,7 @@ package idempotency
 type response struct {
 	Headers map[string][]string `msg:"hs"`
 
-	Body       []byte `msg:"b"`
-	StatusCode int    `msg:"sc"`
+	Fingerprint string `msg:"fp"`
+	Body        []byte `msg:"b"`
+	StatusCode  int    `msg:"sc"`
 }
@@ -67,6 +67,12 @@ func (z *response) DecodeMsg(dc *msgp.Reader) (err error) {
 				}
 				z.Headers[za0001] = za0002
 			}
+		case "fp":
+			z.Fingerprint, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Fingerprint")
+				return
+			}
 		case "b":
 			z.Body, err = dc.ReadBytes(z.Body)
 			if err != nil {
@@ -92,9 +98,9 @@ func (z *response) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *response) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 3
+	// map header, size 4
 	// write "hs"
-	err = en.Append(0x83, 0xa2, 0x68, 0x73)
+	err = en.Append(0x84, 0xa2, 0x68, 0x73)
 	if err != nil {
 		return
 	}
@@ -122,6 +128,16 @@ func (z *response) EncodeMsg(en *msgp.Writer) (err error) {
 			}
 		}
 	}
+	// write "fp"
+	err = en.Append(0xa2, 0x66, 0x70)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Fingerprint)
+	if err != nil {
+		err = msgp.WrapError(err, "Fingerprint")
+		return
+	}
 	// write "b"
 	err = en.Append(0xa1, 0x62)
 	if err != nil {
@@ -148,9 +164,9 @@ func (z *response) EncodeMsg(en *msgp.Writer) (err error) {
 // MarshalMsg implements msgp.Marshaler
 func (z *response) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 3
+	// map header, size 4
 	// string "hs"
-	o = append(o, 0x83, 0xa2, 0x68, 0x73)
+	o = append(o, 0x84, 0xa2, 0x68, 0x73)
 	o = msgp.AppendMapHeader(o, uint32(len(z.Headers)))
 	for za0001, za0002 := range z.Headers {
 		o = msgp.AppendString(o, za0001)
@@ -159,6 +175,9 @@ func (z *response) MarshalMsg(b []byte) (o []byte, err error) {
 			o = msgp.AppendString(o, za0002[za0003])
 		}
 	}
+	// string "fp"
+	o = append(o, 0xa2, 0x66, 0x70)
+	o = msgp.AppendString(o, z.Fingerprint)
 	// string "b"
 	o = append(o, 0xa1, 0x62)
 	o = msgp.AppendBytes(o, z.Body)
@@ -229,6 +248,12 @@ func (z *response) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				}
 				z.Headers[za0001] = za0002
 			}
+		case "fp":
+			z.Fingerprint, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Fingerprint")
+				return
+			}
 		case "b":
 			z.Body, bts, err = msgp.ReadBytesBytes(bts, z.Body)
 			if err != nil {
@@ -265,6 +290,6 @@ func (z *response) Msgsize() (s int) {
 			}
 		}
 	}
-	s += 2 + msgp.BytesPrefixSize + len(z.Body) + 3 + msgp.IntSize
+	s += 3 + msgp.StringPrefixSize + len(z.Fingerprint) + 2 + msgp.BytesPrefixSize + len(z.Body) + 3 + msgp.IntSize
 	return
 }
@@ -1,6 +1,8 @@
 package limiter
 
 import (
+	"fmt"
+	"math"
 	"strconv"
 	"sync"
 
@@ -18,6 +20,12 @@ func (FixedWindow) New(cfg Config) fiber.Handler {
 		expiration = uint64(cfg.Expiration.Seconds())
 	)
 
+	// Use the atomic operations of the storage if available, so the hits are
+	// counted correctly even if multiple instances share the storage
+	if atomicStorage, ok := cfg.Storage.(fiber.AtomicStorage); ok {
+		return newAtomicFixedWindow(cfg, atomicStorage)
+	}
+
 	// Create manager to simplify storage operations ( see manager.go )
 	manager := newManager(cfg.Storage)
 
@@ -105,3 +113,69 @@ func (FixedWindow) New(cfg Config) fiber.Handler {
 		return err
 	}
 }
+
+// newAtomicFixedWindow creates a fixed window handler which counts the hits
+// with the atomic increment of the storage instead of a read-modify-write cycle.
+func newAtomicFixedWindow(cfg Config, storage fiber.AtomicStorage) fiber.Handler {
+	ttlStorage, hasTTL := storage.(fiber.TTLStorage)
+	expiration := uint64(cfg.Expiration.Seconds())
+
+	return func(c fiber.Ctx) error {
+		// Generate maxRequests from generator, if no generator was provided the default value returned is 5
+		maxRequests := cfg.MaxFunc(c)
+
+		// Don't execute middleware if Next returns true or if the max is 0
+		if (cfg.Next != nil && cfg.Next(c)) || maxRequests == 0 {
+			return c.Next()
+		}
+
+		// Get key from request
+		key := cfg.KeyGenerator(c)
+
+		// Increment hits, the key expires at the end of the window
+		hits, err := storage.IncrBy(key, 1, cfg.Expiration)
+		if err != nil {
+			return fmt.Errorf("limiter: failed to increment hits: %w", err)
+		}
+
+		// Calculate when it resets in seconds
+		resetInSec := expiration
+		if hasTTL {
+			if ttl, err := ttlStorage.TTL(key); err == nil && ttl > 0 {
+				resetInSec = uint64(math.Ceil(ttl.Seconds()))
+			}
+		}
+
+		// Set how many hits we have left
+		remaining := maxRequests - int(hits)
+
+		// Check if hits exceed the max
+		if remaining < 0 {
+			// Return response with Retry-After header
+			// https://tools.ietf.org/html/rfc6584
+			c.Set(fiber.HeaderRetryAfter, strconv.FormatUint(resetInSec, 10))
+
+			// Call LimitReached handler
+			return cfg.LimitReached(c)
+		}
+
+		// Continue stack for reaching c.Response().StatusCode()
+		// Store err for returning
+		err = c.Next()
+
+		// Check for SkipFailedRequests and SkipSuccessfulRequests
+		if (cfg.SkipSuccessfulRequests && c.Response().StatusCode() < fiber.StatusBadRequest) ||
+			(cfg.SkipFailedRequests && c.Response().StatusCode() >= fiber.StatusBadRequest) {
+			if _, decrErr := storage.IncrBy(key, -1, cfg.Expiration); decrErr == nil {
+				remaining++
+			}
+		}
+
+		// We can continue, update RateLimit headers
+		c.Set(xRateLimitLimit, strconv.Itoa(maxRequests))
+		c.Set(xRateLimitRemaining, strconv.Itoa(remaining))
+		c.Set(xRateLimitReset, strconv.FormatUint(resetInSec, 10))
+
+		return err
+	}
+}
@@ -1,6 +1,7 @@
 package limiter
 
 import (
+	"fmt"
 	"strconv"
 	"sync"
 	"time"
@@ -19,6 +20,12 @@ func (SlidingWindow) New(cfg Config) fiber.Handler {
 		expiration = uint64(cfg.Expiration.Seconds())
 	)
 
+	// Use the atomic operations of the storage if available, so the hits are
+	// counted correctly even if multiple instances share the storage
+	if atomicStorage, ok := cfg.Storage.(fiber.AtomicStorage); ok {
+		return newAtomicSlidingWindow(cfg, atomicStorage)
+	}
+
 	// Create manager to simplify storage operations ( see manager.go )
 	manager := newManager(cfg.Storage)
 
@@ -136,3 +143,87 @@ func (SlidingWindow) New(cfg Config) fiber.Handler {
 		return err
 	}
 }
+
+// newAtomicSlidingWindow creates a sliding window handler which counts the hits of
+// each window with the atomic increment of the storage instead of a read-modify-write
+// cycle. The windows are aligned to multiples of the expiration, and the hits of a
+// window are stored under the key suffixed with the number of the window.
+func newAtomicSlidingWindow(cfg Config, storage fiber.AtomicStorage) fiber.Handler {
+	expiration := uint64(cfg.Expiration.Seconds())
+
+	// Update timestamp every second
+	utils.StartTimeStampUpdater()
+
+	return func(c fiber.Ctx) error {
+		// Generate maxRequests from generator, if no generator was provided the default value returned is 5
+		maxRequests := cfg.MaxFunc(c)
+
+		// Don't execute middleware if Next returns true or if the max is 0
+		if (cfg.Next != nil && cfg.Next(c)) || maxRequests == 0 {
+			return c.Next()
+		}
+
+		// Get key from request
+		key := cfg.KeyGenerator(c)
+
+		// Get timestamp and the window it falls into
+		ts := uint64(utils.Timestamp())
+		window := ts / expiration
+		currKey := key + "_" + strconv.FormatUint(window, 10)
+		prevKey := key + "_" + strconv.FormatUint(window-1, 10)
+
+		// Increment hits. Garbage collect when the next window ends, because the
+		// hits of the window are the previous hits of the next one.
+		currHits, err := storage.IncrBy(currKey, 1, 2*cfg.Expiration)
+		if err != nil {
+			return fmt.Errorf("limiter: failed to increment hits: %w", err)
+		}
+
+		// Read the hits of the previous window without changing them
+		prevHits, err := storage.IncrBy(prevKey, 0, cfg.Expiration)
+		if err != nil {
+			return fmt.Errorf("limiter: failed to read previous hits: %w", err)
+		}
+
+		// Calculate when it resets in seconds
+		resetInSec := (window+1)*expiration - ts
+
+		// weight = time until current window reset / total window length
+		weight := float64(resetInSec) / float64(expiration)
+
+		// rate = request count in previous window - weight + request count in current window
+		rate := int(float64(prevHits)*weight) + int(currHits)
+
+		// Calculate how many hits can be made based on the current rate
+		remaining := maxRequests - rate
+
+		// Check if hits exceed the max
+		if remaining < 0 {
+			// Return response with Retry-After header
+			// https://tools.ietf.org/html/rfc6584
+			c.Set(fiber.HeaderRetryAfter, strconv.FormatUint(resetInSec, 10))
+
+			// Call LimitReached handler
+			return cfg.LimitReached(c)
+		}
+
+		// Continue stack for reaching c.Response().StatusCode()
+		// Store err for returning
+		err = c.Next()
+
+		// Check for SkipFailedRequests and SkipSuccessfulRequests
+		if (cfg.SkipSuccessfulRequests && c.Response().StatusCode() < fiber.StatusBadRequest) ||
+			(cfg.SkipFailedRequests && c.Response().StatusCode() >= fiber.StatusBadRequest) {
+			if _, decrErr := storage.IncrBy(currKey, -1, 2*cfg.Expiration); decrErr == nil {
+				remaining++
+			}
+		}
+
+		// We can continue, update RateLimit headers
+		c.Set(xRateLimitLimit, strconv.Itoa(maxRequests))
+		c.Set(xRateLimitRemaining, strconv.Itoa(remaining))
+		c.Set(xRateLimitReset, strconv.FormatUint(resetInSec, 10))
+
+		return err
+	}
+}
@@ -842,3 +842,111 @@ func Test_Sliding_Window(t *testing.T) {
 		singleRequest(true)
 	}
 }
+
+// basicStorage hides the optional storage interfaces of the wrapped storage
+type basicStorage struct {
+	fiber.Storage
+}
+
+// go test -run Test_Limiter_Fixed_Window_Storage -race -v
+func Test_Limiter_Fixed_Window_Storage(t *testing.T) {
+	t.Parallel()
+
+	storages := map[string]fiber.Storage{
+		"atomic": memory.New(),
+		"basic":  basicStorage{Storage: memory.New()},
+	}
+	for name, storage := range storages {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			app := fiber.New()
+			app.Use(New(Config{
+				Max:                    2,
+				Expiration:             2 * time.Second,
+				Storage:                storage,
+				SkipSuccessfulRequests: true,
+			}))
+
+			app.Get("/:status", func(c fiber.Ctx) error {
+				if c.Params("status") == "fail" {
+					return c.SendStatus(fiber.StatusBadRequest)
+				}
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			// Successful requests are not counted
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/success", nil))
+			require.NoError(t, err)
+			require.Equal(t, fiber.StatusOK, resp.StatusCode)
+			require.Equal(t, "2", resp.Header.Get(xRateLimitRemaining))
+
+			resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil))
+			require.NoError(t, err)
+			require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+			require.Equal(t, "1", resp.Header.Get(xRateLimitRemaining))
+
+			resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil))
+			require.NoError(t, err)
+			require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+			require.Equal(t, "0", resp.Header.Get(xRateLimitRemaining))
+
+			resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil))
+			require.NoError(t, err)
+			require.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+			require.NotEmpty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+
+			time.Sleep(3 * time.Second)
+
+			resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil))
+			require.NoError(t, err)
+			require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+			require.Equal(t, "1", resp.Header.Get(xRateLimitRemaining))
+		})
+	}
+}
+
+// go test -run Test_Limiter_Sliding_Window_AtomicStorage -race -v
+func Test_Limiter_Sliding_Window_AtomicStorage(t *testing.T) {
+	t.Parallel()
+
+	// Two instances sharing the same storage count the hits together
+	storage := memory.New()
+	apps := make([]*fiber.App, 2)
+	for i := range apps {
+		apps[i] = fiber.New()
+		apps[i].Use(New(Config{
+			Max:                    2,
+			Expiration:             10 * time.Second,
+			Storage:                storage,
+			LimiterMiddleware:      SlidingWindow{},
+			SkipSuccessfulRequests: true,
+		}))
+		apps[i].Get("/:status", func(c fiber.Ctx) error {
+			if c.Params("status") == "fail" {
+				return c.SendStatus(fiber.StatusBadRequest)
+			}
+			return c.SendStatus(fiber.StatusOK)
+		})
+	}
+
+	// Successful requests are not counted
+	resp, err := apps[0].Test(httptest.NewRequest(fiber.MethodGet, "/success", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "2", resp.Header.Get(xRateLimitRemaining))
+
+	resp, err = apps[0].Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	require.Equal(t, "1", resp.Header.Get(xRateLimitRemaining))
+
+	resp, err = apps[1].Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	require.Equal(t, "0", resp.Header.Get(xRateLimitRemaining))
+
+	resp, err = apps[0].Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	require.NotEmpty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+}
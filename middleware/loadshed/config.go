@@ -0,0 +1,127 @@
+package loadshed
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Priority classifies the request. Under overload low priority requests are
+	// rejected first, critical requests are never rejected.
+	//
+	// Optional. Default: func(c fiber.Ctx) Priority {
+	//   return PriorityNormal
+	// }
+	Priority func(c fiber.Ctx) Priority
+
+	// Rejected is called if the request is shed.
+	//
+	// Optional. Default: func(c fiber.Ctx) error {
+	//   c.Set(fiber.HeaderRetryAfter, "1")
+	//   return c.SendStatus(fiber.StatusServiceUnavailable)
+	// }
+	Rejected fiber.Handler
+
+	// CPU returns the current CPU usage between 0 and 1, e.g. from a system metrics
+	// package. The CPU usage is not monitored if it is nil.
+	//
+	// Optional. Default: nil
+	CPU func() float64
+
+	// LatencyThreshold is the p99 latency of the requests in a Window above which
+	// the service is overloaded. It is disabled if it is negative.
+	//
+	// Optional. Default: 1 * time.Second
+	LatencyThreshold time.Duration
+
+	// MaxInFlight is the number of concurrent requests above which the service is
+	// overloaded. It is disabled if it is 0.
+	//
+	// Optional. Default: 0
+	MaxInFlight int
+
+	// CPUThreshold is the CPU usage above which the service is overloaded.
+	//
+	// Optional. Default: 0.9
+	CPUThreshold float64
+
+	// Window is the interval in which the latency, in-flight requests and CPU usage
+	// are evaluated.
+	//
+	// Optional. Default: 1 * time.Second
+	Window time.Duration
+
+	// Step is the fraction of requests which is additionally rejected after each Window
+	// the service is overloaded, and accepted again after each Window it is not.
+	//
+	// Optional. Default: 0.1
+	Step float64
+
+	// MinSamples is the minimum number of requests in a Window to evaluate their latency.
+	//
+	// Optional. Default: 20
+	MinSamples int
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next: nil,
+	Priority: func(_ fiber.Ctx) Priority {
+		return PriorityNormal
+	},
+	Rejected: func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderRetryAfter, "1")
+		return c.SendStatus(fiber.StatusServiceUnavailable)
+	},
+	LatencyThreshold: 1 * time.Second,
+	MaxInFlight:      0,
+	CPUThreshold:     0.9,
+	Window:           1 * time.Second,
+	Step:             0.1,
+	MinSamples:       20,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Priority == nil {
+		cfg.Priority = ConfigDefault.Priority
+	}
+	if cfg.Rejected == nil {
+		cfg.Rejected = ConfigDefault.Rejected
+	}
+	if cfg.LatencyThreshold == 0 {
+		cfg.LatencyThreshold = ConfigDefault.LatencyThreshold
+	}
+	if cfg.MaxInFlight < 0 {
+		cfg.MaxInFlight = ConfigDefault.MaxInFlight
+	}
+	if cfg.CPUThreshold <= 0 {
+		cfg.CPUThreshold = ConfigDefault.CPUThreshold
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = ConfigDefault.Window
+	}
+	if cfg.Step <= 0 || cfg.Step > 1 {
+		cfg.Step = ConfigDefault.Step
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = ConfigDefault.MinSamples
+	}
+	return cfg
+}
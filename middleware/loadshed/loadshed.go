@@ -0,0 +1,134 @@
+// Package loadshed provides a middleware which rejects a growing fraction of low
+// priority requests while the service is overloaded, so it stays responsive for
+// the remaining requests.
+package loadshed
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Priority is the priority of a request
+type Priority int
+
+// The priorities of requests, low priority requests are rejected first
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityCritical
+)
+
+// maxSamples is the maximum number of latencies kept per window
+const maxSamples = 1024
+
+// shedder tracks the load of the service and the fraction of requests which are rejected
+type shedder struct {
+	windowStart time.Time
+	cfg         *Config
+	samples     []time.Duration // ring buffer of the latencies of the window
+	fraction    atomic.Uint64   // float64 bits of the rejected fraction
+	inFlight    atomic.Int64
+	count       int // number of latencies of the window
+	mu          sync.Mutex
+}
+
+// shed reports whether a request with the priority is rejected. The low priority
+// requests are rejected up to a fraction of 0.5, above that normal requests as well.
+func (s *shedder) shed(priority Priority) bool {
+	f := s.rejected()
+	var probability float64
+	switch priority {
+	case PriorityLow:
+		probability = min(1, 2*f)
+	case PriorityNormal:
+		probability = max(0, 2*f-1)
+	default:
+		return false
+	}
+	return probability > 0 && rand.Float64() < probability //nolint:gosec // The randomness is not security relevant
+}
+
+// rejected returns the fraction of requests which are rejected
+func (s *shedder) rejected() float64 {
+	return math.Float64frombits(s.fraction.Load())
+}
+
+// observe records the latency of a request if sampled is true, and evaluates the load
+// at the end of every window
+func (s *shedder) observe(now time.Time, latency time.Duration, sampled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sampled {
+		s.samples[s.count%maxSamples] = latency
+		s.count++
+	}
+	if now.Sub(s.windowStart) < s.cfg.Window {
+		return
+	}
+
+	f := s.rejected()
+	if s.overloaded() {
+		f = min(1, f+s.cfg.Step)
+	} else {
+		f = max(0, f-s.cfg.Step)
+	}
+	s.fraction.Store(math.Float64bits(f))
+	s.windowStart = now
+	s.count = 0
+}
+
+// overloaded reports whether one of the thresholds is exceeded in the window
+func (s *shedder) overloaded() bool {
+	if s.cfg.MaxInFlight > 0 && s.inFlight.Load() > int64(s.cfg.MaxInFlight) {
+		return true
+	}
+	if s.cfg.CPU != nil && s.cfg.CPU() > s.cfg.CPUThreshold {
+		return true
+	}
+	if s.cfg.LatencyThreshold > 0 && s.count >= s.cfg.MinSamples {
+		samples := slices.Clone(s.samples[:min(s.count, maxSamples)])
+		slices.Sort(samples)
+		p99 := samples[(len(samples)*99+99)/100-1]
+		return p99 > s.cfg.LatencyThreshold
+	}
+	return false
+}
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	s := &shedder{cfg: &cfg, samples: make([]time.Duration, maxSamples), windowStart: time.Now()}
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		if s.shed(cfg.Priority(c)) {
+			// The load is still evaluated, so the service recovers without accepted requests
+			s.observe(time.Now(), 0, false)
+			return cfg.Rejected(c)
+		}
+
+		s.inFlight.Add(1)
+		start := time.Now()
+		defer func() {
+			s.inFlight.Add(-1)
+			now := time.Now()
+			s.observe(now, now.Sub(start), true)
+		}()
+
+		// Continue stack
+		return c.Next()
+	}
+}
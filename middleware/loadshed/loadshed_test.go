@@ -0,0 +1,253 @@
+package loadshed
+
+import (
+	"math"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func newShedder(config Config) *shedder {
+	cfg := configDefault(config)
+	return &shedder{cfg: &cfg, samples: make([]time.Duration, maxSamples)}
+}
+
+// observeWindow records the latencies and ends the window
+func observeWindow(s *shedder, now time.Time, latencies ...time.Duration) time.Time {
+	for _, latency := range latencies {
+		s.observe(now, latency, true)
+	}
+	now = now.Add(s.cfg.Window)
+	s.observe(now, 0, false)
+	return now
+}
+
+func repeat(d time.Duration, n int) []time.Duration {
+	latencies := make([]time.Duration, n)
+	for i := range latencies {
+		latencies[i] = d
+	}
+	return latencies
+}
+
+// go test -run Test_LoadShed_Latency
+func Test_LoadShed_Latency(t *testing.T) {
+	t.Parallel()
+	s := newShedder(Config{LatencyThreshold: 100 * time.Millisecond, Step: 0.5, MinSamples: 10})
+	now := time.Now()
+	s.windowStart = now
+
+	// 1% of slow requests doesn't exceed the p99
+	now = observeWindow(s, now, append(repeat(time.Millisecond, 99), time.Second)...)
+	require.InDelta(t, 0.0, s.rejected(), 0)
+
+	// 2% of slow requests do
+	now = observeWindow(s, now, append(repeat(time.Millisecond, 98), time.Second, time.Second)...)
+	require.InDelta(t, 0.5, s.rejected(), 0)
+	require.True(t, s.shed(PriorityLow))
+	require.False(t, s.shed(PriorityNormal))
+	require.False(t, s.shed(PriorityCritical))
+
+	now = observeWindow(s, now, repeat(time.Second, 10)...)
+	require.InDelta(t, 1.0, s.rejected(), 0)
+	require.True(t, s.shed(PriorityLow))
+	require.True(t, s.shed(PriorityNormal))
+	require.False(t, s.shed(PriorityCritical))
+
+	// Too few samples are not evaluated, so the service recovers
+	now = observeWindow(s, now, repeat(time.Second, 9)...)
+	require.InDelta(t, 0.5, s.rejected(), 0)
+	observeWindow(s, now)
+	require.InDelta(t, 0.0, s.rejected(), 0)
+	require.False(t, s.shed(PriorityLow))
+}
+
+// go test -run Test_LoadShed_Fraction
+func Test_LoadShed_Fraction(t *testing.T) {
+	t.Parallel()
+	s := newShedder(Config{Step: 0.1})
+	for f := 0.1; f < 1; f += 0.1 {
+		s.fraction.Store(math.Float64bits(f))
+		var low, normal int
+		for i := 0; i < 10000; i++ {
+			if s.shed(PriorityLow) {
+				low++
+			}
+			if s.shed(PriorityNormal) {
+				normal++
+			}
+		}
+		require.InDelta(t, min(1, 2*f), float64(low)/10000, 0.05, "fraction %f", f)
+		require.InDelta(t, max(0, 2*f-1), float64(normal)/10000, 0.05, "fraction %f", f)
+	}
+}
+
+// go test -run Test_LoadShed_CPU
+func Test_LoadShed_CPU(t *testing.T) {
+	t.Parallel()
+	var usage atomic.Uint64
+	s := newShedder(Config{
+		LatencyThreshold: -1,
+		CPU: func() float64 {
+			return float64(usage.Load()) / 100
+		},
+		CPUThreshold: 0.8,
+		Step:         0.25,
+	})
+	now := time.Now()
+	s.windowStart = now
+
+	usage.Store(95)
+	now = observeWindow(s, now, repeat(time.Hour, 100)...)
+	require.InDelta(t, 0.25, s.rejected(), 0)
+
+	usage.Store(50)
+	observeWindow(s, now)
+	require.InDelta(t, 0.0, s.rejected(), 0)
+}
+
+// go test -run Test_LoadShed_InFlight
+func Test_LoadShed_InFlight(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	var blocked atomic.Int32
+
+	app := fiber.New()
+	app.Use(New(Config{
+		Priority: func(c fiber.Ctx) Priority {
+			if c.Query("critical") != "" {
+				return PriorityCritical
+			}
+			return PriorityNormal
+		},
+		MaxInFlight: 1,
+		Window:      10 * time.Millisecond,
+		Step:        1,
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		if c.Query("block") != "" {
+			blocked.Add(1)
+			<-release
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	status := func(target string) int {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, target, nil), fiber.TestConfig{Timeout: 0})
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	done := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/?block=1", nil), fiber.TestConfig{Timeout: 0})
+			if assert.NoError(t, err) {
+				done <- resp.StatusCode
+			}
+		}()
+	}
+	require.Eventually(t, func() bool {
+		return blocked.Load() == 2
+	}, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	// Two requests are in flight when the window ends
+	require.Equal(t, fiber.StatusOK, status("/?critical=1"))
+	require.Equal(t, fiber.StatusServiceUnavailable, status("/"))
+	require.Equal(t, fiber.StatusOK, status("/?critical=1"))
+
+	close(release)
+	require.Equal(t, fiber.StatusOK, <-done)
+	require.Equal(t, fiber.StatusOK, <-done)
+
+	// The service recovers after the next window
+	require.Eventually(t, func() bool {
+		return status("/") == fiber.StatusOK
+	}, time.Second, 5*time.Millisecond)
+}
+
+// go test -run Test_LoadShed_Rejected
+func Test_LoadShed_Rejected(t *testing.T) {
+	t.Parallel()
+	handler := New(Config{
+		Priority: func(_ fiber.Ctx) Priority {
+			return PriorityLow
+		},
+		LatencyThreshold: time.Nanosecond,
+		Window:           time.Nanosecond,
+		MinSamples:       1,
+		Step:             1,
+	})
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		time.Sleep(time.Millisecond)
+		return c.SendStatus(fiber.StatusOK)
+	}, handler)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, "1", resp.Header.Get(fiber.HeaderRetryAfter))
+
+	app = fiber.New()
+	app.Use(New(Config{
+		LatencyThreshold: time.Nanosecond,
+		MinSamples:       1,
+		Step:             1,
+		Window:           time.Nanosecond,
+		Rejected: func(c fiber.Ctx) error {
+			return c.Status(fiber.StatusTooManyRequests).SendString("overloaded")
+		},
+		Next: func(c fiber.Ctx) bool {
+			return c.Path() == "/health"
+		},
+	}))
+	app.Get("/*", func(c fiber.Ctx) error {
+		time.Sleep(time.Millisecond)
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	// The rejected request ends a window without latencies, so the next request is accepted again
+	var statuses []int
+	for i := 0; i < 3; i++ {
+		resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		require.NoError(t, err)
+		statuses = append(statuses, resp.StatusCode)
+	}
+	require.Equal(t, []int{fiber.StatusOK, fiber.StatusTooManyRequests, fiber.StatusOK}, statuses)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/health", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+// go test -v -run=^$ -bench=Benchmark_LoadShed -benchmem -count=4
+func Benchmark_LoadShed(b *testing.B) {
+	app := fiber.New()
+	app.Use(New())
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	h := app.Handler()
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI("/")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		h(ctx)
+	}
+}
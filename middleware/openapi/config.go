@@ -0,0 +1,148 @@
+package openapi
+
+import (
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Operations annotates the routes in the document. The key is either the name of
+	// the route, or its method and path, e.g. "GET /users/:id".
+	//
+	// Optional. Default: nil
+	Operations map[string]Operation
+
+	// Title of the API.
+	//
+	// Optional. Default: the AppName of the app, or "Fiber API"
+	Title string
+
+	// Version of the API.
+	//
+	// Optional. Default: "1.0.0"
+	Version string
+
+	// Description of the API.
+	//
+	// Optional. Default: ""
+	Description string
+
+	// Path is the path the OpenAPI document is served at.
+	//
+	// Optional. Default: "/openapi.json"
+	Path string
+
+	// UIPath is the path the documentation UI is served at.
+	//
+	// Optional. Default: "/docs"
+	UIPath string
+
+	// UI is the documentation UI, either "swagger" for Swagger UI or "redoc" for Redoc.
+	//
+	// Optional. Default: "swagger"
+	UI string
+
+	// Servers are the URLs of the servers of the API, e.g. "https://api.example.com".
+	//
+	// Optional. Default: nil
+	Servers []string
+
+	// DisableUI disables the documentation UI, only the document is served.
+	//
+	// Optional. Default: false
+	DisableUI bool
+}
+
+// MetaKey is the key of the route metadata with the Operation of the route, which is
+// used if the route isn't annotated by the Operations of the config:
+//
+//	app.Get("/users", handler).Meta(openapi.MetaKey, openapi.Operation{Summary: "List users"})
+const MetaKey = "openapi"
+
+// Operation annotates a route in the OpenAPI document
+type Operation struct {
+	// Parameters is a struct whose fields with a query, header or cookie tag, like
+	// the structs of the Bind methods, are documented as parameters.
+	Parameters any
+
+	// Request is a value of the type of the request body, e.g. a struct which is
+	// bound with c.Bind().Body().
+	Request any
+
+	// Responses are the responses by status code. If there are none, a default
+	// response is documented.
+	Responses map[int]Response
+
+	// OperationID identifies the operation. Default: the name of the route
+	OperationID string
+
+	// Summary of the operation
+	Summary string
+
+	// Description of the operation
+	Description string
+
+	// RequestContentType is the content type of the Request. Default: "application/json"
+	RequestContentType string
+
+	// Tags group the operations in the UI
+	Tags []string
+
+	// Deprecated marks the operation as deprecated
+	Deprecated bool
+
+	// Hidden excludes the route from the document
+	Hidden bool
+}
+
+// Response is a response of an Operation
+type Response struct {
+	// Body is a value of the type of the response body, e.g. a struct which is sent with c.JSON.
+	Body any
+
+	// Description of the response. Default: the status text of the status code
+	Description string
+
+	// ContentType of the Body. Default: "application/json"
+	ContentType string
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:    nil,
+	Version: "1.0.0",
+	Path:    "/openapi.json",
+	UIPath:  "/docs",
+	UI:      "swagger",
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Version == "" {
+		cfg.Version = ConfigDefault.Version
+	}
+	if cfg.Path == "" {
+		cfg.Path = ConfigDefault.Path
+	}
+	if cfg.UIPath == "" {
+		cfg.UIPath = ConfigDefault.UIPath
+	}
+	if cfg.UI == "" {
+		cfg.UI = ConfigDefault.UI
+	}
+	return cfg
+}
@@ -0,0 +1,333 @@
+// Package openapi provides a middleware which generates an OpenAPI 3 document from
+// the routes of the app and serves it with a Swagger UI or Redoc page.
+package openapi
+
+import (
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// methods are the methods supported by OpenAPI path items
+var methods = map[string]struct{}{
+	fiber.MethodGet:     {},
+	fiber.MethodHead:    {},
+	fiber.MethodPost:    {},
+	fiber.MethodPut:     {},
+	fiber.MethodPatch:   {},
+	fiber.MethodDelete:  {},
+	fiber.MethodOptions: {},
+	fiber.MethodTrace:   {},
+}
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	if cfg.UI != "swagger" && cfg.UI != "redoc" {
+		panic("openapi: unsupported UI " + cfg.UI)
+	}
+
+	var (
+		once sync.Once
+		spec []byte
+		err  error
+	)
+	page := uiPage(&cfg)
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return c.Next()
+		}
+
+		switch c.Path() {
+		case cfg.Path:
+			// The document is generated on the first request, when all routes are registered
+			once.Do(func() {
+				spec, err = c.App().Config().JSONEncoder(generate(c.App(), &cfg))
+			})
+			if err != nil {
+				return err
+			}
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+			return c.Send(spec)
+		case cfg.UIPath:
+			if cfg.DisableUI {
+				return c.Next()
+			}
+			c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+			return c.SendString(page)
+		default:
+			return c.Next()
+		}
+	}
+}
+
+// Spec returns the OpenAPI document of the routes of the app as JSON, e.g. to write it
+// to a file or to serve it without the middleware. Only the document fields of the config are used.
+// The document is encoded with the JSONEncoder of the app.
+func Spec(app *fiber.App, config ...Config) ([]byte, error) {
+	cfg := configDefault(config...)
+	return app.Config().JSONEncoder(generate(app, &cfg))
+}
+
+// generate returns the OpenAPI document of the routes of the app
+func generate(app *fiber.App, cfg *Config) *document {
+	title := cfg.Title
+	if title == "" {
+		title = app.Config().AppName
+	}
+	if title == "" {
+		title = "Fiber API"
+	}
+
+	doc := &document{
+		OpenAPI: "3.0.3",
+		Info:    info{Title: title, Description: cfg.Description, Version: cfg.Version},
+		Paths:   make(map[string]map[string]*operation),
+	}
+	for _, url := range cfg.Servers {
+		doc.Servers = append(doc.Servers, server{URL: url})
+	}
+
+	routes := app.GetRoutes(true)
+	// HEAD routes are registered automatically for GET routes
+	gets := make(map[string]struct{})
+	for _, route := range routes {
+		if route.Method == fiber.MethodGet {
+			gets[route.Path] = struct{}{}
+		}
+	}
+
+	g := newSchemaGenerator()
+	for i := range routes {
+		route := &routes[i]
+		if _, ok := methods[route.Method]; !ok {
+			continue
+		}
+		if _, ok := gets[route.Path]; ok && route.Method == fiber.MethodHead {
+			continue
+		}
+		op, ok := cfg.Operations[route.Name]
+		if !ok || route.Name == "" {
+			op, ok = cfg.Operations[route.Method+" "+route.Path]
+		}
+		if !ok {
+			op, _ = route.Meta[MetaKey].(Operation) //nolint:errcheck // Routes without an Operation are documented with defaults
+		}
+		if op.Hidden {
+			continue
+		}
+
+		for _, variant := range pathVariants(route.Segments()) {
+			item, ok := doc.Paths[variant.path]
+			if !ok {
+				item = make(map[string]*operation)
+				doc.Paths[variant.path] = item
+			}
+			item[strings.ToLower(route.Method)] = newOperation(g, route, &op, variant.params)
+		}
+	}
+
+	if len(g.schemas) > 0 {
+		doc.Components = &components{Schemas: g.schemas}
+	}
+	return doc
+}
+
+// newOperation returns the operation of the route
+func newOperation(g *schemaGenerator, route *fiber.Route, op *Operation, pathParams []*parameter) *operation {
+	o := &operation{
+		OperationID: op.OperationID,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Deprecated:  op.Deprecated,
+		Parameters:  pathParams,
+		Responses:   make(map[string]*response),
+	}
+	if o.OperationID == "" {
+		o.OperationID = route.Name
+	}
+	if op.Parameters != nil {
+		o.Parameters = append(o.Parameters, g.parameters(op.Parameters)...)
+	}
+	if op.Request != nil {
+		contentType := op.RequestContentType
+		if contentType == "" {
+			contentType = fiber.MIMEApplicationJSON
+		}
+		o.RequestBody = &requestBody{
+			Required: true,
+			Content:  map[string]*mediaType{contentType: {Schema: g.schemaOf(op.Request)}},
+		}
+	}
+
+	for status, resp := range op.Responses {
+		r := &response{Description: resp.Description}
+		if r.Description == "" {
+			r.Description = http.StatusText(status)
+		}
+		if resp.Body != nil {
+			contentType := resp.ContentType
+			if contentType == "" {
+				contentType = fiber.MIMEApplicationJSON
+			}
+			r.Content = map[string]*mediaType{contentType: {Schema: g.schemaOf(resp.Body)}}
+		}
+		o.Responses[strconv.Itoa(status)] = r
+	}
+	if len(o.Responses) == 0 {
+		o.Responses["default"] = &response{Description: "Default response"}
+	}
+	return o
+}
+
+// pathVariant is an OpenAPI path template of a route with its path parameters
+type pathVariant struct {
+	path   string
+	params []*parameter
+}
+
+// pathVariants returns the path templates of the route segments. OpenAPI doesn't support
+// optional path parameters, so a route with an optional last parameter has two paths.
+func pathVariants(segments []fiber.PathSegment) []pathVariant {
+	var variants []pathVariant
+	if n := len(segments); n > 0 && segments[n-1].IsParam && segments[n-1].IsOptional {
+		path, params := pathTemplate(segments[:n-1])
+		if len(path) > 1 {
+			path = strings.TrimSuffix(path, "/")
+		}
+		variants = append(variants, pathVariant{path: path, params: params})
+	}
+	path, params := pathTemplate(segments)
+	return append(variants, pathVariant{path: path, params: params})
+}
+
+// pathTemplate returns the path template and path parameters of the segments
+func pathTemplate(segments []fiber.PathSegment) (string, []*parameter) {
+	var params []*parameter
+	for _, seg := range segments {
+		if seg.IsParam {
+			params = append(params, &parameter{
+				Name:     seg.TemplateName(),
+				In:       "path",
+				Required: true,
+				Schema:   constraintSchema(seg.Constraints),
+			})
+		}
+	}
+	return fiber.TemplatePath(segments), params
+}
+
+// constraintSchema returns the schema of a path parameter with the constraints
+func constraintSchema(constraints []*fiber.Constraint) *schema {
+	s := &schema{Type: "string"}
+	number := func(i int, c *fiber.Constraint) *float64 {
+		if i >= len(c.Data) {
+			return nil
+		}
+		n, err := strconv.ParseFloat(c.Data[i], 64)
+		if err != nil {
+			return nil
+		}
+		return &n
+	}
+	length := func(i int, c *fiber.Constraint) *int {
+		if i >= len(c.Data) {
+			return nil
+		}
+		n, err := strconv.Atoi(c.Data[i])
+		if err != nil {
+			return nil
+		}
+		return &n
+	}
+
+	for _, c := range constraints {
+		switch strings.ToLower(c.Name) {
+		case fiber.ConstraintInt:
+			s.Type = "integer"
+		case fiber.ConstraintBool:
+			s.Type = "boolean"
+		case fiber.ConstraintFloat:
+			s.Type = "number"
+		case fiber.ConstraintAlpha:
+			s.Pattern = "^[a-zA-Z]+$"
+		case fiber.ConstraintGUID:
+			s.Format = "uuid"
+		case fiber.ConstraintMinLenLower:
+			s.MinLength = length(0, c)
+		case fiber.ConstraintMaxLenLower:
+			s.MaxLength = length(0, c)
+		case fiber.ConstraintLen:
+			s.MinLength, s.MaxLength = length(0, c), length(0, c)
+		case fiber.ConstraintBetweenLenLower:
+			s.MinLength, s.MaxLength = length(0, c), length(1, c)
+		case fiber.ConstraintMin:
+			s.Type, s.Minimum = "integer", number(0, c)
+		case fiber.ConstraintMax:
+			s.Type, s.Maximum = "integer", number(0, c)
+		case fiber.ConstraintRange:
+			s.Type, s.Minimum, s.Maximum = "integer", number(0, c), number(1, c)
+		case fiber.ConstraintRegex:
+			if len(c.Data) > 0 {
+				s.Pattern = c.Data[0]
+			}
+		}
+	}
+	return s
+}
+
+// uiPage returns the HTML page of the documentation UI
+func uiPage(cfg *Config) string {
+	title := html.EscapeString(cfg.Title)
+	if title == "" {
+		title = "API Documentation"
+	}
+	spec := html.EscapeString(cfg.Path)
+	if cfg.UI == "redoc" {
+		return `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>` + title + `</title>
+</head>
+<body>
+<redoc spec-url="` + spec + `"></redoc>
+<script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+</body>
+</html>`
+	}
+	return `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>` + title + `</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui" data-url="` + spec + `"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+<script>
+window.onload = function () {
+  var el = document.getElementById("swagger-ui");
+  SwaggerUIBundle({ url: el.dataset.url, domNode: el });
+};
+</script>
+</body>
+</html>`
+}
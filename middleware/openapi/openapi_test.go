@@ -0,0 +1,340 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type user struct {
+	Created time.Time `json:"created"`
+	Manager *user     `json:"manager,omitempty"`
+	Labels  map[string]string
+	Name    string   `json:"name" validate:"required,min=1"`
+	Email   string   `json:"email,omitempty"`
+	Roles   []string `json:"roles"`
+	Age     int      `json:"age"`
+	secret  string
+	Ignored string `json:"-"`
+}
+
+type createUser struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type listUsers struct {
+	Page    int    `query:"page"`
+	Search  string `query:"q" validate:"required"`
+	TraceID string `header:"X-Trace-ID"`
+	Session string `cookie:"session"`
+}
+
+func newApp(config ...Config) *fiber.App {
+	app := fiber.New()
+	app.Use(New(config...))
+
+	handler := func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	}
+	app.Get("/users", handler).Name("listUsers")
+	app.Post("/users", handler)
+	app.Get("/users/:id<int;min(1)>", handler).Name("getUser")
+	app.Delete("/users/:id<int>", handler)
+	app.Get("/files/*", handler)
+	app.Get("/flights/:from-:to", handler)
+	app.Get("/posts/:slug?", handler)
+	app.Get("/codes/:code<guid>/:name<alpha;betweenLen(2,8)>", handler)
+	app.Connect("/tunnel", handler)
+	app.Get("/internal", handler)
+	return app
+}
+
+func getDocument(t *testing.T, app *fiber.App) map[string]any {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/openapi.json", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, fiber.MIMEApplicationJSONCharsetUTF8, resp.Header.Get(fiber.HeaderContentType))
+	var doc map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+	return doc
+}
+
+// lookup returns the value at the path of nested maps
+func lookup(t *testing.T, v any, path ...string) any {
+	t.Helper()
+	for _, key := range path {
+		m, ok := v.(map[string]any)
+		require.True(t, ok, "%s is not an object", key)
+		v, ok = m[key]
+		require.True(t, ok, "%s is missing", key)
+	}
+	return v
+}
+
+// jsonOf returns the JSON of the value for comparisons
+func jsonOf(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(b)
+}
+
+// go test -run Test_OpenAPI
+func Test_OpenAPI(t *testing.T) {
+	t.Parallel()
+	app := newApp(Config{
+		Title:       "Users API",
+		Description: "Manages users",
+		Servers:     []string{"https://api.example.com"},
+		Operations: map[string]Operation{
+			"listUsers": {
+				Summary:    "List users",
+				Tags:       []string{"users"},
+				Parameters: listUsers{},
+				Responses: map[int]Response{
+					fiber.StatusOK: {Body: []user{}},
+				},
+			},
+			"POST /users": {
+				Summary: "Create a user",
+				Request: createUser{},
+				Responses: map[int]Response{
+					fiber.StatusCreated:    {Body: &user{}, Description: "The created user"},
+					fiber.StatusBadRequest: {},
+				},
+			},
+			"getUser": {
+				Summary:     "Get a user",
+				OperationID: "userByID",
+				Deprecated:  true,
+			},
+			"GET /internal": {Hidden: true},
+		},
+	})
+	doc := getDocument(t, app)
+
+	require.Equal(t, "3.0.3", doc["openapi"])
+	require.JSONEq(t, `{"title":"Users API","description":"Manages users","version":"1.0.0"}`, jsonOf(t, doc["info"]))
+	require.JSONEq(t, `[{"url":"https://api.example.com"}]`, jsonOf(t, doc["servers"]))
+
+	paths, ok := doc["paths"].(map[string]any)
+	require.True(t, ok)
+	var keys []string
+	for path := range paths {
+		keys = append(keys, path)
+	}
+	require.ElementsMatch(t, []string{
+		"/users", "/users/{id}", "/files", "/files/{wildcard}", "/flights/{from}-{to}",
+		"/posts", "/posts/{slug}", "/codes/{code}/{name}",
+	}, keys)
+
+	// HEAD routes of GET routes and CONNECT routes are not documented
+	require.JSONEq(t, `["get","post"]`, jsonOf(t, sortedKeys(t, paths["/users"])))
+	require.JSONEq(t, `["delete","get"]`, jsonOf(t, sortedKeys(t, paths["/users/{id}"])))
+
+	require.JSONEq(t, `{
+		"operationId": "listUsers",
+		"summary": "List users",
+		"tags": ["users"],
+		"parameters": [
+			{"name": "page", "in": "query", "schema": {"type": "integer", "format": "int64"}},
+			{"name": "q", "in": "query", "required": true, "schema": {"type": "string"}},
+			{"name": "X-Trace-ID", "in": "header", "schema": {"type": "string"}},
+			{"name": "session", "in": "cookie", "schema": {"type": "string"}}
+		],
+		"responses": {
+			"200": {
+				"description": "OK",
+				"content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/user"}}}}
+			}
+		}
+	}`, jsonOf(t, lookup(t, paths, "/users", "get")))
+
+	require.JSONEq(t, `{
+		"summary": "Create a user",
+		"requestBody": {
+			"required": true,
+			"content": {"application/json": {"schema": {"$ref": "#/components/schemas/createUser"}}}
+		},
+		"responses": {
+			"201": {
+				"description": "The created user",
+				"content": {"application/json": {"schema": {"$ref": "#/components/schemas/user"}}}
+			},
+			"400": {"description": "Bad Request"}
+		}
+	}`, jsonOf(t, lookup(t, paths, "/users", "post")))
+
+	require.JSONEq(t, `{
+		"operationId": "userByID",
+		"summary": "Get a user",
+		"deprecated": true,
+		"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "minimum": 1}}],
+		"responses": {"default": {"description": "Default response"}}
+	}`, jsonOf(t, lookup(t, paths, "/users/{id}", "get")))
+
+	require.JSONEq(t, `{
+		"user": {
+			"type": "object",
+			"properties": {
+				"created": {"type": "string", "format": "date-time"},
+				"manager": {"$ref": "#/components/schemas/user"},
+				"Labels": {"type": "object", "additionalProperties": {"type": "string"}},
+				"name": {"type": "string"},
+				"email": {"type": "string"},
+				"roles": {"type": "array", "items": {"type": "string"}},
+				"age": {"type": "integer", "format": "int64"}
+			},
+			"required": ["name"]
+		},
+		"createUser": {
+			"type": "object",
+			"properties": {"name": {"type": "string"}},
+			"required": ["name"]
+		}
+	}`, jsonOf(t, lookup(t, doc, "components", "schemas")))
+}
+
+func sortedKeys(t *testing.T, v any) []string {
+	t.Helper()
+	m, ok := v.(map[string]any)
+	require.True(t, ok)
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// go test -run Test_OpenAPI_PathParams
+func Test_OpenAPI_PathParams(t *testing.T) {
+	t.Parallel()
+	paths, ok := getDocument(t, newApp())["paths"].(map[string]any)
+	require.True(t, ok)
+
+	require.JSONEq(t, `[
+		{"name": "code", "in": "path", "required": true, "schema": {"type": "string", "format": "uuid"}},
+		{"name": "name", "in": "path", "required": true, "schema": {"type": "string", "pattern": "^[a-zA-Z]+$", "minLength": 2, "maxLength": 8}}
+	]`, jsonOf(t, lookup(t, paths, "/codes/{code}/{name}", "get", "parameters")))
+
+	require.JSONEq(t, `[
+		{"name": "from", "in": "path", "required": true, "schema": {"type": "string"}},
+		{"name": "to", "in": "path", "required": true, "schema": {"type": "string"}}
+	]`, jsonOf(t, lookup(t, paths, "/flights/{from}-{to}", "get", "parameters")))
+
+	// Optional parameters are documented with and without the parameter
+	require.NotContains(t, lookup(t, paths, "/posts", "get"), "parameters")
+	require.JSONEq(t, `[{"name": "slug", "in": "path", "required": true, "schema": {"type": "string"}}]`,
+		jsonOf(t, lookup(t, paths, "/posts/{slug}", "get", "parameters")))
+	require.JSONEq(t, `[{"name": "wildcard", "in": "path", "required": true, "schema": {"type": "string"}}]`,
+		jsonOf(t, lookup(t, paths, "/files/{wildcard}", "get", "parameters")))
+
+	// The title defaults to the app name
+	require.Equal(t, "Fiber API", lookup(t, getDocument(t, newApp()), "info", "title"))
+}
+
+// go test -run Test_OpenAPI_Spec
+func Test_OpenAPI_Spec(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	handler := func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	}
+	app.Get("/users", handler).Meta(MetaKey, Operation{Summary: "List users", Tags: []string{"users"}})
+	app.Get("/users/:id<int>", handler).Name("getUser").Meta(MetaKey, Operation{Summary: "From metadata"})
+	app.Get("/internal", handler).Meta(MetaKey, Operation{Hidden: true})
+
+	spec, err := Spec(app, Config{
+		Title: "Users API",
+		Operations: map[string]Operation{
+			"getUser": {Summary: "Get a user"},
+		},
+	})
+	require.NoError(t, err)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(spec, &doc))
+	require.Equal(t, "3.0.3", doc["openapi"])
+	require.Equal(t, "Users API", lookup(t, doc, "info", "title"))
+	require.Equal(t, "1.0.0", lookup(t, doc, "info", "version"))
+	require.Equal(t, "List users", lookup(t, doc, "paths", "/users", "get", "summary"))
+	require.Equal(t, []any{"users"}, lookup(t, doc, "paths", "/users", "get", "tags"))
+	// The Operations of the config take precedence over the metadata
+	require.Equal(t, "Get a user", lookup(t, doc, "paths", "/users/{id}", "get", "summary"))
+	require.Equal(t, []string{"/users", "/users/{id}"}, sortedKeys(t, doc["paths"]))
+}
+
+// go test -run Test_OpenAPI_UI
+func Test_OpenAPI_UI(t *testing.T) {
+	t.Parallel()
+	for ui, script := range map[string]string{
+		"swagger": "swagger-ui-bundle.js",
+		"redoc":   "redoc.standalone.js",
+	} {
+		app := newApp(Config{UI: ui, UIPath: "/reference", Path: "/spec.json", Title: "<Users>"})
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/reference", nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		require.Equal(t, fiber.MIMETextHTMLCharsetUTF8, resp.Header.Get(fiber.HeaderContentType))
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Contains(t, string(body), script)
+		require.Contains(t, string(body), `"/spec.json"`)
+		require.Contains(t, string(body), "<title>&lt;Users&gt;</title>")
+
+		resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/spec.json", nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	}
+
+	app := newApp(Config{DisableUI: true})
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/docs", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	// Other methods are passed to the next handler
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodPost, "/openapi.json", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	require.Panics(t, func() {
+		New(Config{UI: "unknown"})
+	})
+}
+
+// go test -run Test_OpenAPI_Next
+func Test_OpenAPI_Next(t *testing.T) {
+	t.Parallel()
+	app := newApp(Config{
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/openapi.json", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+// go test -v -run=^$ -bench=Benchmark_OpenAPI -benchmem -count=4
+func Benchmark_OpenAPI(b *testing.B) {
+	app := newApp()
+	h := app.Handler()
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI("/openapi.json")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		h(ctx)
+	}
+}
@@ -0,0 +1,168 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaGenerator generates the schemas of Go types, named structs are added to the components
+type schemaGenerator struct {
+	schemas map[string]*schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{schemas: make(map[string]*schema), names: make(map[reflect.Type]string)}
+}
+
+// schemaOf returns the schema of the type of the value
+func (g *schemaGenerator) schemaOf(v any) *schema {
+	return g.schema(reflect.TypeOf(v))
+}
+
+// schema returns the schema of the type
+func (g *schemaGenerator) schema(t reflect.Type) *schema {
+	if t == nil {
+		return &schema{}
+	}
+	if t.Kind() == reflect.Pointer {
+		s := g.schema(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	}
+	if t == timeType {
+		return &schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &schema{Type: "string", Format: "byte"}
+		}
+		return &schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return &schema{Ref: "#/components/schemas/" + g.component(t)}
+	default:
+		// Interfaces and other types can have any value
+		return &schema{}
+	}
+}
+
+// component adds the schema of the named struct to the components and returns its name
+func (g *schemaGenerator) component(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	for i := 2; g.schemas[name] != nil; i++ {
+		name = t.Name() + strconv.Itoa(i)
+	}
+	// Register the name first, so recursive types reference themselves
+	g.names[t] = name
+	g.schemas[name] = &schema{}
+	*g.schemas[name] = *g.structSchema(t)
+	return name
+}
+
+// structSchema returns the object schema of the struct, its fields are named like encoding/json
+func (g *schemaGenerator) structSchema(t reflect.Type) *schema {
+	s := &schema{Type: "object", Properties: make(map[string]*schema)}
+	g.addFields(s, t)
+	return s
+}
+
+func (g *schemaGenerator) addFields(s *schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		// The fields of embedded structs are promoted
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(s, ft)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = g.schema(field.Type)
+		if isRequired(field) {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// isRequired reports whether the field has a validate:"required" tag
+func isRequired(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// parameters returns the parameters of the fields of the struct with a query, header or cookie tag
+func (g *schemaGenerator) parameters(v any) []*parameter {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var params []*parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		for _, in := range []string{"query", "header", "cookie"} {
+			name, _, _ := strings.Cut(field.Tag.Get(in), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			params = append(params, &parameter{
+				Name:     name,
+				In:       in,
+				Required: isRequired(field),
+				Schema:   g.schema(field.Type),
+			})
+		}
+	}
+	return params
+}
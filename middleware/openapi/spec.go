@@ -0,0 +1,73 @@
+package openapi
+
+// The objects of the OpenAPI 3.0 specification, see https://spec.openapis.org/oas/v3.0.3
+
+type document struct {
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components *components                      `json:"components,omitempty"`
+	Info       info                             `json:"info"`
+	OpenAPI    string                           `json:"openapi"`
+	Servers    []server                         `json:"servers,omitempty"`
+}
+
+type info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type server struct {
+	URL string `json:"url"`
+}
+
+type operation struct {
+	RequestBody *requestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*response `json:"responses"`
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*parameter         `json:"parameters,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+}
+
+type parameter struct {
+	Schema   *schema `json:"schema"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+}
+
+type requestBody struct {
+	Content  map[string]*mediaType `json:"content"`
+	Required bool                  `json:"required"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type response struct {
+	Content     map[string]*mediaType `json:"content,omitempty"`
+	Description string                `json:"description"`
+}
+
+type components struct {
+	Schemas map[string]*schema `json:"schemas"`
+}
+
+type schema struct {
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+}
@@ -0,0 +1,79 @@
+package recorder
+
+import (
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Dir is the directory the recordings are written to, one JSON file per request.
+	// It is created if it doesn't exist.
+	//
+	// Optional. Default: "./recordings"
+	Dir string
+
+	// RedactHeaders are the request and response headers whose values are replaced
+	// with Redacted.
+	//
+	// Optional. Default: Authorization, Proxy-Authorization, Cookie, Set-Cookie, X-Api-Key
+	RedactHeaders []string
+
+	// RedactQuery are the query and form parameters whose values are replaced with Redacted.
+	//
+	// Optional. Default: nil
+	RedactQuery []string
+
+	// RedactFields are the fields of JSON request and response bodies, at any depth,
+	// whose values are replaced with Redacted, e.g. "password".
+	//
+	// Optional. Default: nil
+	RedactFields []string
+
+	// MaxBodySize is the maximum size of a recorded request or response body, bodies
+	// which are larger are not recorded.
+	//
+	// Optional. Default: 1048576 (1 MiB)
+	MaxBodySize int
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next: nil,
+	Dir:  "./recordings",
+	RedactHeaders: []string{
+		fiber.HeaderAuthorization,
+		fiber.HeaderProxyAuthorization,
+		fiber.HeaderCookie,
+		fiber.HeaderSetCookie,
+		"X-Api-Key",
+	},
+	MaxBodySize: 1024 * 1024,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Dir == "" {
+		cfg.Dir = ConfigDefault.Dir
+	}
+	if cfg.RedactHeaders == nil {
+		cfg.RedactHeaders = ConfigDefault.RedactHeaders
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = ConfigDefault.MaxBodySize
+	}
+	return cfg
+}
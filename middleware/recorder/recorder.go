@@ -0,0 +1,242 @@
+// Package recorder records the request and response pairs of a running app to files,
+// with redaction rules for secrets, and replays them against an app in tests with
+// diffing of the responses, for regression tests of refactored handlers.
+package recorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/utils/v2"
+)
+
+// Redacted replaces the values of redacted headers, parameters and fields. Replay
+// ignores differences in values which were redacted in the recording.
+const Redacted = "[REDACTED]"
+
+// Recording is a recorded request and response pair
+type Recording struct {
+	Time     time.Time `json:"time"`
+	Request  Request   `json:"request"`
+	Response Response  `json:"response"`
+}
+
+// Request is a recorded request
+type Request struct {
+	Header map[string][]string `json:"header"`
+	Method string              `json:"method"`
+	URL    string              `json:"url"` // Path and query
+	Body   Body                `json:"body"`
+}
+
+// Response is a recorded response
+type Response struct {
+	Header map[string][]string `json:"header"`
+	Body   Body                `json:"body"`
+	Status int                 `json:"status"`
+}
+
+// Body is a recorded body, binary bodies are base64 encoded
+type Body struct {
+	Data      string `json:"data"`
+	Encoding  string `json:"encoding,omitempty"`  // "base64" for binary bodies
+	Truncated bool   `json:"truncated,omitempty"` // The body was larger than MaxBodySize or a stream
+}
+
+// sequence orders the recordings of the same nanosecond
+var sequence atomic.Uint64
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+		panic(fmt.Errorf("recorder: failed to create directory %q: %w", cfg.Dir, err))
+	}
+	r := newRedactor(&cfg)
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		// The request is copied before the handlers, which may modify it
+		rec := Recording{
+			Time: time.Now(),
+			Request: Request{
+				Method: utils.CopyString(c.Method()),
+				URL:    r.url(c.OriginalURL()),
+				Header: r.headers(c.GetReqHeaders()),
+				Body:   r.body(c.Body(), c.Get(fiber.HeaderContentType), false, cfg.MaxBodySize),
+			},
+		}
+
+		// Manually call error handler, so the response is complete
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError) //nolint:errcheck // Always return nil
+			}
+		}
+
+		rec.Response = Response{
+			Status: c.Response().StatusCode(),
+			Header: r.headers(c.GetRespHeaders()),
+			Body: r.body(c.Response().Body(), string(c.Response().Header.ContentType()),
+				c.Response().IsBodyStream(), cfg.MaxBodySize),
+		}
+		if err := write(cfg.Dir, &rec); err != nil {
+			log.Errorf("[RECORDER] failed to write recording: %v", err)
+		}
+		return nil
+	}
+}
+
+// write writes the recording to a new file in the directory
+func write(dir string, rec *Recording) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode recording: %w", err)
+	}
+	name := fmt.Sprintf("%d-%06d-%s%s.json", rec.Time.UnixNano(), sequence.Add(1)%1e6,
+		rec.Request.Method, fileSafe(rec.Request.URL))
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
+
+// fileSafe returns the path of the URL with the characters which aren't letters or
+// digits replaced by dashes, limited to 64 characters
+func fileSafe(rawURL string) string {
+	path, _, _ := strings.Cut(rawURL, "?")
+	b := make([]byte, 0, len(path))
+	for i := 0; i < len(path) && len(b) < 64; i++ {
+		ch := path[i]
+		if ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' {
+			b = append(b, ch)
+		} else if len(b) == 0 || b[len(b)-1] != '-' {
+			b = append(b, '-')
+		}
+	}
+	return strings.TrimRight(string(b), "-")
+}
+
+// redactor applies the redaction rules of the config
+type redactor struct {
+	headerKeys map[string]struct{}
+	queryKeys  map[string]struct{}
+	fieldKeys  map[string]struct{}
+}
+
+func newRedactor(cfg *Config) *redactor {
+	set := func(keys []string) map[string]struct{} {
+		m := make(map[string]struct{}, len(keys))
+		for _, key := range keys {
+			m[utils.ToLower(key)] = struct{}{}
+		}
+		return m
+	}
+	return &redactor{
+		headerKeys: set(cfg.RedactHeaders),
+		queryKeys:  set(cfg.RedactQuery),
+		fieldKeys:  set(cfg.RedactFields),
+	}
+}
+
+// headers returns a copy of the headers with redacted values
+func (r *redactor) headers(header map[string][]string) map[string][]string {
+	redacted := make(map[string][]string, len(header))
+	for key, values := range header {
+		_, redact := r.headerKeys[utils.ToLower(key)]
+		copied := make([]string, len(values))
+		for i, v := range values {
+			if redact {
+				copied[i] = Redacted
+			} else {
+				copied[i] = utils.CopyString(v)
+			}
+		}
+		redacted[utils.CopyString(key)] = copied
+	}
+	return redacted
+}
+
+// url returns a copy of the URL with redacted query parameters
+func (r *redactor) url(rawURL string) string {
+	path, query, found := strings.Cut(rawURL, "?")
+	if !found || len(r.queryKeys) == 0 {
+		return utils.CopyString(rawURL)
+	}
+	return utils.CopyString(path) + "?" + r.values(query)
+}
+
+// values returns the URL encoded values with redacted parameters
+func (r *redactor) values(encoded string) string {
+	values, err := url.ParseQuery(encoded)
+	if err != nil {
+		return utils.CopyString(encoded)
+	}
+	for key := range values {
+		if _, ok := r.queryKeys[utils.ToLower(key)]; ok {
+			for i := range values[key] {
+				values[key][i] = Redacted
+			}
+		}
+	}
+	return values.Encode()
+}
+
+// body returns the recorded body, the fields of JSON bodies and the parameters of form
+// bodies are redacted
+func (r *redactor) body(data []byte, contentType string, stream bool, maxSize int) Body {
+	if stream || len(data) > maxSize {
+		return Body{Truncated: true}
+	}
+	mediaType := utils.ToLower(utils.Trim(strings.Split(contentType, ";")[0], ' '))
+	switch {
+	case len(r.fieldKeys) > 0 && (mediaType == fiber.MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json")):
+		var v any
+		if err := json.Unmarshal(data, &v); err == nil {
+			if redacted, err := json.Marshal(r.redactJSON(v)); err == nil {
+				return Body{Data: string(redacted)}
+			}
+		}
+	case len(r.queryKeys) > 0 && mediaType == fiber.MIMEApplicationForm:
+		return Body{Data: r.values(string(data))}
+	}
+	if !utf8.Valid(data) {
+		return Body{Data: encodeBase64(data), Encoding: "base64"}
+	}
+	return Body{Data: string(data)}
+}
+
+// redactJSON replaces the values of the redacted fields in the decoded JSON value
+func (r *redactor) redactJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if _, ok := r.fieldKeys[utils.ToLower(key)]; ok {
+				v[key] = Redacted
+			} else {
+				v[key] = r.redactJSON(value)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = r.redactJSON(v[i])
+		}
+	}
+	return v
+}
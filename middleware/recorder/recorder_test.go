@@ -0,0 +1,240 @@
+package recorder
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// newRecordedApp returns the test app, whose requests are recorded to dir if it isn't empty
+func newRecordedApp(dir string, config Config) *fiber.App {
+	app := fiber.New()
+	if dir != "" {
+		config.Dir = dir
+		app.Use(New(config))
+	}
+	app.Post("/login", func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderSetCookie, "session=secret")
+		return c.JSON(fiber.Map{"user": "john", "token": "abc", "roles": []string{"admin"}})
+	})
+	app.Get("/users/:id", func(c fiber.Ctx) error {
+		return c.SendString("user " + c.Params("id"))
+	})
+	app.Get("/binary", func(c fiber.Ctx) error {
+		return c.Send([]byte{0xff, 0xfe, 0x00})
+	})
+	app.Get("/error", func(_ fiber.Ctx) error {
+		return fiber.ErrTeapot
+	})
+	return app
+}
+
+func recordings(t *testing.T, dir string) []*Recording {
+	t.Helper()
+	files, err := recordingFiles(dir)
+	require.NoError(t, err)
+	recs := make([]*Recording, len(files))
+	for i, file := range files {
+		recs[i], err = load(file)
+		require.NoError(t, err)
+	}
+	return recs
+}
+
+// go test -run Test_Recorder_Record
+func Test_Recorder_Record(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	app := newRecordedApp(dir, Config{
+		RedactQuery:  []string{"api_key"},
+		RedactFields: []string{"password", "token"},
+	})
+
+	req := httptest.NewRequest(fiber.MethodPost, "/login?api_key=123&page=1", strings.NewReader(`{"user":"john","password":"secret"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer secret")
+	_, err := app.Test(req)
+	require.NoError(t, err)
+
+	_, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/binary", nil))
+	require.NoError(t, err)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/error", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusTeapot, resp.StatusCode)
+
+	recs := recordings(t, dir)
+	require.Len(t, recs, 3)
+
+	login := recs[0]
+	require.Equal(t, fiber.MethodPost, login.Request.Method)
+	require.Equal(t, "/login?api_key=%5BREDACTED%5D&page=1", login.Request.URL)
+	require.Equal(t, []string{Redacted}, login.Request.Header[fiber.HeaderAuthorization])
+	require.JSONEq(t, `{"user":"john","password":"[REDACTED]"}`, login.Request.Body.Data)
+	require.Equal(t, fiber.StatusOK, login.Response.Status)
+	require.Equal(t, []string{Redacted}, login.Response.Header[fiber.HeaderSetCookie])
+	require.JSONEq(t, `{"user":"john","token":"[REDACTED]","roles":["admin"]}`, login.Response.Body.Data)
+
+	binary := recs[1]
+	require.Equal(t, "base64", binary.Response.Body.Encoding)
+	data, err := binary.Response.Body.bytes()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xff, 0xfe, 0x00}, data)
+
+	// The response of the error handler is recorded
+	require.Equal(t, fiber.StatusTeapot, recs[2].Response.Status)
+	require.Equal(t, "I'm a teapot", recs[2].Response.Body.Data)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*-GET-binary.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
+
+// go test -run Test_Recorder_Skip
+func Test_Recorder_Skip(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	app := newRecordedApp(dir, Config{
+		Next: func(c fiber.Ctx) bool {
+			return c.Path() == "/binary"
+		},
+		MaxBodySize: 4,
+	})
+
+	_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/binary", nil))
+	require.NoError(t, err)
+	_, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/users/12345", nil))
+	require.NoError(t, err)
+
+	recs := recordings(t, dir)
+	require.Len(t, recs, 1)
+	require.True(t, recs[0].Response.Body.Truncated)
+	require.Empty(t, recs[0].Response.Body.Data)
+}
+
+// go test -run Test_Recorder_Replay
+func Test_Recorder_Replay(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	app := newRecordedApp(dir, Config{RedactFields: []string{"token"}})
+
+	req := httptest.NewRequest(fiber.MethodPost, "/login", strings.NewReader(`{"user":"john"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer secret")
+	_, err := app.Test(req)
+	require.NoError(t, err)
+	_, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/users/1", nil))
+	require.NoError(t, err)
+	_, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/binary", nil))
+	require.NoError(t, err)
+
+	// The same app produces the same responses, redacted values are ignored
+	var authorization []string
+	diffs, err := Replay(newRecordedApp("", Config{}), dir, ReplayConfig{
+		Prepare: func(req *http.Request, _ *Recording) {
+			authorization = append(authorization, req.Header.Get(fiber.HeaderAuthorization))
+		},
+	})
+	require.NoError(t, err)
+	require.Empty(t, diffs)
+	require.Equal(t, []string{Redacted, "", ""}, authorization)
+
+	// A refactored app with changed behavior
+	refactored := fiber.New()
+	refactored.Post("/login", func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderSetCookie, "session=other")
+		// Another key order and token are equal
+		return c.JSON(fiber.Map{"roles": []string{"admin"}, "token": "xyz", "user": "john"})
+	})
+	refactored.Get("/users/:id", func(c fiber.Ctx) error {
+		c.Set("X-Version", "2")
+		return c.Status(fiber.StatusCreated).SendString("user #" + c.Params("id"))
+	})
+	refactored.Get("/binary", func(c fiber.Ctx) error {
+		return c.Send([]byte{0xff})
+	})
+
+	diffs, err = Replay(refactored, dir)
+	require.NoError(t, err)
+	require.Len(t, diffs, 4)
+	require.Equal(t, "GET /users/1", diffs[0].Request)
+	require.Equal(t, "status", diffs[0].Field)
+	require.Equal(t, "200", diffs[0].Expected)
+	require.Equal(t, "201", diffs[0].Actual)
+	require.Equal(t, "header X-Version", diffs[1].Field)
+	require.Equal(t, "2", diffs[1].Actual)
+	require.Equal(t, "body", diffs[2].Field)
+	require.Equal(t, "user 1", diffs[2].Expected)
+	require.Equal(t, "user #1", diffs[2].Actual)
+	require.Contains(t, diffs[2].String(), `GET /users/1: body: expected "user 1", got "user #1"`)
+	require.Equal(t, "GET /binary", diffs[3].Request)
+	require.Equal(t, "/w==", diffs[3].Actual)
+
+	// A single file can be replayed
+	files, err := recordingFiles(dir)
+	require.NoError(t, err)
+	diffs, err = Replay(refactored, files[1])
+	require.NoError(t, err)
+	require.Len(t, diffs, 3)
+}
+
+// go test -run Test_Recorder_Replay_Errors
+func Test_Recorder_Replay_Errors(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	app := fiber.New()
+
+	_, err := Replay(app, filepath.Join(dir, "missing"))
+	require.ErrorContains(t, err, "recorder: failed to read recordings")
+
+	file := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(file, []byte("{"), 0o600))
+	_, err = Replay(app, dir)
+	require.ErrorContains(t, err, "recorder: failed to decode recording")
+}
+
+// go test -run Test_Recorder_FileSafe
+func Test_Recorder_FileSafe(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "", fileSafe("/"))
+	require.Equal(t, "-users-1", fileSafe("/users/1?page=2"))
+	require.Equal(t, "-a-b", fileSafe("/a/../b/"))
+	require.Len(t, fileSafe("/"+strings.Repeat("a", 100)), 64)
+}
+
+// go test -run Test_Recorder_EqualJSON
+func Test_Recorder_EqualJSON(t *testing.T) {
+	t.Parallel()
+	require.True(t, equalJSON(map[string]any{"a": Redacted}, map[string]any{"a": 1.0}))
+	require.True(t, equalJSON([]any{1.0, "x"}, []any{1.0, "x"}))
+	require.False(t, equalJSON([]any{1.0}, []any{1.0, 2.0}))
+	require.False(t, equalJSON(map[string]any{"a": 1.0}, map[string]any{"b": 1.0}))
+	require.False(t, equalJSON(map[string]any{"a": 1.0}, []any{}))
+}
+
+// go test -v -run=^$ -bench=Benchmark_Recorder -benchmem -count=4
+func Benchmark_Recorder(b *testing.B) {
+	app := fiber.New()
+	app.Use(New(Config{Dir: b.TempDir()}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString("Hello, World!")
+	})
+	h := app.Handler()
+
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Request.Header.SetMethod(fiber.MethodGet)
+	fctx.Request.SetRequestURI("/")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		h(fctx)
+	}
+}
@@ -0,0 +1,315 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+)
+
+// ReplayConfig defines the config for Replay.
+type ReplayConfig struct {
+	// Prepare is called with each request before it is sent, e.g. to set the
+	// credentials which were redacted in the recording.
+	//
+	// Optional. Default: nil
+	Prepare func(req *http.Request, rec *Recording)
+
+	// IgnoreHeaders are the response headers which aren't compared.
+	//
+	// Optional. Default: Date, Content-Length, X-Request-Id
+	IgnoreHeaders []string
+
+	// Timeout is the maximum duration of a replayed request.
+	//
+	// Optional. Default: time.Second
+	Timeout time.Duration
+}
+
+// ReplayConfigDefault is the default config of Replay
+var ReplayConfigDefault = ReplayConfig{
+	IgnoreHeaders: []string{fiber.HeaderDate, fiber.HeaderContentLength, fiber.HeaderXRequestID},
+	Timeout:       time.Second,
+}
+
+func replayConfigDefault(config ...ReplayConfig) ReplayConfig {
+	if len(config) < 1 {
+		return ReplayConfigDefault
+	}
+	cfg := config[0]
+	if cfg.IgnoreHeaders == nil {
+		cfg.IgnoreHeaders = ReplayConfigDefault.IgnoreHeaders
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = ReplayConfigDefault.Timeout
+	}
+	return cfg
+}
+
+// Diff is a difference between a recorded and a replayed response
+type Diff struct {
+	File     string // File of the recording
+	Request  string // Method and URL of the request, e.g. "GET /users/1"
+	Field    string // "status", "header <name>" or "body"
+	Expected string
+	Actual   string
+}
+
+// String returns the diff in a readable form
+func (d Diff) String() string {
+	return fmt.Sprintf("%s: %s: %s: expected %q, got %q", d.File, d.Request, d.Field, d.Expected, d.Actual)
+}
+
+// Replay sends the recorded requests of the path, a recording file or a directory of
+// recordings, to the app in the order they were recorded and returns the differences
+// of the responses. Values which were redacted in the recording and bodies which were
+// too large to be recorded aren't compared. JSON bodies are compared semantically.
+//
+//	diffs, err := recorder.Replay(app, "./testdata/recordings")
+//	require.NoError(t, err)
+//	require.Empty(t, diffs)
+func Replay(app *fiber.App, path string, config ...ReplayConfig) ([]Diff, error) {
+	cfg := replayConfigDefault(config...)
+	ignore := make(map[string]struct{}, len(cfg.IgnoreHeaders))
+	for _, header := range cfg.IgnoreHeaders {
+		ignore[http.CanonicalHeaderKey(header)] = struct{}{}
+	}
+
+	files, err := recordingFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []Diff
+	for _, file := range files {
+		rec, err := load(file)
+		if err != nil {
+			return nil, err
+		}
+		req, err := rec.Request.http()
+		if err != nil {
+			return nil, fmt.Errorf("recorder: invalid request in %q: %w", file, err)
+		}
+		if cfg.Prepare != nil {
+			cfg.Prepare(req, rec)
+		}
+
+		resp, err := app.Test(req, fiber.TestConfig{Timeout: cfg.Timeout, FailOnTimeout: true})
+		if err != nil {
+			return nil, fmt.Errorf("recorder: failed to replay %q: %w", file, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("recorder: failed to read response of %q: %w", file, err)
+		}
+
+		d := differ{
+			diffs:   diffs,
+			file:    filepath.Base(file),
+			request: rec.Request.Method + " " + rec.Request.URL,
+		}
+		d.status(rec.Response.Status, resp.StatusCode)
+		d.headers(rec.Response.Header, resp.Header, ignore)
+		d.body(rec.Response.Body, body)
+		diffs = d.diffs
+	}
+	return diffs, nil
+}
+
+// recordingFiles returns the recording files of the path sorted by name, which is the
+// order they were recorded
+func recordingFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: failed to read recordings: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("recorder: failed to read recordings: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// load reads the recording of the file
+func load(file string) (*Recording, error) {
+	data, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, fmt.Errorf("recorder: failed to read recording: %w", err)
+	}
+	rec := new(Recording)
+	if err := json.Unmarshal(data, rec); err != nil {
+		return nil, fmt.Errorf("recorder: failed to decode recording %q: %w", file, err)
+	}
+	return rec, nil
+}
+
+// http returns the recorded request as *http.Request
+func (r *Request) http() (*http.Request, error) {
+	body, err := r.Body.bytes()
+	if err != nil {
+		return nil, err
+	}
+	req := httptest.NewRequest(r.Method, r.URL, bytes.NewReader(body))
+	for key, values := range r.Header {
+		if http.CanonicalHeaderKey(key) == fiber.HeaderHost && len(values) > 0 {
+			req.Host = values[0]
+			continue
+		}
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	return req, nil
+}
+
+// bytes returns the decoded data of the body
+func (b Body) bytes() ([]byte, error) {
+	if b.Encoding == "base64" {
+		data, err := base64.StdEncoding.DecodeString(b.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode body: %w", err)
+		}
+		return data, nil
+	}
+	return []byte(b.Data), nil
+}
+
+func encodeBase64(data []byte) string {
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// differ collects the differences of a replayed response
+type differ struct {
+	file    string
+	request string
+	diffs   []Diff
+}
+
+func (d *differ) add(field, expected, actual string) {
+	d.diffs = append(d.diffs, Diff{
+		File:     d.file,
+		Request:  d.request,
+		Field:    field,
+		Expected: expected,
+		Actual:   actual,
+	})
+}
+
+func (d *differ) status(expected, actual int) {
+	if expected != actual {
+		d.add("status", strconv.Itoa(expected), strconv.Itoa(actual))
+	}
+}
+
+func (d *differ) headers(expected map[string][]string, actual http.Header, ignore map[string]struct{}) {
+	keys := make(map[string]struct{}, len(expected)+len(actual))
+	recorded := make(http.Header, len(expected))
+	for key, values := range expected {
+		key = http.CanonicalHeaderKey(key)
+		recorded[key] = values
+		keys[key] = struct{}{}
+	}
+	for key := range actual {
+		keys[key] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		if _, ok := ignore[key]; !ok {
+			sorted = append(sorted, key)
+		}
+	}
+	sort.Strings(sorted)
+	for _, key := range sorted {
+		want, got := recorded[key], actual[key]
+		if len(want) > 0 && allRedacted(want) {
+			continue
+		}
+		if !reflect.DeepEqual(want, got) {
+			d.add("header "+key, strings.Join(want, ", "), strings.Join(got, ", "))
+		}
+	}
+}
+
+func (d *differ) body(expected Body, actual []byte) {
+	if expected.Truncated {
+		return
+	}
+	want, err := expected.bytes()
+	if err != nil {
+		d.add("body", expected.Data, string(actual))
+		return
+	}
+	if bytes.Equal(want, actual) {
+		return
+	}
+	var wantJSON, gotJSON any
+	if json.Unmarshal(want, &wantJSON) == nil && json.Unmarshal(actual, &gotJSON) == nil && equalJSON(wantJSON, gotJSON) {
+		return
+	}
+	if expected.Encoding == "base64" {
+		d.add("body", expected.Data, encodeBase64(actual))
+		return
+	}
+	d.add("body", string(want), utils.UnsafeString(actual))
+}
+
+// equalJSON compares decoded JSON values, redacted values match any value
+func equalJSON(expected, actual any) bool {
+	switch e := expected.(type) {
+	case string:
+		if e == Redacted {
+			return true
+		}
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok || len(a) != len(e) {
+			return false
+		}
+		for key, value := range e {
+			v, ok := a[key]
+			if !ok || !equalJSON(value, v) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		a, ok := actual.([]any)
+		if !ok || len(a) != len(e) {
+			return false
+		}
+		for i := range e {
+			if !equalJSON(e[i], a[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(expected, actual)
+}
+
+func allRedacted(values []string) bool {
+	for _, v := range values {
+		if v != Redacted {
+			return false
+		}
+	}
+	return true
+}
@@ -2,6 +2,7 @@ package redirect
 
 import (
 	"regexp"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 )
@@ -23,6 +24,22 @@ type Config struct {
 
 	rulesRegex map[*regexp.Regexp]string
 
+	// RuleSet defines redirect rules with conditions on the host, scheme and path of
+	// the request, e.g. to redirect the apex domain to www or http to https.
+	// They are evaluated in order before Rules and RulesFile.
+	// Optional. Default: nil
+	RuleSet []Rule
+
+	// RulesFile is the path of a JSON file with an array of rules like RuleSet,
+	// which are evaluated after RuleSet and before Rules.
+	// Optional. Default: ""
+	RulesFile string
+
+	// ReloadInterval is the interval to check the RulesFile for changes. Changed rules are
+	// reloaded without a restart, invalid rules are logged and the previous rules are kept.
+	// Optional. Default: 0 (the file is not reloaded)
+	ReloadInterval time.Duration
+
 	// The status code when redirecting
 	// This is ignored if Redirect is disabled
 	// Optional. Default: 302 Temporary Redirect
@@ -20,12 +20,36 @@ func New(config ...Config) fiber.Handler {
 		cfg.rulesRegex[regexp.MustCompile(k)] = v
 	}
 
+	ruleSet, err := compileRules(cfg.RuleSet, cfg.StatusCode)
+	if err != nil {
+		panic(err)
+	}
+	var file *rulesFile
+	if cfg.RulesFile != "" {
+		if file, err = newRulesFile(cfg.RulesFile, cfg.ReloadInterval, cfg.StatusCode); err != nil {
+			panic(err)
+		}
+	}
+
 	// Middleware function
 	return func(c fiber.Ctx) error {
 		// Next request to skip middleware
 		if cfg.Next != nil && cfg.Next(c) {
 			return c.Next()
 		}
+		for i := range ruleSet {
+			if location, ok := ruleSet[i].location(c); ok {
+				return c.Redirect().Status(ruleSet[i].StatusCode).To(location)
+			}
+		}
+		if file != nil {
+			rules := file.get()
+			for i := range rules {
+				if location, ok := rules[i].location(c); ok {
+					return c.Redirect().Status(rules[i].StatusCode).To(location)
+				}
+			}
+		}
 		// Rewrite
 		for k, v := range cfg.rulesRegex {
 			replacer := captureTokens(k, c.Path())
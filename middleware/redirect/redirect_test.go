@@ -3,7 +3,11 @@ package redirect
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/stretchr/testify/require"
@@ -287,3 +291,89 @@ func Test_RegexRules(t *testing.T) {
 		}))
 	})
 }
+
+func Test_RuleSet(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		RuleSet: []Rule{
+			{Host: "example.com", ToHost: "www.example.com", StatusCode: fiber.StatusMovedPermanently},
+			{Scheme: "http", Host: "secure.example.com", ToScheme: "https", StatusCode: fiber.StatusPermanentRedirect},
+			{Path: `/blog/(\d+)/(?P<slug>[a-z-]+)`, Target: "/posts/${slug}?id=$1"},
+			{Path: `/blog/(\d+)`, Target: "/posts/$1", StatusCode: fiber.StatusMovedPermanently},
+		},
+	}))
+	app.Get("/*", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	tests := []struct {
+		url      string
+		location string
+		status   int
+	}{
+		{url: "http://example.com/about?a=b", location: "http://www.example.com/about?a=b", status: fiber.StatusMovedPermanently},
+		{url: "http://secure.example.com/login", location: "https://secure.example.com/login", status: fiber.StatusPermanentRedirect},
+		{url: "http://www.example.com/blog/42/hello-world", location: "/posts/hello-world?id=42", status: fiber.StatusFound},
+		{url: "http://www.example.com/blog/42", location: "/posts/42", status: fiber.StatusMovedPermanently},
+		{url: "http://www.example.com/blog/42/", status: fiber.StatusOK},
+		{url: "http://www.example.com/about", status: fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.url, nil))
+		require.NoError(t, err)
+		require.Equal(t, tt.status, resp.StatusCode, tt.url)
+		require.Equal(t, tt.location, resp.Header.Get(fiber.HeaderLocation), tt.url)
+	}
+
+	// Rules without a target are invalid
+	require.Panics(t, func() {
+		New(Config{RuleSet: []Rule{{Host: "example.com"}}})
+	})
+	require.Panics(t, func() {
+		New(Config{RuleSet: []Rule{{Path: "(", Target: "/"}}})
+	})
+}
+
+func Test_RulesFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"path": "/old", "target": "/new"}]`), 0o600))
+
+	app := fiber.New()
+	app.Use(New(Config{
+		RulesFile:      path,
+		ReloadInterval: 10 * time.Millisecond,
+	}))
+	app.Get("/*", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	location := func(target string) string {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, target, nil))
+		require.NoError(t, err)
+		return resp.Header.Get(fiber.HeaderLocation)
+	}
+	require.Equal(t, "/new", location("/old"))
+
+	// Changed rules are reloaded
+	require.NoError(t, os.WriteFile(path, []byte(`[{"path": "/old", "target": "/newer", "status_code": 301}]`), 0o600))
+	modTime := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	require.Eventually(t, func() bool {
+		return location("/old") == "/newer"
+	}, time.Second, 20*time.Millisecond)
+
+	// Invalid rules are ignored and the previous rules are kept
+	require.NoError(t, os.WriteFile(path, []byte(`[{"path": "("`), 0o600))
+	modTime = modTime.Add(time.Second)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, "/newer", location("/old"))
+
+	// A missing file is an error
+	require.Panics(t, func() {
+		New(Config{RulesFile: filepath.Join(t.TempDir(), "missing.json")})
+	})
+}
@@ -0,0 +1,181 @@
+package redirect
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// Rule defines a redirect rule. A request matches the rule if it matches all of its
+// conditions Host, Scheme and Path, empty conditions match every request.
+type Rule struct {
+	// Host matches the hostname of the request without port, e.g. "example.com".
+	// Optional. Default: ""
+	Host string `json:"host"`
+
+	// Scheme matches the scheme of the request, e.g. "http" to upgrade requests to https.
+	// Optional. Default: ""
+	Scheme string `json:"scheme"`
+
+	// Path is a regular expression the whole path of the request must match,
+	// e.g. `/blog/(\d+)`. Its capture groups can be used in Target as $1, $2 or ${name}.
+	// Optional. Default: ""
+	Path string `json:"path"`
+
+	// Target is the location to redirect to, which may be a path or an absolute URL.
+	// If it is empty, the request URL is used with ToScheme and ToHost.
+	// Optional. Default: ""
+	Target string `json:"target"`
+
+	// ToScheme replaces the scheme of the request URL if Target is empty, e.g. "https".
+	// Optional. Default: ""
+	ToScheme string `json:"to_scheme"`
+
+	// ToHost replaces the host of the request URL if Target is empty, e.g. "www.example.com".
+	// Optional. Default: ""
+	ToHost string `json:"to_host"`
+
+	// StatusCode is the status code of the redirect.
+	// Optional. Default: the StatusCode of the config
+	StatusCode int `json:"status_code"`
+}
+
+// compiledRule is a rule with its compiled path expression
+type compiledRule struct {
+	path *regexp.Regexp
+	Rule
+}
+
+// compileRules compiles the path expressions of the rules
+func compileRules(rules []Rule, statusCode int) ([]compiledRule, error) {
+	compiled := make([]compiledRule, len(rules))
+	for i, rule := range rules {
+		if rule.Target == "" && rule.ToScheme == "" && rule.ToHost == "" {
+			return nil, fmt.Errorf("redirect: rule %d has no Target, ToScheme or ToHost", i)
+		}
+		if rule.StatusCode == 0 {
+			rule.StatusCode = statusCode
+		}
+		compiled[i].Rule = rule
+		if rule.Path != "" {
+			re, err := regexp.Compile("^(?:" + rule.Path + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("redirect: invalid path of rule %d: %w", i, err)
+			}
+			compiled[i].path = re
+		}
+	}
+	return compiled, nil
+}
+
+// location returns the location the request is redirected to, or false if the rule doesn't match
+func (r *compiledRule) location(c fiber.Ctx) (string, bool) {
+	if r.Host != "" && !strings.EqualFold(r.Host, c.Hostname()) {
+		return "", false
+	}
+	if r.Scheme != "" && !strings.EqualFold(r.Scheme, c.Scheme()) {
+		return "", false
+	}
+
+	path := c.Path()
+	var match []int
+	if r.path != nil {
+		if match = r.path.FindStringSubmatchIndex(path); match == nil {
+			return "", false
+		}
+	}
+
+	queryString := string(c.RequestCtx().QueryArgs().QueryString())
+	if queryString != "" {
+		queryString = "?" + queryString
+	}
+
+	if r.Target != "" {
+		target := r.Target
+		if match != nil {
+			target = string(r.path.ExpandString(nil, r.Target, path, match))
+		}
+		return target + queryString, true
+	}
+
+	scheme := r.ToScheme
+	if scheme == "" {
+		scheme = c.Scheme()
+	}
+	host := r.ToHost
+	if host == "" {
+		host = c.Host()
+	}
+	return scheme + "://" + host + path + queryString, true
+}
+
+// rulesFile keeps the rules of a file and reloads them when the file changes
+type rulesFile struct {
+	rules      atomic.Pointer[[]compiledRule]
+	modTime    time.Time
+	checked    time.Time
+	path       string
+	interval   time.Duration
+	statusCode int
+	mu         sync.Mutex
+}
+
+// newRulesFile loads the rules of the file, which contains a JSON array of rules
+func newRulesFile(path string, interval time.Duration, statusCode int) (*rulesFile, error) {
+	f := &rulesFile{path: path, interval: interval, statusCode: statusCode}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("redirect: failed to read rules: %w", err)
+	}
+	if err := f.load(info.ModTime()); err != nil {
+		return nil, err
+	}
+	f.checked = time.Now()
+	return f, nil
+}
+
+func (f *rulesFile) load(modTime time.Time) error {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return fmt.Errorf("redirect: failed to read rules: %w", err)
+	}
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return fmt.Errorf("redirect: failed to parse rules: %w", err)
+	}
+	compiled, err := compileRules(rules, f.statusCode)
+	if err != nil {
+		return err
+	}
+	f.rules.Store(&compiled)
+	f.modTime = modTime
+	return nil
+}
+
+// get returns the current rules, it reloads them at most once per interval if the file changed
+func (f *rulesFile) get() []compiledRule {
+	if f.interval > 0 && f.mu.TryLock() {
+		if now := time.Now(); now.Sub(f.checked) >= f.interval {
+			f.checked = now
+			if info, err := os.Stat(f.path); err != nil {
+				log.Errorf("[REDIRECT] failed to check rules file %q: %v", f.path, err)
+			} else if !info.ModTime().Equal(f.modTime) {
+				// The old rules are kept if the new rules are invalid
+				if err := f.load(info.ModTime()); err != nil {
+					f.modTime = info.ModTime()
+					log.Errorf("[REDIRECT] failed to reload rules file %q: %v", f.path, err)
+				}
+			}
+		}
+		f.mu.Unlock()
+	}
+	return *f.rules.Load()
+}
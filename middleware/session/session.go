@@ -319,6 +319,9 @@ func (s *Session) saveSession() error {
 	}
 
 	// Pass copied bytes with session id to provider
+	if storage, ok := s.config.Storage.(fiber.StorageWithContext); ok && s.ctx != nil {
+		return storage.SetWithContext(s.ctx.Context(), s.id, encodedBytes, s.idleTimeout)
+	}
 	return s.config.Storage.Set(s.id, encodedBytes, s.idleTimeout)
 }
 
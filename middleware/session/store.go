@@ -131,7 +131,12 @@ func (s *Store) getSession(c fiber.Ctx) (*Session, error) {
 
 	// Attempt to fetch session data if an ID is provided
 	if id != "" {
-		rawData, err = s.Storage.Get(id)
+		if storage, ok := s.Storage.(fiber.StorageWithContext); ok {
+			// Allow the storage to cancel the lookup when the request is done
+			rawData, err = storage.GetWithContext(c.Context(), id)
+		} else {
+			rawData, err = s.Storage.Get(id)
+		}
 		if err != nil {
 			return nil, err
 		}
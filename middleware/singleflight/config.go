@@ -0,0 +1,67 @@
+package singleflight
+
+import (
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: a function which skips requests which are not GET or HEAD requests
+	Next func(c fiber.Ctx) bool
+
+	// KeyGenerator returns the key of the request, concurrent requests with the
+	// same key and the same VaryHeaders share a single execution of the handler.
+	//
+	// Optional. Default: func(c fiber.Ctx) string {
+	//   return c.Method() + " " + c.OriginalURL()
+	// }
+	KeyGenerator func(c fiber.Ctx) string
+
+	// VaryHeaders is a list of request headers which are added to the key,
+	// as the response of the handler may depend on them.
+	//
+	// Optional. Default: Accept, Accept-Encoding, Accept-Language, Authorization and Cookie
+	VaryHeaders []string
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next: func(c fiber.Ctx) bool {
+		return c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead
+	},
+	KeyGenerator: func(c fiber.Ctx) string {
+		return c.Method() + " " + c.OriginalURL()
+	},
+	VaryHeaders: []string{
+		fiber.HeaderAccept,
+		fiber.HeaderAcceptEncoding,
+		fiber.HeaderAcceptLanguage,
+		fiber.HeaderAuthorization,
+		fiber.HeaderCookie,
+	},
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Next == nil {
+		cfg.Next = ConfigDefault.Next
+	}
+	if cfg.KeyGenerator == nil {
+		cfg.KeyGenerator = ConfigDefault.KeyGenerator
+	}
+	if cfg.VaryHeaders == nil {
+		cfg.VaryHeaders = ConfigDefault.VaryHeaders
+	}
+	return cfg
+}
@@ -0,0 +1,145 @@
+// Package singleflight provides a middleware which executes the handler only once
+// for identical concurrent requests and shares its response with all of them.
+package singleflight
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+)
+
+// The contextKey type is unexported to prevent collisions with context keys defined in
+// other packages.
+type contextKey int
+
+const (
+	localsKeyIsShared contextKey = iota
+)
+
+// IsShared reports whether the response was shared from a concurrent request instead of
+// executing the handler.
+func IsShared(c fiber.Ctx) bool {
+	return c.Locals(localsKeyIsShared) != nil
+}
+
+// call is an execution of the handler which concurrent requests wait for
+type call struct {
+	err     error
+	done    chan struct{}
+	headers [][2]string
+	body    []byte
+	status  int
+	shared  bool // false if the response can't be shared, e.g. because it sets cookies
+}
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	var mu sync.Mutex
+	calls := make(map[string]*call)
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		key := requestKey(c, &cfg)
+
+		mu.Lock()
+		if cl, ok := calls[key]; ok {
+			mu.Unlock()
+			<-cl.done
+			if !cl.shared {
+				// Execute the handler for this request
+				return c.Next()
+			}
+			cl.write(c)
+			_ = c.Locals(localsKeyIsShared, true)
+			return cl.err
+		}
+		cl := &call{done: make(chan struct{})}
+		calls[key] = cl
+		mu.Unlock()
+
+		// Waiting requests execute the handler themselves if it panics
+		defer func() {
+			mu.Lock()
+			delete(calls, key)
+			mu.Unlock()
+			close(cl.done)
+		}()
+
+		// Headers which were set before the handler belong to this request only
+		before := make(map[string]int)
+		c.Response().Header.VisitAll(func(k, v []byte) {
+			before[string(k)+"\x00"+string(v)]++
+		})
+
+		if err := c.Next(); err != nil {
+			// Every request responds with the error itself
+			cl.err, cl.shared = err, true
+			return err
+		}
+
+		cl.capture(c, before)
+		return nil
+	}
+}
+
+// requestKey returns the key of the request including the VaryHeaders
+func requestKey(c fiber.Ctx, cfg *Config) string {
+	var b strings.Builder
+	b.WriteString(cfg.KeyGenerator(c))
+	for _, h := range cfg.VaryHeaders {
+		b.WriteByte('\n')
+		b.WriteString(h)
+		b.WriteByte(':')
+		b.WriteString(c.Get(h))
+	}
+	return b.String()
+}
+
+// capture copies the response which was written by the handler
+func (cl *call) capture(c fiber.Ctx, before map[string]int) {
+	cl.shared = true
+	c.Response().Header.VisitAll(func(k, v []byte) {
+		if utils.EqualFold(utils.UnsafeString(k), fiber.HeaderSetCookie) {
+			// Cookies must never be shared between clients
+			cl.shared = false
+			return
+		}
+		pair := string(k) + "\x00" + string(v)
+		if before[pair] > 0 {
+			before[pair]--
+			return
+		}
+		cl.headers = append(cl.headers, [2]string{string(k), string(v)})
+	})
+	cl.status = c.Response().StatusCode()
+	cl.body = utils.CopyBytes(c.Response().Body())
+}
+
+// write writes the captured response
+func (cl *call) write(c fiber.Ctx) {
+	if cl.err != nil {
+		return
+	}
+	c.Status(cl.status)
+	set := make(map[string]struct{}, len(cl.headers))
+	for _, h := range cl.headers {
+		// The first value replaces values which were set before
+		if _, ok := set[h[0]]; !ok {
+			set[h[0]] = struct{}{}
+			c.Set(h[0], h[1])
+		} else {
+			c.Response().Header.Add(h[0], h[1])
+		}
+	}
+	c.Response().SetBody(cl.body)
+}
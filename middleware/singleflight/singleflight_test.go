@@ -0,0 +1,229 @@
+package singleflight
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type result struct {
+	header http.Header
+	body   string
+	status int
+}
+
+// concurrentRequests sends the requests at once and releases the handler after all of them arrived
+func concurrentRequests(t *testing.T, app *fiber.App, release chan struct{}, reqs ...*http.Request) []result {
+	t.Helper()
+	results := make([]result, len(reqs))
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := app.Test(req, fiber.TestConfig{Timeout: 0})
+			if !assert.NoError(t, err) {
+				return
+			}
+			body, err := io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			results[i] = result{status: resp.StatusCode, header: resp.Header, body: string(body)}
+		}()
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	return results
+}
+
+// go test -run Test_Singleflight
+func Test_Singleflight(t *testing.T) {
+	t.Parallel()
+	var executions, shared atomic.Int32
+	release := make(chan struct{})
+
+	app := fiber.New()
+	var requestID atomic.Int32
+	app.Use(func(c fiber.Ctx) error {
+		c.Set("X-Request-ID", strconv.Itoa(int(requestID.Add(1))))
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if IsShared(c) {
+			shared.Add(1)
+		}
+		return nil
+	})
+	app.Use(New())
+	app.Get("/", func(c fiber.Ctx) error {
+		<-release
+		c.Set("X-Execution", strconv.Itoa(int(executions.Add(1))))
+		return c.Status(fiber.StatusAccepted).SendString("expensive")
+	})
+
+	reqs := make([]*http.Request, 10)
+	for i := range reqs {
+		reqs[i] = httptest.NewRequest(fiber.MethodGet, "/?page=1", nil)
+	}
+	results := concurrentRequests(t, app, release, reqs...)
+
+	require.Equal(t, int32(1), executions.Load())
+	require.Equal(t, int32(9), shared.Load())
+	ids := make(map[string]struct{})
+	for _, res := range results {
+		require.Equal(t, fiber.StatusAccepted, res.status)
+		require.Equal(t, "expensive", res.body)
+		require.Equal(t, "1", res.header.Get("X-Execution"))
+		require.Len(t, res.header.Values("X-Request-ID"), 1)
+		ids[res.header.Get("X-Request-ID")] = struct{}{}
+	}
+	// The headers which were set before the handler are not shared
+	require.Len(t, ids, 10)
+}
+
+// go test -run Test_Singleflight_Keys
+func Test_Singleflight_Keys(t *testing.T) {
+	t.Parallel()
+	var executions atomic.Int32
+	release := make(chan struct{})
+
+	app := fiber.New()
+	app.Use(New())
+	app.Get("/", func(c fiber.Ctx) error {
+		<-release
+		executions.Add(1)
+		return c.SendString(c.Query("page") + c.Get(fiber.HeaderAuthorization))
+	})
+
+	withAuth := func(target, auth string) *http.Request {
+		req := httptest.NewRequest(fiber.MethodGet, target, nil)
+		if auth != "" {
+			req.Header.Set(fiber.HeaderAuthorization, auth)
+		}
+		return req
+	}
+	results := concurrentRequests(t, app, release,
+		withAuth("/?page=1", ""),
+		withAuth("/?page=2", ""),
+		withAuth("/?page=1", "alice"),
+		withAuth("/?page=1", "bob"),
+	)
+
+	require.Equal(t, int32(4), executions.Load())
+	require.Equal(t, "1", results[0].body)
+	require.Equal(t, "2", results[1].body)
+	require.Equal(t, "1alice", results[2].body)
+	require.Equal(t, "1bob", results[3].body)
+}
+
+// go test -run Test_Singleflight_Cookies
+func Test_Singleflight_Cookies(t *testing.T) {
+	t.Parallel()
+	var executions atomic.Int32
+	release := make(chan struct{})
+
+	app := fiber.New()
+	app.Use(New())
+	app.Get("/", func(c fiber.Ctx) error {
+		<-release
+		n := strconv.Itoa(int(executions.Add(1)))
+		c.Cookie(&fiber.Cookie{Name: "session", Value: n})
+		return c.SendString(n)
+	})
+
+	results := concurrentRequests(t, app, release,
+		httptest.NewRequest(fiber.MethodGet, "/", nil),
+		httptest.NewRequest(fiber.MethodGet, "/", nil),
+		httptest.NewRequest(fiber.MethodGet, "/", nil),
+	)
+
+	// Responses with cookies are never shared
+	require.Equal(t, int32(3), executions.Load())
+	bodies := make(map[string]struct{})
+	for _, res := range results {
+		require.Equal(t, "session="+res.body+"; path=/; SameSite=Lax", res.header.Get(fiber.HeaderSetCookie))
+		bodies[res.body] = struct{}{}
+	}
+	require.Len(t, bodies, 3)
+}
+
+// go test -run Test_Singleflight_Error
+func Test_Singleflight_Error(t *testing.T) {
+	t.Parallel()
+	var executions atomic.Int32
+	release := make(chan struct{})
+
+	app := fiber.New()
+	app.Use(New())
+	app.Get("/", func(_ fiber.Ctx) error {
+		<-release
+		executions.Add(1)
+		return fiber.ErrBadGateway
+	})
+
+	results := concurrentRequests(t, app, release,
+		httptest.NewRequest(fiber.MethodGet, "/", nil),
+		httptest.NewRequest(fiber.MethodGet, "/", nil),
+	)
+
+	require.Equal(t, int32(1), executions.Load())
+	for _, res := range results {
+		require.Equal(t, fiber.StatusBadGateway, res.status)
+		require.Equal(t, "Bad Gateway", res.body)
+	}
+}
+
+// go test -run Test_Singleflight_Next
+func Test_Singleflight_Next(t *testing.T) {
+	t.Parallel()
+	var executions atomic.Int32
+	release := make(chan struct{})
+
+	app := fiber.New()
+	app.Use(New())
+	app.Post("/", func(c fiber.Ctx) error {
+		<-release
+		executions.Add(1)
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	results := concurrentRequests(t, app, release,
+		httptest.NewRequest(fiber.MethodPost, "/", nil),
+		httptest.NewRequest(fiber.MethodPost, "/", nil),
+	)
+
+	// Unsafe methods are not coalesced by default
+	require.Equal(t, int32(2), executions.Load())
+	for _, res := range results {
+		require.Equal(t, fiber.StatusCreated, res.status)
+	}
+}
+
+// go test -v -run=^$ -bench=Benchmark_Singleflight -benchmem -count=4
+func Benchmark_Singleflight(b *testing.B) {
+	app := fiber.New()
+	app.Use(New())
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString("Hello, World!")
+	})
+
+	h := app.Handler()
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI("/")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		h(ctx)
+	}
+}
@@ -0,0 +1,102 @@
+package skip
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+)
+
+// Path returns a predicate which is true if the path of the request is one of the paths,
+// e.g. skip.Path("/health", "/metrics"). It can be used as the Next func of any middleware.
+func Path(paths ...string) func(c fiber.Ctx) bool {
+	return func(c fiber.Ctx) bool {
+		path := c.Path()
+		for _, p := range paths {
+			if path == p {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// PathPrefix returns a predicate which is true if the path of the request starts with
+// one of the prefixes, e.g. skip.PathPrefix("/static/").
+func PathPrefix(prefixes ...string) func(c fiber.Ctx) bool {
+	return func(c fiber.Ctx) bool {
+		path := c.Path()
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Methods returns a predicate which is true if the method of the request is one of the
+// methods, e.g. skip.Methods(fiber.MethodGet, fiber.MethodHead).
+func Methods(methods ...string) func(c fiber.Ctx) bool {
+	upper := make([]string, len(methods))
+	for i, method := range methods {
+		upper[i] = utils.ToUpper(method)
+	}
+	return func(c fiber.Ctx) bool {
+		method := c.Method()
+		for _, m := range upper {
+			if method == m {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Header returns a predicate which is true if the request header has one of the values,
+// e.g. skip.Header("X-Internal", "1"). Without values, it is true if the header is present.
+func Header(key string, values ...string) func(c fiber.Ctx) bool {
+	return func(c fiber.Ctx) bool {
+		value := c.Get(key)
+		if len(values) == 0 {
+			return value != ""
+		}
+		for _, v := range values {
+			if value == v {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// And returns a predicate which is true if all predicates are true.
+func And(predicates ...func(c fiber.Ctx) bool) func(c fiber.Ctx) bool {
+	return func(c fiber.Ctx) bool {
+		for _, predicate := range predicates {
+			if !predicate(c) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a predicate which is true if any of the predicates is true.
+func Or(predicates ...func(c fiber.Ctx) bool) func(c fiber.Ctx) bool {
+	return func(c fiber.Ctx) bool {
+		for _, predicate := range predicates {
+			if predicate(c) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a predicate which is true if the predicate is false.
+func Not(predicate func(c fiber.Ctx) bool) func(c fiber.Ctx) bool {
+	return func(c fiber.Ctx) bool {
+		return !predicate(c)
+	}
+}
@@ -0,0 +1,115 @@
+package skip_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/basicauth"
+	"github.com/gofiber/fiber/v3/middleware/skip"
+	"github.com/stretchr/testify/require"
+)
+
+// skipped reports whether the predicate skips the request
+func skipped(t *testing.T, predicate func(c fiber.Ctx) bool, method, target string, headers ...string) bool {
+	t.Helper()
+	app := fiber.New()
+	app.Use(skip.New(errTeapotHandler, predicate))
+	app.All("/*", helloWorldHandler)
+
+	req := httptest.NewRequest(method, target, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp.StatusCode == fiber.StatusOK
+}
+
+// go test -run Test_Path
+func Test_Path(t *testing.T) {
+	t.Parallel()
+	predicate := skip.Path("/health", "/metrics")
+	require.True(t, skipped(t, predicate, fiber.MethodGet, "/health"))
+	require.True(t, skipped(t, predicate, fiber.MethodGet, "/metrics?format=json"))
+	require.False(t, skipped(t, predicate, fiber.MethodGet, "/metrics/cpu"))
+	require.False(t, skipped(t, predicate, fiber.MethodGet, "/"))
+}
+
+// go test -run Test_PathPrefix
+func Test_PathPrefix(t *testing.T) {
+	t.Parallel()
+	predicate := skip.PathPrefix("/static/", "/assets/")
+	require.True(t, skipped(t, predicate, fiber.MethodGet, "/static/app.js"))
+	require.True(t, skipped(t, predicate, fiber.MethodGet, "/assets/logo.png"))
+	require.False(t, skipped(t, predicate, fiber.MethodGet, "/static"))
+}
+
+// go test -run Test_Methods
+func Test_Methods(t *testing.T) {
+	t.Parallel()
+	predicate := skip.Methods(fiber.MethodGet, "head")
+	require.True(t, skipped(t, predicate, fiber.MethodGet, "/"))
+	require.True(t, skipped(t, predicate, fiber.MethodHead, "/"))
+	require.False(t, skipped(t, predicate, fiber.MethodPost, "/"))
+}
+
+// go test -run Test_Header
+func Test_Header(t *testing.T) {
+	t.Parallel()
+	predicate := skip.Header("X-Internal", "1", "true")
+	require.True(t, skipped(t, predicate, fiber.MethodGet, "/", "X-Internal", "1"))
+	require.True(t, skipped(t, predicate, fiber.MethodGet, "/", "X-Internal", "true"))
+	require.False(t, skipped(t, predicate, fiber.MethodGet, "/", "X-Internal", "0"))
+	require.False(t, skipped(t, predicate, fiber.MethodGet, "/"))
+
+	// Without values the header must be present
+	predicate = skip.Header("X-Internal")
+	require.True(t, skipped(t, predicate, fiber.MethodGet, "/", "X-Internal", "0"))
+	require.False(t, skipped(t, predicate, fiber.MethodGet, "/"))
+}
+
+// go test -run Test_Combinators
+func Test_Combinators(t *testing.T) {
+	t.Parallel()
+	internalGet := skip.And(skip.Methods(fiber.MethodGet), skip.Header("X-Internal", "1"))
+	require.True(t, skipped(t, internalGet, fiber.MethodGet, "/", "X-Internal", "1"))
+	require.False(t, skipped(t, internalGet, fiber.MethodPost, "/", "X-Internal", "1"))
+	require.False(t, skipped(t, internalGet, fiber.MethodGet, "/"))
+
+	probes := skip.Or(skip.Path("/health"), skip.PathPrefix("/debug/"))
+	require.True(t, skipped(t, probes, fiber.MethodGet, "/health"))
+	require.True(t, skipped(t, probes, fiber.MethodGet, "/debug/pprof"))
+	require.False(t, skipped(t, probes, fiber.MethodGet, "/"))
+
+	notAPI := skip.Not(skip.PathPrefix("/api/"))
+	require.True(t, skipped(t, notAPI, fiber.MethodGet, "/"))
+	require.False(t, skipped(t, notAPI, fiber.MethodGet, "/api/users"))
+
+	// Empty combinations
+	require.True(t, skipped(t, skip.And(), fiber.MethodGet, "/"))
+	require.False(t, skipped(t, skip.Or(), fiber.MethodGet, "/"))
+}
+
+// go test -run Test_PredicateAsNext
+func Test_PredicateAsNext(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(basicauth.New(basicauth.Config{
+		Users: map[string]string{"john": "doe"},
+		Next:  skip.Or(skip.Path("/health"), skip.Methods(fiber.MethodOptions)),
+	}))
+	app.All("/*", helloWorldHandler)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/health", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodOptions, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
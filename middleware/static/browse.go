@@ -0,0 +1,186 @@
+package static
+
+import (
+	"cmp"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Entry is a file or a directory of a directory listing.
+type Entry struct {
+	// ModTime is the modification time of the entry.
+	ModTime time.Time
+	// Name is the name of the entry, e.g. "style.css".
+	Name string
+	// Path is the URL path of the entry, which ends with a slash for directories.
+	Path string
+	// Size is the size of a file in bytes.
+	Size int64
+	// Mode is the file mode of the entry.
+	Mode fs.FileMode
+	// IsDir reports whether the entry is a directory.
+	IsDir bool
+}
+
+// Listing is a directory listing, which is passed to Config.BrowseRender.
+type Listing struct {
+	// Path is the URL path of the directory, which ends with a slash.
+	Path string
+	// Parent is the URL path of the parent directory, or empty for the root.
+	Parent string
+	// Sort is the column by which the entries are sorted: "name", "size" or "modtime".
+	Sort string
+	// Order is the order of the entries: "asc" or "desc".
+	Order string
+	// Entries are the files and directories of the directory. Directories are listed first.
+	Entries []Entry
+}
+
+// Query parameters which sort directory listings, e.g. "?sort=size&order=desc"
+const (
+	sortQuery  = "sort"
+	orderQuery = "order"
+)
+
+// serveListing renders the listing of the requested directory with Config.BrowseRender or
+// renderListing, unless the directory has an index file. It reports whether the path was
+// a directory whose listing was rendered.
+func serveListing(c fiber.Ctx, cfg *Config, filesystem fs.FS, prefix string) (bool, error) {
+	requestPath := string(c.RequestCtx().Path())
+	name := strings.Trim(strings.TrimPrefix(requestPath, prefix), "/")
+	if name == "" {
+		name = "."
+	}
+	if !fs.ValidPath(name) {
+		return false, nil
+	}
+
+	stat, err := fs.Stat(filesystem, name)
+	if err != nil || !stat.IsDir() {
+		return false, nil
+	}
+	for _, index := range cfg.IndexNames {
+		if _, err := fs.Stat(filesystem, path.Join(name, index)); err == nil {
+			return false, nil
+		}
+	}
+
+	dirEntries, err := fs.ReadDir(filesystem, name)
+	if err != nil {
+		return false, nil //nolint:nilerr // Unreadable directories are answered as not found
+	}
+
+	listing := Listing{
+		Path:  strings.TrimSuffix(requestPath, "/") + "/",
+		Sort:  c.Query(sortQuery, "name"),
+		Order: c.Query(orderQuery, "asc"),
+	}
+	if name != "." {
+		listing.Parent = path.Dir(strings.TrimSuffix(listing.Path, "/"))
+		if listing.Parent != "/" {
+			listing.Parent += "/"
+		}
+	}
+
+	listing.Entries = make([]Entry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		entry := Entry{
+			Name:    dirEntry.Name(),
+			Path:    listing.Path + dirEntry.Name(),
+			Mode:    info.Mode(),
+			ModTime: info.ModTime(),
+			IsDir:   dirEntry.IsDir(),
+		}
+		if entry.IsDir {
+			entry.Path += "/"
+		} else {
+			entry.Size = info.Size()
+		}
+		if cfg.BrowseFilter != nil && !cfg.BrowseFilter(entry) {
+			continue
+		}
+		listing.Entries = append(listing.Entries, entry)
+	}
+	sortEntries(listing.Entries, listing.Sort, listing.Order)
+
+	render := cfg.BrowseRender
+	if render == nil {
+		render = renderListing
+	}
+	if err := render(c, listing); err != nil {
+		return true, fmt.Errorf("static: %w", err)
+	}
+	return true, nil
+}
+
+// sortEntries sorts the entries by the column and the order, with directories first.
+// Unknown columns sort by name.
+func sortEntries(entries []Entry, column, order string) {
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		if a.IsDir != b.IsDir {
+			if a.IsDir {
+				return -1
+			}
+			return 1
+		}
+
+		var result int
+		switch column {
+		case "size":
+			result = cmp.Compare(a.Size, b.Size)
+		case "modtime":
+			result = a.ModTime.Compare(b.ModTime)
+		}
+		if result == 0 {
+			result = strings.Compare(a.Name, b.Name)
+		}
+		if order == "desc" {
+			return -result
+		}
+		return result
+	})
+}
+
+// listingTemplate is the template of renderListing, whose column headers sort the listing
+var listingTemplate = template.Must(template.New("listing").Funcs(template.FuncMap{
+	"sortLink": func(listing Listing, column string) string {
+		order := "asc"
+		if listing.Sort == column && listing.Order == "asc" {
+			order = "desc"
+		}
+		return "?" + sortQuery + "=" + column + "&" + orderQuery + "=" + order
+	},
+}).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Path}}</title></head>
+<body><h1>{{.Path}}</h1>
+<table>
+<thead><tr><th><a href="{{sortLink . "name"}}">Name</a></th><th><a href="{{sortLink . "size"}}">Size</a></th><th><a href="{{sortLink . "modtime"}}">Modified</a></th></tr></thead>
+<tbody>
+{{- if .Parent}}
+<tr><td><a href="{{.Parent}}">..</a></td><td></td><td></td></tr>
+{{- end}}
+{{- range .Entries}}
+<tr><td><a href="{{.Path}}">{{.Name}}{{if .IsDir}}/{{end}}</a></td><td>{{if not .IsDir}}{{.Size}}{{end}}</td><td>{{.ModTime.UTC.Format "2006-01-02 15:04:05"}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+</body></html>
+`))
+
+// renderListing renders the listing as an HTML table, which is the BrowseRender
+// if only BrowseFilter is set.
+func renderListing(c fiber.Ctx, listing Listing) error {
+	c.Type("html", "utf-8")
+	return listingTemplate.Execute(c, listing) //nolint:wrapcheck // serveListing wraps it
+}
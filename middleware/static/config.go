@@ -20,6 +20,13 @@ type Config struct {
 	// Optional. Default: nil
 	Next func(c fiber.Ctx) bool
 
+	// PathRewrite returns the path of the file to serve for the request, relative to the
+	// prefix of the route, e.g. to strip a version from "/v1.2.0/app.js". The next handlers
+	// get the original path of the request.
+	//
+	// Optional. Default: nil
+	PathRewrite func(c fiber.Ctx) string
+
 	// ModifyResponse defines a function that allows you to alter the response.
 	//
 	// Optional. Default: nil
@@ -41,6 +48,23 @@ type Config struct {
 	// Optional. Default: 10 * time.Second.
 	CacheDuration time.Duration `json:"cache_duration"`
 
+	// MemoryCacheSize is the total size in bytes of an in-memory LRU cache, which serves the
+	// responses of small files from memory instead of the file system. 0 disables the cache.
+	//
+	// Optional. Default: 0.
+	MemoryCacheSize int `json:"memory_cache_size"`
+
+	// MemoryCacheMaxEntrySize is the maximum size in bytes of a file which is cached in memory.
+	//
+	// Optional. Default: 64 * 1024.
+	MemoryCacheMaxEntrySize int `json:"memory_cache_max_entry_size"`
+
+	// MemoryCacheTTL is the duration for which a file is served from memory,
+	// so changes of the file are picked up afterwards.
+	//
+	// Optional. Default: time.Minute.
+	MemoryCacheTTL time.Duration `json:"memory_cache_ttl"`
+
 	// The value for the Cache-Control HTTP-header
 	// that is set on the file response. MaxAge is defined in seconds.
 	//
@@ -48,6 +72,8 @@ type Config struct {
 	MaxAge int `json:"max_age"`
 
 	// When set to true, the server tries minimizing CPU usage by caching compressed files.
+	// Precompressed variants next to the files, e.g. "app.js.br" and "app.js.gz", are
+	// served as they are for the requests which accept their encoding.
 	// This works differently than the github.com/gofiber/compression middleware.
 	//
 	// Optional. Default: false
@@ -63,16 +89,48 @@ type Config struct {
 	// Optional. Default: false.
 	Browse bool `json:"browse"`
 
+	// BrowseRender renders the listings of directories without an index file if Browse is
+	// enabled, e.g. with c.Render and a template of the app. The entries of the listing
+	// are sorted by the "sort" and "order" query parameters, e.g. "?sort=size&order=desc".
+	//
+	// Optional. Default: nil, which renders a sortable HTML table if BrowseFilter is set,
+	// or the directory index page of fasthttp otherwise.
+	BrowseRender func(c fiber.Ctx, listing Listing) error
+
+	// BrowseFilter reports whether an entry is listed in the listings of directories,
+	// e.g. to hide the files whose names start with a dot. It doesn't restrict which
+	// files are served.
+	//
+	// Optional. Default: nil
+	BrowseFilter func(entry Entry) bool
+
 	// When set to true, enables direct download.
 	//
 	// Optional. Default: false.
 	Download bool `json:"download"`
+
+	// When set to true, enables the fallback of single page applications with history mode
+	// routing: the index file of the root is served for paths which aren't found, unless
+	// the last segment of the path has an extension, e.g. "/assets/app.js", or the path
+	// starts with one of SPAExclude. The index file is served with "Cache-Control: no-cache",
+	// so new deployments are picked up, while the other files keep MaxAge.
+	//
+	// Optional. Default: false.
+	SPA bool `json:"spa"`
+
+	// The path prefixes, relative to the prefix of the route, which are never answered with
+	// the index file in SPA mode, e.g. []string{"/api", "/assets"}.
+	//
+	// Optional. Default: nil.
+	SPAExclude []string `json:"spa_exclude"`
 }
 
 // ConfigDefault is the default config
 var ConfigDefault = Config{
-	IndexNames:    []string{"index.html"},
-	CacheDuration: 10 * time.Second,
+	IndexNames:              []string{"index.html"},
+	CacheDuration:           10 * time.Second,
+	MemoryCacheMaxEntrySize: 64 * 1024,
+	MemoryCacheTTL:          time.Minute,
 }
 
 // Helper function to set default values
@@ -94,5 +152,13 @@ func configDefault(config ...Config) Config {
 		cfg.CacheDuration = ConfigDefault.CacheDuration
 	}
 
+	if cfg.MemoryCacheMaxEntrySize <= 0 {
+		cfg.MemoryCacheMaxEntrySize = ConfigDefault.MemoryCacheMaxEntrySize
+	}
+
+	if cfg.MemoryCacheTTL <= 0 {
+		cfg.MemoryCacheTTL = ConfigDefault.MemoryCacheTTL
+	}
+
 	return cfg
 }
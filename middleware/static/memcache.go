@@ -0,0 +1,89 @@
+package static
+
+import (
+	"bytes"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/storage/memory"
+)
+
+// cachedHeaders are the headers of the responses of files which are kept in the memory cache
+var cachedHeaders = []string{
+	fiber.HeaderContentType,
+	fiber.HeaderContentEncoding,
+	fiber.HeaderLastModified,
+	fiber.HeaderAcceptRanges,
+	fiber.HeaderVary,
+}
+
+// memoryCacheKey returns the key of the request in the memory cache. As the response
+// may be compressed, the key includes the Accept-Encoding header of the request.
+func memoryCacheKey(c fiber.Ctx, compress bool) string {
+	key := string(c.RequestCtx().Path())
+	if compress {
+		key += "\n" + c.Get(fiber.HeaderAcceptEncoding)
+	}
+	return key
+}
+
+// serveCached serves the response from the memory cache, or 304 Not Modified if the
+// request is fresh. It reports whether the response was cached and whether it's an
+// index file of the SPA mode.
+func serveCached(c fiber.Ctx, store *memory.Storage, key string) (found, spaIndex bool) {
+	data, err := store.Get(key)
+	if err != nil || len(data) == 0 {
+		return false, false
+	}
+
+	// The entry is a flag, the values of cachedHeaders which end with a newline, and the body
+	spaIndex, data = data[0] == 1, data[1:]
+	for _, header := range cachedHeaders {
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			return false, false
+		}
+		if end > 0 {
+			c.Response().Header.SetBytesV(header, data[:end])
+		}
+		data = data[end+1:]
+	}
+
+	c.Status(fiber.StatusOK)
+	if c.Fresh() {
+		c.Status(fiber.StatusNotModified)
+		c.Response().ResetBody()
+		return true, spaIndex
+	}
+	c.Response().SetBodyRaw(data)
+	return true, spaIndex
+}
+
+// storeCached stores the response of a file in the memory cache, unless it's larger than
+// MemoryCacheMaxEntrySize.
+func storeCached(c fiber.Ctx, store *memory.Storage, key string, spaIndex bool, cfg *Config) {
+	resp := c.Response()
+	if length := resp.Header.ContentLength(); length < 0 || length > cfg.MemoryCacheMaxEntrySize {
+		return
+	}
+	body := resp.Body()
+	if len(body) > cfg.MemoryCacheMaxEntrySize {
+		return
+	}
+
+	size := 1 + len(body)
+	for _, header := range cachedHeaders {
+		size += len(resp.Header.Peek(header)) + 1
+	}
+	data := make([]byte, 1, size)
+	if spaIndex {
+		data[0] = 1
+	}
+	for _, header := range cachedHeaders {
+		data = append(data, resp.Header.Peek(header)...)
+		data = append(data, '\n')
+	}
+	data = append(data, body...)
+
+	// Entries which don't fit into the cache at all aren't cached
+	_ = store.Set(key, data, cfg.MemoryCacheTTL) //nolint:errcheck // see above
+}
@@ -0,0 +1,74 @@
+package static
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+)
+
+// Overlay returns a file system which overlays the file systems in order, e.g. generated
+// assets on top of the defaults of an embed.FS. A file is opened from the first file
+// system which has it, and the listings of directories are merged.
+func Overlay(filesystems ...fs.FS) fs.FS {
+	return overlayFS(filesystems)
+}
+
+// overlayFS is the file system of Overlay
+type overlayFS []fs.FS
+
+// Open opens the file of the first file system which has it.
+func (o overlayFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	err := error(&fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist})
+	for _, filesystem := range o {
+		file, openErr := filesystem.Open(name)
+		if openErr == nil {
+			return file, nil
+		}
+		// Errors other than missing files take precedence
+		if !errors.Is(openErr, fs.ErrNotExist) && errors.Is(err, fs.ErrNotExist) {
+			err = openErr
+		}
+	}
+	return nil, err
+}
+
+// ReadDir merges the entries of the directory of all file systems, sorted by name.
+// An entry of a file system hides the entries with the same name of the next ones.
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	var (
+		entries []fs.DirEntry
+		found   bool
+	)
+	err := error(&fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist})
+	seen := make(map[string]struct{})
+	for _, filesystem := range o {
+		dirEntries, readErr := fs.ReadDir(filesystem, name)
+		if readErr != nil {
+			if !errors.Is(readErr, fs.ErrNotExist) && errors.Is(err, fs.ErrNotExist) {
+				err = readErr
+			}
+			continue
+		}
+		found = true
+		for _, entry := range dirEntries {
+			if _, ok := seen[entry.Name()]; ok {
+				continue
+			}
+			seen[entry.Name()] = struct{}{}
+			entries = append(entries, entry)
+		}
+	}
+	if !found {
+		return nil, err
+	}
+
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
@@ -3,27 +3,37 @@ package static
 import (
 	"fmt"
 	"io/fs"
+	"mime"
 	"os"
+	"path"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/httpcache"
+	"github.com/gofiber/fiber/v3/storage/memory"
 	"github.com/gofiber/utils/v2"
 	"github.com/valyala/fasthttp"
 )
 
 // New creates a new middleware handler.
 // The root argument specifies the root directory from which to serve static assets.
-//
-// Note: Root has to be string or fs.FS, otherwise it will panic.
+// If Config.FS is set, e.g. to an embed.FS, the root is a directory or a file in it.
 func New(root string, cfg ...Config) fiber.Handler {
 	config := configDefault(cfg...)
 
 	var createFS sync.Once
 	var fileHandler fasthttp.RequestHandler
 	var cacheControlValue string
+	var routePrefix string
+	var variantHandler fasthttp.RequestHandler
+	var rootFS fs.FS
+	var memoryCache *memory.Storage
+
+	if config.MemoryCacheSize > 0 {
+		memoryCache = memory.New(memory.Config{MaxBytes: config.MemoryCacheSize})
+	}
 
 	// adjustments for io/fs compatibility
 	if config.FS != nil && root == "" {
@@ -57,64 +67,143 @@ func New(root string, cfg ...Config) fiber.Handler {
 				// /john/ -> /john
 				prefixLen--
 			}
+			routePrefix = strings.TrimSuffix(prefix[:prefixLen], "/")
 
-			fs := &fasthttp.FS{
-				Root:                   root,
-				FS:                     config.FS,
-				AllowEmptyRoot:         true,
-				GenerateIndexPages:     config.Browse,
-				AcceptByteRange:        config.ByteRange,
-				Compress:               config.Compress,
-				CompressBrotli:         config.Compress, // Brotli compression won't work without this
-				CompressedFileSuffixes: c.App().Config().CompressedFileSuffixes,
-				CacheDuration:          config.CacheDuration,
-				SkipCache:              config.CacheDuration < 0,
-				IndexNames:             config.IndexNames,
-				PathNotFound: func(fctx *fasthttp.RequestCtx) {
-					fctx.Response.SetStatusCode(fiber.StatusNotFound)
-				},
+			// A directory of the file system is served as its own file system, e.g. the
+			// "dist" directory of an embed.FS, so the paths of the files match with FS as well
+			filesystem := config.FS
+			if filesystem != nil && root != "." {
+				dir := strings.Trim(path.Clean("/"+root), "/")
+				if checkFile, err := isFile(dir, filesystem); err == nil && !checkFile {
+					if sub, err := fs.Sub(filesystem, dir); err == nil {
+						filesystem, root = sub, "."
+					}
+				}
 			}
+			checkFile, checkErr := isFile(root, filesystem)
 
-			fs.PathRewrite = func(fctx *fasthttp.RequestCtx) []byte {
-				path := fctx.Path()
+			newFS := func(compress bool) *fasthttp.FS {
+				fs := &fasthttp.FS{
+					Root:                   root,
+					FS:                     filesystem,
+					AllowEmptyRoot:         true,
+					GenerateIndexPages:     config.Browse,
+					AcceptByteRange:        config.ByteRange,
+					Compress:               compress,
+					CompressBrotli:         compress, // Brotli compression won't work without this
+					CompressedFileSuffixes: c.App().Config().CompressedFileSuffixes,
+					CacheDuration:          config.CacheDuration,
+					SkipCache:              config.CacheDuration < 0,
+					IndexNames:             config.IndexNames,
+					PathNotFound: func(fctx *fasthttp.RequestCtx) {
+						fctx.Response.SetStatusCode(fiber.StatusNotFound)
+					},
+				}
 
-				if len(path) >= prefixLen {
-					checkFile, err := isFile(root, fs.FS)
-					if err != nil {
-						return path
-					}
+				fs.PathRewrite = func(fctx *fasthttp.RequestCtx) []byte {
+					path := fctx.Path()
+
+					if len(path) >= prefixLen {
+						if checkErr != nil {
+							return path
+						}
 
-					// If the root is a file, we need to reset the path to "/" always.
-					switch {
-					case checkFile && fs.FS == nil:
-						path = []byte("/")
-					case checkFile && fs.FS != nil:
-						path = utils.UnsafeBytes(root)
-					default:
-						path = path[prefixLen:]
-						if len(path) == 0 || path[len(path)-1] != '/' {
-							path = append(path, '/')
+						// If the root is a file, we need to reset the path to "/" always.
+						switch {
+						case checkFile && fs.FS == nil:
+							path = []byte("/")
+						case checkFile && fs.FS != nil:
+							path = utils.UnsafeBytes(root)
+						default:
+							path = path[prefixLen:]
+							if len(path) == 0 || path[len(path)-1] != '/' {
+								path = append(path, '/')
+							}
 						}
 					}
+
+					if len(path) > 0 && path[0] != '/' {
+						path = append([]byte("/"), path...)
+					}
+
+					return path
 				}
 
-				if len(path) > 0 && path[0] != '/' {
-					path = append([]byte("/"), path...)
+				return fs
+			}
+
+			fileHandler = newFS(config.Compress).NewRequestHandler()
+
+			if checkErr == nil && !checkFile {
+				rootFS = filesystem
+				if rootFS == nil {
+					rootFS = os.DirFS(root)
 				}
 
-				return path
+				// Precompressed variants are served as they are, next to the original files
+				if config.Compress {
+					variantHandler = newFS(false).NewRequestHandler()
+				}
 			}
 
 			maxAge := config.MaxAge
 			if maxAge > 0 {
-				cacheControlValue = "public, max-age=" + strconv.Itoa(maxAge)
+				cc := httpcache.NewCacheControl()
+				cc.Public = true
+				cc.MaxAge = maxAge
+				cacheControlValue = cc.String()
 			}
-
-			fileHandler = fs.NewRequestHandler()
 		})
 
-		// Serve file
-		fileHandler(c.RequestCtx())
+		// Rewrite the path of the file, the original path is restored for the next handlers
+		requestPath := c.Path()
+		restorePath := func() {}
+		if config.PathRewrite != nil {
+			uri := c.Request().URI()
+			original := utils.CopyBytes(uri.Path())
+			restorePath = func() { uri.SetPathBytes(original) }
+			defer restorePath()
+
+			requestPath = routePrefix + "/" + strings.TrimPrefix(config.PathRewrite(c), "/")
+			uri.SetPath(requestPath)
+		}
+
+		// Serve the file from the memory cache, unless parts of it are requested
+		var cacheKey string
+		var cached bool
+		spaIndex := config.SPA && isRootPath(requestPath, routePrefix)
+		if memoryCache != nil && c.Get(fiber.HeaderRange) == "" {
+			cacheKey = memoryCacheKey(c, config.Compress)
+			var cachedIndex bool
+			if cached, cachedIndex = serveCached(c, memoryCache, cacheKey); cachedIndex {
+				spaIndex = true
+			}
+		}
+
+		if !cached {
+			// Render the listing of a directory
+			if config.Browse && (config.BrowseRender != nil || config.BrowseFilter != nil) && rootFS != nil {
+				if listed, err := serveListing(c, &config, rootFS, routePrefix); listed {
+					return err
+				}
+			}
+
+			// Serve the precompressed variant of the file if there is one, or the file
+			if variantHandler == nil || !servePrecompressed(c, variantHandler, rootFS, routePrefix) {
+				fileHandler(c.RequestCtx())
+			}
+
+			// Serve the index file of the root for the routes of single page applications
+			fallback := false
+			if config.SPA && c.Response().StatusCode() == fiber.StatusNotFound && isSPARoute(requestPath, routePrefix, config.SPAExclude) {
+				serveIndex(c, fileHandler, routePrefix)
+				spaIndex, fallback = true, true
+			}
+
+			if cacheKey != "" && method == fiber.MethodGet && c.Response().StatusCode() == fiber.StatusOK {
+				storeCached(c, memoryCache, cacheKey, fallback, &config)
+			}
+		}
 
 		// Sets the response Content-Disposition header to attachment if the Download option is true
 		if config.Download {
@@ -125,7 +214,11 @@ func New(root string, cfg ...Config) fiber.Handler {
 		status := c.RequestCtx().Response.StatusCode()
 
 		if status != fiber.StatusNotFound && status != fiber.StatusForbidden {
-			if len(cacheControlValue) > 0 {
+			switch {
+			case spaIndex:
+				// The index file must be revalidated, as it references the files of the current deployment
+				c.RequestCtx().Response.Header.Set(fiber.HeaderCacheControl, "no-cache")
+			case len(cacheControlValue) > 0:
 				c.RequestCtx().Response.Header.Set(fiber.HeaderCacheControl, cacheControlValue)
 			}
 
@@ -136,6 +229,8 @@ func New(root string, cfg ...Config) fiber.Handler {
 			return nil
 		}
 
+		restorePath()
+
 		// Return custom 404 handler if provided.
 		if config.NotFoundHandler != nil {
 			return config.NotFoundHandler(c)
@@ -151,6 +246,93 @@ func New(root string, cfg ...Config) fiber.Handler {
 	}
 }
 
+// serveIndex serves the index file of the root by serving the root directory,
+// and restores the path of the request afterwards.
+func serveIndex(c fiber.Ctx, fileHandler fasthttp.RequestHandler, prefix string) {
+	uri := c.Request().URI()
+	original := utils.CopyBytes(uri.Path())
+
+	c.Response().SetStatusCode(fiber.StatusOK)
+	uri.SetPath(prefix + "/")
+	fileHandler(c.RequestCtx())
+	uri.SetPathBytes(original)
+}
+
+// precompressedEncodings are the encodings of precompressed variants, in the order of preference.
+var precompressedEncodings = []struct {
+	encoding string
+	suffix   string
+}{
+	{encoding: "br", suffix: ".br"},
+	{encoding: "gzip", suffix: ".gz"},
+}
+
+// servePrecompressed serves the precompressed variant of the requested file, e.g. "app.js.br",
+// for the first encoding which is accepted by the request and has a variant. It reports whether
+// a variant was served.
+func servePrecompressed(c fiber.Ctx, variantHandler fasthttp.RequestHandler, filesystem fs.FS, prefix string) bool {
+	name := strings.Trim(strings.TrimPrefix(string(c.RequestCtx().Path()), prefix), "/")
+	if name == "" || !fs.ValidPath(name) || c.Get(fiber.HeaderAcceptEncoding) == "" {
+		return false
+	}
+
+	for _, variant := range precompressedEncodings {
+		if c.AcceptsEncodings(variant.encoding) == "" {
+			continue
+		}
+		if stat, err := fs.Stat(filesystem, name+variant.suffix); err != nil || !stat.Mode().IsRegular() {
+			continue
+		}
+
+		uri := c.Request().URI()
+		original := utils.CopyBytes(uri.Path())
+		uri.SetPath(prefix + "/" + name + variant.suffix)
+		variantHandler(c.RequestCtx())
+		uri.SetPathBytes(original)
+
+		status := c.Response().StatusCode()
+		if status == fiber.StatusNotFound || status == fiber.StatusForbidden {
+			c.Response().SetStatusCode(fiber.StatusOK)
+			return false
+		}
+
+		// The headers describe the original file, which is encoded
+		contentType := mime.TypeByExtension(path.Ext(name))
+		if contentType == "" {
+			contentType = fiber.MIMEOctetStream
+		}
+		c.Set(fiber.HeaderContentType, contentType)
+		c.Set(fiber.HeaderContentEncoding, variant.encoding)
+		c.Vary(fiber.HeaderAcceptEncoding)
+		return true
+	}
+	return false
+}
+
+// isRootPath reports whether the path is the root of the route prefix.
+func isRootPath(p, prefix string) bool {
+	return strings.Trim(strings.TrimPrefix(p, prefix), "/") == ""
+}
+
+// isSPARoute reports whether the path is a route of a single page application, which
+// is one without an extension in its last segment and without an excluded prefix.
+func isSPARoute(p, prefix string, exclude []string) bool {
+	rel := strings.TrimPrefix(p, prefix)
+	if rel == "" || rel[0] != '/' {
+		rel = "/" + rel
+	}
+	if path.Ext(path.Base(rel)) != "" {
+		return false
+	}
+	for _, excluded := range exclude {
+		excluded = "/" + strings.Trim(excluded, "/")
+		if excluded == "/" || rel == excluded || strings.HasPrefix(rel, excluded+"/") {
+			return false
+		}
+	}
+	return true
+}
+
 // isFile checks if the root is a file.
 func isFile(root string, filesystem fs.FS) (bool, error) {
 	var file fs.File
@@ -168,6 +350,8 @@ func isFile(root string, filesystem fs.FS) (bool, error) {
 		}
 	}
 
+	defer file.Close() //nolint:errcheck // The file is only read
+
 	stat, err := file.Stat()
 	if err != nil {
 		return false, fmt.Errorf("static: %w", err)
@@ -4,11 +4,14 @@ import (
 	"embed"
 	"io"
 	"io/fs"
+	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
@@ -555,14 +558,13 @@ func Test_Static_FS(t *testing.T) {
 	require.Contains(t, string(body), "color")
 }
 
-/*func Test_Static_FS_DifferentRoot(t *testing.T) {
+func Test_Static_FS_DifferentRoot(t *testing.T) {
 	t.Parallel()
 
 	app := fiber.New()
 	app.Get("/*", New("fs", Config{
-		FS:         os.DirFS("../../.github/testdata"),
-		IndexNames: []string{"index2.html"},
-		Browse:     true,
+		FS:     os.DirFS("../../.github/testdata"),
+		Browse: true,
 	}))
 
 	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
@@ -582,7 +584,410 @@ func Test_Static_FS(t *testing.T) {
 	body, err = io.ReadAll(resp.Body)
 	require.NoError(t, err, "app.Test(req)")
 	require.Contains(t, string(body), "color")
-}*/
+}
+
+// go test -run Test_Static_FS_Features
+func Test_Static_FS_Features(t *testing.T) {
+	t.Parallel()
+
+	index := strings.Repeat("<p>Hello, World!</p>", 100)
+	fsys := fstest.MapFS{
+		"dist/index.html":     {Data: []byte(index)},
+		"dist/assets/app.js":  {Data: []byte("console.log('app')")},
+		"dist/assets/app.css": {Data: []byte("body { color: red; }")},
+	}
+
+	app := fiber.New()
+	app.Get("/*", New("dist", Config{
+		FS:        fsys,
+		ByteRange: true,
+		Compress:  true,
+		MaxAge:    3600,
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "public, max-age=3600", resp.Header.Get(fiber.HeaderCacheControl))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, index, string(body))
+
+	req := httptest.NewRequest(fiber.MethodGet, "/assets/app.js", nil)
+	req.Header.Set(fiber.HeaderRange, "bytes=0-6")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusPartialContent, resp.StatusCode)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "console", string(body))
+
+	req = httptest.NewRequest(fiber.MethodGet, "/index.html", nil)
+	req.Header.Set(fiber.HeaderAcceptEncoding, "gzip")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "gzip", resp.Header.Get(fiber.HeaderContentEncoding))
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Less(t, len(body), len(index))
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/dist/index.html", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+func Test_Static_Precompressed(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"assets/app.js":     {Data: []byte("console.log('app')")},
+		"assets/app.js.br":  {Data: []byte("brotli")},
+		"assets/app.js.gz":  {Data: []byte("gzip")},
+		"assets/app.css":    {Data: []byte("body { color: red; }")},
+		"assets/app.css.gz": {Data: []byte("gzip")},
+	}
+
+	app := fiber.New()
+	app.Use("/static", New("assets", Config{
+		FS:        fsys,
+		Compress:  true,
+		ByteRange: true,
+	}))
+
+	testCases := []struct {
+		path            string
+		acceptEncoding  string
+		body            string
+		contentType     string
+		contentEncoding string
+	}{
+		{path: "/static/app.js", acceptEncoding: "gzip, br", body: "brotli", contentType: "text/javascript; charset=utf-8", contentEncoding: "br"},
+		{path: "/static/app.js", acceptEncoding: "gzip", body: "gzip", contentType: "text/javascript; charset=utf-8", contentEncoding: "gzip"},
+		{path: "/static/app.js", acceptEncoding: "br;q=0, gzip", body: "gzip", contentType: "text/javascript; charset=utf-8", contentEncoding: "gzip"},
+		{path: "/static/app.js", body: "console.log('app')", contentType: "text/javascript; charset=utf-8"},
+		{path: "/static/app.css", acceptEncoding: "br, gzip", body: "gzip", contentType: "text/css; charset=utf-8", contentEncoding: "gzip"},
+		{path: "/static/app.js.gz", acceptEncoding: "br", body: "gzip", contentType: "application/gzip"},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest(fiber.MethodGet, tc.path, nil)
+		if tc.acceptEncoding != "" {
+			req.Header.Set(fiber.HeaderAcceptEncoding, tc.acceptEncoding)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err, tc.path)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, tc.path)
+		require.Equal(t, tc.contentType, resp.Header.Get(fiber.HeaderContentType), tc.path)
+		require.Equal(t, tc.contentEncoding, resp.Header.Get(fiber.HeaderContentEncoding), tc.path)
+		if tc.contentEncoding != "" {
+			require.Equal(t, fiber.HeaderAcceptEncoding, resp.Header.Get(fiber.HeaderVary), tc.path)
+		}
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, tc.path)
+		require.Equal(t, tc.body, string(body), tc.path)
+	}
+
+	// Byte ranges apply to the precompressed variant
+	req := httptest.NewRequest(fiber.MethodGet, "/static/app.js", nil)
+	req.Header.Set(fiber.HeaderAcceptEncoding, "br")
+	req.Header.Set(fiber.HeaderRange, "bytes=0-1")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusPartialContent, resp.StatusCode)
+	require.Equal(t, "br", resp.Header.Get(fiber.HeaderContentEncoding))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "br", string(body))
+
+	// Without Compress, the variants aren't used
+	app = fiber.New()
+	app.Use(New("assets", Config{FS: fsys}))
+	req = httptest.NewRequest(fiber.MethodGet, "/app.js", nil)
+	req.Header.Set(fiber.HeaderAcceptEncoding, "br")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "", resp.Header.Get(fiber.HeaderContentEncoding))
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "console.log('app')", string(body))
+}
+
+func Test_Static_BrowseRender(t *testing.T) {
+	t.Parallel()
+
+	modTime := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"share/b.txt":            {Data: []byte("bb"), ModTime: modTime},
+		"share/a.txt":            {Data: []byte("aaa"), ModTime: modTime.Add(time.Hour)},
+		"share/.env":             {Data: []byte("SECRET=1")},
+		"share/docs/c.txt":       {Data: []byte("c")},
+		"share/site/index.html":  {Data: []byte("index")},
+		"share/docs/.git/config": {Data: []byte("")},
+	}
+
+	var listing Listing
+	app := fiber.New()
+	app.Use("/files", New("share", Config{
+		FS:     fsys,
+		Browse: true,
+		BrowseRender: func(c fiber.Ctx, l Listing) error {
+			listing = l
+			names := make([]string, 0, len(l.Entries))
+			for _, entry := range l.Entries {
+				names = append(names, entry.Name)
+			}
+			return c.SendString(strings.Join(names, ","))
+		},
+		BrowseFilter: func(entry Entry) bool {
+			return !strings.HasPrefix(entry.Name, ".")
+		},
+	}))
+
+	testCases := []struct {
+		path string
+		body string
+	}{
+		{path: "/files", body: "docs,site,a.txt,b.txt"},
+		{path: "/files/?sort=size", body: "docs,site,b.txt,a.txt"},
+		{path: "/files/?sort=size&order=desc", body: "site,docs,a.txt,b.txt"},
+		{path: "/files/?sort=modtime&order=desc", body: "site,docs,a.txt,b.txt"},
+		{path: "/files/docs/", body: "c.txt"},
+		{path: "/files/site/", body: "index"},
+		{path: "/files/a.txt", body: "aaa"},
+		{path: "/files/.env", body: "SECRET=1"},
+	}
+	for _, tc := range testCases {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tc.path, nil))
+		require.NoError(t, err, tc.path)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, tc.path)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, tc.path)
+		require.Equal(t, tc.body, string(body), tc.path)
+	}
+
+	_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/files/docs?sort=size", nil))
+	require.NoError(t, err)
+	require.Equal(t, "/files/docs/", listing.Path)
+	require.Equal(t, "/files/", listing.Parent)
+	require.Equal(t, "size", listing.Sort)
+	require.Equal(t, "asc", listing.Order)
+	require.Equal(t, []Entry{{Name: "c.txt", Path: "/files/docs/c.txt", Size: 1}}, listing.Entries)
+
+	// Without BrowseRender, the filtered listing is rendered as a sortable HTML table
+	app = fiber.New()
+	app.Get("/*", New("share", Config{
+		FS:     fsys,
+		Browse: true,
+		BrowseFilter: func(entry Entry) bool {
+			return !strings.HasPrefix(entry.Name, ".")
+		},
+	}))
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/?sort=name", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, fiber.MIMETextHTMLCharsetUTF8, resp.Header.Get(fiber.HeaderContentType))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `<a href="/a.txt">a.txt</a>`)
+	require.Contains(t, string(body), `<a href="/docs/">docs/</a>`)
+	require.Contains(t, string(body), `<a href="?sort=name&amp;order=desc">Name</a>`)
+	require.NotContains(t, string(body), ".env")
+}
+
+func Test_Static_MemoryCache(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "small.txt"), []byte("small"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "large.txt"), []byte(strings.Repeat("l", 100)), 0o600))
+
+	app := fiber.New()
+	app.Get("/*", New(dir, Config{
+		CacheDuration:           -1,
+		MemoryCacheSize:         1024,
+		MemoryCacheMaxEntrySize: 64,
+		MemoryCacheTTL:          time.Hour,
+		MaxAge:                  60,
+	}))
+
+	get := func(path string, headers ...string) (*http.Response, string) {
+		t.Helper()
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+
+	resp, body := get("/small.txt")
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "small", body)
+	lastModified := resp.Header.Get(fiber.HeaderLastModified)
+	require.NotEmpty(t, lastModified)
+	_, body = get("/large.txt")
+	require.Equal(t, strings.Repeat("l", 100), body)
+
+	// Small files are served from memory, large files from the file system
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "small.txt"), []byte("SMALL"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "large.txt"), []byte(strings.Repeat("L", 100)), 0o600))
+
+	resp, body = get("/small.txt")
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "small", body)
+	require.Equal(t, fiber.MIMETextPlainCharsetUTF8, resp.Header.Get(fiber.HeaderContentType))
+	require.Equal(t, lastModified, resp.Header.Get(fiber.HeaderLastModified))
+	require.Equal(t, "public, max-age=60", resp.Header.Get(fiber.HeaderCacheControl))
+	_, body = get("/large.txt")
+	require.Equal(t, strings.Repeat("L", 100), body)
+
+	// Conditional requests are answered from memory
+	resp, body = get("/small.txt", fiber.HeaderIfModifiedSince, lastModified)
+	require.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+	require.Empty(t, body)
+
+	// Byte ranges are served from the file system
+	resp, body = get("/small.txt", fiber.HeaderRange, "bytes=0-1")
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "SMALL", body)
+}
+
+func Test_Static_Overlay(t *testing.T) {
+	t.Parallel()
+
+	generated := fstest.MapFS{
+		"theme.css":      {Data: []byte("generated theme")},
+		"img/banner.png": {Data: []byte("generated banner")},
+	}
+	defaults := fstest.MapFS{
+		"theme.css":    {Data: []byte("default theme")},
+		"app.js":       {Data: []byte("default app")},
+		"img/logo.png": {Data: []byte("default logo")},
+	}
+
+	app := fiber.New()
+	app.Use("/static", New("", Config{
+		FS:     Overlay(generated, defaults),
+		Browse: true,
+		BrowseRender: func(c fiber.Ctx, listing Listing) error {
+			names := make([]string, 0, len(listing.Entries))
+			for _, entry := range listing.Entries {
+				names = append(names, entry.Name)
+			}
+			return c.SendString(strings.Join(names, ","))
+		},
+		PathRewrite: func(c fiber.Ctx) string {
+			// Strip the version of the assets, e.g. "/static/v1.2.0/app.js"
+			rel := strings.TrimPrefix(c.Path(), "/static")
+			if version, file, ok := strings.Cut(strings.TrimPrefix(rel, "/"), "/"); ok && strings.HasPrefix(version, "v") {
+				return file
+			}
+			return rel
+		},
+	}))
+	app.Use(func(c fiber.Ctx) error {
+		return c.Status(fiber.StatusNotFound).SendString(string(c.Request().URI().Path()))
+	})
+
+	testCases := []struct {
+		path   string
+		body   string
+		status int
+	}{
+		{path: "/static/theme.css", status: fiber.StatusOK, body: "generated theme"},
+		{path: "/static/app.js", status: fiber.StatusOK, body: "default app"},
+		{path: "/static/v1.2.0/app.js", status: fiber.StatusOK, body: "default app"},
+		{path: "/static/img/logo.png", status: fiber.StatusOK, body: "default logo"},
+		{path: "/static/img/banner.png", status: fiber.StatusOK, body: "generated banner"},
+		{path: "/static/img/", status: fiber.StatusOK, body: "banner.png,logo.png"},
+		{path: "/static/", status: fiber.StatusOK, body: "img,app.js,theme.css"},
+		{path: "/static/v1.2.0/missing.js", status: fiber.StatusNotFound, body: "/static/v1.2.0/missing.js"},
+	}
+	for _, tc := range testCases {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tc.path, nil))
+		require.NoError(t, err, tc.path)
+		require.Equal(t, tc.status, resp.StatusCode, tc.path)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, tc.path)
+		require.Equal(t, tc.body, string(body), tc.path)
+	}
+
+	// Errors other than missing files are returned
+	_, err := Overlay(generated, defaults).Open("../theme.css")
+	require.ErrorIs(t, err, fs.ErrInvalid)
+	_, err = fs.ReadDir(Overlay(generated, defaults), "missing")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func Test_Static_SPA(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"dist/index.html":    {Data: []byte("<div id=app></div>")},
+		"dist/assets/app.js": {Data: []byte("console.log('app')")},
+	}
+
+	app := fiber.New()
+	app.Use("/web", New("dist", Config{
+		FS:         fsys,
+		MaxAge:     3600,
+		SPA:        true,
+		SPAExclude: []string{"/api", "/assets"},
+	}))
+	app.Use(func(c fiber.Ctx) error {
+		return c.Status(fiber.StatusTeapot).SendString("next")
+	})
+
+	testCases := []struct {
+		path         string
+		body         string
+		cacheControl string
+		status       int
+	}{
+		{path: "/web", status: fiber.StatusOK, body: "<div id=app></div>", cacheControl: "no-cache"},
+		{path: "/web/", status: fiber.StatusOK, body: "<div id=app></div>", cacheControl: "no-cache"},
+		{path: "/web/users/1", status: fiber.StatusOK, body: "<div id=app></div>", cacheControl: "no-cache"},
+		{path: "/web/settings/profile/", status: fiber.StatusOK, body: "<div id=app></div>", cacheControl: "no-cache"},
+		{path: "/web/assets/app.js", status: fiber.StatusOK, body: "console.log('app')", cacheControl: "public, max-age=3600"},
+		{path: "/web/assets/missing.js", status: fiber.StatusTeapot, body: "next"},
+		{path: "/web/assets/chunks", status: fiber.StatusTeapot, body: "next"},
+		{path: "/web/missing.png", status: fiber.StatusTeapot, body: "next"},
+		{path: "/web/api/users", status: fiber.StatusTeapot, body: "next"},
+		{path: "/web/apis", status: fiber.StatusOK, body: "<div id=app></div>", cacheControl: "no-cache"},
+	}
+	for _, tc := range testCases {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tc.path, nil))
+		require.NoError(t, err, tc.path)
+		require.Equal(t, tc.status, resp.StatusCode, tc.path)
+		require.Equal(t, tc.cacheControl, resp.Header.Get(fiber.HeaderCacheControl), tc.path)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, tc.path)
+		require.Equal(t, tc.body, string(body), tc.path)
+	}
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodHead, "/web/users/1", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "no-cache", resp.Header.Get(fiber.HeaderCacheControl))
+	require.Equal(t, fiber.MIMETextHTMLCharsetUTF8, resp.Header.Get(fiber.HeaderContentType))
+
+	// Without an index file, the next handler gets the original path of the request
+	app = fiber.New()
+	app.Use(New("dist/assets", Config{FS: fsys, SPA: true}))
+	app.Use(func(c fiber.Ctx) error {
+		return c.SendString(string(c.Request().URI().Path()))
+	})
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/users/1", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "/users/1", string(body))
+}
 
 //go:embed static.go config.go
 var fsTestFilesystem embed.FS
@@ -0,0 +1,95 @@
+package tenant
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Resolver returns the ID of the tenant of the request, or an empty string if the
+	// request has no tenant. It overrides KeyLookup.
+	//
+	// Optional. Default: nil
+	Resolver func(c fiber.Ctx) (string, error)
+
+	// Lookup returns the tenant of the ID, e.g. from a database, or nil if the tenant
+	// doesn't exist.
+	//
+	// Optional. Default: a function which returns a Tenant with the ID
+	Lookup func(c fiber.Ctx, id string) (*Tenant, error)
+
+	// ErrorHandler is called if the tenant can't be resolved, with ErrMissingTenant,
+	// ErrInvalidTenant, ErrTenantNotFound or the error of Resolver or Lookup.
+	//
+	// Optional. Default: a function which responds with 400 for a missing or invalid
+	// tenant and 404 for an unknown tenant
+	ErrorHandler fiber.ErrorHandler
+
+	// KeyLookup is a comma separated list of "<source>:<name>" strings, which are
+	// tried in order to resolve the tenant ID.
+	// Possible values:
+	// - "header:<name>"
+	// - "query:<name>"
+	// - "subdomain", "subdomain:<offset>", the first subdomain, where offset is the
+	//   number of labels of the domain, e.g. 3 for "acme.example.co.uk"
+	// - "path", "path:<prefix>", the first path segment after the prefix, e.g.
+	//   "acme" of "/t/acme/users" for "path:/t"
+	//
+	// Optional. Default: "header:X-Tenant-ID"
+	KeyLookup string
+
+	// Optional allows requests without a tenant, FromContext returns nil for them.
+	//
+	// Optional. Default: false
+	Optional bool
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:     nil,
+	Resolver: nil,
+	Lookup: func(_ fiber.Ctx, id string) (*Tenant, error) {
+		return &Tenant{ID: id}, nil
+	},
+	ErrorHandler: func(c fiber.Ctx, err error) error {
+		switch {
+		case errors.Is(err, ErrMissingTenant), errors.Is(err, ErrInvalidTenant):
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		case errors.Is(err, ErrTenantNotFound):
+			return c.Status(fiber.StatusNotFound).SendString(err.Error())
+		}
+		return err
+	},
+	KeyLookup: "header:X-Tenant-ID",
+	Optional:  false,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Lookup == nil {
+		cfg.Lookup = ConfigDefault.Lookup
+	}
+	if cfg.ErrorHandler == nil {
+		cfg.ErrorHandler = ConfigDefault.ErrorHandler
+	}
+	if cfg.KeyLookup == "" {
+		cfg.KeyLookup = ConfigDefault.KeyLookup
+	}
+	return cfg
+}
@@ -0,0 +1,96 @@
+package tenant
+
+import (
+	"sync"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/logger"
+	"github.com/gofiber/fiber/v3/storage"
+)
+
+// Storage returns the storage of the tenant, which prepends "tenant:<id>:" to all
+// keys of the shared storage. Reset only deletes the keys of the tenant.
+//
+//	shared := redis.New()
+//	store := tenant.Storage(shared, tenant.FromContext(c))
+func Storage(shared fiber.Storage, t *Tenant) fiber.Storage {
+	return storage.WithPrefix(shared, "tenant:"+t.ID+":")
+}
+
+// Scoped returns a handler which calls the handler of the tenant of the request, which
+// is created by factory on the first request of the tenant. It is used to give every
+// tenant its own middleware instance, e.g. a session middleware with the storage of
+// the tenant or a limiter with the limit of its plan. Requests without a tenant are
+// passed to the next handler.
+//
+//	app.Use(tenant.Scoped(func(t *tenant.Tenant) fiber.Handler {
+//		return session.New(session.Config{Storage: tenant.Storage(shared, t)})
+//	}))
+func Scoped(factory func(t *Tenant) fiber.Handler) fiber.Handler {
+	var (
+		mu       sync.RWMutex
+		handlers = make(map[string]fiber.Handler)
+	)
+	return func(c fiber.Ctx) error {
+		t := FromContext(c)
+		if t == nil {
+			return c.Next()
+		}
+
+		mu.RLock()
+		handler, ok := handlers[t.ID]
+		mu.RUnlock()
+		if !ok {
+			mu.Lock()
+			// The handler may have been created while the lock was released
+			if handler, ok = handlers[t.ID]; !ok {
+				handler = factory(t)
+				handlers[t.ID] = handler
+			}
+			mu.Unlock()
+		}
+		return handler(c)
+	}
+}
+
+// KeyGenerator returns a key generator, e.g. for the limiter middleware, which prepends
+// the ID of the tenant of the request to the keys of next, so the keys of tenants don't
+// collide in a shared storage.
+//
+//	app.Use(limiter.New(limiter.Config{
+//		KeyGenerator: tenant.KeyGenerator(func(c fiber.Ctx) string {
+//			return c.IP()
+//		}),
+//	}))
+func KeyGenerator(next func(c fiber.Ctx) string) func(c fiber.Ctx) string {
+	return func(c fiber.Ctx) string {
+		if t := FromContext(c); t != nil {
+			return t.ID + ":" + next(c)
+		}
+		return next(c)
+	}
+}
+
+// Fields returns the log fields of the tenant of the request as key and value pairs
+// for the structured log functions, e.g. log.Infow("order created", tenant.Fields(c)...).
+// It returns nil for requests without a tenant.
+func Fields(c fiber.Ctx) []any {
+	if t := FromContext(c); t != nil {
+		return []any{"tenant", t.ID}
+	}
+	return nil
+}
+
+// LogTag is a custom tag of the logger middleware, which writes the ID of the tenant
+// of the request.
+//
+//	app.Use(logger.New(logger.Config{
+//		Format:     "${tenant} ${status} - ${method} ${path}\n",
+//		CustomTags: map[string]logger.LogFunc{"tenant": tenant.LogTag},
+//	}))
+func LogTag(output logger.Buffer, c fiber.Ctx, _ *logger.Data, _ string) (int, error) {
+	if t := FromContext(c); t != nil {
+		return output.WriteString(t.ID)
+	}
+	return output.WriteString("-")
+}
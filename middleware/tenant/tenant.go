@@ -0,0 +1,188 @@
+// Package tenant resolves the tenant of multi-tenant apps by subdomain, header, path
+// prefix or a custom resolver, and provides tenant-scoped storages, handlers, rate limit
+// keys and log fields, so the data of tenants stays isolated.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/utils/v2"
+)
+
+// Errors of the tenant resolution
+var (
+	ErrMissingTenant  = errors.New("tenant: missing tenant")
+	ErrInvalidTenant  = errors.New("tenant: invalid tenant")
+	ErrTenantNotFound = errors.New("tenant: tenant not found")
+)
+
+// maxIDLength is the maximum length of a tenant ID
+const maxIDLength = 64
+
+// Tenant is the tenant of a request
+type Tenant struct {
+	Data any    // Data of the tenant from Config.Lookup, e.g. its plan
+	ID   string // ID of the tenant, it only contains letters, digits, '-' and '_'
+}
+
+// The contextKey type is unexported to prevent collisions with context keys defined in
+// other packages.
+type contextKey int
+
+// The key for the tenant in the locals and the context of the request
+const tenantKey contextKey = 0
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	resolve := cfg.Resolver
+	if resolve == nil {
+		resolve = parseKeyLookup(cfg.KeyLookup)
+	}
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		id, err := resolve(c)
+		if err != nil {
+			return cfg.ErrorHandler(c, err)
+		}
+		if id == "" {
+			if cfg.Optional {
+				return c.Next()
+			}
+			return cfg.ErrorHandler(c, ErrMissingTenant)
+		}
+		if !validID(id) {
+			return cfg.ErrorHandler(c, ErrInvalidTenant)
+		}
+
+		t, err := cfg.Lookup(c, utils.CopyString(id))
+		if err != nil {
+			return cfg.ErrorHandler(c, err)
+		}
+		if t == nil {
+			return cfg.ErrorHandler(c, ErrTenantNotFound)
+		}
+
+		// Add the tenant to locals and the context
+		c.Locals(tenantKey, t)
+		c.SetContext(context.WithValue(c.Context(), tenantKey, t))
+
+		// Continue stack
+		return c.Next()
+	}
+}
+
+// FromContext returns the tenant from context.
+// If there is no tenant, nil is returned.
+// Supported context types:
+// - fiber.Ctx: Retrieves the tenant from Locals
+// - context.Context: Retrieves the tenant from context values
+func FromContext(c any) *Tenant {
+	switch ctx := c.(type) {
+	case fiber.Ctx:
+		if t, ok := ctx.Locals(tenantKey).(*Tenant); ok {
+			return t
+		}
+	case context.Context:
+		if t, ok := ctx.Value(tenantKey).(*Tenant); ok {
+			return t
+		}
+	default:
+		log.Errorf("Unsupported context type: %T. Expected fiber.Ctx or context.Context", c)
+	}
+	return nil
+}
+
+// parseKeyLookup returns a resolver which tries the sources of the KeyLookup in order
+func parseKeyLookup(keyLookup string) func(c fiber.Ctx) (string, error) {
+	var sources []func(c fiber.Ctx) string
+	for _, lookup := range strings.Split(keyLookup, ",") {
+		source, name, _ := strings.Cut(utils.Trim(lookup, ' '), ":")
+		switch source {
+		case "header":
+			sources = append(sources, func(c fiber.Ctx) string {
+				return c.Get(name)
+			})
+		case "query":
+			sources = append(sources, func(c fiber.Ctx) string {
+				return c.Query(name)
+			})
+		case "subdomain":
+			offset := 2
+			if name != "" {
+				var err error
+				if offset, err = strconv.Atoi(name); err != nil || offset < 1 {
+					panic("[tenant] invalid offset of subdomain in KeyLookup")
+				}
+			}
+			sources = append(sources, func(c fiber.Ctx) string {
+				return subdomain(c.Hostname(), offset)
+			})
+		case "path":
+			prefix := "/" + strings.Trim(name, "/")
+			if prefix != "/" {
+				prefix += "/"
+			}
+			sources = append(sources, func(c fiber.Ctx) string {
+				return segment(c.Path(), prefix)
+			})
+		default:
+			panic("[tenant] unsupported source in KeyLookup")
+		}
+	}
+
+	return func(c fiber.Ctx) (string, error) {
+		for _, source := range sources {
+			if id := source(c); id != "" {
+				return id, nil
+			}
+		}
+		return "", nil
+	}
+}
+
+// subdomain returns the first label of the hostname, if it has more labels than the offset
+func subdomain(hostname string, offset int) string {
+	if strings.Count(hostname, ".") < offset {
+		return ""
+	}
+	label, _, _ := strings.Cut(hostname, ".")
+	return label
+}
+
+// segment returns the first path segment after the prefix
+func segment(path, prefix string) string {
+	if !strings.HasPrefix(path, prefix) {
+		return ""
+	}
+	s, _, _ := strings.Cut(path[len(prefix):], "/")
+	return s
+}
+
+// validID reports whether the ID only contains letters, digits, '-' and '_', so it can
+// be used in storage keys and logs
+func validID(id string) bool {
+	if len(id) > maxIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,249 @@
+package tenant
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/internal/storage/memory"
+	"github.com/gofiber/fiber/v3/middleware/limiter"
+	"github.com/gofiber/fiber/v3/middleware/logger"
+	"github.com/gofiber/fiber/v3/middleware/session"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func body(t *testing.T, app *fiber.App, target string, headers ...string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest(fiber.MethodGet, target, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(b)
+}
+
+func tenantHandler(c fiber.Ctx) error {
+	if t := FromContext(c); t != nil {
+		return c.SendString(t.ID)
+	}
+	return c.SendString("none")
+}
+
+// go test -run Test_Tenant_KeyLookup
+func Test_Tenant_KeyLookup(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		keyLookup string
+		target    string
+		header    string
+		tenant    string
+	}{
+		{name: "header", keyLookup: "", target: "/", header: "acme", tenant: "acme"},
+		{name: "query", keyLookup: "query:tenant", target: "/?tenant=acme", tenant: "acme"},
+		{name: "subdomain", keyLookup: "subdomain", target: "http://acme.example.com/", tenant: "acme"},
+		{name: "subdomain offset", keyLookup: "subdomain:3", target: "http://acme.example.co.uk/", tenant: "acme"},
+		{name: "path", keyLookup: "path", target: "/acme/users", tenant: "acme"},
+		{name: "path prefix", keyLookup: "path:/t/", target: "/t/acme", tenant: "acme"},
+		{name: "fallback", keyLookup: "query:tenant, subdomain", target: "http://acme.example.com/", tenant: "acme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			app := fiber.New()
+			app.Use(New(Config{KeyLookup: tt.keyLookup}))
+			app.Get("/*", tenantHandler)
+
+			status, b := body(t, app, tt.target, "X-Tenant-ID", tt.header)
+			require.Equal(t, fiber.StatusOK, status)
+			require.Equal(t, tt.tenant, b)
+		})
+	}
+
+	require.PanicsWithValue(t, "[tenant] unsupported source in KeyLookup", func() {
+		New(Config{KeyLookup: "cookie:tenant"})
+	})
+	require.PanicsWithValue(t, "[tenant] invalid offset of subdomain in KeyLookup", func() {
+		New(Config{KeyLookup: "subdomain:x"})
+	})
+}
+
+// go test -run Test_Tenant_Errors
+func Test_Tenant_Errors(t *testing.T) {
+	t.Parallel()
+	errLookup := errors.New("database down")
+	app := fiber.New()
+	app.Use(New(Config{
+		KeyLookup: "subdomain",
+		Lookup: func(_ fiber.Ctx, id string) (*Tenant, error) {
+			switch id {
+			case "acme":
+				return &Tenant{ID: id, Data: "enterprise"}, nil
+			case "down":
+				return nil, errLookup
+			}
+			return nil, nil
+		},
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(FromContext(c).Data.(string) + " " + FromContext(c.Context()).ID) //nolint:forcetypeassert // We're in a test
+	})
+
+	status, b := body(t, app, "http://acme.example.com/")
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, "enterprise acme", b)
+
+	status, b = body(t, app, "http://example.com/")
+	require.Equal(t, fiber.StatusBadRequest, status)
+	require.Equal(t, ErrMissingTenant.Error(), b)
+
+	status, _ = body(t, app, "http://"+strings.Repeat("a", 65)+".example.com/")
+	require.Equal(t, fiber.StatusBadRequest, status)
+
+	status, b = body(t, app, "http://other.example.com/")
+	require.Equal(t, fiber.StatusNotFound, status)
+	require.Equal(t, ErrTenantNotFound.Error(), b)
+
+	status, _ = body(t, app, "http://down.example.com/")
+	require.Equal(t, fiber.StatusInternalServerError, status)
+}
+
+// go test -run Test_Tenant_Optional
+func Test_Tenant_Optional(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Next: func(c fiber.Ctx) bool {
+			return c.Path() == "/health"
+		},
+		Resolver: func(c fiber.Ctx) (string, error) {
+			return c.Get("X-Org"), nil
+		},
+		Optional: true,
+	}))
+	app.Get("/*", tenantHandler)
+
+	_, b := body(t, app, "/")
+	require.Equal(t, "none", b)
+	_, b = body(t, app, "/", "X-Org", "acme")
+	require.Equal(t, "acme", b)
+	_, b = body(t, app, "/health", "X-Org", "acme")
+	require.Equal(t, "none", b)
+
+	require.Nil(t, FromContext(context.Background()))
+	require.Nil(t, FromContext("invalid"))
+}
+
+// go test -run Test_Tenant_Scoped
+func Test_Tenant_Scoped(t *testing.T) {
+	t.Parallel()
+	shared := memory.New()
+	created := 0
+
+	app := fiber.New()
+	app.Use(New(Config{Optional: true}))
+	app.Use(Scoped(func(t *Tenant) fiber.Handler {
+		created++
+		return session.New(session.Config{Storage: Storage(shared, t)})
+	}))
+	app.Use(limiter.New(limiter.Config{
+		Max: 1,
+		KeyGenerator: KeyGenerator(func(c fiber.Ctx) string {
+			return c.IP()
+		}),
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		sess := session.FromContext(c)
+		if sess == nil {
+			return c.SendString("no session")
+		}
+		sess.Set("visited", true)
+		return c.SendString(sess.ID())
+	})
+
+	// Every tenant has its own session storage
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	id, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	data, err := shared.Get("tenant:acme:" + string(id))
+	require.NoError(t, err)
+	require.NotNil(t, data)
+
+	// The session of a tenant isn't found by another tenant
+	req = httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant-ID", "globex")
+	req.Header.Set(fiber.HeaderCookie, "session_id="+string(id))
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	other, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NotEqual(t, string(id), string(other))
+	require.Equal(t, 2, created)
+
+	// The rate limits of tenants are separate
+	status, _ := body(t, app, "/", "X-Tenant-ID", "acme")
+	require.Equal(t, fiber.StatusTooManyRequests, status)
+	status, _ = body(t, app, "/", "X-Tenant-ID", "globex")
+	require.Equal(t, fiber.StatusTooManyRequests, status)
+	status, b := body(t, app, "/")
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, "no session", b)
+}
+
+// go test -run Test_Tenant_Logging
+func Test_Tenant_Logging(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	var fields []any
+
+	app := fiber.New()
+	app.Use(logger.New(logger.Config{
+		Format:     "${tenant} ${status}\n",
+		CustomTags: map[string]logger.LogFunc{"tenant": LogTag},
+		Output:     &buf,
+	}))
+	app.Use(New(Config{Optional: true}))
+	app.Get("/", func(c fiber.Ctx) error {
+		fields = Fields(c)
+		return nil
+	})
+
+	body(t, app, "/", "X-Tenant-ID", "acme")
+	require.Equal(t, []any{"tenant", "acme"}, fields)
+	body(t, app, "/")
+	require.Nil(t, fields)
+	require.Equal(t, "acme 200\n- 200\n", buf.String())
+}
+
+// go test -v -run=^$ -bench=Benchmark_Tenant -benchmem -count=4
+func Benchmark_Tenant(b *testing.B) {
+	app := fiber.New()
+	app.Use(New())
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(FromContext(c).ID)
+	})
+	h := app.Handler()
+
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Request.Header.SetMethod(fiber.MethodGet)
+	fctx.Request.SetRequestURI("/")
+	fctx.Request.Header.Set("X-Tenant-ID", "acme")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		h(fctx)
+	}
+}
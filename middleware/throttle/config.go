@@ -0,0 +1,87 @@
+package throttle
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// KeyGenerator returns the key whose concurrent requests are limited.
+	//
+	// Optional. Default: the method and path of the route the middleware is registered for
+	KeyGenerator func(c fiber.Ctx) string
+
+	// LimitReached is called if the request can't be executed, because Max requests
+	// with the same key are in progress and the queue is full or MaxWait passed.
+	//
+	// Optional. Default: func(c fiber.Ctx) error {
+	//   return c.SendStatus(fiber.StatusServiceUnavailable)
+	// }
+	LimitReached fiber.Handler
+
+	// Max is the maximum number of concurrent requests per key.
+	//
+	// Optional. Default: 10
+	Max int
+
+	// QueueSize is the maximum number of requests per key which wait for one of the
+	// Max requests to finish. They are executed in the order they arrived.
+	//
+	// Optional. Default: 0 (requests are rejected immediately)
+	QueueSize int
+
+	// MaxWait is the maximum time a request waits in the queue.
+	//
+	// Optional. Default: 0 (wait until the request context is done)
+	MaxWait time.Duration
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next: nil,
+	KeyGenerator: func(c fiber.Ctx) string {
+		return c.Route().Method + " " + c.Route().Path
+	},
+	LimitReached: func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusServiceUnavailable)
+	},
+	Max:       10,
+	QueueSize: 0,
+	MaxWait:   0,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.KeyGenerator == nil {
+		cfg.KeyGenerator = ConfigDefault.KeyGenerator
+	}
+	if cfg.LimitReached == nil {
+		cfg.LimitReached = ConfigDefault.LimitReached
+	}
+	if cfg.Max <= 0 {
+		cfg.Max = ConfigDefault.Max
+	}
+	if cfg.QueueSize < 0 {
+		cfg.QueueSize = ConfigDefault.QueueSize
+	}
+	if cfg.MaxWait < 0 {
+		cfg.MaxWait = ConfigDefault.MaxWait
+	}
+	return cfg
+}
@@ -0,0 +1,115 @@
+// Package throttle provides a middleware which limits the number of concurrent
+// requests per route or key, so a slow dependency of a single endpoint can't
+// occupy every worker of the server.
+package throttle
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+)
+
+// slots are the concurrent requests of a key
+type slots struct {
+	waiters list.List // chan struct{} of the waiting requests in arrival order
+	active  int
+}
+
+// throttler keeps the slots of all keys which have requests in progress
+type throttler struct {
+	keys map[string]*slots
+	cfg  *Config
+	mu   sync.Mutex
+}
+
+// acquire reports whether the request may be executed, it waits in the queue if it is not full
+func (t *throttler) acquire(ctx context.Context, key string) bool {
+	t.mu.Lock()
+	s, ok := t.keys[key]
+	if !ok {
+		s = &slots{}
+		t.keys[key] = s
+	}
+	if s.active < t.cfg.Max {
+		s.active++
+		t.mu.Unlock()
+		return true
+	}
+	if s.waiters.Len() >= t.cfg.QueueSize {
+		t.mu.Unlock()
+		return false
+	}
+	ready := make(chan struct{})
+	elem := s.waiters.PushBack(ready)
+	t.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if t.cfg.MaxWait > 0 {
+		timer := time.NewTimer(t.cfg.MaxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-ready:
+		return true
+	case <-timeout:
+	case <-ctx.Done():
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-ready:
+		// The slot was passed to the request in the meantime
+		return true
+	default:
+		s.waiters.Remove(elem)
+		return false
+	}
+}
+
+// release passes the slot to the first waiting request or frees it
+func (t *throttler) release(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.keys[key]
+	if front := s.waiters.Front(); front != nil {
+		s.waiters.Remove(front)
+		close(front.Value.(chan struct{})) //nolint:forcetypeassert // Only channels are added
+		return
+	}
+	s.active--
+	if s.active == 0 {
+		delete(t.keys, key)
+	}
+}
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	t := &throttler{keys: make(map[string]*slots), cfg: &cfg}
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		key := utils.CopyString(cfg.KeyGenerator(c))
+		if !t.acquire(c.Context(), key) {
+			return cfg.LimitReached(c)
+		}
+		defer t.release(key)
+
+		// Continue stack
+		return c.Next()
+	}
+}
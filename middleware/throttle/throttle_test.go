@@ -0,0 +1,225 @@
+package throttle
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// blockingApp returns an app whose handler blocks until release is closed
+func blockingApp(release <-chan struct{}, config Config) (*fiber.App, *atomic.Int32) {
+	var executions atomic.Int32
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		executions.Add(1)
+		<-release
+		return c.SendStatus(fiber.StatusOK)
+	}, New(config))
+	return app, &executions
+}
+
+// sendAsync sends a request in the background and returns a channel with its status
+func sendAsync(t *testing.T, app *fiber.App, target string) <-chan int {
+	t.Helper()
+	status := make(chan int, 1)
+	go func() {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, target, nil), fiber.TestConfig{Timeout: 0})
+		if assert.NoError(t, err) {
+			status <- resp.StatusCode
+		}
+	}()
+	return status
+}
+
+// go test -run Test_Throttle
+func Test_Throttle(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	app, executions := blockingApp(release, Config{Max: 2})
+
+	first := sendAsync(t, app, "/")
+	second := sendAsync(t, app, "/")
+	require.Eventually(t, func() bool {
+		return executions.Load() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// Both slots are in use and there is no queue
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+
+	close(release)
+	require.Equal(t, fiber.StatusOK, <-first)
+	require.Equal(t, fiber.StatusOK, <-second)
+
+	// The slots are free again
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(3), executions.Load())
+}
+
+// go test -run Test_Throttle_Queue
+func Test_Throttle_Queue(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	app, executions := blockingApp(release, Config{Max: 1, QueueSize: 1})
+
+	first := sendAsync(t, app, "/")
+	require.Eventually(t, func() bool {
+		return executions.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+	queued := sendAsync(t, app, "/")
+	time.Sleep(50 * time.Millisecond)
+
+	// The queue is full
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, int32(1), executions.Load())
+
+	close(release)
+	require.Equal(t, fiber.StatusOK, <-first)
+	require.Equal(t, fiber.StatusOK, <-queued)
+	require.Equal(t, int32(2), executions.Load())
+}
+
+// go test -run Test_Throttle_MaxWait
+func Test_Throttle_MaxWait(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	app, executions := blockingApp(release, Config{
+		Max:       1,
+		QueueSize: 10,
+		MaxWait:   50 * time.Millisecond,
+		LimitReached: func(c fiber.Ctx) error {
+			return c.Status(fiber.StatusServiceUnavailable).SendString("busy")
+		},
+	})
+
+	first := sendAsync(t, app, "/")
+	require.Eventually(t, func() bool {
+		return executions.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	start := time.Now()
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	close(release)
+	require.Equal(t, fiber.StatusOK, <-first)
+	require.Equal(t, int32(1), executions.Load())
+}
+
+// go test -run Test_Throttle_FIFO
+func Test_Throttle_FIFO(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var order []string
+
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		mu.Lock()
+		order = append(order, c.Query("id"))
+		mu.Unlock()
+		<-release
+		return c.SendStatus(fiber.StatusOK)
+	}, New(Config{Max: 1, QueueSize: 5}))
+
+	var statuses []<-chan int
+	for i := 0; i < 5; i++ {
+		statuses = append(statuses, sendAsync(t, app, "/?id="+strconv.Itoa(i)))
+		// Let the request arrive before the next one
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	close(release)
+	for _, status := range statuses {
+		require.Equal(t, fiber.StatusOK, <-status)
+	}
+	require.Equal(t, []string{"0", "1", "2", "3", "4"}, order)
+}
+
+// go test -run Test_Throttle_Keys
+func Test_Throttle_Keys(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	var executions atomic.Int32
+
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		executions.Add(1)
+		<-release
+		return c.SendStatus(fiber.StatusOK)
+	}, New(Config{
+		Max: 1,
+		KeyGenerator: func(c fiber.Ctx) string {
+			return c.Query("tenant")
+		},
+	}))
+
+	a := sendAsync(t, app, "/?tenant=a")
+	b := sendAsync(t, app, "/?tenant=b")
+	require.Eventually(t, func() bool {
+		return executions.Load() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/?tenant=a", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+
+	close(release)
+	require.Equal(t, fiber.StatusOK, <-a)
+	require.Equal(t, fiber.StatusOK, <-b)
+}
+
+// go test -run Test_Throttle_Next
+func Test_Throttle_Next(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	app, executions := blockingApp(release, Config{
+		Max: 1,
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	})
+
+	first := sendAsync(t, app, "/")
+	second := sendAsync(t, app, "/")
+	require.Eventually(t, func() bool {
+		return executions.Load() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	close(release)
+	require.Equal(t, fiber.StatusOK, <-first)
+	require.Equal(t, fiber.StatusOK, <-second)
+}
+
+// go test -v -run=^$ -bench=Benchmark_Throttle -benchmem -count=4
+func Benchmark_Throttle(b *testing.B) {
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	}, New())
+
+	h := app.Handler()
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI("/")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		h(ctx)
+	}
+}
@@ -3,28 +3,71 @@ package timeout
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
 )
 
-// New implementation of timeout middleware. Set custom errors(context.DeadlineExceeded vs) for get fiber.ErrRequestTimeout response.
+var headerPool = sync.Pool{
+	New: func() any {
+		return new(fasthttp.ResponseHeader)
+	},
+}
+
+// New implementation of timeout middleware. The handler runs with a deadline on c.Context(),
+// which cancels all work using the context. If the deadline is exceeded, everything the handler
+// wrote to the response is discarded and fiber.ErrGatewayTimeout is passed to the ErrorHandler.
+// Set custom errors(context.DeadlineExceeded vs) which are returned by the handler on timeout.
+//
+// The handler runs synchronously, so the 504 response is only sent once it returned, not at the
+// deadline. A handler which ignores c.Context() keeps the client waiting until it returns.
 func New(h fiber.Handler, t time.Duration, tErrs ...error) fiber.Handler {
 	return func(ctx fiber.Ctx) error {
-		timeoutContext, cancel := context.WithTimeout(ctx.Context(), t)
+		parent := ctx.Context()
+		timeoutContext, cancel := context.WithTimeout(parent, t)
 		defer cancel()
 		ctx.SetContext(timeoutContext)
-		if err := h(ctx); err != nil {
-			if errors.Is(err, context.DeadlineExceeded) {
-				return fiber.ErrRequestTimeout
-			}
-			for i := range tErrs {
-				if errors.Is(err, tErrs[i]) {
-					return fiber.ErrRequestTimeout
-				}
-			}
-			return err
+
+		// Keep the headers set before the handler, so they can be restored on timeout
+		header, ok := headerPool.Get().(*fasthttp.ResponseHeader)
+		if !ok {
+			panic(errors.New("failed to type-assert to *fasthttp.ResponseHeader"))
+		}
+		defer func() {
+			header.Reset()
+			headerPool.Put(header)
+		}()
+		ctx.Response().Header.CopyTo(header)
+
+		err := h(ctx)
+
+		// The deadline must not affect the ErrorHandler and the middleware before the handler
+		ctx.SetContext(parent)
+
+		if errors.Is(timeoutContext.Err(), context.DeadlineExceeded) || isTimeoutError(err, tErrs) {
+			// Discard the late response of the handler
+			header.CopyTo(&ctx.Response().Header)
+			ctx.Response().ResetBody()
+			return fiber.ErrGatewayTimeout
+		}
+		return err
+	}
+}
+
+// isTimeoutError reports whether the error is a timeout error
+func isTimeoutError(err error, tErrs []error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	for i := range tErrs {
+		if errors.Is(err, tErrs[i]) {
+			return true
 		}
-		return nil
 	}
+	return false
 }
@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
 	"github.com/stretchr/testify/require"
 )
 
@@ -29,7 +31,7 @@ func Test_WithContextTimeout(t *testing.T) {
 	testTimeout := func(timeoutStr string) {
 		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/test/"+timeoutStr, nil))
 		require.NoError(t, err, "app.Test(req)")
-		require.Equal(t, fiber.StatusRequestTimeout, resp.StatusCode, "Status code")
+		require.Equal(t, fiber.StatusGatewayTimeout, resp.StatusCode, "Status code")
 	}
 	testSucces := func(timeoutStr string) {
 		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/test/"+timeoutStr, nil))
@@ -61,7 +63,7 @@ func Test_WithContextTimeoutWithCustomError(t *testing.T) {
 	testTimeout := func(timeoutStr string) {
 		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/test/"+timeoutStr, nil))
 		require.NoError(t, err, "app.Test(req)")
-		require.Equal(t, fiber.StatusRequestTimeout, resp.StatusCode, "Status code")
+		require.Equal(t, fiber.StatusGatewayTimeout, resp.StatusCode, "Status code")
 	}
 	testSucces := func(timeoutStr string) {
 		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/test/"+timeoutStr, nil))
@@ -74,6 +76,36 @@ func Test_WithContextTimeoutWithCustomError(t *testing.T) {
 	testSucces("30")
 }
 
+// go test -run Test_WithContextTimeoutDiscardsLateResponse
+func Test_WithContextTimeoutDiscardsLateResponse(t *testing.T) {
+	t.Parallel()
+	// fiber instance
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		c.Set("X-Before", "kept")
+		if err := c.Next(); err != nil {
+			return err
+		}
+		// The deadline of the handler doesn't apply to the middleware before it
+		return c.Context().Err()
+	})
+	app.Get("/", New(func(c fiber.Ctx) error {
+		// The handler ignores the context and writes after the deadline
+		time.Sleep(150 * time.Millisecond)
+		c.Set("X-Late", "discarded")
+		return c.Status(fiber.StatusCreated).SendString("late")
+	}, 50*time.Millisecond))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err, "app.Test(req)")
+	require.Equal(t, fiber.StatusGatewayTimeout, resp.StatusCode, "Status code")
+	require.Equal(t, "kept", resp.Header.Get("X-Before"))
+	require.Empty(t, resp.Header.Get("X-Late"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, utils.StatusMessage(fiber.StatusGatewayTimeout), string(body))
+}
+
 func sleepWithContext(ctx context.Context, d time.Duration, te error) error {
 	timer := time.NewTimer(d)
 	select {
@@ -0,0 +1,108 @@
+package websocket
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Origins are the allowed values of the Origin header of the handshake,
+	// "*" allows all origins.
+	//
+	// Optional. Default: []string{"*"}
+	Origins []string
+
+	// Subprotocols are the subprotocols supported by the server in the order of
+	// preference. The first subprotocol which is requested by the client is selected.
+	//
+	// Optional. Default: nil
+	Subprotocols []string
+
+	// MaxMessageSize is the maximum size of a message read from the client in bytes,
+	// larger messages close the connection with CloseMessageTooBig.
+	//
+	// Optional. Default: 1MB
+	MaxMessageSize int64
+
+	// ReadBufferSize is the size of the read buffer of a connection in bytes.
+	//
+	// Optional. Default: 4096
+	ReadBufferSize int
+
+	// WriteBufferSize is the size of the write buffer of a connection in bytes.
+	//
+	// Optional. Default: 4096
+	WriteBufferSize int
+
+	// PingInterval is the interval of the pings sent to the client.
+	// Set to a negative value to disable the pings.
+	//
+	// Optional. Default: 30 * time.Second
+	PingInterval time.Duration
+
+	// PongTimeout is the time to wait for a frame from the client, e.g. the pong of
+	// a ping, before the connection is closed. Set to a negative value to wait forever.
+	//
+	// Optional. Default: 60 * time.Second
+	PongTimeout time.Duration
+
+	// WriteTimeout is the time to wait for a write to the client to finish.
+	// Set to a negative value to wait forever.
+	//
+	// Optional. Default: 10 * time.Second
+	WriteTimeout time.Duration
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:            nil,
+	Origins:         []string{"*"},
+	MaxMessageSize:  1 << 20,
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	PingInterval:    30 * time.Second,
+	PongTimeout:     60 * time.Second,
+	WriteTimeout:    10 * time.Second,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if len(cfg.Origins) == 0 {
+		cfg.Origins = ConfigDefault.Origins
+	}
+	if cfg.MaxMessageSize <= 0 {
+		cfg.MaxMessageSize = ConfigDefault.MaxMessageSize
+	}
+	if cfg.ReadBufferSize <= 0 {
+		cfg.ReadBufferSize = ConfigDefault.ReadBufferSize
+	}
+	if cfg.WriteBufferSize <= 0 {
+		cfg.WriteBufferSize = ConfigDefault.WriteBufferSize
+	}
+	if cfg.PingInterval == 0 {
+		cfg.PingInterval = ConfigDefault.PingInterval
+	}
+	if cfg.PongTimeout == 0 {
+		cfg.PongTimeout = ConfigDefault.PongTimeout
+	}
+	if cfg.WriteTimeout == 0 {
+		cfg.WriteTimeout = ConfigDefault.WriteTimeout
+	}
+	return cfg
+}
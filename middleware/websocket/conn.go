@@ -0,0 +1,421 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/utils/v2"
+)
+
+// Message types, the values are the opcodes of RFC 6455
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+	CloseMessage  = 8
+	PingMessage   = 9
+	PongMessage   = 10
+)
+
+// Close codes of RFC 6455
+const (
+	CloseNormalClosure           = 1000
+	CloseGoingAway               = 1001
+	CloseProtocolError           = 1002
+	CloseUnsupportedData         = 1003
+	CloseNoStatusReceived        = 1005
+	CloseAbnormalClosure         = 1006
+	CloseInvalidFramePayloadData = 1007
+	ClosePolicyViolation         = 1008
+	CloseMessageTooBig           = 1009
+	CloseInternalServerErr       = 1011
+)
+
+const (
+	continuationFrame = 0
+	finalBit          = 0x80
+	rsvBits           = 0x70
+	maskBit           = 0x80
+	// maxControlPayload is the maximum payload length of control frames
+	maxControlPayload = 125
+)
+
+// Errors of the connection
+var (
+	ErrCloseSent          = errors.New("websocket: close sent")
+	ErrInvalidMessageType = errors.New("websocket: invalid message type")
+	ErrControlTooLarge    = errors.New("websocket: control frame payload is too large")
+)
+
+// CloseError is returned by ReadMessage when the connection is closed. Code is the
+// close code of the close frame of the client or of the error which closed the connection.
+type CloseError struct {
+	Text string
+	Code int
+}
+
+func (e *CloseError) Error() string {
+	if e.Text == "" {
+		return "websocket: close " + strconv.Itoa(e.Code)
+	}
+	return "websocket: close " + strconv.Itoa(e.Code) + ": " + e.Text
+}
+
+// IsCloseError reports whether err is a *CloseError with one of the codes
+func IsCloseError(err error, codes ...int) bool {
+	var closeErr *CloseError
+	if !errors.As(err, &closeErr) {
+		return false
+	}
+	for _, code := range codes {
+		if closeErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Conn is a WebSocket connection. The values of the request, like its route parameters
+// and locals, are copied before the upgrade, as the fiber.Ctx can't be used anymore.
+// Only one goroutine may read from the connection, the write methods are safe
+// for concurrent use.
+type Conn struct {
+	conn        net.Conn
+	br          *bufio.Reader
+	bw          *bufio.Writer
+	readErr     error
+	locals      map[any]any
+	params      map[string]string
+	queries     map[string]string
+	headers     map[string]string
+	cookies     map[string]string
+	jsonEncoder utils.JSONMarshal
+	jsonDecoder utils.JSONUnmarshal
+	subprotocol string
+
+	maxMessageSize int64
+	pongTimeout    time.Duration
+	writeTimeout   time.Duration
+
+	wmu       sync.Mutex
+	closeSent bool
+	isServer  bool
+}
+
+// init sets the connection of c after the upgrade
+func (c *Conn) init(conn net.Conn, cfg Config, isServer bool) {
+	c.conn = conn
+	c.br = bufio.NewReaderSize(conn, cfg.ReadBufferSize)
+	c.bw = bufio.NewWriterSize(conn, cfg.WriteBufferSize)
+	c.maxMessageSize = cfg.MaxMessageSize
+	c.pongTimeout = cfg.PongTimeout
+	c.writeTimeout = cfg.WriteTimeout
+	c.isServer = isServer
+}
+
+// Subprotocol returns the negotiated subprotocol, or an empty string if none was selected
+func (c *Conn) Subprotocol() string {
+	return c.subprotocol
+}
+
+// Params returns the route parameter of the key
+func (c *Conn) Params(key string, defaultValue ...string) string {
+	return valueOrDefault(c.params[key], defaultValue)
+}
+
+// Query returns the query string parameter of the key
+func (c *Conn) Query(key string, defaultValue ...string) string {
+	return valueOrDefault(c.queries[key], defaultValue)
+}
+
+// Headers returns the request header of the key, the key is case-insensitive
+func (c *Conn) Headers(key string, defaultValue ...string) string {
+	return valueOrDefault(c.headers[utils.ToLower(key)], defaultValue)
+}
+
+// Cookies returns the request cookie of the key
+func (c *Conn) Cookies(key string, defaultValue ...string) string {
+	return valueOrDefault(c.cookies[key], defaultValue)
+}
+
+// Locals returns the local of the key, which was set before the upgrade
+func (c *Conn) Locals(key any) any {
+	return c.locals[key]
+}
+
+// LocalAddr returns the local network address
+func (c *Conn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+// RemoteAddr returns the remote network address
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// ReadMessage returns the next text or binary message. Pings are answered with pongs.
+// If the client closes the connection, a *CloseError with its close code is returned.
+func (c *Conn) ReadMessage() (messageType int, data []byte, err error) { //nolint:nonamedreturns // The names document the results
+	if c.readErr != nil {
+		return 0, nil, c.readErr
+	}
+
+	for {
+		if c.pongTimeout > 0 {
+			_ = c.conn.SetReadDeadline(time.Now().Add(c.pongTimeout)) //nolint:errcheck // A failed read returns the error
+		}
+		fin, opcode, payload, err := c.readFrame(c.maxMessageSize - int64(len(data)))
+		if err != nil {
+			return 0, nil, c.fail(err)
+		}
+
+		switch opcode {
+		case PingMessage:
+			if err := c.writeFrame(PongMessage, payload); err != nil && !errors.Is(err, ErrCloseSent) {
+				return 0, nil, c.fail(err)
+			}
+			continue
+		case PongMessage:
+			continue
+		case CloseMessage:
+			return 0, nil, c.closeReceived(payload)
+		case continuationFrame:
+			if messageType == 0 {
+				return 0, nil, c.fail(&CloseError{Code: CloseProtocolError, Text: "unexpected continuation frame"})
+			}
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				return 0, nil, c.fail(&CloseError{Code: CloseProtocolError, Text: "expected continuation frame"})
+			}
+			messageType = opcode
+		default:
+			return 0, nil, c.fail(&CloseError{Code: CloseProtocolError, Text: "unknown opcode " + strconv.Itoa(opcode)})
+		}
+
+		data = append(data, payload...)
+		if !fin {
+			continue
+		}
+		if messageType == TextMessage && !utf8.Valid(data) {
+			return 0, nil, c.fail(&CloseError{Code: CloseInvalidFramePayloadData, Text: "invalid UTF-8 in text message"})
+		}
+		return messageType, data, nil
+	}
+}
+
+// ReadJSON reads the next message and decodes it into v with the JSON decoder of the app
+func (c *Conn) ReadJSON(v any) error {
+	_, data, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return c.jsonDecoder(data, v)
+}
+
+// WriteMessage writes a text, binary, ping or pong message
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	switch messageType {
+	case TextMessage, BinaryMessage:
+	case PingMessage, PongMessage:
+		if len(data) > maxControlPayload {
+			return ErrControlTooLarge
+		}
+	default:
+		return ErrInvalidMessageType
+	}
+	return c.writeFrame(messageType, data)
+}
+
+// WriteJSON writes v as text message, encoded with the JSON encoder of the app
+func (c *Conn) WriteJSON(v any) error {
+	data, err := c.jsonEncoder(v)
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(TextMessage, data)
+}
+
+// WriteClose writes a close frame with the code and the text, no messages can be written afterwards
+func (c *Conn) WriteClose(code int, text string) error {
+	if len(text) > maxControlPayload-2 {
+		text = text[:maxControlPayload-2]
+	}
+	payload := make([]byte, 2, 2+len(text))
+	binary.BigEndian.PutUint16(payload, uint16(code)) //nolint:gosec // Close codes have 4 digits
+	return c.writeFrame(CloseMessage, append(payload, text...))
+}
+
+// Close closes the connection with CloseNormalClosure
+func (c *Conn) Close() error {
+	_ = c.WriteClose(CloseNormalClosure, "") //nolint:errcheck // The connection is closed anyway
+	return c.conn.Close()                    //nolint:wrapcheck // This must not be wrapped
+}
+
+// readFrame reads the next frame, whose payload must not be larger than limit
+func (c *Conn) readFrame(limit int64) (fin bool, opcode int, payload []byte, err error) { //nolint:nonamedreturns // The names document the results
+	var header [8]byte
+	if _, err = io.ReadFull(c.br, header[:2]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&finalBit != 0
+	opcode = int(header[0] & 0x0f)
+	if header[0]&rsvBits != 0 {
+		return false, 0, nil, &CloseError{Code: CloseProtocolError, Text: "unexpected reserved bits"}
+	}
+	if masked := header[1]&maskBit != 0; masked != c.isServer {
+		return false, 0, nil, &CloseError{Code: CloseProtocolError, Text: "invalid masking of frame"}
+	}
+
+	length := int64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		if _, err = io.ReadFull(c.br, header[:2]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(header[:2]))
+	case 127:
+		if _, err = io.ReadFull(c.br, header[:8]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(header[:8])) //nolint:gosec // Negative lengths are rejected below
+	}
+
+	if opcode >= CloseMessage {
+		if !fin || length > maxControlPayload {
+			return false, 0, nil, &CloseError{Code: CloseProtocolError, Text: "invalid control frame"}
+		}
+	} else if length < 0 || length > limit {
+		return false, 0, nil, &CloseError{Code: CloseMessageTooBig, Text: "message is too large"}
+	}
+
+	var mask [4]byte
+	if c.isServer {
+		if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if c.isServer {
+		maskBytes(mask, payload)
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes a frame with the payload and flushes it
+func (c *Conn) writeFrame(opcode int, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closeSent {
+		return ErrCloseSent
+	}
+	if opcode == CloseMessage {
+		c.closeSent = true
+	}
+	if c.writeTimeout > 0 {
+		_ = c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout)) //nolint:errcheck // A failed write returns the error
+	}
+
+	header := make([]byte, 2, 14)
+	header[0] = finalBit | byte(opcode)
+	switch length := len(payload); {
+	case length <= maxControlPayload:
+		header[1] = byte(length)
+	case length <= 0xffff:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+	if !c.isServer {
+		// Frames of clients are masked
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err //nolint:wrapcheck // This must not be wrapped
+		}
+		header[1] |= maskBit
+		header = append(header, mask[:]...)
+		payload = append([]byte(nil), payload...)
+		maskBytes(mask, payload)
+	}
+
+	_, _ = c.bw.Write(header)  //nolint:errcheck // Write errors are reported by Flush
+	_, _ = c.bw.Write(payload) //nolint:errcheck // Write errors are reported by Flush
+	return c.bw.Flush()        //nolint:wrapcheck // This must not be wrapped
+}
+
+// closeReceived answers the close frame of the client and closes the connection
+func (c *Conn) closeReceived(payload []byte) error {
+	closeErr := &CloseError{Code: CloseNoStatusReceived}
+	switch {
+	case len(payload) == 1:
+		closeErr = &CloseError{Code: CloseProtocolError, Text: "invalid close frame"}
+	case len(payload) >= 2:
+		closeErr.Code = int(binary.BigEndian.Uint16(payload))
+		closeErr.Text = string(payload[2:])
+	}
+
+	code := closeErr.Code
+	if code == CloseNoStatusReceived {
+		code = CloseNormalClosure
+	}
+	_ = c.WriteClose(code, "") //nolint:errcheck // The connection is closed anyway
+	_ = c.conn.Close()         //nolint:errcheck // It is fine to ignore the error here
+	c.readErr = closeErr
+	return closeErr
+}
+
+// fail closes the connection after a read error. Protocol violations are sent to the
+// client with their close code.
+func (c *Conn) fail(err error) error {
+	var closeErr *CloseError
+	switch {
+	case errors.As(err, &closeErr):
+		_ = c.WriteClose(closeErr.Code, closeErr.Text) //nolint:errcheck // The connection is closed anyway
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		err = &CloseError{Code: CloseAbnormalClosure, Text: "unexpected EOF"}
+	}
+	_ = c.conn.Close() //nolint:errcheck // It is fine to ignore the error here
+	c.readErr = err
+	return err
+}
+
+// ping sends pings to the client until done is closed
+func (c *Conn) ping(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := c.writeFrame(PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// maskBytes masks or unmasks the payload with the key
+func maskBytes(key [4]byte, payload []byte) {
+	for i := range payload {
+		payload[i] ^= key[i&3]
+	}
+}
+
+func valueOrDefault(value string, defaultValue []string) string {
+	if value == "" && len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return value
+}
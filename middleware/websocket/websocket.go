@@ -0,0 +1,197 @@
+// Package websocket upgrades requests to WebSocket connections as specified in RFC 6455,
+// with subprotocol negotiation, pings to detect dead connections and access to the
+// route parameters, query parameters, headers, cookies and locals of the request.
+package websocket
+
+import (
+	"crypto/sha1" //nolint:gosec // The accept key of RFC 6455 is a SHA-1 hash
+	"encoding/base64"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/utils/v2"
+)
+
+// acceptGUID is appended to the key of the client to compute the accept key
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// New creates a new middleware handler, which upgrades the requests to WebSocket
+// connections served by handler. The connection is closed when handler returns.
+// Requests which aren't WebSocket handshakes are answered with 426 Upgrade Required.
+//
+//	app.Get("/ws/:room", websocket.New(func(c *websocket.Conn) {
+//		for {
+//			mt, msg, err := c.ReadMessage()
+//			if err != nil {
+//				return
+//			}
+//			_ = c.WriteMessage(mt, msg)
+//		}
+//	}))
+func New(handler func(c *Conn), config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+	allOrigins := slices.Contains(cfg.Origins, "*")
+
+	// Open connections, which are closed with CloseGoingAway on shutdown
+	var (
+		mu       sync.Mutex
+		conns    = make(map[*Conn]struct{})
+		register sync.Once
+	)
+	closeAll := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		for conn := range conns {
+			_ = conn.WriteClose(CloseGoingAway, "server shutdown") //nolint:errcheck // The connection is closed anyway
+			_ = conn.conn.Close()                                  //nolint:errcheck // It is fine to ignore the error here
+		}
+		return nil
+	}
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		if !IsWebSocketUpgrade(c) {
+			return fiber.ErrUpgradeRequired
+		}
+		if c.Get(fiber.HeaderSecWebSocketVersion) != "13" {
+			c.Set(fiber.HeaderSecWebSocketVersion, "13")
+			return fiber.ErrUpgradeRequired
+		}
+		key := c.Get(fiber.HeaderSecWebSocketKey)
+		if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+			return fiber.NewError(fiber.StatusBadRequest, "websocket: invalid Sec-WebSocket-Key")
+		}
+		if !allOrigins && !slices.Contains(cfg.Origins, c.Get(fiber.HeaderOrigin)) {
+			return fiber.ErrForbidden
+		}
+
+		conn := newConn(c)
+		conn.subprotocol = selectSubprotocol(cfg.Subprotocols, c.Get(fiber.HeaderSecWebSocketProtocol))
+		register.Do(func() {
+			c.App().Hooks().OnShutdown(closeAll)
+		})
+
+		c.Set(fiber.HeaderUpgrade, "websocket")
+		c.Set(fiber.HeaderConnection, "Upgrade")
+		c.Set(fiber.HeaderSecWebSocketAccept, acceptKey(key))
+		if conn.subprotocol != "" {
+			c.Set(fiber.HeaderSecWebSocketProtocol, conn.subprotocol)
+		}
+		c.Status(fiber.StatusSwitchingProtocols)
+
+		c.RequestCtx().Hijack(func(netConn net.Conn) {
+			conn.init(netConn, cfg, true)
+
+			mu.Lock()
+			conns[conn] = struct{}{}
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				delete(conns, conn)
+				mu.Unlock()
+			}()
+
+			serve(conn, handler, cfg)
+		})
+		return nil
+	}
+}
+
+// IsWebSocketUpgrade reports whether the request is a WebSocket handshake
+func IsWebSocketUpgrade(c fiber.Ctx) bool {
+	return c.Method() == fiber.MethodGet &&
+		hasToken(c.Get(fiber.HeaderConnection), "upgrade") &&
+		hasToken(c.Get(fiber.HeaderUpgrade), "websocket")
+}
+
+// serve calls the handler with the connection and closes it when the handler returns
+func serve(conn *Conn, handler func(c *Conn), cfg Config) {
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		if r := recover(); r != nil {
+			log.Errorf("websocket: panic in handler: %v", r)
+			_ = conn.WriteClose(CloseInternalServerErr, "") //nolint:errcheck // The connection is closed anyway
+		}
+		_ = conn.Close() //nolint:errcheck // It is fine to ignore the error here
+	}()
+
+	if cfg.PingInterval > 0 {
+		go conn.ping(cfg.PingInterval, done)
+	}
+	handler(conn)
+}
+
+// newConn copies the values of the request, which are used by the connection after the upgrade
+func newConn(c fiber.Ctx) *Conn {
+	conn := &Conn{
+		locals:      make(map[any]any),
+		params:      make(map[string]string),
+		queries:     make(map[string]string),
+		headers:     make(map[string]string),
+		cookies:     make(map[string]string),
+		jsonEncoder: c.App().Config().JSONEncoder,
+		jsonDecoder: c.App().Config().JSONDecoder,
+	}
+	c.RequestCtx().VisitUserValuesAll(func(key, value any) {
+		conn.locals[key] = value
+	})
+	for _, name := range c.Route().Params {
+		conn.params[name] = utils.CopyString(c.Params(name))
+	}
+	c.RequestCtx().QueryArgs().VisitAll(func(key, value []byte) {
+		conn.queries[string(key)] = string(value)
+	})
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		name := utils.ToLower(string(key))
+		if v, ok := conn.headers[name]; ok {
+			conn.headers[name] = v + ", " + string(value)
+		} else {
+			conn.headers[name] = string(value)
+		}
+	})
+	c.Request().Header.VisitAllCookie(func(key, value []byte) {
+		conn.cookies[string(key)] = string(value)
+	})
+	return conn
+}
+
+// selectSubprotocol returns the first supported subprotocol which is requested by the client
+func selectSubprotocol(supported []string, requested string) string {
+	for _, protocol := range supported {
+		for _, r := range strings.Split(requested, ",") {
+			if utils.Trim(r, ' ') == protocol {
+				return protocol
+			}
+		}
+	}
+	return ""
+}
+
+// acceptKey returns the Sec-WebSocket-Accept header of the key
+func acceptKey(key string) string {
+	h := sha1.New() //nolint:gosec // The accept key of RFC 6455 is a SHA-1 hash
+	h.Write([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// hasToken reports whether the comma separated header contains the token, the
+// comparison is case-insensitive
+func hasToken(header, token string) bool {
+	for _, t := range strings.Split(header, ",") {
+		if utils.EqualFold(utils.Trim(t, ' '), token) {
+			return true
+		}
+	}
+	return false
+}
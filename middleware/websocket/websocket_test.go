@@ -0,0 +1,328 @@
+package websocket
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "dGhlIHNhbXBsZSBub25jZQ=="
+
+// listen starts the app and returns its address
+func listen(t testing.TB, app *fiber.App) string {
+	t.Helper()
+	addr := make(chan string, 1)
+	go func() {
+		assert.NoError(t, app.Listen("127.0.0.1:0", fiber.ListenConfig{
+			DisableStartupMessage: true,
+			ListenerAddrFunc: func(a net.Addr) {
+				addr <- a.String()
+			},
+		}))
+	}()
+	t.Cleanup(func() {
+		assert.NoError(t, app.Shutdown())
+	})
+	return <-addr
+}
+
+// dial opens a client connection to the path, headers are added to the handshake
+func dial(t testing.TB, addr, path string, headers ...string) (*Conn, *http.Response) {
+	t.Helper()
+	netConn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = netConn.Close() //nolint:errcheck // It is fine to ignore the error here
+	})
+
+	req := "GET " + path + " HTTP/1.1\r\nHost: " + addr + "\r\n" +
+		"Connection: Upgrade\r\nUpgrade: websocket\r\n" +
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: " + testKey + "\r\n"
+	for i := 0; i+1 < len(headers); i += 2 {
+		req += headers[i] + ": " + headers[i+1] + "\r\n"
+	}
+	_, err = netConn.Write([]byte(req + "\r\n"))
+	require.NoError(t, err)
+
+	br := bufio.NewReader(netConn)
+	resp, err := http.ReadResponse(br, nil)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	conn := &Conn{jsonEncoder: fiber.New().Config().JSONEncoder, jsonDecoder: fiber.New().Config().JSONDecoder}
+	conn.init(netConn, ConfigDefault, false)
+	// The reader may already contain the first frames of the server
+	conn.br = br
+	return conn, resp
+}
+
+// go test -run Test_WebSocket_Echo
+func Test_WebSocket_Echo(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("user", "john")
+		return c.Next()
+	})
+	app.Get("/ws/:room", New(func(c *Conn) {
+		require.NoError(t, c.WriteJSON(fiber.Map{
+			"room":     c.Params("room"),
+			"missing":  c.Params("missing", "default"),
+			"token":    c.Query("token"),
+			"header":   c.Headers("X-Test"),
+			"cookie":   c.Cookies("session"),
+			"user":     c.Locals("user"),
+			"protocol": c.Subprotocol(),
+		}))
+		for {
+			mt, msg, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := c.WriteMessage(mt, msg); err != nil {
+				return
+			}
+		}
+	}, Config{Subprotocols: []string{"chat.v2", "chat.v1"}}))
+	addr := listen(t, app)
+
+	conn, resp := dial(t, addr, "/ws/general?token=secret",
+		"X-Test", "value", "Cookie", "session=abc", "Sec-WebSocket-Protocol", "chat.v1, chat.v2")
+	require.Equal(t, fiber.StatusSwitchingProtocols, resp.StatusCode)
+	require.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get(fiber.HeaderSecWebSocketAccept))
+	require.Equal(t, "chat.v2", resp.Header.Get(fiber.HeaderSecWebSocketProtocol))
+
+	var values map[string]string
+	require.NoError(t, conn.ReadJSON(&values))
+	require.Equal(t, map[string]string{
+		"room":     "general",
+		"missing":  "default",
+		"token":    "secret",
+		"header":   "value",
+		"cookie":   "abc",
+		"user":     "john",
+		"protocol": "chat.v2",
+	}, values)
+
+	require.NoError(t, conn.WriteMessage(TextMessage, []byte("hello")))
+	mt, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, TextMessage, mt)
+	require.Equal(t, "hello", string(msg))
+
+	large := []byte(strings.Repeat("a", 70000))
+	require.NoError(t, conn.WriteMessage(BinaryMessage, large))
+	mt, msg, err = conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, BinaryMessage, mt)
+	require.Equal(t, large, msg)
+
+	// Fragmented messages are joined
+	conn.bw.Write([]byte{TextMessage, maskBit | 3, 0, 0, 0, 0, 'f', 'o', 'o'})                  //nolint:errcheck // Write errors are reported by Flush
+	conn.bw.Write([]byte{finalBit | continuationFrame, maskBit | 3, 0, 0, 0, 0, 'b', 'a', 'r'}) //nolint:errcheck // Write errors are reported by Flush
+	require.NoError(t, conn.bw.Flush())
+	_, msg, err = conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, "foobar", string(msg))
+
+	require.NoError(t, conn.WriteClose(CloseGoingAway, "bye"))
+	_, _, err = conn.ReadMessage()
+	require.True(t, IsCloseError(err, CloseGoingAway))
+}
+
+// go test -run Test_WebSocket_Handshake
+func Test_WebSocket_Handshake(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Get("/", New(func(_ *Conn) {}, Config{Origins: []string{"https://example.com"}}))
+
+	newRequest := func(headers ...string) *http.Request {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(fiber.HeaderConnection, "keep-alive, Upgrade")
+		req.Header.Set(fiber.HeaderUpgrade, "WebSocket")
+		req.Header.Set(fiber.HeaderSecWebSocketVersion, "13")
+		req.Header.Set(fiber.HeaderSecWebSocketKey, testKey)
+		req.Header.Set(fiber.HeaderOrigin, "https://example.com")
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		return req
+	}
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusUpgradeRequired, resp.StatusCode)
+
+	resp, err = app.Test(newRequest(fiber.HeaderSecWebSocketVersion, "8"))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusUpgradeRequired, resp.StatusCode)
+	require.Equal(t, "13", resp.Header.Get(fiber.HeaderSecWebSocketVersion))
+
+	resp, err = app.Test(newRequest(fiber.HeaderSecWebSocketKey, "short"))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	resp, err = app.Test(newRequest(fiber.HeaderOrigin, "https://evil.com"))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+// go test -run Test_WebSocket_Next
+func Test_WebSocket_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Get("/", New(func(_ *Conn) {}, Config{
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	}), func(c fiber.Ctx) error {
+		return c.SendString("skipped")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+// go test -run Test_WebSocket_Ping
+func Test_WebSocket_Ping(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Get("/", New(func(c *Conn) {
+		_, _, _ = c.ReadMessage() //nolint:errcheck // The client closes the connection
+	}, Config{PingInterval: 10 * time.Millisecond}))
+	addr := listen(t, app)
+	conn, _ := dial(t, addr, "/")
+
+	// The server sends pings
+	_, opcode, _, err := conn.readFrame(maxControlPayload)
+	require.NoError(t, err)
+	require.Equal(t, PingMessage, opcode)
+
+	// The pings of the client are answered with pongs
+	require.NoError(t, conn.WriteMessage(PingMessage, []byte("ping")))
+	for {
+		_, opcode, payload, err := conn.readFrame(maxControlPayload)
+		require.NoError(t, err)
+		if opcode == PongMessage {
+			require.Equal(t, "ping", string(payload))
+			break
+		}
+	}
+	require.ErrorIs(t, conn.WriteMessage(PingMessage, make([]byte, 126)), ErrControlTooLarge)
+	require.ErrorIs(t, conn.WriteMessage(CloseMessage, nil), ErrInvalidMessageType)
+}
+
+// go test -run Test_WebSocket_Close
+func Test_WebSocket_Close(t *testing.T) {
+	t.Parallel()
+	closed := make(chan error, 1)
+	app := fiber.New()
+	app.Get("/", New(func(c *Conn) {
+		_, _, err := c.ReadMessage()
+		closed <- err
+	}, Config{MaxMessageSize: 4}))
+	app.Get("/panic", New(func(_ *Conn) {
+		panic("boom")
+	}))
+	addr := listen(t, app)
+
+	// Messages which are too large close the connection
+	conn, _ := dial(t, addr, "/")
+	require.NoError(t, conn.WriteMessage(TextMessage, []byte("too large")))
+	_, _, err := conn.ReadMessage()
+	require.True(t, IsCloseError(err, CloseMessageTooBig))
+	require.True(t, IsCloseError(<-closed, CloseMessageTooBig))
+
+	// Invalid UTF-8 closes the connection
+	conn, _ = dial(t, addr, "/")
+	require.NoError(t, conn.WriteMessage(TextMessage, []byte{0xff}))
+	_, _, err = conn.ReadMessage()
+	require.True(t, IsCloseError(err, CloseInvalidFramePayloadData))
+	<-closed
+
+	// The close code of the client is returned by ReadMessage
+	conn, _ = dial(t, addr, "/")
+	require.NoError(t, conn.WriteClose(CloseNormalClosure, "done"))
+	err = <-closed
+	require.True(t, IsCloseError(err, CloseNormalClosure))
+	require.EqualError(t, err, "websocket: close 1000: done")
+	require.ErrorIs(t, conn.WriteMessage(TextMessage, nil), ErrCloseSent)
+
+	// Panics close the connection with CloseInternalServerErr
+	conn, _ = dial(t, addr, "/panic")
+	_, _, err = conn.ReadMessage()
+	require.True(t, IsCloseError(err, CloseInternalServerErr))
+}
+
+// go test -run Test_WebSocket_Shutdown
+func Test_WebSocket_Shutdown(t *testing.T) {
+	t.Parallel()
+	started := make(chan struct{})
+	app := fiber.New()
+	app.Get("/", New(func(c *Conn) {
+		close(started)
+		_, _, _ = c.ReadMessage() //nolint:errcheck // The connection is closed on shutdown
+	}))
+	addr := make(chan string, 1)
+	go func() {
+		assert.NoError(t, app.Listen("127.0.0.1:0", fiber.ListenConfig{
+			DisableStartupMessage: true,
+			ListenerAddrFunc: func(a net.Addr) {
+				addr <- a.String()
+			},
+		}))
+	}()
+	conn, _ := dial(t, <-addr, "/")
+	<-started
+
+	require.NoError(t, app.Shutdown())
+	_, _, err := conn.ReadMessage()
+	require.True(t, IsCloseError(err, CloseGoingAway))
+}
+
+// go test -run Test_SelectSubprotocol
+func Test_SelectSubprotocol(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "b", selectSubprotocol([]string{"b", "a"}, "a, b"))
+	require.Equal(t, "a", selectSubprotocol([]string{"c", "a"}, "a,b"))
+	require.Equal(t, "", selectSubprotocol([]string{"c"}, "a, b"))
+	require.Equal(t, "", selectSubprotocol(nil, "a"))
+}
+
+// go test -v -run=^$ -bench=Benchmark_WebSocket_Echo -benchmem -count=4
+func Benchmark_WebSocket_Echo(b *testing.B) {
+	app := fiber.New()
+	app.Get("/", New(func(c *Conn) {
+		for {
+			mt, msg, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := c.WriteMessage(mt, msg); err != nil {
+				return
+			}
+		}
+	}))
+	conn, _ := dial(b, listen(b, app), "/")
+	msg := []byte("hello")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if err := conn.WriteMessage(TextMessage, msg); err != nil {
+			b.Fatal(err)
+		}
+		if _, _, err := conn.ReadMessage(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -84,7 +84,7 @@ func (grp *Group) mount(prefix string, subApp *App) Router {
 	}
 
 	// register mounted group
-	mountGroup := &Group{Prefix: groupPath, app: subApp}
+	mountGroup := &Group{Prefix: groupPath, app: subApp, host: grp.host}
 	grp.app.register([]string{methodUse}, groupPath, mountGroup, nil)
 
 	// Execute onMount hooks
@@ -204,6 +204,11 @@ func (app *App) processSubAppsRoutes() {
 				// Add the parent route's path as a prefix to the sub-app's route
 				app.addPrefixToRoute(route.path, subAppRouteClone)
 
+				// Routes of a sub app which is mounted for a host only match the host
+				if route.host != nil && subAppRouteClone.host == nil {
+					subAppRouteClone.setHost(route.host)
+				}
+
 				// Add the cloned sub-app's route to the slice of sub-app routes
 				subRoutes[j] = subAppRouteClone
 			}
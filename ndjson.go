@@ -0,0 +1,82 @@
+package fiber
+
+import (
+	"bufio"
+	"errors"
+)
+
+// ErrNDJSONStreamClosed is returned by the methods of an NDJSONStream after the stream was closed.
+var ErrNDJSONStreamClosed = errors.New("ndjson: stream is closed")
+
+// NDJSONConfig is a struct to customize the stream of Ctx.NDJSON.
+type NDJSONConfig struct {
+	// FlushEvery is the number of records after which the written records are flushed
+	// to the client. Set to a negative value to only flush when NDJSONStream.Flush is
+	// called and when the stream ends, e.g. for bulk exports.
+	//
+	// Optional. Default: 1
+	FlushEvery int
+}
+
+// NDJSONStream writes newline-delimited JSON records to the response, it is created by Ctx.NDJSON.
+// Its methods are not safe for concurrent use.
+type NDJSONStream struct {
+	app        *App
+	w          *bufio.Writer
+	err        error
+	flushEvery int
+	pending    int
+}
+
+// ndjsonConfigDefault is a function to set default values of NDJSONConfig.
+func ndjsonConfigDefault(config ...NDJSONConfig) NDJSONConfig {
+	if len(config) < 1 {
+		return NDJSONConfig{FlushEvery: 1}
+	}
+	cfg := config[0]
+	if cfg.FlushEvery == 0 {
+		cfg.FlushEvery = 1
+	}
+	return cfg
+}
+
+// Write encodes the value with the JSONEncoder of the app and writes it as a record.
+// The records are flushed every NDJSONConfig.FlushEvery records. It returns an error
+// if the value can't be encoded or the client disconnected.
+func (s *NDJSONStream) Write(v any) error {
+	if s.err != nil {
+		return s.err
+	}
+	record, err := s.app.config.JSONEncoder(v)
+	if err != nil {
+		return err
+	}
+	_, _ = s.w.Write(record) //nolint:errcheck // Write errors are reported by Flush
+	_ = s.w.WriteByte('\n')  //nolint:errcheck // Write errors are reported by Flush
+	s.pending++
+	if s.flushEvery > 0 && s.pending >= s.flushEvery {
+		return s.Flush()
+	}
+	return nil
+}
+
+// Flush sends the written records to the client. It returns an error if the client disconnected.
+func (s *NDJSONStream) Flush() error {
+	if s.err != nil {
+		return s.err
+	}
+	s.pending = 0
+	if err := s.w.Flush(); err != nil {
+		s.err = err
+		return err
+	}
+	return nil
+}
+
+// run streams the records of fn and closes the stream when fn returns
+func (s *NDJSONStream) run(fn func(stream *NDJSONStream)) {
+	fn(s)
+	if s.err == nil {
+		s.err = ErrNDJSONStreamClosed
+	}
+}
@@ -0,0 +1,90 @@
+package fiber
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_Ctx_NDJSON
+func Test_Ctx_NDJSON(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		return c.NDJSON(func(stream *NDJSONStream) {
+			for id := range slices.Values([]int{1, 2}) {
+				assert.NoError(t, stream.Write(Map{"id": id}))
+			}
+			assert.NoError(t, stream.Write("text"))
+			assert.Error(t, stream.Write(make(chan int)))
+		})
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, MIMEApplicationNDJSON, resp.Header.Get(HeaderContentType))
+	require.Equal(t, "no", resp.Header.Get("X-Accel-Buffering"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "{\"id\":1}\n{\"id\":2}\n\"text\"\n", string(body))
+}
+
+// go test -run Test_NDJSONStream_Flush
+func Test_NDJSONStream_Flush(t *testing.T) {
+	t.Parallel()
+	app := New()
+	out := &flushRecorder{}
+	stream := &NDJSONStream{app: app, w: bufio.NewWriter(out), flushEvery: 2}
+
+	require.NoError(t, stream.Write(1))
+	require.Empty(t, out.flushes)
+	require.NoError(t, stream.Write(2))
+	require.Equal(t, []string{"1\n2\n"}, out.flushes)
+	require.NoError(t, stream.Write(3))
+	require.NoError(t, stream.Flush())
+	require.Equal(t, []string{"1\n2\n", "3\n"}, out.flushes)
+
+	// Only flush manually
+	out = &flushRecorder{}
+	stream = &NDJSONStream{app: app, w: bufio.NewWriter(out), flushEvery: ndjsonConfigDefault(NDJSONConfig{FlushEvery: -1}).FlushEvery}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, stream.Write(i))
+	}
+	require.Empty(t, out.flushes)
+	require.NoError(t, stream.Flush())
+	require.Equal(t, []string{"0\n1\n2\n"}, out.flushes)
+
+	// The stream is closed after fn returned or the client disconnected
+	stream.run(func(*NDJSONStream) {})
+	require.ErrorIs(t, stream.Write(4), ErrNDJSONStreamClosed)
+	require.ErrorIs(t, stream.Flush(), ErrNDJSONStreamClosed)
+
+	out = &flushRecorder{err: errors.New("client disconnected")}
+	stream = &NDJSONStream{app: app, w: bufio.NewWriter(out), flushEvery: 1}
+	require.EqualError(t, stream.Write(1), "client disconnected")
+	require.EqualError(t, stream.Write(2), "client disconnected")
+
+	require.Equal(t, NDJSONConfig{FlushEvery: 1}, ndjsonConfigDefault())
+}
+
+// flushRecorder records the data of each flush of a bufio.Writer
+type flushRecorder struct {
+	err     error
+	flushes []string
+}
+
+func (r *flushRecorder) Write(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	r.flushes = append(r.flushes, string(p))
+	return len(p), nil
+}
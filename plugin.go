@@ -0,0 +1,143 @@
+package fiber
+
+import (
+	"fmt"
+)
+
+// Plugin is a reusable module like authentication, an admin UI or metrics, which
+// contributes routes, middleware and hooks to an app with RegisterPlugin.
+type Plugin interface {
+	// Name identifies the plugin, it must be unique per app.
+	Name() string
+	// Register adds the routes, middleware and hooks of the plugin to the app.
+	Register(app *App) error
+}
+
+// PluginWithDependencies is a plugin which requires other plugins. The dependencies are
+// registered before the plugin.
+type PluginWithDependencies interface {
+	Plugin
+	// Dependencies returns the names of the plugins the plugin depends on.
+	Dependencies() []string
+}
+
+// PluginWithConfig is a plugin which reads its section of Config.Plugins.
+type PluginWithConfig interface {
+	Plugin
+	// Configure is called with the config section of the plugin before it is registered,
+	// if the app config contains a section with the name of the plugin.
+	Configure(section any) error
+}
+
+// RegisterPlugin registers the plugins with the app. Plugins are registered in the given
+// order, except that the dependencies of a plugin are registered before it. Dependencies
+// must be passed in the same call or be registered before. The registration stops at the
+// first error.
+//
+//	err := app.RegisterPlugin(metrics.New(), admin.New())
+func (app *App) RegisterPlugin(plugins ...Plugin) error {
+	ordered, err := app.orderPlugins(plugins)
+	if err != nil {
+		return err
+	}
+
+	for _, plugin := range ordered {
+		if configurable, ok := plugin.(PluginWithConfig); ok {
+			if section, ok := app.config.Plugins[plugin.Name()]; ok {
+				if err := configurable.Configure(section); err != nil {
+					return fmt.Errorf("plugin: failed to configure %q: %w", plugin.Name(), err)
+				}
+			}
+		}
+		// The app isn't locked, as the plugin registers routes
+		if err := plugin.Register(app); err != nil {
+			return fmt.Errorf("plugin: failed to register %q: %w", plugin.Name(), err)
+		}
+
+		app.mutex.Lock()
+		app.plugins = append(app.plugins, plugin)
+		app.mutex.Unlock()
+	}
+	return nil
+}
+
+// Plugin returns the registered plugin with the name, or nil
+func (app *App) Plugin(name string) Plugin {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	for _, plugin := range app.plugins {
+		if plugin.Name() == name {
+			return plugin
+		}
+	}
+	return nil
+}
+
+// Plugins returns the registered plugins in the order of their registration
+func (app *App) Plugins() []Plugin {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	return append([]Plugin(nil), app.plugins...)
+}
+
+// orderPlugins returns the plugins in the order of registration, so that each plugin is
+// registered after its dependencies
+func (app *App) orderPlugins(plugins []Plugin) ([]Plugin, error) {
+	registered := make(map[string]struct{})
+	for _, plugin := range app.Plugins() {
+		registered[plugin.Name()] = struct{}{}
+	}
+
+	pending := make(map[string]Plugin, len(plugins))
+	for _, plugin := range plugins {
+		name := plugin.Name()
+		if _, ok := registered[name]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrPluginRegistered, name)
+		}
+		if _, ok := pending[name]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrPluginRegistered, name)
+		}
+		pending[name] = plugin
+	}
+
+	ordered := make([]Plugin, 0, len(plugins))
+	visiting := make(map[string]struct{})
+	var visit func(plugin Plugin) error
+	visit = func(plugin Plugin) error {
+		name := plugin.Name()
+		if _, ok := registered[name]; ok {
+			return nil
+		}
+		if _, ok := visiting[name]; ok {
+			return fmt.Errorf("%w: %q", ErrPluginCycle, name)
+		}
+		visiting[name] = struct{}{}
+
+		if dependent, ok := plugin.(PluginWithDependencies); ok {
+			for _, dependency := range dependent.Dependencies() {
+				if _, ok := registered[dependency]; ok {
+					continue
+				}
+				p, ok := pending[dependency]
+				if !ok {
+					return fmt.Errorf("%w: %q requires %q", ErrPluginDependency, name, dependency)
+				}
+				if err := visit(p); err != nil {
+					return err
+				}
+			}
+		}
+
+		delete(visiting, name)
+		registered[name] = struct{}{}
+		ordered = append(ordered, plugin)
+		return nil
+	}
+
+	for _, plugin := range plugins {
+		if err := visit(plugin); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,132 @@
+package fiber
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testPlugin struct {
+	register     func(app *App) error
+	section      any
+	name         string
+	dependencies []string
+}
+
+func (p *testPlugin) Name() string {
+	return p.name
+}
+
+func (p *testPlugin) Register(app *App) error {
+	if p.register != nil {
+		return p.register(app)
+	}
+	return nil
+}
+
+func (p *testPlugin) Dependencies() []string {
+	return p.dependencies
+}
+
+func (p *testPlugin) Configure(section any) error {
+	if section == "invalid" {
+		return errors.New("invalid section")
+	}
+	p.section = section
+	return nil
+}
+
+func pluginNames(plugins []Plugin) []string {
+	names := make([]string, len(plugins))
+	for i, plugin := range plugins {
+		names[i] = plugin.Name()
+	}
+	return names
+}
+
+// go test -run Test_App_RegisterPlugin
+func Test_App_RegisterPlugin(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		Plugins: map[string]any{"admin": Map{"prefix": "/admin"}},
+	})
+
+	var shutdown bool
+	auth := &testPlugin{name: "auth", register: func(app *App) error {
+		app.Use(func(c Ctx) error {
+			c.Set("X-Auth", "checked")
+			return c.Next()
+		})
+		app.Hooks().OnShutdown(func() error {
+			shutdown = true
+			return nil
+		})
+		return nil
+	}}
+	admin := &testPlugin{name: "admin", dependencies: []string{"auth"}}
+	admin.register = func(app *App) error {
+		prefix, ok := admin.section.(Map)["prefix"].(string)
+		require.True(t, ok)
+		app.Get(prefix, func(c Ctx) error {
+			return c.SendString("admin")
+		})
+		return nil
+	}
+
+	// The dependency is registered first
+	require.NoError(t, app.RegisterPlugin(admin, auth))
+	require.Equal(t, []string{"auth", "admin"}, pluginNames(app.Plugins()))
+	require.Same(t, admin, app.Plugin("admin"))
+	require.Nil(t, app.Plugin("metrics"))
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/admin", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "admin", string(body))
+	require.Equal(t, "checked", resp.Header.Get("X-Auth"))
+
+	// Dependencies may be registered before
+	require.NoError(t, app.RegisterPlugin(&testPlugin{name: "audit", dependencies: []string{"auth"}}))
+	require.Equal(t, []string{"auth", "admin", "audit"}, pluginNames(app.Plugins()))
+
+	require.NoError(t, app.Shutdown())
+	require.True(t, shutdown)
+}
+
+// go test -run Test_App_RegisterPlugin_Errors
+func Test_App_RegisterPlugin_Errors(t *testing.T) {
+	t.Parallel()
+
+	app := New()
+	require.NoError(t, app.RegisterPlugin(&testPlugin{name: "auth"}))
+	require.ErrorIs(t, app.RegisterPlugin(&testPlugin{name: "auth"}), ErrPluginRegistered)
+	require.ErrorIs(t, app.RegisterPlugin(&testPlugin{name: "a"}, &testPlugin{name: "a"}), ErrPluginRegistered)
+
+	err := app.RegisterPlugin(&testPlugin{name: "admin", dependencies: []string{"metrics"}})
+	require.ErrorIs(t, err, ErrPluginDependency)
+	require.ErrorContains(t, err, `"admin" requires "metrics"`)
+
+	err = app.RegisterPlugin(
+		&testPlugin{name: "a", dependencies: []string{"b"}},
+		&testPlugin{name: "b", dependencies: []string{"a"}},
+	)
+	require.ErrorIs(t, err, ErrPluginCycle)
+
+	// Nothing is registered if the plugins can't be ordered
+	require.Equal(t, []string{"auth"}, pluginNames(app.Plugins()))
+
+	errRegister := errors.New("register failed")
+	err = app.RegisterPlugin(&testPlugin{name: "broken", register: func(_ *App) error {
+		return errRegister
+	}})
+	require.ErrorIs(t, err, errRegister)
+	require.ErrorContains(t, err, `plugin: failed to register "broken"`)
+	require.Nil(t, app.Plugin("broken"))
+
+	app = New(Config{Plugins: map[string]any{"auth": "invalid"}})
+	require.ErrorContains(t, app.RegisterPlugin(&testPlugin{name: "auth"}), `plugin: failed to configure "auth": invalid section`)
+}
@@ -67,7 +67,7 @@ func (app *App) prefork(addr string, tlsConfig *tls.Config, cfg ListenConfig) er
 		}
 
 		// listen for incoming connections
-		return app.server.Serve(ln)
+		return app.server.Serve(app.newHTTP2Listener(ln, cfg))
 	}
 
 	// 👮 master process 👮
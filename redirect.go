@@ -6,6 +6,7 @@ package fiber
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/gofiber/fiber/v3/binder"
@@ -252,7 +253,11 @@ func (r *Redirect) Route(name string, config ...RedirectConfig) error {
 	}
 
 	// Get location from route name
-	location, err := r.c.getLocationFromRoute(r.c.App().GetRoute(name), cfg.Params)
+	route := r.c.App().GetRoute(name)
+	if route.Path == "" {
+		return fmt.Errorf("%w: %q", ErrRouteNotFound, name)
+	}
+	location, err := r.c.getLocationFromRoute(route, cfg.Params)
 	if err != nil {
 		return err
 	}
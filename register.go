@@ -22,11 +22,12 @@ type Register interface {
 	Route(path string) Register
 }
 
-var _ (Register) = (*Registering)(nil)
+var _ Register = (*Registering)(nil)
 
 // Registering struct
 type Registering struct {
-	app *App
+	app   *App
+	group *Group
 
 	path string
 }
@@ -46,15 +47,14 @@ type Registering struct {
 //
 // This method will match all HTTP verbs: GET, POST, PUT, HEAD etc...
 func (r *Registering) All(handler Handler, middleware ...Handler) Register {
-	r.app.register([]string{methodUse}, r.path, nil, handler, middleware...)
+	r.app.register([]string{methodUse}, r.path, r.group, handler, middleware...)
 	return r
 }
 
 // Get registers a route for GET methods that requests a representation
 // of the specified resource. Requests using GET should only retrieve data.
 func (r *Registering) Get(handler Handler, middleware ...Handler) Register {
-	r.app.Add([]string{MethodGet}, r.path, handler, middleware...)
-	return r
+	return r.Add([]string{MethodGet}, handler, middleware...)
 }
 
 // Head registers a route for HEAD methods that asks for a response identical
@@ -106,7 +106,7 @@ func (r *Registering) Patch(handler Handler, middleware ...Handler) Register {
 
 // Add allows you to specify multiple HTTP methods to register a route.
 func (r *Registering) Add(methods []string, handler Handler, middleware ...Handler) Register {
-	r.app.register(methods, r.path, nil, handler, middleware...)
+	r.app.register(methods, r.path, r.group, handler, middleware...)
 	return r
 }
 
@@ -114,7 +114,7 @@ func (r *Registering) Add(methods []string, handler Handler, middleware ...Handl
 // the path in the current instance as its prefix.
 func (r *Registering) Route(path string) Register {
 	// Create new group
-	route := &Registering{app: r.app, path: getGroupPath(r.path, path)}
+	route := &Registering{app: r.app, group: r.group, path: getGroupPath(r.path, path)}
 
 	return route
 }
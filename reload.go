@@ -0,0 +1,181 @@
+package fiber
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// ConfigUpdate contains the settings which can be changed at runtime with
+// App.ReloadConfig. Nil fields are unchanged.
+type ConfigUpdate struct {
+	// TrustProxy replaces Config.TrustProxy
+	TrustProxy *bool
+	// TrustProxyConfig replaces Config.TrustProxyConfig
+	TrustProxyConfig *TrustProxyConfig
+	// LogLevel is set with log.SetLevel
+	LogLevel *log.Level
+	// Maintenance replaces Config.Maintenance
+	Maintenance *bool
+	// Settings are set as the values of Setting, a nil value deletes the setting.
+	// They're used by middlewares and handlers, e.g. for the limit of a limiter.
+	Settings map[string]any
+}
+
+// runtimeConfig contains the settings which can be changed at runtime, they're read
+// by every request without locks
+type runtimeConfig struct {
+	trustProxy  atomic.Pointer[trustProxy]
+	settings    atomic.Pointer[map[string]any]
+	maintenance atomic.Bool
+	// mu serializes the updates and protects App.config
+	mu sync.RWMutex
+}
+
+// trustProxy is a snapshot of Config.TrustProxy and Config.TrustProxyConfig
+type trustProxy struct {
+	config  TrustProxyConfig
+	enabled bool
+}
+
+// ReloadConfig changes the settings of the update at runtime, without restarting the
+// app. The update is validated before it is applied, an invalid update returns an
+// error and changes nothing. The OnReload hooks are called with the update after it was
+// applied, which allows middlewares to react to changes.
+//
+//	level := log.LevelDebug
+//	maintenance := true
+//	err := app.ReloadConfig(fiber.ConfigUpdate{
+//		LogLevel:    &level,
+//		Maintenance: &maintenance,
+//		Settings:    map[string]any{"limiter.max": 100},
+//	})
+func (app *App) ReloadConfig(update ConfigUpdate) error {
+	rc := &app.runtime
+	rc.mu.Lock()
+
+	// Validate the update
+	if update.LogLevel != nil && (*update.LogLevel < log.LevelTrace || *update.LogLevel > log.LevelPanic) {
+		rc.mu.Unlock()
+		return fmt.Errorf("%w: log level %d", ErrInvalidConfigUpdate, *update.LogLevel)
+	}
+	var proxyConfig TrustProxyConfig
+	if update.TrustProxyConfig != nil {
+		var err error
+		if proxyConfig, err = compileTrustProxyConfig(*update.TrustProxyConfig); err != nil {
+			rc.mu.Unlock()
+			return fmt.Errorf("%w: %w", ErrInvalidConfigUpdate, err)
+		}
+	}
+
+	// Apply the update
+	if update.TrustProxy != nil || update.TrustProxyConfig != nil {
+		if update.TrustProxy != nil {
+			app.config.TrustProxy = *update.TrustProxy
+		}
+		if update.TrustProxyConfig != nil {
+			app.config.TrustProxyConfig = proxyConfig
+		}
+		rc.trustProxy.Store(&trustProxy{enabled: app.config.TrustProxy, config: app.config.TrustProxyConfig})
+	}
+	if update.Maintenance != nil {
+		app.config.Maintenance = *update.Maintenance
+		rc.maintenance.Store(*update.Maintenance)
+	}
+	if update.LogLevel != nil {
+		log.SetLevel(*update.LogLevel)
+	}
+	if update.Settings != nil {
+		current := rc.settings.Load()
+		settings := make(map[string]any, len(*current)+len(update.Settings))
+		for key, value := range *current {
+			settings[key] = value
+		}
+		for key, value := range update.Settings {
+			if value == nil {
+				delete(settings, key)
+			} else {
+				settings[key] = value
+			}
+		}
+		rc.settings.Store(&settings)
+	}
+	rc.mu.Unlock()
+
+	// Notify the middlewares
+	return app.hooks.executeOnReloadHooks(update)
+}
+
+// Setting returns the value of the setting of the key, which is set with
+// App.ReloadConfig, or the default value if the setting isn't set or isn't of type V.
+// It can be called for every request.
+//
+//	app.Use(limiter.New(limiter.Config{
+//		MaxFunc: func(c fiber.Ctx) int {
+//			return fiber.Setting(c.App(), "limiter.max", 20)
+//		},
+//	}))
+func Setting[V any](app *App, key string, defaultValue V) V {
+	if value, ok := (*app.runtime.settings.Load())[key].(V); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// Maintenance returns true if the app is in maintenance mode
+func (app *App) Maintenance() bool {
+	return app.runtime.maintenance.Load()
+}
+
+// initRuntimeConfig initializes the runtime config from the config of the app
+func (app *App) initRuntimeConfig() {
+	proxyConfig, errs := compileTrustProxyConfigLenient(app.config.TrustProxyConfig)
+	for _, err := range errs {
+		log.Warn(err)
+	}
+	app.config.TrustProxyConfig = proxyConfig
+	app.runtime.trustProxy.Store(&trustProxy{enabled: app.config.TrustProxy, config: proxyConfig})
+	app.runtime.maintenance.Store(app.config.Maintenance)
+	app.runtime.settings.Store(&map[string]any{})
+}
+
+// compileTrustProxyConfig returns the config with the parsed proxies, or an error if a
+// proxy can't be parsed
+func compileTrustProxyConfig(cfg TrustProxyConfig) (TrustProxyConfig, error) {
+	compiled, errs := compileTrustProxyConfigLenient(cfg)
+	if len(errs) > 0 {
+		return TrustProxyConfig{}, errors.Join(errs...)
+	}
+	return compiled, nil
+}
+
+// compileTrustProxyConfigLenient returns the config with the parsed proxies and the
+// errors of the proxies which can't be parsed
+func compileTrustProxyConfigLenient(cfg TrustProxyConfig) (TrustProxyConfig, []error) {
+	var errs []error
+	cfg.ips = make(map[string]struct{}, len(cfg.Proxies))
+	cfg.ranges = nil
+	for _, ipAddress := range cfg.Proxies {
+		if strings.Contains(ipAddress, "/") {
+			_, ipNet, err := net.ParseCIDR(ipAddress)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("IP range %q could not be parsed: %w", ipAddress, err))
+			} else {
+				cfg.ranges = append(cfg.ranges, ipNet)
+			}
+		} else {
+			ip := net.ParseIP(ipAddress)
+			if ip == nil {
+				errs = append(errs, fmt.Errorf("IP address %q could not be parsed", ipAddress))
+			} else {
+				cfg.ips[ipAddress] = struct{}{}
+			}
+		}
+	}
+	return cfg, errs
+}
@@ -0,0 +1,187 @@
+package fiber
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_App_ReloadConfig_TrustProxy
+func Test_App_ReloadConfig_TrustProxy(t *testing.T) {
+	t.Parallel()
+	app := New(Config{TrustProxy: true})
+
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	c.Request().Header.Set(HeaderXForwardedHost, "example.com")
+	require.False(t, c.IsProxyTrusted())
+
+	require.NoError(t, app.ReloadConfig(ConfigUpdate{
+		TrustProxyConfig: &TrustProxyConfig{Proxies: []string{"0.0.0.0", "10.0.0.0/8"}},
+	}))
+	require.True(t, c.IsProxyTrusted())
+	require.Equal(t, "example.com", c.Hostname())
+	require.Equal(t, []string{"0.0.0.0", "10.0.0.0/8"}, app.Config().TrustProxyConfig.Proxies)
+
+	disabled := false
+	require.NoError(t, app.ReloadConfig(ConfigUpdate{TrustProxy: &disabled}))
+	require.True(t, c.IsProxyTrusted())
+	require.False(t, app.Config().TrustProxy)
+
+	// An invalid update changes nothing
+	enabled := true
+	err := app.ReloadConfig(ConfigUpdate{
+		TrustProxy:       &enabled,
+		TrustProxyConfig: &TrustProxyConfig{Proxies: []string{"10.0.0.0/64", "invalid"}},
+	})
+	require.ErrorIs(t, err, ErrInvalidConfigUpdate)
+	require.ErrorContains(t, err, `IP range "10.0.0.0/64" could not be parsed`)
+	require.ErrorContains(t, err, `IP address "invalid" could not be parsed`)
+	require.False(t, app.Config().TrustProxy)
+	require.Len(t, app.Config().TrustProxyConfig.Proxies, 2)
+}
+
+// go test -run Test_App_ReloadConfig_Maintenance
+func Test_App_ReloadConfig_Maintenance(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		Maintenance: true,
+		MaintenanceNext: func(c Ctx) bool {
+			return c.Path() == "/health"
+		},
+	})
+	app.Get("/*", func(c Ctx) error {
+		return c.SendString("ok")
+	})
+
+	require.True(t, app.Maintenance())
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusServiceUnavailable, resp.StatusCode)
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/health", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+
+	disabled := false
+	require.NoError(t, app.ReloadConfig(ConfigUpdate{Maintenance: &disabled}))
+	require.False(t, app.Maintenance())
+	require.False(t, app.Config().Maintenance)
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+}
+
+// go test -run Test_App_ReloadConfig_Settings
+func Test_App_ReloadConfig_Settings(t *testing.T) {
+	t.Parallel()
+	app := New()
+	require.Equal(t, 20, Setting(app, "limiter.max", 20))
+
+	require.NoError(t, app.ReloadConfig(ConfigUpdate{
+		Settings: map[string]any{"limiter.max": 100, "banner": "incident"},
+	}))
+	require.Equal(t, 100, Setting(app, "limiter.max", 20))
+	require.Equal(t, "incident", Setting(app, "banner", ""))
+	// The default value is returned for values of another type
+	require.Equal(t, "none", Setting(app, "limiter.max", "none"))
+
+	require.NoError(t, app.ReloadConfig(ConfigUpdate{
+		Settings: map[string]any{"banner": nil},
+	}))
+	require.Equal(t, 100, Setting(app, "limiter.max", 20))
+	require.Equal(t, "", Setting(app, "banner", ""))
+}
+
+// go test -run Test_App_ReloadConfig_LogLevel
+func Test_App_ReloadConfig_LogLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.SetLevel(log.LevelTrace)
+	}()
+
+	app := New()
+	level := log.LevelError
+	require.NoError(t, app.ReloadConfig(ConfigUpdate{LogLevel: &level}))
+	log.Info("hidden")
+	log.Error("shown")
+	require.NotContains(t, buf.String(), "hidden")
+	require.Contains(t, buf.String(), "shown")
+
+	level = log.Level(42)
+	require.ErrorIs(t, app.ReloadConfig(ConfigUpdate{LogLevel: &level}), ErrInvalidConfigUpdate)
+}
+
+// go test -run Test_Hook_OnReload
+func Test_Hook_OnReload(t *testing.T) {
+	t.Parallel()
+	app := New()
+	errHook := errors.New("hook failed")
+
+	var updates []ConfigUpdate
+	app.Hooks().OnReload(func(update ConfigUpdate) error {
+		updates = append(updates, update)
+		if update.Maintenance != nil {
+			return errHook
+		}
+		return nil
+	})
+
+	require.NoError(t, app.ReloadConfig(ConfigUpdate{Settings: map[string]any{"key": "value"}}))
+	require.Len(t, updates, 1)
+	require.Equal(t, "value", updates[0].Settings["key"])
+
+	// The update is applied before the hooks are called
+	enabled := true
+	require.ErrorIs(t, app.ReloadConfig(ConfigUpdate{Maintenance: &enabled}), errHook)
+	require.Len(t, updates, 2)
+	require.True(t, app.Maintenance())
+
+	// Invalid updates don't call the hooks
+	require.Error(t, app.ReloadConfig(ConfigUpdate{TrustProxyConfig: &TrustProxyConfig{Proxies: []string{"x"}}}))
+	require.Len(t, updates, 2)
+}
+
+// go test -run Test_App_ReloadConfig_Concurrent -race
+func Test_App_ReloadConfig_Concurrent(t *testing.T) {
+	t.Parallel()
+	app := New(Config{TrustProxy: true})
+	app.Get("/", func(c Ctx) error {
+		_ = Setting(c.App(), "limiter.max", 0)
+		return c.SendString(c.Hostname())
+	})
+	handler := app.Handler()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			fctx := &fasthttp.RequestCtx{}
+			fctx.Init(&fasthttp.Request{}, &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1)}, nil)
+			fctx.Request.Header.SetMethod(MethodGet)
+			fctx.Request.SetRequestURI("/")
+			handler(fctx)
+		}()
+		go func() {
+			defer wg.Done()
+			enabled := i%2 == 0
+			require.NoError(t, app.ReloadConfig(ConfigUpdate{
+				TrustProxy:       &enabled,
+				TrustProxyConfig: &TrustProxyConfig{Private: true},
+				Settings:         map[string]any{"limiter.max": i},
+			}))
+			_ = app.Config()
+		}()
+	}
+	wg.Wait()
+}
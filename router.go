@@ -8,9 +8,12 @@ import (
 	"errors"
 	"fmt"
 	"html"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/gofiber/utils/v2"
 	"github.com/valyala/fasthttp"
@@ -34,10 +37,17 @@ type Router interface {
 	All(path string, handler Handler, middleware ...Handler) Router
 
 	Group(prefix string, handlers ...Handler) Router
+	Host(host string, handlers ...Handler) Router
 
 	Route(path string) Register
 
 	Name(name string) Router
+	Meta(key string, value any) Router
+	ReadTimeout(timeout time.Duration) Router
+	WriteTimeout(timeout time.Duration) Router
+	BodyLimit(limit int) Router
+
+	SetErrorHandler(handler ErrorHandler) Router
 }
 
 // Route is a struct that holds all metadata for each registered handler.
@@ -51,10 +61,20 @@ type Route struct {
 	Method string `json:"method"` // HTTP method
 	Name   string `json:"name"`   // Route's name
 	//nolint:revive // Having both a Path (uppercase) and a path (lowercase) is fine
-	Path        string      `json:"path"`   // Original registered route path
-	Params      []string    `json:"params"` // Case-sensitive param keys
-	Handlers    []Handler   `json:"-"`      // Ctx handlers
-	routeParser routeParser // Parameter parser
+	Path        string       `json:"path"`           // Original registered route path
+	Host        string       `json:"host,omitempty"` // Host of the route, empty if it matches all hosts
+	Params      []string     `json:"params"`         // Case-sensitive param keys
+	Meta        Map          `json:"meta,omitempty"` // Metadata of the route, set with Meta
+	Handlers    []Handler    `json:"-"`              // Ctx handlers
+	routeParser routeParser  // Parameter parser
+	host        *hostMatcher // Matcher of the host
+	// Timeouts and body limit which override the config of the app
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+	bodyLimit    int
+	// counted is shared by the copies of a Use route in the method stacks and reports
+	// whether its handlers are still counted, it's guarded by the mutex of the app
+	counted *bool
 	// Data for routing
 	pos   uint32 // Position in stack -> important for the sort of the matched routes
 	use   bool   // USE matches path prefixes
@@ -98,12 +118,88 @@ func (r *Route) match(detectionPath, path string, params *[maxParams]string) boo
 	return false
 }
 
-func (app *App) nextCustom(c CustomCtx) (bool, error) { //nolint: unparam // bool param might be useful for testing
-	// Get stack length
-	tree, ok := app.treeStack[c.getMethodINT()][c.getTreePath()]
+// PathSegment is a constant part or a parameter of a route path
+type PathSegment struct {
+	Const       string        // Constant part of the route, if it is not a parameter
+	ParamName   string        // Name of the parameter, wildcard and plus parameters are numbered e.g. "*1"
+	Constraints []*Constraint // Constraints of the parameter, e.g. int or min(5)
+	IsParam     bool          // Whether the segment is a parameter
+	IsGreedy    bool          // Whether the parameter is a wildcard or plus parameter
+	IsOptional  bool          // Whether the parameter is optional
+}
+
+// Segments returns the constant parts and parameters of the route path in order,
+// e.g. to generate documentation of the routes.
+func (r *Route) Segments() []PathSegment {
+	segments := make([]PathSegment, len(r.routeParser.segs))
+	for i, seg := range r.routeParser.segs {
+		segments[i] = PathSegment{
+			Const:       seg.Const,
+			ParamName:   seg.ParamName,
+			Constraints: seg.Constraints,
+			IsParam:     seg.IsParam,
+			IsGreedy:    seg.IsGreedy,
+			IsOptional:  seg.IsOptional,
+		}
+	}
+	return segments
+}
+
+// TemplateName returns the name of the parameter in a path template. Wildcard and plus
+// parameters are named "wildcard" and "plus", followed by their number if it isn't 1.
+func (s PathSegment) TemplateName() string {
+	switch {
+	case strings.HasPrefix(s.ParamName, "*"):
+		return "wildcard" + strings.TrimPrefix(s.ParamName[1:], "1")
+	case strings.HasPrefix(s.ParamName, "+"):
+		return "plus" + strings.TrimPrefix(s.ParamName[1:], "1")
+	}
+	return s.ParamName
+}
+
+// TemplatePath returns the path of the segments with the parameters in braces, as used
+// by OpenAPI, e.g. "/users/{id}" for the route path "/users/:id".
+func TemplatePath(segments []PathSegment) string {
+	return templatePath(segments, false)
+}
+
+// templatePath returns the path template of the segments, greedy parameters get
+// a "+" suffix if greedy is set
+func templatePath(segments []PathSegment, greedy bool) string {
+	var b strings.Builder
+	for _, seg := range segments {
+		if !seg.IsParam {
+			b.WriteString(seg.Const)
+			continue
+		}
+		name := seg.TemplateName()
+		if greedy && seg.IsGreedy {
+			name += "+"
+		}
+		b.WriteString("{" + name + "}")
+	}
+	if b.Len() == 0 {
+		return "/"
+	}
+	return b.String()
+}
+
+// routeTree returns the routes of the tree stack which may match the tree path
+func routeTree(treeStack *[]map[string][]*Route, methodINT int, treePath string) []*Route {
+	tree, ok := (*treeStack)[methodINT][treePath]
 	if !ok {
-		tree = app.treeStack[c.getMethodINT()][""]
+		tree = (*treeStack)[methodINT][""]
+	}
+	return tree
+}
+
+func (app *App) nextCustom(c CustomCtx) (bool, error) { //nolint: unparam // bool param might be useful for testing
+	// Keep the tree of the request when the routing starts, so a rebuild of
+	// the tree doesn't shift the route index of the request
+	if c.getIndexRoute() == -1 || c.getTreeStack() == nil {
+		c.setTreeStack(app.treeStack.Load())
 	}
+	tree := routeTree(c.getTreeStack(), c.getMethodINT(), c.getTreePath())
 	lenr := len(tree) - 1
 
 	// Loop over the route stack starting from previous index
@@ -114,8 +210,8 @@ func (app *App) nextCustom(c CustomCtx) (bool, error) { //nolint: unparam // boo
 		// Get *Route
 		route := tree[c.getIndexRoute()]
 
-		// Check if it matches the request path
-		match := route.match(c.getDetectionPath(), c.Path(), c.getValues())
+		// Check if it matches the request path and host
+		match := route.match(c.getDetectionPath(), c.Path(), c.getValues()) && route.matchHost(c, c.getValues())
 
 		// No match, next route
 		if !match {
@@ -135,23 +231,30 @@ func (app *App) nextCustom(c CustomCtx) (bool, error) { //nolint: unparam // boo
 		return match, err // Stop scanning the stack
 	}
 
+	// Answer OPTIONS requests for paths of other methods with the allowed methods
+	if app.config.EnableAutoOptions && !c.getMatched() && c.getMethodINT() == app.methodInt(MethodOptions) && app.methodExistCustom(c) {
+		c.Append(HeaderAllow, MethodOptions)
+		return false, c.SendStatus(StatusNoContent)
+	}
+
 	// If c.Next() does not match, return 404
 	err := NewError(StatusNotFound, "Cannot "+c.Method()+" "+c.getPathOriginal())
 
 	// If no match, scan stack again if other methods match the request
 	// Moved from app.handler because middleware may break the route chain
-	if !c.getMatched() && app.methodExistCustom(c) {
+	if !c.getMatched() && !app.config.DisableMethodNotAllowed && app.methodExistCustom(c) {
 		err = ErrMethodNotAllowed
 	}
 	return false, err
 }
 
 func (app *App) next(c *DefaultCtx) (bool, error) {
-	// Get stack length
-	tree, ok := app.treeStack[c.methodINT][c.treePath]
-	if !ok {
-		tree = app.treeStack[c.methodINT][""]
+	// Keep the tree of the request when the routing starts, so a rebuild of
+	// the tree doesn't shift the route index of the request
+	if c.indexRoute == -1 || c.treeStack == nil {
+		c.treeStack = app.treeStack.Load()
 	}
+	tree := routeTree(c.treeStack, c.methodINT, c.treePath)
 	lenTree := len(tree) - 1
 
 	// Loop over the route stack starting from previous index
@@ -169,8 +272,8 @@ func (app *App) next(c *DefaultCtx) (bool, error) {
 			continue
 		}
 
-		// Check if it matches the request path
-		match = route.match(c.detectionPath, c.path, &c.values)
+		// Check if it matches the request path and host
+		match = route.match(c.detectionPath, c.path, &c.values) && route.matchHost(c, &c.values)
 		if !match {
 			// No match, next route
 			continue
@@ -191,9 +294,15 @@ func (app *App) next(c *DefaultCtx) (bool, error) {
 		return match, err // Stop scanning the stack
 	}
 
+	// Answer OPTIONS requests for paths of other methods with the allowed methods
+	if app.config.EnableAutoOptions && !c.matched && c.methodINT == app.methodInt(MethodOptions) && app.methodExist(c) {
+		c.Append(HeaderAllow, MethodOptions)
+		return false, c.SendStatus(StatusNoContent)
+	}
+
 	// If c.Next() does not match, return 404
 	err := NewError(StatusNotFound, "Cannot "+c.method+" "+html.EscapeString(c.pathOriginal))
-	if !c.matched && app.methodExist(c) {
+	if !c.matched && !app.config.DisableMethodNotAllowed && app.methodExist(c) {
 		// If no match, scan stack again if other methods match the request
 		// Moved from app.handler because middleware may break the route chain
 		err = ErrMethodNotAllowed
@@ -231,7 +340,9 @@ func (app *App) requestHandler(rctx *fasthttp.RequestCtx) {
 
 	// Find match in stack
 	var err error
-	if app.newCtxFunc != nil {
+	if app.runtime.maintenance.Load() && (app.config.MaintenanceNext == nil || !app.config.MaintenanceNext(c)) {
+		err = ErrServiceUnavailable
+	} else if app.newCtxFunc != nil {
 		_, err = app.nextCustom(c)
 	} else {
 		_, err = app.next(c.(*DefaultCtx)) //nolint:errcheck // It is fine to ignore the error here
@@ -278,12 +389,19 @@ func (*App) copyRoute(route *Route) *Route {
 		routeParser: route.routeParser,
 
 		// misc
-		pos: route.pos,
+		pos:          route.pos,
+		host:         route.host,
+		readTimeout:  route.readTimeout,
+		writeTimeout: route.writeTimeout,
+		bodyLimit:    route.bodyLimit,
+		counted:      route.counted,
 
 		// Public data
 		Path:     route.Path,
+		Host:     route.Host,
 		Params:   route.Params,
 		Name:     route.Name,
+		Meta:     maps.Clone(route.Meta),
 		Method:   route.Method,
 		Handlers: route.Handlers,
 	}
@@ -357,11 +475,23 @@ func (app *App) register(methods []string, pathRaw string, group *Group, handler
 			Method:   method,
 			Handlers: handlers,
 		}
+		if group != nil && group.host != nil {
+			route.setHost(group.host)
+		}
+		if group != nil && len(group.meta) > 0 {
+			route.Meta = maps.Clone(group.meta)
+		}
+		if group != nil {
+			route.readTimeout, route.writeTimeout, route.bodyLimit = group.readTimeout, group.writeTimeout, group.bodyLimit
+		}
 		// Increment global handler count
 		atomic.AddUint32(&app.handlersCount, uint32(len(handlers))) //nolint:gosec // Not a concern
 
 		// Middleware route matches all HTTP methods
 		if isUse {
+			// The handlers are counted once for all copies
+			counted := true
+			route.counted = &counted
 			// Add route to all HTTP methods stack
 			for _, m := range app.config.RequestMethods {
 				// Create a route copy to avoid duplicates during compression
@@ -390,9 +520,15 @@ func (app *App) addRoute(method string, route *Route, isMounted ...bool) {
 
 	// prevent identically route registration
 	l := len(app.stack[m])
-	if l > 0 && app.stack[m][l-1].Path == route.Path && route.use == app.stack[m][l-1].use && !route.mount && !app.stack[m][l-1].mount {
-		preRoute := app.stack[m][l-1]
-		preRoute.Handlers = append(preRoute.Handlers, route.Handlers...)
+	stored := route
+	if l > 0 && app.stack[m][l-1].Path == route.Path && route.use == app.stack[m][l-1].use && route.host == app.stack[m][l-1].host &&
+		!route.mount && !app.stack[m][l-1].mount {
+		// Replace the previous route instead of changing it, as it may be used by requests
+		preRoute := *app.stack[m][l-1]
+		preRoute.Handlers = append(slices.Clip(preRoute.Handlers), route.Handlers...)
+		app.stack[m][l-1] = &preRoute
+		app.routesRefreshed = true
+		stored = &preRoute
 	} else {
 		// Increment global route position
 		route.pos = atomic.AddUint32(&app.routesCount, 1)
@@ -404,21 +540,24 @@ func (app *App) addRoute(method string, route *Route, isMounted ...bool) {
 
 	// Execute onRoute hooks & change latestRoute if not adding mounted route
 	if !mounted {
-		app.latestRoute = route
+		app.latestRoute = stored
+		// The copies of a middleware route are added in the order of the methods
+		if route.use && m > 0 {
+			app.latestRoutes = append(app.latestRoutes, stored)
+		} else {
+			app.latestRoutes = []*Route{stored}
+		}
 		if err := app.hooks.executeOnRouteHooks(*route); err != nil {
 			panic(err)
 		}
 	}
 }
 
-// BuildTree rebuilds the prefix tree from the previously registered routes.
+// RebuildTree rebuilds the prefix tree from the previously registered routes.
 // This method is useful when you want to register routes dynamically after the app has started.
-// It is not recommended to use this method on production environments because rebuilding
-// the tree is performance-intensive and not thread-safe in runtime. Since building the tree
-// is only done in the startupProcess of the app, this method does not makes sure that the
-// routeTree is being safely changed, as it would add a great deal of overhead in the request.
-// Latest benchmark results showed a degradation from 82.79 ns/op to 94.48 ns/op and can be found in:
-// https://github.com/gofiber/fiber/issues/2769#issuecomment-2227385283
+// The new tree is swapped atomically, so it is safe to rebuild the tree while requests are
+// handled: new requests use the new tree, while requests in progress finish with the old one.
+// Rebuilding the tree is performance-intensive, so the routes should be changed in batches.
 func (app *App) RebuildTree() *App {
 	app.mutex.Lock()
 	defer app.mutex.Unlock()
@@ -426,6 +565,82 @@ func (app *App) RebuildTree() *App {
 	return app.buildTree()
 }
 
+// RemoveRoute removes the routes with the registered path, e.g. "/users/:id", for the methods
+// or, if no methods are passed, for all methods, and rebuilds the tree. Requests in progress
+// finish with the removed routes.
+func (app *App) RemoveRoute(path string, methods ...string) {
+	app.removeRoutes(func(r *Route) bool {
+		return r.Path == path
+	}, methods...)
+}
+
+// RemoveRouteByName removes the routes with the name for the methods or, if no methods
+// are passed, for all methods, and rebuilds the tree. Requests in progress finish with
+// the removed routes.
+func (app *App) RemoveRouteByName(name string, methods ...string) {
+	app.removeRoutes(func(r *Route) bool {
+		return r.Name == name
+	}, methods...)
+}
+
+// removeRoutes removes the matching routes of the methods from the stack and rebuilds the tree
+func (app *App) removeRoutes(match func(r *Route) bool, methods ...string) {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	if len(methods) == 0 {
+		methods = app.config.RequestMethods
+	}
+	for _, method := range methods {
+		m := app.methodInt(utils.ToUpper(method))
+		if m == -1 {
+			continue
+		}
+		// The stack is copied, as it may be read by GetRoutes
+		app.stack[m] = slices.DeleteFunc(slices.Clone(app.stack[m]), func(r *Route) bool {
+			if !match(r) {
+				return false
+			}
+			// Middleware routes are counted once, when the first of their copies is removed
+			if r.counted == nil || *r.counted {
+				atomic.AddUint32(&app.handlersCount, ^uint32(len(r.Handlers)-1)) //nolint:gosec // Not a concern
+				if r.counted != nil {
+					*r.counted = false
+				}
+			}
+			app.routesRefreshed = true
+			return true
+		})
+	}
+	app.buildTree()
+}
+
+// autoHeadRoutes returns the HEAD routes with the GET routes whose path and host
+// aren't registered for HEAD, so that they answer HEAD requests as well
+func (app *App) autoHeadRoutes(headRoutes []*Route) []*Route {
+	getIndex := app.methodInt(MethodGet)
+	if getIndex == -1 {
+		return headRoutes
+	}
+	registered := make(map[string]struct{}, len(headRoutes))
+	for _, route := range headRoutes {
+		if !route.use {
+			registered[route.Host+" "+route.Path] = struct{}{}
+		}
+	}
+	routes := slices.Clone(headRoutes)
+	for _, route := range app.stack[getIndex] {
+		// Use routes are registered for all methods
+		if route.use {
+			continue
+		}
+		if _, ok := registered[route.Host+" "+route.Path]; !ok {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
 // buildTree build the prefix tree from the previously registered routes
 func (app *App) buildTree() *App {
 	if !app.routesRefreshed {
@@ -433,9 +648,15 @@ func (app *App) buildTree() *App {
 	}
 
 	// loop all the methods and stacks and create the prefix tree
+	treeStack := make([]map[string][]*Route, len(app.config.RequestMethods))
+	headIndex := app.methodInt(MethodHead)
 	for m := range app.config.RequestMethods {
 		tsMap := make(map[string][]*Route)
-		for _, route := range app.stack[m] {
+		routes := app.stack[m]
+		if m == headIndex && app.config.EnableAutoHead {
+			routes = app.autoHeadRoutes(routes)
+		}
+		for _, route := range routes {
 			treePath := ""
 			if len(route.routeParser.segs) > 0 && len(route.routeParser.segs[0].Const) >= 3 {
 				treePath = route.routeParser.segs[0].Const[:3]
@@ -443,12 +664,12 @@ func (app *App) buildTree() *App {
 			// create tree stack
 			tsMap[treePath] = append(tsMap[treePath], route)
 		}
-		app.treeStack[m] = tsMap
+		treeStack[m] = tsMap
 	}
 
 	// loop the methods and tree stacks and add global stack and sort everything
 	for m := range app.config.RequestMethods {
-		tsMap := app.treeStack[m]
+		tsMap := treeStack[m]
 		for treePart := range tsMap {
 			if treePart != "" {
 				// merge global tree routes in current tree stack
@@ -459,6 +680,7 @@ func (app *App) buildTree() *App {
 			sort.Slice(slc, func(i, j int) bool { return slc[i].pos < slc[j].pos })
 		}
 	}
+	app.treeStack.Store(&treeStack)
 	app.routesRefreshed = false
 
 	return app
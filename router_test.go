@@ -396,6 +396,207 @@ func Test_App_Rebuild_Tree(t *testing.T) {
 	require.Equal(t, http.StatusOK, resp.StatusCode, "Status code")
 }
 
+// go test -run Test_Route_Meta
+func Test_Route_Meta(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	requireRole := func(c Ctx) error {
+		if role, ok := c.Route().Meta["auth"].(string); ok && c.Get("X-Role") != role {
+			return ErrForbidden
+		}
+		return c.Next()
+	}
+	handler := func(c Ctx) error {
+		return c.SendString(fmt.Sprint(c.Route().Meta["summary"]))
+	}
+
+	app.Get("/users", handler, requireRole).Meta("auth", "admin").Meta("summary", "users")
+	app.Get("/public", handler)
+
+	admin := app.Group("/admin").Meta("auth", "admin")
+	admin.Get("/stats", handler, requireRole).Meta("summary", "stats")
+	admin.Group("/v1").Get("/stats", handler, requireRole)
+
+	for _, route := range app.GetRoutes() {
+		switch route.Path {
+		case "/users":
+			require.Equal(t, Map{"auth": "admin", "summary": "users"}, route.Meta, route.Method)
+		case "/public":
+			require.Nil(t, route.Meta, route.Method)
+		case "/admin/v1/stats":
+			require.Equal(t, Map{"auth": "admin"}, route.Meta, route.Method)
+		}
+	}
+
+	testCases := []struct {
+		path   string
+		role   string
+		body   string
+		status int
+	}{
+		{path: "/users", role: "admin", status: StatusOK, body: "users"},
+		{path: "/users", role: "guest", status: StatusForbidden},
+		{path: "/public", status: StatusOK, body: "<nil>"},
+		{path: "/admin/stats", role: "admin", status: StatusOK, body: "stats"},
+		{path: "/admin/stats", status: StatusForbidden},
+		{path: "/admin/v1/stats", status: StatusForbidden},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest(MethodGet, tc.path, nil)
+		req.Header.Set("X-Role", tc.role)
+		resp, err := app.Test(req)
+		require.NoError(t, err, "app.Test(req)")
+		require.Equal(t, tc.status, resp.StatusCode, tc.path)
+		if tc.body != "" {
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, tc.body, string(body), tc.path)
+		}
+	}
+
+	// the metadata of a route isn't shared with the group
+	require.Equal(t, Map{"auth": "admin"}, admin.(*Group).meta)
+}
+
+// go test -run Test_App_RemoveRoute
+func Test_App_RemoveRoute(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/users", func(c Ctx) error {
+		return c.SendString("users")
+	}).Name("users")
+	app.Post("/users", func(c Ctx) error {
+		return c.SendStatus(StatusCreated)
+	})
+	app.Get("/tenant/:id", func(c Ctx) error {
+		return c.SendString(c.Params("id"))
+	})
+
+	status := func(method, path string) int {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest(method, path, nil))
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+	require.Equal(t, StatusOK, status(MethodGet, "/tenant/1"))
+	handlers := app.HandlersCount()
+
+	app.RemoveRoute("/tenant/:id")
+	require.Equal(t, StatusNotFound, status(MethodGet, "/tenant/1"))
+	require.Equal(t, handlers-1, app.HandlersCount())
+
+	// Only the routes of the passed methods are removed
+	app.RemoveRoute("/users", MethodPost)
+	require.Equal(t, StatusOK, status(MethodGet, "/users"))
+	require.Equal(t, StatusMethodNotAllowed, status(MethodPost, "/users"))
+
+	app.RemoveRouteByName("users")
+	require.Equal(t, StatusNotFound, status(MethodGet, "/users"))
+	require.Empty(t, app.GetRoutes(true))
+
+	// Removed routes can be added again
+	app.Get("/tenant/:id", func(c Ctx) error {
+		return c.SendString("v2 " + c.Params("id"))
+	})
+	app.RebuildTree()
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/tenant/2", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "v2 2", string(body))
+}
+
+// go test -run Test_App_RemoveRoute_Use
+func Test_App_RemoveRoute_Use(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Use("/api", func(c Ctx) error {
+		c.Set("X-API", "1")
+		return c.Next()
+	}, func(c Ctx) error {
+		return c.Next()
+	})
+	app.Post("/api/users", testEmptyHandler)
+	require.Equal(t, uint32(3), app.HandlersCount())
+
+	// The handlers of a Use route are counted once, whichever method it is removed for
+	app.RemoveRoute("/api", MethodPost)
+	require.Equal(t, uint32(1), app.HandlersCount())
+	resp, err := app.Test(httptest.NewRequest(MethodPost, "/api/users", nil))
+	require.NoError(t, err)
+	require.Empty(t, resp.Header.Get("X-API"))
+
+	app.RemoveRoute("/api")
+	require.Equal(t, uint32(1), app.HandlersCount())
+}
+
+// go test -run Test_App_RebuildTree_Concurrent -race
+func Test_App_RebuildTree_Concurrent(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Use(func(c Ctx) error {
+		return c.Next()
+	})
+	app.Get("/static", func(c Ctx) error {
+		return c.SendStatus(StatusOK)
+	})
+	handler := app.Handler()
+	app.RebuildTree()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			app.Get("/dynamic", func(c Ctx) error {
+				return c.SendStatus(StatusOK)
+			})
+			app.RebuildTree()
+			app.RemoveRoute("/dynamic")
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		fctx := &fasthttp.RequestCtx{}
+		fctx.Request.Header.SetMethod(MethodGet)
+		fctx.Request.SetRequestURI("/static")
+		handler(fctx)
+		require.Equal(t, StatusOK, fctx.Response.StatusCode())
+	}
+}
+
+// go test -run Test_Route_Segments
+func Test_Route_Segments(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/users/:id<int;min(1)>/files/*", func(_ Ctx) error { return nil })
+
+	var segments []PathSegment
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == MethodGet {
+			segments = route.Segments()
+		}
+	}
+	require.Len(t, segments, 4)
+	require.Equal(t, PathSegment{Const: "/users/"}, segments[0])
+	require.Equal(t, "id", segments[1].ParamName)
+	require.True(t, segments[1].IsParam)
+	require.False(t, segments[1].IsOptional)
+	require.Len(t, segments[1].Constraints, 2)
+	require.Equal(t, ConstraintInt, segments[1].Constraints[0].Name)
+	require.Equal(t, []string{"1"}, segments[1].Constraints[1].Data)
+	require.Equal(t, PathSegment{Const: "/files/"}, segments[2])
+	require.Equal(t, PathSegment{ParamName: "*1", IsParam: true, IsGreedy: true, IsOptional: true}, segments[3])
+	require.Equal(t, "wildcard", segments[3].TemplateName())
+	require.Equal(t, "/users/{id}/files/{wildcard}", TemplatePath(segments))
+	require.Equal(t, "/", TemplatePath(nil))
+}
+
 //////////////////////////////////////////////
 ///////////////// BENCHMARKS /////////////////
 //////////////////////////////////////////////
@@ -0,0 +1,249 @@
+package fiber
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/utils/v2"
+)
+
+// Formats of RoutesExport
+const (
+	RoutesFormatJSON      = "json"
+	RoutesFormatMarkdown  = "markdown"
+	RoutesFormatTerraform = "terraform"
+	RoutesFormatOpenAPI   = "openapi"
+)
+
+// ErrUnknownRoutesFormat is returned by RoutesExport for unsupported formats
+var ErrUnknownRoutesFormat = errors.New("routes: unknown export format")
+
+// RouteInfo describes a route of the route table
+type RouteInfo struct {
+	Method       string   `json:"method"`               // HTTP method
+	Path         string   `json:"path"`                 // Registered route path
+	TemplatePath string   `json:"template_path"`        // Path with OpenAPI style parameters e.g. /users/{id}
+	Name         string   `json:"name,omitempty"`       // Route's name
+	Params       []string `json:"params,omitempty"`     // Param keys
+	Handlers     []string `json:"handlers"`             // Function names of the route handlers
+	Middleware   []string `json:"middleware,omitempty"` // Function names of the matching Use handlers in order
+
+	segments []PathSegment
+}
+
+// RoutesInfo returns the routes of the app, except middleware registered with Use, sorted
+// by path and method. The middleware of a route are the handlers registered with Use for
+// a prefix of the route path before the route.
+func (app *App) RoutesInfo() []RouteInfo {
+	var infos []RouteInfo
+	for _, routes := range app.stack {
+		for _, route := range routes {
+			if route.use {
+				continue
+			}
+			segments := route.Segments()
+			info := RouteInfo{
+				Method:       route.Method,
+				Path:         route.Path,
+				TemplatePath: TemplatePath(segments),
+				Name:         route.Name,
+				Params:       route.Params,
+				Handlers:     handlerNames(route.Handlers),
+				segments:     segments,
+			}
+			var values [maxParams]string
+			for _, mw := range routes {
+				if mw.pos >= route.pos {
+					break
+				}
+				if mw.use && mw.match(route.path, route.path, &values) {
+					info.Middleware = append(info.Middleware, handlerNames(mw.Handlers)...)
+				}
+			}
+			infos = append(infos, info)
+		}
+	}
+
+	sort.SliceStable(infos, func(i, j int) bool {
+		if infos[i].Path != infos[j].Path {
+			return infos[i].Path < infos[j].Path
+		}
+		return infos[i].Method < infos[j].Method
+	})
+	return infos
+}
+
+// RoutesHash returns a hash of the route table, which changes if a route, its name, its
+// handlers or its middleware change. It can be used to detect routing changes between releases.
+func (app *App) RoutesHash() string {
+	return routesHash(app.RoutesInfo())
+}
+
+// RoutesExport returns the route table in the format, which is one of RoutesFormatJSON,
+// RoutesFormatMarkdown, RoutesFormatTerraform and RoutesFormatOpenAPI. All formats
+// contain the hash of RoutesHash.
+func (app *App) RoutesExport(format string) ([]byte, error) {
+	routes := app.RoutesInfo()
+	if routes == nil {
+		routes = []RouteInfo{}
+	}
+	hash := routesHash(routes)
+
+	switch format {
+	case RoutesFormatJSON:
+		return json.MarshalIndent(struct {
+			Hash   string      `json:"hash"`
+			Routes []RouteInfo `json:"routes"`
+		}{Hash: hash, Routes: routes}, "", "  ")
+	case RoutesFormatMarkdown:
+		return routesMarkdown(routes, hash), nil
+	case RoutesFormatTerraform:
+		return routesTerraform(routes, hash), nil
+	case RoutesFormatOpenAPI:
+		return routesOpenAPI(routes, hash)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownRoutesFormat, format)
+	}
+}
+
+// handlerNames returns the function names of the handlers
+func handlerNames(handlers []Handler) []string {
+	names := make([]string, len(handlers))
+	for i, handler := range handlers {
+		names[i] = runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+	}
+	return names
+}
+
+func routesHash(routes []RouteInfo) string {
+	data, err := json.Marshal(routes)
+	if err != nil {
+		// RouteInfo only contains strings
+		panic(fmt.Errorf("routes: failed to encode routes: %w", err))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func routesMarkdown(routes []RouteInfo, hash string) []byte {
+	var b bytes.Buffer
+	b.WriteString("| Method | Path | Name | Handlers | Middleware |\n")
+	b.WriteString("|:-------|:-----|:-----|:---------|:-----------|\n")
+	cell := func(values ...string) string {
+		if len(values) == 0 || (len(values) == 1 && values[0] == "") {
+			return "-"
+		}
+		quoted := make([]string, len(values))
+		for i, v := range values {
+			quoted[i] = "`" + strings.ReplaceAll(v, "|", "\\|") + "`"
+		}
+		return strings.Join(quoted, "<br>")
+	}
+	for _, r := range routes {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", r.Method, cell(r.Path), cell(r.Name), cell(r.Handlers...), cell(r.Middleware...)) //nolint:errcheck // Writing to a buffer doesn't fail
+	}
+	b.WriteString("\nHash: `" + hash + "`\n")
+	return b.Bytes()
+}
+
+// routesTerraform returns the routes as Terraform variables, keyed by the route keys of
+// API gateways e.g. "GET /users/{id}" for use with for_each
+func routesTerraform(routes []RouteInfo, hash string) []byte {
+	quote := func(s string) string {
+		s = strconv.Quote(s)
+		s = strings.ReplaceAll(s, "${", "$${")
+		return strings.ReplaceAll(s, "%{", "%%{")
+	}
+	list := func(values []string) string {
+		quoted := make([]string, len(values))
+		for i, v := range values {
+			quoted[i] = quote(v)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	}
+
+	var b bytes.Buffer
+	b.WriteString("fiber_routes_hash = " + quote(hash) + "\n\n")
+	b.WriteString("fiber_routes = {\n")
+	keys := make(map[string]struct{}, len(routes))
+	for _, r := range routes {
+		path := templatePath(r.segments, true)
+		key := r.Method + " " + path
+		// Routes which only differ in constraints share the route key
+		if _, ok := keys[key]; ok {
+			continue
+		}
+		keys[key] = struct{}{}
+
+		b.WriteString("  " + quote(key) + " = {\n")
+		b.WriteString("    method     = " + quote(r.Method) + "\n")
+		b.WriteString("    path       = " + quote(path) + "\n")
+		b.WriteString("    name       = " + quote(r.Name) + "\n")
+		b.WriteString("    handlers   = " + list(r.Handlers) + "\n")
+		b.WriteString("    middleware = " + list(r.Middleware) + "\n")
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+// routesOpenAPI returns the paths object of an OpenAPI 3 document, the handlers and
+// middleware are stored in extensions
+func routesOpenAPI(routes []RouteInfo, hash string) ([]byte, error) {
+	type parameter struct {
+		Schema   map[string]string `json:"schema"`
+		Name     string            `json:"name"`
+		In       string            `json:"in"`
+		Required bool              `json:"required"`
+	}
+	type operation struct {
+		Responses   map[string]map[string]string `json:"responses"`
+		OperationID string                       `json:"operationId,omitempty"`
+		Parameters  []parameter                  `json:"parameters,omitempty"`
+		Handlers    []string                     `json:"x-fiber-handlers"`
+		Middleware  []string                     `json:"x-fiber-middleware,omitempty"`
+	}
+
+	paths := make(map[string]map[string]*operation)
+	for _, r := range routes {
+		op := &operation{
+			OperationID: r.Name,
+			Handlers:    r.Handlers,
+			Middleware:  r.Middleware,
+			Responses:   map[string]map[string]string{"default": {"description": "Default response"}},
+		}
+		for _, seg := range r.segments {
+			if seg.IsParam {
+				op.Parameters = append(op.Parameters, parameter{
+					Name:     seg.TemplateName(),
+					In:       "path",
+					Required: true,
+					Schema:   map[string]string{"type": "string"},
+				})
+			}
+		}
+
+		if paths[r.TemplatePath] == nil {
+			paths[r.TemplatePath] = make(map[string]*operation)
+		}
+		method := utils.ToLower(r.Method)
+		// Routes which only differ in constraints share the path
+		if _, ok := paths[r.TemplatePath][method]; !ok {
+			paths[r.TemplatePath][method] = op
+		}
+	}
+
+	return json.MarshalIndent(struct {
+		Paths map[string]map[string]*operation `json:"paths"`
+		Hash  string                           `json:"x-fiber-routes-hash"`
+	}{Paths: paths, Hash: hash}, "", "  ")
+}
@@ -0,0 +1,175 @@
+package fiber
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func exportMiddleware(c Ctx) error {
+	return c.Next()
+}
+
+func exportHandler(c Ctx) error {
+	return c.SendStatus(StatusOK)
+}
+
+func newExportApp() *App {
+	app := New()
+	app.Use(exportMiddleware)
+	app.Get("/users/:id", exportHandler).Name("user")
+	api := app.Group("/api", exportMiddleware)
+	api.Post("/files/*", exportHandler, exportMiddleware)
+	app.Get("/", exportHandler)
+	return app
+}
+
+// go test -run Test_App_RoutesInfo
+func Test_App_RoutesInfo(t *testing.T) {
+	t.Parallel()
+	app := newExportApp()
+
+	const (
+		mw      = "github.com/gofiber/fiber/v3.exportMiddleware"
+		handler = "github.com/gofiber/fiber/v3.exportHandler"
+	)
+	routes := app.RoutesInfo()
+	require.Len(t, routes, 3)
+
+	require.Equal(t, "/", routes[0].Path)
+	require.Equal(t, []string{handler}, routes[0].Handlers)
+	require.Equal(t, []string{mw}, routes[0].Middleware)
+
+	require.Equal(t, MethodPost, routes[1].Method)
+	require.Equal(t, "/api/files/*", routes[1].Path)
+	require.Equal(t, "/api/files/{wildcard}", routes[1].TemplatePath)
+	require.Equal(t, []string{"*1"}, routes[1].Params)
+	require.Equal(t, []string{mw, handler}, routes[1].Handlers)
+	// The middleware of the app and of the group
+	require.Equal(t, []string{mw, mw}, routes[1].Middleware)
+
+	require.Equal(t, "/users/:id", routes[2].Path)
+	require.Equal(t, "/users/{id}", routes[2].TemplatePath)
+	require.Equal(t, "user", routes[2].Name)
+	require.Equal(t, []string{mw}, routes[2].Middleware)
+}
+
+// go test -run Test_App_RoutesHash
+func Test_App_RoutesHash(t *testing.T) {
+	t.Parallel()
+
+	// The hash doesn't depend on the registration order
+	app1 := New()
+	app1.Get("/a", exportHandler)
+	app1.Get("/b", exportHandler)
+	app2 := New()
+	app2.Get("/b", exportHandler)
+	app2.Get("/a", exportHandler)
+	require.Len(t, app1.RoutesHash(), 64)
+	require.Equal(t, app1.RoutesHash(), app2.RoutesHash())
+
+	app2.Get("/c", exportHandler)
+	require.NotEqual(t, app1.RoutesHash(), app2.RoutesHash())
+
+	app3 := New()
+	app3.Get("/a", exportHandler)
+	app3.Get("/b", exportHandler).Name("b")
+	require.NotEqual(t, app1.RoutesHash(), app3.RoutesHash())
+}
+
+// go test -run Test_App_RoutesExport
+func Test_App_RoutesExport(t *testing.T) {
+	t.Parallel()
+	app := newExportApp()
+	hash := app.RoutesHash()
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+		data, err := app.RoutesExport(RoutesFormatJSON)
+		require.NoError(t, err)
+		var export struct {
+			Hash   string      `json:"hash"`
+			Routes []RouteInfo `json:"routes"`
+		}
+		require.NoError(t, json.Unmarshal(data, &export))
+		require.Equal(t, hash, export.Hash)
+		require.Len(t, export.Routes, 3)
+		require.Equal(t, "/users/{id}", export.Routes[2].TemplatePath)
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		t.Parallel()
+		data, err := app.RoutesExport(RoutesFormatMarkdown)
+		require.NoError(t, err)
+		lines := strings.Split(string(data), "\n")
+		require.Equal(t, "| Method | Path | Name | Handlers | Middleware |", lines[0])
+		require.Equal(t, "| GET | `/users/:id` | `user` | `github.com/gofiber/fiber/v3.exportHandler` | `github.com/gofiber/fiber/v3.exportMiddleware` |", lines[4])
+		require.Equal(t, "| GET | `/` | - | `github.com/gofiber/fiber/v3.exportHandler` | `github.com/gofiber/fiber/v3.exportMiddleware` |", lines[2])
+		require.Contains(t, string(data), "Hash: `"+hash+"`")
+	})
+
+	t.Run("terraform", func(t *testing.T) {
+		t.Parallel()
+		data, err := app.RoutesExport(RoutesFormatTerraform)
+		require.NoError(t, err)
+		require.Contains(t, string(data), `fiber_routes_hash = "`+hash+`"`)
+		require.Contains(t, string(data), `  "POST /api/files/{wildcard+}" = {
+    method     = "POST"
+    path       = "/api/files/{wildcard+}"
+    name       = ""
+    handlers   = ["github.com/gofiber/fiber/v3.exportMiddleware", "github.com/gofiber/fiber/v3.exportHandler"]
+    middleware = ["github.com/gofiber/fiber/v3.exportMiddleware", "github.com/gofiber/fiber/v3.exportMiddleware"]
+  }`)
+	})
+
+	t.Run("openapi", func(t *testing.T) {
+		t.Parallel()
+		data, err := app.RoutesExport(RoutesFormatOpenAPI)
+		require.NoError(t, err)
+		var export struct {
+			Paths map[string]map[string]struct {
+				OperationID string `json:"operationId"`
+				Parameters  []struct {
+					Name string `json:"name"`
+					In   string `json:"in"`
+				} `json:"parameters"`
+				Handlers []string `json:"x-fiber-handlers"`
+			} `json:"paths"`
+			Hash string `json:"x-fiber-routes-hash"`
+		}
+		require.NoError(t, json.Unmarshal(data, &export))
+		require.Equal(t, hash, export.Hash)
+		require.Len(t, export.Paths, 3)
+		op := export.Paths["/users/{id}"]["get"]
+		require.Equal(t, "user", op.OperationID)
+		require.Len(t, op.Parameters, 1)
+		require.Equal(t, "id", op.Parameters[0].Name)
+		require.Equal(t, "path", op.Parameters[0].In)
+		require.Len(t, export.Paths["/api/files/{wildcard}"]["post"].Handlers, 2)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+		_, err := app.RoutesExport("yaml")
+		require.ErrorIs(t, err, ErrUnknownRoutesFormat)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		data, err := New().RoutesExport(RoutesFormatJSON)
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"routes": []`)
+	})
+}
+
+// go test -v -run=^$ -bench=Benchmark_App_RoutesHash -benchmem -count=4
+func Benchmark_App_RoutesHash(b *testing.B) {
+	app := newExportApp()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = app.RoutesHash()
+	}
+}
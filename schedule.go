@@ -0,0 +1,225 @@
+package fiber
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schedule returns the next time a scheduled task runs after t, or the zero time if it
+// doesn't run anymore
+type schedule interface {
+	next(t time.Time) time.Time
+}
+
+// everySchedule runs a task in a fixed interval
+type everySchedule struct {
+	interval time.Duration
+}
+
+func (s everySchedule) next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
+// cronSchedule runs a task at the minutes matching a cron expression, the fields are
+// bit sets of the matching values
+type cronSchedule struct {
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	anyDay  bool // the day of the month is "*"
+	anyWeek bool // the day of the week is "*"
+}
+
+// cronField is the range of a field of a cron expression
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = [5]cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+// cronDescriptors are the predefined schedules
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Schedule runs the task in a background task at the times of the spec, until the app
+// shuts down. The spec is a cron expression with the fields minute, hour, day of month,
+// month and day of week, e.g. "*/5 * * * *". The fields support "*", values, ranges
+// "1-5", steps "*/15" and lists "1,15". The descriptors "@yearly", "@monthly", "@weekly",
+// "@daily", "@hourly" and "@every <duration>" are supported as well. A run of the task
+// is skipped while its previous run hasn't returned.
+//
+//	err := app.Schedule("0 3 * * *", func(ctx context.Context) {
+//	    cleanup(ctx)
+//	})
+func (app *App) Schedule(spec string, task func(ctx context.Context)) error {
+	s, err := parseSchedule(spec)
+	if err != nil {
+		return err
+	}
+
+	app.Go(func(ctx context.Context) {
+		for {
+			next := s.next(time.Now())
+			if next.IsZero() {
+				return
+			}
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			runTask(func() {
+				task(ctx)
+			})
+		}
+	})
+	return nil
+}
+
+// parseSchedule parses the spec of Schedule
+func parseSchedule(spec string) (schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if interval, ok := strings.CutPrefix(spec, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("schedule: invalid interval %q", interval)
+		}
+		return everySchedule{interval: d}, nil
+	}
+	if expr, ok := cronDescriptors[spec]; ok {
+		spec = expr
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("schedule: expected %d fields, got %d in %q", len(cronFields), len(fields), spec)
+	}
+	var bits [5]uint64
+	for i, field := range fields {
+		b, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = b
+	}
+
+	// Sunday is 0 and 7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return &cronSchedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		anyDay:  fields[2] == "*",
+		anyWeek: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField returns the bit set of the values of the field
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		expr, stepExpr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepExpr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("schedule: invalid step %q of %s", stepExpr, f.name)
+			}
+		}
+
+		low, high := f.min, f.max
+		if expr != "*" {
+			lowExpr, highExpr, isRange := strings.Cut(expr, "-")
+			var err error
+			if low, err = parseCronValue(lowExpr, f); err != nil {
+				return 0, err
+			}
+			high = low
+			if isRange {
+				if high, err = parseCronValue(highExpr, f); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				high = f.max
+			}
+			if low > high {
+				return 0, fmt.Errorf("schedule: invalid range %q of %s", expr, f.name)
+			}
+		}
+		for v := low; v <= high; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(s string, f cronField) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("schedule: invalid value %q of %s", s, f.name)
+	}
+	return v, nil
+}
+
+// cronSearchLimit is the number of years searched for the next time, specs like
+// "0 0 30 2 *" never match
+const cronSearchLimit = 5
+
+// next returns the first matching minute after t
+func (s *cronSchedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.Year() + cronSearchLimit
+
+	for t.Year() <= limit {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// matchDay reports whether the day matches. Like cron, a day matches either field if
+// both the day of the month and the day of the week are restricted.
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.anyDay || s.anyWeek {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,97 @@
+package fiber
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_App_Schedule
+func Test_App_Schedule(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var runs atomic.Int32
+	require.NoError(t, app.Schedule("@every 5ms", func(_ context.Context) {
+		if runs.Add(1) == 1 {
+			panic("first run")
+		}
+	}))
+	require.Eventually(t, func() bool {
+		return runs.Load() >= 3
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, app.Shutdown())
+	stopped := runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	require.Equal(t, stopped, runs.Load())
+
+	require.ErrorContains(t, app.Schedule("* * *", func(_ context.Context) {}), "expected 5 fields")
+}
+
+// go test -run Test_Schedule_Parse
+func Test_Schedule_Parse(t *testing.T) {
+	t.Parallel()
+
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every",
+		"@every 1x",
+		"@every -1s",
+	} {
+		_, err := parseSchedule(spec)
+		require.Error(t, err, spec)
+	}
+
+	s, err := parseSchedule("@every 1m30s")
+	require.NoError(t, err)
+	require.Equal(t, everySchedule{interval: 90 * time.Second}, s)
+}
+
+// go test -run Test_Schedule_Next
+func Test_Schedule_Next(t *testing.T) {
+	t.Parallel()
+	// Wednesday
+	now := time.Date(2024, time.January, 31, 10, 17, 42, 0, time.UTC)
+
+	testCases := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{spec: "* * * * *", expected: time.Date(2024, time.January, 31, 10, 18, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", expected: time.Date(2024, time.January, 31, 10, 30, 0, 0, time.UTC)},
+		{spec: "5,20 * * * *", expected: time.Date(2024, time.January, 31, 10, 20, 0, 0, time.UTC)},
+		{spec: "30 9-11 * * *", expected: time.Date(2024, time.January, 31, 10, 30, 0, 0, time.UTC)},
+		{spec: "10/20 * * * *", expected: time.Date(2024, time.January, 31, 10, 30, 0, 0, time.UTC)},
+		{spec: "0 3 * * *", expected: time.Date(2024, time.February, 1, 3, 0, 0, 0, time.UTC)},
+		{spec: "@hourly", expected: time.Date(2024, time.January, 31, 11, 0, 0, 0, time.UTC)},
+		{spec: "@daily", expected: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "@weekly", expected: time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 * * 7", expected: time.Date(2024, time.February, 4, 0, 0, 0, 0, time.UTC)},
+		{spec: "@monthly", expected: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "@yearly", expected: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 29 2 *", expected: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 31 * *", expected: time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)},
+		// Either the day of the month or the day of the week matches
+		{spec: "0 0 15 * 5", expected: time.Date(2024, time.February, 2, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 30 2 *", expected: time.Time{}},
+	}
+
+	for _, tc := range testCases {
+		s, err := parseSchedule(tc.spec)
+		require.NoError(t, err, tc.spec)
+		require.Equal(t, tc.expected, s.next(now), tc.spec)
+	}
+}
@@ -0,0 +1,201 @@
+package fiber
+
+import (
+	"bufio"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sseLineBreaks removes line breaks, which would end the field of an event
+var sseLineBreaks = strings.NewReplacer("\r", "", "\n", "")
+
+// ErrEventStreamClosed is returned by the methods of an EventStream after the stream was closed.
+var ErrEventStreamClosed = errors.New("sse: event stream is closed")
+
+// SSEConfig is a struct to customize the event stream of Ctx.SSE.
+type SSEConfig struct {
+	// KeepAlive is the interval of the keep-alive comments, which keep proxies from
+	// closing idle streams and detect disconnected clients. Set to a negative value
+	// to disable the keep-alive comments.
+	//
+	// Optional. Default: 15 * time.Second
+	KeepAlive time.Duration
+
+	// Retry is sent to the client as the time to wait before it reconnects.
+	//
+	// Optional. Default: 0
+	Retry time.Duration
+}
+
+// SSEEvent is a server-sent event.
+type SSEEvent struct {
+	// Data is the payload of the event. Strings and byte slices are sent as they are,
+	// other values are encoded with the JSONEncoder of the app.
+	// Data with line breaks is sent as multiple data lines.
+	Data any
+	// ID sets the last event ID of the client, which it sends with the Last-Event-ID
+	// header when it reconnects.
+	ID string
+	// Event is the type of the event, the client dispatches "message" events if it is empty.
+	Event string
+	// Retry sets the time the client waits before it reconnects.
+	Retry time.Duration
+}
+
+// EventStream writes server-sent events to the response, it is created by Ctx.SSE.
+// Its methods are safe for concurrent use.
+type EventStream struct {
+	app         *App
+	w           *bufio.Writer
+	done        chan struct{}
+	lastEventID string
+	err         error
+	mu          sync.Mutex
+}
+
+// defaultSSEKeepAlive is the default interval of the keep-alive comments
+const defaultSSEKeepAlive = 15 * time.Second
+
+// sseConfigDefault is a function to set default values of SSEConfig.
+func sseConfigDefault(config ...SSEConfig) SSEConfig {
+	if len(config) < 1 {
+		return SSEConfig{KeepAlive: defaultSSEKeepAlive}
+	}
+	cfg := config[0]
+	if cfg.KeepAlive == 0 {
+		cfg.KeepAlive = defaultSSEKeepAlive
+	}
+	return cfg
+}
+
+// LastEventID returns the Last-Event-ID header of the request, which contains the ID
+// of the last event the client received before it reconnected.
+func (s *EventStream) LastEventID() string {
+	return s.lastEventID
+}
+
+// Done returns a channel that's closed when the client disconnected.
+func (s *EventStream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Send writes the event and flushes it to the client. It returns an error if the
+// client disconnected.
+func (s *EventStream) Send(event SSEEvent) error {
+	var data []byte
+	switch d := event.Data.(type) {
+	case nil:
+	case string:
+		data = s.app.getBytes(d)
+	case []byte:
+		data = d
+	default:
+		var err error
+		if data, err = s.app.config.JSONEncoder(d); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if event.ID != "" {
+		_, _ = s.w.WriteString("id: " + sseLineBreaks.Replace(event.ID) + "\n") //nolint:errcheck // Write errors are reported by Flush
+	}
+	if event.Event != "" {
+		_, _ = s.w.WriteString("event: " + sseLineBreaks.Replace(event.Event) + "\n") //nolint:errcheck // Write errors are reported by Flush
+	}
+	if event.Retry > 0 {
+		s.writeRetry(event.Retry)
+	}
+	text := s.app.getString(data)
+	for {
+		line, rest, found := strings.Cut(text, "\n")
+		_, _ = s.w.WriteString("data: " + strings.TrimSuffix(line, "\r") + "\n") //nolint:errcheck // Write errors are reported by Flush
+		if !found {
+			break
+		}
+		text = rest
+	}
+	_ = s.w.WriteByte('\n') //nolint:errcheck // Write errors are reported by Flush
+	return s.flush()
+}
+
+// Comment writes a comment, which is ignored by the client, and flushes it.
+func (s *EventStream) Comment(text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	_, _ = s.w.WriteString(": " + sseLineBreaks.Replace(text) + "\n\n") //nolint:errcheck // Write errors are reported by Flush
+	return s.flush()
+}
+
+// writeRetry writes the retry field, s.mu must be locked
+func (s *EventStream) writeRetry(retry time.Duration) {
+	_, _ = s.w.WriteString("retry: " + strconv.FormatInt(retry.Milliseconds(), 10) + "\n") //nolint:errcheck // Write errors are reported by Flush
+}
+
+// flush flushes the written events, the stream is closed if the client
+// disconnected, s.mu must be locked
+func (s *EventStream) flush() error {
+	if err := s.w.Flush(); err != nil {
+		s.closeWithError(err)
+		return err
+	}
+	return nil
+}
+
+// closeWithError closes the stream, s.mu must be locked
+func (s *EventStream) closeWithError(err error) {
+	if s.err == nil {
+		s.err = err
+		close(s.done)
+	}
+}
+
+// run streams the events of fn and writes the keep-alive comments until fn returns
+func (s *EventStream) run(cfg SSEConfig, fn func(stream *EventStream)) {
+	if cfg.Retry > 0 {
+		s.mu.Lock()
+		s.writeRetry(cfg.Retry)
+		_ = s.w.WriteByte('\n') //nolint:errcheck // Write errors are reported by Flush
+		_ = s.flush()           //nolint:errcheck // A failed write closes the stream
+		s.mu.Unlock()
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	if cfg.KeepAlive > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(cfg.KeepAlive)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-stop:
+					return
+				case <-s.done:
+					return
+				case <-ticker.C:
+					_ = s.Comment("keep-alive") //nolint:errcheck // A failed write closes the stream
+				}
+			}
+		}()
+	}
+
+	fn(s)
+	close(stop)
+	wg.Wait()
+
+	s.mu.Lock()
+	s.closeWithError(ErrEventStreamClosed)
+	s.mu.Unlock()
+}
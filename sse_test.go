@@ -0,0 +1,92 @@
+package fiber
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_Ctx_SSE
+func Test_Ctx_SSE(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		return c.SSE(func(stream *EventStream) {
+			assert.NoError(t, stream.Send(SSEEvent{ID: "2", Event: "greeting", Data: "hello\nworld"}))
+			assert.NoError(t, stream.Send(SSEEvent{Data: Map{"after": stream.LastEventID()}, Retry: time.Second}))
+			assert.NoError(t, stream.Send(SSEEvent{ID: "3\n", Data: []byte("bytes")}))
+			assert.NoError(t, stream.Comment("done"))
+		}, SSEConfig{Retry: 5 * time.Second})
+	})
+
+	req := httptest.NewRequest(MethodGet, "/", nil)
+	req.Header.Set(HeaderLastEventID, "1")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get(HeaderContentType))
+	require.Equal(t, "no-cache", resp.Header.Get(HeaderCacheControl))
+	require.Equal(t, "no", resp.Header.Get("X-Accel-Buffering"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "retry: 5000\n\n"+
+		"id: 2\nevent: greeting\ndata: hello\ndata: world\n\n"+
+		"retry: 1000\ndata: {\"after\":\"1\"}\n\n"+
+		"id: 3\ndata: bytes\n\n"+
+		": done\n\n", string(body))
+}
+
+// go test -run Test_Ctx_SSE_KeepAlive
+func Test_Ctx_SSE_KeepAlive(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		return c.SSE(func(_ *EventStream) {
+			time.Sleep(50 * time.Millisecond)
+		}, SSEConfig{KeepAlive: 10 * time.Millisecond})
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), ": keep-alive\n\n")
+	require.Equal(t, 0, strings.Count(string(body), "data:"))
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+// go test -run Test_EventStream_Disconnect
+func Test_EventStream_Disconnect(t *testing.T) {
+	t.Parallel()
+	stream := &EventStream{
+		app:  New(),
+		w:    bufio.NewWriter(failingWriter{}),
+		done: make(chan struct{}),
+	}
+
+	err := stream.Send(SSEEvent{Data: "lost"})
+	require.EqualError(t, err, "broken pipe")
+	select {
+	case <-stream.Done():
+	default:
+		t.Fatal("the stream must be done after the client disconnected")
+	}
+	require.Equal(t, err, stream.Comment("lost"))
+
+	// Data which can't be encoded isn't written
+	stream = &EventStream{app: New(), done: make(chan struct{})}
+	require.Error(t, stream.Send(SSEEvent{Data: make(chan int)}))
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// withContext returns the context methods of the storage. If the storage doesn't
+// implement fiber.StorageWithContext, they call the methods without a context.
+func withContext(storage fiber.Storage) fiber.StorageWithContext {
+	if s, ok := storage.(fiber.StorageWithContext); ok {
+		return s
+	}
+	return contextIgnored{storage: storage}
+}
+
+// contextIgnored ignores the context of the calls to a storage without context methods
+type contextIgnored struct {
+	storage fiber.Storage
+}
+
+func (s contextIgnored) GetWithContext(_ context.Context, key string) ([]byte, error) {
+	return s.storage.Get(key)
+}
+
+func (s contextIgnored) SetWithContext(_ context.Context, key string, val []byte, exp time.Duration) error {
+	return s.storage.Set(key, val, exp)
+}
+
+func (s contextIgnored) DeleteWithContext(_ context.Context, key string) error {
+	return s.storage.Delete(key)
+}
@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// encryptionVersion is the first byte of every encrypted value, so the format can be changed later
+const encryptionVersion byte = 1
+
+// keyIDSize is the size of the key id which is stored in front of the nonce
+const keyIDSize = 4
+
+var (
+	// ErrInvalidKeyLength is returned if an encryption key isn't 16, 24 or 32 bytes long
+	ErrInvalidKeyLength = errors.New("storage: encryption key must be 16, 24, or 32 bytes")
+	// ErrUnknownKey is returned if a value was encrypted with a key which isn't configured anymore
+	ErrUnknownKey = errors.New("storage: value was encrypted with an unknown key")
+	// ErrInvalidCiphertext is returned if a value isn't encrypted or was modified
+	ErrInvalidCiphertext = errors.New("storage: encrypted value is not valid")
+)
+
+// EncryptionConfig defines the config for WithEncryption.
+type EncryptionConfig struct {
+	// Keys are base64 encoded AES keys which are 16, 24, or 32 bytes long when decoded.
+	// The first key encrypts new values, all keys decrypt existing values. To rotate the key,
+	// prepend a new key and remove the old key once all values written with it expired.
+	// You may use `encryptcookie.GenerateKey(length)` to generate a new key.
+	//
+	// Required.
+	Keys []string
+}
+
+// WithEncryption wraps the storage and encrypts all values with AES-GCM, using the key
+// of the entry as additional data, so values can't be moved to other keys.
+// Values can't be incremented while they are encrypted, so the optional interfaces of
+// fiber.StorageV2 except fiber.StorageWithContext are not available.
+// It panics if no key is configured or a key is invalid.
+//
+//	store := storage.WithEncryption(redis.New(), storage.EncryptionConfig{
+//		Keys: []string{os.Getenv("STORAGE_KEY")},
+//	})
+func WithEncryption(storage fiber.Storage, config EncryptionConfig) fiber.Storage {
+	if len(config.Keys) == 0 {
+		panic("storage: encryption requires at least one key")
+	}
+
+	s := &encryptedStorage{storage: storage, ctxStorage: withContext(storage), ciphers: make(map[[keyIDSize]byte]cipher.AEAD, len(config.Keys))}
+	for i, key := range config.Keys {
+		id, aead, err := newAEAD(key)
+		if err != nil {
+			panic(err)
+		}
+		if i == 0 {
+			s.currentID, s.current = id, aead
+		}
+		s.ciphers[id] = aead
+	}
+	return s
+}
+
+// newAEAD decodes the key and returns its id and cipher
+func newAEAD(key string) ([keyIDSize]byte, cipher.AEAD, error) {
+	var id [keyIDSize]byte
+
+	keyDecoded, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return id, nil, fmt.Errorf("storage: failed to base64-decode key: %w", err)
+	}
+	keyLen := len(keyDecoded)
+	if keyLen != 16 && keyLen != 24 && keyLen != 32 {
+		return id, nil, ErrInvalidKeyLength
+	}
+
+	block, err := aes.NewCipher(keyDecoded)
+	if err != nil {
+		return id, nil, fmt.Errorf("storage: failed to create AES cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return id, nil, fmt.Errorf("storage: failed to create GCM mode: %w", err)
+	}
+
+	// The id identifies the key without revealing it
+	sum := sha256.Sum256(keyDecoded)
+	copy(id[:], sum[:keyIDSize])
+	return id, aead, nil
+}
+
+type encryptedStorage struct {
+	storage    fiber.Storage
+	ctxStorage fiber.StorageWithContext
+	current    cipher.AEAD
+	ciphers    map[[keyIDSize]byte]cipher.AEAD
+	currentID  [keyIDSize]byte
+}
+
+// encrypt returns version | key id | nonce | ciphertext
+func (s *encryptedStorage) encrypt(key string, val []byte) ([]byte, error) {
+	nonceSize := s.current.NonceSize()
+	headerSize := 1 + keyIDSize + nonceSize
+	out := make([]byte, headerSize, headerSize+len(val)+s.current.Overhead())
+	out[0] = encryptionVersion
+	copy(out[1:], s.currentID[:])
+
+	nonce := out[1+keyIDSize : headerSize]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("storage: failed to read nonce: %w", err)
+	}
+	return s.current.Seal(out, nonce, val, []byte(key)), nil
+}
+
+func (s *encryptedStorage) decrypt(key string, enc []byte) ([]byte, error) {
+	if len(enc) < 1+keyIDSize || enc[0] != encryptionVersion {
+		return nil, ErrInvalidCiphertext
+	}
+
+	var id [keyIDSize]byte
+	copy(id[:], enc[1:1+keyIDSize])
+	aead, ok := s.ciphers[id]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	enc = enc[1+keyIDSize:]
+	nonceSize := aead.NonceSize()
+	if len(enc) < nonceSize {
+		return nil, ErrInvalidCiphertext
+	}
+	val, err := aead.Open(nil, enc[:nonceSize], enc[nonceSize:], []byte(key))
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return val, nil
+}
+
+// Get value by key and decrypt it
+func (s *encryptedStorage) Get(key string) ([]byte, error) {
+	enc, err := s.storage.Get(key)
+	if err != nil || enc == nil {
+		return nil, err
+	}
+	return s.decrypt(key, enc)
+}
+
+// GetWithContext gets value by key with the context and decrypts it
+func (s *encryptedStorage) GetWithContext(ctx context.Context, key string) ([]byte, error) {
+	enc, err := s.ctxStorage.GetWithContext(ctx, key)
+	if err != nil || enc == nil {
+		return nil, err
+	}
+	return s.decrypt(key, enc)
+}
+
+// Set key with the encrypted value
+func (s *encryptedStorage) Set(key string, val []byte, exp time.Duration) error {
+	// Ain't Nobody Got Time For That
+	if len(key) == 0 || len(val) == 0 {
+		return nil
+	}
+	enc, err := s.encrypt(key, val)
+	if err != nil {
+		return err
+	}
+	return s.storage.Set(key, enc, exp)
+}
+
+// SetWithContext encrypts value and sets key with it with the context
+func (s *encryptedStorage) SetWithContext(ctx context.Context, key string, val []byte, exp time.Duration) error {
+	// Ain't Nobody Got Time For That
+	if len(key) == 0 || len(val) == 0 {
+		return nil
+	}
+	enc, err := s.encrypt(key, val)
+	if err != nil {
+		return err
+	}
+	return s.ctxStorage.SetWithContext(ctx, key, enc, exp)
+}
+
+// Delete key by key
+func (s *encryptedStorage) Delete(key string) error {
+	return s.storage.Delete(key)
+}
+
+// DeleteWithContext deletes key by key with the context
+func (s *encryptedStorage) DeleteWithContext(ctx context.Context, key string) error {
+	return s.ctxStorage.DeleteWithContext(ctx, key)
+}
+
+// Reset the wrapped storage
+func (s *encryptedStorage) Reset() error {
+	return s.storage.Reset()
+}
+
+// Close the wrapped storage
+func (s *encryptedStorage) Close() error {
+	return s.storage.Close()
+}
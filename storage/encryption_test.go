@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/internal/storage/memory"
+	"github.com/gofiber/fiber/v3/middleware/encryptcookie"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithEncryption(t *testing.T) {
+	t.Parallel()
+	raw := memory.New()
+	store := WithEncryption(raw, EncryptionConfig{Keys: []string{encryptcookie.GenerateKey(32)}})
+
+	require.NoError(t, store.Set("john", []byte("doe"), 0))
+
+	// The value is encrypted at rest
+	enc, err := raw.Get("john")
+	require.NoError(t, err)
+	require.NotContains(t, string(enc), "doe")
+
+	val, err := store.Get("john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("doe"), val)
+
+	ctxStore, ok := store.(fiber.StorageWithContext)
+	require.True(t, ok)
+	val, err = ctxStore.GetWithContext(context.Background(), "john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("doe"), val)
+
+	val, err = store.Get("missing")
+	require.NoError(t, err)
+	require.Nil(t, val)
+
+	// Counters can't be encrypted
+	_, ok = store.(fiber.AtomicStorage)
+	require.False(t, ok)
+}
+
+func Test_WithEncryption_Tampered(t *testing.T) {
+	t.Parallel()
+	raw := memory.New()
+	store := WithEncryption(raw, EncryptionConfig{Keys: []string{encryptcookie.GenerateKey(16)}})
+
+	require.NoError(t, store.Set("john", []byte("doe"), 0))
+	enc, err := raw.Get("john")
+	require.NoError(t, err)
+
+	// A value can't be moved to another key
+	require.NoError(t, raw.Set("jane", enc, 0))
+	_, err = store.Get("jane")
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	modified := append([]byte(nil), enc...)
+	modified[len(modified)-1] ^= 0xff
+	require.NoError(t, raw.Set("john", modified, 0))
+	_, err = store.Get("john")
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	require.NoError(t, raw.Set("john", []byte("plain"), 0))
+	_, err = store.Get("john")
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+}
+
+func Test_WithEncryption_KeyRotation(t *testing.T) {
+	t.Parallel()
+	raw := memory.New()
+	oldKey, newKey := encryptcookie.GenerateKey(32), encryptcookie.GenerateKey(32)
+
+	oldStore := WithEncryption(raw, EncryptionConfig{Keys: []string{oldKey}})
+	require.NoError(t, oldStore.Set("john", []byte("doe"), 0))
+
+	// Values of the old key can still be read after the rotation
+	rotated := WithEncryption(raw, EncryptionConfig{Keys: []string{newKey, oldKey}})
+	val, err := rotated.Get("john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("doe"), val)
+
+	// New values are encrypted with the new key
+	require.NoError(t, rotated.Set("jane", []byte("doe"), 0))
+	_, err = oldStore.Get("jane")
+	require.ErrorIs(t, err, ErrUnknownKey)
+
+	newStore := WithEncryption(raw, EncryptionConfig{Keys: []string{newKey}})
+	_, err = newStore.Get("john")
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func Test_WithEncryption_InvalidKey(t *testing.T) {
+	t.Parallel()
+	require.Panics(t, func() {
+		WithEncryption(memory.New(), EncryptionConfig{})
+	})
+	require.PanicsWithValue(t, ErrInvalidKeyLength, func() {
+		WithEncryption(memory.New(), EncryptionConfig{Keys: []string{"c2hvcnQ="}})
+	})
+	require.Panics(t, func() {
+		WithEncryption(memory.New(), EncryptionConfig{Keys: []string{"not base64"}})
+	})
+}
+
+func Benchmark_WithEncryption_Set(b *testing.B) {
+	store := WithEncryption(memory.New(), EncryptionConfig{Keys: []string{encryptcookie.GenerateKey(32)}})
+	val := []byte("doe")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	var err error
+	for i := 0; i < b.N; i++ {
+		err = store.Set("john", val, 0)
+	}
+	require.NoError(b, err)
+}
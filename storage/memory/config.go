@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"time"
+)
+
+// Policy decides which entry is evicted when the storage is full.
+type Policy int
+
+const (
+	// LRU evicts the least recently used entry
+	LRU Policy = iota
+	// LFU evicts the least frequently used entry, ties are broken by recency
+	LFU
+)
+
+// Config defines the config for storage.
+type Config struct {
+	// OnEvict is called with the key and value of every entry that is evicted
+	// because of MaxEntries or MaxBytes. It is not called for expired or deleted entries.
+	// The storage is not locked while it is called.
+	//
+	// Optional. Default is nil
+	OnEvict func(key string, val []byte)
+
+	// Time before deleting expired keys
+	//
+	// Optional. Default is 10 * time.Second
+	GCInterval time.Duration
+
+	// MaxEntries is the maximum number of entries, 0 means no limit
+	//
+	// Optional. Default is 0
+	MaxEntries int
+
+	// MaxBytes is the maximum summed size of all keys and values, 0 means no limit
+	//
+	// Optional. Default is 0
+	MaxBytes int
+
+	// Policy to choose the entry to evict
+	//
+	// Optional. Default is LRU
+	Policy Policy
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	GCInterval: 10 * time.Second,
+	Policy:     LRU,
+}
+
+// configDefault is a helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if int(cfg.GCInterval.Seconds()) <= 0 {
+		cfg.GCInterval = ConfigDefault.GCInterval
+	}
+	if cfg.MaxEntries < 0 {
+		cfg.MaxEntries = 0
+	}
+	if cfg.MaxBytes < 0 {
+		cfg.MaxBytes = 0
+	}
+	return cfg
+}
@@ -0,0 +1,327 @@
+// Package memory provides an in-memory storage which can be limited by the number
+// of entries and their size, evicting entries by the LRU or LFU policy when it is full.
+package memory
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/utils/v2"
+)
+
+// ErrValueTooLarge is returned by Set if the entry alone exceeds MaxBytes
+var ErrValueTooLarge = errors.New("memory: entry exceeds the maximum size of the storage")
+
+// Storage interface that is implemented by storage providers
+type Storage struct {
+	db         map[string]*entry
+	policy     policy
+	onEvict    func(key string, val []byte)
+	done       chan struct{}
+	gcInterval time.Duration
+	maxEntries int
+	maxBytes   int
+	bytes      int
+	policyKind Policy
+	mux        sync.Mutex
+}
+
+type entry struct {
+	elem *list.Element // position for LRU
+	key  string
+	data []byte
+	hits uint64 // number of uses for LFU
+	used uint64 // last use for LFU
+	// index in the heap for LFU
+	index int
+	// max value is 4294967295 -> Sun Feb 07 2106 06:28:15 GMT+0000
+	expiry uint32
+}
+
+// evicted is an entry which was removed because the storage was full
+type evicted struct {
+	key  string
+	data []byte
+}
+
+// New creates a new memory storage
+func New(config ...Config) *Storage {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// Create storage
+	store := &Storage{
+		db:         make(map[string]*entry),
+		policy:     newPolicy(cfg.Policy),
+		policyKind: cfg.Policy,
+		onEvict:    cfg.OnEvict,
+		gcInterval: cfg.GCInterval,
+		maxEntries: cfg.MaxEntries,
+		maxBytes:   cfg.MaxBytes,
+		done:       make(chan struct{}),
+	}
+
+	// Start garbage collector
+	utils.StartTimeStampUpdater()
+	go store.gc()
+
+	return store
+}
+
+// Get value by key
+func (s *Storage) Get(key string) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, nil
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	e := s.lookup(key)
+	if e == nil {
+		return nil, nil
+	}
+	s.policy.touch(e)
+	return e.data, nil
+}
+
+// Set key with value
+func (s *Storage) Set(key string, val []byte, exp time.Duration) error {
+	// Ain't Nobody Got Time For That
+	if len(key) == 0 || len(val) == 0 {
+		return nil
+	}
+	if s.maxBytes > 0 && len(key)+len(val) > s.maxBytes {
+		return ErrValueTooLarge
+	}
+
+	s.mux.Lock()
+	victims := s.evict(s.set(key, val, expiryOf(exp)))
+	s.mux.Unlock()
+
+	s.notify(victims)
+	return nil
+}
+
+// GetWithContext gets value by key, the context is not used by the memory storage
+func (s *Storage) GetWithContext(_ context.Context, key string) ([]byte, error) {
+	return s.Get(key)
+}
+
+// SetWithContext sets key with value, the context is not used by the memory storage
+func (s *Storage) SetWithContext(_ context.Context, key string, val []byte, exp time.Duration) error {
+	return s.Set(key, val, exp)
+}
+
+// DeleteWithContext deletes key by key, the context is not used by the memory storage
+func (s *Storage) DeleteWithContext(_ context.Context, key string) error {
+	return s.Delete(key)
+}
+
+// SetNX sets key with value only if the key does not exist
+func (s *Storage) SetNX(key string, val []byte, exp time.Duration) (bool, error) {
+	// Ain't Nobody Got Time For That
+	if len(key) == 0 || len(val) == 0 {
+		return false, nil
+	}
+	if s.maxBytes > 0 && len(key)+len(val) > s.maxBytes {
+		return false, ErrValueTooLarge
+	}
+
+	s.mux.Lock()
+	if s.lookup(key) != nil {
+		s.mux.Unlock()
+		return false, nil
+	}
+	victims := s.evict(s.set(key, val, expiryOf(exp)))
+	s.mux.Unlock()
+
+	s.notify(victims)
+	return true, nil
+}
+
+// IncrBy increments the integer value of key by delta
+func (s *Storage) IncrBy(key string, delta int64, exp time.Duration) (int64, error) {
+	if len(key) == 0 {
+		return 0, errors.New("memory: empty key")
+	}
+
+	s.mux.Lock()
+	var current int64
+	expiry := expiryOf(exp)
+	if e := s.lookup(key); e != nil {
+		var err error
+		if current, err = strconv.ParseInt(utils.UnsafeString(e.data), 10, 64); err != nil {
+			s.mux.Unlock()
+			return 0, errors.New("memory: value is not an integer")
+		}
+		expiry = e.expiry
+	}
+	current += delta
+	victims := s.evict(s.set(key, strconv.AppendInt(nil, current, 10), expiry))
+	s.mux.Unlock()
+
+	s.notify(victims)
+	return current, nil
+}
+
+// TTL returns the remaining time to live of key
+func (s *Storage) TTL(key string) (time.Duration, error) {
+	s.mux.Lock()
+	e := s.lookup(key)
+	s.mux.Unlock()
+	if e == nil {
+		return -1, nil
+	}
+	if e.expiry == 0 {
+		return 0, nil
+	}
+	return time.Duration(e.expiry-utils.Timestamp()) * time.Second, nil
+}
+
+// Scan returns all keys with the prefix
+func (s *Storage) Scan(prefix string) ([]string, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	ts := utils.Timestamp()
+	var keys []string
+	for key, e := range s.db {
+		if strings.HasPrefix(key, prefix) && (e.expiry == 0 || e.expiry > ts) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// Delete key by key
+func (s *Storage) Delete(key string) error {
+	// Ain't Nobody Got Time For That
+	if len(key) == 0 {
+		return nil
+	}
+	s.mux.Lock()
+	if e, ok := s.db[key]; ok {
+		s.remove(e)
+	}
+	s.mux.Unlock()
+	return nil
+}
+
+// Reset all keys
+func (s *Storage) Reset() error {
+	s.mux.Lock()
+	s.db = make(map[string]*entry)
+	s.policy = newPolicy(s.policyKind)
+	s.bytes = 0
+	s.mux.Unlock()
+	return nil
+}
+
+// Close the memory storage
+func (s *Storage) Close() error {
+	s.done <- struct{}{}
+	return nil
+}
+
+// Len returns the number of entries, including expired entries which were not collected yet
+func (s *Storage) Len() int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return len(s.db)
+}
+
+// Bytes returns the summed size of all keys and values
+func (s *Storage) Bytes() int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.bytes
+}
+
+// lookup returns the entry if it exists and is not expired
+func (s *Storage) lookup(key string) *entry {
+	e, ok := s.db[key]
+	if !ok || e.expiry != 0 && e.expiry <= utils.Timestamp() {
+		return nil
+	}
+	return e
+}
+
+// set stores the value and returns its entry, the caller has to hold the lock
+func (s *Storage) set(key string, val []byte, expiry uint32) *entry {
+	if e, ok := s.db[key]; ok {
+		s.bytes += len(val) - len(e.data)
+		e.data, e.expiry = val, expiry
+		s.policy.touch(e)
+		return e
+	}
+
+	e := &entry{key: key, data: val, expiry: expiry}
+	s.db[key] = e
+	s.bytes += len(key) + len(val)
+	s.policy.add(e)
+	return e
+}
+
+// remove deletes the entry, the caller has to hold the lock
+func (s *Storage) remove(e *entry) {
+	delete(s.db, e.key)
+	s.bytes -= len(e.key) + len(e.data)
+	s.policy.remove(e)
+}
+
+// evict removes entries until the limits are met, the entry which was just
+// stored is never evicted. The caller has to hold the lock.
+func (s *Storage) evict(stored *entry) []evicted {
+	var victims []evicted
+	for s.maxEntries > 0 && len(s.db) > s.maxEntries || s.maxBytes > 0 && s.bytes > s.maxBytes {
+		e := s.policy.victim(stored)
+		if e == nil {
+			break
+		}
+		s.remove(e)
+		if s.onEvict != nil {
+			victims = append(victims, evicted{key: e.key, data: e.data})
+		}
+	}
+	return victims
+}
+
+// notify calls the eviction callback without holding the lock
+func (s *Storage) notify(victims []evicted) {
+	for _, v := range victims {
+		s.onEvict(v.key, v.data)
+	}
+}
+
+// expiryOf converts the expiration duration to a timestamp, 0 means no expiration
+func expiryOf(exp time.Duration) uint32 {
+	if exp == 0 {
+		return 0
+	}
+	return uint32(exp.Seconds()) + utils.Timestamp()
+}
+
+func (s *Storage) gc() {
+	ticker := time.NewTicker(s.gcInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			ts := utils.Timestamp()
+			s.mux.Lock()
+			for _, e := range s.db {
+				if e.expiry != 0 && e.expiry <= ts {
+					s.remove(e)
+				}
+			}
+			s.mux.Unlock()
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package memory
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+var _ fiber.StorageV2 = (*Storage)(nil)
+
+func Test_Storage_Memory_Set_Get(t *testing.T) {
+	t.Parallel()
+	testStore := New()
+
+	require.NoError(t, testStore.Set("john", []byte("doe"), 0))
+
+	result, err := testStore.Get("john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("doe"), result)
+
+	result, err = testStore.Get("empty")
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
+
+func Test_Storage_Memory_Set_Expiration(t *testing.T) {
+	t.Parallel()
+	testStore := New(Config{GCInterval: 300 * time.Millisecond})
+
+	require.NoError(t, testStore.Set("john", []byte("doe"), 1*time.Second))
+	time.Sleep(1100 * time.Millisecond)
+
+	result, err := testStore.Get("john")
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
+
+func Test_Storage_Memory_MaxEntries_LRU(t *testing.T) {
+	t.Parallel()
+	var evictedKeys []string
+	testStore := New(Config{
+		MaxEntries: 2,
+		OnEvict: func(key string, _ []byte) {
+			evictedKeys = append(evictedKeys, key)
+		},
+	})
+
+	require.NoError(t, testStore.Set("a", []byte("1"), 0))
+	require.NoError(t, testStore.Set("b", []byte("2"), 0))
+
+	// Use "a", so "b" is the least recently used entry
+	_, err := testStore.Get("a")
+	require.NoError(t, err)
+
+	require.NoError(t, testStore.Set("c", []byte("3"), 0))
+	require.Equal(t, []string{"b"}, evictedKeys)
+	require.Equal(t, 2, testStore.Len())
+
+	result, err := testStore.Get("b")
+	require.NoError(t, err)
+	require.Nil(t, result)
+
+	result, err = testStore.Get("a")
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), result)
+}
+
+func Test_Storage_Memory_MaxEntries_LFU(t *testing.T) {
+	t.Parallel()
+	var evictedKeys []string
+	testStore := New(Config{
+		MaxEntries: 2,
+		Policy:     LFU,
+		OnEvict: func(key string, _ []byte) {
+			evictedKeys = append(evictedKeys, key)
+		},
+	})
+
+	require.NoError(t, testStore.Set("a", []byte("1"), 0))
+	require.NoError(t, testStore.Set("b", []byte("2"), 0))
+	for range 3 {
+		_, err := testStore.Get("a")
+		require.NoError(t, err)
+	}
+
+	// "b" is used less often than "a", the new entry itself is never evicted
+	require.NoError(t, testStore.Set("c", []byte("3"), 0))
+	require.Equal(t, []string{"b"}, evictedKeys)
+
+	// "c" is the least frequently used entry now
+	require.NoError(t, testStore.Set("d", []byte("4"), 0))
+	require.Equal(t, []string{"b", "c"}, evictedKeys)
+
+	result, err := testStore.Get("a")
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), result)
+}
+
+func Test_Storage_Memory_MaxBytes(t *testing.T) {
+	t.Parallel()
+	testStore := New(Config{MaxBytes: 10})
+
+	require.NoError(t, testStore.Set("a", []byte("1234"), 0))
+	require.NoError(t, testStore.Set("b", []byte("1234"), 0))
+	require.Equal(t, 10, testStore.Bytes())
+
+	// Replacing a value updates the size
+	require.NoError(t, testStore.Set("b", []byte("12"), 0))
+	require.Equal(t, 8, testStore.Bytes())
+
+	require.NoError(t, testStore.Set("c", []byte("123"), 0))
+	require.Equal(t, 7, testStore.Bytes())
+	require.Equal(t, 2, testStore.Len())
+
+	require.ErrorIs(t, testStore.Set("d", []byte("12345678910"), 0), ErrValueTooLarge)
+}
+
+func Test_Storage_Memory_Delete_Reset(t *testing.T) {
+	t.Parallel()
+	testStore := New(Config{MaxEntries: 10, Policy: LFU})
+
+	require.NoError(t, testStore.Set("john", []byte("doe"), 0))
+	require.NoError(t, testStore.Set("jane", []byte("doe"), 0))
+	require.NoError(t, testStore.Delete("john"))
+	require.Equal(t, 1, testStore.Len())
+	require.Equal(t, 7, testStore.Bytes())
+
+	require.NoError(t, testStore.Reset())
+	require.Equal(t, 0, testStore.Len())
+	require.Equal(t, 0, testStore.Bytes())
+
+	require.NoError(t, testStore.Set("john", []byte("doe"), 0))
+	require.Equal(t, 1, testStore.Len())
+}
+
+func Test_Storage_Memory_StorageV2(t *testing.T) {
+	t.Parallel()
+	testStore := New()
+
+	stored, err := testStore.SetNX("john", []byte("doe"), 0)
+	require.NoError(t, err)
+	require.True(t, stored)
+	stored, err = testStore.SetNX("john", []byte("smith"), 0)
+	require.NoError(t, err)
+	require.False(t, stored)
+
+	value, err := testStore.IncrBy("counter", 2, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), value)
+	value, err = testStore.IncrBy("counter", 3, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), value)
+
+	ttl, err := testStore.TTL("counter")
+	require.NoError(t, err)
+	require.Greater(t, ttl, 58*time.Second)
+	ttl, err = testStore.TTL("john")
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(0), ttl)
+	ttl, err = testStore.TTL("missing")
+	require.NoError(t, err)
+	require.Negative(t, ttl)
+
+	keys, err := testStore.Scan("jo")
+	require.NoError(t, err)
+	require.Equal(t, []string{"john"}, keys)
+}
+
+func Test_Storage_Memory_Concurrency(t *testing.T) {
+	t.Parallel()
+	testStore := New(Config{MaxEntries: 50, Policy: LFU})
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 200 {
+				key := strconv.Itoa(i*1000 + j)
+				require.NoError(t, testStore.Set(key, []byte("value"), 0))
+				_, err := testStore.Get(key)
+				require.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 50, testStore.Len())
+}
+
+func Test_Storage_Memory_Close(t *testing.T) {
+	t.Parallel()
+	testStore := New()
+	require.NoError(t, testStore.Close())
+}
+
+func Benchmark_Memory_Set_LRU(b *testing.B) {
+	testStore := New(Config{MaxEntries: 1000})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	var err error
+	for i := 0; i < b.N; i++ {
+		err = testStore.Set(strconv.Itoa(i), []byte("doe"), 0)
+	}
+	require.NoError(b, err)
+}
+
+func Benchmark_Memory_Set_LFU(b *testing.B) {
+	testStore := New(Config{MaxEntries: 1000, Policy: LFU})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	var err error
+	for i := 0; i < b.N; i++ {
+		err = testStore.Set(strconv.Itoa(i), []byte("doe"), 0)
+	}
+	require.NoError(b, err)
+}
@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"container/heap"
+	"container/list"
+)
+
+// policy keeps track of the usage of the entries to find the entry to evict
+type policy interface {
+	add(e *entry)
+	touch(e *entry)
+	remove(e *entry)
+	// victim returns the entry to evict next, other than skip
+	victim(skip *entry) *entry
+}
+
+func newPolicy(p Policy) policy {
+	if p == LFU {
+		return &lfuPolicy{}
+	}
+	return &lruPolicy{order: list.New()}
+}
+
+// lruPolicy orders the entries by their last use, the front is the most recently used
+type lruPolicy struct {
+	order *list.List
+}
+
+func (p *lruPolicy) add(e *entry) {
+	e.elem = p.order.PushFront(e)
+}
+
+func (p *lruPolicy) touch(e *entry) {
+	p.order.MoveToFront(e.elem)
+}
+
+func (p *lruPolicy) remove(e *entry) {
+	p.order.Remove(e.elem)
+	e.elem = nil
+}
+
+func (p *lruPolicy) victim(skip *entry) *entry {
+	back := p.order.Back()
+	if back != nil && back == skip.elem {
+		back = back.Prev()
+	}
+	if back == nil {
+		return nil
+	}
+	return back.Value.(*entry) //nolint:forcetypeassert,errcheck // We store nothing else in the list
+}
+
+// lfuPolicy is a min-heap of the entries ordered by their number of uses and their last use
+type lfuPolicy struct {
+	entries []*entry
+	clock   uint64
+}
+
+func (p *lfuPolicy) add(e *entry) {
+	p.clock++
+	e.hits, e.used = 1, p.clock
+	heap.Push(p, e)
+}
+
+func (p *lfuPolicy) touch(e *entry) {
+	p.clock++
+	e.hits++
+	e.used = p.clock
+	heap.Fix(p, e.index)
+}
+
+func (p *lfuPolicy) remove(e *entry) {
+	heap.Remove(p, e.index)
+}
+
+func (p *lfuPolicy) victim(skip *entry) *entry {
+	if len(p.entries) == 0 {
+		return nil
+	}
+	if p.entries[0] != skip {
+		return p.entries[0]
+	}
+	// The next smallest entry is one of the children of the root
+	switch {
+	case len(p.entries) == 1:
+		return nil
+	case len(p.entries) == 2 || p.Less(1, 2):
+		return p.entries[1]
+	default:
+		return p.entries[2]
+	}
+}
+
+// Len implements heap.Interface
+func (p *lfuPolicy) Len() int {
+	return len(p.entries)
+}
+
+// Less implements heap.Interface
+func (p *lfuPolicy) Less(i, j int) bool {
+	if p.entries[i].hits != p.entries[j].hits {
+		return p.entries[i].hits < p.entries[j].hits
+	}
+	return p.entries[i].used < p.entries[j].used
+}
+
+// Swap implements heap.Interface
+func (p *lfuPolicy) Swap(i, j int) {
+	p.entries[i], p.entries[j] = p.entries[j], p.entries[i]
+	p.entries[i].index = i
+	p.entries[j].index = j
+}
+
+// Push implements heap.Interface
+func (p *lfuPolicy) Push(x any) {
+	e := x.(*entry) //nolint:forcetypeassert,errcheck // We store nothing else in the heap
+	e.index = len(p.entries)
+	p.entries = append(p.entries, e)
+}
+
+// Pop implements heap.Interface
+func (p *lfuPolicy) Pop() any {
+	n := len(p.entries)
+	e := p.entries[n-1]
+	p.entries[n-1] = nil
+	p.entries = p.entries[:n-1]
+	e.index = -1
+	return e
+}
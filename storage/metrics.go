@@ -0,0 +1,266 @@
+// Package storage provides wrappers which add functionality to any fiber.Storage.
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Op is the name of a storage operation.
+type Op string
+
+// Storage operations
+const (
+	OpGet    Op = "get"
+	OpSet    Op = "set"
+	OpDelete Op = "delete"
+	OpReset  Op = "reset"
+	OpClose  Op = "close"
+	OpSetNX  Op = "setnx"
+	OpIncrBy Op = "incrby"
+	OpTTL    Op = "ttl"
+	OpScan   Op = "scan"
+)
+
+// Metric describes a single storage operation.
+type Metric struct {
+	// Err is the error returned by the storage
+	Err error
+	// Op is the operation
+	Op Op
+	// Duration of the operation
+	Duration time.Duration
+	// Hit reports if the key was found, only set for OpGet
+	Hit bool
+}
+
+// MetricsSink receives the metrics of storage operations, e.g. to export them to Prometheus.
+// Record is called synchronously after each operation and must be safe for concurrent use.
+type MetricsSink interface {
+	Record(m Metric)
+}
+
+// MetricsSinkFunc is an adapter to use a function as MetricsSink.
+type MetricsSinkFunc func(m Metric)
+
+// Record calls f(m).
+func (f MetricsSinkFunc) Record(m Metric) {
+	f(m)
+}
+
+// WithMetrics wraps the storage and records every operation to the sink.
+// The optional interfaces of fiber.StorageV2 are kept if the storage implements all of them.
+//
+//	stats := storage.NewStats()
+//	store := storage.WithMetrics(memory.New(), stats)
+func WithMetrics(storage fiber.Storage, sink MetricsSink) fiber.Storage {
+	m := &metricsStorage{storage: storage, ctxStorage: withContext(storage), sink: sink}
+	if v2, ok := storage.(fiber.StorageV2); ok {
+		return &metricsStorageV2{metricsStorage: m, v2: v2}
+	}
+	return m
+}
+
+type metricsStorage struct {
+	storage    fiber.Storage
+	ctxStorage fiber.StorageWithContext
+	sink       MetricsSink
+}
+
+func (s *metricsStorage) record(op Op, start time.Time, err error) {
+	s.sink.Record(Metric{Op: op, Duration: time.Since(start), Err: err})
+}
+
+func (s *metricsStorage) recordGet(start time.Time, val []byte, err error) {
+	s.sink.Record(Metric{Op: OpGet, Duration: time.Since(start), Err: err, Hit: val != nil})
+}
+
+// Get records and forwards the call to the storage
+func (s *metricsStorage) Get(key string) ([]byte, error) {
+	start := time.Now()
+	val, err := s.storage.Get(key)
+	s.recordGet(start, val, err)
+	return val, err
+}
+
+// GetWithContext records and forwards the call with the context to the storage
+func (s *metricsStorage) GetWithContext(ctx context.Context, key string) ([]byte, error) {
+	start := time.Now()
+	val, err := s.ctxStorage.GetWithContext(ctx, key)
+	s.recordGet(start, val, err)
+	return val, err
+}
+
+// Set records and forwards the call to the storage
+func (s *metricsStorage) Set(key string, val []byte, exp time.Duration) error {
+	start := time.Now()
+	err := s.storage.Set(key, val, exp)
+	s.record(OpSet, start, err)
+	return err
+}
+
+// SetWithContext records and forwards the call with the context to the storage
+func (s *metricsStorage) SetWithContext(ctx context.Context, key string, val []byte, exp time.Duration) error {
+	start := time.Now()
+	err := s.ctxStorage.SetWithContext(ctx, key, val, exp)
+	s.record(OpSet, start, err)
+	return err
+}
+
+// Delete records and forwards the call to the storage
+func (s *metricsStorage) Delete(key string) error {
+	start := time.Now()
+	err := s.storage.Delete(key)
+	s.record(OpDelete, start, err)
+	return err
+}
+
+// DeleteWithContext records and forwards the call with the context to the storage
+func (s *metricsStorage) DeleteWithContext(ctx context.Context, key string) error {
+	start := time.Now()
+	err := s.ctxStorage.DeleteWithContext(ctx, key)
+	s.record(OpDelete, start, err)
+	return err
+}
+
+// Reset records and forwards the call to the storage
+func (s *metricsStorage) Reset() error {
+	start := time.Now()
+	err := s.storage.Reset()
+	s.record(OpReset, start, err)
+	return err
+}
+
+// Close records and forwards the call to the storage
+func (s *metricsStorage) Close() error {
+	start := time.Now()
+	err := s.storage.Close()
+	s.record(OpClose, start, err)
+	return err
+}
+
+// metricsStorageV2 additionally records the operations of fiber.StorageV2
+type metricsStorageV2 struct {
+	*metricsStorage
+	v2 fiber.StorageV2
+}
+
+// SetNX records and forwards the call to the storage
+func (s *metricsStorageV2) SetNX(key string, val []byte, exp time.Duration) (bool, error) {
+	start := time.Now()
+	stored, err := s.v2.SetNX(key, val, exp)
+	s.record(OpSetNX, start, err)
+	return stored, err
+}
+
+// IncrBy records and forwards the call to the storage
+func (s *metricsStorageV2) IncrBy(key string, delta int64, exp time.Duration) (int64, error) {
+	start := time.Now()
+	value, err := s.v2.IncrBy(key, delta, exp)
+	s.record(OpIncrBy, start, err)
+	return value, err
+}
+
+// TTL records and forwards the call to the storage
+func (s *metricsStorageV2) TTL(key string) (time.Duration, error) {
+	start := time.Now()
+	ttl, err := s.v2.TTL(key)
+	s.record(OpTTL, start, err)
+	return ttl, err
+}
+
+// Scan records and forwards the call to the storage
+func (s *metricsStorageV2) Scan(prefix string) ([]string, error) {
+	start := time.Now()
+	keys, err := s.v2.Scan(prefix)
+	s.record(OpScan, start, err)
+	return keys, err
+}
+
+// OpStats are the aggregated metrics of an operation.
+type OpStats struct {
+	// Count is the number of calls
+	Count uint64
+	// Errors is the number of calls which returned an error
+	Errors uint64
+	// Hits is the number of Get calls which found the key
+	Hits uint64
+	// Misses is the number of Get calls which didn't find the key
+	Misses uint64
+	// Total is the summed duration of all calls
+	Total time.Duration
+	// Max is the longest duration of a call
+	Max time.Duration
+}
+
+// Average returns the average duration of a call.
+func (o OpStats) Average() time.Duration {
+	if o.Count == 0 {
+		return 0
+	}
+	return o.Total / time.Duration(o.Count)
+}
+
+// HitRatio returns the ratio of Get calls which found the key, between 0 and 1.
+func (o OpStats) HitRatio() float64 {
+	if o.Hits+o.Misses == 0 {
+		return 0
+	}
+	return float64(o.Hits) / float64(o.Hits+o.Misses)
+}
+
+// Stats is a MetricsSink which aggregates the metrics in memory.
+type Stats struct {
+	ops map[Op]OpStats
+	mux sync.Mutex
+}
+
+// NewStats creates a new in-memory MetricsSink.
+func NewStats() *Stats {
+	return &Stats{ops: make(map[Op]OpStats)}
+}
+
+// Record adds the metric to the stats of its operation.
+func (s *Stats) Record(m Metric) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	o := s.ops[m.Op]
+	o.Count++
+	o.Total += m.Duration
+	if m.Duration > o.Max {
+		o.Max = m.Duration
+	}
+	if m.Err != nil {
+		o.Errors++
+	} else if m.Op == OpGet {
+		if m.Hit {
+			o.Hits++
+		} else {
+			o.Misses++
+		}
+	}
+	s.ops[m.Op] = o
+}
+
+// Snapshot returns a copy of the stats of all recorded operations.
+func (s *Stats) Snapshot() map[Op]OpStats {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	snapshot := make(map[Op]OpStats, len(s.ops))
+	for op, o := range s.ops {
+		snapshot[op] = o
+	}
+	return snapshot
+}
+
+// Reset clears all stats.
+func (s *Stats) Reset() {
+	s.mux.Lock()
+	s.ops = make(map[Op]OpStats)
+	s.mux.Unlock()
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/internal/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+// basicStorage hides the optional interfaces of the wrapped storage
+type basicStorage struct {
+	fiber.Storage
+}
+
+type failingStorage struct {
+	basicStorage
+}
+
+var errFailing = errors.New("failing storage")
+
+func (*failingStorage) Set(string, []byte, time.Duration) error {
+	return errFailing
+}
+
+func Test_WithMetrics(t *testing.T) {
+	t.Parallel()
+	stats := NewStats()
+	store := WithMetrics(memory.New(), stats)
+
+	require.NoError(t, store.Set("john", []byte("doe"), 0))
+	_, err := store.Get("john")
+	require.NoError(t, err)
+	_, err = store.Get("jane")
+	require.NoError(t, err)
+	_, err = store.Get("john")
+	require.NoError(t, err)
+	require.NoError(t, store.Delete("john"))
+
+	snapshot := stats.Snapshot()
+	require.Equal(t, uint64(1), snapshot[OpSet].Count)
+	require.Equal(t, uint64(3), snapshot[OpGet].Count)
+	require.Equal(t, uint64(2), snapshot[OpGet].Hits)
+	require.Equal(t, uint64(1), snapshot[OpGet].Misses)
+	require.InDelta(t, 2.0/3.0, snapshot[OpGet].HitRatio(), 0.001)
+	require.Equal(t, uint64(1), snapshot[OpDelete].Count)
+	require.LessOrEqual(t, snapshot[OpGet].Average(), snapshot[OpGet].Max)
+
+	stats.Reset()
+	require.Empty(t, stats.Snapshot())
+}
+
+func Test_WithMetrics_Errors(t *testing.T) {
+	t.Parallel()
+	stats := NewStats()
+	store := WithMetrics(&failingStorage{basicStorage{memory.New()}}, stats)
+
+	require.ErrorIs(t, store.Set("john", []byte("doe"), 0), errFailing)
+	require.Equal(t, uint64(1), stats.Snapshot()[OpSet].Errors)
+}
+
+func Test_WithMetrics_StorageV2(t *testing.T) {
+	t.Parallel()
+	var ops []Op
+	store := WithMetrics(memory.New(), MetricsSinkFunc(func(m Metric) {
+		ops = append(ops, m.Op)
+	}))
+
+	v2, ok := store.(fiber.StorageV2)
+	require.True(t, ok)
+
+	_, err := v2.SetNX("john", []byte("doe"), 0)
+	require.NoError(t, err)
+	_, err = v2.IncrBy("counter", 1, 0)
+	require.NoError(t, err)
+	_, err = v2.TTL("john")
+	require.NoError(t, err)
+	_, err = v2.Scan("j")
+	require.NoError(t, err)
+	_, err = v2.GetWithContext(context.Background(), "john")
+	require.NoError(t, err)
+	require.NoError(t, v2.Reset())
+	require.NoError(t, v2.Close())
+
+	require.Equal(t, []Op{OpSetNX, OpIncrBy, OpTTL, OpScan, OpGet, OpReset, OpClose}, ops)
+}
+
+func Test_WithMetrics_Storage(t *testing.T) {
+	t.Parallel()
+	stats := NewStats()
+	store := WithMetrics(basicStorage{memory.New()}, stats)
+
+	// The optional interfaces are not available if the storage doesn't implement them
+	_, ok := store.(fiber.AtomicStorage)
+	require.False(t, ok)
+
+	// The context is ignored if the storage doesn't support it
+	ctxStore, ok := store.(fiber.StorageWithContext)
+	require.True(t, ok)
+	require.NoError(t, ctxStore.SetWithContext(context.Background(), "john", []byte("doe"), 0))
+	val, err := ctxStore.GetWithContext(context.Background(), "john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("doe"), val)
+	require.NoError(t, ctxStore.DeleteWithContext(context.Background(), "john"))
+
+	snapshot := stats.Snapshot()
+	require.Equal(t, uint64(1), snapshot[OpSet].Count)
+	require.Equal(t, uint64(1), snapshot[OpGet].Hits)
+	require.Equal(t, uint64(1), snapshot[OpDelete].Count)
+}
+
+func Benchmark_WithMetrics_Get(b *testing.B) {
+	store := WithMetrics(memory.New(), NewStats())
+	require.NoError(b, store.Set("john", []byte("doe"), 0))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	var err error
+	for i := 0; i < b.N; i++ {
+		_, err = store.Get("john")
+	}
+	require.NoError(b, err)
+}
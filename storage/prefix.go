@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// ErrScanUnsupported is returned by Reset of a prefixed storage if the wrapped storage
+// doesn't implement fiber.ScanStorage, since the keys of the prefix can't be found.
+var ErrScanUnsupported = errors.New("storage: the storage doesn't support scanning keys")
+
+// WithPrefix wraps the storage and prepends the prefix to all keys, so multiple middlewares
+// or applications can share one storage without key collisions.
+// Reset only deletes the keys with the prefix, which requires the storage to implement fiber.ScanStorage.
+// Close doesn't close the wrapped storage, since it is shared.
+// The optional interfaces of fiber.StorageV2 are kept if the storage implements all of them.
+//
+//	shared := redis.New()
+//	app.Use(csrf.New(csrf.Config{Storage: storage.WithPrefix(shared, "myapp:csrf:")}))
+//	app.Use(limiter.New(limiter.Config{Storage: storage.WithPrefix(shared, "myapp:limiter:")}))
+func WithPrefix(storage fiber.Storage, prefix string) fiber.Storage {
+	p := &prefixStorage{storage: storage, ctxStorage: withContext(storage), prefix: prefix}
+	if v2, ok := storage.(fiber.StorageV2); ok {
+		return &prefixStorageV2{prefixStorage: p, v2: v2}
+	}
+	return p
+}
+
+type prefixStorage struct {
+	storage    fiber.Storage
+	ctxStorage fiber.StorageWithContext
+	prefix     string
+}
+
+// Get value by key with the prefix
+func (s *prefixStorage) Get(key string) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, nil
+	}
+	return s.storage.Get(s.prefix + key)
+}
+
+// GetWithContext is like Get, with ctx passed on to the storage
+func (s *prefixStorage) GetWithContext(ctx context.Context, key string) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, nil
+	}
+	return s.ctxStorage.GetWithContext(ctx, s.prefix+key)
+}
+
+// Set key with the prefix with value
+func (s *prefixStorage) Set(key string, val []byte, exp time.Duration) error {
+	// Ain't Nobody Got Time For That
+	if len(key) == 0 {
+		return nil
+	}
+	return s.storage.Set(s.prefix+key, val, exp)
+}
+
+// SetWithContext is like Set, with ctx passed on to the storage
+func (s *prefixStorage) SetWithContext(ctx context.Context, key string, val []byte, exp time.Duration) error {
+	// Ain't Nobody Got Time For That
+	if len(key) == 0 {
+		return nil
+	}
+	return s.ctxStorage.SetWithContext(ctx, s.prefix+key, val, exp)
+}
+
+// Delete key by key with the prefix
+func (s *prefixStorage) Delete(key string) error {
+	// Ain't Nobody Got Time For That
+	if len(key) == 0 {
+		return nil
+	}
+	return s.storage.Delete(s.prefix + key)
+}
+
+// DeleteWithContext is like Delete, with ctx passed on to the storage
+func (s *prefixStorage) DeleteWithContext(ctx context.Context, key string) error {
+	// Ain't Nobody Got Time For That
+	if len(key) == 0 {
+		return nil
+	}
+	return s.ctxStorage.DeleteWithContext(ctx, s.prefix+key)
+}
+
+// Reset deletes all keys with the prefix
+func (s *prefixStorage) Reset() error {
+	scanner, ok := s.storage.(fiber.ScanStorage)
+	if !ok {
+		return ErrScanUnsupported
+	}
+	keys, err := scanner.Scan(s.prefix)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := s.storage.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close does nothing, the wrapped storage is shared and has to be closed by its owner
+func (*prefixStorage) Close() error {
+	return nil
+}
+
+// prefixStorageV2 additionally prefixes the keys of the fiber.StorageV2 operations
+type prefixStorageV2 struct {
+	*prefixStorage
+	v2 fiber.StorageV2
+}
+
+// SetNX sets key with the prefix with value only if the key does not exist
+func (s *prefixStorageV2) SetNX(key string, val []byte, exp time.Duration) (bool, error) {
+	// Ain't Nobody Got Time For That
+	if len(key) == 0 {
+		return false, nil
+	}
+	return s.v2.SetNX(s.prefix+key, val, exp)
+}
+
+// IncrBy increments the integer value of key with the prefix by delta
+func (s *prefixStorageV2) IncrBy(key string, delta int64, exp time.Duration) (int64, error) {
+	return s.v2.IncrBy(s.prefix+key, delta, exp)
+}
+
+// TTL returns the remaining time to live of key with the prefix
+func (s *prefixStorageV2) TTL(key string) (time.Duration, error) {
+	return s.v2.TTL(s.prefix + key)
+}
+
+// Scan returns all keys with the prefix, without the prefix of the storage
+func (s *prefixStorageV2) Scan(prefix string) ([]string, error) {
+	keys, err := s.v2.Scan(s.prefix + prefix)
+	if err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		keys[i] = strings.TrimPrefix(keys[i], s.prefix)
+	}
+	return keys, nil
+}
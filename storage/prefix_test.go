@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/internal/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithPrefix(t *testing.T) {
+	t.Parallel()
+	shared := memory.New()
+	csrf := WithPrefix(shared, "csrf:")
+	limiter := WithPrefix(shared, "limiter:")
+
+	require.NoError(t, csrf.Set("john", []byte("token"), 0))
+	require.NoError(t, limiter.Set("john", []byte("5"), 0))
+
+	val, err := csrf.Get("john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("token"), val)
+
+	val, err = shared.Get("limiter:john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("5"), val)
+
+	require.NoError(t, csrf.Delete("john"))
+	val, err = csrf.Get("john")
+	require.NoError(t, err)
+	require.Nil(t, val)
+
+	val, err = limiter.Get("john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("5"), val)
+}
+
+func Test_WithPrefix_Reset(t *testing.T) {
+	t.Parallel()
+	shared := memory.New()
+	tenantA := WithPrefix(shared, "a:")
+	tenantB := WithPrefix(shared, "b:")
+
+	require.NoError(t, tenantA.Set("john", []byte("doe"), 0))
+	require.NoError(t, tenantA.Set("jane", []byte("doe"), 0))
+	require.NoError(t, tenantB.Set("john", []byte("doe"), 0))
+
+	// Reset and Close don't touch the keys of other prefixes
+	require.NoError(t, tenantA.Reset())
+	require.NoError(t, tenantA.Close())
+
+	keys, err := shared.Scan("")
+	require.NoError(t, err)
+	require.Equal(t, []string{"b:john"}, keys)
+
+	require.ErrorIs(t, WithPrefix(basicStorage{shared}, "b:").Reset(), ErrScanUnsupported)
+}
+
+func Test_WithPrefix_StorageV2(t *testing.T) {
+	t.Parallel()
+	shared := memory.New()
+	store := WithPrefix(shared, "app:")
+
+	v2, ok := store.(fiber.StorageV2)
+	require.True(t, ok)
+
+	stored, err := v2.SetNX("john", []byte("doe"), 0)
+	require.NoError(t, err)
+	require.True(t, stored)
+
+	value, err := v2.IncrBy("counter", 2, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), value)
+
+	val, err := shared.Get("app:counter")
+	require.NoError(t, err)
+	require.Equal(t, []byte("2"), val)
+
+	ttl, err := v2.TTL("john")
+	require.NoError(t, err)
+	require.Zero(t, ttl)
+
+	keys, err := v2.Scan("jo")
+	require.NoError(t, err)
+	require.Equal(t, []string{"john"}, keys)
+
+	_, ok = WithPrefix(basicStorage{shared}, "app:").(fiber.AtomicStorage)
+	require.False(t, ok)
+}
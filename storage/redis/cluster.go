@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// slotCount is the number of hash slots of a Redis Cluster
+const slotCount = 16384
+
+// slotMap maps the hash slots of the cluster to the addresses of their masters
+type slotMap struct {
+	addrs [slotCount]string
+	mux   sync.RWMutex
+}
+
+func (m *slotMap) get(slot int) string {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	return m.addrs[slot]
+}
+
+func (m *slotMap) set(slot int, addr string) {
+	m.mux.Lock()
+	m.addrs[slot] = addr
+	m.mux.Unlock()
+}
+
+// nodes returns the unique addresses of all known masters
+func (m *slotMap) nodes() []string {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	var nodes []string
+	seen := make(map[string]struct{})
+	for _, addr := range m.addrs {
+		if addr == "" {
+			continue
+		}
+		if _, ok := seen[addr]; !ok {
+			seen[addr] = struct{}{}
+			nodes = append(nodes, addr)
+		}
+	}
+	return nodes
+}
+
+// keySlot returns the hash slot of the key, only the hash tag
+// between { and } is hashed if the key contains one
+func keySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key) % slotCount)
+}
+
+// crc16 implements the CRC16-CCITT (XMODEM) checksum used by Redis Cluster
+func crc16(key string) uint16 {
+	var crc uint16
+	for i := 0; i < len(key); i++ {
+		crc ^= uint16(key[i]) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// redirect is a MOVED or ASK reply of the cluster
+type redirect struct {
+	addr string
+	slot int
+	ask  bool
+}
+
+// parseRedirect parses error replies like "MOVED 3999 127.0.0.1:6381"
+func parseRedirect(reply any) (redirect, bool) {
+	e, ok := reply.(replyError)
+	if !ok {
+		return redirect{}, false
+	}
+	fields := strings.Fields(string(e))
+	if len(fields) != 3 || fields[0] != "MOVED" && fields[0] != "ASK" {
+		return redirect{}, false
+	}
+	slot, err := strconv.Atoi(fields[1])
+	if err != nil || slot < 0 || slot >= slotCount {
+		return redirect{}, false
+	}
+	return redirect{addr: fields[2], slot: slot, ask: fields[0] == "ASK"}, true
+}
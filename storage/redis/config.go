@@ -0,0 +1,144 @@
+package redis
+
+import (
+	"crypto/tls"
+	"runtime"
+	"time"
+)
+
+// Config defines the config for storage.
+type Config struct {
+	// TLS Config to use. When set, TLS will be negotiated.
+	//
+	// Optional. Default is nil
+	TLSConfig *tls.Config
+
+	// Host name where the DB is hosted
+	//
+	// Optional. Default is "127.0.0.1"
+	Host string
+
+	// Username for the server
+	//
+	// Optional. Default is ""
+	Username string
+
+	// Password for the server
+	//
+	// Optional. Default is ""
+	Password string
+
+	// MasterName is the name of the master to use with Redis Sentinel.
+	// When set, Addrs are the addresses of the sentinels.
+	//
+	// Optional. Default is ""
+	MasterName string
+
+	// SentinelUsername for the sentinels
+	//
+	// Optional. Default is ""
+	SentinelUsername string
+
+	// SentinelPassword for the sentinels
+	//
+	// Optional. Default is ""
+	SentinelPassword string
+
+	// Prefix is prepended to all keys, so multiple applications can share a database.
+	// Reset only deletes the keys with the prefix if it is set.
+	//
+	// Optional. Default is ""
+	Prefix string
+
+	// Addrs is a list of host:port addresses, which takes precedence over Host and Port.
+	// In cluster mode these are the seed nodes, with MasterName these are the sentinels.
+	//
+	// Optional. Default is []string{}
+	Addrs []string
+
+	// Port where the DB is listening on
+	//
+	// Optional. Default is 6379
+	Port int
+
+	// Database to be selected after connecting to the server,
+	// it is ignored in cluster mode.
+	//
+	// Optional. Default is 0
+	Database int
+
+	// PoolSize is the maximum number of idle connections kept per server.
+	//
+	// Optional. Default is 10 * runtime.GOMAXPROCS(0)
+	PoolSize int
+
+	// DialTimeout is the timeout for establishing new connections.
+	//
+	// Optional. Default is 5 * time.Second
+	DialTimeout time.Duration
+
+	// ReadTimeout is the timeout for reading the replies of commands. Commands without
+	// a context, or whose context has a later deadline, fail once it expires, so a
+	// stalled server doesn't block them forever.
+	//
+	// Optional. Default is 3 * time.Second
+	ReadTimeout time.Duration
+
+	// WriteTimeout is the timeout for writing commands.
+	//
+	// Optional. Default is 3 * time.Second
+	WriteTimeout time.Duration
+
+	// IsClusterMode enables Redis Cluster support, keys are sent to the node
+	// responsible for their hash slot.
+	//
+	// Optional. Default is false
+	IsClusterMode bool
+
+	// Reset clears any existing keys in the database
+	//
+	// Optional. Default is false
+	Reset bool
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Host:         "127.0.0.1",
+	Port:         6379,
+	PoolSize:     10 * runtime.GOMAXPROCS(0),
+	DialTimeout:  5 * time.Second,
+	ReadTimeout:  3 * time.Second,
+	WriteTimeout: 3 * time.Second,
+}
+
+// configDefault is a helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Host == "" {
+		cfg.Host = ConfigDefault.Host
+	}
+	if cfg.Port <= 0 {
+		cfg.Port = ConfigDefault.Port
+	}
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = ConfigDefault.PoolSize
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = ConfigDefault.DialTimeout
+	}
+	if cfg.ReadTimeout <= 0 {
+		cfg.ReadTimeout = ConfigDefault.ReadTimeout
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = ConfigDefault.WriteTimeout
+	}
+	return cfg
+}
@@ -0,0 +1,281 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// replyError is an error reply of the server, e.g. "ERR unknown command".
+// It doesn't break the connection, so it is returned as a reply and not as an error.
+type replyError string
+
+func (e replyError) Error() string {
+	return "redis: " + string(e)
+}
+
+var errUnexpectedReply = errors.New("redis: unexpected reply")
+
+// conn is a connection to a server speaking the RESP2 protocol.
+type conn struct {
+	netConn      net.Conn
+	rd           *bufio.Reader
+	wr           *bufio.Writer
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+}
+
+func newConn(netConn net.Conn, readTimeout, writeTimeout time.Duration) *conn {
+	return &conn{
+		netConn:      netConn,
+		rd:           bufio.NewReader(netConn),
+		wr:           bufio.NewWriter(netConn),
+		readTimeout:  readTimeout,
+		writeTimeout: writeTimeout,
+	}
+}
+
+// exec writes all commands at once and reads their replies afterwards, so
+// a pipeline only needs a single round trip. Writing and reading are limited
+// by the earlier of the deadline of the context and the timeouts of the connection.
+func (c *conn) exec(ctx context.Context, cmds [][]string) ([]any, error) {
+	deadline, hasDeadline := ctx.Deadline()
+	if ctx.Done() != nil {
+		// Unblock reads and writes when the context is canceled
+		stop := context.AfterFunc(ctx, func() {
+			_ = c.netConn.SetDeadline(time.Unix(1, 0)) //nolint:errcheck // The connection is discarded anyway
+		})
+		defer stop()
+	}
+
+	replies, err := c.roundTrip(ctx, cmds)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		// The connection deadline can expire before the context notices it
+		if hasDeadline && !time.Now().Before(deadline) {
+			return nil, context.DeadlineExceeded
+		}
+	}
+	return replies, err
+}
+
+func (c *conn) roundTrip(ctx context.Context, cmds [][]string) ([]any, error) {
+	if err := c.setDeadline(ctx, c.netConn.SetWriteDeadline, c.writeTimeout); err != nil {
+		return nil, err
+	}
+	for _, cmd := range cmds {
+		c.writeCommand(cmd)
+	}
+	if err := c.wr.Flush(); err != nil {
+		return nil, fmt.Errorf("redis: failed to write: %w", err)
+	}
+
+	if err := c.setDeadline(ctx, c.netConn.SetReadDeadline, c.readTimeout); err != nil {
+		return nil, err
+	}
+
+	replies := make([]any, len(cmds))
+	for i := range replies {
+		reply, err := c.readReply()
+		if err != nil {
+			return nil, err
+		}
+		replies[i] = reply
+	}
+	return replies, nil
+}
+
+// setDeadline sets the earlier of the deadline of the context and the end of the
+// timeout with set. The context is checked afterwards, because the deadline set
+// when the context is canceled must not be overwritten.
+func (c *conn) setDeadline(ctx context.Context, set func(t time.Time) error, timeout time.Duration) error {
+	deadline, _ := ctx.Deadline()
+	if err := set(earliest(deadline, timeout)); err != nil {
+		return fmt.Errorf("redis: failed to set deadline: %w", err)
+	}
+	return ctx.Err()
+}
+
+// earliest returns the earlier of the deadline and the end of the timeout from now,
+// a zero deadline or timeout is ignored
+func earliest(deadline time.Time, timeout time.Duration) time.Time {
+	if timeout <= 0 {
+		return deadline
+	}
+	if end := time.Now().Add(timeout); deadline.IsZero() || end.Before(deadline) {
+		return end
+	}
+	return deadline
+}
+
+// writeCommand writes the command as an array of bulk strings
+func (c *conn) writeCommand(args []string) {
+	c.writeHeader('*', len(args))
+	for _, arg := range args {
+		c.writeHeader('$', len(arg))
+		_, _ = c.wr.WriteString(arg)    //nolint:errcheck // Errors are reported by Flush
+		_, _ = c.wr.WriteString("\r\n") //nolint:errcheck // Errors are reported by Flush
+	}
+}
+
+func (c *conn) writeHeader(prefix byte, n int) {
+	_ = c.wr.WriteByte(prefix) //nolint:errcheck // Errors are reported by Flush
+	var buf [20]byte
+	_, _ = c.wr.Write(strconv.AppendInt(buf[:0], int64(n), 10)) //nolint:errcheck // Errors are reported by Flush
+	_, _ = c.wr.WriteString("\r\n")                             //nolint:errcheck // Errors are reported by Flush
+}
+
+// readReply reads the next reply, which is one of string, int64, []byte, []any,
+// replyError or nil for a null reply.
+func (c *conn) readReply() (any, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errUnexpectedReply
+	}
+
+	switch line[0] {
+	case '+':
+		return string(line[1:]), nil
+	case '-':
+		return replyError(line[1:]), nil
+	case ':':
+		n, err := strconv.ParseInt(string(line[1:]), 10, 64)
+		if err != nil {
+			return nil, errUnexpectedReply
+		}
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return nil, errUnexpectedReply
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.rd, buf); err != nil {
+			return nil, fmt.Errorf("redis: failed to read: %w", err)
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return nil, errUnexpectedReply
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		values := make([]any, n)
+		for i := range values {
+			if values[i], err = c.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	default:
+		return nil, errUnexpectedReply
+	}
+}
+
+func (c *conn) readLine() ([]byte, error) {
+	line, err := c.rd.ReadSlice('\n')
+	if err != nil {
+		return nil, fmt.Errorf("redis: failed to read: %w", err)
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, errUnexpectedReply
+	}
+	return line[:len(line)-2], nil
+}
+
+func (c *conn) close() error {
+	return c.netConn.Close()
+}
+
+// pool keeps idle connections to a single server.
+type pool struct {
+	conns  chan *conn
+	dial   func(ctx context.Context, addr string) (*conn, error)
+	addr   string
+	mux    sync.Mutex
+	closed bool
+}
+
+func newPool(addr string, size int, dial func(ctx context.Context, addr string) (*conn, error)) *pool {
+	return &pool{
+		addr:  addr,
+		conns: make(chan *conn, size),
+		dial:  dial,
+	}
+}
+
+// get returns an idle connection or dials a new one
+func (p *pool) get(ctx context.Context) (*conn, error) {
+	p.mux.Lock()
+	closed := p.closed
+	p.mux.Unlock()
+	if closed {
+		return nil, ErrClosed
+	}
+
+	select {
+	case c := <-p.conns:
+		return c, nil
+	default:
+		return p.dial(ctx, p.addr)
+	}
+}
+
+// put returns the connection to the pool, it is closed if the pool is full or closed
+func (p *pool) put(c *conn) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.closed {
+		_ = c.close() //nolint:errcheck // The connection is not used anymore
+		return
+	}
+
+	select {
+	case p.conns <- c:
+	default:
+		_ = c.close() //nolint:errcheck // The connection is not used anymore
+	}
+}
+
+// close closes all idle connections, connections which are put back afterwards are closed
+func (p *pool) close() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.closed = true
+	for {
+		select {
+		case c := <-p.conns:
+			_ = c.close() //nolint:errcheck // The connection is not used anymore
+		default:
+			return
+		}
+	}
+}
+
+// toString converts a string or bulk string reply
+func toString(reply any) (string, bool) {
+	switch v := reply.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	default:
+		return "", false
+	}
+}
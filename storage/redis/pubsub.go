@@ -0,0 +1,107 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// resubscribeInterval is the time between attempts to restore a broken subscription
+const resubscribeInterval = time.Second
+
+// Publish publishes the message to the channel, the prefix is added to the channel
+func (s *Storage) Publish(ctx context.Context, channel string, msg []byte) error {
+	_, err := s.do(ctx, channel, "PUBLISH", s.key(channel), string(msg))
+	return err
+}
+
+// Subscribe subscribes to the channel and calls the handler with its messages until
+// the context is canceled. It returns after the subscription is established, a broken
+// subscription is restored in the background.
+func (s *Storage) Subscribe(ctx context.Context, channel string, handler func(msg []byte)) error {
+	c, err := s.subscribe(ctx, channel)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			err := s.receive(ctx, c, handler)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Errorf("[REDIS] subscription to %q broken: %v", channel, err)
+
+			// Messages published in the meantime are lost
+			for c = nil; c == nil; {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(resubscribeInterval):
+				}
+				if c, err = s.subscribe(ctx, channel); err != nil {
+					log.Errorf("[REDIS] failed to subscribe to %q: %v", channel, err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// subscribe dials a connection which is subscribed to the channel
+func (s *Storage) subscribe(ctx context.Context, channel string) (*conn, error) {
+	s.mux.RLock()
+	closed := s.closed
+	s.mux.RUnlock()
+	if closed {
+		return nil, ErrClosed
+	}
+
+	c, err := s.dialServer(ctx, s.addrFor(channel))
+	if err != nil {
+		return nil, err
+	}
+	replies, err := c.exec(ctx, [][]string{{"SUBSCRIBE", s.key(channel)}})
+	if err == nil {
+		err = replyErr(replies[0])
+	}
+	if err != nil {
+		_ = c.close() //nolint:errcheck // The connection is not used anymore
+		return nil, err
+	}
+	return c, nil
+}
+
+// receive calls the handler with the messages of the subscribed connection until
+// the context is canceled or the connection breaks
+func (s *Storage) receive(ctx context.Context, c *conn, handler func(msg []byte)) error {
+	defer c.close() //nolint:errcheck // The connection is not used anymore
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.close() //nolint:errcheck // Unblocks the read
+	})
+	defer stop()
+
+	// Messages can arrive at any time, so the read timeout doesn't apply
+	if err := c.netConn.SetReadDeadline(time.Time{}); err != nil {
+		return fmt.Errorf("redis: failed to set deadline: %w", err)
+	}
+	for {
+		reply, err := c.readReply()
+		if err != nil {
+			return err
+		}
+		// A message is the push reply ["message", channel, payload]
+		values, ok := reply.([]any)
+		if !ok || len(values) != 3 {
+			continue
+		}
+		if kind, _ := toString(values[0]); kind != "message" {
+			continue
+		}
+		if payload, ok := values[2].([]byte); ok {
+			handler(payload)
+		}
+	}
+}
@@ -0,0 +1,637 @@
+// Package redis provides a Redis storage for the middlewares of Fiber,
+// with support for Redis Sentinel, Redis Cluster, key prefixes and pipelining.
+// It speaks the RESP protocol directly, so it has no dependencies.
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRedirects is the number of MOVED and ASK redirects followed in cluster mode
+const maxRedirects = 5
+
+// ErrClosed is returned when the storage is used after Close
+var ErrClosed = errors.New("redis: storage is closed")
+
+// Storage interface that is implemented by storage providers
+type Storage struct {
+	pools  map[string]*pool
+	slots  *slotMap
+	master string
+	cfg    Config
+	addrs  []string
+	mux    sync.RWMutex
+	closed bool
+}
+
+// New creates a new redis storage, it panics if the server can't be reached
+func New(config ...Config) *Storage {
+	// Set default config
+	cfg := configDefault(config...)
+
+	addrs := cfg.Addrs
+	if len(addrs) == 0 {
+		addrs = []string{net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))}
+	}
+
+	// Create storage
+	store := &Storage{
+		cfg:   cfg,
+		addrs: addrs,
+		pools: make(map[string]*pool),
+	}
+	if cfg.IsClusterMode {
+		store.slots = &slotMap{}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DialTimeout)
+	defer cancel()
+	if err := store.init(ctx); err != nil {
+		panic(err)
+	}
+
+	// Reset all entries if set to true
+	if cfg.Reset {
+		if err := store.Reset(); err != nil {
+			panic(err)
+		}
+	}
+
+	return store
+}
+
+// init resolves the master or the cluster slots and checks the connection
+func (s *Storage) init(ctx context.Context) error {
+	switch {
+	case s.cfg.MasterName != "":
+		if err := s.resolveMaster(ctx); err != nil {
+			return err
+		}
+	case s.cfg.IsClusterMode:
+		// The slots are learned from MOVED redirects if the seed nodes don't support CLUSTER SLOTS
+		_ = s.loadSlots(ctx) //nolint:errcheck // Not required to work with the cluster
+	}
+
+	for _, addr := range s.nodes() {
+		replies, err := s.execOn(ctx, addr, [][]string{{"PING"}})
+		if err != nil {
+			return err
+		}
+		if err := replyErr(replies[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get value by key
+func (s *Storage) Get(key string) ([]byte, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext gets value by key, the request is canceled with the context
+func (s *Storage) GetWithContext(ctx context.Context, key string) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, nil
+	}
+	reply, err := s.do(ctx, key, "GET", s.key(key))
+	if err != nil {
+		return nil, err
+	}
+	val, _ := reply.([]byte) //nolint:errcheck // A missing key is a nil reply
+	return val, nil
+}
+
+// GetMany gets the values of multiple keys with one pipeline per server,
+// the value of a missing key is nil
+func (s *Storage) GetMany(keys ...string) ([][]byte, error) {
+	ctx := context.Background()
+
+	// Group the keys by the server responsible for them
+	byAddr := make(map[string][]int)
+	for i, key := range keys {
+		addr := s.addrFor(key)
+		byAddr[addr] = append(byAddr[addr], i)
+	}
+
+	vals := make([][]byte, len(keys))
+	for addr, indexes := range byAddr {
+		cmds := make([][]string, len(indexes))
+		for i, index := range indexes {
+			cmds[i] = []string{"GET", s.key(keys[index])}
+		}
+		replies, err := s.execOn(ctx, addr, cmds)
+		if err != nil {
+			return nil, err
+		}
+		for i, reply := range replies {
+			if _, ok := reply.(replyError); ok {
+				// Follow redirects and report errors key by key
+				if vals[indexes[i]], err = s.Get(keys[indexes[i]]); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			vals[indexes[i]], _ = reply.([]byte) //nolint:errcheck // A missing key is a nil reply
+		}
+	}
+	return vals, nil
+}
+
+// Set key with value
+func (s *Storage) Set(key string, val []byte, exp time.Duration) error {
+	return s.SetWithContext(context.Background(), key, val, exp)
+}
+
+// SetWithContext sets key with value, the request is canceled with the context
+func (s *Storage) SetWithContext(ctx context.Context, key string, val []byte, exp time.Duration) error {
+	// Ain't Nobody Got Time For That
+	if len(key) == 0 || len(val) == 0 {
+		return nil
+	}
+	_, err := s.do(ctx, key, withExpiration([]string{"SET", s.key(key), string(val)}, exp)...)
+	return err
+}
+
+// SetNX sets key with value only if the key does not exist
+func (s *Storage) SetNX(key string, val []byte, exp time.Duration) (bool, error) {
+	// Ain't Nobody Got Time For That
+	if len(key) == 0 || len(val) == 0 {
+		return false, nil
+	}
+	reply, err := s.do(context.Background(), key, withExpiration([]string{"SET", s.key(key), string(val), "NX"}, exp)...)
+	if err != nil {
+		return false, err
+	}
+	return reply != nil, nil
+}
+
+// IncrBy increments the integer value of key by delta
+func (s *Storage) IncrBy(key string, delta int64, exp time.Duration) (int64, error) {
+	if len(key) == 0 {
+		return 0, errors.New("redis: empty key")
+	}
+
+	cmds := [][]string{{"INCRBY", s.key(key), strconv.FormatInt(delta, 10)}}
+	if exp > 0 {
+		// Create the key with the expiration first, INCRBY keeps the expiration of existing keys
+		cmds = append([][]string{withExpiration([]string{"SET", s.key(key), "0", "NX"}, exp)}, cmds...)
+	}
+	replies, err := s.pipeline(context.Background(), key, cmds)
+	if err != nil {
+		return 0, err
+	}
+	value, ok := replies[len(replies)-1].(int64)
+	if !ok {
+		return 0, errUnexpectedReply
+	}
+	return value, nil
+}
+
+// TTL returns the remaining time to live of key
+func (s *Storage) TTL(key string) (time.Duration, error) {
+	reply, err := s.do(context.Background(), key, "PTTL", s.key(key))
+	if err != nil {
+		return 0, err
+	}
+	ms, ok := reply.(int64)
+	if !ok {
+		return 0, errUnexpectedReply
+	}
+	switch {
+	case ms == -1:
+		// The key has no expiration
+		return 0, nil
+	case ms < 0:
+		return -1, nil
+	default:
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+}
+
+// Scan returns all keys with the prefix
+func (s *Storage) Scan(prefix string) ([]string, error) {
+	keys, err := s.scan(context.Background(), s.key(prefix))
+	if err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		keys[i] = strings.TrimPrefix(keys[i], s.cfg.Prefix)
+	}
+	return keys, nil
+}
+
+// Delete key by key
+func (s *Storage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext deletes key by key, the request is canceled with the context
+func (s *Storage) DeleteWithContext(ctx context.Context, key string) error {
+	// Ain't Nobody Got Time For That
+	if len(key) == 0 {
+		return nil
+	}
+	_, err := s.do(ctx, key, "DEL", s.key(key))
+	return err
+}
+
+// Reset all keys, only the keys with the prefix are deleted if a prefix is set
+func (s *Storage) Reset() error {
+	ctx := context.Background()
+
+	if s.cfg.Prefix == "" {
+		for _, addr := range s.nodes() {
+			replies, err := s.execOn(ctx, addr, [][]string{{"FLUSHDB"}})
+			if err != nil {
+				return err
+			}
+			if err := replyErr(replies[0]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	keys, err := s.scan(ctx, s.cfg.Prefix)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if _, err := s.do(ctx, strings.TrimPrefix(key, s.cfg.Prefix), "DEL", key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close the storage and all idle connections
+func (s *Storage) Close() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.closed = true
+	for addr, p := range s.pools {
+		p.close()
+		delete(s.pools, addr)
+	}
+	return nil
+}
+
+// key returns the key with the prefix
+func (s *Storage) key(key string) string {
+	return s.cfg.Prefix + key
+}
+
+// do executes a single command for the key and returns its reply or its error reply as error
+func (s *Storage) do(ctx context.Context, key string, args ...string) (any, error) {
+	replies, err := s.pipeline(ctx, key, [][]string{args})
+	if err != nil {
+		return nil, err
+	}
+	return replies[0], nil
+}
+
+// pipeline executes the commands for the key on the responsible server and
+// follows redirects of the cluster. Error replies are returned as error.
+func (s *Storage) pipeline(ctx context.Context, key string, cmds [][]string) ([]any, error) {
+	addr := s.addrFor(key)
+	asking := false
+
+	for range maxRedirects {
+		pipeline := cmds
+		if asking {
+			pipeline = append([][]string{{"ASKING"}}, cmds...)
+		}
+		replies, err := s.execOn(ctx, addr, pipeline)
+		if err != nil {
+			return nil, err
+		}
+		if asking {
+			replies = replies[1:]
+		}
+
+		redirected := false
+		for _, reply := range replies {
+			err := replyErr(reply)
+			if err == nil {
+				continue
+			}
+			if s.cfg.IsClusterMode {
+				if redirect, ok := parseRedirect(reply); ok {
+					if !redirect.ask {
+						s.slots.set(redirect.slot, redirect.addr)
+					}
+					addr, asking, redirected = redirect.addr, redirect.ask, true
+					break
+				}
+			}
+			return nil, err
+		}
+		if !redirected {
+			return replies, nil
+		}
+	}
+	return nil, errors.New("redis: too many cluster redirects")
+}
+
+// execOn executes the commands on the server with the address
+func (s *Storage) execOn(ctx context.Context, addr string, cmds [][]string) ([]any, error) {
+	p, err := s.pool(addr)
+	if err != nil {
+		return nil, err
+	}
+	c, err := p.get(ctx)
+	if err != nil {
+		if s.cfg.MasterName != "" {
+			// The master might have failed over, ask the sentinels again
+			_ = s.resolveMaster(ctx) //nolint:errcheck // The dial error is more helpful
+		}
+		return nil, err
+	}
+
+	replies, err := c.exec(ctx, cmds)
+	if err != nil {
+		// The state of the connection is unknown after an error
+		_ = c.close() //nolint:errcheck // The connection is not used anymore
+		return nil, err
+	}
+	p.put(c)
+
+	if s.cfg.MasterName != "" {
+		for _, reply := range replies {
+			if e, ok := reply.(replyError); ok && strings.HasPrefix(string(e), "READONLY") {
+				// The server was demoted to a replica
+				_ = s.resolveMaster(ctx) //nolint:errcheck // The error reply is returned
+			}
+		}
+	}
+	return replies, nil
+}
+
+// pool returns the connection pool of the server with the address
+func (s *Storage) pool(addr string) (*pool, error) {
+	s.mux.RLock()
+	p, ok := s.pools[addr]
+	closed := s.closed
+	s.mux.RUnlock()
+	if closed {
+		return nil, ErrClosed
+	}
+	if ok {
+		return p, nil
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if p, ok = s.pools[addr]; !ok {
+		p = newPool(addr, s.cfg.PoolSize, s.dialServer)
+		s.pools[addr] = p
+	}
+	return p, nil
+}
+
+// addrFor returns the address of the server responsible for the key
+func (s *Storage) addrFor(key string) string {
+	if s.cfg.MasterName != "" {
+		s.mux.RLock()
+		defer s.mux.RUnlock()
+		return s.master
+	}
+	if s.cfg.IsClusterMode {
+		if addr := s.slots.get(keySlot(s.key(key))); addr != "" {
+			return addr
+		}
+	}
+	return s.addrs[0]
+}
+
+// nodes returns the addresses of all servers which hold keys
+func (s *Storage) nodes() []string {
+	switch {
+	case s.cfg.MasterName != "":
+		s.mux.RLock()
+		defer s.mux.RUnlock()
+		return []string{s.master}
+	case s.cfg.IsClusterMode:
+		if nodes := s.slots.nodes(); len(nodes) > 0 {
+			return nodes
+		}
+		return s.addrs
+	default:
+		return s.addrs[:1]
+	}
+}
+
+// scan returns the keys matching the prefix from all servers
+func (s *Storage) scan(ctx context.Context, prefix string) ([]string, error) {
+	pattern := escapePattern(prefix) + "*"
+
+	var keys []string
+	for _, addr := range s.nodes() {
+		cursor := "0"
+		for {
+			replies, err := s.execOn(ctx, addr, [][]string{{"SCAN", cursor, "MATCH", pattern, "COUNT", "100"}})
+			if err != nil {
+				return nil, err
+			}
+			if err := replyErr(replies[0]); err != nil {
+				return nil, err
+			}
+			values, ok := replies[0].([]any)
+			if !ok || len(values) != 2 {
+				return nil, errUnexpectedReply
+			}
+			if cursor, ok = toString(values[0]); !ok {
+				return nil, errUnexpectedReply
+			}
+			batch, _ := values[1].([]any) //nolint:errcheck // An empty batch is nil
+			for _, value := range batch {
+				if key, ok := toString(value); ok {
+					keys = append(keys, key)
+				}
+			}
+			if cursor == "0" {
+				break
+			}
+		}
+	}
+	return keys, nil
+}
+
+// dialServer dials a data server and authenticates the connection
+func (s *Storage) dialServer(ctx context.Context, addr string) (*conn, error) {
+	var cmds [][]string
+	if s.cfg.Password != "" {
+		cmds = append(cmds, authCommand(s.cfg.Username, s.cfg.Password))
+	}
+	if s.cfg.Database != 0 && !s.cfg.IsClusterMode {
+		cmds = append(cmds, []string{"SELECT", strconv.Itoa(s.cfg.Database)})
+	}
+	return s.dial(ctx, addr, cmds)
+}
+
+// dial connects to the address and executes the handshake commands
+func (s *Storage) dial(ctx context.Context, addr string, handshake [][]string) (*conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.DialTimeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	var netConn net.Conn
+	var err error
+	if s.cfg.TLSConfig != nil {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: s.cfg.TLSConfig}
+		netConn, err = tlsDialer.DialContext(ctx, "tcp", addr)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis: failed to connect to %s: %w", addr, err)
+	}
+
+	c := newConn(netConn, s.cfg.ReadTimeout, s.cfg.WriteTimeout)
+	if len(handshake) == 0 {
+		return c, nil
+	}
+	replies, err := c.exec(ctx, handshake)
+	if err == nil {
+		for _, reply := range replies {
+			if err = replyErr(reply); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		_ = c.close() //nolint:errcheck // The connection is not used anymore
+		return nil, err
+	}
+	return c, nil
+}
+
+// resolveMaster asks the sentinels for the address of the master
+func (s *Storage) resolveMaster(ctx context.Context) error {
+	var handshake [][]string
+	if s.cfg.SentinelPassword != "" {
+		handshake = append(handshake, authCommand(s.cfg.SentinelUsername, s.cfg.SentinelPassword))
+	}
+
+	err := errors.New("redis: no sentinel available")
+	for _, addr := range s.addrs {
+		var c *conn
+		if c, err = s.dial(ctx, addr, handshake); err != nil {
+			continue
+		}
+		var replies []any
+		replies, err = c.exec(ctx, [][]string{{"SENTINEL", "get-master-addr-by-name", s.cfg.MasterName}})
+		_ = c.close() //nolint:errcheck // Sentinel connections are not reused
+		if err != nil {
+			continue
+		}
+		values, ok := replies[0].([]any)
+		if !ok || len(values) != 2 {
+			err = fmt.Errorf("redis: sentinel %s doesn't know master %q", addr, s.cfg.MasterName)
+			continue
+		}
+		host, _ := toString(values[0]) //nolint:errcheck // Checked below
+		port, _ := toString(values[1]) //nolint:errcheck // Checked below
+		if host == "" || port == "" {
+			err = errUnexpectedReply
+			continue
+		}
+
+		s.mux.Lock()
+		s.master = net.JoinHostPort(host, port)
+		s.mux.Unlock()
+		return nil
+	}
+	return err
+}
+
+// loadSlots loads the slot map of the cluster from the first seed node which answers
+func (s *Storage) loadSlots(ctx context.Context) error {
+	err := errors.New("redis: no cluster node available")
+	for _, addr := range s.addrs {
+		var replies []any
+		if replies, err = s.execOn(ctx, addr, [][]string{{"CLUSTER", "SLOTS"}}); err != nil {
+			continue
+		}
+		if err = replyErr(replies[0]); err != nil {
+			continue
+		}
+		ranges, ok := replies[0].([]any)
+		if !ok {
+			err = errUnexpectedReply
+			continue
+		}
+		for _, r := range ranges {
+			fields, ok := r.([]any)
+			if !ok || len(fields) < 3 {
+				continue
+			}
+			start, _ := fields[0].(int64) //nolint:errcheck // Invalid ranges are empty
+			end, _ := fields[1].(int64)   //nolint:errcheck // Invalid ranges are empty
+			node, ok := fields[2].([]any)
+			if !ok || len(node) < 2 {
+				continue
+			}
+			host, _ := toString(node[0]) //nolint:errcheck // An empty host is the queried node
+			port, _ := node[1].(int64)   //nolint:errcheck // Checked below
+			if port == 0 {
+				continue
+			}
+			if host == "" {
+				host, _, _ = net.SplitHostPort(addr) //nolint:errcheck // The seed address is valid
+			}
+			nodeAddr := net.JoinHostPort(host, strconv.FormatInt(port, 10))
+			for slot := start; slot <= end && slot < slotCount; slot++ {
+				s.slots.set(int(slot), nodeAddr)
+			}
+		}
+		return nil
+	}
+	return err
+}
+
+// withExpiration appends the expiration to a SET command, 0 means no expiration
+func withExpiration(cmd []string, exp time.Duration) []string {
+	if exp > 0 {
+		cmd = append(cmd, "PX", strconv.FormatInt(exp.Milliseconds(), 10))
+	}
+	return cmd
+}
+
+func authCommand(username, password string) []string {
+	if username != "" {
+		return []string{"AUTH", username, password}
+	}
+	return []string{"AUTH", password}
+}
+
+// replyErr returns the error reply as error
+func replyErr(reply any) error {
+	if err, ok := reply.(replyError); ok {
+		return err
+	}
+	return nil
+}
+
+// escapePattern escapes the glob characters of a SCAN pattern
+func escapePattern(pattern string) string {
+	var b strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
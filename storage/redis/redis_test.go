@@ -0,0 +1,548 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+var _ fiber.StorageV2 = (*Storage)(nil)
+
+type fakeEntry struct {
+	expiry time.Time
+	val    string
+}
+
+// fakeServer is a minimal in-memory server speaking RESP, so the storage can be tested without Redis
+type fakeServer struct {
+	ln          net.Listener
+	data        map[string]fakeEntry
+	subscribers map[string][]net.Conn
+	moved       string // redirect all keys to this address like a cluster node
+	master      string // reply to sentinel requests with this address
+	password    string
+	commands    []string
+	mux         sync.Mutex
+	block       bool // never reply to GET
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := &fakeServer{ln: ln, data: make(map[string]fakeEntry), subscribers: make(map[string][]net.Conn)}
+	go srv.serve()
+	t.Cleanup(func() {
+		require.NoError(t, ln.Close())
+	})
+	return srv
+}
+
+func (srv *fakeServer) addr() string {
+	return srv.ln.Addr().String()
+}
+
+func (srv *fakeServer) received() []string {
+	srv.mux.Lock()
+	defer srv.mux.Unlock()
+	return append([]string(nil), srv.commands...)
+}
+
+func (srv *fakeServer) serve() {
+	for {
+		netConn, err := srv.ln.Accept()
+		if err != nil {
+			return
+		}
+		go srv.handle(netConn)
+	}
+}
+
+func (srv *fakeServer) handle(netConn net.Conn) {
+	defer netConn.Close() //nolint:errcheck // It is a test
+	rd := bufio.NewReader(netConn)
+	srv.mux.Lock()
+	password := srv.password
+	srv.mux.Unlock()
+	authenticated := password == ""
+
+	for {
+		args, err := readCommand(rd)
+		if err != nil {
+			return
+		}
+		name := strings.ToUpper(args[0])
+		if name == "AUTH" {
+			authenticated = args[len(args)-1] == password
+		}
+
+		srv.mux.Lock()
+		srv.commands = append(srv.commands, name)
+		var reply string
+		switch {
+		case !authenticated:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case srv.block && name == "GET":
+			srv.mux.Unlock()
+			continue
+		case name == "SUBSCRIBE":
+			srv.subscribers[args[1]] = append(srv.subscribers[args[1]], netConn)
+			reply = "*3\r\n" + bulk("subscribe") + bulk(args[1]) + ":1\r\n"
+		default:
+			reply = srv.exec(name, args[1:])
+		}
+		srv.mux.Unlock()
+
+		if _, err := netConn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func (srv *fakeServer) exec(name string, args []string) string {
+	if srv.moved != "" {
+		switch name {
+		case "GET", "SET", "DEL", "INCRBY", "PTTL":
+			return "-MOVED " + strconv.Itoa(keySlot(args[0])) + " " + srv.moved + "\r\n"
+		}
+	}
+
+	switch name {
+	case "PING":
+		return "+PONG\r\n"
+	case "AUTH", "SELECT", "ASKING":
+		return "+OK\r\n"
+	case "FLUSHDB":
+		srv.data = make(map[string]fakeEntry)
+		return "+OK\r\n"
+	case "GET":
+		e, ok := srv.get(args[0])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(e.val)
+	case "SET":
+		e := fakeEntry{val: args[1]}
+		nx := false
+		for i := 2; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "NX":
+				nx = true
+			case "PX":
+				ms, _ := strconv.Atoi(args[i+1]) //nolint:errcheck // It is a test
+				e.expiry = time.Now().Add(time.Duration(ms) * time.Millisecond)
+				i++
+			}
+		}
+		if _, ok := srv.get(args[0]); ok && nx {
+			return "$-1\r\n"
+		}
+		srv.data[args[0]] = e
+		return "+OK\r\n"
+	case "DEL":
+		_, ok := srv.get(args[0])
+		delete(srv.data, args[0])
+		if ok {
+			return ":1\r\n"
+		}
+		return ":0\r\n"
+	case "INCRBY":
+		e, _ := srv.get(args[0])
+		current, err := strconv.ParseInt("0"+e.val, 10, 64)
+		if err != nil {
+			return "-ERR value is not an integer or out of range\r\n"
+		}
+		delta, _ := strconv.ParseInt(args[1], 10, 64) //nolint:errcheck // It is a test
+		e.val = strconv.FormatInt(current+delta, 10)
+		srv.data[args[0]] = e
+		return ":" + e.val + "\r\n"
+	case "PTTL":
+		e, ok := srv.get(args[0])
+		switch {
+		case !ok:
+			return ":-2\r\n"
+		case e.expiry.IsZero():
+			return ":-1\r\n"
+		default:
+			return ":" + strconv.FormatInt(time.Until(e.expiry).Milliseconds(), 10) + "\r\n"
+		}
+	case "SCAN":
+		prefix := strings.ReplaceAll(strings.TrimSuffix(args[2], "*"), "\\", "")
+		var keys []string
+		for key := range srv.data {
+			if _, ok := srv.get(key); ok && strings.HasPrefix(key, prefix) {
+				keys = append(keys, bulk(key))
+			}
+		}
+		return "*2\r\n" + bulk("0") + "*" + strconv.Itoa(len(keys)) + "\r\n" + strings.Join(keys, "")
+	case "PUBLISH":
+		for _, subscriber := range srv.subscribers[args[0]] {
+			_, _ = subscriber.Write([]byte("*3\r\n" + bulk("message") + bulk(args[0]) + bulk(args[1]))) //nolint:errcheck // It is a test
+		}
+		return ":" + strconv.Itoa(len(srv.subscribers[args[0]])) + "\r\n"
+	case "SENTINEL":
+		host, port, _ := net.SplitHostPort(srv.master) //nolint:errcheck // It is a test
+		return "*2\r\n" + bulk(host) + bulk(port)
+	default:
+		return "-ERR unknown command '" + name + "'\r\n"
+	}
+}
+
+func (srv *fakeServer) get(key string) (fakeEntry, bool) {
+	e, ok := srv.data[key]
+	if ok && !e.expiry.IsZero() && time.Now().After(e.expiry) {
+		delete(srv.data, key)
+		return fakeEntry{}, false
+	}
+	return e, ok
+}
+
+func bulk(s string) string {
+	return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n"
+}
+
+func readCommand(rd *bufio.Reader) ([]string, error) {
+	c := &conn{rd: rd}
+	reply, err := c.readReply()
+	if err != nil {
+		return nil, err
+	}
+	values, ok := reply.([]any)
+	if !ok || len(values) == 0 {
+		return nil, errUnexpectedReply
+	}
+	args := make([]string, len(values))
+	for i, value := range values {
+		args[i], _ = toString(value) //nolint:errcheck // It is a test
+	}
+	return args, nil
+}
+
+func newTestStore(t *testing.T, srv *fakeServer, config ...Config) *Storage {
+	t.Helper()
+	cfg := Config{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if len(cfg.Addrs) == 0 {
+		cfg.Addrs = []string{srv.addr()}
+	}
+	store := New(cfg)
+	t.Cleanup(func() {
+		require.NoError(t, store.Close())
+	})
+	return store
+}
+
+func Test_Storage_Redis_Set_Get(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t, newFakeServer(t))
+
+	require.NoError(t, store.Set("john", []byte("doe"), 0))
+
+	result, err := store.Get("john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("doe"), result)
+
+	result, err = store.Get("empty")
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
+
+func Test_Storage_Redis_Set_Expiration(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t, newFakeServer(t))
+
+	require.NoError(t, store.Set("john", []byte("doe"), 50*time.Millisecond))
+	time.Sleep(100 * time.Millisecond)
+
+	result, err := store.Get("john")
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
+
+func Test_Storage_Redis_Delete(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t, newFakeServer(t))
+
+	require.NoError(t, store.Set("john", []byte("doe"), 0))
+	require.NoError(t, store.Delete("john"))
+
+	result, err := store.Get("john")
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
+
+func Test_Storage_Redis_SetNX(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t, newFakeServer(t))
+
+	stored, err := store.SetNX("john", []byte("doe"), 0)
+	require.NoError(t, err)
+	require.True(t, stored)
+
+	stored, err = store.SetNX("john", []byte("smith"), 0)
+	require.NoError(t, err)
+	require.False(t, stored)
+
+	result, err := store.Get("john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("doe"), result)
+}
+
+func Test_Storage_Redis_IncrBy_TTL(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t, newFakeServer(t))
+
+	value, err := store.IncrBy("counter", 2, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), value)
+
+	value, err = store.IncrBy("counter", 3, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), value)
+
+	ttl, err := store.TTL("counter")
+	require.NoError(t, err)
+	require.Greater(t, ttl, 58*time.Second)
+
+	require.NoError(t, store.Set("forever", []byte("doe"), 0))
+	ttl, err = store.TTL("forever")
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(0), ttl)
+
+	ttl, err = store.TTL("missing")
+	require.NoError(t, err)
+	require.Negative(t, ttl)
+
+	_, err = store.IncrBy("forever", 1, 0)
+	require.ErrorContains(t, err, "not an integer")
+}
+
+func Test_Storage_Redis_Prefix(t *testing.T) {
+	t.Parallel()
+	srv := newFakeServer(t)
+	store := newTestStore(t, srv, Config{Prefix: "app:"})
+	other := newTestStore(t, srv, Config{Prefix: "other:"})
+
+	require.NoError(t, store.Set("user:1", []byte("john"), 0))
+	require.NoError(t, store.Set("user:2", []byte("jane"), 0))
+	require.NoError(t, other.Set("user:1", []byte("doe"), 0))
+
+	keys, err := store.Scan("user:")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"user:1", "user:2"}, keys)
+
+	// Reset only deletes the keys with the prefix
+	require.NoError(t, store.Reset())
+
+	keys, err = store.Scan("")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	result, err := other.Get("user:1")
+	require.NoError(t, err)
+	require.Equal(t, []byte("doe"), result)
+}
+
+func Test_Storage_Redis_Reset(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t, newFakeServer(t))
+
+	require.NoError(t, store.Set("john", []byte("doe"), 0))
+	require.NoError(t, store.Reset())
+
+	result, err := store.Get("john")
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
+
+func Test_Storage_Redis_GetMany(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t, newFakeServer(t))
+
+	require.NoError(t, store.Set("john", []byte("doe"), 0))
+	require.NoError(t, store.Set("jane", []byte("smith"), 0))
+
+	vals, err := store.GetMany("john", "missing", "jane")
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("doe"), nil, []byte("smith")}, vals)
+}
+
+func Test_Storage_Redis_WithContext(t *testing.T) {
+	t.Parallel()
+	srv := newFakeServer(t)
+	store := newTestStore(t, srv)
+
+	require.NoError(t, store.SetWithContext(context.Background(), "john", []byte("doe"), 0))
+
+	srv.mux.Lock()
+	srv.block = true
+	srv.mux.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := store.GetWithContext(ctx, "john")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func Test_Storage_Redis_ReadTimeout(t *testing.T) {
+	t.Parallel()
+	srv := newFakeServer(t)
+	store := newTestStore(t, srv, Config{ReadTimeout: 50 * time.Millisecond})
+
+	srv.mux.Lock()
+	srv.block = true
+	srv.mux.Unlock()
+
+	// Methods without a context fail once the read timeout expires
+	_, err := store.Get("john")
+	require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+
+	// The read timeout applies if the deadline of the context is later
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	_, err = store.GetWithContext(ctx, "john")
+	require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+}
+
+func Test_Storage_Redis_Auth(t *testing.T) {
+	t.Parallel()
+	srv := newFakeServer(t)
+	srv.mux.Lock()
+	srv.password = "secret"
+	srv.mux.Unlock()
+
+	require.Panics(t, func() {
+		New(Config{Addrs: []string{srv.addr()}})
+	})
+
+	store := newTestStore(t, srv, Config{Password: "secret", Database: 2})
+	require.NoError(t, store.Set("john", []byte("doe"), 0))
+	require.Contains(t, srv.received(), "SELECT")
+}
+
+func Test_Storage_Redis_Cluster_Moved(t *testing.T) {
+	t.Parallel()
+	seed := newFakeServer(t)
+	node := newFakeServer(t)
+	seed.mux.Lock()
+	seed.moved = node.addr()
+	seed.mux.Unlock()
+
+	store := newTestStore(t, seed, Config{IsClusterMode: true})
+
+	require.NoError(t, store.Set("john", []byte("doe"), 0))
+
+	result, err := store.Get("john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("doe"), result)
+
+	// The slot is remembered, so only the first request is redirected
+	require.Equal(t, node.addr(), store.addrFor("john"))
+	require.Equal(t, 1, strings.Count(strings.Join(seed.received(), " "), "SET"))
+	require.NotContains(t, seed.received(), "GET")
+}
+
+func Test_Storage_Redis_Sentinel(t *testing.T) {
+	t.Parallel()
+	master := newFakeServer(t)
+	sentinel := newFakeServer(t)
+	sentinel.mux.Lock()
+	sentinel.master = master.addr()
+	sentinel.mux.Unlock()
+
+	store := newTestStore(t, sentinel, Config{MasterName: "mymaster"})
+
+	require.NoError(t, store.Set("john", []byte("doe"), 0))
+
+	result, err := store.Get("john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("doe"), result)
+	require.Contains(t, master.received(), "SET")
+	require.Contains(t, sentinel.received(), "SENTINEL")
+}
+
+func Test_Storage_Redis_Close(t *testing.T) {
+	t.Parallel()
+	srv := newFakeServer(t)
+	store := New(Config{Addrs: []string{srv.addr()}})
+
+	require.NoError(t, store.Close())
+	require.ErrorIs(t, store.Set("john", []byte("doe"), 0), ErrClosed)
+
+	// Connections which are put back after the pool is closed are closed
+	p := newPool(srv.addr(), 1, nil)
+	p.close()
+	client, server := net.Pipe()
+	defer server.Close() //nolint:errcheck // It is a test
+	p.put(newConn(client, time.Second, time.Second))
+	_, err := client.Write([]byte("PING"))
+	require.ErrorIs(t, err, io.ErrClosedPipe)
+	_, err = p.get(context.Background())
+	require.ErrorIs(t, err, ErrClosed)
+}
+
+func Test_Storage_Redis_PubSub(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t, newFakeServer(t), Config{Prefix: "app:"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	messages := make(chan string, 2)
+	require.NoError(t, store.Subscribe(ctx, "events", func(msg []byte) {
+		messages <- string(msg)
+	}))
+
+	require.NoError(t, store.Publish(context.Background(), "events", []byte("hello")))
+	require.NoError(t, store.Publish(context.Background(), "other", []byte("ignored")))
+	require.NoError(t, store.Publish(context.Background(), "events", []byte("world")))
+	require.Equal(t, "hello", <-messages)
+	require.Equal(t, "world", <-messages)
+
+	// No messages are received after the context is canceled
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, store.Publish(context.Background(), "events", []byte("late")))
+	select {
+	case msg := <-messages:
+		t.Fatalf("unexpected message %q", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func Test_KeySlot(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, uint16(0x31C3), crc16("123456789"))
+	require.Equal(t, 12182, keySlot("foo"))
+	require.Equal(t, keySlot("{user1000}.following"), keySlot("{user1000}.followers"))
+	require.Equal(t, keySlot("foo{}{bar}"), int(crc16("foo{}{bar}")%slotCount))
+}
+
+func Benchmark_Redis_Set(b *testing.B) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(b, err)
+	srv := &fakeServer{ln: ln, data: make(map[string]fakeEntry), subscribers: make(map[string][]net.Conn)}
+	go srv.serve()
+	defer ln.Close() //nolint:errcheck // It is a test
+
+	store := New(Config{Addrs: []string{srv.addr()}})
+	defer store.Close() //nolint:errcheck // It is a test
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err = store.Set("john", []byte("doe"), 0)
+	}
+	require.NoError(b, err)
+}
@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/storage/memory"
+)
+
+// TieredConfig defines the config for NewTiered.
+type TieredConfig struct {
+	// Local is the storage which is read first, it should be fast and close to the application.
+	//
+	// Optional. Default is a memory storage with at most 10000 entries
+	Local fiber.Storage
+
+	// LocalTTL is the maximum time an entry is kept in the local storage.
+	// Changes of other instances to the remote storage become visible after this time,
+	// so it should be short.
+	//
+	// Optional. Default is 5 * time.Second
+	LocalTTL time.Duration
+}
+
+// TieredConfigDefault is the default config
+var TieredConfigDefault = TieredConfig{
+	LocalTTL: 5 * time.Second,
+}
+
+// tieredConfigDefault is a helper function to set default values
+func tieredConfigDefault(config ...TieredConfig) TieredConfig {
+	// Return default config if nothing provided
+	cfg := TieredConfigDefault
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// Set default values
+	if cfg.Local == nil {
+		cfg.Local = memory.New(memory.Config{MaxEntries: 10_000})
+	}
+	if cfg.LocalTTL <= 0 {
+		cfg.LocalTTL = TieredConfigDefault.LocalTTL
+	}
+	return cfg
+}
+
+// NewTiered creates a storage which reads from a local storage first and falls back to the
+// remote storage, e.g. to save round trips to Redis for hot session or cache keys.
+// Writes go to the remote storage first and then to the local storage.
+// The optional interfaces of fiber.StorageV2 are kept if the remote storage implements all of them,
+// their operations are sent to the remote storage directly. Close closes both storages.
+//
+//	store := storage.NewTiered(redis.New(), storage.TieredConfig{
+//		LocalTTL: 2 * time.Second,
+//	})
+func NewTiered(remote fiber.Storage, config ...TieredConfig) fiber.Storage {
+	cfg := tieredConfigDefault(config...)
+
+	t := &tieredStorage{local: cfg.Local, remote: remote, ctxRemote: withContext(remote), localTTL: cfg.LocalTTL}
+	if v2, ok := remote.(fiber.StorageV2); ok {
+		return &tieredStorageV2{tieredStorage: t, v2: v2}
+	}
+	return t
+}
+
+type tieredStorage struct {
+	local     fiber.Storage
+	remote    fiber.Storage
+	ctxRemote fiber.StorageWithContext
+	localTTL  time.Duration
+}
+
+// Get value by key from the local storage or the remote storage
+func (s *tieredStorage) Get(key string) ([]byte, error) {
+	return s.GetWithContext(context.Background(), key)
+}
+
+// GetWithContext gets value by key from the local storage or with the context from the remote storage
+func (s *tieredStorage) GetWithContext(ctx context.Context, key string) ([]byte, error) {
+	if val, err := s.local.Get(key); err == nil && val != nil {
+		return val, nil
+	}
+
+	val, err := s.ctxRemote.GetWithContext(ctx, key)
+	if err != nil || val == nil {
+		return val, err
+	}
+
+	// The local storage is only a copy, so its errors are ignored
+	_ = s.local.Set(key, val, s.localTTL) //nolint:errcheck // The value is served from the remote storage
+	return val, nil
+}
+
+// Set key with value in the remote storage and the local storage
+func (s *tieredStorage) Set(key string, val []byte, exp time.Duration) error {
+	return s.SetWithContext(context.Background(), key, val, exp)
+}
+
+// SetWithContext sets key with value with the context in the remote storage and then in the local storage
+func (s *tieredStorage) SetWithContext(ctx context.Context, key string, val []byte, exp time.Duration) error {
+	if err := s.ctxRemote.SetWithContext(ctx, key, val, exp); err != nil {
+		// Don't serve a value which might be outdated now
+		_ = s.local.Delete(key) //nolint:errcheck // The error of the remote storage is returned
+		return err
+	}
+
+	_ = s.local.Set(key, val, s.localExpiration(exp)) //nolint:errcheck // The value is stored in the remote storage
+	return nil
+}
+
+// Delete key by key from both storages
+func (s *tieredStorage) Delete(key string) error {
+	return s.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteWithContext deletes key by key from the local storage and with the context from the remote storage
+func (s *tieredStorage) DeleteWithContext(ctx context.Context, key string) error {
+	localErr := s.local.Delete(key)
+	if err := s.ctxRemote.DeleteWithContext(ctx, key); err != nil {
+		return err
+	}
+	return localErr
+}
+
+// Reset both storages
+func (s *tieredStorage) Reset() error {
+	localErr := s.local.Reset()
+	if err := s.remote.Reset(); err != nil {
+		return err
+	}
+	return localErr
+}
+
+// Close both storages
+func (s *tieredStorage) Close() error {
+	localErr := s.local.Close()
+	if err := s.remote.Close(); err != nil {
+		return err
+	}
+	return localErr
+}
+
+// localExpiration returns the expiration of a local copy, which never outlives the remote entry
+func (s *tieredStorage) localExpiration(exp time.Duration) time.Duration {
+	if exp > 0 && exp < s.localTTL {
+		return exp
+	}
+	return s.localTTL
+}
+
+// tieredStorageV2 sends the operations of fiber.StorageV2 to the remote storage
+type tieredStorageV2 struct {
+	*tieredStorage
+	v2 fiber.StorageV2
+}
+
+// SetNX sets key with value in the remote storage only if the key does not exist
+func (s *tieredStorageV2) SetNX(key string, val []byte, exp time.Duration) (bool, error) {
+	stored, err := s.v2.SetNX(key, val, exp)
+	if err == nil && stored {
+		_ = s.local.Set(key, val, s.localExpiration(exp)) //nolint:errcheck // The value is stored in the remote storage
+	}
+	return stored, err
+}
+
+// IncrBy increments the integer value of key by delta in the remote storage
+func (s *tieredStorageV2) IncrBy(key string, delta int64, exp time.Duration) (int64, error) {
+	value, err := s.v2.IncrBy(key, delta, exp)
+	// The local copy is outdated now
+	_ = s.local.Delete(key) //nolint:errcheck // The value is stored in the remote storage
+	return value, err
+}
+
+// TTL returns the remaining time to live of key in the remote storage
+func (s *tieredStorageV2) TTL(key string) (time.Duration, error) {
+	return s.v2.TTL(key)
+}
+
+// Scan returns all keys with the prefix from the remote storage
+func (s *tieredStorageV2) Scan(prefix string) ([]string, error) {
+	return s.v2.Scan(prefix)
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/internal/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Tiered_Get(t *testing.T) {
+	t.Parallel()
+	stats := NewStats()
+	remote := memory.New()
+	store := NewTiered(WithMetrics(remote, stats))
+
+	require.NoError(t, remote.Set("john", []byte("doe"), 0))
+
+	// The first read falls back to the remote storage, the second is served locally
+	for range 2 {
+		val, err := store.Get("john")
+		require.NoError(t, err)
+		require.Equal(t, []byte("doe"), val)
+	}
+	require.Equal(t, uint64(1), stats.Snapshot()[OpGet].Count)
+
+	val, err := store.Get("missing")
+	require.NoError(t, err)
+	require.Nil(t, val)
+}
+
+func Test_Tiered_Set_Delete(t *testing.T) {
+	t.Parallel()
+	stats := NewStats()
+	local := memory.New()
+	store := NewTiered(WithMetrics(memory.New(), stats), TieredConfig{Local: local})
+
+	require.NoError(t, store.Set("john", []byte("doe"), 0))
+
+	// Writes go through to both storages
+	val, err := local.Get("john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("doe"), val)
+	require.Equal(t, uint64(1), stats.Snapshot()[OpSet].Count)
+
+	val, err = store.Get("john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("doe"), val)
+	require.Zero(t, stats.Snapshot()[OpGet].Count)
+
+	require.NoError(t, store.Delete("john"))
+	val, err = store.Get("john")
+	require.NoError(t, err)
+	require.Nil(t, val)
+}
+
+func Test_Tiered_LocalTTL(t *testing.T) {
+	t.Parallel()
+	remote := memory.New()
+	store := NewTiered(remote, TieredConfig{LocalTTL: time.Second})
+
+	require.NoError(t, store.Set("john", []byte("doe"), 0))
+
+	// Another instance changes the remote storage
+	require.NoError(t, remote.Set("john", []byte("smith"), 0))
+
+	val, err := store.Get("john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("doe"), val)
+
+	time.Sleep(1500 * time.Millisecond)
+
+	val, err = store.Get("john")
+	require.NoError(t, err)
+	require.Equal(t, []byte("smith"), val)
+}
+
+func Test_Tiered_StorageV2(t *testing.T) {
+	t.Parallel()
+	local := memory.New()
+	store := NewTiered(memory.New(), TieredConfig{Local: local})
+
+	v2, ok := store.(fiber.StorageV2)
+	require.True(t, ok)
+
+	stored, err := v2.SetNX("john", []byte("doe"), 0)
+	require.NoError(t, err)
+	require.True(t, stored)
+
+	require.NoError(t, local.Set("counter", []byte("10"), 0))
+	value, err := v2.IncrBy("counter", 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), value)
+
+	// The outdated local copy was removed
+	val, err := store.Get("counter")
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), val)
+
+	keys, err := v2.Scan("jo")
+	require.NoError(t, err)
+	require.Equal(t, []string{"john"}, keys)
+
+	_, ok = NewTiered(basicStorage{memory.New()}).(fiber.AtomicStorage)
+	require.False(t, ok)
+}
+
+func Test_Tiered_Reset_Close(t *testing.T) {
+	t.Parallel()
+	local := memory.New()
+	remote := memory.New()
+	store := NewTiered(remote, TieredConfig{Local: local})
+
+	require.NoError(t, store.Set("john", []byte("doe"), 0))
+	require.NoError(t, store.Reset())
+
+	for _, s := range []fiber.Storage{local, remote} {
+		val, err := s.Get("john")
+		require.NoError(t, err)
+		require.Nil(t, val)
+	}
+
+	require.NoError(t, store.Close())
+}
@@ -0,0 +1,94 @@
+package fiber
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// tasks keeps the background tasks of an app, which are stopped on shutdown
+type tasks struct {
+	ctx     context.Context //nolint:containedctx // The context is canceled on shutdown
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	stopped bool
+}
+
+func newTasks() *tasks {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &tasks{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Go runs the task in a new goroutine. The context of the task is canceled when the app
+// shuts down, and Shutdown waits for the task to return. Panics of the task are recovered
+// and logged. Tasks aren't started anymore once the app shut down.
+//
+//	app.Go(func(ctx context.Context) {
+//	    for {
+//	        select {
+//	        case <-ctx.Done():
+//	            return
+//	        case job := <-jobs:
+//	            process(job)
+//	        }
+//	    }
+//	})
+func (app *App) Go(task func(ctx context.Context)) {
+	app.tasks.mu.Lock()
+	defer app.tasks.mu.Unlock()
+	if app.tasks.stopped {
+		return
+	}
+
+	app.tasks.wg.Add(1)
+	go func() {
+		defer app.tasks.wg.Done()
+		runTask(func() {
+			task(app.tasks.ctx)
+		})
+	}()
+}
+
+// stop cancels the context of the tasks and waits until they returned or ctx is done
+func (t *tasks) stop(ctx context.Context) error {
+	t.mu.Lock()
+	t.stopped = true
+	t.mu.Unlock()
+	t.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runTask runs the task and logs its panic
+func runTask(task func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("task: recovered from panic: %v", r)
+		}
+	}()
+	task()
+}
+
+// runDeferred runs the functions of Ctx.Defer in a background task
+func (app *App) runDeferred(fns []func()) {
+	app.Go(func(_ context.Context) {
+		for _, fn := range fns {
+			runTask(fn)
+		}
+	})
+}
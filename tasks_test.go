@@ -0,0 +1,112 @@
+package fiber
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_App_Go
+func Test_App_Go(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	started := make(chan struct{})
+	var stopped atomic.Bool
+	app.Go(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		stopped.Store(true)
+	})
+	// Panics of tasks are recovered
+	app.Go(func(_ context.Context) {
+		panic("task")
+	})
+	<-started
+
+	// Shutdown waits for the tasks
+	require.NoError(t, app.Shutdown())
+	require.True(t, stopped.Load())
+
+	// Tasks aren't started after the shutdown
+	var ran atomic.Bool
+	app.Go(func(_ context.Context) {
+		ran.Store(true)
+	})
+	require.NoError(t, app.tasks.stop(context.Background()))
+	require.False(t, ran.Load())
+}
+
+// go test -run Test_App_Go_ShutdownTimeout
+func Test_App_Go_ShutdownTimeout(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	release := make(chan struct{})
+	app.Go(func(_ context.Context) {
+		<-release
+	})
+	require.ErrorIs(t, app.ShutdownWithTimeout(10*time.Millisecond), context.DeadlineExceeded)
+	close(release)
+}
+
+// go test -run Test_Ctx_Defer
+func Test_Ctx_Defer(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	calls := make(chan string, 3)
+	release := make(chan struct{})
+	app.Get("/", func(c Ctx) error {
+		path := c.Path()
+		c.Defer(func() {
+			<-release
+			calls <- "first " + path
+		})
+		c.Defer(func() {
+			panic("deferred")
+		})
+		c.Defer(func() {
+			calls <- "second"
+		})
+		return c.SendString("ok")
+	})
+
+	// The response doesn't wait for the deferred functions
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Empty(t, calls)
+
+	close(release)
+	require.NoError(t, app.Shutdown())
+	require.Equal(t, "first /", <-calls)
+	require.Equal(t, "second", <-calls)
+}
+
+// go test -v -run=^$ -bench=Benchmark_Ctx_Defer -benchmem -count=4
+func Benchmark_Ctx_Defer(b *testing.B) {
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		c.Defer(func() {})
+		return nil
+	})
+	h := app.Handler()
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Request.Header.SetMethod(MethodGet)
+	fctx.Request.SetRequestURI("/")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		h(fctx)
+	}
+	b.StopTimer()
+	require.NoError(b, app.Shutdown())
+}
@@ -0,0 +1,156 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// headerCtxPool holds the contexts which are used to find the route of a request
+// when its headers were received
+var headerCtxPool = sync.Pool{
+	New: func() any {
+		return new(fasthttp.RequestCtx)
+	},
+}
+
+// ReadTimeout overrides the ReadTimeout of the app for the latest created route.
+// The timeout starts when the headers of a request were read and limits the time
+// to read its body, e.g. longer for uploads and shorter for health checks.
+func (app *App) ReadTimeout(timeout time.Duration) Router {
+	app.updateLatestRoute(func(route *Route) {
+		route.readTimeout = timeout
+	})
+	atomic.StoreUint32(&app.routeConfigs, 1)
+
+	return app
+}
+
+// WriteTimeout overrides the WriteTimeout of the app for the latest created route,
+// e.g. longer for streamed exports and shorter for health checks.
+func (app *App) WriteTimeout(timeout time.Duration) Router {
+	app.updateLatestRoute(func(route *Route) {
+		route.writeTimeout = timeout
+	})
+	atomic.StoreUint32(&app.routeConfigs, 1)
+
+	return app
+}
+
+// BodyLimit overrides the BodyLimit of the app for the latest created route, e.g. a
+// larger limit for uploads while the limit of the app stays small. The limit is
+// applied before the body is read, so larger bodies are rejected with 413.
+func (app *App) BodyLimit(limit int) Router {
+	app.updateLatestRoute(func(route *Route) {
+		route.bodyLimit = limit
+	})
+	atomic.StoreUint32(&app.routeConfigs, 1)
+
+	return app
+}
+
+// ReadTimeout overrides the ReadTimeout of the app for the latest route of the group.
+//
+// If this method is used before any route added to group, the timeout applies to all
+// routes which are added to the group afterwards.
+func (grp *Group) ReadTimeout(timeout time.Duration) Router {
+	if grp.anyRouteDefined {
+		grp.app.ReadTimeout(timeout)
+
+		return grp
+	}
+
+	grp.app.mutex.Lock()
+	grp.readTimeout = timeout
+	grp.app.mutex.Unlock()
+	atomic.StoreUint32(&grp.app.routeConfigs, 1)
+
+	return grp
+}
+
+// WriteTimeout overrides the WriteTimeout of the app for the latest route of the group.
+//
+// If this method is used before any route added to group, the timeout applies to all
+// routes which are added to the group afterwards.
+func (grp *Group) WriteTimeout(timeout time.Duration) Router {
+	if grp.anyRouteDefined {
+		grp.app.WriteTimeout(timeout)
+
+		return grp
+	}
+
+	grp.app.mutex.Lock()
+	grp.writeTimeout = timeout
+	grp.app.mutex.Unlock()
+	atomic.StoreUint32(&grp.app.routeConfigs, 1)
+
+	return grp
+}
+
+// BodyLimit overrides the BodyLimit of the app for the latest route of the group.
+//
+// If this method is used before any route added to group, the limit applies to all
+// routes which are added to the group afterwards.
+func (grp *Group) BodyLimit(limit int) Router {
+	if grp.anyRouteDefined {
+		grp.app.BodyLimit(limit)
+
+		return grp
+	}
+
+	grp.app.mutex.Lock()
+	grp.bodyLimit = limit
+	grp.app.mutex.Unlock()
+	atomic.StoreUint32(&grp.app.routeConfigs, 1)
+
+	return grp
+}
+
+// headerReceived returns the timeouts and the body limit of the route which matches the
+// headers of the request, before its body is read. Zero values keep the config of the app.
+func (app *App) headerReceived(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+	if atomic.LoadUint32(&app.routeConfigs) == 0 {
+		return fasthttp.RequestConfig{}
+	}
+
+	fctx := headerCtxPool.Get().(*fasthttp.RequestCtx) //nolint:forcetypeassert,errcheck // We store nothing else in the pool
+	header.CopyTo(&fctx.Request.Header)
+	c := app.AcquireCtx(fctx)
+
+	var cfg fasthttp.RequestConfig
+	if route := app.matchRoute(c); route != nil {
+		cfg.ReadTimeout = route.readTimeout
+		cfg.WriteTimeout = route.writeTimeout
+		cfg.MaxRequestBodySize = route.bodyLimit
+	}
+
+	app.ReleaseCtx(c)
+	fctx.Request.Reset()
+	headerCtxPool.Put(fctx)
+
+	return cfg
+}
+
+// matchRoute returns the first handler route of the method of the request which
+// matches its path and host, or nil if there is none
+func (app *App) matchRoute(c CustomCtx) *Route {
+	treeStack := app.treeStack.Load()
+	if treeStack == nil || c.getMethodINT() == -1 {
+		return nil
+	}
+	for _, route := range routeTree(treeStack, c.getMethodINT(), c.getTreePath()) {
+		if route.use || route.mount {
+			continue
+		}
+		if route.match(c.getDetectionPath(), c.Path(), c.getValues()) && route.matchHost(c, c.getValues()) {
+			return route
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package fiber
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+func Test_App_RouteTimeouts(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	app.Get("/health", testEmptyHandler).ReadTimeout(time.Second).WriteTimeout(2 * time.Second)
+	app.Get("/users/:id", testEmptyHandler)
+
+	exports := app.Group("/exports").WriteTimeout(time.Minute)
+	exports.Get("/csv", testEmptyHandler)
+	exports.Get("/pdf", testEmptyHandler).WriteTimeout(2 * time.Minute)
+	exports.Group("/v1").Post("/csv", testEmptyHandler)
+
+	tenants := app.Host(":tenant.example.com").ReadTimeout(3 * time.Second)
+	tenants.Post("/upload", testEmptyHandler)
+	// The timeouts of a route don't apply to the routes of other hosts with the same path
+	app.Post("/upload", testEmptyHandler).WriteTimeout(time.Hour)
+
+	app.startupProcess()
+
+	testCases := []struct {
+		method string
+		uri    string
+		host   string
+		config fasthttp.RequestConfig
+	}{
+		{method: MethodGet, uri: "/health", config: fasthttp.RequestConfig{ReadTimeout: time.Second, WriteTimeout: 2 * time.Second}},
+		{method: MethodGet, uri: "/HEALTH/?check=1", config: fasthttp.RequestConfig{ReadTimeout: time.Second, WriteTimeout: 2 * time.Second}},
+		{method: MethodGet, uri: "/users/1"},
+		{method: MethodGet, uri: "/unknown"},
+		{method: MethodPost, uri: "/health"},
+		{method: "UNKNOWN", uri: "/health"},
+		{method: MethodGet, uri: "/exports/csv", config: fasthttp.RequestConfig{WriteTimeout: time.Minute}},
+		{method: MethodGet, uri: "/exports/pdf", config: fasthttp.RequestConfig{WriteTimeout: 2 * time.Minute}},
+		{method: MethodPost, uri: "/exports/v1/csv", config: fasthttp.RequestConfig{WriteTimeout: time.Minute}},
+		{method: MethodPost, uri: "/upload", host: "acme.example.com", config: fasthttp.RequestConfig{ReadTimeout: 3 * time.Second}},
+		{method: MethodPost, uri: "/upload", host: "example.com", config: fasthttp.RequestConfig{WriteTimeout: time.Hour}},
+	}
+	for _, tc := range testCases {
+		var header fasthttp.RequestHeader
+		header.SetMethod(tc.method)
+		header.SetRequestURI(tc.uri)
+		header.SetHost(tc.host)
+		require.Equal(t, tc.config, app.headerReceived(&header), tc.method+" "+tc.uri)
+	}
+
+	// Without timeouts of routes, the routes aren't matched
+	app = New()
+	app.Get("/", testEmptyHandler)
+	app.startupProcess()
+	var header fasthttp.RequestHeader
+	header.SetRequestURI("/")
+	require.Equal(t, fasthttp.RequestConfig{}, app.headerReceived(&header))
+
+	// GET routes answer HEAD requests with their timeouts
+	app = New(Config{EnableAutoHead: true})
+	app.Get("/", testEmptyHandler).ReadTimeout(time.Second)
+	app.startupProcess()
+	header.SetMethod(MethodHead)
+	require.Equal(t, fasthttp.RequestConfig{ReadTimeout: time.Second}, app.headerReceived(&header))
+}
+
+func Test_App_RouteTimeouts_ReadTimeout(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Post("/upload", testEmptyHandler).ReadTimeout(50 * time.Millisecond)
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		assert.NoError(t, app.Listener(ln, ListenConfig{DisableStartupMessage: true}))
+	}()
+	defer func() {
+		require.NoError(t, app.Shutdown())
+	}()
+
+	conn, err := ln.Dial()
+	require.NoError(t, err)
+	defer conn.Close() //nolint:errcheck // It is fine to ignore the error here
+
+	// The body is never sent, so the route's read timeout expires
+	_, err = conn.Write([]byte("POST /upload HTTP/1.1\r\nHost: example.com\r\nContent-Length: 10\r\n\r\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck // It is fine to ignore the error here
+	require.Equal(t, StatusRequestTimeout, resp.StatusCode)
+}
+
+func Test_App_RouteBodyLimit(t *testing.T) {
+	t.Parallel()
+	app := New(Config{BodyLimit: 10})
+
+	handler := func(c Ctx) error {
+		return c.SendString(strconv.Itoa(len(c.Body())))
+	}
+	app.Post("/upload", handler).BodyLimit(100)
+	app.Post("/small", handler)
+	app.Group("/files").BodyLimit(50).Post("/", handler)
+
+	testCases := []struct {
+		uri    string
+		size   int
+		status int
+	}{
+		{uri: "/upload", size: 100, status: StatusOK},
+		{uri: "/upload", size: 101, status: StatusRequestEntityTooLarge},
+		{uri: "/small", size: 11, status: StatusRequestEntityTooLarge},
+		{uri: "/small", size: 10, status: StatusOK},
+		{uri: "/files", size: 50, status: StatusOK},
+		{uri: "/files", size: 51, status: StatusRequestEntityTooLarge},
+	}
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		assert.NoError(t, app.Listener(ln, ListenConfig{DisableStartupMessage: true}))
+	}()
+	defer func() {
+		require.NoError(t, app.Shutdown())
+	}()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
+			return ln.Dial()
+		},
+	}}
+	for _, tc := range testCases {
+		resp, err := client.Post("http://example.com"+tc.uri, MIMETextPlain, strings.NewReader(strings.Repeat("a", tc.size)))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, tc.status, resp.StatusCode, tc.uri+" "+strconv.Itoa(tc.size))
+	}
+}
+
+// go test -v -run=^$ -bench=Benchmark_App_RouteTimeouts -benchmem -count=4
+func Benchmark_App_RouteTimeouts(b *testing.B) {
+	app := New()
+	app.Get("/health", testEmptyHandler).WriteTimeout(time.Second)
+	for i := 0; i < 20; i++ {
+		app.Get("/route"+string(rune('a'+i)), testEmptyHandler)
+	}
+	app.startupProcess()
+
+	var header fasthttp.RequestHeader
+	header.SetRequestURI("/health")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		app.headerReceived(&header)
+	}
+}
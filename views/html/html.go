@@ -0,0 +1,216 @@
+// Package html provides a Views engine based on the html/template package of the standard library,
+// so simple applications can use Render without an external template package.
+package html
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+// ErrTemplateNotFound is returned when a template or layout does not exist.
+var ErrTemplateNotFound = errors.New("html: template does not exist")
+
+// Engine is a Views engine based on html/template.
+//
+// All files with the configured extension are parsed into one template set.
+// A template is named after its path relative to the root directory without
+// the extension, e.g. "index" or "partials/header", so partials can be included with
+// {{template "partials/header" .}}. Layouts include the rendered view with {{embed}}.
+type Engine struct {
+	// fileSystem to load the templates from
+	fileSystem fs.FS
+	// funcMap contains the functions added to all templates
+	funcMap template.FuncMap
+	// templates is the parsed template set
+	templates *template.Template
+	// extension of the template files
+	extension string
+	// layoutFunc is the name of the function which embeds the view into a layout
+	layoutFunc string
+	// delimiters of the template actions
+	left  string
+	right string
+	// mutex protects the templates and the function map
+	mutex sync.RWMutex
+	// loaded indicates if the templates were loaded
+	loaded bool
+	// reload indicates if the templates are reloaded on each render
+	reload bool
+}
+
+// New creates a new Engine which loads the templates with the given extension
+// from the directory on the local disk.
+//
+//	engine := html.New("./views", ".html")
+func New(directory, extension string) *Engine {
+	return NewFileSystem(os.DirFS(directory), extension)
+}
+
+// NewFileSystem creates a new Engine which loads the templates with the given extension
+// from a file system like embed.FS.
+//
+//	//go:embed views/*
+//	var views embed.FS
+//
+//	sub, _ := fs.Sub(views, "views")
+//	engine := html.NewFileSystem(sub, ".html")
+func NewFileSystem(fileSystem fs.FS, extension string) *Engine {
+	return &Engine{
+		fileSystem: fileSystem,
+		extension:  extension,
+		layoutFunc: "embed",
+		funcMap:    make(template.FuncMap),
+	}
+}
+
+// AddFunc adds the function to the function map of the templates.
+// It has to be called before the templates are loaded.
+func (e *Engine) AddFunc(name string, fn any) *Engine {
+	e.mutex.Lock()
+	e.funcMap[name] = fn
+	e.mutex.Unlock()
+	return e
+}
+
+// AddFuncMap adds the functions to the function map of the templates.
+// It has to be called before the templates are loaded.
+func (e *Engine) AddFuncMap(funcMap map[string]any) *Engine {
+	e.mutex.Lock()
+	for name, fn := range funcMap {
+		e.funcMap[name] = fn
+	}
+	e.mutex.Unlock()
+	return e
+}
+
+// Delims sets the action delimiters, the default is {{ and }}.
+func (e *Engine) Delims(left, right string) *Engine {
+	e.left, e.right = left, right
+	return e
+}
+
+// Layout sets the name of the function which embeds the view into a layout, the default is "embed".
+func (e *Engine) Layout(name string) *Engine {
+	e.layoutFunc = name
+	return e
+}
+
+// Reload enables reloading the templates on each render, which is useful during development.
+func (e *Engine) Reload(enabled bool) *Engine {
+	e.reload = enabled
+	return e
+}
+
+// Load parses all templates of the file system.
+func (e *Engine) Load() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.load()
+}
+
+func (e *Engine) load() error {
+	// The layout function is replaced on each render with the rendered view
+	e.funcMap[e.layoutFunc] = func() (template.HTML, error) {
+		return "", fmt.Errorf("html: %s called outside of a layout", e.layoutFunc)
+	}
+
+	templates := template.New("").Delims(e.left, e.right).Funcs(e.funcMap)
+
+	err := fs.WalkDir(e.fileSystem, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), e.extension) {
+			return nil
+		}
+
+		content, err := fs.ReadFile(e.fileSystem, filePath)
+		if err != nil {
+			return fmt.Errorf("html: failed to read %s: %w", filePath, err)
+		}
+
+		name := strings.TrimSuffix(path.Clean(filePath), e.extension)
+		if _, err := templates.New(name).Parse(string(content)); err != nil {
+			return fmt.Errorf("html: failed to parse %s: %w", filePath, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	e.templates = templates
+	e.loaded = true
+
+	return nil
+}
+
+// Render executes the template with the given name and writes the result to out.
+// If a layout is given, the layout is executed and the rendered template is
+// embedded into it by the layout function.
+func (e *Engine) Render(out io.Writer, name string, binding any, layout ...string) error {
+	e.mutex.RLock()
+	loaded := e.loaded
+	e.mutex.RUnlock()
+	if !loaded || e.reload {
+		if err := e.Load(); err != nil {
+			return err
+		}
+	}
+
+	e.mutex.RLock()
+	tmpl := e.templates.Lookup(name)
+	var lay *template.Template
+	if len(layout) > 0 && layout[0] != "" {
+		lay = e.templates.Lookup(layout[0])
+		if lay == nil {
+			e.mutex.RUnlock()
+			return fmt.Errorf("%w: %s", ErrTemplateNotFound, layout[0])
+		}
+	}
+	e.mutex.RUnlock()
+	if tmpl == nil {
+		return fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	if lay == nil {
+		if err := tmpl.Execute(out, binding); err != nil {
+			return fmt.Errorf("html: failed to execute %s: %w", name, err)
+		}
+		return nil
+	}
+
+	// Render the view before the layout, so the layout can be written directly to out
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, binding); err != nil {
+		return fmt.Errorf("html: failed to execute %s: %w", name, err)
+	}
+
+	// The function map of the layout is shared, so layouts are rendered one at a time
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	lay.Funcs(template.FuncMap{
+		e.layoutFunc: func() template.HTML {
+			return template.HTML(buf.String()) //nolint:gosec // The view was escaped by html/template
+		},
+	})
+	err := lay.Execute(out, binding)
+	// Restore the layout function, so it doesn't leak the view into other renders
+	lay.Funcs(template.FuncMap{e.layoutFunc: e.funcMap[e.layoutFunc]})
+	if err != nil {
+		return fmt.Errorf("html: failed to execute layout %s: %w", layout[0], err)
+	}
+
+	return nil
+}
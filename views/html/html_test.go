@@ -0,0 +1,145 @@
+package html
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+const testViews = "../../.github/testdata/views"
+
+func newTestEngine() *Engine {
+	return New(testViews, ".html").AddFunc("upper", strings.ToUpper)
+}
+
+// go test -run Test_HTML_Render
+func Test_HTML_Render(t *testing.T) {
+	t.Parallel()
+	engine := newTestEngine()
+	require.NoError(t, engine.Load())
+
+	var buf bytes.Buffer
+	err := engine.Render(&buf, "index", fiber.Map{
+		"Title":  "Hello, <World>!",
+		"Header": "header",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "<h2>HEADER</h2><h1>Hello, &lt;World&gt;!</h1>", buf.String())
+
+	buf.Reset()
+	err = engine.Render(&buf, "partials/header", fiber.Map{"Header": "partial"})
+	require.NoError(t, err)
+	require.Equal(t, "<h2>PARTIAL</h2>", buf.String())
+
+	err = engine.Render(&buf, "ignored", nil)
+	require.ErrorIs(t, err, ErrTemplateNotFound)
+}
+
+// go test -run Test_HTML_Render_Layout
+func Test_HTML_Render_Layout(t *testing.T) {
+	t.Parallel()
+	engine := newTestEngine()
+	require.NoError(t, engine.Load())
+
+	var buf bytes.Buffer
+	err := engine.Render(&buf, "index", fiber.Map{
+		"Title":  "Hello",
+		"Header": "header",
+	}, "layouts/main")
+	require.NoError(t, err)
+	require.Equal(t, "<main><h2>HEADER</h2><h1>Hello</h1></main>", buf.String())
+
+	err = engine.Render(&buf, "index", nil, "layouts/unknown")
+	require.ErrorIs(t, err, ErrTemplateNotFound)
+
+	// embed is only available inside of layouts
+	buf.Reset()
+	err = engine.Render(&buf, "layouts/main", nil)
+	require.Error(t, err)
+}
+
+// go test -run Test_HTML_FileSystem
+func Test_HTML_FileSystem(t *testing.T) {
+	t.Parallel()
+	engine := NewFileSystem(os.DirFS(testViews), ".html").
+		AddFuncMap(map[string]any{"upper": strings.ToUpper}).
+		Layout("content").
+		Delims("[[", "]]")
+
+	// Without loading, the templates are loaded on the first render
+	var buf bytes.Buffer
+	err := engine.Render(&buf, "partials/header", fiber.Map{"Header": "fs"})
+	require.NoError(t, err)
+	// The default delimiters are not evaluated anymore
+	require.Equal(t, "<h2>{{upper .Header}}</h2>", buf.String())
+}
+
+// go test -run Test_HTML_Reload
+func Test_HTML_Reload(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(dir+"/index.html", []byte("before"), 0o600))
+
+	engine := New(dir, ".html").Reload(true)
+	require.NoError(t, engine.Load())
+
+	var buf bytes.Buffer
+	require.NoError(t, engine.Render(&buf, "index", nil))
+	require.Equal(t, "before", buf.String())
+
+	require.NoError(t, os.WriteFile(dir+"/index.html", []byte("after"), 0o600))
+	buf.Reset()
+	require.NoError(t, engine.Render(&buf, "index", nil))
+	require.Equal(t, "after", buf.String())
+
+	require.NoError(t, os.WriteFile(dir+"/invalid.html", []byte("{{.Invalid"), 0o600))
+	require.Error(t, engine.Render(&buf, "index", nil))
+}
+
+// go test -run Test_HTML_App
+func Test_HTML_App(t *testing.T) {
+	t.Parallel()
+	app := fiber.New(fiber.Config{
+		Views:       newTestEngine(),
+		ViewsLayout: "layouts/main",
+	})
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.Render("index", fiber.Map{
+			"Title":  "Hello",
+			"Header": "app",
+		})
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "<main><h2>APP</h2><h1>Hello</h1></main>", string(body))
+}
+
+// go test -v -run=^$ -bench=Benchmark_HTML_Render -benchmem -count=4
+func Benchmark_HTML_Render(b *testing.B) {
+	engine := newTestEngine()
+	require.NoError(b, engine.Load())
+	bind := fiber.Map{"Title": "Hello", "Header": "header"}
+
+	var buf bytes.Buffer
+	var err error
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		buf.Reset()
+		err = engine.Render(&buf, "index", bind, "layouts/main")
+	}
+	require.NoError(b, err)
+	require.Equal(b, "<main><h2>HEADER</h2><h1>Hello</h1></main>", buf.String())
+}